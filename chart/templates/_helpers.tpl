@@ -80,3 +80,13 @@ ingresses
 {{- end -}}
 {{- end -}}
 {{- end }}
+
+{{/*
+Whether to run FalkorDB as a sidecar. The in-memory graph backend runs inside
+the spectre container and needs neither the sidecar nor its volume.
+*/}}
+{{- define "spectre.falkordbSidecar" -}}
+{{- if and .Values.graph.enabled .Values.graph.falkordb.sidecar (ne (default "falkordb" .Values.graph.backend) "memory") -}}
+true
+{{- end -}}
+{{- end }}
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/moolen/spectre/internal/agent"
+	"github.com/moolen/spectre/internal/lifecycle"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/moolen/spectre/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentHubURL            string
+	agentHubTokenFile      string
+	agentClusterName       string
+	agentID                string
+	agentWatcherConfigPath string
+	agentCheckpointFile    string
+	agentSpoolDir          string
+	agentSpoolMaxMB        int
+	agentBatchSize         int
+	agentFlushInterval     time.Duration
+	agentHealthPort        int
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Start a Spectre agent forwarding events to a hub",
+	Long: `Start a lightweight Spectre agent which watches Kubernetes resources and
+forwards the captured events to a central Spectre server (the hub) instead of
+storing them in a local graph database. Events are spooled on disk while the
+hub is unreachable and delivered at least once.`,
+	Run: runAgent,
+}
+
+func init() {
+	agentCmd.Flags().StringVar(&agentHubURL, "hub-url", "", "Base URL of the hub's API server (e.g., http://spectre.spectre.svc:8080)")
+	agentCmd.Flags().StringVar(&agentHubTokenFile, "hub-token-file", "",
+		"Path to a file containing the bearer token used to authenticate against the hub (requires the admin role). Re-read on every push.")
+	agentCmd.Flags().StringVar(&agentClusterName, "cluster-name", "", "Name of the watched cluster, used to tag all forwarded events (required)")
+	agentCmd.Flags().StringVar(&agentID, "agent-id", "", "Identifier of this agent in the hub's logs (default: hostname)")
+	agentCmd.Flags().StringVar(&agentWatcherConfigPath, "watcher-config", "watcher.yaml", "Path to the YAML file containing watcher configuration")
+	agentCmd.Flags().StringVar(&agentCheckpointFile, "watcher-checkpoint-file", "",
+		"File the last seen resourceVersion per watched resource is persisted in, so that watches resume after a restart instead of relisting (default: empty, kept in memory)")
+	agentCmd.Flags().StringVar(&agentSpoolDir, "spool-dir", "/var/lib/spectre/spool", "Directory events are spooled in until the hub acknowledges them")
+	agentCmd.Flags().IntVar(&agentSpoolMaxMB, "spool-max-mb", 512,
+		"Maximum size of the spool in MB; the oldest batches are dropped beyond it (0 = unbounded)")
+	agentCmd.Flags().IntVar(&agentBatchSize, "batch-size", agent.DefaultBatchSize, "Maximum number of events per batch pushed to the hub")
+	agentCmd.Flags().DurationVar(&agentFlushInterval, "flush-interval", agent.DefaultFlushInterval, "Maximum time events are buffered before being spooled")
+	agentCmd.Flags().IntVar(&agentHealthPort, "health-port", 8080, "Port serving the /health and /ready endpoints (0 disables them)")
+
+	_ = agentCmd.MarkFlagRequired("hub-url")
+	_ = agentCmd.MarkFlagRequired("cluster-name")
+}
+
+func runAgent(cmd *cobra.Command, args []string) {
+	if err := models.ValidateClusterName(agentClusterName); err != nil {
+		HandleError(err, "Configuration error")
+	}
+
+	// Setup logging
+	if err := setupLog(logLevelFlags); err != nil {
+		HandleError(err, "Failed to setup logging")
+	}
+	logger := logging.GetLogger("agent")
+
+	logger.Info("Starting Spectre agent v%s for cluster %q", Version, agentClusterName)
+
+	if agentID == "" {
+		agentID, _ = os.Hostname()
+	}
+
+	forwarder, err := agent.NewForwarder(agent.Config{
+		HubURL:        agentHubURL,
+		Cluster:       agentClusterName,
+		AgentID:       agentID,
+		TokenFile:     agentHubTokenFile,
+		SpoolDir:      agentSpoolDir,
+		SpoolMaxBytes: int64(agentSpoolMaxMB) * 1024 * 1024,
+		BatchSize:     agentBatchSize,
+		FlushInterval: agentFlushInterval,
+	})
+	if err != nil {
+		logger.Error("Failed to create forwarder: %v", err)
+		HandleError(err, "Forwarder initialization error")
+	}
+
+	// The forwarder takes the place of the graph pipeline, pruning and
+	// redaction still happen in the agent before events leave the cluster
+	eventHandler := watcher.NewEventCaptureHandler(forwarder)
+	eventHandler.SetCluster(agentClusterName)
+
+	watcherComponent, err := watcher.New(eventHandler, agentWatcherConfigPath)
+	if err != nil {
+		logger.Error("Failed to create watcher component: %v", err)
+		HandleError(err, "Watcher initialization error")
+	}
+	if agentCheckpointFile != "" {
+		checkpoints, err := watcher.NewCheckpointStore(agentCheckpointFile)
+		if err != nil {
+			logger.Error("Failed to load watch checkpoints: %v", err)
+			HandleError(err, "Watcher initialization error")
+		}
+		watcherComponent.SetCheckpointStore(checkpoints)
+	}
+
+	manager := lifecycle.NewManager()
+	if err := manager.Register(forwarder); err != nil {
+		logger.Error("Failed to register forwarder: %v", err)
+		HandleError(err, "Forwarder registration error")
+	}
+	// The watcher depends on the forwarder so it stops first and the
+	// forwarder can spool the last captured events
+	if err := manager.Register(watcherComponent, forwarder); err != nil {
+		logger.Error("Failed to register watcher component: %v", err)
+		HandleError(err, "Watcher registration error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := manager.Start(ctx); err != nil {
+		logger.Error("Failed to start components: %v", err)
+		HandleError(err, "Startup error")
+	}
+
+	var healthServer *http.Server
+	if agentHealthPort > 0 {
+		healthServer = newAgentHealthServer(agentHealthPort, watcherComponent)
+		go func() {
+			if err := healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Health server failed: %v", err)
+			}
+		}()
+	}
+
+	logger.Info("Agent started, forwarding events to %s", agentHubURL)
+
+	// Set up signal handling for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	// Wait for shutdown signal
+	<-sigChan
+	logger.Info("Shutdown signal received, gracefully shutting down...")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer shutdownCancel()
+
+	if healthServer != nil {
+		_ = healthServer.Shutdown(shutdownCtx)
+	}
+	if err := manager.Stop(shutdownCtx); err != nil {
+		logger.Error("Error during shutdown: %v", err)
+	}
+
+	logger.Info("Shutdown complete")
+}
+
+// newAgentHealthServer serves liveness and readiness probes for the agent,
+// which has no API server of its own
+func newAgentHealthServer(port int, watcherComponent *watcher.Watcher) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if !watcherComponent.IsReady() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("not ready"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ready"))
+	})
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/moolen/spectre/internal/config"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/graphservice"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rebuildAuditLogPath     string
+	rebuildSince            string
+	rebuildKinds            []string
+	rebuildBatchSize        int
+	rebuildIncludeK8sEvents bool
+	rebuildForce            bool
+	rebuildGraphHost        string
+	rebuildGraphPort        int
+	rebuildGraphName        string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Graph database maintenance",
+	Long:  `Maintenance tasks for the FalkorDB graph Spectre stores resources and events in.`,
+}
+
+var graphRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the graph from the audit log",
+	Long: `Replay the events recorded with --audit-log into the graph, e.g. after the
+FalkorDB volume was lost. Rotated audit log segments next to the log file are
+read too. Events are replayed in timestamp order, so rebuilding the same audit
+log always produces the same graph.
+
+A full rebuild refuses to write into a graph that already has data unless
+--force is given. With --kinds, only the given kinds are replayed into the
+existing graph.`,
+	Example: `  spectre graph rebuild --from-audit-log=/var/lib/spectre/audit.jsonl --since=72h
+  spectre graph rebuild --from-audit-log=/var/lib/spectre/audit.jsonl --since=7d --kinds=Deployment,ReplicaSet`,
+	Run: runGraphRebuild,
+}
+
+func init() {
+	graphRebuildCmd.Flags().StringVar(&rebuildAuditLogPath, "from-audit-log", "", "Path to the audit log file, or a directory of audit log segments")
+	graphRebuildCmd.Flags().StringVar(&rebuildSince, "since", "24h", "How far back to replay events, e.g. 72h or 7d")
+	graphRebuildCmd.Flags().StringSliceVar(&rebuildKinds, "kinds", nil, "Only replay these resource kinds (default: all)")
+	graphRebuildCmd.Flags().IntVar(&rebuildBatchSize, "batch-size", sync.DefaultRebuildOptions().BatchSize, "Number of events written to the graph at once")
+	graphRebuildCmd.Flags().BoolVar(&rebuildIncludeK8sEvents, "include-k8s-events", true, "Replay Kubernetes Event objects")
+	graphRebuildCmd.Flags().BoolVar(&rebuildForce, "force", false, "Rebuild even if the graph is not empty")
+	graphRebuildCmd.Flags().StringVar(&rebuildGraphHost, "graph-host", "localhost", "FalkorDB host (default: localhost)")
+	graphRebuildCmd.Flags().IntVar(&rebuildGraphPort, "graph-port", 6379, "FalkorDB port (default: 6379)")
+	graphRebuildCmd.Flags().StringVar(&rebuildGraphName, "graph-name", "spectre", "FalkorDB graph name (default: spectre)")
+
+	_ = graphRebuildCmd.MarkFlagRequired("from-audit-log")
+
+	graphCmd.AddCommand(graphRebuildCmd)
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraphRebuild(cmd *cobra.Command, args []string) {
+	if err := setupLog(logLevelFlags); err != nil {
+		HandleError(err, "Failed to setup logging")
+	}
+	logger := logging.GetLogger("graph.rebuild")
+
+	since, err := config.ParseDuration(rebuildSince)
+	if err != nil {
+		HandleError(fmt.Errorf("invalid --since: %w", err), "Configuration error")
+	}
+	if rebuildBatchSize <= 0 {
+		HandleError(fmt.Errorf("--batch-size must be positive"), "Configuration error")
+	}
+
+	querier := watcher.NewAuditLogQuerier(rebuildAuditLogPath)
+	segments, err := querier.Segments()
+	if err != nil {
+		HandleError(err, "Audit log error")
+	}
+	logger.Info("Rebuilding graph from %d audit log segments of %s (since %s)", len(segments), rebuildAuditLogPath, rebuildSince)
+
+	graphConfig := graph.DefaultClientConfig()
+	graphConfig.Host = rebuildGraphHost
+	graphConfig.Port = rebuildGraphPort
+	graphConfig.GraphName = rebuildGraphName
+
+	service := graphservice.NewService(graphservice.ServiceConfig{
+		GraphConfig:    graphConfig,
+		PipelineConfig: sync.DefaultPipelineConfig(),
+	})
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := service.Initialize(ctx); err != nil {
+		HandleError(err, "Graph service initialization error")
+	}
+	defer func() {
+		if err := service.GetClient().Close(); err != nil {
+			logger.Warn("Error closing graph client: %v", err)
+		}
+	}()
+
+	opts := sync.DefaultRebuildOptions()
+	opts.TimeWindow = since
+	opts.BatchSize = rebuildBatchSize
+	opts.IncludeK8sEvents = rebuildIncludeK8sEvents
+
+	// Failed events per kind, as reported with the last batch of the kind
+	failedByKind := make(map[string]int)
+	opts.Progress = func(p sync.RebuildProgress) {
+		if p.Kind != "" {
+			logger.Info("Rebuild progress (%s): %d/%d events (%d%%), %d failed", p.Kind, p.Processed, p.Total, percent(p.Processed, p.Total), p.Failed)
+		} else {
+			logger.Info("Rebuild progress: %d/%d events (%d%%), %d failed", p.Processed, p.Total, percent(p.Processed, p.Total), p.Failed)
+		}
+		failedByKind[p.Kind] = p.Failed
+	}
+
+	rebuilder := sync.NewRebuilder(querier, service.GetPipeline())
+	start := time.Now()
+
+	if len(rebuildKinds) > 0 {
+		err = rebuilder.PartialRebuild(ctx, rebuildKinds, opts)
+	} else {
+		if !rebuildForce {
+			empty, checkErr := service.IsGraphEmpty(ctx)
+			if checkErr != nil {
+				HandleError(checkErr, "Graph query error")
+			}
+			if !empty {
+				HandleError(fmt.Errorf("graph %q already contains data, use --force to rebuild into it", rebuildGraphName), "Rebuild error")
+			}
+		}
+		err = rebuilder.Rebuild(ctx, opts)
+	}
+	if err != nil {
+		HandleError(err, "Rebuild error")
+	}
+	if ctx.Err() != nil {
+		HandleError(ctx.Err(), "Rebuild interrupted")
+	}
+
+	stats := service.GetPipeline().GetStats()
+	logger.Info("Rebuild finished in %v: %d nodes, %d edges, %d causality links",
+		time.Since(start).Round(time.Millisecond), stats.NodesCreated, stats.EdgesCreated, stats.CausalityLinksFound)
+	failed := 0
+	for _, n := range failedByKind {
+		failed += n
+	}
+	if failed > 0 {
+		HandleError(fmt.Errorf("%d events could not be written to the graph", failed), "Rebuild incomplete")
+	}
+}
+
+// percent returns part as a percentage of total, 100 for an empty total
+func percent(part, total int) int {
+	if total == 0 {
+		return 100
+	}
+	return part * 100 / total
+}
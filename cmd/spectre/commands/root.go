@@ -37,6 +37,7 @@ func init() {
 
 	// Add subcommands
 	rootCmd.AddCommand(serverCmd)
+	rootCmd.AddCommand(agentCmd)
 	rootCmd.AddCommand(debugCmd)
 }
 
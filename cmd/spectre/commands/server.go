@@ -15,10 +15,13 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/moolen/spectre/internal/api"
 	"github.com/moolen/spectre/internal/apiserver"
+	"github.com/moolen/spectre/internal/archive"
+	"github.com/moolen/spectre/internal/auth"
 	"github.com/moolen/spectre/internal/config"
 	"github.com/moolen/spectre/internal/graph"
 	"github.com/moolen/spectre/internal/graph/reconciler"
 	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/graph/sync/extractors"
 	"github.com/moolen/spectre/internal/graphservice"
 	"github.com/moolen/spectre/internal/importexport"
 	"github.com/moolen/spectre/internal/integration"
@@ -26,18 +29,24 @@ import (
 	// Import integration implementations to register their factories
 	_ "github.com/moolen/spectre/internal/integration/logzio"
 	_ "github.com/moolen/spectre/internal/integration/victorialogs"
+	"github.com/moolen/spectre/internal/kubeaudit"
 	"github.com/moolen/spectre/internal/lifecycle"
 	"github.com/moolen/spectre/internal/logging"
 	"github.com/moolen/spectre/internal/mcp"
+	"github.com/moolen/spectre/internal/models"
 	"github.com/moolen/spectre/internal/tracing"
 	"github.com/moolen/spectre/internal/watcher"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 var (
 	apiPort               int
 	watcherConfigPath     string
 	watcherEnabled        bool
+	clusterName           string
+	watcherCheckpointFile string
 	maxConcurrentRequests int
 	importPath            string
 	pprofEnabled          bool
@@ -51,12 +60,21 @@ var (
 	tracingTLSInsecure    bool
 	// Graph reasoning layer flags
 	graphEnabled        bool
+	graphBackend        string
 	graphHost           string
 	graphPort           int
 	graphName           string
 	graphRetentionHours int
-	// Audit log flag
-	auditLogPath string
+	graphRetentionPath  string
+	graphArchiveDir     string
+	graphCausalityPath  string
+	graphRelationsPath  string
+	// Audit log flags
+	auditLogPath        string
+	auditLogMaxSizeMB   int
+	auditLogMaxSegments int
+	// Kubernetes audit log to correlate change actors from
+	kubeAuditLogPath string
 	// Metadata cache configuration
 	metadataCacheRefreshSeconds int
 	// Namespace graph cache configuration
@@ -72,6 +90,9 @@ var (
 	minIntegrationVersion  string
 	// MCP server configuration
 	stdioEnabled bool
+	// API authentication configuration
+	authConfigPath     string
+	corsAllowedOrigins []string
 )
 
 var serverCmd = &cobra.Command{
@@ -86,6 +107,10 @@ func init() {
 	serverCmd.Flags().IntVar(&apiPort, "api-port", 8080, "Port the API server listens on")
 	serverCmd.Flags().StringVar(&watcherConfigPath, "watcher-config", "watcher.yaml", "Path to the YAML file containing watcher configuration")
 	serverCmd.Flags().BoolVar(&watcherEnabled, "watcher-enabled", true, "Enable Kubernetes watcher (default: true)")
+	serverCmd.Flags().StringVar(&clusterName, "cluster-name", "",
+		"Name of the watched cluster. Events are tagged with it so several clusters can share one graph (default: empty, single-cluster)")
+	serverCmd.Flags().StringVar(&watcherCheckpointFile, "watcher-checkpoint-file", "",
+		"File the last seen resourceVersion per watched resource is persisted in, so that watches resume after a restart instead of relisting (default: empty, kept in memory)")
 	serverCmd.Flags().IntVar(&maxConcurrentRequests, "max-concurrent-requests", 100, "Maximum number of concurrent API requests")
 	serverCmd.Flags().StringVar(&importPath, "import-path", "", "Path to the binary file containing events to import on startup")
 	serverCmd.Flags().BoolVar(&pprofEnabled, "pprof-enabled", false, "Enable pprof profiling server (default: false)")
@@ -100,15 +125,32 @@ func init() {
 
 	// Graph reasoning layer flags
 	serverCmd.Flags().BoolVar(&graphEnabled, "graph-enabled", false, "Enable graph-based reasoning layer (default: false)")
+	serverCmd.Flags().StringVar(&graphBackend, "graph-backend", string(graph.BackendFalkorDB),
+		"Graph storage backend: 'falkordb' or 'memory'. The in-memory backend needs no FalkorDB sidecar but loses all data on restart (default: falkordb)")
 	serverCmd.Flags().StringVar(&graphHost, "graph-host", "localhost", "FalkorDB host (default: localhost)")
 	serverCmd.Flags().IntVar(&graphPort, "graph-port", 6379, "FalkorDB port (default: 6379)")
 	serverCmd.Flags().StringVar(&graphName, "graph-name", "spectre", "FalkorDB graph name (default: spectre)")
 	serverCmd.Flags().IntVar(&graphRetentionHours, "graph-retention-hours", 168, "Graph data retention window in hours (default: 168 = 7 days)")
+	serverCmd.Flags().StringVar(&graphRetentionPath, "graph-retention-config", "",
+		"Path to a YAML file with per-kind retention policies and compaction of old change events (optional)")
+	serverCmd.Flags().StringVar(&graphArchiveDir, "graph-archive-dir", "",
+		"Directory to archive events into before retention deletes them. Timeline queries reaching back past the graph are then served from the archive (optional)")
+	serverCmd.Flags().StringVar(&graphCausalityPath, "graph-causality-config", "",
+		"Path to a YAML file declaring causality heuristics in addition to the built-in ones. Reloaded when it changes (optional)")
+	serverCmd.Flags().StringVar(&graphRelationsPath, "graph-relationships-config", "",
+		"Path to a YAML file declaring relationships of custom resources to extract in addition to the built-in ones (optional)")
 
 	// Audit log flag
 	serverCmd.Flags().StringVar(&auditLogPath, "audit-log", "",
 		"Path to write event audit log (JSONL format) for test fixtures. "+
 			"If empty, audit logging is disabled.")
+	serverCmd.Flags().IntVar(&auditLogMaxSizeMB, "audit-log-max-size-mb", 100,
+		"Size in MB at which the audit log is rotated to <name>-<timestamp><ext>, 0 disables rotation (default: 100)")
+	serverCmd.Flags().IntVar(&auditLogMaxSegments, "audit-log-max-segments", 0,
+		"Number of rotated audit log segments to keep, 0 keeps all of them (default: 0)")
+
+	serverCmd.Flags().StringVar(&kubeAuditLogPath, "kube-audit-log", "",
+		"Path of a Kubernetes API server audit log (--audit-log-path) to follow. Its events record who made each change (optional)")
 
 	// Metadata cache configuration
 	serverCmd.Flags().IntVar(&metadataCacheRefreshSeconds, "metadata-cache-refresh-seconds", 30,
@@ -138,6 +180,13 @@ func init() {
 
 	// MCP server configuration
 	serverCmd.Flags().BoolVar(&stdioEnabled, "stdio", false, "Enable stdio MCP transport alongside HTTP (default: false)")
+
+	// API authentication configuration
+	serverCmd.Flags().StringVar(&authConfigPath, "auth-config", "",
+		"Path to the auth configuration YAML file (static tokens, OIDC, TokenReview, role bindings). "+
+			"If empty, authentication is disabled.")
+	serverCmd.Flags().StringSliceVar(&corsAllowedOrigins, "cors-allowed-origins", []string{"*"},
+		"Origins allowed for browser access to the API (default: * allows all origins)")
 }
 
 func runServer(cmd *cobra.Command, args []string) {
@@ -157,6 +206,15 @@ func runServer(cmd *cobra.Command, args []string) {
 	if err := cfg.Validate(); err != nil {
 		HandleError(err, "Configuration error")
 	}
+	if clusterName != "" {
+		if err := models.ValidateClusterName(clusterName); err != nil {
+			HandleError(err, "Configuration error")
+		}
+	}
+	backend, err := graph.ParseBackend(graphBackend)
+	if err != nil {
+		HandleError(err, "Configuration error")
+	}
 
 	// Setup logging
 	if err := setupLog(cfg.LogLevelFlags); err != nil {
@@ -237,13 +295,15 @@ func runServer(cmd *cobra.Command, args []string) {
 
 	var watcherComponent *watcher.Watcher
 	var graphQueryExecutor api.QueryExecutor
+	var archiveExecutor api.QueryExecutor
 	var auditLogWriter *watcher.FileAuditLogWriter
 
 	// Initialize audit log if enabled
 	if auditLogPath != "" {
 		logger.Info("Event audit logging enabled: %s", auditLogPath)
 		var err error
-		auditLogWriter, err = watcher.NewFileAuditLogWriter(auditLogPath)
+		auditLogWriter, err = watcher.NewFileAuditLogWriterWithRotation(auditLogPath,
+			int64(auditLogMaxSizeMB)*1024*1024, auditLogMaxSegments)
 		if err != nil {
 			logger.Error("Failed to create audit log writer: %v", err)
 			HandleError(err, "Audit log initialization error")
@@ -253,12 +313,14 @@ func runServer(cmd *cobra.Command, args []string) {
 	var graphServiceComponent *graphservice.Service
 	var graphClient graph.Client
 	var graphPipeline sync.Pipeline
+	var causalityLimits config.CausalityLimits
 
 	// Initialize graph service (unless in audit-only mode)
 	if !auditOnlyMode {
 		logger.Info("Initializing graph service")
 
 		graphConfig := graph.ClientConfig{
+			Backend:            backend,
 			Host:               graphHost,
 			Port:               graphPort,
 			GraphName:          graphName,
@@ -280,6 +342,63 @@ func runServer(cmd *cobra.Command, args []string) {
 
 		// Set retention window from flag
 		serviceConfig.PipelineConfig.RetentionWindow = time.Duration(graphRetentionHours) * time.Hour
+		if graphRetentionPath != "" {
+			retentionConfig, err := config.LoadRetentionConfig(graphRetentionPath)
+			if err != nil {
+				logger.Error("Failed to load retention config: %v", err)
+				HandleError(err, "Retention config error")
+			}
+			for _, policy := range retentionConfig.Policies {
+				serviceConfig.PipelineConfig.RetentionPolicies = append(serviceConfig.PipelineConfig.RetentionPolicies, sync.RetentionPolicy{
+					Name:       policy.Name,
+					Source:     policy.Source,
+					Kinds:      policy.Kinds,
+					Namespaces: policy.Namespaces,
+					Types:      policy.Types,
+					Changes:    policy.Changes,
+					Retention:  time.Duration(policy.Retention),
+				})
+			}
+			serviceConfig.PipelineConfig.CompactionAge = time.Duration(retentionConfig.CompactAfter)
+			logger.Info("Loaded %d graph retention policies from %s", len(retentionConfig.Policies), graphRetentionPath)
+		}
+		if graphCausalityPath != "" {
+			causalityLimits = config.CausalityLimits{
+				BuiltinNames: sync.BuiltinHeuristicNames(),
+				MaxLag:       serviceConfig.PipelineConfig.CausalityMaxLag,
+			}
+			causalityConfig, err := config.LoadCausalityConfig(graphCausalityPath, causalityLimits)
+			if err != nil {
+				logger.Error("Failed to load causality config: %v", err)
+				HandleError(err, "Causality config error")
+			}
+			serviceConfig.PipelineConfig.CausalityRules = causalityRules(causalityConfig)
+			logger.Info("Loaded %d causality heuristics from %s", len(causalityConfig.Heuristics), graphCausalityPath)
+		}
+		if graphRelationsPath != "" {
+			relationshipsConfig, err := config.LoadRelationshipsConfig(graphRelationsPath)
+			if err != nil {
+				logger.Error("Failed to load relationships config: %v", err)
+				HandleError(err, "Relationships config error")
+			}
+			configExtractors, err := relationshipExtractors(relationshipsConfig)
+			if err != nil {
+				logger.Error("Failed to create relationship extractors: %v", err)
+				HandleError(err, "Relationships config error")
+			}
+			serviceConfig.PipelineConfig.Extractors = configExtractors
+			logger.Info("Loaded %d relationship extractors from %s", len(configExtractors), graphRelationsPath)
+		}
+		if graphArchiveDir != "" {
+			archiveStore, err := archive.NewStore(graphArchiveDir)
+			if err != nil {
+				logger.Error("Failed to open graph archive: %v", err)
+				HandleError(err, "Graph archive error")
+			}
+			serviceConfig.PipelineConfig.Archive = archiveStore
+			archiveExecutor = archive.NewExecutor(archiveStore)
+			logger.Info("Archiving expired graph events to %s", graphArchiveDir)
+		}
 
 		graphServiceComponent = graphservice.NewService(serviceConfig)
 
@@ -314,6 +433,10 @@ func runServer(cmd *cobra.Command, args []string) {
 				eventHandler.SetAuditLog(auditLogWriter)
 			}
 		}
+		if clusterName != "" {
+			eventHandler.SetCluster(clusterName)
+			logger.Info("Tagging captured events with cluster %q", clusterName)
+		}
 
 		var err error
 		watcherComponent, err = watcher.New(eventHandler, cfg.WatcherConfigPath)
@@ -321,6 +444,19 @@ func runServer(cmd *cobra.Command, args []string) {
 			logger.Error("Failed to create watcher component: %v", err)
 			HandleError(err, "Watcher initialization error")
 		}
+		if watcherCheckpointFile != "" {
+			checkpoints, err := watcher.NewCheckpointStore(watcherCheckpointFile)
+			if err != nil {
+				logger.Error("Failed to load watch checkpoints: %v", err)
+				HandleError(err, "Watcher initialization error")
+			}
+			watcherComponent.SetCheckpointStore(checkpoints)
+			logger.Info("Watch checkpoints persisted to: %s", watcherCheckpointFile)
+		}
+		if graphClient != nil {
+			// Relists after a restart only write objects that changed in the meantime
+			watcherComponent.SetStateSource(sync.NewObjectStateReader(graphClient, clusterName))
+		}
 		if auditOnlyMode {
 			logger.Info("Watcher component created (audit-only mode)")
 		} else {
@@ -424,7 +560,7 @@ func runServer(cmd *cobra.Command, args []string) {
 	// Create API server first (without MCP server) to initialize TimelineService
 	apiComponent := apiserver.NewWithStorageGraphAndPipeline(
 		cfg.APIPort,
-		nil, // No storage executor
+		archiveExecutor, // Serves windows that expired from the graph (nil without an archive)
 		graphQueryExecutor,
 		querySource,
 		nil, // No storage component
@@ -444,6 +580,20 @@ func runServer(cmd *cobra.Command, args []string) {
 	)
 	logger.Info("API server component created (graph-only)")
 
+	// Configure authentication for the REST, Connect and MCP endpoints
+	apiComponent.SetCORSAllowedOrigins(corsAllowedOrigins)
+	if authConfigPath != "" {
+		authService, err := newAuthService(authConfigPath, watcherComponent)
+		if err != nil {
+			logger.Error("Failed to initialize authentication: %v", err)
+			HandleError(err, "Authentication initialization error")
+		}
+		apiComponent.SetAuthenticator(authService)
+		logger.Info("API authentication enabled from: %s", authConfigPath)
+	} else {
+		logger.Warn("API authentication disabled - all endpoints are accessible without credentials")
+	}
+
 	// Now create MCP server with TimelineService and GraphService from API server
 	logger.Info("Initializing MCP server with TimelineService and GraphService")
 	timelineService := apiComponent.GetTimelineService()
@@ -460,6 +610,7 @@ func runServer(cmd *cobra.Command, args []string) {
 		Version:         Version,
 		TimelineService: timelineService, // Direct service access for tools
 		GraphService:    graphService,    // Direct graph service access for tools
+		Pipeline:        graphPipeline,   // Notifies resource subscribers of changes
 	})
 	if err != nil {
 		logger.Error("Failed to create MCP server: %v", err)
@@ -500,12 +651,40 @@ func runServer(cmd *cobra.Command, args []string) {
 	}
 
 	// Register MCP endpoint on API server now that MCP server is ready
-	if err := apiComponent.RegisterMCPEndpoint(mcpServer); err != nil {
+	if err := apiComponent.RegisterMCPEndpoint(mcpServer, spectreServer.HTTPMiddleware); err != nil {
 		logger.Error("Failed to register MCP endpoint: %v", err)
 		HandleError(err, "MCP endpoint registration error")
 	}
 	logger.Info("MCP endpoint registered on API server")
 
+	// Expose the watch set so users can see what is actually recorded
+	if watcherComponent != nil {
+		if err := apiComponent.RegisterWatchStatusHandler(watcherComponent); err != nil {
+			logger.Error("Failed to register watch status endpoint: %v", err)
+			HandleError(err, "Watch status endpoint registration error")
+		}
+	}
+
+	// Record who made changes from Kubernetes audit events, posted by the audit
+	// webhook backend or read from an audit log
+	if graphClient != nil {
+		correlator := kubeaudit.NewCorrelator(graphClient, clusterName)
+		if err := apiComponent.RegisterKubeAuditHandler(correlator); err != nil {
+			logger.Error("Failed to register Kubernetes audit endpoint: %v", err)
+			HandleError(err, "Kubernetes audit endpoint registration error")
+		}
+		if err := manager.Register(correlator); err != nil {
+			logger.Error("Failed to register audit event correlator: %v", err)
+			HandleError(err, "Audit event correlator registration error")
+		}
+		if kubeAuditLogPath != "" {
+			if err := manager.Register(kubeaudit.NewFileTailer(kubeAuditLogPath, clusterName, correlator), correlator); err != nil {
+				logger.Error("Failed to register Kubernetes audit log tailer: %v", err)
+				HandleError(err, "Kubernetes audit log tailer registration error")
+			}
+		}
+	}
+
 	// Register namespace graph cache with GraphService for event-driven invalidation
 	// This enables the cache to be notified when events affect specific namespaces
 	if graphServiceComponent != nil && apiComponent.GetNamespaceGraphCache() != nil {
@@ -535,6 +714,7 @@ func runServer(cmd *cobra.Command, args []string) {
 			Enabled:   true,
 			Interval:  time.Duration(reconcilerIntervalMins) * time.Minute,
 			BatchSize: reconcilerBatchSize,
+			Cluster:   clusterName,
 		}
 
 		restConfig := watcherComponent.GetRestConfig()
@@ -563,6 +743,14 @@ func runServer(cmd *cobra.Command, args []string) {
 		HandleError(err, "API server registration error")
 	}
 
+	// Notify MCP resource subscribers of the changes the graph pipeline writes
+	if notifier := spectreServer.GetResourceNotifier(); notifier != nil {
+		if err := manager.Register(notifier, graphServiceComponent); err != nil {
+			logger.Error("Failed to register MCP resource notifier: %v", err)
+			HandleError(err, "MCP resource notifier registration error")
+		}
+	}
+
 	logger.Info("All components registered with dependencies")
 	ctx, cancel := context.WithCancel(context.Background())
 	if err := manager.Start(ctx); err != nil {
@@ -570,6 +758,24 @@ func runServer(cmd *cobra.Command, args []string) {
 		HandleError(err, "Startup error")
 	}
 
+	// Follow changes of the causality heuristics until shutdown
+	if graphCausalityPath != "" && graphPipeline != nil {
+		engine := graphPipeline.GetCausalityEngine()
+		loadCausality := func(path string) (*config.CausalityConfig, error) {
+			return config.LoadCausalityConfig(path, causalityLimits)
+		}
+		causalityWatcher, err := config.NewFileWatcher(config.FileWatcherConfig{FilePath: graphCausalityPath},
+			loadCausality, func(cfg *config.CausalityConfig) error {
+				return engine.SetRules(causalityRules(cfg))
+			})
+		if err == nil {
+			err = causalityWatcher.Start(ctx)
+		}
+		if err != nil {
+			logger.Warn("Causality heuristics will not be reloaded: %v", err)
+		}
+	}
+
 	// Start stdio MCP transport if requested
 	if stdioEnabled {
 		logger.Info("Starting stdio MCP transport alongside HTTP")
@@ -608,3 +814,86 @@ func runServer(cmd *cobra.Command, args []string) {
 
 	logger.Info("Shutdown complete")
 }
+
+// causalityRules converts the configured causality heuristics
+func causalityRules(cfg *config.CausalityConfig) []sync.HeuristicRule {
+	rules := make([]sync.HeuristicRule, 0, len(cfg.Heuristics))
+	for _, heuristic := range cfg.Heuristics {
+		rules = append(rules, sync.HeuristicRule{
+			Name:          heuristic.Name,
+			Description:   heuristic.Description,
+			CauseKinds:    heuristic.Cause.Kinds,
+			CauseTypes:    heuristic.Cause.EventTypes,
+			EffectKinds:   heuristic.Effect.Kinds,
+			EffectTypes:   heuristic.Effect.EventTypes,
+			SameNamespace: heuristic.SameNamespace,
+			OwnedByCause:  heuristic.OwnedByCause,
+			MinLag:        time.Duration(heuristic.MinLag),
+			MaxLag:        time.Duration(heuristic.MaxLag),
+			Confidence:    heuristic.Confidence,
+		})
+	}
+	return rules
+}
+
+// relationshipExtractors creates the configured relationship extractors
+func relationshipExtractors(cfg *config.RelationshipsConfig) ([]extractors.RelationshipExtractor, error) {
+	result := make([]extractors.RelationshipExtractor, 0, len(cfg.Extractors))
+	for i, extractorConfig := range cfg.Extractors {
+		rule := extractors.RelationshipRule{
+			Name:     extractorConfig.Name,
+			Group:    extractorConfig.Group,
+			Kind:     extractorConfig.Kind,
+			Priority: extractorConfig.Priority,
+		}
+		for _, ref := range extractorConfig.Refs {
+			rule.Refs = append(rule.Refs, extractors.ReferenceRule{
+				Type:          extractors.ReferenceType(ref.Type),
+				Path:          ref.Path,
+				TargetKind:    ref.TargetKind,
+				TargetGroup:   ref.TargetGroup,
+				Namespace:     ref.Namespace,
+				ClusterScoped: ref.ClusterScoped,
+				Edge:          graph.EdgeType(ref.Edge),
+				Reverse:       ref.Reverse,
+			})
+		}
+
+		extractor, err := extractors.NewConfigExtractor(rule)
+		if err != nil {
+			return nil, fmt.Errorf("extractors[%d]: %w", i, err)
+		}
+		result = append(result, extractor)
+	}
+	return result, nil
+}
+
+// newAuthService loads the auth configuration and creates the auth service.
+// A Kubernetes client is only created when TokenReview is enabled; it reuses
+// the watcher's REST config if available.
+func newAuthService(path string, watcherComponent *watcher.Watcher) (*auth.Service, error) {
+	authCfg, err := config.LoadAuthConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var kubeClient kubernetes.Interface
+	if authCfg.TokenReview != nil && authCfg.TokenReview.Enabled {
+		var restConfig *rest.Config
+		if watcherComponent != nil {
+			restConfig = watcherComponent.GetRestConfig()
+		} else {
+			restConfig, err = rest.InClusterConfig()
+			if err != nil {
+				return nil, fmt.Errorf("tokenReview requires in-cluster config when the watcher is disabled: %w", err)
+			}
+		}
+
+		kubeClient, err = kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kubernetes client for TokenReview: %w", err)
+		}
+	}
+
+	return auth.NewService(authCfg, kubeClient)
+}
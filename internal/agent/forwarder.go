@@ -0,0 +1,307 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	apipb "github.com/moolen/spectre/internal/api/pb"
+	"github.com/moolen/spectre/internal/api/pb/pbconnect"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of events per spooled batch
+	DefaultBatchSize = 500
+
+	// DefaultFlushInterval is how long events are buffered before they are spooled
+	DefaultFlushInterval = 2 * time.Second
+
+	initialRetryBackoff = time.Second
+	maxRetryBackoff     = time.Minute
+)
+
+// Config configures the event forwarder of an agent
+type Config struct {
+	// HubURL is the base URL of the hub's API server (e.g. https://spectre.example.com)
+	HubURL string
+
+	// Cluster is the name of the cluster the agent watches
+	Cluster string
+
+	// AgentID identifies this agent instance in the hub's logs
+	AgentID string
+
+	// TokenFile is read before every push and sent as bearer token. Optional.
+	TokenFile string
+
+	// SpoolDir is the directory batches are spooled in until the hub acknowledges them
+	SpoolDir string
+
+	// SpoolMaxBytes bounds the spool size, 0 means unbounded
+	SpoolMaxBytes int64
+
+	// BatchSize is the maximum number of events per batch
+	BatchSize int
+
+	// FlushInterval is the maximum time events are buffered before being spooled
+	FlushInterval time.Duration
+
+	// HTTPClient is used to talk to the hub, defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Forwarder buffers events captured by the watcher, spools them to disk and
+// pushes them to the hub's IngestService. A batch is only removed from the
+// spool after the hub acknowledged it, so events are delivered at least once;
+// the hub deduplicates retried events by their ID.
+//
+// Forwarder implements watcher.GraphPipeline and lifecycle.Component.
+type Forwarder struct {
+	config Config
+	spool  *Spool
+	client pbconnect.IngestServiceClient
+	logger *logging.Logger
+
+	mu     sync.Mutex
+	buffer []models.Event
+
+	notify  chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running bool
+}
+
+// NewForwarder creates a forwarder and opens its spool
+func NewForwarder(config Config) (*Forwarder, error) {
+	if config.HubURL == "" {
+		return nil, fmt.Errorf("hub URL is required")
+	}
+	if err := models.ValidateClusterName(config.Cluster); err != nil {
+		return nil, err
+	}
+	if config.SpoolDir == "" {
+		return nil, fmt.Errorf("spool directory is required")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	spool, err := OpenSpool(config.SpoolDir, config.SpoolMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Forwarder{
+		config: config,
+		spool:  spool,
+		logger: logging.GetLogger("agent.forwarder"),
+		notify: make(chan struct{}, 1),
+	}
+	f.client = pbconnect.NewIngestServiceClient(
+		config.HTTPClient,
+		strings.TrimSuffix(config.HubURL, "/"),
+		connect.WithInterceptors(connect.UnaryInterceptorFunc(f.authenticate)),
+	)
+
+	return f, nil
+}
+
+// ProcessEvent buffers an event for forwarding to the hub
+func (f *Forwarder) ProcessEvent(ctx context.Context, event models.Event) error {
+	f.mu.Lock()
+	f.buffer = append(f.buffer, event)
+	full := len(f.buffer) >= f.config.BatchSize
+	f.mu.Unlock()
+
+	if full {
+		return f.flush()
+	}
+	return nil
+}
+
+// Start starts the flush and delivery loops
+func (f *Forwarder) Start(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.running {
+		return nil
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	f.running = true
+
+	f.wg.Add(2)
+	go f.flushLoop(loopCtx)
+	go f.sendLoop(loopCtx)
+
+	f.logger.Info("Forwarding events of cluster %q to %s (%d batches spooled)",
+		f.config.Cluster, f.config.HubURL, f.spool.Len())
+	return nil
+}
+
+// Stop spools buffered events and stops delivery. Batches that were not
+// delivered yet stay in the spool and are sent after the next start.
+func (f *Forwarder) Stop(ctx context.Context) error {
+	f.mu.Lock()
+	if !f.running {
+		f.mu.Unlock()
+		return nil
+	}
+	f.running = false
+	f.cancel()
+	f.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("timeout waiting for forwarder to stop: %w", ctx.Err())
+	}
+
+	if err := f.flush(); err != nil {
+		return fmt.Errorf("failed to spool buffered events: %w", err)
+	}
+
+	f.logger.Info("Forwarder stopped (%d batches spooled)", f.spool.Len())
+	return nil
+}
+
+// Name returns the component name
+func (f *Forwarder) Name() string {
+	return "agent.forwarder"
+}
+
+// flush moves buffered events to the spool and wakes up the delivery loop
+func (f *Forwarder) flush() error {
+	f.mu.Lock()
+	events := f.buffer
+	f.buffer = nil
+	f.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := f.spool.Append(events); err != nil {
+		f.logger.Error("Failed to spool %d events: %v", len(events), err)
+		return err
+	}
+
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (f *Forwarder) flushLoop(ctx context.Context) {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = f.flush()
+		}
+	}
+}
+
+// sendLoop delivers spooled batches oldest first, backing off while the hub is unreachable
+func (f *Forwarder) sendLoop(ctx context.Context) {
+	defer f.wg.Done()
+
+	backoff := initialRetryBackoff
+	for {
+		batch, err := f.spool.Oldest()
+		if err != nil {
+			f.logger.Error("Failed to read spool: %v", err)
+		}
+
+		if batch == nil || err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-f.notify:
+			case <-time.After(f.config.FlushInterval):
+			}
+			continue
+		}
+
+		if err := f.push(ctx, batch); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if connect.CodeOf(err) == connect.CodeInvalidArgument {
+				// The hub will never accept this batch, retrying would block delivery forever
+				f.logger.Error("Hub rejected batch %d (%d events), dropping it: %v", batch.Seq, len(batch.Events), err)
+				f.spool.Remove(batch.Seq)
+				continue
+			}
+
+			f.logger.Warn("Failed to push batch %d to hub, retrying in %s: %v", batch.Seq, backoff, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxRetryBackoff)
+			continue
+		}
+
+		backoff = initialRetryBackoff
+		f.spool.Remove(batch.Seq)
+	}
+}
+
+func (f *Forwarder) push(ctx context.Context, batch *Batch) error {
+	resp, err := f.client.PushEvents(ctx, connect.NewRequest(&apipb.PushEventsRequest{
+		Cluster: f.config.Cluster,
+		AgentId: f.config.AgentID,
+		Events:  batch.Events,
+	}))
+	if err != nil {
+		return err
+	}
+
+	f.logger.Debug("Pushed batch %d: %d accepted, %d duplicates",
+		batch.Seq, resp.Msg.Accepted, resp.Msg.Duplicates)
+	return nil
+}
+
+// authenticate adds the bearer token to outgoing requests. The token file is
+// re-read on every request so rotated service account tokens are picked up.
+func (f *Forwarder) authenticate(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if f.config.TokenFile != "" {
+			token, err := os.ReadFile(f.config.TokenFile)
+			if err != nil {
+				return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("failed to read hub token: %w", err))
+			}
+			req.Header().Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/api/pb/pbconnect"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// flakyProcessor fails the first batches to simulate an unavailable hub
+type flakyProcessor struct {
+	mu       sync.Mutex
+	failures int
+	events   []models.Event
+}
+
+func (p *flakyProcessor) ProcessBatch(ctx context.Context, events []models.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("graph unavailable")
+	}
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func (p *flakyProcessor) received() []models.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]models.Event(nil), p.events...)
+}
+
+func TestForwarder_DeliversSpooledEventsToHub(t *testing.T) {
+	processor := &flakyProcessor{failures: 1}
+	var authHeader string
+	var authMu sync.Mutex
+
+	service := api.NewIngestConnectService(processor, logging.GetLogger("test"), noop.NewTracerProvider().Tracer("test"))
+	path, handler := pbconnect.NewIngestServiceHandler(service)
+	mux := http.NewServeMux()
+	mux.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authMu.Lock()
+		authHeader = r.Header.Get("Authorization")
+		authMu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	hub := httptest.NewServer(mux)
+	defer hub.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("agent-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	forwarder, err := NewForwarder(Config{
+		HubURL:        hub.URL,
+		Cluster:       "edge-1",
+		AgentID:       "agent-0",
+		TokenFile:     tokenFile,
+		SpoolDir:      t.TempDir(),
+		BatchSize:     2,
+		FlushInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewForwarder() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := forwarder.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	for _, event := range testEvents("e", 3) {
+		if err := forwarder.ProcessEvent(ctx, event); err != nil {
+			t.Fatalf("ProcessEvent() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for len(processor.received()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := forwarder.Stop(stopCtx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	received := processor.received()
+	if len(received) != 3 {
+		t.Fatalf("hub received %d events, want 3", len(received))
+	}
+	for _, event := range received {
+		if event.Resource.Cluster != "edge-1" || models.ClusterOfUID(event.Resource.UID) != "edge-1" {
+			t.Errorf("event %s not tagged with cluster: %+v", event.ID, event.Resource)
+		}
+	}
+	if forwarder.spool.Len() != 0 {
+		t.Errorf("spool has %d batches left after delivery", forwarder.spool.Len())
+	}
+
+	authMu.Lock()
+	defer authMu.Unlock()
+	if authHeader != "Bearer agent-token" {
+		t.Errorf("Authorization header = %q, want bearer token from file", authHeader)
+	}
+}
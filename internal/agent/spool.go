@@ -0,0 +1,259 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	apipb "github.com/moolen/spectre/internal/api/pb"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	modelspb "github.com/moolen/spectre/internal/models/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	segmentSuffix = ".batch"
+	tmpSuffix     = ".tmp"
+)
+
+// Batch is a spooled batch of events waiting to be delivered to the hub
+type Batch struct {
+	Seq    uint64
+	Events []*modelspb.Event
+}
+
+type segment struct {
+	seq  uint64
+	size int64
+}
+
+// Spool is a durable on-disk queue of event batches. Every batch is written
+// to its own segment file and fsynced before Append returns, so events
+// survive agent restarts while the hub is unreachable. Segments are delivered
+// oldest first and removed once the hub acknowledged them.
+type Spool struct {
+	dir      string
+	maxBytes int64 // 0 means unbounded
+	logger   *logging.Logger
+
+	mu       sync.Mutex
+	segments []segment // Ordered oldest first
+	size     int64
+	nextSeq  uint64
+}
+
+// OpenSpool opens the spool in dir, creating the directory if needed, and
+// picks up segments left behind by a previous run. When maxBytes is
+// positive, the oldest segments are dropped once the spool grows beyond it.
+func OpenSpool(dir string, maxBytes int64) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+
+	s := &Spool{
+		dir:      dir,
+		maxBytes: maxBytes,
+		logger:   logging.GetLogger("agent.spool"),
+		nextSeq:  1,
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(name, tmpSuffix) {
+			// Partially written segment from a crash, it was never acknowledged to the watcher
+			_ = os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		if !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat spool segment %s: %w", name, err)
+		}
+		s.segments = append(s.segments, segment{seq: seq, size: info.Size()})
+		s.size += info.Size()
+		if seq >= s.nextSeq {
+			s.nextSeq = seq + 1
+		}
+	}
+
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].seq < s.segments[j].seq })
+
+	if len(s.segments) > 0 {
+		s.logger.Info("Recovered %d spooled batches (%d bytes) from %s", len(s.segments), s.size, dir)
+	}
+
+	return s, nil
+}
+
+// Append durably writes a batch of events to the spool
+func (s *Spool) Append(events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	req := &apipb.PushEventsRequest{Events: make([]*modelspb.Event, 0, len(events))}
+	for i := range events {
+		pbEvent, err := events[i].ToProto()
+		if err != nil {
+			return fmt.Errorf("failed to convert event %s: %w", events[i].ID, err)
+		}
+		req.Events = append(req.Events, pbEvent)
+	}
+
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seq := s.nextSeq
+	path := s.segmentPath(seq)
+	if err := writeFileSync(path+tmpSuffix, data); err != nil {
+		return err
+	}
+	if err := os.Rename(path+tmpSuffix, path); err != nil {
+		return fmt.Errorf("failed to commit spool segment: %w", err)
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	s.nextSeq++
+	s.segments = append(s.segments, segment{seq: seq, size: int64(len(data))})
+	s.size += int64(len(data))
+	s.enforceLimit()
+
+	return nil
+}
+
+// Oldest returns the oldest spooled batch, or nil if the spool is empty.
+// Segments that cannot be decoded are discarded.
+func (s *Spool) Oldest() (*Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.segments) > 0 {
+		seg := s.segments[0]
+		data, err := os.ReadFile(s.segmentPath(seg.seq))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spool segment %d: %w", seg.seq, err)
+		}
+
+		req := &apipb.PushEventsRequest{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			s.logger.Error("Discarding corrupt spool segment %d: %v", seg.seq, err)
+			s.removeLocked(seg.seq)
+			continue
+		}
+
+		return &Batch{Seq: seg.seq, Events: req.Events}, nil
+	}
+
+	return nil, nil
+}
+
+// Remove deletes an acknowledged batch from the spool
+func (s *Spool) Remove(seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(seq)
+}
+
+// Len returns the number of spooled batches
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.segments)
+}
+
+// Size returns the number of bytes currently spooled
+func (s *Spool) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+func (s *Spool) removeLocked(seq uint64) {
+	for i, seg := range s.segments {
+		if seg.seq != seq {
+			continue
+		}
+		if err := os.Remove(s.segmentPath(seq)); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("Failed to remove spool segment %d: %v", seq, err)
+		}
+		s.size -= seg.size
+		s.segments = append(s.segments[:i], s.segments[i+1:]...)
+		return
+	}
+}
+
+// enforceLimit drops the oldest segments while the spool exceeds its size
+// limit. The newest segment is always kept.
+func (s *Spool) enforceLimit() {
+	if s.maxBytes <= 0 {
+		return
+	}
+	dropped := 0
+	for s.size > s.maxBytes && len(s.segments) > 1 {
+		s.removeLocked(s.segments[0].seq)
+		dropped++
+	}
+	if dropped > 0 {
+		s.logger.Warn("Spool exceeded %d bytes, dropped %d oldest batches", s.maxBytes, dropped)
+	}
+}
+
+func (s *Spool) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, segmentSuffix))
+}
+
+// writeFileSync writes data to path and fsyncs it
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to create spool segment: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write spool segment: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync spool segment: %w", err)
+	}
+	return f.Close()
+}
+
+// syncDir fsyncs a directory so that renames within it are durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open spool directory: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync spool directory: %w", err)
+	}
+	return nil
+}
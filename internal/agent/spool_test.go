@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moolen/spectre/internal/models"
+)
+
+func testEvents(prefix string, n int) []models.Event {
+	events := make([]models.Event, n)
+	for i := range events {
+		events[i] = models.Event{
+			ID:        fmt.Sprintf("%s-%d", prefix, i),
+			Timestamp: int64(i + 1),
+			Type:      models.EventTypeUpdate,
+			Resource: models.ResourceMetadata{
+				Version:   "v1",
+				Kind:      "Pod",
+				Namespace: "default",
+				Name:      fmt.Sprintf("pod-%d", i),
+				UID:       fmt.Sprintf("uid-%d", i),
+			},
+			Data: []byte(`{"kind":"Pod"}`),
+		}
+	}
+	return events
+}
+
+func TestSpool_AppendOldestRemove(t *testing.T) {
+	spool, err := OpenSpool(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("OpenSpool() error = %v", err)
+	}
+
+	if batch, err := spool.Oldest(); err != nil || batch != nil {
+		t.Fatalf("Oldest() on empty spool = %v, %v; want nil, nil", batch, err)
+	}
+
+	if err := spool.Append(testEvents("a", 2)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := spool.Append(testEvents("b", 3)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if spool.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", spool.Len())
+	}
+
+	batch, err := spool.Oldest()
+	if err != nil {
+		t.Fatalf("Oldest() error = %v", err)
+	}
+	if len(batch.Events) != 2 || batch.Events[0].Id != "a-0" {
+		t.Fatalf("Oldest() = %+v, want first batch", batch.Events)
+	}
+
+	spool.Remove(batch.Seq)
+
+	batch, err = spool.Oldest()
+	if err != nil {
+		t.Fatalf("Oldest() error = %v", err)
+	}
+	if len(batch.Events) != 3 || batch.Events[0].Id != "b-0" {
+		t.Fatalf("Oldest() after Remove = %+v, want second batch", batch.Events)
+	}
+}
+
+func TestSpool_RecoversAfterReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	spool, err := OpenSpool(dir, 0)
+	if err != nil {
+		t.Fatalf("OpenSpool() error = %v", err)
+	}
+	for _, prefix := range []string{"a", "b"} {
+		if err := spool.Append(testEvents(prefix, 1)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	// Leftover of a write interrupted by a crash
+	if err := os.WriteFile(filepath.Join(dir, "00000000000000000099.batch.tmp"), []byte("partial"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenSpool(dir, 0)
+	if err != nil {
+		t.Fatalf("OpenSpool() error = %v", err)
+	}
+	if reopened.Len() != 2 {
+		t.Fatalf("Len() after reopen = %d, want 2", reopened.Len())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000099.batch.tmp")); !os.IsNotExist(err) {
+		t.Errorf("expected partial segment to be removed, stat error = %v", err)
+	}
+
+	// New batches are ordered after the recovered ones
+	if err := reopened.Append(testEvents("c", 1)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	var ids []string
+	for {
+		batch, err := reopened.Oldest()
+		if err != nil {
+			t.Fatalf("Oldest() error = %v", err)
+		}
+		if batch == nil {
+			break
+		}
+		ids = append(ids, batch.Events[0].Id)
+		reopened.Remove(batch.Seq)
+	}
+	if len(ids) != 3 || ids[0] != "a-0" || ids[1] != "b-0" || ids[2] != "c-0" {
+		t.Errorf("delivery order = %v, want [a-0 b-0 c-0]", ids)
+	}
+}
+
+func TestSpool_DropsOldestOverLimit(t *testing.T) {
+	spool, err := OpenSpool(t.TempDir(), 1)
+	if err != nil {
+		t.Fatalf("OpenSpool() error = %v", err)
+	}
+
+	for _, prefix := range []string{"a", "b", "c"} {
+		if err := spool.Append(testEvents(prefix, 1)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	if spool.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", spool.Len())
+	}
+	batch, err := spool.Oldest()
+	if err != nil {
+		t.Fatalf("Oldest() error = %v", err)
+	}
+	if batch.Events[0].Id != "c-0" {
+		t.Errorf("Oldest() = %s, want newest batch c-0 to be kept", batch.Events[0].Id)
+	}
+}
+
+func TestSpool_DiscardsCorruptSegment(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "00000000000000000001.batch"), []byte{0xff, 0xff, 0xff}, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	spool, err := OpenSpool(dir, 0)
+	if err != nil {
+		t.Fatalf("OpenSpool() error = %v", err)
+	}
+	if err := spool.Append(testEvents("a", 1)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	batch, err := spool.Oldest()
+	if err != nil {
+		t.Fatalf("Oldest() error = %v", err)
+	}
+	if batch == nil || batch.Events[0].Id != "a-0" {
+		t.Fatalf("Oldest() = %+v, want batch a-0 after corrupt segment", batch)
+	}
+	if spool.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", spool.Len())
+	}
+}
@@ -235,6 +235,15 @@ func (a *RootCauseAnalyzer) applyDiffFormat(graph *CausalGraph, failureTime time
 		// Convert ChangeEvent to diff format
 		if node.ChangeEvent != nil && node.ChangeEvent.Data != nil {
 			ConvertSingleEventToDiff(node.ChangeEvent, nil, true)
+
+			// The snapshot has no diff of its own, take the managers of the
+			// change from the event history
+			for _, event := range node.AllEvents {
+				if event.EventID == node.ChangeEvent.EventID {
+					node.ChangeEvent.Managers = event.Managers
+					break
+				}
+			}
 		}
 	}
 }
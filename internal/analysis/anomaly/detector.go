@@ -105,45 +105,7 @@ func (d *AnomalyDetector) Detect(ctx context.Context, input DetectInput) (*Anoma
 	for i := range result.Incident.Graph.Nodes {
 		node := &result.Incident.Graph.Nodes[i]
 
-		detectorInput := DetectorInput{
-			Node:       node,
-			TimeWindow: timeWindow,
-			AllEvents:  node.AllEvents,
-			K8sEvents:  node.K8sEvents,
-		}
-
-		var nodeAnomalies []Anomaly
-
-		// Run all detectors
-		eventAnomalies := d.eventDetector.Detect(detectorInput)
-		d.logger.Debug("Node %s (%s): %d event anomalies",
-			node.Resource.Name, node.Resource.Kind, len(eventAnomalies))
-		nodeAnomalies = append(nodeAnomalies, eventAnomalies...)
-
-		stateAnomalies := d.stateDetector.Detect(detectorInput)
-		d.logger.Debug("Node %s (%s): %d state anomalies",
-			node.Resource.Name, node.Resource.Kind, len(stateAnomalies))
-		nodeAnomalies = append(nodeAnomalies, stateAnomalies...)
-
-		changeAnomalies := d.changeDetector.Detect(detectorInput)
-		d.logger.Debug("Node %s (%s): %d change anomalies",
-			node.Resource.Name, node.Resource.Kind, len(changeAnomalies))
-		nodeAnomalies = append(nodeAnomalies, changeAnomalies...)
-
-		frequencyAnomalies := d.frequencyDetector.Detect(detectorInput)
-		d.logger.Debug("Node %s (%s): %d frequency anomalies",
-			node.Resource.Name, node.Resource.Kind, len(frequencyAnomalies))
-		nodeAnomalies = append(nodeAnomalies, frequencyAnomalies...)
-
-		configAnomalies := d.configDetector.Detect(detectorInput)
-		d.logger.Debug("Node %s (%s): %d config anomalies",
-			node.Resource.Name, node.Resource.Kind, len(configAnomalies))
-		nodeAnomalies = append(nodeAnomalies, configAnomalies...)
-
-		networkAnomalies := d.networkDetector.Detect(detectorInput)
-		d.logger.Debug("Node %s (%s): %d network anomalies",
-			node.Resource.Name, node.Resource.Kind, len(networkAnomalies))
-		nodeAnomalies = append(nodeAnomalies, networkAnomalies...)
+		nodeAnomalies := d.DetectNodeAnomalies(node, timeWindow)
 
 		// Store for graph-level detection
 		nodeAnomaliesMap[node.ID] = nodeAnomalies
@@ -170,6 +132,77 @@ func (d *AnomalyDetector) Detect(ctx context.Context, input DetectInput) (*Anoma
 	}, nil
 }
 
+// DetectNodeAnomalies runs the node-level detectors on a single graph node.
+// Graph-level anomalies, which need the node's neighbours, are not included.
+func (d *AnomalyDetector) DetectNodeAnomalies(node *analysis.GraphNode, timeWindow TimeWindow) []Anomaly {
+	detectorInput := DetectorInput{
+		Node:       node,
+		TimeWindow: timeWindow,
+		AllEvents:  node.AllEvents,
+		K8sEvents:  node.K8sEvents,
+	}
+
+	var nodeAnomalies []Anomaly
+
+	// Run all detectors
+	eventAnomalies := d.eventDetector.Detect(detectorInput)
+	d.logger.Debug("Node %s (%s): %d event anomalies",
+		node.Resource.Name, node.Resource.Kind, len(eventAnomalies))
+	nodeAnomalies = append(nodeAnomalies, eventAnomalies...)
+
+	stateAnomalies := d.stateDetector.Detect(detectorInput)
+	d.logger.Debug("Node %s (%s): %d state anomalies",
+		node.Resource.Name, node.Resource.Kind, len(stateAnomalies))
+	nodeAnomalies = append(nodeAnomalies, stateAnomalies...)
+
+	changeAnomalies := d.changeDetector.Detect(detectorInput)
+	d.logger.Debug("Node %s (%s): %d change anomalies",
+		node.Resource.Name, node.Resource.Kind, len(changeAnomalies))
+	nodeAnomalies = append(nodeAnomalies, changeAnomalies...)
+
+	frequencyAnomalies := d.frequencyDetector.Detect(detectorInput)
+	d.logger.Debug("Node %s (%s): %d frequency anomalies",
+		node.Resource.Name, node.Resource.Kind, len(frequencyAnomalies))
+	nodeAnomalies = append(nodeAnomalies, frequencyAnomalies...)
+
+	configAnomalies := d.configDetector.Detect(detectorInput)
+	d.logger.Debug("Node %s (%s): %d config anomalies",
+		node.Resource.Name, node.Resource.Kind, len(configAnomalies))
+	nodeAnomalies = append(nodeAnomalies, configAnomalies...)
+
+	networkAnomalies := d.networkDetector.Detect(detectorInput)
+	d.logger.Debug("Node %s (%s): %d network anomalies",
+		node.Resource.Name, node.Resource.Kind, len(networkAnomalies))
+	nodeAnomalies = append(nodeAnomalies, networkAnomalies...)
+
+	return nodeAnomalies
+}
+
+// DetectResourceAnomalies runs the node-level detectors on each of the given
+// resources, using their events within the time window. The result is keyed by
+// resource UID and only contains resources with at least one anomaly.
+func (d *AnomalyDetector) DetectResourceAnomalies(
+	ctx context.Context,
+	resources []analysis.SymptomResource,
+	timeWindow TimeWindow,
+) (map[string][]Anomaly, error) {
+	nodes, err := d.analyzer.BuildResourceNodes(ctx, resources,
+		timeWindow.End.UnixNano(), timeWindow.End.Sub(timeWindow.Start).Nanoseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load resource events: %w", err)
+	}
+
+	anomalies := make(map[string][]Anomaly)
+	for i := range nodes {
+		nodeAnomalies := deduplicateAnomalies(d.DetectNodeAnomalies(&nodes[i], timeWindow))
+		if len(nodeAnomalies) > 0 {
+			anomalies[nodes[i].Resource.UID] = nodeAnomalies
+		}
+	}
+
+	return anomalies, nil
+}
+
 // detectGraphLevelAnomalies detects anomalies that require graph context
 // (e.g., Service with no ready endpoints based on SELECTS edges)
 func (d *AnomalyDetector) detectGraphLevelAnomalies(
@@ -0,0 +1,484 @@
+// Package blastradius finds the resources that degraded after a change to an
+// upstream resource, following the relationships along which impact propagates.
+// It is the forward-looking counterpart of causal path discovery: causal paths
+// start at a symptom and look for its cause, blast radius starts at a change
+// and looks for its effects.
+package blastradius
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/moolen/spectre/internal/analysis"
+	"github.com/moolen/spectre/internal/analysis/anomaly"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+// ErrResourceNotFound is returned when the changed resource is not in the graph
+var ErrResourceNotFound = errors.New("resource not found")
+
+// propagation is the set of directions in which a relationship carries impact
+type propagation int
+
+const (
+	propagatesForward propagation = 1 << iota // From the edge's source to its target
+	propagatesReverse                         // From the edge's target to its source
+)
+
+// relationshipPropagation lists the relationships that are traversed and the
+// direction in which a change on one end affects the other
+var relationshipPropagation = map[string]propagation{
+	"OWNS":             propagatesForward, // Deployment change rolls out to ReplicaSets and Pods
+	"MANAGES":          propagatesForward, // HelmRelease/Kustomization change reaches managed resources
+	"CREATES_OBSERVED": propagatesForward,
+	"GRANTS_TO":        propagatesForward, // RoleBinding change alters the subject's permissions
+
+	"REFERENCES_SPEC":      propagatesReverse, // ConfigMap/Secret change affects the resources referencing it
+	"MOUNTS":               propagatesReverse, // PVC change affects the Pods mounting it
+	"SCHEDULED_ON":         propagatesReverse, // Node cordon or pressure affects its Pods
+	"USES_SERVICE_ACCOUNT": propagatesReverse, // ServiceAccount change affects its Pods
+	"BINDS_ROLE":           propagatesReverse, // Role change affects the bindings to it
+
+	// NetworkPolicy changes affect the selected Pods, failing Pods leave the
+	// selecting Service without endpoints
+	"SELECTS": propagatesForward | propagatesReverse,
+}
+
+// degradationCategories are the anomaly categories that indicate a resource is
+// unhealthy, as opposed to merely changed
+var degradationCategories = map[anomaly.AnomalyCategory]bool{
+	anomaly.CategoryState:     true,
+	anomaly.CategoryEvent:     true,
+	anomaly.CategoryFrequency: true,
+	anomaly.CategoryNetwork:   true,
+}
+
+var severityScores = map[anomaly.Severity]float64{
+	anomaly.SeverityCritical: 1.0,
+	anomaly.SeverityHigh:     0.75,
+	anomaly.SeverityMedium:   0.5,
+	anomaly.SeverityLow:      0.25,
+}
+
+// Analyzer computes the blast radius of resource changes
+type Analyzer struct {
+	graphClient graph.Client
+	detector    *anomaly.AnomalyDetector
+	logger      *logging.Logger
+}
+
+// NewAnalyzer creates a new blast radius analyzer
+func NewAnalyzer(graphClient graph.Client) *Analyzer {
+	return &Analyzer{
+		graphClient: graphClient,
+		detector:    anomaly.NewDetector(graphClient),
+		logger:      logging.GetLogger("blastradius"),
+	}
+}
+
+// candidate is a resource in reach of the change that changed itself within
+// the window, with the shortest path along which impact propagates
+type candidate struct {
+	resource analysis.SymptomResource
+	pathUIDs []string // From the changed resource to this one
+	relTypes []string
+	reverse  []bool
+}
+
+// Analyze returns the resources that degraded within the window after the
+// change, ranked by impact
+func (a *Analyzer) Analyze(ctx context.Context, input BlastRadiusInput) (*BlastRadiusResponse, error) {
+	startTime := time.Now()
+	applyDefaults(&input)
+
+	trigger, err := a.getResource(ctx, input.ResourceUID)
+	if err != nil {
+		return nil, err
+	}
+	if trigger == nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, input.ResourceUID)
+	}
+
+	candidates, truncated, err := a.findCandidates(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Debug("Blast radius of %s: %d candidates changed within the window", input.ResourceUID, len(candidates))
+
+	window := anomaly.TimeWindow{
+		Start: time.Unix(0, input.ChangeTimestamp),
+		End:   time.Unix(0, input.ChangeTimestamp+input.WindowNs),
+	}
+
+	resources := make([]analysis.SymptomResource, 0, len(candidates))
+	for _, c := range candidates {
+		resources = append(resources, c.resource)
+	}
+	anomalies, err := a.detector.DetectResourceAnomalies(ctx, resources, window)
+	if err != nil {
+		return nil, err
+	}
+
+	pathResources, err := a.getPathResources(ctx, *trigger, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	impacted := make([]ImpactedResource, 0)
+	for _, c := range candidates {
+		degradation := degradationAnomalies(anomalies[c.resource.UID], window)
+		if len(degradation) == 0 {
+			continue
+		}
+		impacted = append(impacted, buildImpactedResource(c, degradation, pathResources, input))
+	}
+
+	sort.SliceStable(impacted, func(i, j int) bool {
+		if impacted[i].ImpactScore != impacted[j].ImpactScore {
+			return impacted[i].ImpactScore > impacted[j].ImpactScore
+		}
+		if impacted[i].Distance != impacted[j].Distance {
+			return impacted[i].Distance < impacted[j].Distance
+		}
+		if !impacted[i].FirstDegradedAt.Equal(impacted[j].FirstDegradedAt) {
+			return impacted[i].FirstDegradedAt.Before(impacted[j].FirstDegradedAt)
+		}
+		return impacted[i].Resource.UID < impacted[j].Resource.UID
+	})
+
+	impactedCount := len(impacted)
+	groups := buildGroups(impacted)
+	if len(impacted) > input.Limit {
+		impacted = impacted[:input.Limit]
+	}
+
+	return &BlastRadiusResponse{
+		Trigger:  *trigger,
+		Impacted: impacted,
+		Groups:   groups,
+		Metadata: ResponseMetadata{
+			ChangeTimestamp:    window.Start,
+			WindowEnd:          window.End,
+			QueryExecutionMs:   time.Since(startTime).Milliseconds(),
+			AlgorithmVersion:   AlgorithmVersion,
+			ExecutedAt:         time.Now(),
+			CandidatesExplored: len(candidates),
+			ImpactedCount:      impactedCount,
+			Truncated:          truncated,
+		},
+	}, nil
+}
+
+// applyDefaults fills in and clamps the optional input parameters
+func applyDefaults(input *BlastRadiusInput) {
+	if input.WindowNs <= 0 {
+		input.WindowNs = DefaultWindowNs
+	}
+	if input.WindowNs > MaxWindowNs {
+		input.WindowNs = MaxWindowNs
+	}
+	if input.MaxDepth < MinMaxDepth || input.MaxDepth > MaxMaxDepth {
+		input.MaxDepth = DefaultMaxDepth
+	}
+	if input.Limit <= 0 {
+		input.Limit = DefaultLimit
+	}
+	if input.Limit > MaxLimit {
+		input.Limit = MaxLimit
+	}
+}
+
+// getResource returns the resource with the UID, or nil if it is not in the graph
+func (a *Analyzer) getResource(ctx context.Context, uid string) (*analysis.SymptomResource, error) {
+	result, err := a.graphClient.ExecuteQuery(ctx, graph.FindResourceByUIDQuery(uid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up resource: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		props, err := graph.ParseNodeFromResult(row[0])
+		if err != nil {
+			continue
+		}
+		resource := toSymptomResource(graph.ParseResourceIdentityFromNode(props))
+		return &resource, nil
+	}
+	return nil, nil
+}
+
+// findCandidates runs the blast radius query and keeps, per resource, the
+// shortest path whose every hop propagates impact away from the change. It
+// reports whether the query hit its row limit, in which case candidates
+// further away may be missing.
+func (a *Analyzer) findCandidates(ctx context.Context, input BlastRadiusInput) ([]candidate, bool, error) {
+	var forwardTypes, reverseTypes []string
+	for relType, direction := range relationshipPropagation {
+		if direction&propagatesForward != 0 {
+			forwardTypes = append(forwardTypes, relType)
+		}
+		if direction&propagatesReverse != 0 {
+			reverseTypes = append(reverseTypes, relType)
+		}
+	}
+	sort.Strings(forwardTypes)
+	sort.Strings(reverseTypes)
+
+	query := graph.CalculateBlastRadiusQuery(input.ResourceUID, input.ChangeTimestamp,
+		input.WindowNs/int64(time.Millisecond), forwardTypes, reverseTypes)
+	query.Timeout = QueryTimeoutMs
+
+	result, err := a.graphClient.ExecuteQuery(ctx, query)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query blast radius: %w", err)
+	}
+	truncated := len(result.Rows) >= graph.BlastRadiusQueryLimit
+	if truncated {
+		a.logger.Warn("Blast radius query of %s returned %d paths, results may be incomplete",
+			input.ResourceUID, len(result.Rows))
+	}
+
+	byUID := make(map[string]*candidate)
+	var order []string
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		props, err := graph.ParseNodeFromResult(row[0])
+		if err != nil {
+			a.logger.Warn("Failed to parse impacted resource node: %v", err)
+			continue
+		}
+		c := candidate{
+			resource: toSymptomResource(graph.ParseResourceIdentityFromNode(props)),
+			pathUIDs: toStrings(row[1]),
+			relTypes: toStrings(row[2]),
+		}
+		sources := toStrings(row[3])
+		if len(c.relTypes) == 0 || len(c.relTypes) > input.MaxDepth ||
+			len(c.pathUIDs) != len(c.relTypes)+1 || len(sources) != len(c.relTypes) {
+			continue
+		}
+		if !propagates(&c, sources) {
+			continue
+		}
+
+		if existing, ok := byUID[c.resource.UID]; ok {
+			if len(existing.relTypes) <= len(c.relTypes) {
+				continue
+			}
+		} else {
+			order = append(order, c.resource.UID)
+		}
+		byUID[c.resource.UID] = &c
+	}
+
+	candidates := make([]candidate, 0, len(order))
+	for _, uid := range order {
+		candidates = append(candidates, *byUID[uid])
+	}
+	return candidates, truncated, nil
+}
+
+// propagates records the direction of each hop of the candidate's path and
+// reports whether impact propagates along all of them. Paths that visit a
+// resource twice are rejected.
+func propagates(c *candidate, sources []string) bool {
+	seen := make(map[string]bool, len(c.pathUIDs))
+	for _, uid := range c.pathUIDs {
+		if seen[uid] {
+			return false
+		}
+		seen[uid] = true
+	}
+
+	c.reverse = make([]bool, len(c.relTypes))
+	for i, relType := range c.relTypes {
+		reverse := sources[i] != c.pathUIDs[i]
+		direction := propagatesForward
+		if reverse {
+			direction = propagatesReverse
+		}
+		if relationshipPropagation[relType]&direction == 0 {
+			return false
+		}
+		c.reverse[i] = reverse
+	}
+	return true
+}
+
+// getPathResources returns the resources on the candidates' paths, keyed by UID
+func (a *Analyzer) getPathResources(ctx context.Context, trigger analysis.SymptomResource, candidates []candidate) (map[string]analysis.SymptomResource, error) {
+	resources := map[string]analysis.SymptomResource{trigger.UID: trigger}
+	for _, c := range candidates {
+		resources[c.resource.UID] = c.resource
+	}
+
+	var missing []string
+	for _, c := range candidates {
+		for _, uid := range c.pathUIDs {
+			if _, ok := resources[uid]; !ok {
+				resources[uid] = analysis.SymptomResource{UID: uid}
+				missing = append(missing, uid)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return resources, nil
+	}
+
+	result, err := a.graphClient.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (r:ResourceIdentity)
+			WHERE r.uid IN $uids
+			RETURN r
+		`,
+		Parameters: map[string]interface{}{"uids": missing},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query path resources: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		props, err := graph.ParseNodeFromResult(row[0])
+		if err != nil {
+			continue
+		}
+		resource := toSymptomResource(graph.ParseResourceIdentityFromNode(props))
+		resources[resource.UID] = resource
+	}
+	return resources, nil
+}
+
+// degradationAnomalies returns the anomalies that indicate degradation within
+// the window
+func degradationAnomalies(anomalies []anomaly.Anomaly, window anomaly.TimeWindow) []anomaly.Anomaly {
+	var degradation []anomaly.Anomaly
+	for _, a := range anomalies {
+		if !degradationCategories[a.Category] || a.Timestamp.Before(window.Start) {
+			continue
+		}
+		degradation = append(degradation, a)
+	}
+	return degradation
+}
+
+// buildImpactedResource scores a degraded candidate. The impact score weighs
+// the worst severity, the distance from the change and how soon after the
+// change the degradation started.
+func buildImpactedResource(
+	c candidate,
+	degradation []anomaly.Anomaly,
+	pathResources map[string]analysis.SymptomResource,
+	input BlastRadiusInput,
+) ImpactedResource {
+	maxSeverity := anomaly.SeverityLow
+	firstDegradedAt := degradation[0].Timestamp
+	for _, a := range degradation {
+		if severityScores[a.Severity] > severityScores[maxSeverity] {
+			maxSeverity = a.Severity
+		}
+		if a.Timestamp.Before(firstDegradedAt) {
+			firstDegradedAt = a.Timestamp
+		}
+	}
+
+	distance := len(c.relTypes)
+	distanceScore := 1.0 - float64(distance-1)/float64(MaxMaxDepth)
+
+	lag := firstDegradedAt.UnixNano() - input.ChangeTimestamp
+	temporalScore := 1.0 - float64(lag)/float64(input.WindowNs)
+	temporalScore = max(0, min(1, temporalScore))
+
+	score := severityScores[maxSeverity]*WeightSeverity +
+		distanceScore*WeightDistance +
+		temporalScore*WeightTemporal
+
+	path := make([]Hop, len(c.relTypes))
+	for i, relType := range c.relTypes {
+		path[i] = Hop{
+			RelationshipType: relType,
+			Reverse:          c.reverse[i],
+			Resource:         pathResources[c.pathUIDs[i+1]],
+		}
+	}
+
+	return ImpactedResource{
+		Resource:         c.resource,
+		Distance:         distance,
+		RelationshipType: c.relTypes[distance-1],
+		Path:             path,
+		FirstDegradedAt:  firstDegradedAt,
+		MaxSeverity:      maxSeverity,
+		ImpactScore:      score,
+		Anomalies:        degradation,
+	}
+}
+
+// buildGroups groups the impacted resources by relationship type and distance,
+// nearest first
+func buildGroups(impacted []ImpactedResource) []ImpactGroup {
+	type groupKey struct {
+		relType  string
+		distance int
+	}
+
+	index := make(map[groupKey]int)
+	groups := make([]ImpactGroup, 0)
+	for _, resource := range impacted {
+		key := groupKey{relType: resource.RelationshipType, distance: resource.Distance}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ImpactGroup{
+				RelationshipType: key.relType,
+				Distance:         key.distance,
+				MaxSeverity:      anomaly.SeverityLow,
+			})
+		}
+		group := &groups[i]
+		group.Count++
+		group.Resources = append(group.Resources, resource.Resource)
+		if severityScores[resource.MaxSeverity] > severityScores[group.MaxSeverity] {
+			group.MaxSeverity = resource.MaxSeverity
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Distance != groups[j].Distance {
+			return groups[i].Distance < groups[j].Distance
+		}
+		return groups[i].RelationshipType < groups[j].RelationshipType
+	})
+	return groups
+}
+
+func toSymptomResource(ri graph.ResourceIdentity) analysis.SymptomResource {
+	return analysis.SymptomResource{
+		UID:       ri.UID,
+		Kind:      ri.Kind,
+		Namespace: ri.Namespace,
+		Name:      ri.Name,
+	}
+}
+
+func toStrings(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		s, _ := item.(string)
+		result = append(result, s)
+	}
+	return result
+}
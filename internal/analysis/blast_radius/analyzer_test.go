@@ -0,0 +1,166 @@
+package blastradius
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/analysis"
+	"github.com/moolen/spectre/internal/analysis/anomaly"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// changeTs is the time of the ConfigMap edit in the test graph
+const changeTs = int64(1_000_000_000_000)
+
+func newTestAnalyzer(t *testing.T) *Analyzer {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	// The ConfigMap is edited at changeTs. The Pod and Deployment referencing
+	// it change afterwards, the Pod and the Deployment's Pod start failing.
+	_, err := client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			CREATE (cm:ResourceIdentity {uid: 'cm', kind: 'ConfigMap', namespace: 'shop', name: 'settings'})
+			CREATE (podA:ResourceIdentity {uid: 'pod-a', kind: 'Pod', namespace: 'shop', name: 'worker'})
+			CREATE (deploy:ResourceIdentity {uid: 'deploy', kind: 'Deployment', namespace: 'shop', name: 'api'})
+			CREATE (podB:ResourceIdentity {uid: 'pod-b', kind: 'Pod', namespace: 'shop', name: 'api-1'})
+			CREATE (other:ResourceIdentity {uid: 'other', kind: 'Secret', namespace: 'shop', name: 'creds'})
+			CREATE (late:ResourceIdentity {uid: 'late', kind: 'Pod', namespace: 'shop', name: 'batch'})
+			CREATE (podA)-[:REFERENCES_SPEC]->(cm)
+			CREATE (deploy)-[:REFERENCES_SPEC]->(cm)
+			CREATE (deploy)-[:OWNS]->(podB)
+			CREATE (cm)-[:REFERENCES_SPEC]->(other)
+			CREATE (late)-[:REFERENCES_SPEC]->(cm)
+			CREATE (cm)-[:CHANGED]->(:ChangeEvent {id: 'ce-cm', timestamp: $t0, eventType: 'UPDATE', configChanged: true})
+			CREATE (podA)-[:CHANGED]->(:ChangeEvent {id: 'ce-a', timestamp: $t0 + 60000000000, eventType: 'UPDATE', statusChanged: true})
+			CREATE (podA)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-a', timestamp: $t0 + 90000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off restarting failed container', count: 3})
+			CREATE (deploy)-[:CHANGED]->(:ChangeEvent {id: 'ce-d', timestamp: $t0 + 30000000000, eventType: 'UPDATE', configChanged: true})
+			CREATE (podB)-[:CHANGED]->(:ChangeEvent {id: 'ce-b', timestamp: $t0 + 120000000000, eventType: 'UPDATE', statusChanged: true})
+			CREATE (podB)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-b', timestamp: $t0 + 150000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off restarting failed container', count: 2})
+			CREATE (other)-[:CHANGED]->(:ChangeEvent {id: 'ce-o', timestamp: $t0 + 60000000000, eventType: 'UPDATE', statusChanged: true})
+			CREATE (other)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-o', timestamp: $t0 + 60000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off', count: 1})
+			CREATE (late)-[:CHANGED]->(:ChangeEvent {id: 'ce-l', timestamp: $t0 + 3600000000000, eventType: 'UPDATE', statusChanged: true})
+			CREATE (late)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-l', timestamp: $t0 + 3600000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off', count: 1})
+		`,
+		Parameters: map[string]interface{}{"t0": changeTs},
+	})
+	require.NoError(t, err)
+
+	return NewAnalyzer(client)
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	result, err := analyzer.Analyze(context.Background(), BlastRadiusInput{
+		ResourceUID:     "cm",
+		ChangeTimestamp: changeTs,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ConfigMap", result.Trigger.Kind)
+	assert.Equal(t, "settings", result.Trigger.Name)
+
+	// The Secret is referenced by the ConfigMap rather than referencing it,
+	// the late Pod changed outside of the window
+	require.Len(t, result.Impacted, 2)
+
+	podA := result.Impacted[0]
+	assert.Equal(t, "pod-a", podA.Resource.UID)
+	assert.Equal(t, 1, podA.Distance)
+	assert.Equal(t, "REFERENCES_SPEC", podA.RelationshipType)
+	require.Len(t, podA.Path, 1)
+	assert.True(t, podA.Path[0].Reverse)
+	assert.Equal(t, time.Unix(0, changeTs+90_000_000_000), podA.FirstDegradedAt)
+	assert.NotEmpty(t, podA.Anomalies)
+	for _, a := range podA.Anomalies {
+		assert.NotEqual(t, anomaly.CategoryChange, a.Category)
+	}
+
+	podB := result.Impacted[1]
+	assert.Equal(t, "pod-b", podB.Resource.UID)
+	assert.Equal(t, 2, podB.Distance)
+	assert.Equal(t, "OWNS", podB.RelationshipType)
+	require.Len(t, podB.Path, 2)
+	assert.Equal(t, "deploy", podB.Path[0].Resource.UID)
+	assert.Equal(t, "Deployment", podB.Path[0].Resource.Kind)
+	assert.True(t, podB.Path[0].Reverse)
+	assert.False(t, podB.Path[1].Reverse)
+	assert.Greater(t, podA.ImpactScore, podB.ImpactScore)
+
+	require.Len(t, result.Groups, 2)
+	assert.Equal(t, ImpactGroup{
+		RelationshipType: "REFERENCES_SPEC",
+		Distance:         1,
+		Count:            1,
+		MaxSeverity:      podA.MaxSeverity,
+		Resources:        []analysis.SymptomResource{podA.Resource},
+	}, result.Groups[0])
+	assert.Equal(t, "OWNS", result.Groups[1].RelationshipType)
+
+	// The healthy Deployment is a candidate but not impacted
+	assert.Equal(t, 3, result.Metadata.CandidatesExplored)
+	assert.Equal(t, 2, result.Metadata.ImpactedCount)
+	assert.False(t, result.Metadata.Truncated)
+}
+
+func TestAnalyzer_QueryFollowsPropagation(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	// Paths that don't propagate impact are dropped by the query, so they
+	// don't count towards its row limit
+	query := graph.CalculateBlastRadiusQuery("cm", changeTs, int64(DefaultWindowNs/int64(time.Millisecond)),
+		[]string{"OWNS"}, []string{"REFERENCES_SPEC"})
+	result, err := analyzer.graphClient.ExecuteQuery(context.Background(), query)
+	require.NoError(t, err)
+
+	var uids []string
+	for _, row := range result.Rows {
+		props, err := graph.ParseNodeFromResult(row[0])
+		require.NoError(t, err)
+		uids = append(uids, graph.ParseResourceIdentityFromNode(props).UID)
+	}
+	assert.ElementsMatch(t, []string{"pod-a", "deploy", "pod-b"}, uids)
+}
+
+func TestAnalyzer_AnalyzeLimits(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	result, err := analyzer.Analyze(context.Background(), BlastRadiusInput{
+		ResourceUID:     "cm",
+		ChangeTimestamp: changeTs,
+		MaxDepth:        1,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Impacted, 1)
+	assert.Equal(t, "pod-a", result.Impacted[0].Resource.UID)
+
+	result, err = analyzer.Analyze(context.Background(), BlastRadiusInput{
+		ResourceUID:     "cm",
+		ChangeTimestamp: changeTs,
+		Limit:           1,
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Impacted, 1)
+	assert.Equal(t, 2, result.Metadata.ImpactedCount)
+	assert.Len(t, result.Groups, 2)
+}
+
+func TestAnalyzer_AnalyzeUnknownResource(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	_, err := analyzer.Analyze(context.Background(), BlastRadiusInput{
+		ResourceUID:     "missing",
+		ChangeTimestamp: changeTs,
+	})
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
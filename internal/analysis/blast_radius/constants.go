@@ -0,0 +1,31 @@
+package blastradius
+
+import "time"
+
+// Algorithm version for reproducibility tracking
+const AlgorithmVersion = "v1.0-forward"
+
+// Default input parameters
+const (
+	DefaultWindowNs = int64(15 * time.Minute) // Window after the change in which impact is attributed to it
+	DefaultMaxDepth = 3                       // Maximum hops from the changed resource
+	DefaultLimit    = 50                      // Maximum impacted resources to return
+)
+
+// Input validation limits
+const (
+	MinMaxDepth = 1
+	MaxMaxDepth = 3 // Bounded by CalculateBlastRadiusQuery
+	MaxWindowNs = int64(6 * time.Hour)
+	MaxLimit    = 500
+)
+
+// Ranking weights (sum to 1.0)
+const (
+	WeightSeverity = 0.50 // Worse degradation ranks higher
+	WeightDistance = 0.30 // Fewer hops from the change ranks higher
+	WeightTemporal = 0.20 // Degradation soon after the change ranks higher
+)
+
+// QueryTimeoutMs is the timeout for the traversal query in milliseconds
+const QueryTimeoutMs = 15000
@@ -0,0 +1,70 @@
+package blastradius
+
+import (
+	"time"
+
+	"github.com/moolen/spectre/internal/analysis"
+	"github.com/moolen/spectre/internal/analysis/anomaly"
+)
+
+// BlastRadiusInput defines input parameters for blast radius analysis
+type BlastRadiusInput struct {
+	ResourceUID     string // Changed resource UID (required)
+	ChangeTimestamp int64  // Unix nanoseconds (required)
+	WindowNs        int64  // Window after the change in nanoseconds (default: 15 minutes)
+	MaxDepth        int    // Maximum hops from the changed resource (default: 3)
+	Limit           int    // Maximum impacted resources to return (default: 50)
+}
+
+// BlastRadiusResponse is the API response structure
+type BlastRadiusResponse struct {
+	Trigger  analysis.SymptomResource `json:"trigger"`
+	Impacted []ImpactedResource       `json:"impacted"` // Ranked by impact score, highest first
+	Groups   []ImpactGroup            `json:"groups"`   // Impacted resources by relationship type and distance
+	Metadata ResponseMetadata         `json:"metadata"`
+}
+
+// ImpactedResource is a downstream resource that degraded after the change
+type ImpactedResource struct {
+	Resource analysis.SymptomResource `json:"resource"`
+	Distance int                      `json:"distance"` // Hops from the changed resource
+
+	// RelationshipType is the type of the last hop, i.e. the relationship
+	// through which the impact reached this resource
+	RelationshipType string `json:"relationshipType"`
+
+	Path            []Hop             `json:"path"` // Hops from the changed resource to this one
+	FirstDegradedAt time.Time         `json:"firstDegradedAt"`
+	MaxSeverity     anomaly.Severity  `json:"maxSeverity"`
+	ImpactScore     float64           `json:"impactScore"` // 0.0-1.0, deterministic
+	Anomalies       []anomaly.Anomaly `json:"anomalies"`   // Degradation detected within the window
+}
+
+// Hop is one edge on the path from the changed resource to an impacted one
+type Hop struct {
+	RelationshipType string                   `json:"relationshipType"`
+	Reverse          bool                     `json:"reverse"`  // The edge points towards the changed resource
+	Resource         analysis.SymptomResource `json:"resource"` // Resource the hop leads to
+}
+
+// ImpactGroup summarizes the impacted resources reached through the same
+// relationship type at the same distance
+type ImpactGroup struct {
+	RelationshipType string                     `json:"relationshipType"`
+	Distance         int                        `json:"distance"`
+	Count            int                        `json:"count"`
+	MaxSeverity      anomaly.Severity           `json:"maxSeverity"`
+	Resources        []analysis.SymptomResource `json:"resources"` // Includes resources cut off by the limit
+}
+
+// ResponseMetadata provides execution information
+type ResponseMetadata struct {
+	ChangeTimestamp    time.Time `json:"changeTimestamp"`
+	WindowEnd          time.Time `json:"windowEnd"`
+	QueryExecutionMs   int64     `json:"queryExecutionMs"`
+	AlgorithmVersion   string    `json:"algorithmVersion"`
+	ExecutedAt         time.Time `json:"executedAt"`
+	CandidatesExplored int       `json:"candidatesExplored"` // Resources in reach that changed within the window
+	ImpactedCount      int       `json:"impactedCount"`      // Candidates that degraded, before the limit
+	Truncated          bool      `json:"truncated"`          // The traversal hit its path limit, farther resources may be missing
+}
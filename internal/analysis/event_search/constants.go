@@ -0,0 +1,24 @@
+package eventsearch
+
+const (
+	// DefaultLimit is the default number of hits returned
+	DefaultLimit = 50
+
+	// MaxLimit is the maximum number of hits returned
+	MaxLimit = 500
+
+	// QueryTimeoutMs is the timeout for a full-text query in milliseconds
+	QueryTimeoutMs = 15000
+
+	// SourceK8sEvents selects Kubernetes event messages
+	SourceK8sEvents = "k8s"
+
+	// SourceChangeEvents selects error messages of resource changes
+	SourceChangeEvents = "change"
+
+	// HitTypeK8sEvent marks a hit on a Kubernetes event message
+	HitTypeK8sEvent = "k8s_event"
+
+	// HitTypeChangeEvent marks a hit on the error message of a resource change
+	HitTypeChangeEvent = "change_event"
+)
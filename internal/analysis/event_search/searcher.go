@@ -0,0 +1,208 @@
+// Package eventsearch searches the messages of Kubernetes events and the
+// error messages of resource changes through the graph's full-text indexes.
+package eventsearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/fulltext"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+// source describes how one kind of event node is searched
+type source struct {
+	label        string
+	relationship string
+	hitType      string
+}
+
+var sources = map[string]source{
+	SourceK8sEvents:    {label: "K8sEvent", relationship: "EMITTED_EVENT", hitType: HitTypeK8sEvent},
+	SourceChangeEvents: {label: "ChangeEvent", relationship: "CHANGED", hitType: HitTypeChangeEvent},
+}
+
+// Searcher runs full-text searches over event messages
+type Searcher struct {
+	graphClient graph.Client
+	logger      *logging.Logger
+}
+
+// NewSearcher creates a new Searcher
+func NewSearcher(graphClient graph.Client) *Searcher {
+	return &Searcher{
+		graphClient: graphClient,
+		logger:      logging.GetLogger("eventsearch"),
+	}
+}
+
+// Search returns the events matching the query, best matches first
+func (s *Searcher) Search(ctx context.Context, input SearchInput) (*SearchResponse, error) {
+	startTime := time.Now()
+
+	q, err := fulltext.Parse(input.Query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	selected := input.Sources
+	if len(selected) == 0 {
+		selected = []string{SourceK8sEvents, SourceChangeEvents}
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	var hits []Hit
+	for _, name := range selected {
+		src, ok := sources[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown source %q", name)
+		}
+		sourceHits, err := s.searchSource(ctx, src, q, input, limit)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, sourceHits...)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Timestamp > hits[j].Timestamp
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	if hits == nil {
+		hits = []Hit{}
+	}
+
+	return &SearchResponse{
+		Query:           q.String(),
+		Hits:            hits,
+		Count:           len(hits),
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// searchSource queries the full-text index of one event label
+func (s *Searcher) searchSource(ctx context.Context, src source, q *fulltext.Query, input SearchInput, limit int) ([]Hit, error) {
+	query := buildSearchQuery(src, q, input, limit)
+	result, err := s.graphClient.ExecuteQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s messages: %w", src.label, err)
+	}
+
+	hits := make([]Hit, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		resourceProps, err := graph.ParseNodeFromResult(row[0])
+		if err != nil {
+			s.logger.Warn("Failed to parse resource node: %v", err)
+			continue
+		}
+		eventProps, err := graph.ParseNodeFromResult(row[1])
+		if err != nil {
+			s.logger.Warn("Failed to parse %s node: %v", src.label, err)
+			continue
+		}
+
+		resource := graph.ParseResourceIdentityFromNode(resourceProps)
+		hit := Hit{
+			Type:  src.hitType,
+			Score: toFloat(row[2]),
+			Resource: Resource{
+				UID:       resource.UID,
+				Kind:      resource.Kind,
+				Namespace: resource.Namespace,
+				Cluster:   resource.Cluster,
+				Name:      resource.Name,
+			},
+		}
+		if src.hitType == HitTypeK8sEvent {
+			event := graph.ParseK8sEventFromNode(eventProps)
+			hit.ID = event.ID
+			hit.Timestamp = event.Timestamp
+			hit.Reason = event.Reason
+			hit.EventType = event.Type
+			hit.Message = event.Message
+		} else {
+			event := graph.ParseChangeEventFromNode(eventProps)
+			hit.ID = event.ID
+			hit.Timestamp = event.Timestamp
+			hit.EventType = event.EventType
+			hit.Status = event.Status
+			hit.Message = event.ErrorMessage
+		}
+		hit.Highlights = fulltext.Highlight(hit.Message, q)
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// buildSearchQuery builds the Cypher query for one source. The full-text
+// procedure yields the best matches first, the time range and resource
+// filters are applied to what it yields.
+func buildSearchQuery(src source, q *fulltext.Query, input SearchInput, limit int) graph.GraphQuery {
+	params := map[string]interface{}{
+		"label":   src.label,
+		"query":   q.String(),
+		"startNs": input.Start * int64(time.Second),
+		"endNs":   input.End * int64(time.Second),
+		"limit":   limit,
+	}
+
+	var conditions []string
+	if len(input.Namespaces) > 0 {
+		conditions = append(conditions, "r.namespace IN $namespaces")
+		params["namespaces"] = input.Namespaces
+	}
+	if len(input.Clusters) > 0 {
+		conditions = append(conditions, "r.cluster IN $clusters")
+		params["clusters"] = input.Clusters
+	}
+	if input.AllowedNamespaces != nil {
+		conditions = append(conditions, "r.namespace IN $allowedNamespaces")
+		params["allowedNamespaces"] = input.AllowedNamespaces
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		CALL db.idx.fulltext.queryNodes($label, $query) YIELD node, score
+		WITH node, score
+		WHERE node.timestamp >= $startNs AND node.timestamp <= $endNs
+		MATCH (r:ResourceIdentity)-[:%s]->(node)
+		%s
+		RETURN r, node, score
+		ORDER BY score DESC, node.timestamp DESC
+		LIMIT $limit
+	`, src.relationship, where)
+
+	return graph.GraphQuery{
+		Query:      query,
+		Parameters: params,
+		Timeout:    QueryTimeoutMs,
+	}
+}
+
+func toFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
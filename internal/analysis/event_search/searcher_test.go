@@ -0,0 +1,128 @@
+package eventsearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/fulltext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSearcher(t *testing.T) *Searcher {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	_, err := client.ExecuteQuery(ctx, graph.GraphQuery{Query: `
+		CREATE (pod:ResourceIdentity {uid: 'pod', kind: 'Pod', namespace: 'payments', name: 'api-7f9c', cluster: 'prod'})
+		CREATE (web:ResourceIdentity {uid: 'web', kind: 'Pod', namespace: 'shop', name: 'web-1', cluster: 'prod'})
+		CREATE (node:ResourceIdentity {uid: 'node', kind: 'Node', namespace: '', name: 'node-1', cluster: 'prod'})
+		CREATE (pod)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-1', timestamp: 2000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off restarting failed container'})
+		CREATE (web)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-2', timestamp: 3000000000, reason: 'Failed', type: 'Warning', message: 'Failed to pull image "web:v2": x509: certificate signed by unknown authority'})
+		CREATE (node)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-3', timestamp: 3000000000, reason: 'NodeNotReady', type: 'Warning', message: 'Node is not ready, kubelet restarting'})
+		CREATE (web)-[:CHANGED]->(:ChangeEvent {id: 'ce-1', timestamp: 3000000000, eventType: 'UPDATE', status: 'Error', errorMessage: 'ImagePullBackOff: x509 certificate error'})
+		CREATE (pod)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-old', timestamp: 90000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off restarting failed container'})
+	`})
+	require.NoError(t, err)
+
+	return NewSearcher(client)
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestSearcher_Search(t *testing.T) {
+	searcher := newTestSearcher(t)
+
+	tests := []struct {
+		name     string
+		input    SearchInput
+		expected []string
+	}{
+		{"k8s and change events", SearchInput{Query: "x509:certificate", End: 10}, []string{"ev-2", "ce-1"}},
+		{"prefix", SearchInput{Query: "restart*", End: 10}, []string{"ev-1", "ev-3"}},
+		{"time range", SearchInput{Query: "restarting", Start: 3, End: 100}, []string{"ev-3", "ev-old"}},
+		{"source", SearchInput{Query: "x509", End: 10, Sources: []string{SourceChangeEvents}}, []string{"ce-1"}},
+		{"namespace", SearchInput{Query: "x509", End: 10, Namespaces: []string{"payments"}}, nil},
+		{"cluster", SearchInput{Query: "x509", End: 10, Clusters: []string{"staging"}}, nil},
+		{"limit", SearchInput{Query: "restarting", End: 100, Limit: 1}, []string{"ev-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := searcher.Search(context.Background(), tt.input)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, hitIDs(result.Hits))
+			assert.Equal(t, len(result.Hits), result.Count)
+		})
+	}
+}
+
+func TestSearcher_Hit(t *testing.T) {
+	searcher := newTestSearcher(t)
+
+	result, err := searcher.Search(context.Background(), SearchInput{Query: `"failed container"`, End: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+
+	hit := result.Hits[0]
+	assert.Equal(t, HitTypeK8sEvent, hit.Type)
+	assert.Equal(t, "BackOff", hit.Reason)
+	assert.Equal(t, int64(2000000000), hit.Timestamp)
+	assert.Greater(t, hit.Score, 0.0)
+	assert.Equal(t, Resource{UID: "pod", Kind: "Pod", Namespace: "payments", Cluster: "prod", Name: "api-7f9c"}, hit.Resource)
+	assert.Equal(t, []fulltext.Fragment{
+		{Text: "Back-off restarting "},
+		{Text: "failed container", Match: true},
+	}, hit.Highlights)
+}
+
+func TestSearcher_Ranking(t *testing.T) {
+	searcher := newTestSearcher(t)
+
+	// The shorter message mentions the term more densely and ranks first
+	result, err := searcher.Search(context.Background(), SearchInput{Query: "x509", End: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ce-1", "ev-2"}, hitIDs(result.Hits))
+}
+
+func TestSearcher_TenantScope(t *testing.T) {
+	searcher := newTestSearcher(t)
+
+	// Cluster-scoped resources are left out of scoped searches
+	result, err := searcher.Search(context.Background(), SearchInput{
+		Query:             "restarting",
+		End:               10,
+		AllowedNamespaces: []string{"payments"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ev-1"}, hitIDs(result.Hits))
+
+	result, err = searcher.Search(context.Background(), SearchInput{
+		Query:             "restarting",
+		End:               10,
+		AllowedNamespaces: []string{},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Hits)
+}
+
+func TestSearcher_InvalidQuery(t *testing.T) {
+	searcher := newTestSearcher(t)
+
+	_, err := searcher.Search(context.Background(), SearchInput{Query: `"unterminated`, End: 10})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid query")
+}
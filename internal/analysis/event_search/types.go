@@ -0,0 +1,50 @@
+package eventsearch
+
+import "github.com/moolen/spectre/internal/graph/fulltext"
+
+// SearchInput contains the parameters of an event search
+type SearchInput struct {
+	Query      string   // Required: full-text query
+	Start      int64    // Required: Unix seconds
+	End        int64    // Required: Unix seconds
+	Sources    []string // Optional: SourceK8sEvents and/or SourceChangeEvents (default both)
+	Namespaces []string // Optional: Restrict to resources in these namespaces
+	Clusters   []string // Optional: Restrict to resources of these clusters
+	Limit      int      // Optional: Maximum number of hits (default 50)
+
+	// AllowedNamespaces restricts the search to a tenant's namespaces. Hits
+	// of cluster-scoped resources are left out in that case, since their
+	// messages are not part of any tenant. Nil means unrestricted.
+	AllowedNamespaces []string
+}
+
+// SearchResponse is the API response structure
+type SearchResponse struct {
+	Query           string `json:"query"`
+	Hits            []Hit  `json:"hits"`
+	Count           int    `json:"count"`
+	ExecutionTimeMs int64  `json:"executionTimeMs"`
+}
+
+// Hit is an event whose message matches the query
+type Hit struct {
+	Type       string              `json:"type"` // HitTypeK8sEvent or HitTypeChangeEvent
+	ID         string              `json:"id"`
+	Timestamp  int64               `json:"timestamp"` // Unix nanoseconds
+	Score      float64             `json:"score"`
+	Reason     string              `json:"reason,omitempty"`    // Kubernetes events
+	EventType  string              `json:"eventType,omitempty"` // Warning/Normal, or CREATE/UPDATE/DELETE
+	Status     string              `json:"status,omitempty"`    // resource changes
+	Message    string              `json:"message"`
+	Highlights []fulltext.Fragment `json:"highlights"`
+	Resource   Resource            `json:"resource"`
+}
+
+// Resource is the resource that emitted or underwent the event
+type Resource struct {
+	UID       string `json:"uid"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster,omitempty"`
+	Name      string `json:"name"`
+}
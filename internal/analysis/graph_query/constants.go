@@ -0,0 +1,23 @@
+package graphquery
+
+const (
+	// DefaultRowLimit is the default number of rows returned
+	DefaultRowLimit = 100
+
+	// MaxRowLimit is the maximum number of rows returned
+	MaxRowLimit = 1000
+
+	// DefaultTimeoutSeconds is the default query timeout
+	DefaultTimeoutSeconds = 10
+
+	// MaxTimeoutSeconds is the maximum query timeout
+	MaxTimeoutSeconds = 60
+
+	// MaxQueryLength is the maximum length of a query in bytes
+	MaxQueryLength = 10000
+
+	// AllowedNamespacesParameter is the parameter bound to the namespaces
+	// of a restricted caller, or null for unrestricted callers. Queries of
+	// restricted callers scope their nodes with it.
+	AllowedNamespacesParameter = "allowedNamespaces"
+)
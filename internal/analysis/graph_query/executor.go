@@ -0,0 +1,230 @@
+// Package graphquery runs read-only, ad-hoc Cypher queries against the graph
+// for questions the built-in analyses don't cover, along with a library of
+// saved queries.
+package graphquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/FalkorDB/falkordb-go/v2"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/cypher"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+// ErrInvalidQuery is returned for queries that are rejected before they
+// run: write queries, syntax errors, unknown saved queries, missing
+// parameters and queries reaching outside of a restricted caller's scope
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Executor runs read-only queries with row limits, timeouts and tenancy
+// restrictions
+type Executor struct {
+	graphClient graph.Client
+	logger      *logging.Logger
+}
+
+// NewExecutor creates a new Executor
+func NewExecutor(graphClient graph.Client) *Executor {
+	return &Executor{
+		graphClient: graphClient,
+		logger:      logging.GetLogger("graphquery"),
+	}
+}
+
+// Execute validates and runs a query
+func (e *Executor) Execute(ctx context.Context, input QueryInput) (*QueryResponse, error) {
+	startTime := time.Now()
+
+	query, params, err := resolveQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := graph.ParseReadOnlyQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+	if input.AllowedNamespaces != nil {
+		if err := checkScope(parsed); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+		}
+		params[AllowedNamespacesParameter] = input.AllowedNamespaces
+	} else {
+		params[AllowedNamespacesParameter] = nil
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultRowLimit
+	}
+	if limit > MaxRowLimit {
+		limit = MaxRowLimit
+	}
+	timeoutSeconds := input.TimeoutSeconds
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = DefaultTimeoutSeconds
+	}
+	if timeoutSeconds > MaxTimeoutSeconds {
+		timeoutSeconds = MaxTimeoutSeconds
+	}
+
+	// One row more than the limit tells whether the result was truncated
+	if needsLimit(parsed) {
+		query = fmt.Sprintf("%s\nLIMIT %d", strings.TrimRight(query, " \t\r\n;"), limit+1)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+
+	result, err := e.graphClient.ExecuteQuery(ctx, graph.GraphQuery{
+		Query:      query,
+		Parameters: params,
+		Timeout:    timeoutSeconds * 1000,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	rows := result.Rows
+	truncated := len(rows) > limit
+	if truncated {
+		rows = rows[:limit]
+	}
+	converted := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		converted[i] = make([]interface{}, len(row))
+		for j, value := range row {
+			converted[i][j] = convertValue(value)
+		}
+	}
+	if truncated {
+		e.logger.Debug("Graph query truncated to %d rows", limit)
+	}
+
+	return &QueryResponse{
+		SavedQuery:      input.SavedQuery,
+		Query:           query,
+		Columns:         result.Columns,
+		Rows:            converted,
+		Count:           len(converted),
+		Truncated:       truncated,
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// resolveQuery returns the query to run and a copy of its parameters
+func resolveQuery(input QueryInput) (string, map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(input.Parameters)+1)
+	for name, value := range input.Parameters {
+		if name == AllowedNamespacesParameter {
+			return "", nil, fmt.Errorf("%w: parameter %s is reserved", ErrInvalidQuery, name)
+		}
+		params[name] = normalizeParameter(value)
+	}
+
+	if input.SavedQuery == "" {
+		query := strings.TrimSpace(input.Query)
+		if query == "" {
+			return "", nil, fmt.Errorf("%w: query or savedQuery is required", ErrInvalidQuery)
+		}
+		if len(query) > MaxQueryLength {
+			return "", nil, fmt.Errorf("%w: query exceeds %d bytes", ErrInvalidQuery, MaxQueryLength)
+		}
+		return query, params, nil
+	}
+
+	if input.Query != "" {
+		return "", nil, fmt.Errorf("%w: query and savedQuery are mutually exclusive", ErrInvalidQuery)
+	}
+	saved, ok := LookupSavedQuery(input.SavedQuery)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unknown saved query %q", ErrInvalidQuery, input.SavedQuery)
+	}
+	for _, param := range saved.Parameters {
+		if _, ok := params[param.Name]; ok {
+			continue
+		}
+		if param.Required {
+			return "", nil, fmt.Errorf("%w: saved query %s requires parameter %s", ErrInvalidQuery, saved.Name, param.Name)
+		}
+		params[param.Name] = nil
+	}
+	return saved.Query, params, nil
+}
+
+// normalizeParameter turns the whole numbers JSON decodes as float64 back
+// into integers, so they compare equal to integer properties and can be used
+// in LIMIT and SKIP
+func normalizeParameter(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = normalizeParameter(item)
+		}
+		return list
+	}
+	return value
+}
+
+// needsLimit reports whether a LIMIT can be appended to the query: it ends
+// with a RETURN that has none. Other results are truncated after the fact.
+func needsLimit(q *cypher.Query) bool {
+	ret, ok := q.Clauses[len(q.Clauses)-1].(*cypher.Return)
+	return ok && ret.Limit == nil
+}
+
+// convertValue converts the nodes, relationships and paths of a row to
+// their JSON representation
+func convertValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *falkordb.Node:
+		return convertNode(v)
+	case falkordb.Node:
+		return convertNode(&v)
+	case *falkordb.Edge:
+		return convertEdge(v)
+	case falkordb.Edge:
+		return convertEdge(&v)
+	case falkordb.Path:
+		path := Path{Nodes: make([]Node, len(v.Nodes)), Edges: make([]Edge, len(v.Edges))}
+		for i, node := range v.Nodes {
+			path.Nodes[i] = convertNode(node)
+		}
+		for i, edge := range v.Edges {
+			path.Edges[i] = convertEdge(edge)
+		}
+		return path
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = convertValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = convertValue(item)
+		}
+		return m
+	}
+	return value
+}
+
+func convertNode(node *falkordb.Node) Node {
+	return Node{ID: node.ID, Labels: node.Labels, Properties: node.Properties}
+}
+
+func convertEdge(edge *falkordb.Edge) Edge {
+	return Edge{ID: edge.ID, Type: edge.Relation, Properties: edge.Properties}
+}
@@ -0,0 +1,181 @@
+package graphquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExecutor(t *testing.T) *Executor {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	_, err := client.ExecuteQuery(ctx, graph.GraphQuery{Query: `
+		CREATE (pvc:ResourceIdentity {uid: 'pvc', kind: 'PersistentVolumeClaim', namespace: 'shop', name: 'data', deleted: false, lastSeen: 100})
+		CREATE (p1:ResourceIdentity {uid: 'pod-1', kind: 'Pod', namespace: 'shop', name: 'db-0', deleted: true, deletedAt: 50, lastSeen: 50})
+		CREATE (p2:ResourceIdentity {uid: 'pod-2', kind: 'Pod', namespace: 'shop', name: 'db-0', deleted: false, lastSeen: 100})
+		CREATE (hr:ResourceIdentity {uid: 'hr', kind: 'HelmRelease', namespace: 'flux', name: 'platform', deleted: false})
+		CREATE (cm1:ResourceIdentity {uid: 'cm-1', kind: 'ConfigMap', namespace: 'shop', name: 'settings', deleted: false})
+		CREATE (cm2:ResourceIdentity {uid: 'cm-2', kind: 'ConfigMap', namespace: 'payments', name: 'settings', deleted: false})
+		CREATE (p1)-[:MOUNTS]->(pvc)
+		CREATE (p2)-[:MOUNTS]->(pvc)
+		CREATE (p2)-[:CHANGED]->(:ChangeEvent {id: 'ce-1', timestamp: 100, status: 'Ready'})
+		CREATE (hr)-[:MANAGES]->(cm1)
+		CREATE (hr)-[:MANAGES]->(cm2)
+	`})
+	require.NoError(t, err)
+
+	return NewExecutor(client)
+}
+
+func TestExecutor_Query(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	result, err := executor.Execute(context.Background(), QueryInput{
+		Query:      "MATCH (r:ResourceIdentity {kind: $kind}) WHERE NOT r.deleted RETURN r ORDER BY r.uid",
+		Parameters: map[string]interface{}{"kind": "ConfigMap"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r"}, result.Columns)
+	require.Equal(t, 2, result.Count)
+	node, ok := result.Rows[0][0].(Node)
+	require.True(t, ok, "expected a node, got %T", result.Rows[0][0])
+	assert.Equal(t, []string{"ResourceIdentity"}, node.Labels)
+	assert.Equal(t, "cm-1", node.Properties["uid"])
+	assert.False(t, result.Truncated)
+	assert.Contains(t, result.Query, "LIMIT 101")
+}
+
+func TestExecutor_Limit(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	result, err := executor.Execute(context.Background(), QueryInput{
+		Query: "MATCH (r:ResourceIdentity) RETURN r.uid;",
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Count)
+	assert.True(t, result.Truncated)
+
+	// A LIMIT of the query is kept, the rows are still capped
+	result, err = executor.Execute(context.Background(), QueryInput{
+		Query: "MATCH (r:ResourceIdentity) RETURN r.uid LIMIT 5",
+		Limit: 3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Count)
+	assert.True(t, result.Truncated)
+}
+
+func TestExecutor_RejectsInvalidQueries(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	tests := []struct {
+		name  string
+		input QueryInput
+	}{
+		{"write", QueryInput{Query: "MATCH (r) DETACH DELETE r"}},
+		{"write procedure", QueryInput{Query: "CALL db.idx.fulltext.drop('K8sEvent')"}},
+		{"syntax", QueryInput{Query: "MATCH (r RETURN r"}},
+		{"empty", QueryInput{Query: "  "}},
+		{"both", QueryInput{Query: "MATCH (r) RETURN r", SavedQuery: "recently_deleted"}},
+		{"unknown saved query", QueryInput{SavedQuery: "nope"}},
+		{"missing parameter", QueryInput{SavedQuery: "pods_mounting_pvc", Parameters: map[string]interface{}{"namespace": "shop"}}},
+		{"reserved parameter", QueryInput{Query: "MATCH (r) RETURN r", Parameters: map[string]interface{}{"allowedNamespaces": []string{"shop"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.Execute(context.Background(), tt.input)
+			assert.ErrorIs(t, err, ErrInvalidQuery)
+		})
+	}
+}
+
+func TestExecutor_Scope(t *testing.T) {
+	executor := newTestExecutor(t)
+	shop := []string{"shop"}
+
+	rejected := []string{
+		"MATCH (r:ResourceIdentity) RETURN r",
+		"MATCH (r:ResourceIdentity) WHERE r.namespace IN $allowedNamespaces OR true RETURN r",
+		"MATCH (r:ResourceIdentity)-[:MANAGES]->(c) WHERE r.namespace IN $allowedNamespaces RETURN c",
+		"MATCH (r:ResourceIdentity) WHERE r.namespace IN $allowedNamespaces AND (r)-[:MANAGES]->() RETURN r",
+		"MATCH (r:ResourceIdentity) WHERE r.namespace IN $allowedNamespaces WITH r.uid AS uid MATCH (x:ResourceIdentity {uid: uid}) RETURN x",
+		"MATCH p = (r:ResourceIdentity)-[:OWNS*1..3]->(c) WHERE r.namespace IN $allowedNamespaces AND c.namespace IN $allowedNamespaces RETURN p",
+		"CALL db.idx.fulltext.queryNodes('K8sEvent', 'oom') YIELD node RETURN node",
+	}
+	for _, query := range rejected {
+		_, err := executor.Execute(context.Background(), QueryInput{Query: query, AllowedNamespaces: shop})
+		assert.ErrorIs(t, err, ErrInvalidQuery, query)
+	}
+
+	// Scoped resources, their events and resources reached through WITH
+	result, err := executor.Execute(context.Background(), QueryInput{
+		Query: `MATCH (pod:ResourceIdentity)-[:CHANGED]->(e) WHERE pod.namespace IN $allowedNamespaces
+			WITH pod, e
+			MATCH (pod)-[:MOUNTS]->(pvc) WHERE pvc.namespace IN $allowedNamespaces
+			RETURN pod.uid, e.status, pvc.name`,
+		AllowedNamespaces: shop,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"pod-2", "Ready", "data"}}, result.Rows)
+
+	// The predicate restricts the result to the caller's namespaces
+	result, err = executor.Execute(context.Background(), QueryInput{
+		Query:             "MATCH (r:ResourceIdentity {kind: 'ConfigMap'}) WHERE r.namespace IN $allowedNamespaces RETURN r.uid",
+		AllowedNamespaces: shop,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"cm-1"}}, result.Rows)
+}
+
+func TestExecutor_SavedQueries(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	result, err := executor.Execute(context.Background(), QueryInput{
+		SavedQuery: "pods_mounting_pvc",
+		Parameters: map[string]interface{}{"namespace": "shop", "name": "data"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pods_mounting_pvc", result.SavedQuery)
+	require.Equal(t, 2, result.Count)
+	assert.Equal(t, "pod-2", result.Rows[0][0])
+	assert.Equal(t, "pod-1", result.Rows[1][0])
+
+	// Managers spanning namespaces, unless the caller only sees one of them
+	result, err = executor.Execute(context.Background(), QueryInput{SavedQuery: "multi_namespace_managers"})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Count)
+	assert.Equal(t, "hr", result.Rows[0][0])
+
+	result, err = executor.Execute(context.Background(), QueryInput{
+		SavedQuery:        "multi_namespace_managers",
+		AllowedNamespaces: []string{"flux", "shop"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Count)
+
+	// Every saved query passes the checks for restricted callers
+	for _, saved := range SavedQueries() {
+		params := make(map[string]interface{})
+		for _, param := range saved.Parameters {
+			params[param.Name] = "x"
+		}
+		_, err := executor.Execute(context.Background(), QueryInput{
+			SavedQuery:        saved.Name,
+			Parameters:        params,
+			AllowedNamespaces: []string{"shop"},
+		})
+		assert.NoError(t, err, saved.Name)
+	}
+}
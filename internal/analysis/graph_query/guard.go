@@ -0,0 +1,183 @@
+package graphquery
+
+import (
+	"fmt"
+
+	"github.com/moolen/spectre/internal/graph/cypher"
+)
+
+// eventRelationships lead from a resource to its change and Kubernetes
+// event nodes, which are only reachable through the resource
+var eventRelationships = map[string]bool{
+	"CHANGED":       true,
+	"EMITTED_EVENT": true,
+}
+
+// checkScope verifies that a query of a namespace-restricted caller only
+// reads nodes within the caller's namespaces. Every node matched by the
+// query has to be bound to a variable that the WHERE clause of its MATCH
+// restricts with a top-level "v.namespace IN $allowedNamespaces" predicate,
+// or be an event node one CHANGED or EMITTED_EVENT hop away from such a
+// variable. Procedure calls are not available to restricted callers.
+func checkScope(q *cypher.Query) error {
+	scoped := make(map[string]bool)
+	for _, clause := range q.Clauses {
+		switch c := clause.(type) {
+		case *cypher.Call:
+			return fmt.Errorf("procedure calls are not available to namespace-restricted callers")
+		case *cypher.Match:
+			for _, v := range scopedVariables(c.Where) {
+				scoped[v] = true
+			}
+			for _, part := range c.Pattern {
+				if err := checkPattern(part, scoped); err != nil {
+					return err
+				}
+			}
+			if err := checkPatternPredicates(c.Where, scoped); err != nil {
+				return err
+			}
+		case *cypher.With:
+			if err := checkProjection(&c.Projection, scoped); err != nil {
+				return err
+			}
+			if err := checkPatternPredicates(c.Where, scoped); err != nil {
+				return err
+			}
+			if !c.Star {
+				projected := make(map[string]bool)
+				for _, item := range c.Items {
+					if v, ok := item.Expr.(*cypher.Variable); ok && scoped[v.Name] {
+						projected[item.Alias] = true
+					}
+				}
+				scoped = projected
+			}
+		case *cypher.Return:
+			if err := checkProjection(&c.Projection, scoped); err != nil {
+				return err
+			}
+		case *cypher.Unwind:
+			if err := checkPatternPredicates(c.Expr, scoped); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scopedVariables returns the variables a WHERE clause restricts to the
+// allowed namespaces
+func scopedVariables(where cypher.Expr) []string {
+	var variables []string
+	for _, conjunct := range conjuncts(where) {
+		op, ok := conjunct.(*cypher.BinaryOp)
+		if !ok || op.Op != "IN" || !isAllowedNamespaces(op.Right) {
+			continue
+		}
+		property, ok := op.Left.(*cypher.Property)
+		if !ok || property.Key != "namespace" {
+			continue
+		}
+		if v, ok := property.Expr.(*cypher.Variable); ok {
+			variables = append(variables, v.Name)
+		}
+	}
+	return variables
+}
+
+// conjuncts splits an expression at its top-level ANDs
+func conjuncts(e cypher.Expr) []cypher.Expr {
+	if op, ok := e.(*cypher.BinaryOp); ok && op.Op == "AND" {
+		return append(conjuncts(op.Left), conjuncts(op.Right)...)
+	}
+	if e == nil {
+		return nil
+	}
+	return []cypher.Expr{e}
+}
+
+// isAllowedNamespaces reports whether an expression evaluates to the allowed
+// namespaces of a restricted caller: $allowedNamespaces itself or, as used by
+// the saved queries, coalesce($allowedNamespaces, ...)
+func isAllowedNamespaces(e cypher.Expr) bool {
+	if call, ok := e.(*cypher.FunctionCall); ok && call.Name == "coalesce" && len(call.Args) > 0 {
+		e = call.Args[0]
+	}
+	param, ok := e.(*cypher.Parameter)
+	return ok && param.Name == AllowedNamespacesParameter
+}
+
+// checkPattern verifies that every node of a pattern is scoped
+func checkPattern(part *cypher.PatternPart, scoped map[string]bool) error {
+	for _, rel := range part.Relationships {
+		if rel.VarLength && (rel.Variable != "" || part.PathVariable != "") {
+			return fmt.Errorf("variable-length relationships cannot be bound to a variable by namespace-restricted callers")
+		}
+	}
+
+	for i, node := range part.Nodes {
+		if node.Variable != "" && scoped[node.Variable] {
+			continue
+		}
+		if i > 0 && isEventHop(part.Relationships[i-1], cypher.DirectionRight) && isScopedNode(part.Nodes[i-1], scoped) {
+			continue
+		}
+		if i < len(part.Relationships) && isEventHop(part.Relationships[i], cypher.DirectionLeft) && isScopedNode(part.Nodes[i+1], scoped) {
+			continue
+		}
+		if node.Variable == "" {
+			return fmt.Errorf("anonymous nodes must be event nodes of a scoped resource for namespace-restricted callers; " +
+				"bind the node to a variable v and add WHERE v.namespace IN $allowedNamespaces")
+		}
+		return fmt.Errorf("node %s is not restricted to the allowed namespaces; add %s.namespace IN $allowedNamespaces to the WHERE clause of its MATCH",
+			node.Variable, node.Variable)
+	}
+	return nil
+}
+
+// isEventHop reports whether a relationship is a single hop from a resource
+// to one of its events, pointing in the given direction
+func isEventHop(rel *cypher.RelationshipPattern, direction cypher.Direction) bool {
+	if rel.VarLength || rel.Direction != direction || len(rel.Types) == 0 {
+		return false
+	}
+	for _, relType := range rel.Types {
+		if !eventRelationships[relType] {
+			return false
+		}
+	}
+	return true
+}
+
+func isScopedNode(node *cypher.NodePattern, scoped map[string]bool) bool {
+	return node.Variable != "" && scoped[node.Variable]
+}
+
+// checkProjection verifies the pattern predicates of a WITH or RETURN
+func checkProjection(projection *cypher.Projection, scoped map[string]bool) error {
+	for _, item := range projection.Items {
+		if err := checkPatternPredicates(item.Expr, scoped); err != nil {
+			return err
+		}
+	}
+	for _, item := range projection.OrderBy {
+		if err := checkPatternPredicates(item.Expr, scoped); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPatternPredicates verifies the patterns used as predicates within an
+// expression, such as WHERE (r)-[:OWNS]->(pod)
+func checkPatternPredicates(e cypher.Expr, scoped map[string]bool) error {
+	var err error
+	cypher.Walk(e, func(e cypher.Expr) bool {
+		if predicate, ok := e.(*cypher.PatternPredicate); ok {
+			err = checkPattern(predicate.Part, scoped)
+		}
+		return err == nil
+	})
+	return err
+}
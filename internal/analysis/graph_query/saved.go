@@ -0,0 +1,92 @@
+package graphquery
+
+import "sort"
+
+// savedQueries is the library of saved queries, by name. Every node is
+// scoped with "IN coalesce($allowedNamespaces, [n.namespace])", which
+// restricts it to the caller's namespaces and matches every node when the
+// caller is unrestricted, so the queries serve both kinds of callers.
+var savedQueries = map[string]SavedQuery{
+	"pods_mounting_pvc": {
+		Name:        "pods_mounting_pvc",
+		Description: "Pods that mounted a PersistentVolumeClaim, including deleted ones, most recently seen first",
+		Query: `MATCH (pod:ResourceIdentity {kind: 'Pod'})-[:MOUNTS]->(pvc:ResourceIdentity {kind: 'PersistentVolumeClaim', namespace: $namespace, name: $name})
+WHERE pod.namespace IN coalesce($allowedNamespaces, [pod.namespace])
+  AND pvc.namespace IN coalesce($allowedNamespaces, [pvc.namespace])
+RETURN pod.uid AS uid, pod.name AS name, pod.deleted AS deleted, pod.firstSeen AS firstSeen, pod.lastSeen AS lastSeen
+ORDER BY pod.lastSeen DESC`,
+		Parameters: []SavedQueryParameter{
+			{Name: "namespace", Description: "Namespace of the PersistentVolumeClaim", Required: true},
+			{Name: "name", Description: "Name of the PersistentVolumeClaim", Required: true},
+		},
+	},
+	"pods_using_service_account": {
+		Name:        "pods_using_service_account",
+		Description: "Pods that ran as a ServiceAccount, including deleted ones, most recently seen first",
+		Query: `MATCH (pod:ResourceIdentity {kind: 'Pod'})-[:USES_SERVICE_ACCOUNT]->(sa:ResourceIdentity {kind: 'ServiceAccount', namespace: $namespace, name: $name})
+WHERE pod.namespace IN coalesce($allowedNamespaces, [pod.namespace])
+  AND sa.namespace IN coalesce($allowedNamespaces, [sa.namespace])
+RETURN pod.uid AS uid, pod.name AS name, pod.deleted AS deleted, pod.firstSeen AS firstSeen, pod.lastSeen AS lastSeen
+ORDER BY pod.lastSeen DESC`,
+		Parameters: []SavedQueryParameter{
+			{Name: "namespace", Description: "Namespace of the ServiceAccount", Required: true},
+			{Name: "name", Description: "Name of the ServiceAccount", Required: true},
+		},
+	},
+	"multi_namespace_managers": {
+		Name:        "multi_namespace_managers",
+		Description: "Managers such as HelmReleases, Kustomizations and Argo CD Applications that manage resources in more than one namespace",
+		Query: `MATCH (manager:ResourceIdentity)-[:MANAGES]->(r:ResourceIdentity)
+WHERE manager.namespace IN coalesce($allowedNamespaces, [manager.namespace])
+  AND r.namespace IN coalesce($allowedNamespaces, [r.namespace])
+  AND NOT r.deleted
+WITH manager, collect(DISTINCT r.namespace) AS namespaces
+WHERE size(namespaces) > 1
+RETURN manager.uid AS uid, manager.kind AS kind, manager.namespace AS namespace, manager.name AS name, namespaces
+ORDER BY size(namespaces) DESC, name`,
+	},
+	"recently_deleted": {
+		Name:        "recently_deleted",
+		Description: "Resources deleted from the cluster that are still within retention, most recently deleted first",
+		Query: `MATCH (r:ResourceIdentity)
+WHERE r.namespace IN coalesce($allowedNamespaces, [r.namespace])
+  AND r.deleted
+  AND ($namespace IS NULL OR r.namespace = $namespace)
+  AND ($kind IS NULL OR r.kind = $kind)
+RETURN r.uid AS uid, r.kind AS kind, r.namespace AS namespace, r.name AS name, r.deletedAt AS deletedAt
+ORDER BY r.deletedAt DESC`,
+		Parameters: []SavedQueryParameter{
+			{Name: "namespace", Description: "Only resources of this namespace"},
+			{Name: "kind", Description: "Only resources of this kind"},
+		},
+	},
+	"owner_chain": {
+		Name:        "owner_chain",
+		Description: "Owners of a resource up to five levels, e.g. the ReplicaSet and Deployment of a Pod",
+		Query: `MATCH (owner:ResourceIdentity)-[:OWNS*1..5]->(r:ResourceIdentity {uid: $uid})
+WHERE owner.namespace IN coalesce($allowedNamespaces, [owner.namespace])
+  AND r.namespace IN coalesce($allowedNamespaces, [r.namespace])
+RETURN owner.uid AS uid, owner.kind AS kind, owner.namespace AS namespace, owner.name AS name, owner.deleted AS deleted`,
+		Parameters: []SavedQueryParameter{
+			{Name: "uid", Description: "UID of the owned resource", Required: true},
+		},
+	},
+}
+
+// SavedQueries returns the saved queries ordered by name
+func SavedQueries() []SavedQuery {
+	queries := make([]SavedQuery, 0, len(savedQueries))
+	for _, q := range savedQueries {
+		queries = append(queries, q)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+	return queries
+}
+
+// LookupSavedQuery returns the saved query with the given name
+func LookupSavedQuery(name string) (SavedQuery, bool) {
+	q, ok := savedQueries[name]
+	return q, ok
+}
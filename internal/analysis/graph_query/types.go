@@ -0,0 +1,65 @@
+package graphquery
+
+// QueryInput contains the parameters of an ad-hoc graph query
+type QueryInput struct {
+	// Query is a read-only Cypher query. Exactly one of Query and
+	// SavedQuery is set.
+	Query string
+
+	// SavedQuery is the name of a saved query to run instead of Query
+	SavedQuery string
+
+	Parameters     map[string]interface{} // Optional: Query parameters
+	Limit          int                    // Optional: Maximum number of rows (default 100)
+	TimeoutSeconds int                    // Optional: Query timeout (default 10)
+
+	// AllowedNamespaces restricts the query to a tenant's namespaces. Nil
+	// means unrestricted.
+	AllowedNamespaces []string
+}
+
+// QueryResponse is the API response structure
+type QueryResponse struct {
+	SavedQuery      string          `json:"savedQuery,omitempty"`
+	Query           string          `json:"query"`
+	Columns         []string        `json:"columns"`
+	Rows            [][]interface{} `json:"rows"`
+	Count           int             `json:"count"`
+	Truncated       bool            `json:"truncated"`
+	ExecutionTimeMs int64           `json:"executionTimeMs"`
+}
+
+// Node is a node in the rows of a query result
+type Node struct {
+	ID         uint64                 `json:"id"`
+	Labels     []string               `json:"labels"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Edge is a relationship in the rows of a query result
+type Edge struct {
+	ID         uint64                 `json:"id"`
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Path is a path in the rows of a query result
+type Path struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// SavedQuery is a named, documented query
+type SavedQuery struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Query       string                `json:"query"`
+	Parameters  []SavedQueryParameter `json:"parameters,omitempty"`
+}
+
+// SavedQueryParameter documents a parameter of a saved query
+type SavedQueryParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+
+	"github.com/moolen/spectre/internal/models"
 )
 
 // ComputeJSONDiff calculates the differences between two JSON byte slices.
@@ -112,12 +114,9 @@ func diffMaps(prefix string, old, newMap map[string]any) []EventDiff {
 func diffArrays(prefix string, old, newArr []any) []EventDiff {
 	var diffs []EventDiff
 
-	// Try to identify elements by common key fields
-	keyFields := []string{"name", "containerPort", "port", "type", "key"}
-
 	// Find a suitable key field
 	keyField := ""
-	for _, field := range keyFields {
+	for _, field := range models.ListKeyFields {
 		if arrayHasKeyField(old, field) && arrayHasKeyField(newArr, field) {
 			keyField = field
 			break
@@ -393,7 +392,9 @@ func ConvertEventsToDiffFormat(events []ChangeEventInfo, filterNoisy bool) []Cha
 				if filterNoisy {
 					diffs = FilterNoisyPaths(diffs)
 				}
+				AnnotateFieldManagers(diffs, event.FieldManagers)
 				result[i].Diff = diffs
+				result[i].Managers = DiffManagers(diffs)
 			}
 			prevData = event.Data
 		}
@@ -408,10 +409,79 @@ func ConvertEventsToDiffFormat(events []ChangeEventInfo, filterNoisy bool) []Cha
 	return final
 }
 
+// AnnotateFieldManagers sets the Manager of each diff from a field manager
+// summary (field path to manager). A path is attributed to the manager of
+// the path itself or of its closest summarized ancestor; a path added as a
+// whole, e.g. a new container, to the manager of all its fields if there is
+// only one. Removed fields have no owner left in managedFields and are not
+// annotated.
+func AnnotateFieldManagers(diffs []EventDiff, managers map[string]string) {
+	if len(managers) == 0 {
+		return
+	}
+	for i := range diffs {
+		if diffs[i].Op == "remove" {
+			continue
+		}
+		diffs[i].Manager = fieldManagerForPath(managers, diffs[i].Path)
+	}
+}
+
+// fieldManagerForPath looks up the manager of a diff path in a field
+// manager summary
+func fieldManagerForPath(managers map[string]string, path string) string {
+	closest := ""
+	manager := ""
+	for summarized, owner := range managers {
+		if isPathWithin(path, summarized) && len(summarized) >= len(closest) {
+			closest, manager = summarized, owner
+		}
+	}
+	if manager != "" {
+		return manager
+	}
+
+	// The path is above the summarized paths
+	for summarized, owner := range managers {
+		if !isPathWithin(summarized, path) {
+			continue
+		}
+		if manager != "" && manager != owner {
+			return ""
+		}
+		manager = owner
+	}
+	return manager
+}
+
+// isPathWithin reports whether path equals ancestor or lies below it
+func isPathWithin(path, ancestor string) bool {
+	if !strings.HasPrefix(path, ancestor) {
+		return false
+	}
+	rest := path[len(ancestor):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
+
+// DiffManagers returns the distinct field managers of diffs, sorted
+func DiffManagers(diffs []EventDiff) []string {
+	var managers []string
+	seen := make(map[string]bool)
+	for _, diff := range diffs {
+		if diff.Manager != "" && !seen[diff.Manager] {
+			seen[diff.Manager] = true
+			managers = append(managers, diff.Manager)
+		}
+	}
+	sort.Strings(managers)
+	return managers
+}
+
 // FormatUnifiedDiff converts a slice of EventDiff to a git-style unified diff string.
 // This provides a compact, human-readable representation of all changes.
 // For arrays like status.conditions, it matches elements by key fields (e.g., "type")
-// and shows per-element changes.
+// and shows per-element changes. Changes attributed to a field manager are
+// followed by a "#  by <manager>" line.
 func FormatUnifiedDiff(diffs []EventDiff) string {
 	if len(diffs) == 0 {
 		return ""
@@ -477,6 +547,11 @@ func FormatUnifiedDiff(diffs []EventDiff) string {
 				sb.WriteString(formatValue(diff.NewValue))
 				sb.WriteString("\n")
 			}
+			if diff.Manager != "" {
+				sb.WriteString("#  by ")
+				sb.WriteString(diff.Manager)
+				sb.WriteString("\n")
+			}
 		}
 	}
 
@@ -774,7 +849,9 @@ func ConvertSingleEventToDiff(event *ChangeEventInfo, prevData []byte, filterNoi
 			if filterNoisy {
 				diffs = FilterNoisyPaths(diffs)
 			}
+			AnnotateFieldManagers(diffs, event.FieldManagers)
 			event.Diff = diffs
+			event.Managers = DiffManagers(diffs)
 		}
 	}
 
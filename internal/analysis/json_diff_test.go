@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -473,6 +474,62 @@ func TestConvertSingleEventToDiff(t *testing.T) {
 	}
 }
 
+func TestAnnotateFieldManagers(t *testing.T) {
+	managers := map[string]string{
+		"metadata.labels": "helm",
+		"spec.replicas":   "argocd-controller",
+		"spec.template.spec.containers[name=app].image":             "kubectl-edit",
+		"spec.template.spec.containers[name=app].env":               "argocd-controller",
+		"spec.template.spec.containers[name=sidecar].image":         "kustomize-controller",
+		"spec.template.spec.containers[name=sidecar].name":          "kustomize-controller",
+		"spec.template.spec.containers[name=app-metrics].resources": "kube-controller-manager",
+	}
+
+	diffs := []EventDiff{
+		{Path: "metadata.labels.app.kubernetes.io/version", NewValue: "2", Op: "replace"},
+		{Path: "spec.replicas", OldValue: float64(2), NewValue: float64(3), Op: "replace"},
+		{Path: "spec.template.spec.containers[name=app].image", NewValue: "app:2", Op: "replace"},
+		{Path: "spec.template.spec.containers[name=sidecar]", NewValue: map[string]any{"name": "sidecar"}, Op: "add"},
+		{Path: "spec.template.spec.containers[name=app]", Op: "replace"},
+		{Path: "spec.template.spec.containers[name=app].args", OldValue: "--debug", Op: "remove"},
+		{Path: "spec.paused", NewValue: true, Op: "add"},
+	}
+	AnnotateFieldManagers(diffs, managers)
+
+	expected := []string{"helm", "argocd-controller", "kubectl-edit", "kustomize-controller", "", "", ""}
+	for i, diff := range diffs {
+		if diff.Manager != expected[i] {
+			t.Errorf("manager of %s = %q, want %q", diff.Path, diff.Manager, expected[i])
+		}
+	}
+
+	if got := DiffManagers(diffs); len(got) != 4 || got[0] != "argocd-controller" || got[3] != "kustomize-controller" {
+		t.Errorf("DiffManagers() = %v", got)
+	}
+}
+
+func TestConvertEventsToDiffFormat_FieldManagers(t *testing.T) {
+	events := []ChangeEventInfo{
+		{
+			EventID:       "2",
+			Data:          []byte(`{"spec":{"replicas":3}}`),
+			FieldManagers: map[string]string{"spec.replicas": "kubectl-edit"},
+		},
+		{EventID: "1", Data: []byte(`{"spec":{"replicas":1}}`)},
+	}
+
+	result := ConvertEventsToDiffFormat(events, false)
+	if len(result[0].Diff) != 1 || result[0].Diff[0].Manager != "kubectl-edit" {
+		t.Fatalf("expected diff attributed to kubectl-edit, got %+v", result[0].Diff)
+	}
+	if len(result[0].Managers) != 1 || result[0].Managers[0] != "kubectl-edit" {
+		t.Errorf("Managers = %v, want [kubectl-edit]", result[0].Managers)
+	}
+	if !strings.Contains(FormatUnifiedDiff(result[0].Diff), "#  by kubectl-edit") {
+		t.Errorf("expected unified diff to name the manager")
+	}
+}
+
 func TestJoinPath(t *testing.T) {
 	tests := []struct {
 		prefix   string
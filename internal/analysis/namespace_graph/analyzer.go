@@ -63,7 +63,7 @@ func (a *Analyzer) Analyze(ctx context.Context, input AnalyzeInput) (*NamespaceG
 
 	// Step 1: Fetch namespaced resources
 	namespacedResources, hasMore, nextCursor, err := a.resourceFetcher.FetchNamespacedResources(
-		ctx, input.Namespace, input.Timestamp, input.Limit, input.Cursor)
+		ctx, input.Namespace, input.Clusters, input.Timestamp, input.Limit, input.Cursor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch namespaced resources: %w", err)
 	}
@@ -207,6 +207,7 @@ func (a *Analyzer) buildNodes(resources []resourceResult, latestEvents map[strin
 			Kind:      r.Kind,
 			APIGroup:  r.APIGroup,
 			Namespace: r.Namespace,
+			Cluster:   r.Cluster,
 			Name:      r.Name,
 			Status:    StatusUnknown,
 			Labels:    r.Labels,
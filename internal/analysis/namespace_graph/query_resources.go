@@ -30,6 +30,7 @@ type resourceResult struct {
 	Kind      string
 	APIGroup  string
 	Namespace string
+	Cluster   string
 	Name      string
 	Labels    map[string]string
 	FirstSeen int64
@@ -38,10 +39,12 @@ type resourceResult struct {
 	DeletedAt int64
 }
 
-// FetchNamespacedResources fetches resources in the given namespace at the specified timestamp
+// FetchNamespacedResources fetches resources in the given namespace at the specified timestamp.
+// If clusters is non-empty, only resources of those clusters are returned.
 func (f *ResourceFetcher) FetchNamespacedResources(
 	ctx context.Context,
 	namespace string,
+	clusters []string,
 	timestamp int64,
 	limit int,
 	cursor string,
@@ -66,6 +69,12 @@ func (f *ResourceFetcher) FetchNamespacedResources(
 		"limit":     limit + 1, // Fetch one extra to check if there are more
 	}
 
+	clusterFilter := ""
+	if len(clusters) > 0 {
+		clusterFilter = "AND r.cluster IN $clusters"
+		params["clusters"] = clusters
+	}
+
 	if lastKind != "" || lastName != "" {
 		// Continue from cursor position
 		// Note: Event kind is excluded as it clutters the graph and doesn't provide useful visualization
@@ -77,10 +86,11 @@ func (f *ResourceFetcher) FetchNamespacedResources(
 			  AND r.firstSeen <= $timestamp
 			  AND (r.deleted = false OR r.deleted IS NULL OR r.deletedAt > $timestamp)
 			  AND r.kind <> 'Event'
+			  ` + clusterFilter + `
 			  AND ((r.kind > $lastKind) OR (r.kind = $lastKind AND r.name > $lastName))
 			RETURN DISTINCT r.uid as uid, r.kind as kind, r.apiGroup as apiGroup, r.namespace as namespace, 
 			       r.name as name, r.labels as labels, r.firstSeen as firstSeen, r.lastSeen as lastSeen,
-			       r.deleted as deleted, r.deletedAt as deletedAt
+			       r.deleted as deleted, r.deletedAt as deletedAt, r.cluster as cluster
 			ORDER BY r.kind, r.name
 			LIMIT $limit
 		`
@@ -97,9 +107,10 @@ func (f *ResourceFetcher) FetchNamespacedResources(
 			  AND r.firstSeen <= $timestamp
 			  AND (r.deleted = false OR r.deleted IS NULL OR r.deletedAt > $timestamp)
 			  AND r.kind <> 'Event'
+			  ` + clusterFilter + `
 			RETURN DISTINCT r.uid as uid, r.kind as kind, r.apiGroup as apiGroup, r.namespace as namespace, 
 			       r.name as name, r.labels as labels, r.firstSeen as firstSeen, r.lastSeen as lastSeen,
-			       r.deleted as deleted, r.deletedAt as deletedAt
+			       r.deleted as deleted, r.deletedAt as deletedAt, r.cluster as cluster
 			ORDER BY r.kind, r.name
 			LIMIT $limit
 		`
@@ -165,7 +176,7 @@ func (f *ResourceFetcher) FetchClusterScopedResources(
 			  AND (cs.deleted = false OR cs.deleted IS NULL OR cs.deletedAt > $timestamp)
 			RETURN DISTINCT cs.uid as uid, cs.kind as kind, cs.apiGroup as apiGroup, cs.namespace as namespace,
 			       cs.name as name, cs.labels as labels, cs.firstSeen as firstSeen, cs.lastSeen as lastSeen,
-			       cs.deleted as deleted, cs.deletedAt as deletedAt
+			       cs.deleted as deleted, cs.deletedAt as deletedAt, cs.cluster as cluster
 			LIMIT 100
 		`
 	} else {
@@ -178,7 +189,7 @@ func (f *ResourceFetcher) FetchClusterScopedResources(
 			  AND (cs.deleted = false OR cs.deleted IS NULL OR cs.deletedAt > $timestamp)
 			RETURN DISTINCT cs.uid as uid, cs.kind as kind, cs.apiGroup as apiGroup, cs.namespace as namespace,
 			       cs.name as name, cs.labels as labels, cs.firstSeen as firstSeen, cs.lastSeen as lastSeen,
-			       cs.deleted as deleted, cs.deletedAt as deletedAt
+			       cs.deleted as deleted, cs.deletedAt as deletedAt, cs.cluster as cluster
 			LIMIT 100
 		`
 	}
@@ -455,6 +466,11 @@ func parseResourceResults(result *graph.QueryResult) []resourceResult {
 		case float64:
 			r.DeletedAt = int64(deletedAt)
 		}
+		if len(row) > 10 {
+			if cluster, ok := row[10].(string); ok {
+				r.Cluster = cluster
+			}
+		}
 
 		if r.UID != "" {
 			resources = append(resources, r)
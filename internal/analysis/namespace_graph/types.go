@@ -10,6 +10,7 @@ import (
 // AnalyzeInput contains the parameters for namespace graph analysis
 type AnalyzeInput struct {
 	Namespace          string        // Required: Kubernetes namespace
+	Clusters           []string      // Optional: Restrict to resources of these clusters
 	Timestamp          int64         // Required: Point in time (Unix nanoseconds)
 	IncludeAnomalies   bool          // Optional: Include anomaly detection
 	IncludeCausalPaths bool          // Optional: Include causal path analysis
@@ -39,6 +40,7 @@ type Node struct {
 	Kind        string            `json:"kind"`
 	APIGroup    string            `json:"apiGroup,omitempty"`
 	Namespace   string            `json:"namespace"` // Empty for cluster-scoped
+	Cluster     string            `json:"cluster,omitempty"`
 	Name        string            `json:"name"`
 	Status      string            `json:"status"` // "unknown" for now
 	LatestEvent *ChangeEventInfo  `json:"latestEvent,omitempty"`
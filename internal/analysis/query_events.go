@@ -128,6 +128,7 @@ func (a *RootCauseAnalyzer) getChangeEvents(
 				StatusChanged: event.StatusChanged,
 				Description:   fmt.Sprintf("%s event", event.EventType),
 				Data:          []byte(event.Data),
+				FieldManagers: graph.ChangeEventFieldManagers(event),
 			}
 
 			events[resourceUID] = append(events[resourceUID], changeEvent)
@@ -292,3 +293,41 @@ func (a *RootCauseAnalyzer) getChangeEventsForRelated(
 
 	return nil
 }
+
+// BuildResourceNodes loads the change and Kubernetes events of the given resources
+// within the lookback window ending at endTimestamp and returns one RELATED node per
+// resource, with events converted to diff format (oldest first). Callers use it to run
+// node-level detection on resources outside of a causal graph.
+func (a *RootCauseAnalyzer) BuildResourceNodes(
+	ctx context.Context,
+	resources []SymptomResource,
+	endTimestamp int64,
+	lookbackNs int64,
+) ([]GraphNode, error) {
+	if len(resources) == 0 {
+		return []GraphNode{}, nil
+	}
+
+	uids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		uids = append(uids, resource.UID)
+	}
+
+	changeEvents, err := a.getChangeEvents(ctx, uids, endTimestamp, lookbackNs)
+	if err != nil {
+		return nil, err
+	}
+	k8sEvents, err := a.getK8sEvents(ctx, uids, endTimestamp, lookbackNs)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]GraphNode, 0, len(resources))
+	for _, resource := range resources {
+		node := createRelatedNode(createNodeID(resource.UID), resource, ConvertEventsToDiffFormat(changeEvents[resource.UID], true))
+		node.K8sEvents = k8sEvents[resource.UID]
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,40 @@
+package resourcelookup
+
+const (
+	// DefaultLimit is the default number of matches returned
+	DefaultLimit = 10
+
+	// MaxLimit is the maximum number of matches returned
+	MaxLimit = 50
+
+	// MaxCandidates bounds the resources read from the graph for scoring
+	MaxCandidates = 20000
+
+	// MinScore is the score below which resources are not considered a match
+	MinScore = 0.5
+
+	// MinSimilarity is the normalized Levenshtein similarity below which a
+	// term doesn't match a word at all
+	MinSimilarity = 0.7
+
+	// QueryTimeoutMs is the timeout for the candidate query in milliseconds
+	QueryTimeoutMs = 15000
+)
+
+// Weights of the fields a term can match
+const (
+	WeightName      = 1.0
+	WeightLabel     = 0.9
+	WeightNamespace = 0.9
+	WeightCluster   = 0.8
+)
+
+// Score factors
+const (
+	// KindMismatchFactor scales the score of resources of another kind than
+	// the kinds named in the query
+	KindMismatchFactor = 0.3
+
+	// DeletedFactor ranks deleted resources below live ones of equal score
+	DeletedFactor = 0.9
+)
@@ -0,0 +1,409 @@
+// Package resourcelookup resolves fuzzy descriptions of resources, such as
+// "checkout api pod in prod", into the resource identities of the graph.
+package resourcelookup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/texttheater/golang-levenshtein/levenshtein"
+)
+
+// stopwords are ignored in queries
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "in": true, "of": true,
+	"for": true, "on": true, "at": true, "from": true, "named": true,
+}
+
+// query is a parsed lookup query
+type query struct {
+	terms  []string          // free words, lowercase
+	kinds  map[string]bool   // kinds named in the query
+	labels map[string]string // key=value words
+}
+
+// Finder looks up resources by name, labels, kind and namespace
+type Finder struct {
+	graphClient   graph.Client
+	logger        *logging.Logger
+	maxCandidates int
+}
+
+// NewFinder creates a new Finder
+func NewFinder(graphClient graph.Client) *Finder {
+	return &Finder{
+		graphClient:   graphClient,
+		logger:        logging.GetLogger("resourcelookup"),
+		maxCandidates: MaxCandidates,
+	}
+}
+
+// Find returns the resources matching the input, best matches first
+func (f *Finder) Find(ctx context.Context, input FindInput) (*FindResponse, error) {
+	startTime := time.Now()
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	if input.Kind != "" {
+		if kind, ok := kindAliases[strings.ToLower(input.Kind)]; ok {
+			input.Kind = kind
+		}
+	}
+
+	words := strings.Fields(strings.ToLower(input.Query))
+	if len(words) == 0 && input.Kind == "" && input.Namespace == "" && input.Cluster == "" && len(input.Labels) == 0 {
+		return nil, fmt.Errorf("query or at least one filter is required")
+	}
+
+	// Kinds named in the query are resolved against the kinds in the graph
+	// first, so that they narrow down the candidates
+	knownKinds := make(map[string]string)
+	if input.Kind == "" && len(words) > 0 {
+		var err error
+		if knownKinds, err = f.fetchKinds(ctx); err != nil {
+			return nil, err
+		}
+	}
+	q := parseQuery(words, knownKinds)
+	for key, value := range input.Labels {
+		q.labels[key] = value
+	}
+
+	candidates, err := f.fetchCandidates(ctx, input, q, wantsEvents(input.Kind, words))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, resource := range candidates {
+		if match, ok := score(resource, q); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		// Of equally good matches, the shorter name is closer to the query
+		if len(matches[i].Name) != len(matches[j].Name) {
+			return len(matches[i].Name) < len(matches[j].Name)
+		}
+		if matches[i].LastSeen != matches[j].LastSeen {
+			return matches[i].LastSeen > matches[j].LastSeen
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	total := len(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	if matches == nil {
+		matches = []Match{}
+	}
+
+	if len(candidates) >= f.maxCandidates {
+		f.logger.Warn("Resource lookup %q scanned the maximum of %d candidates, narrow it down with filters", input.Query, f.maxCandidates)
+	}
+
+	return &FindResponse{
+		Query:             input.Query,
+		Matches:           matches,
+		Count:             len(matches),
+		TotalMatches:      total,
+		CandidatesScanned: len(candidates),
+		ExecutionTimeMs:   time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// fetchKinds returns the kinds of the resources in the graph, keyed by their
+// lowercase name
+func (f *Finder) fetchKinds(ctx context.Context) (map[string]string, error) {
+	result, err := f.graphClient.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (r:ResourceIdentity)
+			RETURN DISTINCT r.kind
+		`,
+		Timeout: QueryTimeoutMs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kinds: %w", err)
+	}
+
+	kinds := make(map[string]string, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		if kind, ok := row[0].(string); ok && kind != "" {
+			kinds[strings.ToLower(kind)] = kind
+		}
+	}
+	return kinds, nil
+}
+
+// fetchCandidates reads the resources passing the hard filters. Kubernetes
+// Event resources outnumber everything else and are only read when asked for.
+func (f *Finder) fetchCandidates(ctx context.Context, input FindInput, q query, includeEvents bool) ([]graph.ResourceIdentity, error) {
+	result, err := f.graphClient.ExecuteQuery(ctx, buildCandidateQuery(input, q, includeEvents, f.maxCandidates))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query resources: %w", err)
+	}
+
+	candidates := make([]graph.ResourceIdentity, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		props, err := graph.ParseNodeFromResult(row[0])
+		if err != nil {
+			f.logger.Warn("Failed to parse resource node: %v", err)
+			continue
+		}
+		candidates = append(candidates, graph.ParseResourceIdentityFromNode(props))
+	}
+	return candidates, nil
+}
+
+// buildCandidateQuery builds the Cypher query for the resources passing the
+// hard filters, most recently seen first. The filters are applied before the
+// limit, so resources filtered out don't take the place of matches. Resources
+// of other kinds than the ones named in the query score below MinScore, so
+// the named kinds are a hard filter as well.
+func buildCandidateQuery(input FindInput, q query, includeEvents bool, limit int) graph.GraphQuery {
+	params := map[string]interface{}{
+		"limit": limit,
+	}
+
+	var conditions []string
+	if input.Kind != "" {
+		conditions = append(conditions, "r.kind = $kind")
+		params["kind"] = input.Kind
+	} else if len(q.kinds) > 0 {
+		kinds := make([]string, 0, len(q.kinds))
+		for kind := range q.kinds {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		conditions = append(conditions, "r.kind IN $kinds")
+		params["kinds"] = kinds
+	} else if !includeEvents {
+		conditions = append(conditions, "r.kind <> 'Event'")
+	}
+	if input.Namespace != "" {
+		conditions = append(conditions, "r.namespace = $namespace")
+		params["namespace"] = input.Namespace
+	}
+	if input.ExcludeDeleted {
+		conditions = append(conditions, "coalesce(r.deleted, false) = false")
+	}
+	if input.Cluster != "" {
+		conditions = append(conditions, "r.cluster = $cluster")
+		params["cluster"] = input.Cluster
+	}
+	if input.AllowedNamespaces != nil {
+		conditions = append(conditions, "(coalesce(r.namespace, '') = '' OR r.namespace IN $allowedNamespaces)")
+		params["allowedNamespaces"] = input.AllowedNamespaces
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return graph.GraphQuery{
+		Query: fmt.Sprintf(`
+			MATCH (r:ResourceIdentity)
+			%s
+			RETURN r
+			ORDER BY r.lastSeen DESC
+			LIMIT $limit
+		`, where),
+		Parameters: params,
+		Timeout:    QueryTimeoutMs,
+	}
+}
+
+// wantsEvents reports whether the lookup asks for Kubernetes Event resources
+func wantsEvents(kind string, words []string) bool {
+	if kind != "" {
+		return kind == "Event"
+	}
+	for _, word := range words {
+		if word == "event" || kindAliases[word] == "Event" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQuery splits the query words into kinds, label selectors and free terms
+func parseQuery(words []string, knownKinds map[string]string) query {
+	q := query{kinds: make(map[string]bool), labels: make(map[string]string)}
+	for _, word := range words {
+		word = strings.Trim(word, ",;\"'")
+		if word == "" || stopwords[word] {
+			continue
+		}
+		if key, value, ok := strings.Cut(word, "="); ok && key != "" {
+			q.labels[key] = value
+			continue
+		}
+		if kind, ok := resolveKind(word, knownKinds); ok {
+			q.kinds[kind] = true
+			continue
+		}
+		q.terms = append(q.terms, word)
+	}
+	return q
+}
+
+// field is a resource property free terms are matched against
+type field struct {
+	name   string
+	value  string
+	weight float64
+}
+
+// score rates how well a resource matches the query. Label selectors must
+// match exactly and every free term must match some field, fuzzily. A term
+// scores by its best matching field and the term scores are averaged.
+func score(resource graph.ResourceIdentity, q query) (Match, bool) {
+	var matchedOn []string
+	for key, value := range q.labels {
+		if !labelMatches(resource.Labels, key, value) {
+			return Match{}, false
+		}
+		matchedOn = append(matchedOn, fmt.Sprintf("label %s=%s", key, value))
+	}
+
+	fields := []field{
+		{name: "name", value: resource.Name, weight: WeightName},
+		{name: "namespace", value: resource.Namespace, weight: WeightNamespace},
+		{name: "cluster", value: resource.Cluster, weight: WeightCluster},
+	}
+	keys := make([]string, 0, len(resource.Labels))
+	for key := range resource.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, field{name: "label " + key, value: resource.Labels[key], weight: WeightLabel})
+	}
+
+	total := 1.0
+	if len(q.terms) > 0 {
+		sum := 0.0
+		for _, term := range q.terms {
+			best, bestField := 0.0, ""
+			for _, fld := range fields {
+				if s := matchText(term, fld.value) * fld.weight; s > best {
+					best, bestField = s, fld.name
+				}
+			}
+			if best == 0 {
+				return Match{}, false
+			}
+			matchedOn = append(matchedOn, fmt.Sprintf("%s~%s", bestField, term))
+			sum += best
+		}
+		total = sum / float64(len(q.terms))
+	}
+
+	if len(q.kinds) > 0 {
+		if q.kinds[resource.Kind] {
+			matchedOn = append(matchedOn, "kind="+resource.Kind)
+		} else {
+			total *= KindMismatchFactor
+		}
+	}
+	if resource.Deleted {
+		total *= DeletedFactor
+	}
+	if total < MinScore {
+		return Match{}, false
+	}
+
+	sort.Strings(matchedOn)
+	return Match{
+		UID:       resource.UID,
+		Kind:      resource.Kind,
+		APIGroup:  resource.APIGroup,
+		Namespace: resource.Namespace,
+		Cluster:   resource.Cluster,
+		Name:      resource.Name,
+		Labels:    resource.Labels,
+		Deleted:   resource.Deleted,
+		DeletedAt: resource.DeletedAt,
+		LastSeen:  resource.LastSeen,
+		Score:     float64(int(total*1000)) / 1000,
+		MatchedOn: matchedOn,
+	}, true
+}
+
+// labelMatches reports whether a label selector matches. Keys and values are
+// compared case-insensitively since the query is lowercased.
+func labelMatches(labels map[string]string, key, value string) bool {
+	for k, v := range labels {
+		if strings.EqualFold(k, key) && strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchText scores a lowercase term against a value between 0 and 1. The
+// value matches as a whole or through one of its parts: "chekout" matches
+// "checkout-api-7f9c" through the part "checkout".
+func matchText(term, value string) float64 {
+	value = strings.ToLower(value)
+	if value == "" {
+		return 0
+	}
+	if term == value {
+		return 1
+	}
+
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == '-' || r == '.' || r == '_' || r == '/' || r == ':'
+	})
+	best := 0.0
+	for _, part := range parts {
+		switch {
+		case part == term:
+			return 1
+		case len(term) >= 3 && strings.HasPrefix(part, term):
+			best = max(best, 0.9)
+		}
+	}
+	if len(term) >= 3 && strings.Contains(value, term) {
+		best = max(best, 0.8)
+	}
+
+	for _, candidate := range append(parts, value) {
+		if s := similarity(term, candidate); s >= MinSimilarity {
+			best = max(best, s)
+		}
+	}
+	return best
+}
+
+// similarity is the Levenshtein distance normalized to 0..1
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	distance := levenshtein.DistanceForStrings(ra, rb, levenshtein.DefaultOptionsWithSub)
+	return 1 - float64(distance)/float64(longest)
+}
@@ -0,0 +1,156 @@
+package resourcelookup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFinder(t *testing.T) *Finder {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	_, err := client.ExecuteQuery(ctx, graph.GraphQuery{Query: `
+		CREATE (:ResourceIdentity {uid: 'pod', kind: 'Pod', namespace: 'prod', name: 'checkout-api-7f9c-x2k4', cluster: 'eu', labels: '{"app":"checkout","tier":"api"}', lastSeen: 300, deleted: false})
+		CREATE (:ResourceIdentity {uid: 'old-pod', kind: 'Pod', namespace: 'prod', name: 'checkout-api-6d8b-q9z1', cluster: 'eu', labels: '{"app":"checkout","tier":"api"}', lastSeen: 100, deleted: true, deletedAt: 100})
+		CREATE (:ResourceIdentity {uid: 'deploy', kind: 'Deployment', apiGroup: 'apps', namespace: 'prod', name: 'checkout-api', cluster: 'eu', labels: '{"app":"checkout"}', lastSeen: 200, deleted: false})
+		CREATE (:ResourceIdentity {uid: 'staging-pod', kind: 'Pod', namespace: 'staging', name: 'checkout-api-1a2b-c3d4', cluster: 'eu', labels: '{"app":"checkout"}', lastSeen: 250, deleted: false})
+		CREATE (:ResourceIdentity {uid: 'svc', kind: 'Service', namespace: 'prod', name: 'payments', cluster: 'eu', lastSeen: 200, deleted: false})
+		CREATE (:ResourceIdentity {uid: 'node', kind: 'Node', namespace: '', name: 'worker-prod-1', cluster: 'eu', lastSeen: 200, deleted: false})
+		CREATE (:ResourceIdentity {uid: 'event', kind: 'Event', namespace: 'prod', name: 'checkout-api-7f9c-x2k4.17a', cluster: 'eu', lastSeen: 300, deleted: false})
+	`})
+	require.NoError(t, err)
+
+	return NewFinder(client)
+}
+
+func matchUIDs(matches []Match) []string {
+	uids := make([]string, len(matches))
+	for i, match := range matches {
+		uids[i] = match.UID
+	}
+	return uids
+}
+
+func TestFinder_Find(t *testing.T) {
+	finder := newTestFinder(t)
+
+	tests := []struct {
+		name     string
+		input    FindInput
+		expected []string
+	}{
+		{"kind alias and namespace", FindInput{Query: "checkout api po in prod"}, []string{"pod", "old-pod"}},
+		{"typo", FindInput{Query: "chekout deployment"}, []string{"deploy"}},
+		{"label selector", FindInput{Query: "app=checkout", Namespace: "staging"}, []string{"staging-pod"}},
+		{"label filter", FindInput{Labels: map[string]string{"tier": "api"}, ExcludeDeleted: true}, []string{"pod"}},
+		{"kind filter alias", FindInput{Query: "payments", Kind: "svc"}, []string{"svc"}},
+		{"cluster filter", FindInput{Query: "payments", Cluster: "us"}, nil},
+		{"events on request", FindInput{Query: "checkout events"}, []string{"event"}},
+		{"limit", FindInput{Query: "checkout api po", Limit: 1}, []string{"pod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := finder.Find(context.Background(), tt.input)
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Empty(t, result.Matches)
+			} else {
+				assert.Equal(t, tt.expected, matchUIDs(result.Matches))
+			}
+			assert.Equal(t, len(result.Matches), result.Count)
+		})
+	}
+}
+
+func TestFinder_Ranking(t *testing.T) {
+	finder := newTestFinder(t)
+
+	result, err := finder.Find(context.Background(), FindInput{Query: "checkout api"})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Matches)
+
+	// The exact name ranks first, deleted resources rank below live ones
+	assert.Equal(t, "deploy", result.Matches[0].UID)
+	assert.Equal(t, "old-pod", result.Matches[len(result.Matches)-1].UID)
+	assert.NotContains(t, matchUIDs(result.Matches), "event")
+	assert.Equal(t, []string{"name~api", "name~checkout"}, result.Matches[0].MatchedOn)
+	assert.True(t, result.Matches[len(result.Matches)-1].Deleted)
+}
+
+func TestFinder_FiltersBeforeLimit(t *testing.T) {
+	finder := newTestFinder(t)
+	finder.maxCandidates = 1
+
+	// The most recently seen resource is deleted
+	_, err := finder.graphClient.ExecuteQuery(context.Background(), graph.GraphQuery{Query: `
+		CREATE (:ResourceIdentity {uid: 'dead-pod', kind: 'Pod', namespace: 'prod', name: 'checkout-api-0000-dead', cluster: 'eu', lastSeen: 400, deleted: true, deletedAt: 400})
+	`})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    FindInput
+		expected []string
+	}{
+		{"exclude deleted", FindInput{Query: "checkout api", ExcludeDeleted: true}, []string{"pod"}},
+		{"kind in query", FindInput{Query: "checkout deployment"}, []string{"deploy"}},
+		{"kind alias in query", FindInput{Query: "payments svc"}, []string{"svc"}},
+		{"namespace", FindInput{Query: "checkout", Namespace: "staging"}, []string{"staging-pod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := finder.Find(context.Background(), tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, matchUIDs(result.Matches))
+			assert.Equal(t, 1, result.CandidatesScanned)
+		})
+	}
+}
+
+func TestFinder_AllowedNamespaces(t *testing.T) {
+	finder := newTestFinder(t)
+
+	result, err := finder.Find(context.Background(), FindInput{Query: "prod", AllowedNamespaces: []string{"staging"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node"}, matchUIDs(result.Matches))
+}
+
+func TestFinder_EmptyQuery(t *testing.T) {
+	finder := newTestFinder(t)
+
+	_, err := finder.Find(context.Background(), FindInput{Query: "  "})
+	assert.Error(t, err)
+}
+
+func TestMatchText(t *testing.T) {
+	tests := []struct {
+		term  string
+		value string
+		min   float64
+		max   float64
+	}{
+		{"checkout", "checkout-api-7f9c", 1, 1},
+		{"check", "checkout-api-7f9c", 0.9, 0.9},
+		{"chekout", "checkout-api-7f9c", 0.8, 0.9},
+		{"prod", "production", 0.9, 0.9},
+		{"payments", "checkout-api-7f9c", 0, 0},
+	}
+
+	for _, tt := range tests {
+		s := matchText(tt.term, tt.value)
+		assert.GreaterOrEqual(t, s, tt.min, "%s vs %s", tt.term, tt.value)
+		assert.LessOrEqual(t, s, tt.max, "%s vs %s", tt.term, tt.value)
+	}
+}
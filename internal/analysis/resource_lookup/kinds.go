@@ -0,0 +1,68 @@
+package resourcelookup
+
+import "strings"
+
+// kindAliases maps kubectl short names and plurals to kinds. Kinds not
+// listed here are still recognized by their name and plural, as long as a
+// resource of that kind is in the graph.
+var kindAliases = map[string]string{
+	"po":            "Pod",
+	"pods":          "Pod",
+	"deploy":        "Deployment",
+	"deployments":   "Deployment",
+	"rs":            "ReplicaSet",
+	"replicasets":   "ReplicaSet",
+	"sts":           "StatefulSet",
+	"statefulsets":  "StatefulSet",
+	"ds":            "DaemonSet",
+	"daemonsets":    "DaemonSet",
+	"job":           "Job",
+	"jobs":          "Job",
+	"cj":            "CronJob",
+	"cronjobs":      "CronJob",
+	"svc":           "Service",
+	"services":      "Service",
+	"ep":            "Endpoints",
+	"ing":           "Ingress",
+	"ingresses":     "Ingress",
+	"netpol":        "NetworkPolicy",
+	"cm":            "ConfigMap",
+	"configmaps":    "ConfigMap",
+	"secrets":       "Secret",
+	"pvc":           "PersistentVolumeClaim",
+	"pv":            "PersistentVolume",
+	"sc":            "StorageClass",
+	"sa":            "ServiceAccount",
+	"ns":            "Namespace",
+	"namespaces":    "Namespace",
+	"no":            "Node",
+	"nodes":         "Node",
+	"hpa":           "HorizontalPodAutoscaler",
+	"pdb":           "PodDisruptionBudget",
+	"ev":            "Event",
+	"events":        "Event",
+	"crd":           "CustomResourceDefinition",
+	"crds":          "CustomResourceDefinition",
+	"helmrelease":   "HelmRelease",
+	"hr":            "HelmRelease",
+	"kustomization": "Kustomization",
+	"ks":            "Kustomization",
+}
+
+// resolveKind returns the kind a word refers to, given the kinds present in
+// the graph. Matching is case-insensitive and accepts aliases and plurals.
+func resolveKind(word string, knownKinds map[string]string) (string, bool) {
+	word = strings.ToLower(word)
+	if kind, ok := kindAliases[word]; ok {
+		return kind, true
+	}
+	if kind, ok := knownKinds[word]; ok {
+		return kind, true
+	}
+	for _, suffix := range []string{"es", "s"} {
+		if kind, ok := knownKinds[strings.TrimSuffix(word, suffix)]; ok && strings.HasSuffix(word, suffix) {
+			return kind, true
+		}
+	}
+	return "", false
+}
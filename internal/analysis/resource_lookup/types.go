@@ -0,0 +1,51 @@
+package resourcelookup
+
+// FindInput contains the parameters of a resource lookup
+type FindInput struct {
+	// Query is free text such as "checkout api pod in prod". Words are
+	// matched fuzzily against names, label values, namespaces and clusters;
+	// kind names and aliases (po, deploy, svc, ...) select kinds and
+	// key=value words require a label.
+	Query string
+
+	Kind           string            // Optional: Restrict to a kind, aliases accepted
+	Namespace      string            // Optional: Restrict to a namespace
+	Cluster        string            // Optional: Restrict to a cluster
+	Labels         map[string]string // Optional: Require these labels
+	ExcludeDeleted bool              // Optional: Leave out deleted resources
+	Limit          int               // Optional: Maximum number of matches (default 10)
+
+	// AllowedNamespaces restricts the lookup to a tenant's namespaces and
+	// cluster-scoped resources. Nil means unrestricted.
+	AllowedNamespaces []string
+}
+
+// FindResponse is the API response structure
+type FindResponse struct {
+	Query             string  `json:"query"`
+	Matches           []Match `json:"matches"`
+	Count             int     `json:"count"`
+	TotalMatches      int     `json:"totalMatches"`
+	CandidatesScanned int     `json:"candidatesScanned"`
+	ExecutionTimeMs   int64   `json:"executionTimeMs"`
+}
+
+// Match is a resource matching the lookup
+type Match struct {
+	UID       string            `json:"uid"`
+	Kind      string            `json:"kind"`
+	APIGroup  string            `json:"apiGroup,omitempty"`
+	Namespace string            `json:"namespace"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Deleted   bool              `json:"deleted,omitempty"`
+	DeletedAt int64             `json:"deletedAt,omitempty"` // Unix nanoseconds
+	LastSeen  int64             `json:"lastSeen"`            // Unix nanoseconds
+
+	// Score is between 0 and 1, higher is better
+	Score float64 `json:"score"`
+
+	// MatchedOn explains the match, e.g. name~chekout or kind=Pod
+	MatchedOn []string `json:"matchedOn"`
+}
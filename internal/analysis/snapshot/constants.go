@@ -0,0 +1,12 @@
+package snapshot
+
+const (
+	// DefaultLimit is the default number of resources in a snapshot
+	DefaultLimit = 1000
+
+	// MaxLimit is the maximum number of resources in a snapshot
+	MaxLimit = 5000
+
+	// QueryTimeoutMs is the timeout for the snapshot query in milliseconds
+	QueryTimeoutMs = 30000
+)
@@ -0,0 +1,167 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serverMetadataFields are set by the API server and prevent applying a
+// manifest to another cluster. Owner references are dropped as well: they
+// point at UIDs that don't exist elsewhere, so the garbage collector would
+// delete the applied object.
+var serverMetadataFields = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"managedFields",
+	"selfLink",
+	"ownerReferences",
+}
+
+// lastAppliedAnnotation duplicates the object and is dropped from manifests
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// applyOrder ranks the kinds that other resources depend on, so that a
+// snapshot can be applied in one go. Other kinds follow in snapshot order.
+var applyOrder = map[string]int{
+	"Namespace":                1,
+	"CustomResourceDefinition": 2,
+	"ServiceAccount":           3,
+	"Secret":                   4,
+	"ConfigMap":                4,
+	"PersistentVolumeClaim":    5,
+}
+
+// RenderManifests renders the resources of a snapshot as a multi-document
+// YAML stream of Kubernetes manifests. Server-managed metadata is removed so
+// the manifests can be applied to another cluster, and with includeStatus
+// unset the status is dropped as well. Resources without a recorded object
+// are listed in the header comment.
+func RenderManifests(resp *SnapshotResponse, includeStatus bool) ([]byte, error) {
+	resources := make([]ResourceState, len(resp.Resources))
+	copy(resources, resp.Resources)
+	sort.SliceStable(resources, func(i, j int) bool {
+		return rank(resources[i].Kind) < rank(resources[j].Kind)
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Snapshot at %s\n", time.Unix(0, resp.At).UTC().Format(time.RFC3339))
+
+	// Listed in the header so the comments don't end up in another document
+	for _, resource := range resources {
+		if len(resource.Object) == 0 {
+			fmt.Fprintf(&buf, "# %s %s: no recorded state\n", resource.Kind, qualifiedName(resource))
+		}
+	}
+
+	for _, resource := range resources {
+		if len(resource.Object) == 0 {
+			continue
+		}
+
+		manifest, err := cleanManifest(resource, includeStatus)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s %s: %w", resource.Kind, qualifiedName(resource), err)
+		}
+
+		buf.WriteString("---\n")
+		fmt.Fprintf(&buf, "# %s %s, %s at %s\n", resource.Kind, qualifiedName(resource),
+			resource.EventType, time.Unix(0, resource.ChangedAt).UTC().Format(time.RFC3339))
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(manifest); err != nil {
+			return nil, fmt.Errorf("failed to render %s %s: %w", resource.Kind, qualifiedName(resource), err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// cleanManifest decodes the object of a resource and strips what the API
+// server sets
+func cleanManifest(resource ResourceState, includeStatus bool) (map[string]interface{}, error) {
+	// Numbers are decoded as json.Number so that large integers aren't
+	// rendered in exponent notation
+	var manifest map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(resource.Object))
+	decoder.UseNumber()
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, err
+	}
+	convertNumbers(manifest)
+
+	if _, ok := manifest["apiVersion"]; !ok && resource.Version != "" {
+		if resource.APIGroup != "" {
+			manifest["apiVersion"] = resource.APIGroup + "/" + resource.Version
+		} else {
+			manifest["apiVersion"] = resource.Version
+		}
+	}
+	if _, ok := manifest["kind"]; !ok {
+		manifest["kind"] = resource.Kind
+	}
+	if !includeStatus {
+		delete(manifest, "status")
+	}
+
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return manifest, nil
+}
+
+// convertNumbers replaces the json.Numbers of a decoded object, which YAML
+// would quote as strings, with integers or floats
+func convertNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	}
+	return value
+}
+
+func rank(kind string) int {
+	if r, ok := applyOrder[kind]; ok {
+		return r
+	}
+	return len(applyOrder) + 1
+}
+
+func qualifiedName(resource ResourceState) string {
+	if resource.Namespace == "" {
+		return resource.Name
+	}
+	return resource.Namespace + "/" + resource.Name
+}
@@ -0,0 +1,152 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReconstructor(t *testing.T) *Reconstructor {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	// The Deployment is scaled at 200, the old ConfigMap is deleted at 150,
+	// the new one created at 300 and the Pod lives in another namespace
+	_, err := client.ExecuteQuery(ctx, graph.GraphQuery{Query: `
+		CREATE (d:ResourceIdentity {uid: 'deploy', kind: 'Deployment', apiGroup: 'apps', version: 'v1', namespace: 'shop', name: 'api', deleted: false})
+		CREATE (old:ResourceIdentity {uid: 'cm-old', kind: 'ConfigMap', version: 'v1', namespace: 'shop', name: 'settings', deleted: true, deletedAt: 150})
+		CREATE (new:ResourceIdentity {uid: 'cm-new', kind: 'ConfigMap', version: 'v1', namespace: 'shop', name: 'settings', deleted: false})
+		CREATE (ev:ResourceIdentity {uid: 'event', kind: 'Event', version: 'v1', namespace: 'shop', name: 'api.17a', deleted: false})
+		CREATE (pod:ResourceIdentity {uid: 'pod', kind: 'Pod', version: 'v1', namespace: 'payments', name: 'worker', deleted: false})
+		CREATE (d)-[:CHANGED]->(:ChangeEvent {id: 'd-1', timestamp: 100, eventType: 'CREATE', status: 'Ready', data: '{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api","namespace":"shop","uid":"deploy","resourceVersion":"1"},"spec":{"replicas":1}}'})
+		CREATE (d)-[:CHANGED]->(:ChangeEvent {id: 'd-2', timestamp: 200, eventType: 'UPDATE', status: 'Ready', data: '{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api","namespace":"shop","uid":"deploy","resourceVersion":"2"},"spec":{"replicas":3}}'})
+		CREATE (old)-[:CHANGED]->(:ChangeEvent {id: 'cm-1', timestamp: 50, eventType: 'CREATE', status: 'Ready', data: '{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop"},"data":{"mode":"old"}}'})
+		CREATE (old)-[:CHANGED]->(:ChangeEvent {id: 'cm-2', timestamp: 150, eventType: 'DELETE', status: 'Terminating'})
+		CREATE (new)-[:CHANGED]->(:ChangeEvent {id: 'cm-3', timestamp: 300, eventType: 'CREATE', status: 'Ready', data: '{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop"},"data":{"mode":"new"}}'})
+		CREATE (ev)-[:CHANGED]->(:ChangeEvent {id: 'ev-1', timestamp: 100, eventType: 'CREATE', status: 'Ready'})
+		CREATE (pod)-[:CHANGED]->(:ChangeEvent {id: 'p-1', timestamp: 100, eventType: 'CREATE', status: 'Error'})
+	`})
+	require.NoError(t, err)
+
+	return NewReconstructor(client)
+}
+
+func stateUIDs(resources []ResourceState) []string {
+	uids := make([]string, len(resources))
+	for i, resource := range resources {
+		uids[i] = resource.UID
+	}
+	return uids
+}
+
+func TestReconstructor_Snapshot(t *testing.T) {
+	reconstructor := newTestReconstructor(t)
+
+	tests := []struct {
+		name     string
+		input    SnapshotInput
+		expected []string
+	}{
+		{"before anything", SnapshotInput{At: 10}, []string{}},
+		{"old config", SnapshotInput{At: 120, Namespace: "shop"}, []string{"cm-old", "deploy"}},
+		{"config deleted", SnapshotInput{At: 160, Namespace: "shop"}, []string{"deploy"}},
+		{"config recreated", SnapshotInput{At: 300, Namespace: "shop"}, []string{"cm-new", "deploy"}},
+		{"all namespaces", SnapshotInput{At: 300}, []string{"pod", "cm-new", "deploy"}},
+		{"kinds", SnapshotInput{At: 300, Kinds: []string{"Event"}}, []string{"event"}},
+		{"tenant", SnapshotInput{At: 300, AllowedNamespaces: []string{"payments"}}, []string{"pod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := reconstructor.Snapshot(context.Background(), tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stateUIDs(result.Resources))
+			assert.Equal(t, len(result.Resources), result.Count)
+		})
+	}
+}
+
+func TestReconstructor_LatestState(t *testing.T) {
+	reconstructor := newTestReconstructor(t)
+
+	result, err := reconstructor.Snapshot(context.Background(), SnapshotInput{At: 199, Namespace: "shop", Kinds: []string{"Deployment"}})
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 1)
+	assert.Equal(t, int64(100), result.Resources[0].ChangedAt)
+	assert.Contains(t, string(result.Resources[0].Object), `"replicas":1`)
+
+	result, err = reconstructor.Snapshot(context.Background(), SnapshotInput{At: 200, Namespace: "shop", Kinds: []string{"Deployment"}})
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 1)
+	assert.Equal(t, "UPDATE", result.Resources[0].EventType)
+	assert.Contains(t, string(result.Resources[0].Object), `"replicas":3`)
+
+	result, err = reconstructor.Snapshot(context.Background(), SnapshotInput{At: 300, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Count)
+	assert.True(t, result.Truncated)
+
+	_, err = reconstructor.Snapshot(context.Background(), SnapshotInput{})
+	assert.Error(t, err)
+}
+
+func TestRenderManifests(t *testing.T) {
+	reconstructor := newTestReconstructor(t)
+
+	result, err := reconstructor.Snapshot(context.Background(), SnapshotInput{At: 300})
+	require.NoError(t, err)
+
+	out, err := RenderManifests(result, false)
+	require.NoError(t, err)
+	expected := `# Snapshot at 1970-01-01T00:00:00Z
+# Pod payments/worker: no recorded state
+---
+# ConfigMap shop/settings, CREATE at 1970-01-01T00:00:00Z
+apiVersion: v1
+data:
+  mode: new
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: shop
+---
+# Deployment shop/api, UPDATE at 1970-01-01T00:00:00Z
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: shop
+spec:
+  replicas: 3
+`
+	assert.Equal(t, expected, string(out))
+}
+
+func TestCleanManifest(t *testing.T) {
+	resource := ResourceState{
+		Kind:     "Widget",
+		APIGroup: "example.com",
+		Version:  "v1",
+		Object: []byte(`{"metadata":{"name":"w","uid":"u","resourceVersion":"9","ownerReferences":[{"uid":"o"}],` +
+			`"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},"spec":{"size":10000000},"status":{"ready":true}}`),
+	}
+
+	manifest, err := cleanManifest(resource, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "w"},
+		"spec":       map[string]interface{}{"size": int64(10000000)},
+		"status":     map[string]interface{}{"ready": true},
+	}, manifest)
+}
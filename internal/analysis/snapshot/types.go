@@ -0,0 +1,45 @@
+package snapshot
+
+import "encoding/json"
+
+// SnapshotInput contains the parameters of a point-in-time reconstruction
+type SnapshotInput struct {
+	At        int64    // Unix nanoseconds
+	Namespace string   // Optional: Restrict to a namespace
+	Cluster   string   // Optional: Restrict to a cluster
+	Kinds     []string // Optional: Restrict to kinds, Kubernetes Events are left out otherwise
+	Limit     int      // Optional: Maximum number of resources (default 1000)
+
+	// AllowedNamespaces restricts the snapshot to a tenant's namespaces.
+	// Nil means unrestricted.
+	AllowedNamespaces []string
+}
+
+// SnapshotResponse is the API response structure
+type SnapshotResponse struct {
+	At              int64           `json:"at"` // Unix nanoseconds
+	Namespace       string          `json:"namespace,omitempty"`
+	Resources       []ResourceState `json:"resources"`
+	Count           int             `json:"count"`
+	Truncated       bool            `json:"truncated"`
+	ExecutionTimeMs int64           `json:"executionTimeMs"`
+}
+
+// ResourceState is a resource as it was at the snapshot time: the state
+// recorded by its latest change at or before that time
+type ResourceState struct {
+	UID       string `json:"uid"`
+	Kind      string `json:"kind"`
+	APIGroup  string `json:"apiGroup,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+	Name      string `json:"name"`
+
+	Status    string `json:"status,omitempty"`
+	EventType string `json:"eventType"`
+	ChangedAt int64  `json:"changedAt"` // Unix nanoseconds of the change
+
+	// Object is the full resource, absent when the change recorded none
+	Object json.RawMessage `json:"object,omitempty"`
+}
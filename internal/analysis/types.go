@@ -81,10 +81,11 @@ type EventSignificance struct {
 
 // EventDiff represents a single change between consecutive events
 type EventDiff struct {
-	Path     string `json:"path"`           // JSON path, e.g., "spec.replicas"
-	OldValue any    `json:"old,omitempty"`  // Previous value (nil for additions)
-	NewValue any    `json:"new,omitempty"`  // New value (nil for removals)
-	Op       string `json:"op"`             // "add", "remove", "replace"
+	Path     string `json:"path"`              // JSON path, e.g., "spec.replicas"
+	OldValue any    `json:"old,omitempty"`     // Previous value (nil for additions)
+	NewValue any    `json:"new,omitempty"`     // New value (nil for removals)
+	Op       string `json:"op"`                // "add", "remove", "replace"
+	Manager  string `json:"manager,omitempty"` // Field manager that made the change, from managedFields
 }
 
 // ChangeEventInfo represents a change event in the causal chain
@@ -106,6 +107,14 @@ type ChangeEventInfo struct {
 
 	// Legacy format - full resource JSON (deprecated, for backward compat)
 	Data []byte `json:"data,omitempty"`
+
+	// Field managers that made the changes in Diff, e.g. "kubectl-edit" or
+	// "argocd-controller"
+	Managers []string `json:"managers,omitempty"`
+
+	// FieldManagers maps field paths to the manager that last set them, used
+	// to annotate Diff
+	FieldManagers map[string]string `json:"-"`
 }
 
 // K8sEventInfo represents a Kubernetes Event (kind: Event) related to a resource
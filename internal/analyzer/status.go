@@ -412,6 +412,14 @@ func (r *resourceData) metadata() map[string]any {
 	return getMapValue(r.object, "metadata")
 }
 
+// ResourceVersion returns metadata.resourceVersion of the resource
+func (r *ResourceData) ResourceVersion() string {
+	if r == nil {
+		return ""
+	}
+	return getStringValue(r.metadata(), "resourceVersion")
+}
+
 func (r *resourceData) isDeleting() bool {
 	meta := r.metadata()
 	if meta == nil {
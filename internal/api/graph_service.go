@@ -2,18 +2,27 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/moolen/spectre/internal/analysis/anomaly"
+	blastradius "github.com/moolen/spectre/internal/analysis/blast_radius"
 	causalpaths "github.com/moolen/spectre/internal/analysis/causal_paths"
+	eventsearch "github.com/moolen/spectre/internal/analysis/event_search"
+	graphquery "github.com/moolen/spectre/internal/analysis/graph_query"
 	namespacegraph "github.com/moolen/spectre/internal/analysis/namespace_graph"
+	resourcelookup "github.com/moolen/spectre/internal/analysis/resource_lookup"
+	"github.com/moolen/spectre/internal/analysis/snapshot"
+	"github.com/moolen/spectre/internal/auth"
 	"github.com/moolen/spectre/internal/graph"
 	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // GraphService provides unified access to graph analysis operations.
-// It wraps existing analyzers (causal paths, anomaly detection, namespace graph)
+// It wraps existing analyzers (causal paths, blast radius, anomaly detection, namespace graph)
 // and provides a service layer for both REST handlers and MCP tools.
 type GraphService struct {
 	graphClient graph.Client
@@ -24,6 +33,11 @@ type GraphService struct {
 	pathDiscoverer  *causalpaths.PathDiscoverer
 	anomalyDetector *anomaly.AnomalyDetector
 	namespaceAnalyzer *namespacegraph.Analyzer
+	eventSearcher   *eventsearch.Searcher
+	blastRadiusAnalyzer *blastradius.Analyzer
+	resourceFinder  *resourcelookup.Finder
+	queryExecutor   *graphquery.Executor
+	snapshotReconstructor *snapshot.Reconstructor
 }
 
 // NewGraphService creates a new GraphService instance
@@ -35,6 +49,11 @@ func NewGraphService(graphClient graph.Client, logger *logging.Logger, tracer tr
 		pathDiscoverer:    causalpaths.NewPathDiscoverer(graphClient),
 		anomalyDetector:   anomaly.NewDetector(graphClient),
 		namespaceAnalyzer: namespacegraph.NewAnalyzer(graphClient),
+		eventSearcher:     eventsearch.NewSearcher(graphClient),
+		blastRadiusAnalyzer: blastradius.NewAnalyzer(graphClient),
+		resourceFinder:    resourcelookup.NewFinder(graphClient),
+		queryExecutor:     graphquery.NewExecutor(graphClient),
+		snapshotReconstructor: snapshot.NewReconstructor(graphClient),
 	}
 }
 
@@ -50,6 +69,10 @@ func (s *GraphService) DiscoverCausalPaths(ctx context.Context, input causalpath
 	s.logger.Debug("GraphService: Discovering causal paths for resource %s at timestamp %d",
 		input.ResourceUID, input.FailureTimestamp)
 
+	if err := CheckResourceAccess(ctx, s.graphClient, input.ResourceUID); err != nil {
+		return nil, err
+	}
+
 	// Delegate to the existing path discoverer
 	result, err := s.pathDiscoverer.DiscoverCausalPaths(ctx, input)
 	if err != nil {
@@ -61,7 +84,36 @@ func (s *GraphService) DiscoverCausalPaths(ctx context.Context, input causalpath
 	}
 
 	s.logger.Debug("GraphService: Discovered %d causal paths", len(result.Paths))
-	return result, nil
+	return ScopeCausalPathsResponse(ctx, result), nil
+}
+
+// AnalyzeBlastRadius finds the downstream resources that degraded after a change
+func (s *GraphService) AnalyzeBlastRadius(ctx context.Context, input blastradius.BlastRadiusInput) (*blastradius.BlastRadiusResponse, error) {
+	// Add tracing span
+	var span trace.Span
+	if s.tracer != nil {
+		ctx, span = s.tracer.Start(ctx, "graph.analyzeBlastRadius")
+		defer span.End()
+	}
+
+	s.logger.Debug("GraphService: Analyzing blast radius of resource %s changed at %d",
+		input.ResourceUID, input.ChangeTimestamp)
+
+	if err := CheckResourceAccess(ctx, s.graphClient, input.ResourceUID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.blastRadiusAnalyzer.Analyze(ctx, input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		s.logger.Error("GraphService: Failed to analyze blast radius: %v", err)
+		return nil, fmt.Errorf("blast radius analysis failed: %w", err)
+	}
+
+	s.logger.Debug("GraphService: Blast radius has %d impacted resources", result.Metadata.ImpactedCount)
+	return ScopeBlastRadiusResponse(ctx, result), nil
 }
 
 // DetectAnomalies detects anomalies in a resource's causal subgraph
@@ -76,6 +128,10 @@ func (s *GraphService) DetectAnomalies(ctx context.Context, input anomaly.Detect
 	s.logger.Debug("GraphService: Detecting anomalies for resource %s from %d to %d",
 		input.ResourceUID, input.Start, input.End)
 
+	if err := CheckResourceAccess(ctx, s.graphClient, input.ResourceUID); err != nil {
+		return nil, err
+	}
+
 	// Delegate to the existing anomaly detector
 	result, err := s.anomalyDetector.Detect(ctx, input)
 	if err != nil {
@@ -87,7 +143,7 @@ func (s *GraphService) DetectAnomalies(ctx context.Context, input anomaly.Detect
 	}
 
 	s.logger.Debug("GraphService: Detected %d anomalies", len(result.Anomalies))
-	return result, nil
+	return ScopeAnomalyResponse(ctx, result), nil
 }
 
 // AnalyzeNamespaceGraph analyzes resources and relationships in a namespace at a point in time
@@ -102,6 +158,10 @@ func (s *GraphService) AnalyzeNamespaceGraph(ctx context.Context, input namespac
 	s.logger.Debug("GraphService: Analyzing namespace graph for %s at timestamp %d",
 		input.Namespace, input.Timestamp)
 
+	if err := CheckNamespaceAccess(ctx, input.Namespace); err != nil {
+		return nil, err
+	}
+
 	// Delegate to the existing namespace analyzer
 	result, err := s.namespaceAnalyzer.Analyze(ctx, input)
 	if err != nil {
@@ -114,5 +174,214 @@ func (s *GraphService) AnalyzeNamespaceGraph(ctx context.Context, input namespac
 
 	s.logger.Debug("GraphService: Namespace graph has %d nodes and %d edges",
 		result.Metadata.NodeCount, result.Metadata.EdgeCount)
+	return ScopeNamespaceGraphResponse(ctx, result), nil
+}
+
+// SearchEvents runs a full-text search over event and error messages,
+// restricted to the caller's tenant scope
+func (s *GraphService) SearchEvents(ctx context.Context, input eventsearch.SearchInput) (*eventsearch.SearchResponse, error) {
+	// Add tracing span
+	var span trace.Span
+	if s.tracer != nil {
+		ctx, span = s.tracer.Start(ctx, "graph.searchEvents")
+		defer span.End()
+	}
+
+	s.logger.Debug("GraphService: Searching events for %q from %d to %d",
+		input.Query, input.Start, input.End)
+
+	for _, namespace := range input.Namespaces {
+		if err := CheckNamespaceAccess(ctx, namespace); err != nil {
+			return nil, err
+		}
+	}
+	input.AllowedNamespaces = auth.AllowedNamespaces(ctx)
+
+	result, err := s.eventSearcher.Search(ctx, input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		s.logger.Error("GraphService: Failed to search events: %v", err)
+		return nil, fmt.Errorf("event search failed: %w", err)
+	}
+
+	s.logger.Debug("GraphService: Event search returned %d hits", result.Count)
+	return result, nil
+}
+
+// FindResources looks up resources by a fuzzy description, restricted to
+// the caller's tenant scope
+func (s *GraphService) FindResources(ctx context.Context, input resourcelookup.FindInput) (*resourcelookup.FindResponse, error) {
+	// Add tracing span
+	var span trace.Span
+	if s.tracer != nil {
+		ctx, span = s.tracer.Start(ctx, "graph.findResources")
+		defer span.End()
+	}
+
+	s.logger.Debug("GraphService: Finding resources for %q", input.Query)
+
+	if input.Namespace != "" {
+		if err := CheckNamespaceAccess(ctx, input.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	input.AllowedNamespaces = auth.AllowedNamespaces(ctx)
+
+	result, err := s.resourceFinder.Find(ctx, input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		s.logger.Error("GraphService: Failed to find resources: %v", err)
+		return nil, fmt.Errorf("resource lookup failed: %w", err)
+	}
+
+	s.logger.Debug("GraphService: Resource lookup returned %d of %d matches", result.Count, result.TotalMatches)
+	return result, nil
+}
+
+// QueryGraph runs a read-only Cypher query or a saved query. Callers
+// restricted to namespaces must scope the nodes of their queries with
+// $allowedNamespaces.
+func (s *GraphService) QueryGraph(ctx context.Context, input graphquery.QueryInput) (*graphquery.QueryResponse, error) {
+	// Add tracing span
+	var span trace.Span
+	if s.tracer != nil {
+		ctx, span = s.tracer.Start(ctx, "graph.queryGraph")
+		defer span.End()
+	}
+
+	s.logger.Debug("GraphService: Running graph query (saved query: %q)", input.SavedQuery)
+
+	input.AllowedNamespaces = auth.AllowedNamespaces(ctx)
+
+	result, err := s.queryExecutor.Execute(ctx, input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		if errors.Is(err, graphquery.ErrInvalidQuery) {
+			return nil, err
+		}
+		s.logger.Error("GraphService: Failed to run graph query: %v", err)
+		return nil, fmt.Errorf("graph query failed: %w", err)
+	}
+
+	s.logger.Debug("GraphService: Graph query returned %d rows", result.Count)
+	return result, nil
+}
+
+// Snapshot reconstructs the resources alive at a point in time
+func (s *GraphService) Snapshot(ctx context.Context, input snapshot.SnapshotInput) (*snapshot.SnapshotResponse, error) {
+	// Add tracing span
+	var span trace.Span
+	if s.tracer != nil {
+		ctx, span = s.tracer.Start(ctx, "graph.snapshot")
+		defer span.End()
+	}
+
+	s.logger.Debug("GraphService: Reconstructing snapshot of namespace %q at %d", input.Namespace, input.At)
+
+	if input.Namespace != "" {
+		if err := CheckNamespaceAccess(ctx, input.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	input.AllowedNamespaces = auth.AllowedNamespaces(ctx)
+
+	result, err := s.snapshotReconstructor.Snapshot(ctx, input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		s.logger.Error("GraphService: Failed to reconstruct snapshot: %v", err)
+		return nil, fmt.Errorf("snapshot failed: %w", err)
+	}
+
+	s.logger.Debug("GraphService: Snapshot returned %d resources (truncated: %v)", result.Count, result.Truncated)
 	return result, nil
 }
+
+// ErrResourceNotFound is returned when a resource is not in the graph
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ResourceState is the latest state of a resource recorded in the graph
+type ResourceState struct {
+	Resource graph.ResourceIdentity `json:"resource"`
+
+	// Status, message and data of the most recent change, absent when no
+	// change of the resource was recorded
+	Status       string          `json:"status,omitempty"`
+	Message      string          `json:"message,omitempty"`
+	LastChangeAt int64           `json:"lastChangeAt,omitempty"` // Unix nanoseconds
+	EventType    string          `json:"eventType,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+}
+
+// CheckResourceAccess returns ErrNamespaceForbidden if the resource lies
+// outside of the caller's tenant scope
+func (s *GraphService) CheckResourceAccess(ctx context.Context, resourceUID string) error {
+	return CheckResourceAccess(ctx, s.graphClient, resourceUID)
+}
+
+// GetResourceState returns the identity of a resource together with its most
+// recent change
+func (s *GraphService) GetResourceState(ctx context.Context, resourceUID string) (*ResourceState, error) {
+	// Add tracing span
+	var span trace.Span
+	if s.tracer != nil {
+		ctx, span = s.tracer.Start(ctx, "graph.getResourceState")
+		defer span.End()
+	}
+
+	if err := CheckResourceAccess(ctx, s.graphClient, resourceUID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.graphClient.ExecuteQuery(ctx, graph.FindResourceByUIDQuery(resourceUID))
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		return nil, fmt.Errorf("failed to query resource: %w", err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, resourceUID)
+	}
+	props, err := graph.ParseNodeFromResult(result.Rows[0][0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource: %w", err)
+	}
+	state := &ResourceState{Resource: graph.ParseResourceIdentityFromNode(props)}
+
+	result, err = s.graphClient.ExecuteQuery(ctx, graph.FindLatestChangeEventQuery(resourceUID))
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		return nil, fmt.Errorf("failed to query latest change: %w", err)
+	}
+	if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
+		props, err := graph.ParseNodeFromResult(result.Rows[0][0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse change event: %w", err)
+		}
+		event := graph.ParseChangeEventFromNode(props)
+		state.Status = event.Status
+		state.Message = event.ErrorMessage
+		state.LastChangeAt = event.Timestamp
+		state.EventType = event.EventType
+		if event.Data != "" {
+			state.Data = json.RawMessage(event.Data)
+		}
+	}
+
+	// Tenants only see the status of cluster-scoped resources
+	if newNamespaceScope(ctx).reduces(state.Resource.Namespace) {
+		state.Message = ""
+		state.Data = models.ReduceResourceData(state.Data)
+	}
+	return state, nil
+}
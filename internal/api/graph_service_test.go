@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGraphService(t *testing.T) *GraphService {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	_, err := client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			CREATE (d:ResourceIdentity {uid: 'deploy', kind: 'Deployment', namespace: 'shop', name: 'api'})
+			CREATE (n:ResourceIdentity {uid: 'node', kind: 'Node', namespace: '', name: 'worker-1'})
+			CREATE (d)-[:CHANGED]->(:ChangeEvent {id: 'ce-1', timestamp: 100, eventType: 'CREATE', status: 'Ready', data: '{"spec":{"replicas":1}}'})
+			CREATE (d)-[:CHANGED]->(:ChangeEvent {id: 'ce-2', timestamp: 200, eventType: 'UPDATE', status: 'Error', errorMessage: 'ProgressDeadlineExceeded', data: '{"spec":{"replicas":3}}'})
+			CREATE (n)-[:CHANGED]->(:ChangeEvent {id: 'ce-3', timestamp: 150, eventType: 'UPDATE', status: 'Warning', errorMessage: 'DiskPressure', data: '{"metadata":{"name":"worker-1","labels":{"pool":"a"}},"spec":{"unschedulable":true},"status":{"phase":"Running"}}'})
+		`,
+	})
+	require.NoError(t, err)
+
+	return NewGraphService(client, logging.GetLogger("test"), nil)
+}
+
+func TestGraphService_GetResourceState(t *testing.T) {
+	service := newTestGraphService(t)
+
+	state, err := service.GetResourceState(context.Background(), "deploy")
+	require.NoError(t, err)
+	assert.Equal(t, "api", state.Resource.Name)
+	assert.Equal(t, "Error", state.Status)
+	assert.Equal(t, "ProgressDeadlineExceeded", state.Message)
+	assert.Equal(t, int64(200), state.LastChangeAt)
+	assert.JSONEq(t, `{"spec":{"replicas":3}}`, string(state.Data))
+
+	_, err = service.GetResourceState(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestGraphService_GetResourceStateScoped(t *testing.T) {
+	service := newTestGraphService(t)
+
+	_, err := service.GetResourceState(tenantContext("payments"), "deploy")
+	assert.ErrorIs(t, err, ErrNamespaceForbidden)
+
+	// Tenants only see the status of cluster-scoped resources
+	state, err := service.GetResourceState(tenantContext("shop"), "node")
+	require.NoError(t, err)
+	assert.Equal(t, "Warning", state.Status)
+	assert.Empty(t, state.Message)
+	assert.NotContains(t, string(state.Data), "unschedulable")
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -77,6 +78,10 @@ func (h *AnomalyHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		if span != nil {
 			span.RecordError(err)
 		}
+		if errors.Is(err, api.ErrNamespaceForbidden) {
+			h.respondWithError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
 		h.logger.Error("Anomaly detection failed: %v", err)
 		h.respondWithError(w, http.StatusInternalServerError, "DETECTION_FAILED", err.Error())
 		return
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	blastradius "github.com/moolen/spectre/internal/analysis/blast_radius"
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BlastRadiusHandler handles /v1/blast-radius requests
+type BlastRadiusHandler struct {
+	graphService *api.GraphService
+	logger       *logging.Logger
+	tracer       trace.Tracer
+}
+
+// NewBlastRadiusHandler creates a new handler
+func NewBlastRadiusHandler(graphService *api.GraphService, logger *logging.Logger, tracer trace.Tracer) *BlastRadiusHandler {
+	return &BlastRadiusHandler{
+		graphService: graphService,
+		logger:       logger,
+		tracer:       tracer,
+	}
+}
+
+// Handle processes blast radius requests
+func (h *BlastRadiusHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Create tracing span
+	var span trace.Span
+	if h.tracer != nil {
+		ctx, span = h.tracer.Start(ctx, "blast_radius.Handle")
+		defer span.End()
+	}
+
+	input, err := h.parseInput(r)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		h.respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.String("resource_uid", input.ResourceUID),
+			attribute.Int64("change_timestamp", input.ChangeTimestamp),
+			attribute.Int("max_depth", input.MaxDepth),
+		)
+	}
+
+	result, err := h.graphService.AnalyzeBlastRadius(ctx, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrNamespaceForbidden):
+			h.respondWithError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+		case errors.Is(err, blastradius.ErrResourceNotFound):
+			h.respondWithError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		default:
+			if span != nil {
+				span.RecordError(err)
+			}
+			h.logger.Error("Blast radius analysis failed: %v", err)
+			h.respondWithError(w, http.StatusInternalServerError, "ANALYSIS_FAILED", err.Error())
+		}
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.Int("candidates_explored", result.Metadata.CandidatesExplored),
+			attribute.Int("impacted_count", result.Metadata.ImpactedCount),
+			attribute.Int64("query_execution_ms", result.Metadata.QueryExecutionMs),
+		)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, result)
+}
+
+// parseInput extracts and validates query parameters
+func (h *BlastRadiusHandler) parseInput(r *http.Request) (blastradius.BlastRadiusInput, error) {
+	query := r.URL.Query()
+
+	// Required: resourceUID
+	resourceUID := query.Get("resourceUID")
+	if resourceUID == "" {
+		return blastradius.BlastRadiusInput{}, api.NewValidationError("resourceUID is required")
+	}
+
+	// Required: changeTimestamp
+	changeTimestampStr := query.Get("changeTimestamp")
+	if changeTimestampStr == "" {
+		return blastradius.BlastRadiusInput{}, api.NewValidationError("changeTimestamp is required")
+	}
+	changeTimestamp, err := strconv.ParseInt(changeTimestampStr, 10, 64)
+	if err != nil || changeTimestamp <= 0 {
+		return blastradius.BlastRadiusInput{}, api.NewValidationError("invalid changeTimestamp format")
+	}
+
+	input := blastradius.BlastRadiusInput{
+		ResourceUID:     resourceUID,
+		ChangeTimestamp: normalizeToNanoseconds(changeTimestamp),
+		WindowNs:        blastradius.DefaultWindowNs,
+		MaxDepth:        blastradius.DefaultMaxDepth,
+		Limit:           blastradius.DefaultLimit,
+	}
+
+	// Optional: window (default 15m, at most 6h)
+	if v := query.Get("window"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil || window <= 0 || window.Nanoseconds() > blastradius.MaxWindowNs {
+			return blastradius.BlastRadiusInput{}, api.NewValidationError(
+				"window must be a positive duration of at most %s", time.Duration(blastradius.MaxWindowNs))
+		}
+		input.WindowNs = window.Nanoseconds()
+	}
+
+	// Optional: maxDepth (default 3, range 1-3)
+	if v := query.Get("maxDepth"); v != "" {
+		maxDepth, err := strconv.Atoi(v)
+		if err != nil || maxDepth < blastradius.MinMaxDepth || maxDepth > blastradius.MaxMaxDepth {
+			return blastradius.BlastRadiusInput{}, api.NewValidationError(
+				"maxDepth must be between %d and %d", blastradius.MinMaxDepth, blastradius.MaxMaxDepth)
+		}
+		input.MaxDepth = maxDepth
+	}
+
+	// Optional: limit (default 50, at most 500)
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > blastradius.MaxLimit {
+			return blastradius.BlastRadiusInput{}, api.NewValidationError(
+				"limit must be between 1 and %d", blastradius.MaxLimit)
+		}
+		input.Limit = limit
+	}
+
+	return input, nil
+}
+
+// respondWithError writes an error response
+func (h *BlastRadiusHandler) respondWithError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = api.WriteJSON(w, map[string]interface{}{
+		"error": map[string]string{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
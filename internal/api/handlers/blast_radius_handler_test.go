@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	blastradius "github.com/moolen/spectre/internal/analysis/blast_radius"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBlastRadiusHandler(t *testing.T) *BlastRadiusHandler {
+	t.Helper()
+
+	// The ConfigMap is edited at 1000s. The Pod referencing it and the Pod
+	// of the Deployment referencing it start failing afterwards.
+	graphService := newTestGraphService(t, `
+		CREATE (cm:ResourceIdentity {uid: 'cm', kind: 'ConfigMap', namespace: 'shop', name: 'settings'})
+		CREATE (podA:ResourceIdentity {uid: 'pod-a', kind: 'Pod', namespace: 'shop', name: 'worker'})
+		CREATE (deploy:ResourceIdentity {uid: 'deploy', kind: 'Deployment', namespace: 'shop', name: 'api'})
+		CREATE (podB:ResourceIdentity {uid: 'pod-b', kind: 'Pod', namespace: 'shop', name: 'api-1'})
+		CREATE (podA)-[:REFERENCES_SPEC]->(cm)
+		CREATE (deploy)-[:REFERENCES_SPEC]->(cm)
+		CREATE (deploy)-[:OWNS]->(podB)
+		CREATE (cm)-[:CHANGED]->(:ChangeEvent {id: 'ce-cm', timestamp: 1000000000000, eventType: 'UPDATE', configChanged: true})
+		CREATE (podA)-[:CHANGED]->(:ChangeEvent {id: 'ce-a', timestamp: 1060000000000, eventType: 'UPDATE', statusChanged: true})
+		CREATE (podA)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-a', timestamp: 1090000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off restarting failed container', count: 3})
+		CREATE (deploy)-[:CHANGED]->(:ChangeEvent {id: 'ce-d', timestamp: 1030000000000, eventType: 'UPDATE', configChanged: true})
+		CREATE (podB)-[:CHANGED]->(:ChangeEvent {id: 'ce-b', timestamp: 1120000000000, eventType: 'UPDATE', statusChanged: true})
+		CREATE (podB)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-b', timestamp: 1150000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off restarting failed container', count: 2})
+	`)
+	return NewBlastRadiusHandler(graphService, logging.GetLogger("test"), nil)
+}
+
+func TestBlastRadiusHandler(t *testing.T) {
+	handler := newTestBlastRadiusHandler(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		namespaces []string
+		wantStatus int
+		wantUIDs   []string
+	}{
+		{name: "impacted resources", query: "?resourceUID=cm&changeTimestamp=1000", wantStatus: http.StatusOK, wantUIDs: []string{"pod-a", "pod-b"}},
+		{name: "maxDepth", query: "?resourceUID=cm&changeTimestamp=1000&maxDepth=1", wantStatus: http.StatusOK, wantUIDs: []string{"pod-a"}},
+		{name: "window", query: "?resourceUID=cm&changeTimestamp=1000&window=100s", wantStatus: http.StatusOK, wantUIDs: []string{"pod-a"}},
+		{name: "tenant scope", query: "?resourceUID=cm&changeTimestamp=1000", namespaces: []string{"shop"}, wantStatus: http.StatusOK, wantUIDs: []string{"pod-a", "pod-b"}},
+		{name: "resource outside the tenant", query: "?resourceUID=cm&changeTimestamp=1000", namespaces: []string{"payments"}, wantStatus: http.StatusForbidden},
+		{name: "unknown resource", query: "?resourceUID=missing&changeTimestamp=1000", wantStatus: http.StatusNotFound},
+		{name: "missing changeTimestamp", query: "?resourceUID=cm", wantStatus: http.StatusBadRequest},
+		{name: "maxDepth out of range", query: "?resourceUID=cm&changeTimestamp=1000&maxDepth=5", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/blast-radius"+tt.query, http.NoBody)
+			rec := serveRequest(handler.Handle, req, tt.namespaces...)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result blastradius.BlastRadiusResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			assert.Equal(t, "settings", result.Trigger.Name)
+			uids := make([]string, len(result.Impacted))
+			for i, impacted := range result.Impacted {
+				uids[i] = impacted.Resource.UID
+			}
+			assert.Equal(t, tt.wantUIDs, uids)
+			assert.Equal(t, len(result.Impacted), result.Metadata.ImpactedCount)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -16,19 +17,21 @@ import (
 
 // CausalGraphHandler handles /v1/causal-graph requests
 type CausalGraphHandler struct {
-	analyzer  *analysis.RootCauseAnalyzer
-	logger    *logging.Logger
-	validator *api.Validator
-	tracer    trace.Tracer
+	analyzer    *analysis.RootCauseAnalyzer
+	graphClient graph.Client
+	logger      *logging.Logger
+	validator   *api.Validator
+	tracer      trace.Tracer
 }
 
 // NewCausalGraphHandler creates a new handler
 func NewCausalGraphHandler(graphClient graph.Client, logger *logging.Logger, tracer trace.Tracer) *CausalGraphHandler {
 	return &CausalGraphHandler{
-		analyzer:  analysis.NewRootCauseAnalyzer(graphClient),
-		logger:    logger,
-		validator: api.NewValidator(),
-		tracer:    tracer,
+		analyzer:    analysis.NewRootCauseAnalyzer(graphClient),
+		graphClient: graphClient,
+		logger:      logger,
+		validator:   api.NewValidator(),
+		tracer:      tracer,
 	}
 }
 
@@ -73,6 +76,15 @@ func (h *CausalGraphHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 3. Execute analysis
+	if err := api.CheckResourceAccess(ctx, h.graphClient, input.ResourceUID); err != nil {
+		if errors.Is(err, api.ErrNamespaceForbidden) {
+			h.respondWithError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+		} else {
+			h.respondWithError(w, http.StatusInternalServerError, "ANALYSIS_FAILED", err.Error())
+		}
+		return
+	}
+
 	result, err := h.analyzer.Analyze(ctx, input)
 	if err != nil {
 		if span != nil {
@@ -86,7 +98,7 @@ func (h *CausalGraphHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	// 4. Return JSON response
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_ = api.WriteJSON(w, result)
+	_ = api.WriteJSON(w, api.ScopeRootCauseAnalysis(ctx, result))
 }
 
 // parseInput extracts and normalizes query parameters
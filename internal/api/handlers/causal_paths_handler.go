@@ -98,6 +98,9 @@ func (h *CausalPathsHandler) Handle(w http.ResponseWriter, r *http.Request) {
 				)
 			}
 			h.logger.Debug("No data in requested time range: %v", err)
+		} else if errors.Is(err, api.ErrNamespaceForbidden) {
+			h.respondWithError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
 		} else {
 			if span != nil {
 				span.RecordError(err)
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+// CausalityHeuristicsHandler handles /v1/causality/heuristics requests
+type CausalityHeuristicsHandler struct {
+	engine sync.CausalityEngine
+	logger *logging.Logger
+}
+
+// NewCausalityHeuristicsHandler creates a new causality heuristics handler
+func NewCausalityHeuristicsHandler(engine sync.CausalityEngine, logger *logging.Logger) *CausalityHeuristicsHandler {
+	return &CausalityHeuristicsHandler{
+		engine: engine,
+		logger: logger,
+	}
+}
+
+// causalityHeuristicsResponse lists the active heuristics in the order they
+// are tried
+type causalityHeuristicsResponse struct {
+	Heuristics []sync.HeuristicStats `json:"heuristics"`
+}
+
+// Handle returns the active causality heuristics and how many TRIGGERED_BY
+// links each one inferred
+func (h *CausalityHeuristicsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, causalityHeuristicsResponse{Heuristics: h.engine.GetHeuristicStats()})
+}
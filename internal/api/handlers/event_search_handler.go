@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	eventsearch "github.com/moolen/spectre/internal/analysis/event_search"
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/graph/fulltext"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventSearchHandler handles /v1/events/search requests
+type EventSearchHandler struct {
+	graphService *api.GraphService
+	logger       *logging.Logger
+	tracer       trace.Tracer
+}
+
+// NewEventSearchHandler creates a new handler
+func NewEventSearchHandler(graphService *api.GraphService, logger *logging.Logger, tracer trace.Tracer) *EventSearchHandler {
+	return &EventSearchHandler{
+		graphService: graphService,
+		logger:       logger,
+		tracer:       tracer,
+	}
+}
+
+// Handle processes event search requests
+func (h *EventSearchHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var span trace.Span
+	if h.tracer != nil {
+		ctx, span = h.tracer.Start(ctx, "event_search.Handle")
+		defer span.End()
+	}
+
+	// 1. Parse and validate query parameters
+	input, err := h.parseInput(r)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		api.WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.String("query", input.Query),
+			attribute.Int64("start", input.Start),
+			attribute.Int64("end", input.End),
+			attribute.StringSlice("sources", input.Sources),
+			attribute.Int("limit", input.Limit),
+		)
+	}
+
+	h.logger.Debug("Processing event search: query=%q, time range: %d to %d", input.Query, input.Start, input.End)
+
+	// 2. Execute the search via GraphService
+	result, err := h.graphService.SearchEvents(ctx, input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		if errors.Is(err, api.ErrNamespaceForbidden) {
+			api.WriteError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		h.logger.Error("Event search failed: %v", err)
+		api.WriteError(w, http.StatusInternalServerError, "SEARCH_FAILED", err.Error())
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("hits_returned", result.Count))
+	}
+
+	// 3. Return JSON response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, result)
+}
+
+func (h *EventSearchHandler) parseInput(r *http.Request) (eventsearch.SearchInput, error) {
+	query := r.URL.Query()
+
+	// Required: q
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		return eventsearch.SearchInput{}, api.NewValidationError("q is required")
+	}
+	if _, err := fulltext.Parse(q); err != nil {
+		return eventsearch.SearchInput{}, api.NewValidationError("invalid q: %v", err)
+	}
+
+	// Required: start and end
+	start, err := api.ParseTimestamp(query.Get("start"), "start")
+	if err != nil {
+		return eventsearch.SearchInput{}, err
+	}
+	end, err := api.ParseTimestamp(query.Get("end"), "end")
+	if err != nil {
+		return eventsearch.SearchInput{}, err
+	}
+	if end <= start {
+		return eventsearch.SearchInput{}, api.NewValidationError("end must be greater than start")
+	}
+
+	// Optional: source (k8s, change or all, default all)
+	var sources []string
+	switch source := query.Get("source"); source {
+	case "", "all":
+	case eventsearch.SourceK8sEvents, eventsearch.SourceChangeEvents:
+		sources = []string{source}
+	default:
+		return eventsearch.SearchInput{}, api.NewValidationError("source must be one of k8s, change or all")
+	}
+
+	clusters := api.ParseClusterParams(query)
+	for _, cluster := range clusters {
+		if err := models.ValidateClusterName(cluster); err != nil {
+			return eventsearch.SearchInput{}, api.NewValidationError("invalid cluster filter: %v", err)
+		}
+	}
+
+	// Optional: limit (default 50, max 500)
+	limit := eventsearch.DefaultLimit
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > eventsearch.MaxLimit {
+			return eventsearch.SearchInput{}, api.NewValidationError("limit must be between 1 and %d", eventsearch.MaxLimit)
+		}
+		limit = parsed
+	}
+
+	return eventsearch.SearchInput{
+		Query:      q,
+		Start:      start,
+		End:        end,
+		Sources:    sources,
+		Namespaces: api.ParseNamespaceParams(query),
+		Clusters:   clusters,
+		Limit:      limit,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	eventsearch "github.com/moolen/spectre/internal/analysis/event_search"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEventSearchHandler(t *testing.T) *EventSearchHandler {
+	t.Helper()
+
+	graphService := newTestGraphService(t, `
+		CREATE (pod:ResourceIdentity {uid: 'pod', kind: 'Pod', namespace: 'payments', name: 'api-7f9c', cluster: 'prod'})
+		CREATE (web:ResourceIdentity {uid: 'web', kind: 'Pod', namespace: 'shop', name: 'web-1', cluster: 'prod'})
+		CREATE (pod)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-1', timestamp: 2000000000, reason: 'BackOff', type: 'Warning', message: 'Back-off restarting failed container'})
+		CREATE (web)-[:EMITTED_EVENT]->(:K8sEvent {id: 'ev-2', timestamp: 3000000000, reason: 'Failed', type: 'Warning', message: 'Failed to pull image "web:v2": x509: certificate signed by unknown authority'})
+		CREATE (web)-[:CHANGED]->(:ChangeEvent {id: 'ce-1', timestamp: 3000000000, eventType: 'UPDATE', status: 'Error', errorMessage: 'ImagePullBackOff: x509 certificate error'})
+	`)
+	return NewEventSearchHandler(graphService, logging.GetLogger("test"), nil)
+}
+
+func TestEventSearchHandler(t *testing.T) {
+	handler := newTestEventSearchHandler(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		namespaces []string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{name: "k8s and change events", query: "?q=x509&start=1&end=10", wantStatus: http.StatusOK, wantIDs: []string{"ev-2", "ce-1"}},
+		{name: "source", query: "?q=x509&start=1&end=10&source=change", wantStatus: http.StatusOK, wantIDs: []string{"ce-1"}},
+		{name: "time range", query: "?q=restarting&start=3&end=10", wantStatus: http.StatusOK, wantIDs: []string{}},
+		{name: "namespace", query: "?q=failed&start=1&end=10&namespace=payments", wantStatus: http.StatusOK, wantIDs: []string{"ev-1"}},
+		{name: "limit", query: "?q=failed&start=1&end=10&limit=1", wantStatus: http.StatusOK, wantIDs: []string{"ev-1"}},
+		{name: "tenant scope", query: "?q=failed&start=1&end=10", namespaces: []string{"payments"}, wantStatus: http.StatusOK, wantIDs: []string{"ev-1"}},
+		{name: "namespace outside the tenant", query: "?q=x509&start=1&end=10&namespace=shop", namespaces: []string{"payments"}, wantStatus: http.StatusForbidden},
+		{name: "invalid q", query: "?q=%22unterminated&start=1&end=10", wantStatus: http.StatusBadRequest},
+		{name: "end before start", query: "?q=x509&start=10&end=1", wantStatus: http.StatusBadRequest},
+		{name: "unknown source", query: "?q=x509&start=1&end=10&source=logs", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/events/search"+tt.query, http.NoBody)
+			rec := serveRequest(handler.Handle, req, tt.namespaces...)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result eventsearch.SearchResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			ids := make([]string, len(result.Hits))
+			for i, hit := range result.Hits {
+				ids[i] = hit.ID
+			}
+			assert.ElementsMatch(t, tt.wantIDs, ids)
+			assert.Equal(t, len(result.Hits), result.Count)
+		})
+	}
+}
@@ -77,6 +77,16 @@ func (h *ExportHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		EndTimestamp:   to,
 		Filters:        models.QueryFilters{}, // Empty filters = all events
 	}
+	// A cluster_id restricts the export to the events of a single cluster
+	if clusterID != "" {
+		if err := models.ValidateClusterName(clusterID); err != nil {
+			api.WriteError(w, http.StatusBadRequest, "INVALID_PARAMETER", fmt.Sprintf("Invalid 'cluster_id' parameter: %v", err))
+			return
+		}
+		query.Filters.Clusters = []string{clusterID}
+	}
+	// Tenants only export the namespaces they may access
+	api.ApplyTenantScope(r.Context(), &query.Filters)
 
 	// Validate query request
 	if err := query.Validate(); err != nil {
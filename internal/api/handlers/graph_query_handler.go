@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	graphquery "github.com/moolen/spectre/internal/analysis/graph_query"
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxGraphQueryRequestSize bounds the request body of a graph query
+const maxGraphQueryRequestSize = 64 * 1024
+
+// GraphQueryHandler handles /v1/graph-query requests
+type GraphQueryHandler struct {
+	graphService *api.GraphService
+	logger       *logging.Logger
+	tracer       trace.Tracer
+}
+
+// NewGraphQueryHandler creates a new handler
+func NewGraphQueryHandler(graphService *api.GraphService, logger *logging.Logger, tracer trace.Tracer) *GraphQueryHandler {
+	return &GraphQueryHandler{
+		graphService: graphService,
+		logger:       logger,
+		tracer:       tracer,
+	}
+}
+
+// graphQueryRequest is the request body of POST /v1/graph-query
+type graphQueryRequest struct {
+	Query          string                 `json:"query"`
+	SavedQuery     string                 `json:"savedQuery"`
+	Parameters     map[string]interface{} `json:"parameters"`
+	Limit          int                    `json:"limit"`
+	TimeoutSeconds int                    `json:"timeoutSeconds"`
+}
+
+// savedQueriesResponse lists the saved queries
+type savedQueriesResponse struct {
+	Queries []graphquery.SavedQuery `json:"queries"`
+}
+
+// Handle processes graph query requests
+func (h *GraphQueryHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var span trace.Span
+	if h.tracer != nil {
+		ctx, span = h.tracer.Start(ctx, "graph_query.Handle")
+		defer span.End()
+	}
+
+	// 1. Parse and validate the request body
+	input, err := h.parseInput(r)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		api.WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.String("saved_query", input.SavedQuery),
+			attribute.Int("limit", input.Limit),
+		)
+	}
+
+	// 2. Run the query via GraphService
+	result, err := h.graphService.QueryGraph(ctx, input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		if errors.Is(err, graphquery.ErrInvalidQuery) {
+			api.WriteError(w, http.StatusBadRequest, "INVALID_QUERY", err.Error())
+			return
+		}
+		h.logger.Error("Graph query failed: %v", err)
+		api.WriteError(w, http.StatusInternalServerError, "QUERY_FAILED", err.Error())
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("rows_returned", result.Count))
+	}
+
+	// 3. Return JSON response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, result)
+}
+
+// HandleSavedQueries lists the saved queries
+func (h *GraphQueryHandler) HandleSavedQueries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, savedQueriesResponse{Queries: graphquery.SavedQueries()})
+}
+
+func (h *GraphQueryHandler) parseInput(r *http.Request) (graphquery.QueryInput, error) {
+	var req graphQueryRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxGraphQueryRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return graphquery.QueryInput{}, api.NewValidationError("invalid request body: %v", err)
+	}
+
+	if (req.Query == "") == (req.SavedQuery == "") {
+		return graphquery.QueryInput{}, api.NewValidationError("exactly one of query and savedQuery is required")
+	}
+	if req.Limit < 0 || req.Limit > graphquery.MaxRowLimit {
+		return graphquery.QueryInput{}, api.NewValidationError("limit must be between 1 and %d", graphquery.MaxRowLimit)
+	}
+	if req.TimeoutSeconds < 0 || req.TimeoutSeconds > graphquery.MaxTimeoutSeconds {
+		return graphquery.QueryInput{}, api.NewValidationError("timeoutSeconds must be between 1 and %d", graphquery.MaxTimeoutSeconds)
+	}
+
+	return graphquery.QueryInput{
+		Query:          req.Query,
+		SavedQuery:     req.SavedQuery,
+		Parameters:     req.Parameters,
+		Limit:          req.Limit,
+		TimeoutSeconds: req.TimeoutSeconds,
+	}, nil
+}
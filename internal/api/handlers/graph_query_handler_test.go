@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	graphquery "github.com/moolen/spectre/internal/analysis/graph_query"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGraphQueryHandler(t *testing.T) *GraphQueryHandler {
+	t.Helper()
+
+	graphService := newTestGraphService(t, `
+		CREATE (pvc:ResourceIdentity {uid: 'pvc', kind: 'PersistentVolumeClaim', namespace: 'shop', name: 'data', deleted: false, lastSeen: 100})
+		CREATE (p1:ResourceIdentity {uid: 'pod-1', kind: 'Pod', namespace: 'shop', name: 'db-0', deleted: true, deletedAt: 50, lastSeen: 50})
+		CREATE (p2:ResourceIdentity {uid: 'pod-2', kind: 'Pod', namespace: 'shop', name: 'db-0', deleted: false, lastSeen: 100})
+		CREATE (cm1:ResourceIdentity {uid: 'cm-1', kind: 'ConfigMap', namespace: 'shop', name: 'settings', deleted: false})
+		CREATE (cm2:ResourceIdentity {uid: 'cm-2', kind: 'ConfigMap', namespace: 'payments', name: 'settings', deleted: false})
+		CREATE (p1)-[:MOUNTS]->(pvc)
+		CREATE (p2)-[:MOUNTS]->(pvc)
+	`)
+	return NewGraphQueryHandler(graphService, logging.GetLogger("test"), nil)
+}
+
+func TestGraphQueryHandler(t *testing.T) {
+	handler := newTestGraphQueryHandler(t)
+
+	tests := []struct {
+		name          string
+		body          string
+		namespaces    []string
+		wantStatus    int
+		wantRows      [][]interface{}
+		wantTruncated bool
+	}{
+		{
+			name:       "query with parameters",
+			body:       `{"query":"MATCH (r:ResourceIdentity {kind: $kind}) RETURN r.uid ORDER BY r.uid","parameters":{"kind":"ConfigMap"}}`,
+			wantStatus: http.StatusOK,
+			wantRows:   [][]interface{}{{"cm-1"}, {"cm-2"}},
+		},
+		{
+			name:          "limit",
+			body:          `{"query":"MATCH (r:ResourceIdentity {kind: 'ConfigMap'}) RETURN r.uid ORDER BY r.uid","limit":1}`,
+			wantStatus:    http.StatusOK,
+			wantRows:      [][]interface{}{{"cm-1"}},
+			wantTruncated: true,
+		},
+		{
+			name:       "saved query",
+			body:       `{"savedQuery":"pods_mounting_pvc","parameters":{"namespace":"shop","name":"data"}}`,
+			wantStatus: http.StatusOK,
+			wantRows:   [][]interface{}{{"pod-2", "db-0", false, nil, float64(100)}, {"pod-1", "db-0", true, nil, float64(50)}},
+		},
+		{
+			name:       "tenant scope",
+			body:       `{"query":"MATCH (r:ResourceIdentity {kind: 'ConfigMap'}) WHERE r.namespace IN $allowedNamespaces RETURN r.uid"}`,
+			namespaces: []string{"shop"},
+			wantStatus: http.StatusOK,
+			wantRows:   [][]interface{}{{"cm-1"}},
+		},
+		{
+			name:       "unscoped query of a tenant",
+			body:       `{"query":"MATCH (r:ResourceIdentity) RETURN r.uid"}`,
+			namespaces: []string{"shop"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{name: "write query", body: `{"query":"MATCH (r) DETACH DELETE r"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown saved query", body: `{"savedQuery":"nope"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", body: `{"query":`, wantStatus: http.StatusBadRequest},
+		{name: "query and saved query", body: `{"query":"MATCH (n) RETURN n","savedQuery":"recently_deleted"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/graph-query", strings.NewReader(tt.body))
+			rec := serveRequest(handler.Handle, req, tt.namespaces...)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result graphquery.QueryResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			assert.Equal(t, tt.wantRows, result.Rows)
+			assert.Equal(t, len(tt.wantRows), result.Count)
+			assert.Equal(t, tt.wantTruncated, result.Truncated)
+		})
+	}
+
+	// Deleted resources are still in the graph
+	var result graphquery.QueryResponse
+	req := httptest.NewRequest(http.MethodPost, "/v1/graph-query", strings.NewReader(`{"savedQuery":"recently_deleted"}`))
+	rec := serveRequest(handler.Handle, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, "recently_deleted", result.SavedQuery)
+	assert.Equal(t, []string{"uid", "kind", "namespace", "name", "deletedAt"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{"pod-1", "Pod", "shop", "db-0", float64(50)}}, result.Rows)
+}
+
+func TestGraphQueryHandlerSavedQueries(t *testing.T) {
+	handler := newTestGraphQueryHandler(t)
+
+	rec := serveRequest(handler.HandleSavedQueries, httptest.NewRequest(http.MethodGet, "/v1/graph-query/saved", http.NoBody))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var result savedQueriesResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	names := make([]string, len(result.Queries))
+	for i, query := range result.Queries {
+		names[i] = query.Name
+	}
+	assert.Contains(t, names, "pods_mounting_pvc")
+	assert.Contains(t, names, "recently_deleted")
+}
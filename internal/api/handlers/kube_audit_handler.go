@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/kubeaudit"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// KubeAuditSink correlates Kubernetes audit events with recorded changes
+type KubeAuditSink interface {
+	Cluster() string
+	Ingest(cluster string, events []kubeaudit.Event) int
+}
+
+// KubeAuditHandler handles /v1/kube-audit requests, the target of the API
+// server's audit webhook backend
+type KubeAuditHandler struct {
+	sink   KubeAuditSink
+	logger *logging.Logger
+}
+
+// NewKubeAuditHandler creates a new Kubernetes audit webhook handler
+func NewKubeAuditHandler(sink KubeAuditSink, logger *logging.Logger) *KubeAuditHandler {
+	return &KubeAuditHandler{
+		sink:   sink,
+		logger: logger,
+	}
+}
+
+// kubeAuditResponse reports how many of the posted events record a change
+type kubeAuditResponse struct {
+	Received int `json:"received"`
+	Accepted int `json:"accepted"`
+}
+
+// Handle accepts an audit.k8s.io/v1 EventList. The cluster parameter names
+// the cluster the events come from and defaults to the server's cluster.
+func (h *KubeAuditHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			h.logger.Error("Failed to close request body: %v", err)
+		}
+	}()
+
+	cluster := h.sink.Cluster()
+	if value := r.URL.Query().Get("cluster"); value != "" {
+		if err := models.ValidateClusterName(value); err != nil {
+			api.WriteError(w, http.StatusBadRequest, "INVALID_PARAMETER", fmt.Sprintf("Invalid 'cluster' parameter: %v", err))
+			return
+		}
+		cluster = value
+	}
+
+	var list kubeaudit.EventList
+	if err := json.NewDecoder(io.LimitReader(r.Body, MaxPayloadSize)).Decode(&list); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("Invalid audit event list: %v", err))
+		return
+	}
+
+	accepted := h.sink.Ingest(cluster, list.Items)
+	h.logger.Debug("Received %d audit events for cluster %q, %d record a change", len(list.Items), cluster, accepted)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, kubeAuditResponse{Received: len(list.Items), Accepted: accepted})
+}
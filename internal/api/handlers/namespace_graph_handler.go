@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 	namespacegraph "github.com/moolen/spectre/internal/analysis/namespace_graph"
 	"github.com/moolen/spectre/internal/api"
 	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -103,13 +105,23 @@ func (h *NamespaceGraphHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	// 3. Execute analysis via GraphService (use cache if available)
 	var result *namespacegraph.NamespaceGraphResponse
 
-	if h.cache != nil {
-		result, err = h.cache.Analyze(ctx, input)
+	// The cache holds one snapshot per namespace across all clusters, so
+	// cluster-filtered requests are always computed directly
+	if h.cache != nil && len(input.Clusters) == 0 {
+		// The cache is shared between tenants, so scope its responses per caller
+		if err = api.CheckNamespaceAccess(ctx, input.Namespace); err == nil {
+			result, err = h.cache.Analyze(ctx, input)
+			result = api.ScopeNamespaceGraphResponse(ctx, result)
+		}
 	} else {
 		result, err = h.graphService.AnalyzeNamespaceGraph(ctx, input)
 	}
 
 	if err != nil {
+		if errors.Is(err, api.ErrNamespaceForbidden) {
+			h.respondWithError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
 		if span != nil {
 			span.RecordError(err)
 		}
@@ -208,6 +220,7 @@ func (h *NamespaceGraphHandler) parseInput(r *http.Request) (namespacegraph.Anal
 
 	return namespacegraph.AnalyzeInput{
 		Namespace:          namespace,
+		Clusters:           api.ParseClusterParams(query),
 		Timestamp:          timestamp,
 		IncludeAnomalies:   includeAnomalies,
 		IncludeCausalPaths: includeCausalPaths,
@@ -223,6 +236,11 @@ func (h *NamespaceGraphHandler) validateInput(input namespacegraph.AnalyzeInput)
 	if input.Namespace == "" {
 		return api.NewValidationError("namespace cannot be empty")
 	}
+	for _, cluster := range input.Clusters {
+		if err := models.ValidateClusterName(cluster); err != nil {
+			return api.NewValidationError("invalid cluster filter: %v", err)
+		}
+	}
 
 	// Validate namespace length (Kubernetes limit is 63 characters)
 	if len(input.Namespace) > 63 {
@@ -97,6 +97,13 @@ func RegisterHandlers(
 		logger.Info("Registered /v1/causal-paths endpoint")
 	}
 
+	// Register blast radius handler if graph service is available
+	if graphService != nil {
+		blastRadiusHandler := NewBlastRadiusHandler(graphService, logger, tracer)
+		router.HandleFunc("/v1/blast-radius", withMethod(http.MethodGet, blastRadiusHandler.Handle))
+		logger.Info("Registered /v1/blast-radius endpoint")
+	}
+
 	// Register namespace graph handler if graph service is available
 	if graphService != nil {
 		var namespaceGraphHandler *NamespaceGraphHandler
@@ -110,6 +117,43 @@ func RegisterHandlers(
 		router.HandleFunc("/v1/namespace-graph", withMethod(http.MethodGet, namespaceGraphHandler.Handle))
 	}
 
+	// Register event search handler if graph service is available
+	if graphService != nil {
+		eventSearchHandler := NewEventSearchHandler(graphService, logger, tracer)
+		router.HandleFunc("/v1/events/search", withMethod(http.MethodGet, eventSearchHandler.Handle))
+		logger.Info("Registered /v1/events/search endpoint")
+	}
+
+	// Register graph query handler if graph service is available
+	if graphService != nil {
+		graphQueryHandler := NewGraphQueryHandler(graphService, logger, tracer)
+		router.HandleFunc("/v1/graph-query", withMethod(http.MethodPost, graphQueryHandler.Handle))
+		router.HandleFunc("/v1/graph-query/saved", withMethod(http.MethodGet, graphQueryHandler.HandleSavedQueries))
+		logger.Info("Registered /v1/graph-query endpoints")
+	}
+
+	// Register snapshot handler if graph service is available
+	if graphService != nil {
+		snapshotHandler := NewSnapshotHandler(graphService, logger, tracer)
+		router.HandleFunc("/v1/snapshot", withMethod(http.MethodGet, snapshotHandler.Handle))
+		logger.Info("Registered /v1/snapshot endpoint")
+	}
+
+	// Register live timeline stream if the graph pipeline is available
+	if graphPipeline != nil {
+		timelineWatcher := api.NewTimelineWatcher(graphPipeline, logger)
+		timelineStreamHandler := NewTimelineStreamHandler(timelineService, timelineWatcher, logger, tracer)
+		router.HandleFunc("/v1/timeline/stream", withMethod(http.MethodGet, timelineStreamHandler.Handle))
+		logger.Info("Registered /v1/timeline/stream endpoint")
+	}
+
+	// Register causality heuristics listing if the graph pipeline is available
+	if graphPipeline != nil {
+		heuristicsHandler := NewCausalityHeuristicsHandler(graphPipeline.GetCausalityEngine(), logger)
+		router.HandleFunc("/v1/causality/heuristics", withMethod(http.MethodGet, heuristicsHandler.Handle))
+		logger.Info("Registered /v1/causality/heuristics endpoint")
+	}
+
 	// Register import handler if graph pipeline is available
 	if graphPipeline != nil {
 		importHandler := NewImportHandler(graphPipeline, logger)
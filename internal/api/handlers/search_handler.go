@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/moolen/spectre/internal/api"
 	"github.com/moolen/spectre/internal/logging"
@@ -40,6 +41,7 @@ func (sh *SearchHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		"version":   query.Get("version"),
 		"kind":      query.Get("kind"),
 		"namespace": query.Get("namespace"),
+		"clusters":  strings.Join(api.ParseClusterParams(query), ","),
 	}
 
 	// Parse query using SearchService
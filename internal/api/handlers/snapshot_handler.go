@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/moolen/spectre/internal/analysis/snapshot"
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SnapshotHandler handles /v1/snapshot requests
+type SnapshotHandler struct {
+	graphService *api.GraphService
+	logger       *logging.Logger
+	tracer       trace.Tracer
+}
+
+// NewSnapshotHandler creates a new handler
+func NewSnapshotHandler(graphService *api.GraphService, logger *logging.Logger, tracer trace.Tracer) *SnapshotHandler {
+	return &SnapshotHandler{
+		graphService: graphService,
+		logger:       logger,
+		tracer:       tracer,
+	}
+}
+
+// snapshotRequest holds the parsed query parameters of a snapshot request
+type snapshotRequest struct {
+	input         snapshot.SnapshotInput
+	yaml          bool
+	includeStatus bool
+}
+
+// Handle processes snapshot requests
+func (h *SnapshotHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var span trace.Span
+	if h.tracer != nil {
+		ctx, span = h.tracer.Start(ctx, "snapshot.Handle")
+		defer span.End()
+	}
+
+	// 1. Parse and validate query parameters
+	req, err := h.parseInput(r)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		api.WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.String("namespace", req.input.Namespace),
+			attribute.Int64("at", req.input.At),
+			attribute.StringSlice("kinds", req.input.Kinds),
+			attribute.Bool("yaml", req.yaml),
+		)
+	}
+
+	h.logger.Debug("Processing snapshot: namespace=%q, at=%d", req.input.Namespace, req.input.At)
+
+	// 2. Reconstruct the snapshot via GraphService
+	result, err := h.graphService.Snapshot(ctx, req.input)
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		if errors.Is(err, api.ErrNamespaceForbidden) {
+			api.WriteError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		h.logger.Error("Snapshot failed: %v", err)
+		api.WriteError(w, http.StatusInternalServerError, "SNAPSHOT_FAILED", err.Error())
+		return
+	}
+
+	if span != nil {
+		span.SetAttributes(
+			attribute.Int("resources_returned", result.Count),
+			attribute.Bool("truncated", result.Truncated),
+		)
+	}
+
+	// 3. Return the manifests or the JSON response
+	if req.yaml {
+		manifests, err := snapshot.RenderManifests(result, req.includeStatus)
+		if err != nil {
+			h.logger.Error("Failed to render snapshot manifests: %v", err)
+			api.WriteError(w, http.StatusInternalServerError, "SNAPSHOT_FAILED", err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Header().Set("X-Snapshot-Truncated", strconv.FormatBool(result.Truncated))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(manifests)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, result)
+}
+
+func (h *SnapshotHandler) parseInput(r *http.Request) (snapshotRequest, error) {
+	query := r.URL.Query()
+
+	// Required: at
+	at, err := api.ParseTimestamp(query.Get("at"), "at")
+	if err != nil {
+		return snapshotRequest{}, err
+	}
+	if at == 0 {
+		return snapshotRequest{}, api.NewValidationError("at must be greater than 0")
+	}
+
+	cluster := query.Get("cluster")
+	if cluster != "" {
+		if err := models.ValidateClusterName(cluster); err != nil {
+			return snapshotRequest{}, api.NewValidationError("invalid cluster filter: %v", err)
+		}
+	}
+
+	// Optional: limit (default 1000, max 5000)
+	limit := snapshot.DefaultLimit
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > snapshot.MaxLimit {
+			return snapshotRequest{}, api.NewValidationError("limit must be between 1 and %d", snapshot.MaxLimit)
+		}
+		limit = parsed
+	}
+
+	// Optional: format (json or yaml, default json)
+	var asYAML bool
+	switch format := query.Get("format"); format {
+	case "", "json":
+	case "yaml":
+		asYAML = true
+	default:
+		return snapshotRequest{}, api.NewValidationError("format must be json or yaml")
+	}
+
+	// Optional: status, keeps the status in YAML manifests
+	var includeStatus bool
+	if v := query.Get("status"); v != "" {
+		includeStatus, err = strconv.ParseBool(v)
+		if err != nil {
+			return snapshotRequest{}, api.NewValidationError("status must be true or false")
+		}
+	}
+
+	return snapshotRequest{
+		input: snapshot.SnapshotInput{
+			At:        at * int64(time.Second),
+			Namespace: query.Get("namespace"),
+			Cluster:   cluster,
+			Kinds:     api.ParseKindParams(query),
+			Limit:     limit,
+		},
+		yaml:          asYAML,
+		includeStatus: includeStatus,
+	}, nil
+}
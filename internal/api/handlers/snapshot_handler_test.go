@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moolen/spectre/internal/analysis/snapshot"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshotHandler(t *testing.T) *SnapshotHandler {
+	t.Helper()
+
+	// The Deployment is scaled at 200s, the old ConfigMap is deleted at 150s,
+	// the new one created at 300s and the Pod lives in another namespace
+	graphService := newTestGraphService(t, `
+		CREATE (d:ResourceIdentity {uid: 'deploy', kind: 'Deployment', apiGroup: 'apps', version: 'v1', namespace: 'shop', name: 'api', deleted: false})
+		CREATE (old:ResourceIdentity {uid: 'cm-old', kind: 'ConfigMap', version: 'v1', namespace: 'shop', name: 'settings', deleted: true, deletedAt: 150000000000})
+		CREATE (new:ResourceIdentity {uid: 'cm-new', kind: 'ConfigMap', version: 'v1', namespace: 'shop', name: 'settings', deleted: false})
+		CREATE (pod:ResourceIdentity {uid: 'pod', kind: 'Pod', version: 'v1', namespace: 'payments', name: 'worker', deleted: false})
+		CREATE (d)-[:CHANGED]->(:ChangeEvent {id: 'd-1', timestamp: 100000000000, eventType: 'CREATE', status: 'Ready', data: '{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api","namespace":"shop","uid":"deploy","resourceVersion":"1"},"spec":{"replicas":1}}'})
+		CREATE (d)-[:CHANGED]->(:ChangeEvent {id: 'd-2', timestamp: 200000000000, eventType: 'UPDATE', status: 'Ready', data: '{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api","namespace":"shop","uid":"deploy","resourceVersion":"2"},"spec":{"replicas":3},"status":{"readyReplicas":3}}'})
+		CREATE (old)-[:CHANGED]->(:ChangeEvent {id: 'cm-1', timestamp: 50000000000, eventType: 'CREATE', status: 'Ready', data: '{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop"},"data":{"mode":"old"}}'})
+		CREATE (old)-[:CHANGED]->(:ChangeEvent {id: 'cm-2', timestamp: 150000000000, eventType: 'DELETE', status: 'Terminating'})
+		CREATE (new)-[:CHANGED]->(:ChangeEvent {id: 'cm-3', timestamp: 300000000000, eventType: 'CREATE', status: 'Ready', data: '{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop"},"data":{"mode":"new"}}'})
+		CREATE (pod)-[:CHANGED]->(:ChangeEvent {id: 'p-1', timestamp: 100000000000, eventType: 'CREATE', status: 'Error'})
+	`)
+	return NewSnapshotHandler(graphService, logging.GetLogger("test"), nil)
+}
+
+func TestSnapshotHandler(t *testing.T) {
+	handler := newTestSnapshotHandler(t)
+
+	tests := []struct {
+		name          string
+		query         string
+		namespaces    []string
+		wantStatus    int
+		wantUIDs      []string
+		wantTruncated bool
+	}{
+		{name: "old config", query: "?at=120&namespace=shop", wantStatus: http.StatusOK, wantUIDs: []string{"cm-old", "deploy"}},
+		{name: "config deleted", query: "?at=160&namespace=shop", wantStatus: http.StatusOK, wantUIDs: []string{"deploy"}},
+		{name: "all namespaces", query: "?at=300", wantStatus: http.StatusOK, wantUIDs: []string{"pod", "cm-new", "deploy"}},
+		{name: "kind", query: "?at=300&kind=ConfigMap", wantStatus: http.StatusOK, wantUIDs: []string{"cm-new"}},
+		{name: "limit", query: "?at=300&namespace=shop&limit=1", wantStatus: http.StatusOK, wantUIDs: []string{"cm-new"}, wantTruncated: true},
+		{name: "tenant scope", query: "?at=300", namespaces: []string{"payments"}, wantStatus: http.StatusOK, wantUIDs: []string{"pod"}},
+		{name: "namespace outside the tenant", query: "?at=300&namespace=shop", namespaces: []string{"payments"}, wantStatus: http.StatusForbidden},
+		{name: "missing at", query: "?namespace=shop", wantStatus: http.StatusBadRequest},
+		{name: "unknown format", query: "?at=300&format=xml", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/snapshot"+tt.query, http.NoBody)
+			rec := serveRequest(handler.Handle, req, tt.namespaces...)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result snapshot.SnapshotResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			uids := make([]string, len(result.Resources))
+			for i, resource := range result.Resources {
+				uids[i] = resource.UID
+			}
+			assert.Equal(t, tt.wantUIDs, uids)
+			assert.Equal(t, len(tt.wantUIDs), result.Count)
+			assert.Equal(t, tt.wantTruncated, result.Truncated)
+		})
+	}
+}
+
+func TestSnapshotHandlerYAML(t *testing.T) {
+	handler := newTestSnapshotHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/snapshot?at=300&namespace=shop&kind=Deployment&format=yaml", http.NoBody)
+	rec := serveRequest(handler.Handle, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "false", rec.Header().Get("X-Snapshot-Truncated"))
+
+	expected := `# Snapshot at 1970-01-01T00:05:00Z
+---
+# Deployment shop/api, UPDATE at 1970-01-01T00:03:20Z
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: shop
+spec:
+  replicas: 3
+`
+	assert.Equal(t, expected, rec.Body.String())
+
+	// The status is kept on request
+	req = httptest.NewRequest(http.MethodGet, "/v1/snapshot?at=300&namespace=shop&kind=Deployment&format=yaml&status=true", http.NoBody)
+	rec = serveRequest(handler.Handle, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "status:\n  readyReplicas: 3\n")
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/auth"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGraphService returns a GraphService over an in-memory graph seeded
+// with the given Cypher query
+func newTestGraphService(t *testing.T, seed string) *api.GraphService {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	_, err := client.ExecuteQuery(ctx, graph.GraphQuery{Query: seed})
+	require.NoError(t, err)
+
+	return api.NewGraphService(client, logging.GetLogger("test"), nil)
+}
+
+// serveRequest runs the request against handler. With namespaces set, the
+// request is made by a caller restricted to them.
+func serveRequest(handler http.HandlerFunc, req *http.Request, namespaces ...string) *httptest.ResponseRecorder {
+	if len(namespaces) > 0 {
+		identity := &auth.Identity{Name: "tenant", Role: auth.RoleViewer, Namespaces: namespaces}
+		req = req.WithContext(auth.WithIdentity(req.Context(), identity))
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
@@ -87,6 +87,7 @@ func (h *TimelineCompareHandler) Handle(w http.ResponseWriter, r *http.Request)
 func (h *TimelineCompareHandler) executeQuery(ctx context.Context, executor api.QueryExecutor, query *models.QueryRequest) ComparisonMetrics {
 	start := time.Now()
 
+	api.ApplyTenantScope(ctx, &query.Filters)
 	result, err := executor.Execute(ctx, query)
 	elapsed := time.Since(start)
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -560,3 +561,52 @@ func TestBuildTimelineResponse_WithoutEvents(t *testing.T) {
 		t.Fatalf("Expected 1 resource in array, got %d", len(response.Resources))
 	}
 }
+
+// TestBuildTimelineResponse_FieldManagers tests that segments only carry
+// field managers that changed
+func TestBuildTimelineResponse_FieldManagers(t *testing.T) {
+	logger := logging.GetLogger("test")
+	tracer := noop.NewTracerProvider().Tracer("test")
+
+	mockExecutor := &mockConcurrentQueryExecutor{}
+	timelineService := api.NewTimelineService(mockExecutor, logger, tracer)
+
+	now := time.Now()
+	resource := models.ResourceMetadata{
+		Kind:      "Deployment",
+		Namespace: "default",
+		Name:      "api",
+		UID:       "deploy-uid-123",
+	}
+	created := map[string]string{"spec": "kubectl-client-side-apply"}
+	scaled := map[string]string{"spec": "kubectl-client-side-apply", "spec.replicas": "hpa-controller"}
+
+	resourceResult := &models.QueryResult{
+		Events: []models.Event{
+			{ID: "1", Timestamp: now.UnixNano(), Type: models.EventTypeCreate, Resource: resource, FieldManagers: created},
+			{ID: "2", Timestamp: now.Add(time.Minute).UnixNano(), Type: models.EventTypeUpdate, Resource: resource, FieldManagers: created},
+			{ID: "3", Timestamp: now.Add(2 * time.Minute).UnixNano(), Type: models.EventTypeUpdate, Resource: resource, FieldManagers: scaled},
+		},
+		Count:           3,
+		ExecutionTimeMs: 10,
+	}
+
+	response := timelineService.BuildTimelineResponse(resourceResult, &models.QueryResult{})
+
+	if len(response.Resources) != 1 {
+		t.Fatalf("Expected 1 resource in array, got %d", len(response.Resources))
+	}
+	segments := response.Resources[0].StatusSegments
+	if len(segments) != 3 {
+		t.Fatalf("Expected 3 segments, got %d", len(segments))
+	}
+	if !reflect.DeepEqual(segments[0].FieldManagers, created) {
+		t.Errorf("Expected the first segment to carry the field managers, got %v", segments[0].FieldManagers)
+	}
+	if segments[1].FieldManagers != nil {
+		t.Errorf("Expected no field managers on an unchanged segment, got %v", segments[1].FieldManagers)
+	}
+	if !reflect.DeepEqual(segments[2].FieldManagers, scaled) {
+		t.Errorf("Expected the changed field managers, got %v", segments[2].FieldManagers)
+	}
+}
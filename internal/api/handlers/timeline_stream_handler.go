@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// streamKeepAliveInterval is how often an idle stream sends a comment, so
+// that proxies don't close the connection
+const streamKeepAliveInterval = 15 * time.Second
+
+// TimelineStreamHandler handles /v1/timeline/stream, the Server-Sent Events
+// variant of the WatchTimeline RPC. It sends the timeline as a "snapshot"
+// event, followed by a "delta" event whenever new changes match the filters.
+type TimelineStreamHandler struct {
+	timelineService *api.TimelineService
+	watcher         *api.TimelineWatcher
+	logger          *logging.Logger
+	tracer          trace.Tracer
+}
+
+// NewTimelineStreamHandler creates a new timeline stream handler
+func NewTimelineStreamHandler(timelineService *api.TimelineService, watcher *api.TimelineWatcher, logger *logging.Logger, tracer trace.Tracer) *TimelineStreamHandler {
+	return &TimelineStreamHandler{
+		timelineService: timelineService,
+		watcher:         watcher,
+		logger:          logger,
+		tracer:          tracer,
+	}
+}
+
+// Handle streams a timeline. It accepts the filters of /v1/timeline; end
+// defaults to now and only bounds the snapshot.
+func (h *TimelineStreamHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx, span := h.tracer.Start(ctx, "timeline.HandleStream",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", "/v1/timeline/stream"),
+		),
+	)
+	defer span.End()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.logger.Error("SSE not supported: ResponseWriter doesn't implement Flusher")
+		api.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Streaming not supported")
+		return
+	}
+
+	params := r.URL.Query()
+	end := params.Get("end")
+	if end == "" {
+		end = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	query, err := h.timelineService.ParseQueryParameters(ctx, params.Get("start"), end, params)
+	if err != nil {
+		span.RecordError(err)
+		api.WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	watch, _, snapshot, err := h.timelineService.WatchTimeline(ctx, h.watcher, query)
+	if err != nil {
+		span.RecordError(err)
+		h.logger.Error("Timeline stream snapshot failed: %v", err)
+		api.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to execute query")
+		return
+	}
+	defer watch.Close()
+
+	// The stream outlives the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debug("Could not clear write deadline of timeline stream: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeSSE(w, flusher, "snapshot", snapshot); err != nil {
+		h.logger.Debug("Timeline stream client gone: %v", err)
+		return
+	}
+	h.logger.Debug("Timeline stream started with %d resources", snapshot.Count)
+
+	deltas := make(chan *api.TimelineDelta)
+	watchErr := make(chan error, 1)
+	go func() {
+		for {
+			delta, err := watch.Next(ctx)
+			if err != nil {
+				watchErr <- err
+				return
+			}
+			select {
+			case deltas <- delta:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.logger.Debug("Timeline stream client disconnected")
+			return
+		case delta := <-deltas:
+			if err := writeSSE(w, flusher, "delta", delta); err != nil {
+				h.logger.Debug("Timeline stream client gone: %v", err)
+				return
+			}
+		case err := <-watchErr:
+			if ctx.Err() != nil {
+				return
+			}
+			// The watch fell behind; the client has to reload the timeline
+			h.logger.Warn("Timeline stream ended: %v", err)
+			_ = writeSSE(w, flusher, "error", map[string]string{"message": err.Error()})
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSE writes one Server-Sent Event with a JSON payload
+func writeSSE(w http.ResponseWriter, flusher http.Flusher, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/watcher"
+)
+
+// WatchStatusProvider exposes the resources the watcher is recording
+type WatchStatusProvider interface {
+	ActiveWatches() watcher.WatchSet
+}
+
+// WatchStatusHandler handles /v1/watches requests
+type WatchStatusHandler struct {
+	provider WatchStatusProvider
+	logger   *logging.Logger
+}
+
+// NewWatchStatusHandler creates a new watch status handler
+func NewWatchStatusHandler(provider WatchStatusProvider, logger *logging.Logger) *WatchStatusHandler {
+	return &WatchStatusHandler{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// Handle returns the active watchers and the resources waiting for their CRD
+func (h *WatchStatusHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = api.WriteJSON(w, h.provider.ActiveWatches())
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/moolen/spectre/internal/api/pb"
+	"github.com/moolen/spectre/internal/api/pb/pbconnect"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultIngestDedupSize is the number of recently ingested event IDs the hub
+// remembers to detect batches that agents retry after a lost acknowledgement
+const DefaultIngestDedupSize = 100000
+
+// IngestConnectService implements the Connect IngestService interface.
+// It receives event batches from remote agents and writes them to the graph.
+// Agents deliver at least once, so batches are deduplicated by event ID.
+type IngestConnectService struct {
+	pbconnect.UnimplementedIngestServiceHandler
+	processor EventBatchProcessor
+	seen      *lru.Cache[string, struct{}]
+	logger    *logging.Logger
+	tracer    trace.Tracer
+}
+
+// NewIngestConnectService creates a new ingest Connect service writing to the given processor
+func NewIngestConnectService(processor EventBatchProcessor, logger *logging.Logger, tracer trace.Tracer) *IngestConnectService {
+	seen, _ := lru.New[string, struct{}](DefaultIngestDedupSize)
+	return &IngestConnectService{
+		processor: processor,
+		seen:      seen,
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// PushEvents implements the Connect unary endpoint used by agents
+func (s *IngestConnectService) PushEvents(
+	ctx context.Context,
+	req *connect.Request[pb.PushEventsRequest],
+) (*connect.Response[pb.PushEventsResponse], error) {
+	ctx, span := s.tracer.Start(ctx, "connect.PushEvents",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("ingest.cluster", req.Msg.Cluster),
+			attribute.String("ingest.agent_id", req.Msg.AgentId),
+			attribute.Int("ingest.events", len(req.Msg.Events)),
+		),
+	)
+	defer span.End()
+
+	events, duplicates, err := s.convertEvents(req.Msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid request")
+		s.logger.Warn("Rejected event batch from agent %q (cluster=%q): %v", req.Msg.AgentId, req.Msg.Cluster, err)
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if len(events) > 0 {
+		if err := s.processor.ProcessBatch(ctx, events); err != nil {
+			// Remember the events that were stored despite the failure, so
+			// the agent's retry only applies the failed ones
+			var batchErr *sync.BatchError
+			if errors.As(err, &batchErr) {
+				for i := range events {
+					if _, failed := batchErr.Failed[events[i].ID]; !failed {
+						s.seen.Add(events[i].ID, struct{}{})
+					}
+				}
+			}
+
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to process batch")
+			s.logger.Error("Failed to process event batch from cluster %q: %v", req.Msg.Cluster, err)
+			return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("failed to process events: %w", err))
+		}
+
+		// Only remember events once they are stored, so a failed batch is
+		// processed again when the agent retries it
+		for i := range events {
+			s.seen.Add(events[i].ID, struct{}{})
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("ingest.accepted", len(events)),
+		attribute.Int("ingest.duplicates", duplicates),
+	)
+	s.logger.Debug("Ingested %d events from cluster %q (agent=%q, duplicates=%d)",
+		len(events), req.Msg.Cluster, req.Msg.AgentId, duplicates)
+
+	return connect.NewResponse(&pb.PushEventsResponse{
+		Accepted:   int32(len(events)),
+		Duplicates: int32(duplicates),
+	}), nil
+}
+
+// convertEvents validates the request and converts its events, tagging them
+// with the agent's cluster. Events that were already ingested are skipped.
+func (s *IngestConnectService) convertEvents(req *pb.PushEventsRequest) ([]models.Event, int, error) {
+	if err := models.ValidateClusterName(req.Cluster); err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]models.Event, 0, len(req.Events))
+	inBatch := make(map[string]struct{}, len(req.Events))
+	duplicates := 0
+
+	for i, pbEvent := range req.Events {
+		if pbEvent.GetId() == "" {
+			return nil, 0, NewValidationError("event %d has no id", i)
+		}
+		if _, ok := inBatch[pbEvent.Id]; ok {
+			duplicates++
+			continue
+		}
+		if s.seen.Contains(pbEvent.Id) {
+			duplicates++
+			continue
+		}
+		inBatch[pbEvent.Id] = struct{}{}
+
+		var event models.Event
+		if err := event.FromProto(pbEvent); err != nil {
+			return nil, 0, fmt.Errorf("event %q: %w", pbEvent.Id, err)
+		}
+		if event.Resource.Cluster != "" && event.Resource.Cluster != req.Cluster {
+			return nil, 0, NewValidationError("event %q belongs to cluster %q, not %q", pbEvent.Id, event.Resource.Cluster, req.Cluster)
+		}
+		event.Resource.SetCluster(req.Cluster)
+		events = append(events, event)
+	}
+
+	return events, duplicates, nil
+}
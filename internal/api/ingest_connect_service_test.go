@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/moolen/spectre/internal/api/pb"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	modelspb "github.com/moolen/spectre/internal/models/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type recordingProcessor struct {
+	batches [][]models.Event
+	err     error
+}
+
+func (p *recordingProcessor) ProcessBatch(ctx context.Context, events []models.Event) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.batches = append(p.batches, events)
+	return nil
+}
+
+func pushRequest(cluster string, ids ...string) *connect.Request[pb.PushEventsRequest] {
+	req := &pb.PushEventsRequest{Cluster: cluster, AgentId: "agent-0"}
+	for _, id := range ids {
+		req.Events = append(req.Events, &modelspb.Event{
+			Id:   id,
+			Type: modelspb.EventType_UPDATE,
+			Resource: &modelspb.ResourceMetadata{
+				Version: "v1", Kind: "Pod", Namespace: "default", Name: id, Uid: "uid-" + id,
+			},
+			Data: []byte(`{}`),
+		})
+	}
+	return connect.NewRequest(req)
+}
+
+func TestIngestConnectService_PushEvents(t *testing.T) {
+	processor := &recordingProcessor{}
+	service := NewIngestConnectService(processor, logging.GetLogger("test"), noop.NewTracerProvider().Tracer("test"))
+	ctx := context.Background()
+
+	resp, err := service.PushEvents(ctx, pushRequest("edge-1", "e1", "e2", "e2"))
+	if err != nil {
+		t.Fatalf("PushEvents() error = %v", err)
+	}
+	if resp.Msg.Accepted != 2 || resp.Msg.Duplicates != 1 {
+		t.Errorf("PushEvents() = %d accepted / %d duplicates, want 2 / 1", resp.Msg.Accepted, resp.Msg.Duplicates)
+	}
+	if got := processor.batches[0][0].Resource; got.Cluster != "edge-1" || got.UID != "edge-1:uid-e1" {
+		t.Errorf("event not tagged with the agent's cluster: %+v", got)
+	}
+
+	// A retried batch after a lost acknowledgement is not processed again
+	resp, err = service.PushEvents(ctx, pushRequest("edge-1", "e1", "e2", "e3"))
+	if err != nil {
+		t.Fatalf("PushEvents() error = %v", err)
+	}
+	if resp.Msg.Accepted != 1 || resp.Msg.Duplicates != 2 {
+		t.Errorf("retried PushEvents() = %d accepted / %d duplicates, want 1 / 2", resp.Msg.Accepted, resp.Msg.Duplicates)
+	}
+	if len(processor.batches) != 2 || len(processor.batches[1]) != 1 || processor.batches[1][0].ID != "e3" {
+		t.Errorf("processed batches = %+v, want only e3 in the second batch", processor.batches)
+	}
+}
+
+func TestIngestConnectService_FailedBatchIsRetryable(t *testing.T) {
+	processor := &recordingProcessor{err: errors.New("graph unavailable")}
+	service := NewIngestConnectService(processor, logging.GetLogger("test"), noop.NewTracerProvider().Tracer("test"))
+	ctx := context.Background()
+
+	_, err := service.PushEvents(ctx, pushRequest("edge-1", "e1"))
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("PushEvents() error = %v, want Unavailable", err)
+	}
+
+	processor.err = nil
+	resp, err := service.PushEvents(ctx, pushRequest("edge-1", "e1"))
+	if err != nil {
+		t.Fatalf("PushEvents() error = %v", err)
+	}
+	if resp.Msg.Accepted != 1 {
+		t.Errorf("PushEvents() accepted = %d, want 1 after failed attempt", resp.Msg.Accepted)
+	}
+}
+
+func TestIngestConnectService_RejectsInvalidRequests(t *testing.T) {
+	service := NewIngestConnectService(&recordingProcessor{}, logging.GetLogger("test"), noop.NewTracerProvider().Tracer("test"))
+
+	tests := []struct {
+		name string
+		req  *connect.Request[pb.PushEventsRequest]
+	}{
+		{name: "missing cluster", req: pushRequest("", "e1")},
+		{name: "invalid cluster", req: pushRequest("Edge_1", "e1")},
+		{name: "missing event id", req: pushRequest("edge-1", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.PushEvents(context.Background(), tt.req)
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("PushEvents() error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+// failingWriteClient fails writing the change event with the given ID once
+type failingWriteClient struct {
+	graph.Client
+	failID string
+}
+
+func (c *failingWriteClient) ExecuteQuery(ctx context.Context, query graph.GraphQuery) (*graph.QueryResult, error) {
+	if c.failID != "" && strings.Contains(query.Query, "MERGE (e:ChangeEvent {id: $id})") && query.Parameters["id"] == c.failID {
+		c.failID = ""
+		return nil, errors.New("write timed out")
+	}
+	return c.Client.ExecuteQuery(ctx, query)
+}
+
+func TestIngestConnectService_RetriesFailedEvents(t *testing.T) {
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	memory := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, memory.Connect(ctx))
+	require.NoError(t, memory.InitializeSchema(ctx))
+
+	client := &failingWriteClient{Client: memory, failID: "e2"}
+	pipeline := sync.NewPipeline(sync.DefaultPipelineConfig(), client)
+	service := NewIngestConnectService(pipeline, logging.GetLogger("test"), noop.NewTracerProvider().Tracer("test"))
+
+	_, err := service.PushEvents(ctx, pushRequest("edge-1", "e1", "e2"))
+	require.Equal(t, connect.CodeUnavailable, connect.CodeOf(err), "error = %v", err)
+
+	// The agent retries the batch: e1 was stored and is skipped, e2 is applied
+	resp, err := service.PushEvents(ctx, pushRequest("edge-1", "e1", "e2"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, resp.Msg.Accepted)
+	assert.EqualValues(t, 1, resp.Msg.Duplicates)
+
+	result, err := memory.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: "MATCH (e:ChangeEvent) RETURN e.id ORDER BY e.id",
+	})
+	require.NoError(t, err)
+	var ids []interface{}
+	for _, row := range result.Rows {
+		ids = append(ids, row[0])
+	}
+	assert.Equal(t, []interface{}{"e1", "e2"}, ids)
+}
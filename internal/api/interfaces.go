@@ -12,6 +12,14 @@ type QueryExecutor interface {
 	SetSharedCache(cache interface{})
 }
 
+// ArchiveExecutor is a QueryExecutor over data that expired from the graph.
+// Horizon returns the timestamp of the newest archived event in Unix
+// nanoseconds, or 0 when nothing has been archived yet.
+type ArchiveExecutor interface {
+	QueryExecutor
+	Horizon() int64
+}
+
 // TimelineQuerySource specifies which executor to use for queries
 type TimelineQuerySource string
 
@@ -19,3 +27,9 @@ const (
 	TimelineQuerySourceStorage TimelineQuerySource = "storage"
 	TimelineQuerySourceGraph   TimelineQuerySource = "graph"
 )
+
+// EventBatchProcessor writes batches of events into the graph, e.g. events
+// pushed by remote agents
+type EventBatchProcessor interface {
+	ProcessBatch(ctx context.Context, events []models.Event) error
+}
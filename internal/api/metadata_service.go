@@ -62,7 +62,7 @@ func (s *MetadataService) GetMetadata(ctx context.Context, useCache bool, startT
 			)
 			s.logger.Debug("Metadata cache hit: %d namespaces, %d kinds",
 				len(cachedData.Namespaces), len(cachedData.Kinds))
-			return cachedData, true, nil
+			return scopeMetadataResponse(ctx, cachedData), true, nil
 		}
 
 		// Cache failed - log and fall through to direct query
@@ -104,7 +104,7 @@ func (s *MetadataService) GetMetadata(ctx context.Context, useCache bool, startT
 		s.logger.Debug("Metadata query completed: %d namespaces, %d kinds",
 			len(namespacesList), len(kindsList))
 
-		return response, false, nil
+		return scopeMetadataResponse(ctx, response), false, nil
 	}
 
 	// Fallback to old method (shouldn't happen with current implementations)
@@ -123,7 +123,21 @@ func (s *MetadataService) GetMetadata(ctx context.Context, useCache bool, startT
 		attribute.Int("kind_count", len(response.Kinds)),
 	)
 
-	return response, false, nil
+	return scopeMetadataResponse(ctx, response), false, nil
+}
+
+// scopeMetadataResponse returns a copy of the metadata listing only the
+// namespaces in the caller's tenant scope. The input may be the shared cached
+// response and is never modified.
+func scopeMetadataResponse(ctx context.Context, response *models.MetadataResponse) *models.MetadataResponse {
+	namespaces := FilterNamespaces(ctx, response.Namespaces)
+	if len(namespaces) == len(response.Namespaces) {
+		return response
+	}
+
+	scoped := *response
+	scoped.Namespaces = namespaces
+	return &scoped
 }
 
 // QueryDistinctMetadataFallback performs a full query and extracts metadata
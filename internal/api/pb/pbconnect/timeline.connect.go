@@ -36,11 +36,17 @@ const (
 	// TimelineServiceGetTimelineProcedure is the fully-qualified name of the TimelineService's
 	// GetTimeline RPC.
 	TimelineServiceGetTimelineProcedure = "/api.TimelineService/GetTimeline"
+	// TimelineServiceWatchTimelineProcedure is the fully-qualified name of the TimelineService's
+	// WatchTimeline RPC.
+	TimelineServiceWatchTimelineProcedure = "/api.TimelineService/WatchTimeline"
 )
 
 // TimelineServiceClient is a client for the api.TimelineService service.
 type TimelineServiceClient interface {
 	GetTimeline(context.Context, *connect.Request[pb.TimelineRequest]) (*connect.ServerStreamForClient[pb.TimelineChunk], error)
+	// WatchTimeline streams the snapshot like GetTimeline, then keeps the
+	// stream open and sends a delta whenever new changes match the filters
+	WatchTimeline(context.Context, *connect.Request[pb.TimelineRequest]) (*connect.ServerStreamForClient[pb.TimelineChunk], error)
 }
 
 // NewTimelineServiceClient constructs a client for the api.TimelineService service. By default, it
@@ -60,12 +66,19 @@ func NewTimelineServiceClient(httpClient connect.HTTPClient, baseURL string, opt
 			connect.WithSchema(timelineServiceMethods.ByName("GetTimeline")),
 			connect.WithClientOptions(opts...),
 		),
+		watchTimeline: connect.NewClient[pb.TimelineRequest, pb.TimelineChunk](
+			httpClient,
+			baseURL+TimelineServiceWatchTimelineProcedure,
+			connect.WithSchema(timelineServiceMethods.ByName("WatchTimeline")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // timelineServiceClient implements TimelineServiceClient.
 type timelineServiceClient struct {
-	getTimeline *connect.Client[pb.TimelineRequest, pb.TimelineChunk]
+	getTimeline   *connect.Client[pb.TimelineRequest, pb.TimelineChunk]
+	watchTimeline *connect.Client[pb.TimelineRequest, pb.TimelineChunk]
 }
 
 // GetTimeline calls api.TimelineService.GetTimeline.
@@ -73,9 +86,17 @@ func (c *timelineServiceClient) GetTimeline(ctx context.Context, req *connect.Re
 	return c.getTimeline.CallServerStream(ctx, req)
 }
 
+// WatchTimeline calls api.TimelineService.WatchTimeline.
+func (c *timelineServiceClient) WatchTimeline(ctx context.Context, req *connect.Request[pb.TimelineRequest]) (*connect.ServerStreamForClient[pb.TimelineChunk], error) {
+	return c.watchTimeline.CallServerStream(ctx, req)
+}
+
 // TimelineServiceHandler is an implementation of the api.TimelineService service.
 type TimelineServiceHandler interface {
 	GetTimeline(context.Context, *connect.Request[pb.TimelineRequest], *connect.ServerStream[pb.TimelineChunk]) error
+	// WatchTimeline streams the snapshot like GetTimeline, then keeps the
+	// stream open and sends a delta whenever new changes match the filters
+	WatchTimeline(context.Context, *connect.Request[pb.TimelineRequest], *connect.ServerStream[pb.TimelineChunk]) error
 }
 
 // NewTimelineServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -91,10 +112,18 @@ func NewTimelineServiceHandler(svc TimelineServiceHandler, opts ...connect.Handl
 		connect.WithSchema(timelineServiceMethods.ByName("GetTimeline")),
 		connect.WithHandlerOptions(opts...),
 	)
+	timelineServiceWatchTimelineHandler := connect.NewServerStreamHandler(
+		TimelineServiceWatchTimelineProcedure,
+		svc.WatchTimeline,
+		connect.WithSchema(timelineServiceMethods.ByName("WatchTimeline")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/api.TimelineService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case TimelineServiceGetTimelineProcedure:
 			timelineServiceGetTimelineHandler.ServeHTTP(w, r)
+		case TimelineServiceWatchTimelineProcedure:
+			timelineServiceWatchTimelineHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -107,3 +136,7 @@ type UnimplementedTimelineServiceHandler struct{}
 func (UnimplementedTimelineServiceHandler) GetTimeline(context.Context, *connect.Request[pb.TimelineRequest], *connect.ServerStream[pb.TimelineChunk]) error {
 	return connect.NewError(connect.CodeUnimplemented, errors.New("api.TimelineService.GetTimeline is not implemented"))
 }
+
+func (UnimplementedTimelineServiceHandler) WatchTimeline(context.Context, *connect.Request[pb.TimelineRequest], *connect.ServerStream[pb.TimelineChunk]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("api.TimelineService.WatchTimeline is not implemented"))
+}
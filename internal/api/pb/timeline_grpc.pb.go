@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TimelineService_GetTimeline_FullMethodName = "/api.TimelineService/GetTimeline"
+	TimelineService_GetTimeline_FullMethodName   = "/api.TimelineService/GetTimeline"
+	TimelineService_WatchTimeline_FullMethodName = "/api.TimelineService/WatchTimeline"
 )
 
 // TimelineServiceClient is the client API for TimelineService service.
@@ -29,6 +30,9 @@ const (
 // TimelineService definition
 type TimelineServiceClient interface {
 	GetTimeline(ctx context.Context, in *TimelineRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TimelineChunk], error)
+	// WatchTimeline streams the snapshot like GetTimeline, then keeps the
+	// stream open and sends a delta whenever new changes match the filters
+	WatchTimeline(ctx context.Context, in *TimelineRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TimelineChunk], error)
 }
 
 type timelineServiceClient struct {
@@ -58,6 +62,25 @@ func (c *timelineServiceClient) GetTimeline(ctx context.Context, in *TimelineReq
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type TimelineService_GetTimelineClient = grpc.ServerStreamingClient[TimelineChunk]
 
+func (c *timelineServiceClient) WatchTimeline(ctx context.Context, in *TimelineRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TimelineChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TimelineService_ServiceDesc.Streams[1], TimelineService_WatchTimeline_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TimelineRequest, TimelineChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TimelineService_WatchTimelineClient = grpc.ServerStreamingClient[TimelineChunk]
+
 // TimelineServiceServer is the server API for TimelineService service.
 // All implementations must embed UnimplementedTimelineServiceServer
 // for forward compatibility.
@@ -65,6 +88,9 @@ type TimelineService_GetTimelineClient = grpc.ServerStreamingClient[TimelineChun
 // TimelineService definition
 type TimelineServiceServer interface {
 	GetTimeline(*TimelineRequest, grpc.ServerStreamingServer[TimelineChunk]) error
+	// WatchTimeline streams the snapshot like GetTimeline, then keeps the
+	// stream open and sends a delta whenever new changes match the filters
+	WatchTimeline(*TimelineRequest, grpc.ServerStreamingServer[TimelineChunk]) error
 	mustEmbedUnimplementedTimelineServiceServer()
 }
 
@@ -78,6 +104,9 @@ type UnimplementedTimelineServiceServer struct{}
 func (UnimplementedTimelineServiceServer) GetTimeline(*TimelineRequest, grpc.ServerStreamingServer[TimelineChunk]) error {
 	return status.Errorf(codes.Unimplemented, "method GetTimeline not implemented")
 }
+func (UnimplementedTimelineServiceServer) WatchTimeline(*TimelineRequest, grpc.ServerStreamingServer[TimelineChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTimeline not implemented")
+}
 func (UnimplementedTimelineServiceServer) mustEmbedUnimplementedTimelineServiceServer() {}
 func (UnimplementedTimelineServiceServer) testEmbeddedByValue()                         {}
 
@@ -110,6 +139,17 @@ func _TimelineService_GetTimeline_Handler(srv interface{}, stream grpc.ServerStr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type TimelineService_GetTimelineServer = grpc.ServerStreamingServer[TimelineChunk]
 
+func _TimelineService_WatchTimeline_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TimelineRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TimelineServiceServer).WatchTimeline(m, &grpc.GenericServerStream[TimelineRequest, TimelineChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TimelineService_WatchTimelineServer = grpc.ServerStreamingServer[TimelineChunk]
+
 // TimelineService_ServiceDesc is the grpc.ServiceDesc for TimelineService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -123,6 +163,11 @@ var TimelineService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _TimelineService_GetTimeline_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchTimeline",
+			Handler:       _TimelineService_WatchTimeline_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "internal/api/proto/timeline.proto",
 }
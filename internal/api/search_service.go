@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/moolen/spectre/internal/logging"
 	"github.com/moolen/spectre/internal/models"
@@ -31,11 +32,9 @@ func NewSearchService(queryExecutor QueryExecutor, logger *logging.Logger, trace
 }
 
 // ParseSearchQuery parses and validates query parameters into a QueryRequest
-// The q parameter is reserved for future full-text search functionality but is currently optional.
+// The optional q parameter is a Spectre query language expression (see package
+// searchql) that further restricts the dimensional filters.
 func (s *SearchService) ParseSearchQuery(q string, startStr, endStr string, filters map[string]string) (*models.QueryRequest, error) {
-	// Note: q parameter is reserved for future full-text search but currently unused
-	// The search operates on time windows and dimensional filters only
-
 	// Parse timestamps
 	start, err := ParseTimestamp(startStr, "start")
 	if err != nil {
@@ -61,6 +60,10 @@ func (s *SearchService) ParseSearchQuery(q string, startStr, endStr string, filt
 		Version:   filters["version"],
 		Kind:      filters["kind"],
 		Namespace: filters["namespace"],
+		Query:     strings.TrimSpace(q),
+	}
+	if clusters := filters["clusters"]; clusters != "" {
+		queryFilters.Clusters = strings.Split(clusters, ",")
 	}
 
 	// Validate filters
@@ -89,6 +92,9 @@ func (s *SearchService) ExecuteSearch(ctx context.Context, query *models.QueryRe
 	ctx, span := s.tracer.Start(ctx, "search.execute")
 	defer span.End()
 
+	// Restrict the query to the caller's tenant namespaces
+	query = ScopeQuery(ctx, query)
+
 	// Log query execution
 	s.logger.Debug("Executing search query: start=%d, end=%d, filters=%s",
 		query.StartTimestamp, query.EndTimestamp, query.Filters.String())
@@ -136,6 +142,7 @@ func (s *SearchService) BuildSearchResponse(queryResult *models.QueryResult) (*m
 				Kind:      event.Resource.Kind,
 				Namespace: event.Resource.Namespace,
 				Name:      event.Resource.Name,
+				Cluster:   event.Resource.Cluster,
 			}
 		}
 	}
@@ -0,0 +1,475 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/moolen/spectre/internal/analysis"
+	"github.com/moolen/spectre/internal/analysis/anomaly"
+	blastradius "github.com/moolen/spectre/internal/analysis/blast_radius"
+	causalpaths "github.com/moolen/spectre/internal/analysis/causal_paths"
+	namespacegraph "github.com/moolen/spectre/internal/analysis/namespace_graph"
+	"github.com/moolen/spectre/internal/auth"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// ErrNamespaceForbidden is returned when the requested namespace or resource
+// lies outside of the caller's tenant scope
+var ErrNamespaceForbidden = errors.New("namespace is outside of the caller's tenant scope")
+
+// MaskedName replaces the name and namespace of resources outside of the
+// caller's tenant scope
+const MaskedName = "<masked>"
+
+// maskedUIDPrefix prefixes the opaque identifiers of masked resources. The
+// suffix is derived from the real UID so that edges stay connected.
+const maskedUIDPrefix = "masked-"
+
+// ApplyTenantScope restricts the query filters to the namespaces the caller
+// in the context may access
+func ApplyTenantScope(ctx context.Context, filters *models.QueryFilters) {
+	filters.AllowedNamespaces = auth.AllowedNamespaces(ctx)
+}
+
+// ScopeQuery returns a copy of the query restricted to the namespaces the
+// caller in the context may access. The query itself is left untouched, as it
+// may be shared with other callers.
+func ScopeQuery(ctx context.Context, query *models.QueryRequest) *models.QueryRequest {
+	scoped := *query
+	ApplyTenantScope(ctx, &scoped.Filters)
+	return &scoped
+}
+
+// CheckNamespaceAccess returns ErrNamespaceForbidden if the caller may not
+// access the namespace. Cluster-scoped resources (empty namespace) are accessible.
+func CheckNamespaceAccess(ctx context.Context, namespace string) error {
+	if !newNamespaceScope(ctx).allows(namespace) {
+		return ErrNamespaceForbidden
+	}
+	return nil
+}
+
+// CheckResourceAccess returns ErrNamespaceForbidden if the resource lives in a
+// namespace outside of the caller's tenant scope. Unknown resources are
+// allowed so that the analyzers report them as not found.
+func CheckResourceAccess(ctx context.Context, graphClient graph.Client, resourceUID string) error {
+	if auth.AllowedNamespaces(ctx) == nil {
+		return nil
+	}
+
+	result, err := graphClient.ExecuteQuery(ctx, graph.GraphQuery{
+		Query:      "MATCH (r:ResourceIdentity {uid: $uid}) RETURN r.namespace",
+		Parameters: map[string]interface{}{"uid": resourceUID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up resource namespace: %w", err)
+	}
+
+	for _, row := range result.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		namespace, _ := row[0].(string)
+		if err := CheckNamespaceAccess(ctx, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FilterNamespaces returns the subset of namespaces the caller may access
+func FilterNamespaces(ctx context.Context, namespaces []string) []string {
+	scope := newNamespaceScope(ctx)
+	if scope == nil {
+		return namespaces
+	}
+
+	filtered := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if ns != "" && scope.allows(ns) {
+			filtered = append(filtered, ns)
+		}
+	}
+	return filtered
+}
+
+// namespaceScope is the set of namespaces a tenant may access.
+// A nil scope is unrestricted.
+type namespaceScope struct {
+	allowed map[string]bool
+}
+
+func newNamespaceScope(ctx context.Context) *namespaceScope {
+	namespaces := auth.AllowedNamespaces(ctx)
+	if namespaces == nil {
+		return nil
+	}
+
+	scope := &namespaceScope{allowed: make(map[string]bool, len(namespaces))}
+	for _, ns := range namespaces {
+		scope.allowed[ns] = true
+	}
+	return scope
+}
+
+// allows reports whether resources in the namespace are visible
+func (s *namespaceScope) allows(namespace string) bool {
+	return s == nil || namespace == "" || s.allowed[namespace]
+}
+
+// reduces reports whether a resource in the namespace is cluster-scoped and
+// must be shown in reduced form
+func (s *namespaceScope) reduces(namespace string) bool {
+	return s != nil && namespace == ""
+}
+
+// maskUID returns a stable opaque identifier for a masked resource
+func maskUID(uid string) string {
+	sum := sha256.Sum256([]byte(uid))
+	return maskedUIDPrefix + hex.EncodeToString(sum[:8])
+}
+
+// maskSymptomResource hides the identity of a resource outside of the scope.
+// The kind is kept so that the shape of a causal chain remains meaningful.
+func (s *namespaceScope) maskSymptomResource(resource analysis.SymptomResource) (analysis.SymptomResource, bool) {
+	if s.allows(resource.Namespace) {
+		return resource, false
+	}
+	return analysis.SymptomResource{
+		UID:       maskUID(resource.UID),
+		Kind:      resource.Kind,
+		Namespace: MaskedName,
+		Name:      MaskedName,
+	}, true
+}
+
+// scopeAnomalies drops anomalies of resources outside of the scope and strips
+// the details of cluster-scoped ones
+func (s *namespaceScope) scopeAnomalies(anomalies []anomaly.Anomaly) []anomaly.Anomaly {
+	if s == nil || anomalies == nil {
+		return anomalies
+	}
+
+	scoped := make([]anomaly.Anomaly, 0, len(anomalies))
+	for _, a := range anomalies {
+		if !s.allows(a.Node.Namespace) {
+			continue
+		}
+		if s.reduces(a.Node.Namespace) {
+			a.Details = nil
+		}
+		scoped = append(scoped, a)
+	}
+	return scoped
+}
+
+// reduceChangeEvent strips everything but status information from a change
+// event of a cluster-scoped resource
+func reduceChangeEvent(event analysis.ChangeEventInfo) analysis.ChangeEventInfo {
+	event.Description = ""
+	event.Data = models.ReduceResourceData(event.Data)
+	if event.FullSnapshot != nil {
+		event.FullSnapshot = models.ReduceResourceObject(event.FullSnapshot)
+	}
+	if event.Diff != nil {
+		diffs := make([]analysis.EventDiff, 0, len(event.Diff))
+		for _, diff := range event.Diff {
+			if strings.HasPrefix(diff.Path, "status.phase") || strings.HasPrefix(diff.Path, "status.conditions") {
+				diffs = append(diffs, diff)
+			}
+		}
+		event.Diff = diffs
+	}
+	return event
+}
+
+// ScopeAnomalyResponse returns a copy of the response restricted to the
+// caller's tenant scope
+func ScopeAnomalyResponse(ctx context.Context, resp *anomaly.AnomalyResponse) *anomaly.AnomalyResponse {
+	scope := newNamespaceScope(ctx)
+	if scope == nil || resp == nil {
+		return resp
+	}
+
+	scoped := *resp
+	scoped.Anomalies = scope.scopeAnomalies(resp.Anomalies)
+	return &scoped
+}
+
+// ScopeCausalPathsResponse returns a copy of the response in which resources
+// outside of the caller's tenant scope are masked
+func ScopeCausalPathsResponse(ctx context.Context, resp *causalpaths.CausalPathsResponse) *causalpaths.CausalPathsResponse {
+	scope := newNamespaceScope(ctx)
+	if scope == nil || resp == nil {
+		return resp
+	}
+
+	scoped := *resp
+	scoped.Paths = scope.scopeCausalPaths(resp.Paths)
+	return &scoped
+}
+
+func (s *namespaceScope) scopeCausalPaths(paths []causalpaths.CausalPath) []causalpaths.CausalPath {
+	if s == nil || paths == nil {
+		return paths
+	}
+
+	explanations := causalpaths.NewExplanationBuilder()
+	scoped := make([]causalpaths.CausalPath, len(paths))
+	for i, path := range paths {
+		var masked, m bool
+		path.CandidateRoot, masked = s.scopePathNode(path.CandidateRoot)
+
+		steps := make([]causalpaths.PathStep, len(path.Steps))
+		for j, step := range path.Steps {
+			step.Node, m = s.scopePathNode(step.Node)
+			masked = masked || m
+			steps[j] = step
+		}
+		path.Steps = steps
+
+		if path.AffectedSymptoms != nil {
+			symptoms := make([]causalpaths.PathNode, len(path.AffectedSymptoms))
+			for j, symptom := range path.AffectedSymptoms {
+				symptoms[j], _ = s.scopePathNode(symptom)
+			}
+			path.AffectedSymptoms = symptoms
+		}
+
+		// The explanation names the root cause and symptom
+		if masked {
+			path.Explanation = explanations.GenerateExplanation(path)
+		}
+		scoped[i] = path
+	}
+	return scoped
+}
+
+// scopePathNode masks or reduces a causal path node and reports whether it was masked
+func (s *namespaceScope) scopePathNode(node causalpaths.PathNode) (causalpaths.PathNode, bool) {
+	namespace := node.Resource.Namespace
+
+	resource, masked := s.maskSymptomResource(node.Resource)
+	if masked {
+		return causalpaths.PathNode{
+			ID:        maskUID(node.ID),
+			Resource:  resource,
+			Anomalies: []anomaly.Anomaly{},
+		}, true
+	}
+
+	node.Anomalies = s.scopeAnomalies(node.Anomalies)
+	if s.reduces(namespace) && node.PrimaryEvent != nil {
+		event := reduceChangeEvent(*node.PrimaryEvent)
+		node.PrimaryEvent = &event
+	}
+	return node, false
+}
+
+// ScopeBlastRadiusResponse returns a copy of the response in which resources
+// outside of the caller's tenant scope are masked. Masked resources keep their
+// place in the ranking so that the extent of the impact stays visible.
+func ScopeBlastRadiusResponse(ctx context.Context, resp *blastradius.BlastRadiusResponse) *blastradius.BlastRadiusResponse {
+	scope := newNamespaceScope(ctx)
+	if scope == nil || resp == nil {
+		return resp
+	}
+
+	scoped := *resp
+	scoped.Trigger, _ = scope.maskSymptomResource(resp.Trigger)
+
+	impacted := make([]blastradius.ImpactedResource, len(resp.Impacted))
+	for i, resource := range resp.Impacted {
+		resource.Resource, _ = scope.maskSymptomResource(resource.Resource)
+		resource.Anomalies = scope.scopeAnomalies(resource.Anomalies)
+
+		path := make([]blastradius.Hop, len(resource.Path))
+		for j, hop := range resource.Path {
+			hop.Resource, _ = scope.maskSymptomResource(hop.Resource)
+			path[j] = hop
+		}
+		resource.Path = path
+		impacted[i] = resource
+	}
+	scoped.Impacted = impacted
+
+	groups := make([]blastradius.ImpactGroup, len(resp.Groups))
+	for i, group := range resp.Groups {
+		resources := make([]analysis.SymptomResource, len(group.Resources))
+		for j, resource := range group.Resources {
+			resources[j], _ = scope.maskSymptomResource(resource)
+		}
+		group.Resources = resources
+		groups[i] = group
+	}
+	scoped.Groups = groups
+	return &scoped
+}
+
+// ScopeNamespaceGraphResponse returns a copy of the response in which
+// resources outside of the caller's tenant scope are masked and cluster-scoped
+// resources are reduced. The input may be a shared cached response and is
+// never modified.
+func ScopeNamespaceGraphResponse(ctx context.Context, resp *namespacegraph.NamespaceGraphResponse) *namespacegraph.NamespaceGraphResponse {
+	scope := newNamespaceScope(ctx)
+	if scope == nil || resp == nil {
+		return resp
+	}
+
+	scoped := *resp
+	maskedUIDs := make(map[string]string)
+
+	nodes := make([]namespacegraph.Node, len(resp.Graph.Nodes))
+	for i, node := range resp.Graph.Nodes {
+		switch {
+		case !scope.allows(node.Namespace):
+			maskedUID := maskUID(node.UID)
+			maskedUIDs[node.UID] = maskedUID
+			node = namespacegraph.Node{
+				UID:       maskedUID,
+				Kind:      node.Kind,
+				Namespace: MaskedName,
+				Name:      MaskedName,
+				Status:    "unknown",
+			}
+		case scope.reduces(node.Namespace):
+			node.Labels = nil
+			if node.LatestEvent != nil {
+				event := *node.LatestEvent
+				event.ErrorMessage = ""
+				event.ContainerIssues = nil
+				event.SpecChanges = ""
+				node.LatestEvent = &event
+			}
+		}
+		nodes[i] = node
+	}
+
+	edges := make([]namespacegraph.Edge, len(resp.Graph.Edges))
+	for i, edge := range resp.Graph.Edges {
+		if uid, ok := maskedUIDs[edge.Source]; ok {
+			edge.Source = uid
+		}
+		if uid, ok := maskedUIDs[edge.Target]; ok {
+			edge.Target = uid
+		}
+		if edge.Source != resp.Graph.Edges[i].Source || edge.Target != resp.Graph.Edges[i].Target {
+			edge.ID = maskUID(edge.ID)
+		}
+		edges[i] = edge
+	}
+
+	scoped.Graph = namespacegraph.Graph{Nodes: nodes, Edges: edges}
+	scoped.Anomalies = scope.scopeAnomalies(resp.Anomalies)
+	scoped.CausalPaths = scope.scopeCausalPaths(resp.CausalPaths)
+	return &scoped
+}
+
+// ScopeRootCauseAnalysis returns a copy of the causal graph analysis in which
+// resources outside of the caller's tenant scope are masked and cluster-scoped
+// resources are reduced
+func ScopeRootCauseAnalysis(ctx context.Context, result *analysis.RootCauseAnalysisV2) *analysis.RootCauseAnalysisV2 {
+	scope := newNamespaceScope(ctx)
+	if scope == nil || result == nil {
+		return result
+	}
+
+	scoped := *result
+	var maskedNames []string
+	maskedIDs := make(map[string]string)
+
+	nodes := make([]analysis.GraphNode, len(result.Incident.Graph.Nodes))
+	for i, node := range result.Incident.Graph.Nodes {
+		namespace := node.Resource.Namespace
+		resource, masked := scope.maskSymptomResource(node.Resource)
+		switch {
+		case masked:
+			maskedNames = append(maskedNames, node.Resource.Name)
+			maskedIDs[node.ID] = maskUID(node.ID)
+			node = analysis.GraphNode{
+				ID:         maskedIDs[node.ID],
+				Resource:   resource,
+				NodeType:   node.NodeType,
+				StepNumber: node.StepNumber,
+			}
+		case scope.reduces(namespace):
+			if node.ChangeEvent != nil {
+				event := reduceChangeEvent(*node.ChangeEvent)
+				node.ChangeEvent = &event
+			}
+			if node.AllEvents != nil {
+				events := make([]analysis.ChangeEventInfo, len(node.AllEvents))
+				for j, event := range node.AllEvents {
+					events[j] = reduceChangeEvent(event)
+				}
+				node.AllEvents = events
+			}
+			if node.K8sEvents != nil {
+				events := make([]analysis.K8sEventInfo, len(node.K8sEvents))
+				for j, event := range node.K8sEvents {
+					event.Message = ""
+					event.Source = ""
+					events[j] = event
+				}
+				node.K8sEvents = events
+			}
+		}
+		nodes[i] = node
+	}
+
+	edges := make([]analysis.GraphEdge, len(result.Incident.Graph.Edges))
+	for i, edge := range result.Incident.Graph.Edges {
+		if id, ok := maskedIDs[edge.From]; ok {
+			edge.From = id
+		}
+		if id, ok := maskedIDs[edge.To]; ok {
+			edge.To = id
+		}
+		edges[i] = edge
+	}
+	scoped.Incident.Graph = analysis.CausalGraph{Nodes: nodes, Edges: edges}
+
+	if resource, masked := scope.maskSymptomResource(result.Incident.RootCause.Resource); masked {
+		scoped.Incident.RootCause = analysis.RootCauseHypothesis{
+			Resource:      resource,
+			CausationType: result.Incident.RootCause.CausationType,
+			TimeLagMs:     result.Incident.RootCause.TimeLagMs,
+			Explanation:   "The root cause is a resource outside of your tenant scope.",
+		}
+	}
+
+	alternatives := make([]analysis.ExcludedHypothesis, 0, len(result.ExcludedAlternatives))
+	for _, alt := range result.ExcludedAlternatives {
+		if _, masked := scope.maskSymptomResource(alt.Resource); masked {
+			continue
+		}
+		alternatives = append(alternatives, alt)
+	}
+	scoped.ExcludedAlternatives = alternatives
+
+	// Evidence is free text built from resource names, so drop any item that
+	// mentions a masked resource
+	evidence := make([]analysis.EvidenceItem, 0, len(result.SupportingEvidence))
+	for _, item := range result.SupportingEvidence {
+		if !mentionsAny(item.Description, maskedNames) && !mentionsAny(fmt.Sprintf("%v", item.Details), maskedNames) {
+			evidence = append(evidence, item)
+		}
+	}
+	scoped.SupportingEvidence = evidence
+
+	return &scoped
+}
+
+func mentionsAny(text string, names []string) bool {
+	for _, name := range names {
+		if name != "" && strings.Contains(text, name) {
+			return true
+		}
+	}
+	return false
+}
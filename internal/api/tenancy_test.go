@@ -0,0 +1,251 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/moolen/spectre/internal/analysis"
+	"github.com/moolen/spectre/internal/analysis/anomaly"
+	blastradius "github.com/moolen/spectre/internal/analysis/blast_radius"
+	causalpaths "github.com/moolen/spectre/internal/analysis/causal_paths"
+	namespacegraph "github.com/moolen/spectre/internal/analysis/namespace_graph"
+	"github.com/moolen/spectre/internal/auth"
+	"github.com/moolen/spectre/internal/models"
+)
+
+func tenantContext(namespaces ...string) context.Context {
+	if namespaces == nil {
+		namespaces = []string{}
+	}
+	return auth.WithIdentity(context.Background(), &auth.Identity{
+		Name:       "alice",
+		Role:       auth.RoleViewer,
+		Namespaces: namespaces,
+	})
+}
+
+func TestApplyTenantScope(t *testing.T) {
+	filters := models.QueryFilters{}
+	ApplyTenantScope(context.Background(), &filters)
+	if filters.IsScoped() {
+		t.Fatalf("expected unrestricted filters without an identity")
+	}
+
+	ApplyTenantScope(tenantContext("team-a"), &filters)
+	if !filters.IsScoped() {
+		t.Fatalf("expected scoped filters for a tenant identity")
+	}
+
+	tests := []struct {
+		namespace string
+		want      bool
+	}{
+		{namespace: "team-a", want: true},
+		{namespace: "team-b", want: false},
+		{namespace: "", want: true}, // cluster-scoped
+	}
+	for _, tt := range tests {
+		got := filters.Matches(models.ResourceMetadata{Kind: "Pod", Namespace: tt.namespace})
+		if got != tt.want {
+			t.Errorf("Matches(namespace=%q) = %v, want %v", tt.namespace, got, tt.want)
+		}
+	}
+}
+
+func TestScopeQuery(t *testing.T) {
+	query := &models.QueryRequest{StartTimestamp: 1, EndTimestamp: 2}
+
+	scoped := ScopeQuery(tenantContext("team-a"), query)
+	if !scoped.Filters.IsScoped() {
+		t.Fatalf("expected a scoped query for a tenant identity")
+	}
+	if query.Filters.IsScoped() {
+		t.Errorf("expected the original query to stay unrestricted")
+	}
+	if scoped.StartTimestamp != 1 || scoped.EndTimestamp != 2 {
+		t.Errorf("expected the time range to be kept, got %d-%d", scoped.StartTimestamp, scoped.EndTimestamp)
+	}
+}
+
+func TestFilterNamespaces(t *testing.T) {
+	all := []string{"", "team-a", "team-b", "kube-system"}
+
+	if got := FilterNamespaces(context.Background(), all); len(got) != len(all) {
+		t.Errorf("FilterNamespaces() without identity = %v, want %v", got, all)
+	}
+
+	got := FilterNamespaces(tenantContext("team-a"), all)
+	if len(got) != 1 || got[0] != "team-a" {
+		t.Errorf("FilterNamespaces() = %v, want [team-a]", got)
+	}
+}
+
+func TestCheckNamespaceAccess(t *testing.T) {
+	ctx := tenantContext("team-a")
+	if err := CheckNamespaceAccess(ctx, "team-a"); err != nil {
+		t.Errorf("CheckNamespaceAccess(team-a) error = %v", err)
+	}
+	if err := CheckNamespaceAccess(ctx, "team-b"); !errors.Is(err, ErrNamespaceForbidden) {
+		t.Errorf("CheckNamespaceAccess(team-b) error = %v, want ErrNamespaceForbidden", err)
+	}
+	if err := CheckNamespaceAccess(context.Background(), "team-b"); err != nil {
+		t.Errorf("CheckNamespaceAccess() without identity error = %v", err)
+	}
+}
+
+func TestScopeNamespaceGraphResponse(t *testing.T) {
+	resp := &namespacegraph.NamespaceGraphResponse{
+		Graph: namespacegraph.Graph{
+			Nodes: []namespacegraph.Node{
+				{UID: "pod-1", Kind: "Pod", Namespace: "team-a", Name: "web", Labels: map[string]string{"app": "web"}},
+				{UID: "svc-1", Kind: "Service", Namespace: "team-b", Name: "billing", Labels: map[string]string{"app": "billing"}},
+				{UID: "node-1", Kind: "Node", Name: "worker-1", Labels: map[string]string{"zone": "a"},
+					LatestEvent: &namespacegraph.ChangeEventInfo{Status: "Warning", ErrorMessage: "evicting team-b/billing"}},
+			},
+			Edges: []namespacegraph.Edge{
+				{ID: "e1", Source: "pod-1", Target: "svc-1", RelationshipType: "SELECTS"},
+				{ID: "e2", Source: "pod-1", Target: "node-1", RelationshipType: "SCHEDULED_ON"},
+			},
+		},
+		Anomalies: []anomaly.Anomaly{
+			{Node: anomaly.AnomalyNode{UID: "pod-1", Namespace: "team-a"}, Type: "CrashLoop"},
+			{Node: anomaly.AnomalyNode{UID: "svc-1", Namespace: "team-b"}, Type: "NoEndpoints"},
+			{Node: anomaly.AnomalyNode{UID: "node-1"}, Type: "DiskPressure", Details: map[string]interface{}{"pods": "team-b/billing"}},
+		},
+	}
+
+	scoped := ScopeNamespaceGraphResponse(tenantContext("team-a"), resp)
+
+	if scoped == resp {
+		t.Fatal("expected a copy of the response")
+	}
+	if resp.Graph.Nodes[1].Name != "billing" || resp.Graph.Edges[0].Target != "svc-1" {
+		t.Fatal("input response must not be modified")
+	}
+
+	pod, svc, node := scoped.Graph.Nodes[0], scoped.Graph.Nodes[1], scoped.Graph.Nodes[2]
+	if pod.Name != "web" || pod.Labels["app"] != "web" {
+		t.Errorf("in-scope node changed: %+v", pod)
+	}
+	if svc.Name != MaskedName || svc.Namespace != MaskedName || svc.UID == "svc-1" || svc.Labels != nil {
+		t.Errorf("out-of-scope node not masked: %+v", svc)
+	}
+	if svc.Kind != "Service" {
+		t.Errorf("masked node kind = %q, want Service", svc.Kind)
+	}
+	if node.Name != "worker-1" || node.Labels != nil || node.LatestEvent.ErrorMessage != "" || node.LatestEvent.Status != "Warning" {
+		t.Errorf("cluster-scoped node not reduced: %+v %+v", node, node.LatestEvent)
+	}
+
+	if scoped.Graph.Edges[0].Target != svc.UID {
+		t.Errorf("edge target = %q, want masked UID %q", scoped.Graph.Edges[0].Target, svc.UID)
+	}
+	if scoped.Graph.Edges[1].Target != "node-1" {
+		t.Errorf("edge to cluster-scoped node changed: %+v", scoped.Graph.Edges[1])
+	}
+
+	if len(scoped.Anomalies) != 2 {
+		t.Fatalf("anomalies = %+v, want 2 (out-of-scope dropped)", scoped.Anomalies)
+	}
+	if scoped.Anomalies[1].Details != nil {
+		t.Errorf("cluster-scoped anomaly details not stripped: %+v", scoped.Anomalies[1].Details)
+	}
+
+	if got := ScopeNamespaceGraphResponse(context.Background(), resp); got != resp {
+		t.Error("expected response to be returned unchanged without a tenant scope")
+	}
+}
+
+func TestScopeCausalPathsResponse(t *testing.T) {
+	root := causalpaths.PathNode{
+		ID:        "node-cm",
+		Resource:  analysis.SymptomResource{UID: "cm-1", Kind: "ConfigMap", Namespace: "shared", Name: "feature-flags"},
+		Anomalies: []anomaly.Anomaly{{Node: anomaly.AnomalyNode{UID: "cm-1", Namespace: "shared"}, Type: "ConfigChange"}},
+	}
+	symptom := causalpaths.PathNode{
+		ID:       "node-pod",
+		Resource: analysis.SymptomResource{UID: "pod-1", Kind: "Pod", Namespace: "team-a", Name: "web"},
+	}
+	resp := &causalpaths.CausalPathsResponse{
+		Paths: []causalpaths.CausalPath{{
+			CandidateRoot: root,
+			Steps:         []causalpaths.PathStep{{Node: root}, {Node: symptom}},
+			Explanation:   "ConfigMap 'shared/feature-flags' changed",
+		}},
+	}
+
+	scoped := ScopeCausalPathsResponse(tenantContext("team-a"), resp)
+	path := scoped.Paths[0]
+
+	if path.CandidateRoot.Resource.Name != MaskedName || len(path.CandidateRoot.Anomalies) != 0 {
+		t.Errorf("root cause not masked: %+v", path.CandidateRoot)
+	}
+	if path.Steps[0].Node.Resource.UID != path.CandidateRoot.Resource.UID {
+		t.Errorf("masked root UID differs between candidate root and first step")
+	}
+	if path.Steps[1].Node.Resource.Name != "web" {
+		t.Errorf("in-scope symptom changed: %+v", path.Steps[1].Node.Resource)
+	}
+	if path.Explanation == resp.Paths[0].Explanation {
+		t.Errorf("explanation still names the masked root cause: %q", path.Explanation)
+	}
+	if resp.Paths[0].CandidateRoot.Resource.Name != "feature-flags" {
+		t.Error("input response must not be modified")
+	}
+}
+
+func TestScopeBlastRadiusResponse(t *testing.T) {
+	node := analysis.SymptomResource{UID: "node-1", Kind: "Node", Name: "worker-1"}
+	own := analysis.SymptomResource{UID: "pod-1", Kind: "Pod", Namespace: "team-a", Name: "web"}
+	other := analysis.SymptomResource{UID: "pod-2", Kind: "Pod", Namespace: "team-b", Name: "billing"}
+	resp := &blastradius.BlastRadiusResponse{
+		Trigger: node,
+		Impacted: []blastradius.ImpactedResource{
+			{
+				Resource:  other,
+				Path:      []blastradius.Hop{{RelationshipType: "SCHEDULED_ON", Reverse: true, Resource: other}},
+				Anomalies: []anomaly.Anomaly{{Node: anomaly.AnomalyNode{UID: "pod-2", Namespace: "team-b"}, Type: "Evicted"}},
+			},
+			{
+				Resource: own,
+				Path:     []blastradius.Hop{{RelationshipType: "SCHEDULED_ON", Reverse: true, Resource: own}},
+			},
+		},
+		Groups: []blastradius.ImpactGroup{{RelationshipType: "SCHEDULED_ON", Distance: 1, Count: 2, Resources: []analysis.SymptomResource{other, own}}},
+	}
+
+	scoped := ScopeBlastRadiusResponse(tenantContext("team-a"), resp)
+
+	if scoped.Trigger.Name != "worker-1" {
+		t.Errorf("cluster-scoped trigger masked: %+v", scoped.Trigger)
+	}
+	masked := scoped.Impacted[0]
+	if masked.Resource.Name != MaskedName || len(masked.Anomalies) != 0 {
+		t.Errorf("out-of-scope resource not masked: %+v", masked)
+	}
+	if masked.Path[0].Resource.UID != masked.Resource.UID {
+		t.Errorf("masked UID differs between resource and path")
+	}
+	if scoped.Groups[0].Resources[0].UID != masked.Resource.UID || scoped.Groups[0].Resources[1].Name != "web" {
+		t.Errorf("group resources not scoped: %+v", scoped.Groups[0].Resources)
+	}
+	if scoped.Impacted[1].Resource.Name != "web" {
+		t.Errorf("in-scope resource changed: %+v", scoped.Impacted[1].Resource)
+	}
+	if resp.Impacted[0].Resource.Name != "billing" {
+		t.Error("input response must not be modified")
+	}
+}
+
+func TestScopeMetadataResponse(t *testing.T) {
+	cached := &models.MetadataResponse{Namespaces: []string{"team-a", "team-b"}, Kinds: []string{"Pod"}}
+
+	scoped := scopeMetadataResponse(tenantContext("team-a"), cached)
+	if len(scoped.Namespaces) != 1 || scoped.Namespaces[0] != "team-a" {
+		t.Errorf("Namespaces = %v, want [team-a]", scoped.Namespaces)
+	}
+	if len(cached.Namespaces) != 2 {
+		t.Error("cached response must not be modified")
+	}
+}
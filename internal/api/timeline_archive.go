@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"sort"
+
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// ExecutorFor returns the executor for a timeline query. When the graph is
+// the query source and the storage executor is an archive, queries whose
+// window starts at or before the archive horizon are served from both, as
+// the older part of the window may have expired from the graph.
+func (s *TimelineService) ExecutorFor(query *models.QueryRequest) QueryExecutor {
+	executor := s.GetActiveExecutor()
+	if s.querySource != TimelineQuerySourceGraph || s.graphExecutor == nil {
+		return executor
+	}
+
+	archive, ok := s.storageExecutor.(ArchiveExecutor)
+	if !ok {
+		return executor
+	}
+	horizon := archive.Horizon()
+	if horizon == 0 || query.StartTimestamp*1e9 > horizon {
+		return executor
+	}
+
+	s.logger.Debug("Query window starts before archive horizon %d, including archived events", horizon)
+	return &archiveFallbackExecutor{
+		graph:   executor,
+		archive: archive,
+		logger:  s.logger,
+	}
+}
+
+// archiveFallbackExecutor merges the results of the graph and the archive.
+// Events present in both are taken from the graph.
+type archiveFallbackExecutor struct {
+	graph   QueryExecutor
+	archive QueryExecutor
+	logger  *logging.Logger
+}
+
+// paginatedExecutor is implemented by executors that page results by resource
+type paginatedExecutor interface {
+	ExecutePaginated(ctx context.Context, query *models.QueryRequest, pagination *models.PaginationRequest) (*models.QueryResult, *models.PaginationResponse, error)
+}
+
+func (e *archiveFallbackExecutor) SetSharedCache(cache interface{}) {}
+
+func (e *archiveFallbackExecutor) Execute(ctx context.Context, query *models.QueryRequest) (*models.QueryResult, error) {
+	if query.Pagination != nil {
+		result, _, err := e.ExecutePaginated(ctx, query, query.Pagination)
+		return result, err
+	}
+
+	result, err := e.graph.Execute(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := e.archive.Execute(ctx, query)
+	if err != nil {
+		// The graph result is still valid, just not complete
+		e.logger.Warn("Failed to query archived events: %v", err)
+		return result, nil
+	}
+
+	return mergeQueryResults(result, archived), nil
+}
+
+// ExecutePaginated pages the merged results by resource, in the (kind,
+// namespace, name) order of the graph executor. Archived resources before the
+// cursor belong to earlier pages, and when the graph has more pages, those
+// after the graph's last resource belong to later ones.
+func (e *archiveFallbackExecutor) ExecutePaginated(ctx context.Context, query *models.QueryRequest, pagination *models.PaginationRequest) (*models.QueryResult, *models.PaginationResponse, error) {
+	var result *models.QueryResult
+	graphPagination := &models.PaginationResponse{PageSize: pagination.GetPageSize()}
+	var err error
+	if paginated, ok := e.graph.(paginatedExecutor); ok {
+		result, graphPagination, err = paginated.ExecutePaginated(ctx, query, pagination)
+	} else {
+		unpaged := *query
+		unpaged.Pagination = nil
+		result, err = e.graph.Execute(ctx, &unpaged)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unpaged := *query
+	unpaged.Pagination = nil
+	archived, err := e.archive.Execute(ctx, &unpaged)
+	if err != nil {
+		e.logger.Warn("Failed to query archived events: %v", err)
+		return result, graphPagination, nil
+	}
+
+	cursor, _ := models.DecodeCursor(pagination.Cursor)
+	var bound *models.ResourceCursor
+	if graphPagination.HasMore {
+		bound, _ = models.DecodeCursor(graphPagination.NextCursor)
+	}
+	inRange := archived.Events[:0]
+	for _, event := range archived.Events {
+		r := event.Resource
+		if cursor != nil && cursor.Compare(r.Kind, r.Namespace, r.Name) >= 0 {
+			continue
+		}
+		if bound != nil && bound.Compare(r.Kind, r.Namespace, r.Name) < 0 {
+			continue
+		}
+		inRange = append(inRange, event)
+	}
+	archived.Events = inRange
+
+	merged := mergeQueryResults(result, archived)
+	return pageByResource(merged, pagination.GetPageSize(), graphPagination)
+}
+
+// pageByResource keeps the events of the first pageSize resources of a
+// result. Otherwise the pagination of the underlying query stands.
+func pageByResource(result *models.QueryResult, pageSize int, pagination *models.PaginationResponse) (*models.QueryResult, *models.PaginationResponse, error) {
+	resources := make(map[string]models.ResourceMetadata)
+	for _, event := range result.Events {
+		resources[event.Resource.UID] = event.Resource
+	}
+	if len(resources) <= pageSize {
+		return result, pagination, nil
+	}
+
+	sorted := make([]models.ResourceMetadata, 0, len(resources))
+	for _, resource := range resources {
+		sorted = append(sorted, resource)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a := models.NewResourceCursor(sorted[i].Kind, sorted[i].Namespace, sorted[i].Name)
+		return a.Compare(sorted[j].Kind, sorted[j].Namespace, sorted[j].Name) < 0
+	})
+
+	page := make(map[string]bool, pageSize)
+	for _, resource := range sorted[:pageSize] {
+		page[resource.UID] = true
+	}
+	events := result.Events[:0]
+	for _, event := range result.Events {
+		if page[event.Resource.UID] {
+			events = append(events, event)
+		}
+	}
+	result.Events = events
+	result.Count = int32(len(events)) // #nosec G115 -- bounded by the page size
+	for uid := range result.K8sEventsByResource {
+		if !page[uid] {
+			delete(result.K8sEventsByResource, uid)
+		}
+	}
+
+	last := sorted[pageSize-1]
+	return result, &models.PaginationResponse{
+		NextCursor: models.NewResourceCursor(last.Kind, last.Namespace, last.Name).Encode(),
+		HasMore:    true,
+		PageSize:   pageSize,
+	}, nil
+}
+
+// mergeQueryResults adds the events of archived to result that it doesn't
+// already contain
+func mergeQueryResults(result, archived *models.QueryResult) *models.QueryResult {
+	seen := make(map[string]bool, len(result.Events))
+	for _, event := range result.Events {
+		seen[event.ID] = true
+	}
+	for _, event := range archived.Events {
+		if !seen[event.ID] {
+			seen[event.ID] = true
+			result.Events = append(result.Events, event)
+		}
+	}
+	result.Count = int32(len(result.Events)) // #nosec G115 -- bounded by the query window
+	result.ExecutionTimeMs = max(result.ExecutionTimeMs, archived.ExecutionTimeMs)
+
+	if len(archived.K8sEventsByResource) > 0 && result.K8sEventsByResource == nil {
+		result.K8sEventsByResource = make(map[string][]models.K8sEvent)
+	}
+	for uid, events := range archived.K8sEventsByResource {
+		known := make(map[string]bool, len(result.K8sEventsByResource[uid]))
+		for _, event := range result.K8sEventsByResource[uid] {
+			known[event.ID] = true
+		}
+		for _, event := range events {
+			if !known[event.ID] {
+				result.K8sEventsByResource[uid] = append(result.K8sEventsByResource[uid], event)
+			}
+		}
+	}
+	return result
+}
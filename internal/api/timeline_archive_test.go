@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// staticExecutor returns the same result for every query
+type staticExecutor struct {
+	result  *models.QueryResult
+	horizon int64
+}
+
+func (e *staticExecutor) Execute(ctx context.Context, query *models.QueryRequest) (*models.QueryResult, error) {
+	result := *e.result
+	result.Events = append([]models.Event(nil), e.result.Events...)
+	return &result, nil
+}
+
+func (e *staticExecutor) SetSharedCache(cache interface{}) {}
+
+func (e *staticExecutor) Horizon() int64 { return e.horizon }
+
+func TestTimelineService_ExecutorFor(t *testing.T) {
+	now := time.Now()
+	graphExecutor := &staticExecutor{
+		result: &models.QueryResult{
+			Events: []models.Event{{ID: "recent", Resource: models.ResourceMetadata{UID: "pod"}}},
+			K8sEventsByResource: map[string][]models.K8sEvent{
+				"pod": {{ID: "k-recent"}},
+			},
+		},
+	}
+	archiveExecutor := &staticExecutor{
+		result: &models.QueryResult{
+			Events: []models.Event{
+				{ID: "recent", Resource: models.ResourceMetadata{UID: "pod"}},
+				{ID: "archived", Resource: models.ResourceMetadata{UID: "pod"}},
+			},
+			K8sEventsByResource: map[string][]models.K8sEvent{
+				"pod": {{ID: "k-recent"}, {ID: "k-archived"}},
+			},
+		},
+		horizon: now.Add(-24 * time.Hour).UnixNano(),
+	}
+	service := NewTimelineServiceWithMode(archiveExecutor, graphExecutor, TimelineQuerySourceGraph,
+		logging.GetLogger("test"), noop.NewTracerProvider().Tracer("test"))
+
+	// Windows after the horizon only query the graph
+	recent := &models.QueryRequest{StartTimestamp: now.Add(-time.Hour).Unix(), EndTimestamp: now.Unix()}
+	assert.Same(t, graphExecutor, service.ExecutorFor(recent))
+
+	old := &models.QueryRequest{StartTimestamp: now.Add(-48 * time.Hour).Unix(), EndTimestamp: now.Unix()}
+	result, err := service.ExecutorFor(old).Execute(context.Background(), old)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result.Events))
+	for _, event := range result.Events {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"recent", "archived"}, ids)
+	assert.Equal(t, int32(2), result.Count)
+	assert.Len(t, result.K8sEventsByResource["pod"], 2)
+
+	// Without archived data there is nothing to fall back to
+	archiveExecutor.horizon = 0
+	assert.Same(t, graphExecutor, service.ExecutorFor(old))
+}
+
+// pagedExecutor pages its events by resource like the graph executor
+type pagedExecutor struct {
+	staticExecutor
+}
+
+func (e *pagedExecutor) ExecutePaginated(ctx context.Context, query *models.QueryRequest, pagination *models.PaginationRequest) (*models.QueryResult, *models.PaginationResponse, error) {
+	cursor, _ := models.DecodeCursor(pagination.Cursor)
+	result := &models.QueryResult{}
+	var last models.ResourceMetadata
+	for _, event := range e.result.Events {
+		r := event.Resource
+		if cursor != nil && cursor.Compare(r.Kind, r.Namespace, r.Name) >= 0 {
+			continue
+		}
+		if len(result.Events) == pagination.GetPageSize() {
+			return result, &models.PaginationResponse{
+				NextCursor: models.NewResourceCursor(last.Kind, last.Namespace, last.Name).Encode(),
+				HasMore:    true,
+				PageSize:   pagination.GetPageSize(),
+			}, nil
+		}
+		result.Events = append(result.Events, event)
+		last = r
+	}
+	return result, &models.PaginationResponse{PageSize: pagination.GetPageSize()}, nil
+}
+
+func TestArchiveFallbackExecutor_Pagination(t *testing.T) {
+	pod := func(name string) models.Event {
+		return models.Event{ID: name, Resource: models.ResourceMetadata{Kind: "Pod", Namespace: "default", Name: name, UID: name}}
+	}
+	// The graph holds a, c and e, the archive b, d and f
+	executor := &archiveFallbackExecutor{
+		graph:   &pagedExecutor{staticExecutor{result: &models.QueryResult{Events: []models.Event{pod("a"), pod("c"), pod("e")}}}},
+		archive: &staticExecutor{result: &models.QueryResult{Events: []models.Event{pod("b"), pod("d"), pod("f")}}},
+		logger:  logging.GetLogger("test"),
+	}
+
+	var pages [][]string
+	pagination := &models.PaginationRequest{PageSize: 2}
+	for len(pages) < 5 {
+		result, resp, err := executor.ExecutePaginated(context.Background(), &models.QueryRequest{}, pagination)
+		require.NoError(t, err)
+
+		var names []string
+		for _, event := range result.Events {
+			names = append(names, event.Resource.Name)
+		}
+		sort.Strings(names)
+		pages = append(pages, names)
+
+		if !resp.HasMore {
+			break
+		}
+		pagination = &models.PaginationRequest{PageSize: 2, Cursor: resp.NextCursor}
+	}
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}}, pages)
+}
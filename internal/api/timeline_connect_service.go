@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/moolen/spectre/internal/api/pb"
@@ -19,6 +20,7 @@ import (
 type TimelineConnectService struct {
 	pbconnect.UnimplementedTimelineServiceHandler
 	service *TimelineService
+	watcher *TimelineWatcher // nil when live updates are unavailable
 }
 
 // NewTimelineConnectService creates a new timeline Connect service with storage executor only
@@ -35,6 +37,11 @@ func NewTimelineConnectServiceWithMode(storageExecutor, graphExecutor QueryExecu
 	}
 }
 
+// EnableWatch enables WatchTimeline using the given watcher
+func (s *TimelineConnectService) EnableWatch(watcher *TimelineWatcher) {
+	s.watcher = watcher
+}
+
 // GetTimeline implements the Connect streaming endpoint
 func (s *TimelineConnectService) GetTimeline(
 	ctx context.Context,
@@ -49,6 +56,7 @@ func (s *TimelineConnectService) GetTimeline(
 			attribute.Int64("query.end_timestamp", req.Msg.EndTimestamp),
 			attribute.StringSlice("query.namespaces", req.Msg.Namespaces),
 			attribute.StringSlice("query.kinds", req.Msg.Kinds),
+			attribute.StringSlice("query.clusters", req.Msg.Clusters),
 			attribute.Int("query.page_size", int(req.Msg.PageSize)),
 		),
 	)
@@ -64,9 +72,12 @@ func (s *TimelineConnectService) GetTimeline(
 		return connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
+	// Restrict the query to the caller's tenant namespaces
+	ApplyTenantScope(ctx, &query.Filters)
+
 	// Execute queries with pagination support
 	// Check if the executor supports pagination (graph executor does, storage doesn't yet)
-	executor := s.service.GetActiveExecutor()
+	executor := s.service.ExecutorFor(query)
 	if executor == nil {
 		span.RecordError(fmt.Errorf("no query executor available"))
 		span.SetStatus(codes.Error, "No executor available")
@@ -116,9 +127,11 @@ func (s *TimelineConnectService) GetTimeline(
 			StartTimestamp: query.StartTimestamp,
 			EndTimestamp:   query.EndTimestamp,
 			Filters: models.QueryFilters{
-				Kinds:      []string{"Event"},
-				Version:    "v1",
-				Namespaces: query.Filters.GetNamespaces(),
+				Kinds:             []string{"Event"},
+				Version:           "v1",
+				Namespaces:        query.Filters.GetNamespaces(),
+				Clusters:          query.Filters.Clusters,
+				AllowedNamespaces: query.Filters.AllowedNamespaces,
 			},
 		}
 		var eventErr error
@@ -280,6 +293,96 @@ func (s *TimelineConnectService) GetTimeline(
 	return nil
 }
 
+// WatchTimeline streams the timeline snapshot like GetTimeline without
+// pagination, then keeps the stream open and sends a delta chunk whenever the
+// sync pipeline writes changes matching the request's filters. The end
+// timestamp only bounds the snapshot.
+func (s *TimelineConnectService) WatchTimeline(
+	ctx context.Context,
+	req *connect.Request[pb.TimelineRequest],
+	stream *connect.ServerStream[pb.TimelineChunk],
+) error {
+	ctx, span := s.service.Tracer().Start(ctx, "connect.WatchTimeline",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.Int64("query.start_timestamp", req.Msg.StartTimestamp),
+			attribute.StringSlice("query.namespaces", req.Msg.Namespaces),
+			attribute.StringSlice("query.kinds", req.Msg.Kinds),
+			attribute.StringSlice("query.clusters", req.Msg.Clusters),
+		),
+	)
+	defer span.End()
+
+	if s.watcher == nil {
+		return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("live timeline updates require the graph sync pipeline"))
+	}
+
+	query, _, err := s.protoToQueryRequest(req.Msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid request")
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	watch, resourceResult, snapshot, err := s.service.WatchTimeline(ctx, s.watcher, query)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Query execution failed")
+		s.service.Logger().Error("Connect watch snapshot failed: %v", err)
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	defer watch.Close()
+
+	if err := s.sendMetadata(stream, resourceResult, snapshot.Count, nil); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	groups := groupAndSortResources(snapshot.Resources)
+	if len(groups) == 0 {
+		groups = []*GroupedResources{{Resources: []models.Resource{}}}
+	}
+	if err := s.streamResourceBatches(stream, groups); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+
+	for {
+		delta, err := watch.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			span.RecordError(err)
+			s.service.Logger().Warn("Timeline watch ended: %v", err)
+			return connect.NewError(connect.CodeResourceExhausted, err)
+		}
+
+		if err := stream.Send(s.deltaToProto(delta)); err != nil {
+			return connect.NewError(connect.CodeInternal, err)
+		}
+	}
+}
+
+// deltaToProto converts a timeline delta into a delta chunk
+func (s *TimelineConnectService) deltaToProto(delta *TimelineDelta) *pb.TimelineChunk {
+	pbResources := make([]*pb.TimelineResource, len(delta.Resources))
+	for i := range delta.Resources {
+		pbResources[i] = s.service.ResourceToProto(&delta.Resources[i])
+		// Segment IDs are indexes within the message; key them by start
+		// time instead so they don't collide with the snapshot's
+		for _, seg := range pbResources[i].StatusSegments {
+			seg.Id = fmt.Sprintf("%s-%d", seg.ResourceId, seg.StartTime)
+		}
+	}
+
+	return &pb.TimelineChunk{
+		ChunkType: &pb.TimelineChunk_Delta{
+			Delta: &pb.TimelineDelta{
+				Resources: pbResources,
+				Timestamp: delta.Timestamp,
+			},
+		},
+	}
+}
+
 // sendMetadata sends the metadata chunk with count, query stats, and pagination info
 func (s *TimelineConnectService) sendMetadata(stream *connect.ServerStream[pb.TimelineChunk], result *models.QueryResult, totalCount int, pagination *models.PaginationResponse) error {
 	metadata := &pb.TimelineMetadata{
@@ -361,6 +464,8 @@ func (s *TimelineConnectService) protoToQueryRequest(req *pb.TimelineRequest) (*
 	filters := models.QueryFilters{
 		Kinds:      kinds,
 		Namespaces: namespaces,
+		Clusters:   req.Clusters,
+		Query:      strings.TrimSpace(req.Query),
 		// Note: Name and LabelSelector are not currently supported by QueryFilters
 		// They would need to be added to the models.QueryFilters struct if needed
 	}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/moolen/spectre/internal/api/pb"
 	"github.com/moolen/spectre/internal/logging"
@@ -179,6 +180,8 @@ func (s *TimelineGRPCService) protoToQueryRequest(req *pb.TimelineRequest) (*mod
 	filters := models.QueryFilters{
 		Kind:      req.Kind,
 		Namespace: req.Namespace,
+		Clusters:  req.Clusters,
+		Query:     strings.TrimSpace(req.Query),
 		// Note: Name and LabelSelector are not currently supported by QueryFilters
 		// They would need to be added to the models.QueryFilters struct if needed
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 	"sync"
@@ -76,6 +77,7 @@ func (s *TimelineService) ResourceToProto(res *models.Resource) *pb.TimelineReso
 		ApiVersion:  fmt.Sprintf("%s/%s", res.Group, res.Version),
 		Namespace:   res.Namespace,
 		Name:        res.Name,
+		Cluster:     res.Cluster,
 		PreExisting: res.PreExisting,
 		Labels:      make(map[string]string),
 	}
@@ -195,13 +197,16 @@ func (s *TimelineService) ExecuteConcurrentQueries(ctx context.Context, query *m
 	defer span.End()
 
 	// Select which executor to use
-	executor := s.GetActiveExecutor()
+	executor := s.ExecutorFor(query)
 	if executor == nil {
 		return nil, nil, fmt.Errorf("no query executor available")
 	}
 
 	span.SetAttributes(attribute.String("query.source", string(s.querySource)))
 
+	// Restrict both queries to the caller's tenant namespaces
+	query = ScopeQuery(ctx, query)
+
 	var (
 		resourceResult *models.QueryResult
 		eventResult    *models.QueryResult
@@ -214,14 +219,16 @@ func (s *TimelineService) ExecuteConcurrentQueries(ctx context.Context, query *m
 	// Graph queries are handled differently and don't require shared cache
 
 	// Build Event query upfront
-	// Use same namespaces filter as the resource query
+	// Use same namespace and cluster filters as the resource query
 	eventQuery := &models.QueryRequest{
 		StartTimestamp: query.StartTimestamp,
 		EndTimestamp:   query.EndTimestamp,
 		Filters: models.QueryFilters{
-			Kinds:      []string{"Event"},
-			Version:    "v1",
-			Namespaces: query.Filters.GetNamespaces(),
+			Kinds:             []string{"Event"},
+			Version:           "v1",
+			Namespaces:        query.Filters.GetNamespaces(),
+			Clusters:          query.Filters.Clusters,
+			AllowedNamespaces: query.Filters.AllowedNamespaces,
 		},
 	}
 
@@ -316,12 +323,16 @@ func (s *TimelineService) ParseQueryParameters(ctx context.Context, startStr, en
 	// Support both ?kind=Pod&kind=Deployment and ?kinds=Pod,Deployment
 	kinds := parseMultiValueParam(filterParams, "kind", "kinds")
 	namespaces := parseMultiValueParam(filterParams, "namespace", "namespaces")
+	clusters := ParseClusterParams(filterParams)
 
 	filters := models.QueryFilters{
 		Group:      getSingleParam(filterParams, "group"),
 		Version:    getSingleParam(filterParams, "version"),
 		Kinds:      kinds,
 		Namespaces: namespaces,
+		Clusters:   clusters,
+		Query:      strings.TrimSpace(getSingleParam(filterParams, "q")),
+		Actor:      strings.TrimSpace(getSingleParam(filterParams, "actor")),
 	}
 
 	if err := s.validator.ValidateFilters(filters); err != nil {
@@ -345,10 +356,11 @@ func (s *TimelineService) ParseQueryParameters(ctx context.Context, startStr, en
 		attribute.Int64("query.end", end),
 		attribute.StringSlice("query.kinds", kinds),
 		attribute.StringSlice("query.namespaces", namespaces),
+		attribute.StringSlice("query.clusters", clusters),
 	)
 
-	s.logger.Debug("Parsed query parameters: start=%d, end=%d, kinds=%v, namespaces=%v",
-		start, end, kinds, namespaces)
+	s.logger.Debug("Parsed query parameters: start=%d, end=%d, kinds=%v, namespaces=%v, clusters=%v",
+		start, end, kinds, namespaces, clusters)
 
 	return queryRequest, nil
 }
@@ -423,11 +435,13 @@ func (s *TimelineService) BuildTimelineResponse(queryResult, eventResult *models
 			Kind:      firstEvent.Resource.Kind,
 			Namespace: firstEvent.Resource.Namespace,
 			Name:      firstEvent.Resource.Name,
+			Cluster:   firstEvent.Resource.Cluster,
 			Events:    []models.K8sEvent{},
 		}
 
 		// Build status segments from events
 		var segments []models.StatusSegment
+		var managers map[string]string
 		for i, event := range events {
 			// Infer status from resource data
 			status := analyzer.InferStatusFromResource(event.Resource.Kind, event.Data, string(event.Type))
@@ -445,6 +459,11 @@ func (s *TimelineService) BuildTimelineResponse(queryResult, eventResult *models
 				EndTime:      endTime,
 				Status:       status,
 				ResourceData: event.Data, // Include full resource data for container issue analysis
+				Actor:        event.Actor,
+			}
+			if !maps.Equal(event.FieldManagers, managers) {
+				segment.FieldManagers = event.FieldManagers
+				managers = event.FieldManagers
 			}
 
 			// Extract error message from resource data if available
@@ -594,6 +613,24 @@ func parseMultiValueParam(params map[string][]string, singularName, pluralName s
 	return nil
 }
 
+// ParseClusterParams parses the cluster filter of a request
+// e.g., ?cluster=prod-eu&cluster=prod-us or ?clusters=prod-eu,prod-us
+func ParseClusterParams(params map[string][]string) []string {
+	return parseMultiValueParam(params, "cluster", "clusters")
+}
+
+// ParseNamespaceParams parses the namespace filter of a request
+// e.g., ?namespace=default&namespace=payments or ?namespaces=default,payments
+func ParseNamespaceParams(params map[string][]string) []string {
+	return parseMultiValueParam(params, "namespace", "namespaces")
+}
+
+// ParseKindParams parses the kind filter of a request
+// e.g., ?kind=Pod&kind=Deployment or ?kinds=Pod,Deployment
+func ParseKindParams(params map[string][]string) []string {
+	return parseMultiValueParam(params, "kind", "kinds")
+}
+
 // getSingleParam gets a single parameter value from the map
 func getSingleParam(params map[string][]string, name string) string {
 	if values, ok := params[name]; ok && len(values) > 0 {
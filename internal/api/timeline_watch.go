@@ -0,0 +1,393 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/searchql"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// TimelineWatcher turns the batches the sync pipeline writes to the graph
+// into incremental updates of timeline queries
+type TimelineWatcher struct {
+	pipeline sync.Pipeline
+	logger   *logging.Logger
+}
+
+// NewTimelineWatcher creates a watcher following the given pipeline
+func NewTimelineWatcher(pipeline sync.Pipeline, logger *logging.Logger) *TimelineWatcher {
+	return &TimelineWatcher{
+		pipeline: pipeline,
+		logger:   logger,
+	}
+}
+
+// TimelineDelta holds the status segments and Kubernetes events added to the
+// resources of a watched timeline. Resources carry only the new segments and
+// events; a resource that was not on the timeline before is added to it.
+type TimelineDelta struct {
+	Resources []models.Resource `json:"resources"`
+	Timestamp int64             `json:"timestamp"` // newest change in the delta, Unix nanoseconds
+}
+
+// TimelineWatch is an open watch of a timeline query
+type TimelineWatch struct {
+	filters models.QueryFilters
+	query   *searchql.Query // nil without a q filter
+	sub     *sync.Subscription
+
+	// known holds the resources on the client's timeline. Once a resource is
+	// on the timeline, all of its changes and events are streamed.
+	known map[string]bool
+
+	// lastData holds the latest data of the resources that pass the filters
+	// but aren't on the timeline yet, used to check the changed: terms of the
+	// query. It stays empty for queries without changed: terms.
+	lastData map[string][]byte
+}
+
+// Watch starts watching the timeline of a query. The subscription starts
+// before the caller queries the snapshot so that no batch is lost in between;
+// the first deltas may therefore repeat segments of the snapshot.
+func (w *TimelineWatcher) Watch(query *models.QueryRequest) (*TimelineWatch, error) {
+	watch := &TimelineWatch{
+		filters:  query.Filters,
+		known:    make(map[string]bool),
+		lastData: make(map[string][]byte),
+	}
+
+	if q := strings.TrimSpace(query.Filters.Query); q != "" {
+		parsed, err := searchql.Parse(q)
+		if err != nil {
+			return nil, err
+		}
+		watch.query = parsed
+	}
+
+	watch.sub = w.pipeline.Subscribe()
+	w.logger.Debug("Timeline watch started (filters: %s)", query.Filters.String())
+	return watch, nil
+}
+
+// WatchTimeline starts watching a query and returns the watch together with
+// the snapshot its deltas apply to. The caller closes the watch.
+func (s *TimelineService) WatchTimeline(ctx context.Context, watcher *TimelineWatcher, query *models.QueryRequest) (*TimelineWatch, *models.QueryResult, *models.SearchResponse, error) {
+	// Scope the watch like the snapshot queries
+	query = ScopeQuery(ctx, query)
+
+	watch, err := watcher.Watch(query)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	resourceResult, eventResult, err := s.ExecuteConcurrentQueries(ctx, query)
+	if err != nil {
+		watch.Close()
+		return nil, nil, nil, err
+	}
+
+	snapshot := s.BuildTimelineResponse(resourceResult, eventResult)
+	watch.Seed(snapshot.Resources)
+	return watch, resourceResult, snapshot, nil
+}
+
+// Seed adds the resources of the snapshot to the timeline
+func (tw *TimelineWatch) Seed(resources []models.Resource) {
+	for _, resource := range resources {
+		uid := resourceUIDFromID(resource.ID)
+		if uid == "" {
+			continue
+		}
+		tw.known[uid] = true
+	}
+}
+
+// Next blocks until a processed batch changes the timeline and returns the
+// delta. It returns ctx.Err() when the context ends and
+// sync.ErrSubscriptionOverflow when the watch fell too far behind.
+func (tw *TimelineWatch) Next(ctx context.Context) (*TimelineDelta, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case updates, ok := <-tw.sub.C:
+			if !ok {
+				if err := tw.sub.Err(); err != nil {
+					return nil, err
+				}
+				return nil, fmt.Errorf("timeline watch closed")
+			}
+			if delta := tw.apply(updates); delta != nil {
+				return delta, nil
+			}
+		}
+	}
+}
+
+// Close stops the watch
+func (tw *TimelineWatch) Close() {
+	tw.sub.Close()
+}
+
+// watchedResource collects the nodes of one resource in a batch
+type watchedResource struct {
+	identity     graph.ResourceIdentity
+	partial      bool // identity was built from an involvedObject, without labels
+	changeEvents []graph.ChangeEvent
+	k8sEvents    []graph.K8sEvent
+}
+
+// apply computes the delta of a batch, or nil when nothing on the timeline changed
+func (tw *TimelineWatch) apply(updates []*sync.GraphUpdate) *TimelineDelta {
+	resources := make(map[string]*watchedResource)
+	var order []string
+	get := func(identity graph.ResourceIdentity, partial bool) *watchedResource {
+		r, ok := resources[identity.UID]
+		if !ok {
+			r = &watchedResource{identity: identity, partial: partial}
+			resources[identity.UID] = r
+			order = append(order, identity.UID)
+		} else if r.partial && !partial {
+			r.identity = identity
+			r.partial = false
+		}
+		return r
+	}
+
+	for _, update := range updates {
+		if len(update.ResourceNodes) == 0 {
+			continue
+		}
+		if len(update.EventNodes) > 0 {
+			r := get(update.ResourceNodes[0], false)
+			r.changeEvents = append(r.changeEvents, update.EventNodes...)
+			continue
+		}
+		// A Kubernetes Event is attached to the object it's about, whose
+		// identity is built from the Event's involvedObject and follows the
+		// Event's own identity
+		if len(update.K8sEventNodes) > 0 && len(update.ResourceNodes) > 1 {
+			r := get(update.ResourceNodes[1], true)
+			r.k8sEvents = append(r.k8sEvents, update.K8sEventNodes...)
+		}
+	}
+
+	delta := &TimelineDelta{Resources: []models.Resource{}}
+	for _, uid := range order {
+		r := resources[uid]
+		sort.SliceStable(r.changeEvents, func(i, j int) bool {
+			return r.changeEvents[i].Timestamp < r.changeEvents[j].Timestamp
+		})
+
+		if !tw.known[uid] && !tw.matches(r) {
+			tw.rememberData(r)
+			continue
+		}
+		// Resources on the timeline aren't matched again
+		tw.known[uid] = true
+		delete(tw.lastData, uid)
+
+		for _, event := range r.changeEvents {
+			delta.Timestamp = max(delta.Timestamp, event.Timestamp)
+		}
+		for _, event := range r.k8sEvents {
+			delta.Timestamp = max(delta.Timestamp, event.Timestamp)
+		}
+		delta.Resources = append(delta.Resources, *tw.resource(r))
+	}
+
+	if len(delta.Resources) == 0 {
+		return nil
+	}
+	for i := range delta.Resources {
+		segments := delta.Resources[i].StatusSegments
+		if n := len(segments); n > 0 {
+			segments[n-1].EndTime = delta.Timestamp
+		}
+	}
+	return delta
+}
+
+// matches checks a resource that isn't on the timeline yet against the
+// filters of the watch
+func (tw *TimelineWatch) matches(r *watchedResource) bool {
+	if !tw.filters.Matches(r.metadata()) {
+		return false
+	}
+	if tw.filters.Actor != "" && !tw.changedByActor(r) {
+		return false
+	}
+	if tw.query == nil {
+		return true
+	}
+	return tw.query.Match(tw.record(r))
+}
+
+// rememberData keeps the latest data of a resource that didn't match, so that
+// the changed: terms of the query can be checked against its next change.
+// Resources outside of the filters can never match and deleted resources
+// don't change anymore, so neither is kept.
+func (tw *TimelineWatch) rememberData(r *watchedResource) {
+	if tw.query == nil || !tw.query.HasChangedTerms() {
+		return
+	}
+	uid := r.identity.UID
+	n := len(r.changeEvents)
+	if n == 0 {
+		return
+	}
+	latest := r.changeEvents[n-1]
+	if latest.EventType == string(models.EventTypeDelete) || r.identity.Deleted || !tw.filters.Matches(r.metadata()) {
+		delete(tw.lastData, uid)
+		return
+	}
+	if latest.Data != "" {
+		tw.lastData[uid] = []byte(latest.Data)
+	}
+}
+
+// metadata returns the metadata the filters are checked against
+func (r *watchedResource) metadata() models.ResourceMetadata {
+	return models.ResourceMetadata{
+		Group:     r.identity.APIGroup,
+		Version:   r.identity.Version,
+		Kind:      r.identity.Kind,
+		Namespace: r.identity.Namespace,
+		Name:      r.identity.Name,
+		UID:       r.identity.UID,
+		Cluster:   r.identity.Cluster,
+	}
+}
+
+// changedByActor reports whether one of the changes of the batch was made by
+// the actor of the filters. Actors are usually correlated after the change was
+// processed, so new resources are rarely added to a timeline filtered by actor.
+func (tw *TimelineWatch) changedByActor(r *watchedResource) bool {
+	for _, event := range r.changeEvents {
+		if graph.ChangeEventActor(event).Matches(tw.filters.Actor) {
+			return true
+		}
+	}
+	return false
+}
+
+// record converts the nodes of a resource to the property maps matched by
+// search queries. Resources only known from an event's involvedObject have
+// no labels, so label terms don't match them until they change.
+func (tw *TimelineWatch) record(r *watchedResource) *searchql.Record {
+	labels := ""
+	if !r.partial {
+		encoded, _ := json.Marshal(r.identity.Labels)
+		labels = string(encoded)
+	}
+
+	record := &searchql.Record{
+		Resource: map[string]interface{}{
+			"uid":       r.identity.UID,
+			"cluster":   r.identity.Cluster,
+			"kind":      r.identity.Kind,
+			"apiGroup":  r.identity.APIGroup,
+			"version":   r.identity.Version,
+			"namespace": r.identity.Namespace,
+			"name":      r.identity.Name,
+			"labels":    labels,
+			"deleted":   r.identity.Deleted,
+		},
+		Previous: tw.lastData[r.identity.UID],
+	}
+	for _, event := range r.changeEvents {
+		record.ChangeEvents = append(record.ChangeEvents, map[string]interface{}{
+			"eventType":       event.EventType,
+			"status":          event.Status,
+			"errorMessage":    event.ErrorMessage,
+			"containerIssues": event.ContainerIssues,
+			"data":            event.Data,
+		})
+	}
+	for _, event := range r.k8sEvents {
+		record.K8sEvents = append(record.K8sEvents, map[string]interface{}{
+			"reason":  event.Reason,
+			"message": event.Message,
+			"type":    event.Type,
+		})
+	}
+	return record
+}
+
+// resource builds the timeline resource holding the new segments and events
+func (tw *TimelineWatch) resource(r *watchedResource) *models.Resource {
+	id := r.identity
+	resource := &models.Resource{
+		ID:        fmt.Sprintf("%s/%s/%s/%s", id.APIGroup, id.Version, id.Kind, id.UID),
+		Group:     id.APIGroup,
+		Version:   id.Version,
+		Kind:      id.Kind,
+		Namespace: id.Namespace,
+		Name:      id.Name,
+		Cluster:   id.Cluster,
+		Events:    []models.K8sEvent{},
+	}
+	reduce := tw.filters.IsScoped() && id.Namespace == ""
+
+	var managers map[string]string
+	for i, event := range r.changeEvents {
+		segment := models.StatusSegment{
+			StartTime: event.Timestamp,
+			EndTime:   event.Timestamp,
+			Status:    event.Status,
+			Message:   event.ErrorMessage,
+			Actor:     graph.ChangeEventActor(event),
+		}
+		if eventManagers := graph.ChangeEventFieldManagers(event); !maps.Equal(eventManagers, managers) {
+			segment.FieldManagers = eventManagers
+			managers = eventManagers
+		}
+		if i < len(r.changeEvents)-1 {
+			segment.EndTime = r.changeEvents[i+1].Timestamp
+		}
+		if event.Data != "" {
+			segment.ResourceData = json.RawMessage(event.Data)
+		}
+		if reduce {
+			segment.ResourceData = models.ReduceResourceData(segment.ResourceData)
+			segment.Message = ""
+		}
+		resource.StatusSegments = append(resource.StatusSegments, segment)
+	}
+
+	for _, event := range r.k8sEvents {
+		k8sEvent := models.K8sEvent{
+			ID:        event.ID,
+			Timestamp: event.Timestamp,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			Type:      event.Type,
+			Count:     int32(event.Count), // #nosec G115 -- event counts are small
+			Source:    event.Source,
+		}
+		if reduce {
+			k8sEvent.Message = ""
+			k8sEvent.Source = ""
+		}
+		resource.Events = append(resource.Events, k8sEvent)
+	}
+	return resource
+}
+
+// resourceUIDFromID extracts the UID from a timeline resource ID
+// (format: group/version/kind/uid)
+func resourceUIDFromID(id string) string {
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[3]
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// second is one second in the nanosecond timestamps of events
+const second = int64(time.Second)
+
+type watchFixture struct {
+	pipeline sync.Pipeline
+	service  *TimelineService
+	watcher  *TimelineWatcher
+}
+
+func newWatchFixture(t *testing.T) *watchFixture {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	logger := logging.GetLogger("test")
+	pipeline := sync.NewPipeline(sync.DefaultPipelineConfig(), client)
+	return &watchFixture{
+		pipeline: pipeline,
+		service: NewTimelineServiceWithMode(nil, graph.NewQueryExecutor(client), TimelineQuerySourceGraph,
+			logger, noop.NewTracerProvider().Tracer("test")),
+		watcher: NewTimelineWatcher(pipeline, logger),
+	}
+}
+
+func podEvent(t *testing.T, id string, eventType models.EventType, timestamp int64, namespace, name string, labels map[string]string) models.Event {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace, "uid": name, "labels": labels},
+		"status":     map[string]interface{}{"phase": "Running"},
+	})
+	require.NoError(t, err)
+
+	return models.Event{
+		ID:        id,
+		Timestamp: timestamp,
+		Type:      eventType,
+		Resource:  models.ResourceMetadata{Version: "v1", Kind: "Pod", Namespace: namespace, Name: name, UID: name},
+		Data:      data,
+	}
+}
+
+func k8sEvent(t *testing.T, id string, timestamp int64, namespace, involved, reason string) models.Event {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"reason":  reason,
+		"message": reason + " for " + involved,
+		"type":    "Warning",
+		"involvedObject": map[string]interface{}{
+			"apiVersion": "v1", "kind": "Pod", "name": involved, "namespace": namespace, "uid": involved,
+		},
+	})
+	require.NoError(t, err)
+
+	return models.Event{
+		ID:        id,
+		Timestamp: timestamp,
+		Type:      models.EventTypeCreate,
+		Resource: models.ResourceMetadata{
+			Version: "v1", Kind: "Event", Namespace: namespace, Name: id, UID: id, InvolvedObjectUID: involved,
+		},
+		Data: data,
+	}
+}
+
+func nextDelta(t *testing.T, watch *TimelineWatch) *TimelineDelta {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	delta, err := watch.Next(ctx)
+	require.NoError(t, err)
+	return delta
+}
+
+func deltaResources(delta *TimelineDelta) map[string]models.Resource {
+	resources := make(map[string]models.Resource)
+	for _, resource := range delta.Resources {
+		resources[resource.Name] = resource
+	}
+	return resources
+}
+
+func TestTimelineWatch_SnapshotThenDeltas(t *testing.T) {
+	f := newWatchFixture(t)
+	ctx := context.Background()
+
+	require.NoError(t, f.pipeline.ProcessBatch(ctx, []models.Event{
+		podEvent(t, "api-1", models.EventTypeCreate, 1000*second, "payments", "api", map[string]string{"app": "api"}),
+		podEvent(t, "web-1", models.EventTypeCreate, 1000*second, "shop", "web", map[string]string{"app": "web"}),
+	}))
+
+	watch, _, snapshot, err := f.service.WatchTimeline(ctx, f.watcher, &models.QueryRequest{
+		StartTimestamp: 0,
+		EndTimestamp:   2000,
+		Filters:        models.QueryFilters{Namespaces: []string{"payments"}},
+	})
+	require.NoError(t, err)
+	defer watch.Close()
+
+	require.Len(t, snapshot.Resources, 1)
+	assert.Equal(t, "api", snapshot.Resources[0].Name)
+
+	require.NoError(t, f.pipeline.ProcessBatch(ctx, []models.Event{
+		podEvent(t, "api-2", models.EventTypeUpdate, 1100*second, "payments", "api", map[string]string{"app": "api"}),
+		podEvent(t, "web-2", models.EventTypeUpdate, 1100*second, "shop", "web", map[string]string{"app": "web"}),
+		podEvent(t, "worker-1", models.EventTypeCreate, 1200*second, "payments", "worker", map[string]string{"app": "worker"}),
+		k8sEvent(t, "ev-1", 1150*second, "payments", "api", "BackOff"),
+	}))
+
+	delta := nextDelta(t, watch)
+	assert.Equal(t, 1200*second, delta.Timestamp)
+
+	resources := deltaResources(delta)
+	require.Len(t, resources, 2)
+
+	api := resources["api"]
+	assert.Equal(t, "/v1/Pod/api", api.ID)
+	require.Len(t, api.StatusSegments, 1)
+	assert.Equal(t, 1100*second, api.StatusSegments[0].StartTime)
+	assert.Equal(t, 1200*second, api.StatusSegments[0].EndTime)
+	require.Len(t, api.Events, 1)
+	assert.Equal(t, "BackOff", api.Events[0].Reason)
+
+	worker := resources["worker"]
+	require.Len(t, worker.StatusSegments, 1)
+	assert.Equal(t, "payments", worker.Namespace)
+}
+
+func TestTimelineWatch_SearchQuery(t *testing.T) {
+	f := newWatchFixture(t)
+	ctx := context.Background()
+
+	watch, err := f.watcher.Watch(&models.QueryRequest{
+		Filters: models.QueryFilters{Query: "label:app=worker OR reason~backoff"},
+	})
+	require.NoError(t, err)
+	defer watch.Close()
+
+	require.NoError(t, f.pipeline.ProcessBatch(ctx, []models.Event{
+		podEvent(t, "api-1", models.EventTypeCreate, 1000*second, "payments", "api", map[string]string{"app": "api"}),
+		podEvent(t, "worker-1", models.EventTypeCreate, 1000*second, "payments", "worker", map[string]string{"app": "worker"}),
+	}))
+	resources := deltaResources(nextDelta(t, watch))
+	assert.Len(t, resources, 1)
+	assert.Contains(t, resources, "worker")
+
+	// A matching Kubernetes event adds the resource it is about
+	require.NoError(t, f.pipeline.ProcessBatch(ctx, []models.Event{
+		k8sEvent(t, "ev-1", 1100*second, "payments", "api", "BackOff"),
+	}))
+	resources = deltaResources(nextDelta(t, watch))
+	require.Len(t, resources, 1)
+	assert.Len(t, resources["api"].Events, 1)
+
+	// Once on the timeline, all changes of the resource are streamed
+	require.NoError(t, f.pipeline.ProcessBatch(ctx, []models.Event{
+		podEvent(t, "api-2", models.EventTypeUpdate, 1200*second, "payments", "api", map[string]string{"app": "api"}),
+	}))
+	resources = deltaResources(nextDelta(t, watch))
+	assert.Len(t, resources["api"].StatusSegments, 1)
+}
+
+func TestTimelineWatch_TenantScope(t *testing.T) {
+	f := newWatchFixture(t)
+
+	watch, err := f.watcher.Watch(&models.QueryRequest{
+		Filters: models.QueryFilters{AllowedNamespaces: []string{"payments"}},
+	})
+	require.NoError(t, err)
+	defer watch.Close()
+
+	require.NoError(t, f.pipeline.ProcessBatch(context.Background(), []models.Event{
+		podEvent(t, "api-1", models.EventTypeCreate, 1000*second, "payments", "api", nil),
+		podEvent(t, "web-1", models.EventTypeCreate, 1000*second, "shop", "web", nil),
+	}))
+
+	resources := deltaResources(nextDelta(t, watch))
+	assert.Len(t, resources, 1)
+	assert.Contains(t, resources, "api")
+}
+
+func TestTimelineWatch_Cancel(t *testing.T) {
+	f := newWatchFixture(t)
+
+	watch, err := f.watcher.Watch(&models.QueryRequest{})
+	require.NoError(t, err)
+	defer watch.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = watch.Next(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTimelineWatch_ChangedTerms(t *testing.T) {
+	f := newWatchFixture(t)
+	ctx := context.Background()
+
+	watch, err := f.watcher.Watch(&models.QueryRequest{
+		Filters: models.QueryFilters{Namespaces: []string{"payments"}, Query: "changed:metadata.labels.app"},
+	})
+	require.NoError(t, err)
+	defer watch.Close()
+
+	// Creations aren't changes, so nothing is streamed yet. Only the data of
+	// resources within the filters is kept for the next change.
+	require.NoError(t, f.pipeline.ProcessBatch(ctx, []models.Event{
+		podEvent(t, "api-1", models.EventTypeCreate, 1000*second, "payments", "api", map[string]string{"app": "api"}),
+		podEvent(t, "old-1", models.EventTypeCreate, 1000*second, "payments", "old", map[string]string{"app": "old"}),
+		podEvent(t, "web-1", models.EventTypeCreate, 1000*second, "shop", "web", map[string]string{"app": "web"}),
+	}))
+	require.NoError(t, f.pipeline.ProcessBatch(ctx, []models.Event{
+		podEvent(t, "api-2", models.EventTypeUpdate, 1100*second, "payments", "api", map[string]string{"app": "api-v2"}),
+		podEvent(t, "old-2", models.EventTypeDelete, 1100*second, "payments", "old", map[string]string{"app": "old"}),
+	}))
+
+	resources := deltaResources(nextDelta(t, watch))
+	require.Len(t, resources, 1)
+	assert.Contains(t, resources, "api")
+
+	// The resource on the timeline and the deleted one are forgotten
+	assert.Empty(t, watch.lastData)
+}
+
+func TestTimelineWatch_NoDataWithoutChangedTerms(t *testing.T) {
+	f := newWatchFixture(t)
+
+	watch, err := f.watcher.Watch(&models.QueryRequest{
+		Filters: models.QueryFilters{Query: "label:app=worker"},
+	})
+	require.NoError(t, err)
+	defer watch.Close()
+
+	require.NoError(t, f.pipeline.ProcessBatch(context.Background(), []models.Event{
+		podEvent(t, "api-1", models.EventTypeCreate, 1000*second, "payments", "api", map[string]string{"app": "api"}),
+		podEvent(t, "worker-1", models.EventTypeCreate, 1000*second, "payments", "worker", map[string]string{"app": "worker"}),
+	}))
+
+	resources := deltaResources(nextDelta(t, watch))
+	assert.Contains(t, resources, "worker")
+	assert.Empty(t, watch.lastData)
+}
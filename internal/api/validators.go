@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 
+	"github.com/moolen/spectre/internal/graph/searchql"
 	"github.com/moolen/spectre/internal/models"
 )
 
@@ -77,6 +78,25 @@ func (v *Validator) ValidateFilters(filters models.QueryFilters) error {
 		}
 	}
 
+	// Validate clusters (multi-value)
+	for _, cluster := range filters.Clusters {
+		if err := models.ValidateClusterName(cluster); err != nil {
+			return NewValidationError("invalid cluster filter: %v", err)
+		}
+	}
+
+	// Validate actor
+	if len(filters.Actor) > 255 {
+		return NewValidationError("actor filter is too long (max 255 characters)")
+	}
+
+	// Validate the search query
+	if filters.Query != "" {
+		if _, err := searchql.Parse(filters.Query); err != nil {
+			return NewValidationError("%v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,96 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/api/pb/pbconnect"
+	"github.com/moolen/spectre/internal/auth"
+)
+
+// Authenticator authenticates a bearer token; implemented by *auth.Service
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*auth.Identity, error)
+}
+
+// authMiddleware authenticates API requests and enforces the role required
+// by the route. Health checks, preflight requests and the static UI are public.
+// When no authenticator is configured, all requests pass through unchanged.
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.authenticator == nil || r.Method == http.MethodOptions || !isProtectedPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identity, err := s.authenticator.Authenticate(r.Context(), bearerToken(r))
+		if err != nil {
+			if errors.Is(err, auth.ErrNoRole) {
+				writeAPIError(w, api.NewForbiddenError("%v", err))
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="spectre"`)
+			writeAPIError(w, api.NewUnauthorizedError("%v", err))
+			return
+		}
+
+		required := requiredRole(r)
+		if !identity.Role.Allows(required) {
+			s.logger.Warn("Denied %s %s for %s (role %s, requires %s)", r.Method, r.URL.Path, identity.Name, identity.Role, required)
+			writeAPIError(w, api.NewForbiddenError("%s %s requires the %s role", r.Method, r.URL.Path, required))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	})
+}
+
+// isProtectedPath reports whether the path serves cluster data or configuration
+func isProtectedPath(path string) bool {
+	return strings.HasPrefix(path, "/v1/") ||
+		strings.HasPrefix(path, "/api/") ||
+		strings.HasPrefix(path, "/"+pbconnect.TimelineServiceName+"/") ||
+		strings.HasPrefix(path, "/"+pbconnect.IngestServiceName+"/")
+}
+
+// requiredRole returns the role needed for the request. Reads are viewer-level;
+// imports, events pushed by agents and any mutation of the integrations config
+// (including connection tests and syncs, which act with the integration's
+// credentials) are admin-only.
+func requiredRole(r *http.Request) auth.Role {
+	path := r.URL.Path
+
+	if path == "/v1/storage/import" || path == "/v1/kube-audit" || strings.HasPrefix(path, "/"+pbconnect.IngestServiceName+"/") {
+		return auth.RoleAdmin
+	}
+
+	if strings.HasPrefix(path, "/api/config/integrations") && r.Method != http.MethodGet {
+		return auth.RoleAdmin
+	}
+
+	return auth.RoleViewer
+}
+
+// bearerToken extracts the token from the Authorization header.
+// GET requests may pass it as an access_token query parameter instead, since
+// browser EventSource connections (SSE streams) cannot set headers.
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(header[len("Bearer "):])
+	}
+
+	if r.Method == http.MethodGet {
+		return r.URL.Query().Get("access_token")
+	}
+
+	return ""
+}
+
+// writeAPIError writes an APIError as a JSON error response
+func writeAPIError(w http.ResponseWriter, err *api.APIError) {
+	api.WriteError(w, err.GetStatusCode(), string(err.Code), err.Message)
+}
@@ -0,0 +1,163 @@
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moolen/spectre/internal/api/pb/pbconnect"
+	"github.com/moolen/spectre/internal/auth"
+	"github.com/moolen/spectre/internal/config"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+func newAuthTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	svc, err := auth.NewService(&config.AuthConfig{
+		StaticTokens: []config.StaticTokenConfig{
+			{Name: "dashboard", Token: "viewer-token", Role: config.RoleViewer},
+			{Name: "ci", Token: "admin-token", Role: config.RoleAdmin},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	return &Server{authenticator: svc, logger: logging.GetLogger("test")}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	server := newAuthTestServer(t)
+
+	var gotIdentity *auth.Identity
+	handler := server.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIdentity = auth.IdentityFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		header       string
+		wantStatus   int
+		wantIdentity string
+	}{
+		{name: "missing header", method: http.MethodGet, path: "/v1/timeline", wantStatus: http.StatusUnauthorized},
+		{name: "basic auth", method: http.MethodGet, path: "/v1/timeline", header: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "bearer without token", method: http.MethodGet, path: "/v1/timeline", header: "Bearer ", wantStatus: http.StatusUnauthorized},
+		{name: "unknown token", method: http.MethodGet, path: "/v1/timeline", header: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "viewer reads", method: http.MethodGet, path: "/v1/timeline", header: "Bearer viewer-token", wantStatus: http.StatusOK, wantIdentity: "dashboard"},
+		{name: "scheme is case insensitive", method: http.MethodGet, path: "/v1/timeline", header: "bearer viewer-token", wantStatus: http.StatusOK, wantIdentity: "dashboard"},
+		{name: "viewer reads integrations", method: http.MethodGet, path: "/api/config/integrations", header: "Bearer viewer-token", wantStatus: http.StatusOK, wantIdentity: "dashboard"},
+		{name: "viewer imports", method: http.MethodPost, path: "/v1/storage/import", header: "Bearer viewer-token", wantStatus: http.StatusForbidden},
+		{name: "admin imports", method: http.MethodPost, path: "/v1/storage/import", header: "Bearer admin-token", wantStatus: http.StatusOK, wantIdentity: "ci"},
+		{name: "viewer pushes audit events", method: http.MethodPost, path: "/v1/kube-audit", header: "Bearer viewer-token", wantStatus: http.StatusForbidden},
+		{name: "viewer ingests", method: http.MethodPost, path: pbconnect.IngestServicePushEventsProcedure, header: "Bearer viewer-token", wantStatus: http.StatusForbidden},
+		{name: "viewer updates integration", method: http.MethodPut, path: "/api/config/integrations/prometheus", header: "Bearer viewer-token", wantStatus: http.StatusForbidden},
+		{name: "admin deletes integration", method: http.MethodDelete, path: "/api/config/integrations/prometheus", header: "Bearer admin-token", wantStatus: http.StatusOK, wantIdentity: "ci"},
+		{name: "health is public", method: http.MethodGet, path: "/health", wantStatus: http.StatusOK},
+		{name: "readiness is public", method: http.MethodGet, path: "/ready", wantStatus: http.StatusOK},
+		{name: "preflight is public", method: http.MethodOptions, path: "/v1/storage/import", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIdentity = nil
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a WWW-Authenticate challenge")
+			}
+
+			var gotName string
+			if gotIdentity != nil {
+				gotName = gotIdentity.Name
+			}
+			if gotName != tt.wantIdentity {
+				t.Errorf("identity = %q, want %q", gotName, tt.wantIdentity)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	server := &Server{logger: logging.GetLogger("test")}
+	handler := server.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/storage/import", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   auth.Role
+	}{
+		{method: http.MethodGet, path: "/v1/timeline", want: auth.RoleViewer},
+		{method: http.MethodPost, path: "/v1/search", want: auth.RoleViewer},
+		{method: http.MethodPost, path: "/v1/storage/import", want: auth.RoleAdmin},
+		{method: http.MethodPost, path: "/v1/kube-audit", want: auth.RoleAdmin},
+		{method: http.MethodPost, path: pbconnect.IngestServicePushEventsProcedure, want: auth.RoleAdmin},
+		{method: http.MethodGet, path: "/api/config/integrations", want: auth.RoleViewer},
+		{method: http.MethodPost, path: "/api/config/integrations", want: auth.RoleAdmin},
+		{method: http.MethodPost, path: "/api/config/integrations/test", want: auth.RoleAdmin},
+		{method: http.MethodPost, path: "/api/config/integrations/prometheus/sync", want: auth.RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			got := requiredRole(httptest.NewRequest(tt.method, tt.path, http.NoBody))
+			if got != tt.want {
+				t.Errorf("requiredRole() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header string
+		want   string
+	}{
+		{name: "bearer header", method: http.MethodGet, target: "/v1/timeline", header: "Bearer abc", want: "abc"},
+		{name: "lower case scheme", method: http.MethodGet, target: "/v1/timeline", header: "bearer abc", want: "abc"},
+		{name: "surrounding spaces", method: http.MethodGet, target: "/v1/timeline", header: "Bearer  abc ", want: "abc"},
+		{name: "other scheme", method: http.MethodGet, target: "/v1/timeline", header: "Basic abc", want: ""},
+		{name: "scheme only", method: http.MethodGet, target: "/v1/timeline", header: "Bearer", want: ""},
+		{name: "no header", method: http.MethodGet, target: "/v1/timeline", want: ""},
+		{name: "query parameter on GET", method: http.MethodGet, target: "/v1/timeline/watch?access_token=abc", want: "abc"},
+		{name: "header wins over query parameter", method: http.MethodGet, target: "/v1/timeline/watch?access_token=abc", header: "Bearer def", want: "def"},
+		{name: "query parameter on POST", method: http.MethodPost, target: "/v1/storage/import?access_token=abc", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, http.NoBody)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if got := bearerToken(req); got != tt.want {
+				t.Errorf("bearerToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
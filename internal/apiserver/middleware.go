@@ -2,15 +2,20 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 )
 
-// corsMiddleware adds CORS headers to allow browser access
-// For local development only - allows all origins
+// corsMiddleware adds CORS headers to allow browser access.
+// Origins are restricted to corsAllowedOrigins; "*" allows all origins.
 func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		s.logger.Info("path: %s", r.URL.Path)
 		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		origin := r.Header.Get("Origin")
+		if allowed := s.allowedOrigin(origin); allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+		}
+		w.Header().Add("Vary", "Origin")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		w.Header().Set("Access-Control-Max-Age", "3600")
@@ -36,3 +41,17 @@ func (s *Server) withMethod(method string, handler http.HandlerFunc) http.Handle
 		handler(w, r)
 	}
 }
+
+// allowedOrigin returns the Access-Control-Allow-Origin value for the request
+// origin, or an empty string if the origin is not allowed
+func (s *Server) allowedOrigin(origin string) string {
+	for _, allowed := range s.corsAllowedOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package apiserver
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/moolen/spectre/internal/api"
 	"github.com/moolen/spectre/internal/api/handlers"
@@ -28,7 +29,7 @@ func (s *Server) registerHandlers() {
 	s.registerStaticUIHandlers()
 }
 
-// registerConnectService creates and registers the Connect Timeline service
+// registerConnectService creates and registers the Connect Timeline and Ingest services
 func (s *Server) registerConnectService() {
 	tracer := s.getTracer("spectre.api.connect")
 
@@ -45,9 +46,30 @@ func (s *Server) registerConnectService() {
 		timelineConnectService = api.NewTimelineConnectService(s.queryExecutor, s.logger, tracer)
 	}
 
+	// Live updates (WatchTimeline) follow the graph sync pipeline
+	if s.graphPipeline != nil {
+		timelineConnectService.EnableWatch(api.NewTimelineWatcher(s.graphPipeline, s.logger))
+	}
+
 	// Register Connect handler (supports gRPC, gRPC-Web, and Connect protocols)
 	timelinePath, timelineHandler := pbconnect.NewTimelineServiceHandler(timelineConnectService)
-	s.router.Handle(timelinePath, timelineHandler)
+	s.router.Handle(timelinePath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Watches stay open beyond the server's write timeout
+		if r.URL.Path == pbconnect.TimelineServiceWatchTimelineProcedure {
+			if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+				s.logger.Debug("Could not clear write deadline of timeline watch: %v", err)
+			}
+		}
+		timelineHandler.ServeHTTP(w, r)
+	}))
+
+	// Register Connect Ingest service so remote agents can push events into the graph
+	if s.graphPipeline != nil {
+		ingestConnectService := api.NewIngestConnectService(s.graphPipeline, s.logger, tracer)
+		ingestPath, ingestHandler := pbconnect.NewIngestServiceHandler(ingestConnectService)
+		s.router.Handle(ingestPath, ingestHandler)
+		s.logger.Info("Connect Ingest service registered for remote agents")
+	}
 }
 
 // registerHTTPHandlers registers all HTTP API handlers
@@ -9,6 +9,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	namespacegraph "github.com/moolen/spectre/internal/analysis/namespace_graph"
 	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/api/handlers"
 	"github.com/moolen/spectre/internal/graph"
 	"github.com/moolen/spectre/internal/graph/sync"
 	"github.com/moolen/spectre/internal/integration"
@@ -55,6 +56,12 @@ type Server struct {
 	integrationManager     *integration.Manager
 	// MCP server
 	mcpServer *server.MCPServer
+	// Wraps the MCP transport (optional)
+	mcpMiddleware func(http.Handler) http.Handler
+	// Authentication (nil disables authentication)
+	authenticator Authenticator
+	// CORS allowed origins ("*" allows all)
+	corsAllowedOrigins []string
 }
 
 // NamespaceGraphCacheConfig holds configuration for the namespace graph cache
@@ -98,6 +105,7 @@ func NewWithStorageGraphAndPipeline(
 		integrationsConfigPath: integrationsConfigPath,
 		integrationManager:     integrationManager,
 		mcpServer:              mcpServer,
+		corsAllowedOrigins:     []string{"*"},
 	}
 
 	// Create metadata cache if we have a query executor
@@ -153,8 +161,9 @@ func NewWithStorageGraphAndPipeline(
 
 // configureHTTPServer creates the HTTP server with CORS middleware and appropriate timeouts
 func (s *Server) configureHTTPServer(port int) {
-	// Use router with CORS middleware as the main handler
-	handler := s.corsMiddleware(s.router)
+	// Use router with auth and CORS middleware as the main handler.
+	// CORS runs first so that preflight requests and error responses carry CORS headers.
+	handler := s.corsMiddleware(s.authMiddleware(s.router))
 
 	// Create HTTP server
 	// Use longer timeouts to accommodate long-running imports (can take 5+ minutes)
@@ -184,8 +193,13 @@ func (s *Server) registerMCPHandler() {
 		server.WithStateLess(true), // Stateless mode per requirements
 	)
 
+	var handler http.Handler = streamableServer
+	if s.mcpMiddleware != nil {
+		handler = s.mcpMiddleware(handler)
+	}
+
 	// Register on router (must be BEFORE static UI catch-all)
-	s.router.Handle(endpointPath, streamableServer)
+	s.router.Handle(endpointPath, handler)
 	s.logger.Info("MCP endpoint registered at %s", endpointPath)
 }
 
@@ -336,11 +350,13 @@ func (s *Server) GetTimelineService() *api.TimelineService {
 
 // RegisterMCPEndpoint registers the MCP server endpoint after server initialization.
 // This allows the MCP server to be created with the TimelineService from this API server.
-func (s *Server) RegisterMCPEndpoint(mcpServer *server.MCPServer) error {
+// The optional middleware wraps the transport, e.g. to handle resource subscriptions.
+func (s *Server) RegisterMCPEndpoint(mcpServer *server.MCPServer, middleware func(http.Handler) http.Handler) error {
 	if mcpServer == nil {
 		return fmt.Errorf("mcpServer cannot be nil")
 	}
 	s.mcpServer = mcpServer
+	s.mcpMiddleware = middleware
 
 	// Register the MCP endpoint using the existing method
 	s.registerMCPHandler()
@@ -359,3 +375,41 @@ func (s *Server) RegisterIntegrationHandlers(integrationManager *integration.Man
 	s.registerIntegrationConfigHandlers()
 	return nil
 }
+
+// RegisterWatchStatusHandler registers the /v1/watches endpoint listing the
+// resources the watcher records. Only called when the watcher is enabled.
+func (s *Server) RegisterWatchStatusHandler(provider handlers.WatchStatusProvider) error {
+	if provider == nil {
+		return fmt.Errorf("provider cannot be nil")
+	}
+
+	watchStatusHandler := handlers.NewWatchStatusHandler(provider, s.logger)
+	s.router.HandleFunc("/v1/watches", s.withMethod(http.MethodGet, watchStatusHandler.Handle))
+	s.logger.Info("Registered /v1/watches endpoint")
+	return nil
+}
+
+// RegisterKubeAuditHandler registers the audit webhook endpoint that
+// Kubernetes API servers post their audit events to
+func (s *Server) RegisterKubeAuditHandler(sink handlers.KubeAuditSink) error {
+	if sink == nil {
+		return fmt.Errorf("sink cannot be nil")
+	}
+
+	kubeAuditHandler := handlers.NewKubeAuditHandler(sink, s.logger)
+	s.router.HandleFunc("/v1/kube-audit", s.withMethod(http.MethodPost, kubeAuditHandler.Handle))
+	s.logger.Info("Registered /v1/kube-audit endpoint")
+	return nil
+}
+
+// SetAuthenticator enables authentication and role-based authorization for
+// the API, Connect and MCP endpoints. Must be called before Start.
+func (s *Server) SetAuthenticator(authenticator Authenticator) {
+	s.authenticator = authenticator
+}
+
+// SetCORSAllowedOrigins restricts the origins allowed for browser access.
+// Must be called before Start.
+func (s *Server) SetCORSAllowedOrigins(origins []string) {
+	s.corsAllowedOrigins = origins
+}
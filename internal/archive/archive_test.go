@@ -0,0 +1,258 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+
+func podEvent(id, uid, namespace string, at time.Time, phase string) models.Event {
+	data, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      uid,
+			"namespace": namespace,
+			"labels":    map[string]string{"app": uid},
+		},
+		"status": map[string]interface{}{"phase": phase},
+	})
+	return models.Event{
+		ID:        id,
+		Timestamp: at.UnixNano(),
+		Type:      models.EventTypeUpdate,
+		Resource: models.ResourceMetadata{
+			Version: "v1", Kind: "Pod", Namespace: namespace, Name: uid, UID: uid,
+		},
+		Data: data,
+	}
+}
+
+func k8sEvent(id, involvedUID, namespace string, at time.Time, reason string) models.Event {
+	data, _ := json.Marshal(map[string]interface{}{
+		"reason":  reason,
+		"message": "message of " + id,
+		"type":    "Warning",
+		"count":   3,
+		"source":  map[string]interface{}{"component": "kubelet"},
+	})
+	return models.Event{
+		ID:        id,
+		Timestamp: at.UnixNano(),
+		Type:      models.EventTypeCreate,
+		Resource: models.ResourceMetadata{
+			Version: "v1", Kind: "Event", Namespace: namespace, Name: id, UID: id, InvolvedObjectUID: involvedUID,
+		},
+		Data: data,
+	}
+}
+
+func TestStore_ArchiveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+	assert.Zero(t, store.Horizon())
+
+	ctx := context.Background()
+	require.NoError(t, store.Archive(ctx, []models.Event{
+		podEvent("e1", "api", "payments", base, "Running"),
+		podEvent("e2", "api", "payments", base.Add(time.Hour), "Failed"),
+	}))
+	require.NoError(t, store.Archive(ctx, []models.Event{
+		podEvent("e3", "web", "shop", base.Add(10*time.Minute), "Running"),
+	}))
+
+	// One partition per hour, one segment per Archive call
+	segments, err := filepath.Glob(filepath.Join(dir, "2026-10-01", "12", "*"+segmentSuffix))
+	require.NoError(t, err)
+	assert.Len(t, segments, 2)
+	assert.DirExists(t, filepath.Join(dir, "2026-10-01", "13"))
+	assert.Equal(t, base.Add(time.Hour).UnixNano(), store.Horizon())
+
+	events, err := store.Load(ctx, base.UnixNano(), base.Add(30*time.Minute).UnixNano())
+	require.NoError(t, err)
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	assert.ElementsMatch(t, []string{"e1", "e3"}, ids)
+
+	// The horizon is restored when the archive is reopened
+	reopened, err := NewStore(dir)
+	require.NoError(t, err)
+	assert.Equal(t, store.Horizon(), reopened.Horizon())
+}
+
+func TestStore_SkipsUnreadableSegments(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Archive(ctx, []models.Event{podEvent("e1", "api", "payments", base, "Running")}))
+	partition := filepath.Join(dir, "2026-10-01", "12")
+	require.NoError(t, os.WriteFile(filepath.Join(partition, "broken"+segmentSuffix), []byte("not gzip"), 0o600))
+
+	events, err := store.Load(ctx, base.Add(-time.Hour).UnixNano(), base.Add(time.Hour).UnixNano())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "e1", events[0].ID)
+}
+
+func TestStore_ArchiveIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+
+	// A batch archived again after a crash replaces its segment, and events
+	// stored in two segments are loaded once
+	ctx := context.Background()
+	batch := []models.Event{
+		podEvent("e1", "api", "payments", base, "Running"),
+		podEvent("e2", "api", "payments", base.Add(time.Minute), "Failed"),
+	}
+	require.NoError(t, store.Archive(ctx, batch))
+	require.NoError(t, store.Archive(ctx, batch))
+	require.NoError(t, store.Archive(ctx, batch[:1]))
+
+	segments, err := filepath.Glob(filepath.Join(dir, "2026-10-01", "12", "*"+segmentSuffix))
+	require.NoError(t, err)
+	assert.Len(t, segments, 2)
+
+	events, err := store.Load(ctx, base.UnixNano(), base.Add(time.Hour).UnixNano())
+	require.NoError(t, err)
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	assert.ElementsMatch(t, []string{"e1", "e2"}, ids)
+}
+
+func newTestExecutor(t *testing.T, events ...models.Event) *Executor {
+	t.Helper()
+
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Archive(context.Background(), events))
+	return NewExecutor(store)
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	executor := newTestExecutor(t,
+		podEvent("e1", "api", "payments", base, "Running"),
+		podEvent("e2", "web", "shop", base.Add(time.Minute), "Running"),
+		k8sEvent("k1", "api", "payments", base.Add(2*time.Minute), "BackOff"),
+	)
+
+	query := &models.QueryRequest{
+		StartTimestamp: base.Add(-time.Hour).Unix(),
+		EndTimestamp:   base.Add(time.Hour).Unix(),
+		Filters:        models.QueryFilters{Namespaces: []string{"payments"}},
+	}
+	result, err := executor.Execute(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, "e1", result.Events[0].ID)
+
+	require.Len(t, result.K8sEventsByResource["api"], 1)
+	event := result.K8sEventsByResource["api"][0]
+	assert.Equal(t, "BackOff", event.Reason)
+	assert.Equal(t, "kubelet", event.Source)
+	assert.Equal(t, int32(3), event.Count)
+
+	// Queries for the Event kind return the events themselves
+	query.Filters = models.QueryFilters{Kinds: []string{"Event"}, Version: "v1"}
+	result, err = executor.Execute(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, "k1", result.Events[0].ID)
+}
+
+func TestExecutor_SearchQuery(t *testing.T) {
+	executor := newTestExecutor(t,
+		podEvent("e1", "api", "payments", base, "Running"),
+		podEvent("e2", "web", "payments", base.Add(time.Minute), "Running"),
+		k8sEvent("k1", "web", "payments", base.Add(2*time.Minute), "OOMKilling"),
+	)
+
+	query := &models.QueryRequest{
+		StartTimestamp: base.Add(-time.Hour).Unix(),
+		EndTimestamp:   base.Add(time.Hour).Unix(),
+		Filters:        models.QueryFilters{Query: `reason~oom`},
+	}
+	result, err := executor.Execute(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, "e2", result.Events[0].ID)
+
+	query.Filters.Query = "label:app=api"
+	result, err = executor.Execute(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, "e1", result.Events[0].ID)
+
+	query.Filters.Query = "kind:("
+	_, err = executor.Execute(context.Background(), query)
+	assert.Error(t, err)
+}
+
+func TestExecutor_ActorFilter(t *testing.T) {
+	changed := podEvent("e2", "web", "payments", base.Add(time.Minute), "Running")
+	changed.Actor = &models.Actor{User: "system:serviceaccount:argocd:argocd-server", ServiceAccount: "argocd/argocd-server"}
+	executor := newTestExecutor(t,
+		podEvent("e1", "api", "payments", base, "Running"),
+		podEvent("e0", "web", "payments", base, "Pending"),
+		changed,
+	)
+
+	query := &models.QueryRequest{
+		StartTimestamp: base.Add(-time.Hour).Unix(),
+		EndTimestamp:   base.Add(time.Hour).Unix(),
+		Filters:        models.QueryFilters{Actor: "ArgoCD/"},
+	}
+	result, err := executor.Execute(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 2)
+	assert.Equal(t, "e0", result.Events[0].ID)
+	assert.Equal(t, "argocd/argocd-server", result.Events[1].Actor.ServiceAccount)
+}
+
+func TestExecutor_TenantScope(t *testing.T) {
+	node := models.Event{
+		ID:        "n1",
+		Timestamp: base.UnixNano(),
+		Type:      models.EventTypeUpdate,
+		Resource:  models.ResourceMetadata{Version: "v1", Kind: "Node", Name: "node-1", UID: "node-1"},
+		Data:      json.RawMessage(`{"metadata":{"name":"node-1","annotations":{"secret":"x"}},"status":{"conditions":[]}}`),
+	}
+	executor := newTestExecutor(t,
+		podEvent("e1", "api", "payments", base, "Running"),
+		podEvent("e2", "web", "shop", base, "Running"),
+		node,
+	)
+
+	result, err := executor.Execute(context.Background(), &models.QueryRequest{
+		StartTimestamp: base.Add(-time.Hour).Unix(),
+		EndTimestamp:   base.Add(time.Hour).Unix(),
+		Filters:        models.QueryFilters{AllowedNamespaces: []string{"payments"}},
+	})
+	require.NoError(t, err)
+
+	ids := make(map[string]models.Event)
+	for _, event := range result.Events {
+		ids[event.ID] = event
+	}
+	assert.Contains(t, ids, "e1")
+	assert.NotContains(t, ids, "e2")
+	require.Contains(t, ids, "n1")
+	assert.NotContains(t, string(ids["n1"].Data), "annotations")
+}
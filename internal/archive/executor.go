@@ -0,0 +1,249 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moolen/spectre/internal/analyzer"
+	"github.com/moolen/spectre/internal/graph/searchql"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// Executor serves timeline queries from the archive. It implements
+// api.QueryExecutor and is used as the storage query source, consulted for
+// time windows reaching back to data that expired from the graph.
+type Executor struct {
+	store  *Store
+	logger *logging.Logger
+}
+
+// NewExecutor creates a query executor reading from the store
+func NewExecutor(store *Store) *Executor {
+	return &Executor{
+		store:  store,
+		logger: logging.GetLogger("archive"),
+	}
+}
+
+// Horizon returns the timestamp of the newest archived event in Unix nanoseconds
+func (e *Executor) Horizon() int64 {
+	return e.store.Horizon()
+}
+
+// SetSharedCache is a no-op, the archive has no shared cache
+func (e *Executor) SetSharedCache(cache interface{}) {}
+
+// Execute returns the archived events matching the query. Like the graph
+// executor, Kubernetes events are attached to their resources in
+// K8sEventsByResource unless the query asks for the Event kind itself.
+func (e *Executor) Execute(ctx context.Context, query *models.QueryRequest) (*models.QueryResult, error) {
+	start := time.Now()
+
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	var parsed *searchql.Query
+	if query.Filters.Query != "" {
+		q, err := searchql.Parse(query.Filters.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query: %w", err)
+		}
+		parsed = q
+	}
+
+	startNs := query.StartTimestamp * 1e9
+	endNs := query.EndTimestamp * 1e9
+	archived, err := e.store.Load(ctx, startNs, endNs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived events: %w", err)
+	}
+
+	wantsEvents := false
+	for _, kind := range query.Filters.GetKinds() {
+		if kind == "Event" {
+			wantsEvents = true
+		}
+	}
+
+	var events []models.Event
+	k8sEvents := make(map[string][]models.K8sEvent)
+	for _, event := range archived {
+		isK8sEvent := event.Resource.Kind == "Event" && event.Resource.InvolvedObjectUID != ""
+		if isK8sEvent && !wantsEvents {
+			k8sEvents[event.Resource.InvolvedObjectUID] = append(k8sEvents[event.Resource.InvolvedObjectUID], k8sEventFromData(event))
+			continue
+		}
+		if query.Filters.Matches(event.Resource) {
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+	if parsed != nil {
+		events = filterQuery(events, k8sEvents, parsed)
+	}
+	if query.Filters.Actor != "" {
+		events = filterActor(events, query.Filters.Actor)
+	}
+
+	// Only keep the Kubernetes events of returned resources
+	k8sEventsByResource := make(map[string][]models.K8sEvent)
+	for _, event := range events {
+		if resourceEvents, ok := k8sEvents[event.Resource.UID]; ok {
+			k8sEventsByResource[event.Resource.UID] = resourceEvents
+		}
+	}
+
+	if query.Filters.IsScoped() {
+		for i := range events {
+			if events[i].Resource.Namespace != "" {
+				continue
+			}
+			events[i].Data = models.ReduceResourceData(events[i].Data)
+			resourceEvents := k8sEventsByResource[events[i].Resource.UID]
+			for j := range resourceEvents {
+				resourceEvents[j].Message = ""
+				resourceEvents[j].Source = ""
+			}
+		}
+	}
+
+	if events == nil {
+		events = []models.Event{}
+	}
+	e.logger.Debug("Archive query returned %d events (filters: %s)", len(events), query.Filters.String())
+
+	return &models.QueryResult{
+		Events:              events,
+		Count:               int32(len(events)),                      // #nosec G115 -- bounded by the archive window
+		ExecutionTimeMs:     int32(time.Since(start).Milliseconds()), // #nosec G115 -- query durations are small
+		QueryStartTime:      startNs,
+		QueryEndTime:        endNs,
+		K8sEventsByResource: k8sEventsByResource,
+	}, nil
+}
+
+// filterQuery keeps the events of resources matching the search query.
+// events must be sorted by timestamp.
+func filterQuery(events []models.Event, k8sEvents map[string][]models.K8sEvent, query *searchql.Query) []models.Event {
+	byResource := make(map[string][]models.Event)
+	for _, event := range events {
+		byResource[event.Resource.UID] = append(byResource[event.Resource.UID], event)
+	}
+
+	matches := make(map[string]bool, len(byResource))
+	for uid, resourceEvents := range byResource {
+		matches[uid] = query.Match(record(resourceEvents, k8sEvents[uid]))
+	}
+
+	filtered := events[:0]
+	for _, event := range events {
+		if matches[event.Resource.UID] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// filterActor keeps the events of resources changed by a matching actor.
+// Like the graph executor, the preceding event doesn't count.
+func filterActor(events []models.Event, actor string) []models.Event {
+	matches := make(map[string]bool)
+	for _, event := range events {
+		if !event.PreExisting && event.Actor.Matches(actor) {
+			matches[event.Resource.UID] = true
+		}
+	}
+
+	filtered := events[:0]
+	for _, event := range events {
+		if matches[event.Resource.UID] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// record converts the events of a resource to the property maps matched by
+// search queries, mirroring the properties the graph stores
+func record(events []models.Event, k8sEvents []models.K8sEvent) *searchql.Record {
+	latest := events[len(events)-1]
+
+	labels := ""
+	var metadata struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if len(latest.Data) > 0 && json.Unmarshal(latest.Data, &metadata) == nil {
+		encoded, _ := json.Marshal(metadata.Metadata.Labels)
+		labels = string(encoded)
+	}
+
+	r := &searchql.Record{
+		Resource: map[string]interface{}{
+			"uid":       latest.Resource.UID,
+			"cluster":   latest.Resource.Cluster,
+			"kind":      latest.Resource.Kind,
+			"apiGroup":  latest.Resource.Group,
+			"version":   latest.Resource.Version,
+			"namespace": latest.Resource.Namespace,
+			"name":      latest.Resource.Name,
+			"labels":    labels,
+			"deleted":   latest.Type == models.EventTypeDelete,
+		},
+	}
+	for _, event := range events {
+		status := analyzer.InferStatusFromResource(event.Resource.Kind, event.Data, string(event.Type))
+		errorMessage := ""
+		if len(event.Data) > 0 {
+			errorMessage = strings.Join(analyzer.InferErrorMessages(event.Resource.Kind, event.Data, status), "; ")
+		}
+		r.ChangeEvents = append(r.ChangeEvents, map[string]interface{}{
+			"eventType":    string(event.Type),
+			"status":       status,
+			"errorMessage": errorMessage,
+			"data":         string(event.Data),
+		})
+	}
+	for _, event := range k8sEvents {
+		r.K8sEvents = append(r.K8sEvents, map[string]interface{}{
+			"reason":  event.Reason,
+			"message": event.Message,
+			"type":    event.Type,
+		})
+	}
+	return r
+}
+
+// k8sEventFromData extracts the timeline fields of an archived Kubernetes Event
+func k8sEventFromData(event models.Event) models.K8sEvent {
+	var data struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Count   int32  `json:"count"`
+		Source  struct {
+			Component string `json:"component"`
+		} `json:"source"`
+	}
+	_ = json.Unmarshal(event.Data, &data)
+
+	return models.K8sEvent{
+		ID:        event.ID,
+		Timestamp: event.Timestamp,
+		Reason:    data.Reason,
+		Message:   data.Message,
+		Type:      data.Type,
+		Count:     data.Count,
+		Source:    data.Source.Component,
+	}
+}
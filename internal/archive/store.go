@@ -0,0 +1,366 @@
+// Package archive keeps events that expired from the graph in compressed,
+// time-partitioned segment files and serves timeline queries from them.
+//
+// Segments live in one directory per hour of event time:
+//
+//	<dir>/2026-10-16/14/segment-<oldest>-<newest>-<hash>.json.gz
+//
+// Each segment is a gzip-compressed JSON document in the import format
+// ({"events": [...]}), so a decompressed segment can be passed to --import-path.
+// The name holds the time range of the segment's events in Unix nanoseconds
+// and a hash of their IDs, so archiving the same events again replaces the
+// segment instead of adding a second one.
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moolen/spectre/internal/importexport"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+)
+
+const (
+	segmentPrefix = "segment-"
+	segmentSuffix = ".json.gz"
+	dayLayout     = "2006-01-02"
+	hourLayout    = "15"
+)
+
+// Store writes and reads archive segments
+type Store struct {
+	dir    string
+	logger *logging.Logger
+
+	mu      sync.RWMutex
+	horizon int64 // newest archived event, Unix nanoseconds
+}
+
+// NewStore opens the archive in dir, creating the directory if needed
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory %s: %w", dir, err)
+	}
+
+	s := &Store{
+		dir:    dir,
+		logger: logging.GetLogger("archive"),
+	}
+	horizon, err := s.scanHorizon()
+	if err != nil {
+		return nil, err
+	}
+	s.horizon = horizon
+	return s, nil
+}
+
+// Horizon returns the timestamp of the newest archived event in Unix
+// nanoseconds, or 0 when the archive is empty. Data up to the horizon may
+// have expired from the graph.
+func (s *Store) Horizon() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.horizon
+}
+
+// Archive writes the events into one segment per hour they fall into
+func (s *Store) Archive(ctx context.Context, events []models.Event) error {
+	byHour := make(map[string][]models.Event)
+	newest := int64(0)
+	for _, event := range events {
+		dir := s.partitionDir(event.Timestamp)
+		byHour[dir] = append(byHour[dir], event)
+		newest = max(newest, event.Timestamp)
+	}
+
+	for dir, partition := range byHour {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		sort.Slice(partition, func(i, j int) bool {
+			return partition[i].Timestamp < partition[j].Timestamp
+		})
+		if err := writeSegment(dir, partition); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.horizon = max(s.horizon, newest)
+	s.mu.Unlock()
+
+	if len(events) > 0 {
+		s.logger.Info("Archived %d events into %d partitions", len(events), len(byHour))
+	}
+	return nil
+}
+
+// Load returns the archived events with timestamps in [startNs, endNs].
+// Segments outside of the range aren't read, and events stored in more than
+// one segment are returned once.
+func (s *Store) Load(ctx context.Context, startNs, endNs int64) ([]models.Event, error) {
+	partitions, err := s.partitions()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []models.Event
+	seen := make(map[string]bool)
+	for _, partition := range partitions {
+		if partition.end <= startNs || partition.start > endNs {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		segments, err := filepath.Glob(filepath.Join(partition.dir, "*"+segmentSuffix))
+		if err != nil {
+			return nil, err
+		}
+		for _, segment := range segments {
+			if oldest, newest, ok := segmentRange(segment); ok && (newest < startNs || oldest > endNs) {
+				continue
+			}
+			err := readSegment(segment, func(event models.Event) {
+				if event.Timestamp < startNs || event.Timestamp > endNs || seen[event.ID] {
+					return
+				}
+				seen[event.ID] = true
+				events = append(events, event)
+			})
+			if err != nil {
+				s.logger.Warn("Skipping unreadable archive segment %s: %v", segment, err)
+			}
+		}
+	}
+	return events, nil
+}
+
+// partition is the directory holding the segments of one hour
+type partition struct {
+	dir        string
+	start, end int64 // Unix nanoseconds, end exclusive
+}
+
+// partitions lists the hour partitions of the archive, oldest first
+func (s *Store) partitions() ([]partition, error) {
+	days, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive directory: %w", err)
+	}
+
+	var partitions []partition
+	for _, day := range days {
+		if !day.IsDir() {
+			continue
+		}
+		dayStart, err := time.Parse(dayLayout, day.Name())
+		if err != nil {
+			continue
+		}
+		hours, err := os.ReadDir(filepath.Join(s.dir, day.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive directory: %w", err)
+		}
+		for _, hour := range hours {
+			hourStart, err := time.Parse(hourLayout, hour.Name())
+			if err != nil || !hour.IsDir() {
+				continue
+			}
+			start := dayStart.Add(time.Duration(hourStart.Hour()) * time.Hour)
+			partitions = append(partitions, partition{
+				dir:   filepath.Join(s.dir, day.Name(), hour.Name()),
+				start: start.UnixNano(),
+				end:   start.Add(time.Hour).UnixNano(),
+			})
+		}
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].start < partitions[j].start
+	})
+	return partitions, nil
+}
+
+// scanHorizon finds the newest archived event, looking at the newest partition
+func (s *Store) scanHorizon() (int64, error) {
+	partitions, err := s.partitions()
+	if err != nil {
+		return 0, err
+	}
+	for i := len(partitions) - 1; i >= 0; i-- {
+		segments, err := filepath.Glob(filepath.Join(partitions[i].dir, "*"+segmentSuffix))
+		if err != nil {
+			return 0, err
+		}
+
+		newest := int64(0)
+		for _, segment := range segments {
+			if _, segmentNewest, ok := segmentRange(segment); ok {
+				newest = max(newest, segmentNewest)
+				continue
+			}
+			// Segments written before their range was part of the name
+			err := readSegment(segment, func(event models.Event) {
+				newest = max(newest, event.Timestamp)
+			})
+			if err != nil {
+				s.logger.Warn("Skipping unreadable archive segment %s: %v", segment, err)
+			}
+		}
+		if newest > 0 {
+			return newest, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *Store) partitionDir(timestamp int64) string {
+	t := time.Unix(0, timestamp).UTC()
+	return filepath.Join(s.dir, t.Format(dayLayout), t.Format(hourLayout))
+}
+
+// segmentName names the segment of events sorted by timestamp
+func segmentName(events []models.Event) string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	sort.Strings(ids)
+	hash := sha256.Sum256([]byte(strings.Join(ids, "\n")))
+
+	return fmt.Sprintf("%s%d-%d-%s%s", segmentPrefix, events[0].Timestamp, events[len(events)-1].Timestamp,
+		hex.EncodeToString(hash[:8]), segmentSuffix)
+}
+
+// segmentRange returns the time range of a segment's events from its name.
+// ok is false for names without a range.
+func segmentRange(path string) (oldest, newest int64, ok bool) {
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), segmentPrefix), segmentSuffix)
+	parts := strings.Split(name, "-")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+	oldest, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	newest, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return oldest, newest, true
+}
+
+// writeSegment writes a segment atomically, so readers never see partial
+// files. events must be sorted by timestamp.
+func writeSegment(dir string, events []models.Event) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create archive partition %s: %w", dir, err)
+	}
+
+	file, err := os.CreateTemp(dir, segmentPrefix+"*"+segmentSuffix+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create archive segment: %w", err)
+	}
+	tmpPath := file.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+
+	gz := gzip.NewWriter(file)
+	if err := json.NewEncoder(gz).Encode(importexport.BatchEventImportRequest{Events: events}); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write archive segment: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write archive segment: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write archive segment: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(dir, segmentName(events))); err != nil {
+		return fmt.Errorf("failed to publish archive segment: %w", err)
+	}
+	return nil
+}
+
+// readSegment calls fn for every event of a segment. Events are decoded one
+// at a time, so a segment is never held in memory as a whole.
+func readSegment(path string, fn func(models.Event)) error {
+	file, err := os.Open(path) // #nosec G304 -- segments are listed from the archive directory
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	// Walk the {"events": [...]} document down to the array
+	decoder := json.NewDecoder(gz)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if key != "events" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(decoder, '['); err != nil {
+			return err
+		}
+		for decoder.More() {
+			var event models.Event
+			if err := decoder.Decode(&event); err != nil {
+				return err
+			}
+			fn(event)
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, '}')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected %v in archive segment, expected %v", token, delim)
+	}
+	return nil
+}
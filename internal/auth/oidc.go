@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/moolen/spectre/internal/config"
+)
+
+const (
+	// oidcClockSkew is the leeway applied to exp/nbf/iat validation
+	oidcClockSkew = 30 * time.Second
+
+	// oidcMinKeyRefreshInterval rate-limits JWKS refreshes triggered by unknown key IDs
+	oidcMinKeyRefreshInterval = time.Minute
+)
+
+// oidcSupportedAlgorithms are the asymmetric signature algorithms accepted for ID tokens.
+// Symmetric algorithms are rejected to prevent key confusion with public JWKS keys.
+var oidcSupportedAlgorithms = map[string]bool{
+	string(jose.RS256): true,
+	string(jose.RS384): true,
+	string(jose.RS512): true,
+	string(jose.ES256): true,
+	string(jose.ES384): true,
+	string(jose.ES512): true,
+	string(jose.PS256): true,
+	string(jose.PS384): true,
+	string(jose.PS512): true,
+}
+
+// OIDCAuthenticator validates JWT bearer tokens issued by an OpenID Connect provider
+type OIDCAuthenticator struct {
+	issuerURL     string
+	clientID      string
+	jwksURL       string
+	usernameClaim string
+	groupsClaim   string
+	httpClient    *http.Client
+
+	keysMu        sync.Mutex
+	keys          *jose.JSONWebKeySet
+	lastRefreshed time.Time
+}
+
+// NewOIDCAuthenticator creates an OIDC authenticator.
+// Provider discovery and key fetching happen lazily on the first request.
+func NewOIDCAuthenticator(cfg config.OIDCConfig, httpClient *http.Client) *OIDCAuthenticator {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	usernameClaim := cfg.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+	groupsClaim := cfg.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	return &OIDCAuthenticator{
+		issuerURL:     strings.TrimSuffix(cfg.IssuerURL, "/"),
+		clientID:      cfg.ClientID,
+		jwksURL:       cfg.JWKSURL,
+		usernameClaim: usernameClaim,
+		groupsClaim:   groupsClaim,
+		httpClient:    httpClient,
+	}
+}
+
+// Name returns the authenticator name
+func (a *OIDCAuthenticator) Name() string {
+	return MethodOIDC
+}
+
+// Authenticate verifies the token signature, issuer, audience and expiry.
+// Tokens that are not JWTs or were issued by another issuer (e.g. Kubernetes
+// ServiceAccount tokens) are reported as unrecognized.
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, ErrUnrecognizedToken
+	}
+
+	var unverified jwt.Claims
+	if err := parsed.UnsafeClaimsWithoutVerification(&unverified); err != nil {
+		return nil, ErrUnrecognizedToken
+	}
+	if strings.TrimSuffix(unverified.Issuer, "/") != a.issuerURL {
+		return nil, ErrUnrecognizedToken
+	}
+
+	if len(parsed.Headers) != 1 {
+		return nil, fmt.Errorf("%w: expected exactly one signature", ErrInvalidToken)
+	}
+	header := parsed.Headers[0]
+	if !oidcSupportedAlgorithms[header.Algorithm] {
+		return nil, fmt.Errorf("%w: unsupported signing algorithm %q", ErrInvalidToken, header.Algorithm)
+	}
+
+	key, err := a.signingKey(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwt.Claims
+	var extra map[string]interface{}
+	if err := parsed.Claims(key, &claims, &extra); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	expected := jwt.Expected{
+		Issuer:   unverified.Issuer,
+		Audience: jwt.Audience{a.clientID},
+		Time:     time.Now(),
+	}
+	if err := claims.ValidateWithLeeway(expected, oidcClockSkew); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrInvalidToken)
+	}
+
+	username, _ := extra[a.usernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("%w: claim %q is missing", ErrInvalidToken, a.usernameClaim)
+	}
+
+	return &Identity{
+		Name:   username,
+		Groups: stringSliceClaim(extra[a.groupsClaim]),
+		Method: MethodOIDC,
+	}, nil
+}
+
+// signingKey returns the JWKS key with the given ID, refreshing the key set
+// if the key is unknown (provider key rotation)
+func (a *OIDCAuthenticator) signingKey(ctx context.Context, keyID string) (*jose.JSONWebKey, error) {
+	a.keysMu.Lock()
+	defer a.keysMu.Unlock()
+
+	if key := a.findKeyLocked(keyID); key != nil {
+		return key, nil
+	}
+
+	if a.keys != nil && time.Since(a.lastRefreshed) < oidcMinKeyRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, keyID)
+	}
+
+	keys, err := a.fetchKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC signing keys: %w", err)
+	}
+	a.keys = keys
+	a.lastRefreshed = time.Now()
+
+	if key := a.findKeyLocked(keyID); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, keyID)
+}
+
+// findKeyLocked looks up a key by ID. A token without a key ID matches the
+// only key of a single-key set. Caller must hold keysMu.
+func (a *OIDCAuthenticator) findKeyLocked(keyID string) *jose.JSONWebKey {
+	if a.keys == nil {
+		return nil
+	}
+	if keyID == "" {
+		if len(a.keys.Keys) == 1 {
+			return &a.keys.Keys[0]
+		}
+		return nil
+	}
+	for _, key := range a.keys.Key(keyID) {
+		if key.Use == "" || key.Use == "sig" {
+			return &key
+		}
+	}
+	return nil
+}
+
+// fetchKeys downloads the provider's JWKS, discovering its URL if not configured
+func (a *OIDCAuthenticator) fetchKeys(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	jwksURL := a.jwksURL
+	if jwksURL == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := a.getJSON(ctx, a.issuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, fmt.Errorf("provider discovery failed: %w", err)
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != a.issuerURL {
+			return nil, fmt.Errorf("discovered issuer %q does not match configured issuer %q", discovery.Issuer, a.issuerURL)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, fmt.Errorf("provider discovery document has no jwks_uri")
+		}
+		jwksURL = discovery.JWKSURI
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := a.getJSON(ctx, jwksURL, &keys); err != nil {
+		return nil, err
+	}
+	return &keys, nil
+}
+
+func (a *OIDCAuthenticator) getJSON(ctx context.Context, url string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+// stringSliceClaim converts a claim holding a string or a list of strings
+func stringSliceClaim(value interface{}) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []interface{}:
+		result := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
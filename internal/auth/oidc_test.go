@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/moolen/spectre/internal/config"
+)
+
+type testOIDCProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	keyID  string
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &testOIDCProvider{key: key, keyID: "test-key"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   p.server.URL,
+			"jwks_uri": p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: p.keyID, Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func (p *testOIDCProvider) sign(t *testing.T, claims jwt.Claims, extra map[string]interface{}) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", p.keyID),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := jwt.Signed(signer).Claims(claims).Claims(extra).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	authenticator := NewOIDCAuthenticator(config.OIDCConfig{
+		IssuerURL:     provider.server.URL,
+		ClientID:      "spectre",
+		UsernameClaim: "email",
+	}, provider.server.Client())
+
+	now := time.Now()
+	validClaims := jwt.Claims{
+		Issuer:   provider.server.URL,
+		Subject:  "user-1",
+		Audience: jwt.Audience{"spectre"},
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}
+	extra := map[string]interface{}{"email": "jane@example.com", "groups": []string{"sre"}}
+
+	t.Run("valid token", func(t *testing.T) {
+		identity, err := authenticator.Authenticate(context.Background(), provider.sign(t, validClaims, extra))
+		if err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+		if identity.Name != "jane@example.com" {
+			t.Errorf("Name = %q, want jane@example.com", identity.Name)
+		}
+		if len(identity.Groups) != 1 || identity.Groups[0] != "sre" {
+			t.Errorf("Groups = %v, want [sre]", identity.Groups)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := validClaims
+		claims.Expiry = jwt.NewNumericDate(now.Add(-time.Hour))
+		_, err := authenticator.Authenticate(context.Background(), provider.sign(t, claims, extra))
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("error = %v, want ErrInvalidToken", err)
+		}
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := validClaims
+		claims.Audience = jwt.Audience{"other"}
+		_, err := authenticator.Authenticate(context.Background(), provider.sign(t, claims, extra))
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("error = %v, want ErrInvalidToken", err)
+		}
+	})
+
+	t.Run("other issuer is not recognized", func(t *testing.T) {
+		claims := validClaims
+		claims.Issuer = "https://kubernetes.default.svc"
+		_, err := authenticator.Authenticate(context.Background(), provider.sign(t, claims, extra))
+		if !errors.Is(err, ErrUnrecognizedToken) {
+			t.Errorf("error = %v, want ErrUnrecognizedToken", err)
+		}
+	})
+
+	t.Run("not a JWT", func(t *testing.T) {
+		_, err := authenticator.Authenticate(context.Background(), "opaque-token")
+		if !errors.Is(err, ErrUnrecognizedToken) {
+			t.Errorf("error = %v, want ErrUnrecognizedToken", err)
+		}
+	})
+
+	t.Run("signed by another key", func(t *testing.T) {
+		other := newTestOIDCProvider(t)
+		other.keyID = provider.keyID
+		_, err := authenticator.Authenticate(context.Background(), other.sign(t, validClaims, extra))
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("error = %v, want ErrInvalidToken", err)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/moolen/spectre/internal/config"
+	"github.com/moolen/spectre/internal/logging"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Service authenticates bearer tokens with the configured authenticators and
+// resolves the caller's role from static token roles and role bindings
+type Service struct {
+	authenticators []Authenticator
+	bindings       []config.RoleBinding
+	tenants        []config.TenantConfig
+	anonymousRole  Role
+	defaultRole    Role
+	logger         *logging.Logger
+}
+
+// NewService creates an auth service from the configuration.
+// kubeClient is only required when TokenReview authentication is enabled.
+func NewService(cfg *config.AuthConfig, kubeClient kubernetes.Interface) (*Service, error) {
+	s := &Service{
+		bindings:      cfg.RoleBindings,
+		tenants:       cfg.Tenants,
+		anonymousRole: ParseRole(cfg.AnonymousRole),
+		defaultRole:   ParseRole(cfg.DefaultRole),
+		logger:        logging.GetLogger("auth"),
+	}
+
+	if len(cfg.StaticTokens) > 0 {
+		static, err := NewStaticTokenAuthenticator(cfg.StaticTokens)
+		if err != nil {
+			return nil, err
+		}
+		s.authenticators = append(s.authenticators, static)
+	}
+
+	if cfg.OIDC != nil {
+		s.authenticators = append(s.authenticators, NewOIDCAuthenticator(*cfg.OIDC, nil))
+	}
+
+	if cfg.TokenReview != nil && cfg.TokenReview.Enabled {
+		if kubeClient == nil {
+			return nil, fmt.Errorf("tokenReview is enabled but no Kubernetes client is available")
+		}
+		s.authenticators = append(s.authenticators, NewTokenReviewAuthenticator(kubeClient, *cfg.TokenReview))
+	}
+
+	for _, a := range s.authenticators {
+		s.logger.Info("Authenticator enabled: %s", a.Name())
+	}
+	if s.anonymousRole != RoleNone {
+		s.logger.Warn("Anonymous access enabled with role %s", s.anonymousRole)
+	}
+
+	return s, nil
+}
+
+// Authenticate returns the identity for the bearer token.
+// An empty token yields the anonymous identity if anonymous access is enabled.
+func (s *Service) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	if token == "" {
+		if s.anonymousRole == RoleNone {
+			return nil, ErrMissingCredentials
+		}
+		identity := &Identity{Name: AnonymousUser, Role: s.anonymousRole, Method: MethodAnonymous}
+		identity.Namespaces = s.resolveNamespaces(identity)
+		return identity, nil
+	}
+
+	for _, a := range s.authenticators {
+		identity, err := a.Authenticate(ctx, token)
+		if errors.Is(err, ErrUnrecognizedToken) {
+			continue
+		}
+		if err != nil {
+			s.logger.Debug("Authenticator %s rejected token: %v", a.Name(), err)
+			return nil, ErrInvalidToken
+		}
+
+		if identity.Role == RoleNone {
+			identity.Role = s.resolveRole(identity)
+		}
+		if identity.Role == RoleNone {
+			return nil, fmt.Errorf("%w to %s", ErrNoRole, identity.Name)
+		}
+		identity.Namespaces = s.resolveNamespaces(identity)
+		return identity, nil
+	}
+
+	return nil, ErrInvalidToken
+}
+
+// resolveRole returns the highest role granted to the identity by role
+// bindings, falling back to the default role
+func (s *Service) resolveRole(identity *Identity) Role {
+	role := RoleNone
+	for _, binding := range s.bindings {
+		bindingRole := ParseRole(binding.Role)
+		if bindingRole > role && bindingMatches(binding, identity) {
+			role = bindingRole
+		}
+	}
+
+	if role == RoleNone {
+		return s.defaultRole
+	}
+	return role
+}
+
+// resolveNamespaces returns the union of the namespaces of every tenant the
+// identity belongs to, or nil if the identity is not restricted
+func (s *Service) resolveNamespaces(identity *Identity) []string {
+	if len(s.tenants) == 0 || identity.Role.Allows(RoleAdmin) {
+		return nil
+	}
+
+	namespaces := []string{}
+	seen := make(map[string]bool)
+	for _, tenant := range s.tenants {
+		if !subjectMatches(tenant.Users, tenant.Groups, identity) {
+			continue
+		}
+		for _, ns := range tenant.Namespaces {
+			if ns == AllNamespaces {
+				return nil
+			}
+			if !seen[ns] {
+				seen[ns] = true
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	return namespaces
+}
+
+func bindingMatches(binding config.RoleBinding, identity *Identity) bool {
+	return subjectMatches(binding.Users, binding.Groups, identity)
+}
+
+// subjectMatches reports whether the identity is one of the users or a member
+// of one of the groups
+func subjectMatches(users, groups []string, identity *Identity) bool {
+	for _, user := range users {
+		if user == identity.Name {
+			return true
+		}
+	}
+	for _, group := range groups {
+		for _, identityGroup := range identity.Groups {
+			if group == identityGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moolen/spectre/internal/config"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestService_StaticTokens(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	svc, err := NewService(&config.AuthConfig{
+		StaticTokens: []config.StaticTokenConfig{
+			{Name: "ci", Token: "inline-token", Role: config.RoleAdmin},
+			{Name: "dashboard", TokenFile: tokenFile, Role: config.RoleViewer, Groups: []string{"team-a"}},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantName string
+		wantRole Role
+		wantErr  error
+	}{
+		{name: "inline token", token: "inline-token", wantName: "ci", wantRole: RoleAdmin},
+		{name: "token file is trimmed", token: "file-token", wantName: "dashboard", wantRole: RoleViewer},
+		{name: "unknown token", token: "nope", wantErr: ErrInvalidToken},
+		{name: "missing token", token: "", wantErr: ErrMissingCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := svc.Authenticate(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if identity.Name != tt.wantName || identity.Role != tt.wantRole {
+				t.Errorf("Authenticate() = %s/%s, want %s/%s", identity.Name, identity.Role, tt.wantName, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestService_AnonymousRole(t *testing.T) {
+	svc, err := NewService(&config.AuthConfig{AnonymousRole: config.RoleViewer}, nil)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	identity, err := svc.Authenticate(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if identity.Role != RoleViewer || identity.Method != MethodAnonymous {
+		t.Errorf("Authenticate() = %+v, want anonymous viewer", identity)
+	}
+}
+
+func newFakeTokenReviewClient(users map[string]authenticationv1.UserInfo) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		user, ok := users[review.Spec.Token]
+		review = review.DeepCopy()
+		review.Status.Authenticated = ok
+		review.Status.User = user
+		return true, review, nil
+	})
+	return client
+}
+
+func TestService_TokenReviewWithRoleBindings(t *testing.T) {
+	client := newFakeTokenReviewClient(map[string]authenticationv1.UserInfo{
+		"admin-sa":  {Username: "system:serviceaccount:ops:deployer", Groups: []string{"system:serviceaccounts"}},
+		"viewer-sa": {Username: "system:serviceaccount:app:reader", Groups: []string{"system:authenticated"}},
+		"nobody":    {Username: "system:serviceaccount:app:other"},
+	})
+
+	svc, err := NewService(&config.AuthConfig{
+		TokenReview: &config.TokenReviewConfig{Enabled: true},
+		RoleBindings: []config.RoleBinding{
+			{Role: config.RoleViewer, Groups: []string{"system:authenticated"}},
+			{Role: config.RoleAdmin, Users: []string{"system:serviceaccount:ops:deployer"}},
+		},
+	}, client)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	identity, err := svc.Authenticate(ctx, "admin-sa")
+	if err != nil || identity.Role != RoleAdmin {
+		t.Errorf("admin-sa: got %+v, %v; want admin", identity, err)
+	}
+
+	identity, err = svc.Authenticate(ctx, "viewer-sa")
+	if err != nil || identity.Role != RoleViewer {
+		t.Errorf("viewer-sa: got %+v, %v; want viewer", identity, err)
+	}
+
+	if _, err := svc.Authenticate(ctx, "nobody"); !errors.Is(err, ErrNoRole) {
+		t.Errorf("nobody: error = %v, want ErrNoRole", err)
+	}
+
+	if _, err := svc.Authenticate(ctx, "unknown"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown: error = %v, want ErrInvalidToken", err)
+	}
+
+	// Successful reviews are cached
+	reviews := 0
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "tokenreviews" {
+			reviews++
+		}
+	}
+	if _, err := svc.Authenticate(ctx, "admin-sa"); err != nil {
+		t.Fatal(err)
+	}
+	after := 0
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "tokenreviews" {
+			after++
+		}
+	}
+	if after != reviews {
+		t.Errorf("expected cached review, got %d new TokenReview calls", after-reviews)
+	}
+}
+
+func TestService_TokenReviewRequiresClient(t *testing.T) {
+	_, err := NewService(&config.AuthConfig{TokenReview: &config.TokenReviewConfig{Enabled: true}}, nil)
+	if err == nil {
+		t.Fatal("expected error when TokenReview is enabled without a Kubernetes client")
+	}
+}
+
+func TestService_TenantNamespaces(t *testing.T) {
+	svc, err := NewService(&config.AuthConfig{
+		StaticTokens: []config.StaticTokenConfig{
+			{Name: "ops", Token: "ops-token", Role: config.RoleAdmin},
+			{Name: "alice", Token: "alice-token", Role: config.RoleViewer, Groups: []string{"team-a", "team-b"}},
+			{Name: "bob", Token: "bob-token", Role: config.RoleViewer},
+			{Name: "platform", Token: "platform-token", Role: config.RoleViewer},
+		},
+		Tenants: []config.TenantConfig{
+			{Name: "a", Groups: []string{"team-a"}, Namespaces: []string{"a-prod", "a-dev"}},
+			{Name: "b", Groups: []string{"team-b"}, Namespaces: []string{"b-prod", "a-prod"}},
+			{Name: "platform", Users: []string{"platform"}, Namespaces: []string{AllNamespaces}},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	tests := []struct {
+		token string
+		want  []string
+	}{
+		{token: "ops-token", want: nil},
+		{token: "alice-token", want: []string{"a-prod", "a-dev", "b-prod"}},
+		{token: "bob-token", want: []string{}},
+		{token: "platform-token", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			identity, err := svc.Authenticate(context.Background(), tt.token)
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if (identity.Namespaces == nil) != (tt.want == nil) {
+				t.Fatalf("Namespaces = %#v, want %#v", identity.Namespaces, tt.want)
+			}
+			if len(identity.Namespaces) != len(tt.want) {
+				t.Fatalf("Namespaces = %v, want %v", identity.Namespaces, tt.want)
+			}
+			for i := range tt.want {
+				if identity.Namespaces[i] != tt.want[i] {
+					t.Errorf("Namespaces = %v, want %v", identity.Namespaces, tt.want)
+				}
+			}
+
+			ctx := WithIdentity(context.Background(), identity)
+			if got := AllowedNamespaces(ctx); len(got) != len(tt.want) {
+				t.Errorf("AllowedNamespaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := AllowedNamespaces(context.Background()); got != nil {
+		t.Errorf("AllowedNamespaces() without identity = %v, want nil", got)
+	}
+}
+
+func TestRole_Allows(t *testing.T) {
+	if !RoleAdmin.Allows(RoleViewer) {
+		t.Error("admin should allow viewer routes")
+	}
+	if RoleViewer.Allows(RoleAdmin) {
+		t.Error("viewer should not allow admin routes")
+	}
+	if RoleNone.Allows(RoleViewer) {
+		t.Error("none should not allow viewer routes")
+	}
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/moolen/spectre/internal/config"
+)
+
+// staticToken is a loaded static token entry
+type staticToken struct {
+	name   string
+	token  []byte
+	role   Role
+	groups []string
+}
+
+// StaticTokenAuthenticator authenticates fixed bearer tokens from the config
+type StaticTokenAuthenticator struct {
+	tokens []staticToken
+}
+
+// NewStaticTokenAuthenticator creates an authenticator for the configured tokens.
+// Token files are read once at construction.
+func NewStaticTokenAuthenticator(configs []config.StaticTokenConfig) (*StaticTokenAuthenticator, error) {
+	a := &StaticTokenAuthenticator{}
+
+	for _, cfg := range configs {
+		value := cfg.Token
+		if cfg.TokenFile != "" {
+			data, err := os.ReadFile(cfg.TokenFile) //nolint:gosec // path is provided by the operator
+			if err != nil {
+				return nil, fmt.Errorf("failed to read token file for %q: %w", cfg.Name, err)
+			}
+			value = strings.TrimSpace(string(data))
+		}
+		if value == "" {
+			return nil, fmt.Errorf("static token %q is empty", cfg.Name)
+		}
+
+		a.tokens = append(a.tokens, staticToken{
+			name:   cfg.Name,
+			token:  []byte(value),
+			role:   ParseRole(cfg.Role),
+			groups: cfg.Groups,
+		})
+	}
+
+	return a, nil
+}
+
+// Name returns the authenticator name
+func (a *StaticTokenAuthenticator) Name() string {
+	return MethodStaticToken
+}
+
+// Authenticate compares the token against every configured token in constant time
+func (a *StaticTokenAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	var match *staticToken
+	for i := range a.tokens {
+		// Compare against all tokens so timing does not reveal which one matched
+		if subtle.ConstantTimeCompare(a.tokens[i].token, []byte(token)) == 1 {
+			match = &a.tokens[i]
+		}
+	}
+
+	if match == nil {
+		return nil, ErrUnrecognizedToken
+	}
+
+	return &Identity{
+		Name:   match.name,
+		Groups: match.groups,
+		Role:   match.role,
+		Method: MethodStaticToken,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moolen/spectre/internal/config"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultTokenReviewCacheTTL bounds how often the same token is sent to the API server
+const defaultTokenReviewCacheTTL = time.Minute
+
+// tokenReviewCacheEntry is a cached review result
+type tokenReviewCacheEntry struct {
+	identity *Identity
+	expires  time.Time
+}
+
+// TokenReviewAuthenticator validates tokens (typically ServiceAccount tokens)
+// with the Kubernetes TokenReview API
+type TokenReviewAuthenticator struct {
+	client    kubernetes.Interface
+	audiences []string
+	cacheTTL  time.Duration
+
+	cacheMu sync.Mutex
+	cache   map[[sha256.Size]byte]tokenReviewCacheEntry
+}
+
+// NewTokenReviewAuthenticator creates a TokenReview authenticator
+func NewTokenReviewAuthenticator(client kubernetes.Interface, cfg config.TokenReviewConfig) *TokenReviewAuthenticator {
+	ttl := cfg.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultTokenReviewCacheTTL
+	}
+
+	return &TokenReviewAuthenticator{
+		client:    client,
+		audiences: cfg.Audiences,
+		cacheTTL:  ttl,
+		cache:     make(map[[sha256.Size]byte]tokenReviewCacheEntry),
+	}
+}
+
+// Name returns the authenticator name
+func (a *TokenReviewAuthenticator) Name() string {
+	return MethodTokenReview
+}
+
+// Authenticate submits a TokenReview for the token.
+// Only successful reviews are cached; rejected tokens are re-checked each time.
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+
+	a.cacheMu.Lock()
+	if entry, ok := a.cache[key]; ok {
+		if now.Before(entry.expires) {
+			a.cacheMu.Unlock()
+			return entry.identity, nil
+		}
+		delete(a.cache, key)
+	}
+	a.cacheMu.Unlock()
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: a.audiences,
+		},
+	}
+
+	result, err := a.client.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+
+	if !result.Status.Authenticated {
+		if result.Status.Error != "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidToken, result.Status.Error)
+		}
+		return nil, ErrInvalidToken
+	}
+
+	identity := &Identity{
+		Name:   result.Status.User.Username,
+		Groups: result.Status.User.Groups,
+		Method: MethodTokenReview,
+	}
+
+	a.cacheMu.Lock()
+	a.evictExpiredLocked(now)
+	a.cache[key] = tokenReviewCacheEntry{identity: identity, expires: now.Add(a.cacheTTL)}
+	a.cacheMu.Unlock()
+
+	return identity, nil
+}
+
+// evictExpiredLocked removes expired entries. Caller must hold cacheMu.
+func (a *TokenReviewAuthenticator) evictExpiredLocked(now time.Time) {
+	for key, entry := range a.cache {
+		if now.After(entry.expires) {
+			delete(a.cache, key)
+		}
+	}
+}
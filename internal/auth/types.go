@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/moolen/spectre/internal/config"
+)
+
+// Role is an authorization level. Roles are ordered: a higher role includes
+// every permission of the lower ones.
+type Role int
+
+const (
+	// RoleNone grants no access
+	RoleNone Role = iota
+	// RoleViewer grants read access to timelines, graphs and MCP tools
+	RoleViewer
+	// RoleAdmin additionally grants imports and integration management
+	RoleAdmin
+)
+
+// ParseRole converts a config role name to a Role.
+// Unknown or empty names map to RoleNone.
+func ParseRole(name string) Role {
+	switch name {
+	case config.RoleViewer:
+		return RoleViewer
+	case config.RoleAdmin:
+		return RoleAdmin
+	default:
+		return RoleNone
+	}
+}
+
+// String returns the config name of the role
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return config.RoleViewer
+	case RoleAdmin:
+		return config.RoleAdmin
+	default:
+		return "none"
+	}
+}
+
+// Allows reports whether the role satisfies the required role
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// Authentication methods recorded on an Identity
+const (
+	MethodAnonymous   = "anonymous"
+	MethodStaticToken = "static-token"
+	MethodOIDC        = "oidc"
+	MethodTokenReview = "tokenreview"
+)
+
+// AnonymousUser is the identity name of unauthenticated callers
+const AnonymousUser = "system:anonymous"
+
+// AllNamespaces in a tenant's namespace list grants access to every namespace
+const AllNamespaces = "*"
+
+// Identity is the authenticated caller of an API request
+type Identity struct {
+	// Name is the user name, service account or static token name
+	Name string
+
+	// Groups the caller belongs to
+	Groups []string
+
+	// Role is the resolved authorization level
+	Role Role
+
+	// Method is the authentication method that produced this identity
+	Method string
+
+	// Namespaces the caller is restricted to by tenant configuration.
+	// nil means the caller may access every namespace; an empty, non-nil
+	// slice means the caller may only access cluster-scoped resources.
+	Namespaces []string
+}
+
+var (
+	// ErrMissingCredentials is returned when no bearer token was provided
+	// and anonymous access is not enabled
+	ErrMissingCredentials = errors.New("missing bearer token")
+
+	// ErrInvalidToken is returned when no authenticator accepts the token
+	ErrInvalidToken = errors.New("invalid bearer token")
+
+	// ErrNoRole is returned when the caller authenticated successfully but is
+	// not granted any role
+	ErrNoRole = errors.New("no role granted")
+
+	// ErrUnrecognizedToken is returned by an Authenticator when the token is
+	// not in a format it handles, so the next authenticator should try
+	ErrUnrecognizedToken = errors.New("token not recognized")
+)
+
+// Authenticator validates a bearer token and returns the caller's identity.
+// The returned identity's Role may be RoleNone; the Service resolves it from
+// role bindings.
+type Authenticator interface {
+	// Name returns the authenticator name for logging
+	Name() string
+
+	// Authenticate validates the token. Implementations return
+	// ErrUnrecognizedToken for tokens they cannot handle.
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+type identityContextKey struct{}
+
+// WithIdentity returns a context carrying the identity
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// AllowedNamespaces returns the tenant namespace scope of the caller in the
+// context. nil means unrestricted, which is also the case when authentication
+// is disabled.
+func AllowedNamespaces(ctx context.Context) []string {
+	identity := IdentityFromContext(ctx)
+	if identity == nil {
+		return nil
+	}
+	return identity.Namespaces
+}
+
+// IdentityFromContext returns the identity stored in the context, if any.
+// Returns nil when authentication is disabled or the request came from an
+// in-process caller (e.g. the stdio MCP transport).
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityContextKey{}).(*Identity)
+	return identity
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Role names used by the auth configuration
+const (
+	RoleViewer = "viewer"
+	RoleAdmin  = "admin"
+)
+
+// AuthConfig configures authentication and authorization for the API server.
+// Every configured authenticator is tried in order: static tokens, OIDC, TokenReview.
+type AuthConfig struct {
+	// AnonymousRole is granted to requests without credentials.
+	// Empty (default) rejects unauthenticated requests.
+	AnonymousRole string `yaml:"anonymousRole,omitempty"`
+
+	// DefaultRole is granted to identities authenticated via OIDC or TokenReview
+	// that do not match any role binding. Empty (default) rejects them.
+	DefaultRole string `yaml:"defaultRole,omitempty"`
+
+	// StaticTokens are long-lived bearer tokens with a fixed role
+	StaticTokens []StaticTokenConfig `yaml:"staticTokens,omitempty"`
+
+	// TokenReview validates bearer tokens against the Kubernetes API server
+	TokenReview *TokenReviewConfig `yaml:"tokenReview,omitempty"`
+
+	// OIDC validates bearer tokens as JWTs issued by an OpenID Connect provider
+	OIDC *OIDCConfig `yaml:"oidc,omitempty"`
+
+	// RoleBindings map users and groups to roles. Admin bindings take precedence.
+	RoleBindings []RoleBinding `yaml:"roleBindings,omitempty"`
+
+	// Tenants restrict viewers to the namespaces of the tenants they belong to.
+	// When empty, every identity can see all namespaces. When set, viewers that
+	// match no tenant only see cluster-scoped resources. Admins are never restricted.
+	Tenants []TenantConfig `yaml:"tenants,omitempty"`
+}
+
+// StaticTokenConfig is a single static bearer token
+type StaticTokenConfig struct {
+	// Name identifies the token holder in logs and as the identity name
+	Name string `yaml:"name"`
+
+	// Token is the bearer token value. Prefer TokenFile to keep secrets out of the config.
+	Token string `yaml:"token,omitempty"`
+
+	// TokenFile is a path to a file containing the token (e.g. a mounted Secret)
+	TokenFile string `yaml:"tokenFile,omitempty"`
+
+	// Role granted to the token holder (viewer or admin)
+	Role string `yaml:"role"`
+
+	// Groups attached to the identity, used by role bindings and namespace tenancy
+	Groups []string `yaml:"groups,omitempty"`
+}
+
+// TokenReviewConfig configures Kubernetes TokenReview authentication
+type TokenReviewConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Audiences requested in the TokenReview (optional)
+	Audiences []string `yaml:"audiences,omitempty"`
+
+	// CacheTTL is how long successful reviews are cached (default: 1m)
+	CacheTTL time.Duration `yaml:"cacheTTL,omitempty"`
+}
+
+// OIDCConfig configures OpenID Connect JWT validation
+type OIDCConfig struct {
+	// IssuerURL must match the "iss" claim and is used for provider discovery
+	IssuerURL string `yaml:"issuerURL"`
+
+	// ClientID must be contained in the "aud" claim
+	ClientID string `yaml:"clientID"`
+
+	// JWKSURL overrides the jwks_uri from discovery (optional)
+	JWKSURL string `yaml:"jwksURL,omitempty"`
+
+	// UsernameClaim is the claim used as the identity name (default: "sub")
+	UsernameClaim string `yaml:"usernameClaim,omitempty"`
+
+	// GroupsClaim is the claim holding the user's groups (default: "groups")
+	GroupsClaim string `yaml:"groupsClaim,omitempty"`
+}
+
+// RoleBinding grants a role to a set of users and groups
+type RoleBinding struct {
+	Role   string   `yaml:"role"`
+	Users  []string `yaml:"users,omitempty"`
+	Groups []string `yaml:"groups,omitempty"`
+}
+
+// TenantConfig grants a set of users and groups access to a set of namespaces
+type TenantConfig struct {
+	// Name identifies the tenant in logs
+	Name string `yaml:"name"`
+
+	// Namespaces the tenant may access. "*" grants access to all namespaces.
+	Namespaces []string `yaml:"namespaces"`
+
+	Users  []string `yaml:"users,omitempty"`
+	Groups []string `yaml:"groups,omitempty"`
+}
+
+// LoadAuthConfig loads the auth configuration from a YAML file
+func LoadAuthConfig(path string) (*AuthConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator via flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config file %s: %w", path, err)
+	}
+
+	var cfg AuthConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse auth config YAML: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid auth config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks that the auth configuration is valid
+func (c *AuthConfig) Validate() error {
+	if c.AnonymousRole != "" && !isValidRole(c.AnonymousRole) {
+		return fmt.Errorf("anonymousRole: invalid role %q", c.AnonymousRole)
+	}
+	if c.DefaultRole != "" && !isValidRole(c.DefaultRole) {
+		return fmt.Errorf("defaultRole: invalid role %q", c.DefaultRole)
+	}
+
+	names := make(map[string]bool)
+	for i, token := range c.StaticTokens {
+		if token.Name == "" {
+			return fmt.Errorf("staticTokens[%d]: name must not be empty", i)
+		}
+		if names[token.Name] {
+			return fmt.Errorf("staticTokens[%d]: duplicate name %q", i, token.Name)
+		}
+		names[token.Name] = true
+		if (token.Token == "") == (token.TokenFile == "") {
+			return fmt.Errorf("staticTokens[%d]: exactly one of token or tokenFile must be set", i)
+		}
+		if !isValidRole(token.Role) {
+			return fmt.Errorf("staticTokens[%d]: invalid role %q", i, token.Role)
+		}
+	}
+
+	if c.OIDC != nil {
+		if c.OIDC.IssuerURL == "" {
+			return fmt.Errorf("oidc: issuerURL must not be empty")
+		}
+		if c.OIDC.ClientID == "" {
+			return fmt.Errorf("oidc: clientID must not be empty")
+		}
+	}
+
+	for i, binding := range c.RoleBindings {
+		if !isValidRole(binding.Role) {
+			return fmt.Errorf("roleBindings[%d]: invalid role %q", i, binding.Role)
+		}
+		if len(binding.Users) == 0 && len(binding.Groups) == 0 {
+			return fmt.Errorf("roleBindings[%d]: at least one user or group must be specified", i)
+		}
+	}
+
+	tenants := make(map[string]bool)
+	for i, tenant := range c.Tenants {
+		if tenant.Name == "" {
+			return fmt.Errorf("tenants[%d]: name must not be empty", i)
+		}
+		if tenants[tenant.Name] {
+			return fmt.Errorf("tenants[%d]: duplicate name %q", i, tenant.Name)
+		}
+		tenants[tenant.Name] = true
+		if len(tenant.Namespaces) == 0 {
+			return fmt.Errorf("tenants[%d]: at least one namespace must be specified", i)
+		}
+		for _, ns := range tenant.Namespaces {
+			if ns == "" {
+				return fmt.Errorf("tenants[%d]: namespace must not be empty", i)
+			}
+		}
+		if len(tenant.Users) == 0 && len(tenant.Groups) == 0 {
+			return fmt.Errorf("tenants[%d]: at least one user or group must be specified", i)
+		}
+	}
+
+	if len(c.StaticTokens) == 0 && c.OIDC == nil && (c.TokenReview == nil || !c.TokenReview.Enabled) && c.AnonymousRole == "" {
+		return fmt.Errorf("at least one authenticator or anonymousRole must be configured")
+	}
+
+	return nil
+}
+
+func isValidRole(role string) bool {
+	return role == RoleViewer || role == RoleAdmin
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CausalityConfig declares causality heuristics in addition to the built-in
+// ones, for changes made by operators Spectre does not know about
+type CausalityConfig struct {
+	Heuristics []CausalityHeuristicConfig `yaml:"heuristics"`
+}
+
+// CausalityHeuristicConfig links an effect event to an earlier cause event.
+// An effect is attributed to the first heuristic that matches the pair.
+type CausalityHeuristicConfig struct {
+	// Name identifies the heuristic on TRIGGERED_BY edges and in logs
+	Name string `yaml:"name"`
+
+	// Description is the reason recorded on the edges
+	Description string `yaml:"description,omitempty"`
+
+	Cause  CausalityEventMatcher `yaml:"cause"`
+	Effect CausalityEventMatcher `yaml:"effect"`
+
+	// SameNamespace requires both resources to be in the same namespace
+	SameNamespace bool `yaml:"sameNamespace,omitempty"`
+
+	// OwnedByCause requires the effect resource to have an owner reference
+	// to the cause resource
+	OwnedByCause bool `yaml:"ownedByCause,omitempty"`
+
+	// MinLag and MaxLag bound the time between cause and effect. MaxLag is
+	// limited by the pipeline's causality lag window.
+	MinLag Duration `yaml:"minLag,omitempty"`
+	MaxLag Duration `yaml:"maxLag"`
+
+	// Confidence is the confidence of the links, between 0 and 1
+	Confidence float64 `yaml:"confidence"`
+}
+
+// CausalityEventMatcher selects events by resource kind and event type. Empty
+// selectors match everything.
+type CausalityEventMatcher struct {
+	Kinds []string `yaml:"kinds,omitempty"`
+
+	// EventTypes are CREATE, UPDATE and DELETE
+	EventTypes []string `yaml:"eventTypes,omitempty"`
+}
+
+// CausalityLimits are the constraints of the sync pipeline that configured
+// heuristics are validated against
+type CausalityLimits struct {
+	// BuiltinNames are the names of the built-in heuristics
+	BuiltinNames []string
+
+	// MaxLag is the pipeline's causality lag window. Zero disables the check.
+	MaxLag time.Duration
+}
+
+// LoadCausalityConfig loads causality heuristics from a YAML file
+func LoadCausalityConfig(path string, limits CausalityLimits) (*CausalityConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator via flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read causality config file %s: %w", path, err)
+	}
+
+	var cfg CausalityConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse causality config YAML: %w", err)
+	}
+
+	if err := cfg.Validate(limits); err != nil {
+		return nil, fmt.Errorf("invalid causality config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks that the causality configuration is valid
+func (c *CausalityConfig) Validate(limits CausalityLimits) error {
+	names := make(map[string]bool)
+	for i, heuristic := range c.Heuristics {
+		if heuristic.Name == "" {
+			return fmt.Errorf("heuristics[%d]: name must not be empty", i)
+		}
+		if names[heuristic.Name] {
+			return fmt.Errorf("heuristics[%d]: duplicate name %q", i, heuristic.Name)
+		}
+		names[heuristic.Name] = true
+		if slices.Contains(limits.BuiltinNames, heuristic.Name) {
+			return fmt.Errorf("heuristics[%d]: name %q conflicts with a built-in heuristic", i, heuristic.Name)
+		}
+
+		for _, matcher := range []CausalityEventMatcher{heuristic.Cause, heuristic.Effect} {
+			for _, eventType := range matcher.EventTypes {
+				switch eventType {
+				case "CREATE", "UPDATE", "DELETE":
+				default:
+					return fmt.Errorf("heuristics[%d]: invalid event type %q (must be CREATE, UPDATE or DELETE)", i, eventType)
+				}
+			}
+		}
+		if heuristic.MinLag < 0 {
+			return fmt.Errorf("heuristics[%d]: minLag must not be negative", i)
+		}
+		if heuristic.MaxLag <= heuristic.MinLag {
+			return fmt.Errorf("heuristics[%d]: maxLag must be greater than minLag", i)
+		}
+		if limits.MaxLag > 0 && time.Duration(heuristic.MaxLag) > limits.MaxLag {
+			return fmt.Errorf("heuristics[%d]: maxLag must not exceed the causality lag window of %v", i, limits.MaxLag)
+		}
+		if heuristic.Confidence <= 0 || heuristic.Confidence > 1 {
+			return fmt.Errorf("heuristics[%d]: confidence must be in (0, 1]", i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCausalityConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		yaml   string
+		errMsg string
+	}{
+		{
+			name: "valid config",
+			yaml: `
+heuristics:
+  - name: db-operator-restart
+    cause:
+      kinds: [PostgresCluster]
+      eventTypes: [UPDATE]
+    effect:
+      kinds: [StatefulSet]
+    sameNamespace: true
+    ownedByCause: true
+    maxLag: 2m
+    confidence: 0.85
+`,
+		},
+		{
+			name: "invalid event type",
+			yaml: `
+heuristics:
+  - name: restart
+    effect:
+      eventTypes: [RESTART]
+    maxLag: 1m
+    confidence: 0.5
+`,
+			errMsg: "invalid event type",
+		},
+		{
+			name: "missing max lag",
+			yaml: `
+heuristics:
+  - name: restart
+    minLag: 10s
+    confidence: 0.5
+`,
+			errMsg: "maxLag must be greater than minLag",
+		},
+		{
+			name: "confidence out of range",
+			yaml: `
+heuristics:
+  - name: restart
+    maxLag: 1m
+    confidence: 1.5
+`,
+			errMsg: "confidence must be in (0, 1]",
+		},
+		{
+			name: "duplicate heuristic names",
+			yaml: `
+heuristics:
+  - name: restart
+    maxLag: 1m
+    confidence: 0.5
+  - name: restart
+    maxLag: 2m
+    confidence: 0.5
+`,
+			errMsg: "duplicate name",
+		},
+		{
+			name: "built-in heuristic name",
+			yaml: `
+heuristics:
+  - name: deployment-rollout
+    maxLag: 1m
+    confidence: 0.5
+`,
+			errMsg: "conflicts with a built-in heuristic",
+		},
+		{
+			name: "maxLag above the lag window",
+			yaml: `
+heuristics:
+  - name: slow-operator
+    maxLag: 10m
+    confidence: 0.5
+`,
+			errMsg: "maxLag must not exceed the causality lag window",
+		},
+	}
+	limits := CausalityLimits{BuiltinNames: []string{"deployment-rollout"}, MaxLag: 5 * time.Minute}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config CausalityConfig
+			if err := yaml.Unmarshal([]byte(tt.yaml), &config); err != nil {
+				t.Fatalf("Failed to unmarshal YAML: %v", err)
+			}
+
+			err := config.Validate(limits)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if got := time.Duration(config.Heuristics[0].MaxLag); got != 2*time.Minute {
+					t.Errorf("Expected maxLag 2m, got %v", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
@@ -15,8 +15,14 @@ import (
 type ReloadCallback func(config *IntegrationsFile) error
 
 // IntegrationWatcherConfig holds configuration for the IntegrationWatcher.
-type IntegrationWatcherConfig struct {
-	// FilePath is the path to the integrations YAML file to watch
+type IntegrationWatcherConfig = FileWatcherConfig
+
+// IntegrationWatcher watches the integrations config file
+type IntegrationWatcher = FileWatcher[IntegrationsFile]
+
+// FileWatcherConfig holds configuration for a FileWatcher.
+type FileWatcherConfig struct {
+	// FilePath is the path to the YAML file to watch
 	FilePath string
 
 	// DebounceMillis is the debounce period in milliseconds
@@ -25,14 +31,16 @@ type IntegrationWatcherConfig struct {
 	DebounceMillis int
 }
 
-// IntegrationWatcher watches an integrations config file for changes and triggers
-// reload callbacks with debouncing to prevent reload storms from editor save sequences.
+// FileWatcher watches a config file for changes and triggers reload callbacks
+// with debouncing to prevent reload storms from editor save sequences. T is
+// the type the file is loaded into.
 //
 // Invalid configs during reload are logged but do not crash the watcher - it continues
 // watching with the previous valid config.
-type IntegrationWatcher struct {
-	config   IntegrationWatcherConfig
-	callback ReloadCallback
+type FileWatcher[T any] struct {
+	config   FileWatcherConfig
+	load     func(path string) (*T, error)
+	callback func(config *T) error
 	cancel   context.CancelFunc
 	stopped  chan struct{}
 	ready    chan struct{} // signals when fsnotify watcher is fully initialized
@@ -47,6 +55,15 @@ type IntegrationWatcher struct {
 //
 // Returns an error if FilePath is empty.
 func NewIntegrationWatcher(config IntegrationWatcherConfig, callback ReloadCallback) (*IntegrationWatcher, error) {
+	return NewFileWatcher(config, LoadIntegrationsFile, callback)
+}
+
+// NewFileWatcher creates a new watcher for the given config file, loaded with
+// load. The callback will be invoked when the file changes and the new config
+// is valid.
+//
+// Returns an error if FilePath is empty.
+func NewFileWatcher[T any](config FileWatcherConfig, load func(path string) (*T, error), callback func(config *T) error) (*FileWatcher[T], error) {
 	if config.FilePath == "" {
 		return nil, fmt.Errorf("FilePath cannot be empty")
 	}
@@ -60,8 +77,9 @@ func NewIntegrationWatcher(config IntegrationWatcherConfig, callback ReloadCallb
 		config.DebounceMillis = 500
 	}
 
-	return &IntegrationWatcher{
+	return &FileWatcher[T]{
 		config:   config,
+		load:     load,
 		callback: callback,
 		stopped:  make(chan struct{}),
 		ready:    make(chan struct{}),
@@ -73,9 +91,9 @@ func NewIntegrationWatcher(config IntegrationWatcherConfig, callback ReloadCallb
 //
 // This method blocks until Stop() is called or the context is cancelled.
 // Returns an error if initial config load fails or callback returns error.
-func (w *IntegrationWatcher) Start(ctx context.Context) error {
+func (w *FileWatcher[T]) Start(ctx context.Context) error {
 	// Load initial config
-	initialConfig, err := LoadIntegrationsFile(w.config.FilePath)
+	initialConfig, err := w.load(w.config.FilePath)
 	if err != nil {
 		return fmt.Errorf("failed to load initial config: %w", err)
 	}
@@ -109,7 +127,7 @@ func (w *IntegrationWatcher) Start(ctx context.Context) error {
 }
 
 // signalReady safely closes the ready channel exactly once
-func (w *IntegrationWatcher) signalReady() {
+func (w *FileWatcher[T]) signalReady() {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	select {
@@ -121,7 +139,7 @@ func (w *IntegrationWatcher) signalReady() {
 }
 
 // watchLoop is the main file watching loop
-func (w *IntegrationWatcher) watchLoop(ctx context.Context) {
+func (w *FileWatcher[T]) watchLoop(ctx context.Context) {
 	defer close(w.stopped)
 	defer w.signalReady() // Ensure ready is signaled even on error paths
 
@@ -190,7 +208,7 @@ func (w *IntegrationWatcher) watchLoop(ctx context.Context) {
 
 // handleFileChange is called when a file change event is detected.
 // It implements debouncing by resetting a timer on each event.
-func (w *IntegrationWatcher) handleFileChange(ctx context.Context) {
+func (w *FileWatcher[T]) handleFileChange(ctx context.Context) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -210,11 +228,11 @@ func (w *IntegrationWatcher) handleFileChange(ctx context.Context) {
 
 // reloadConfig reloads the config file and calls the callback if successful.
 // Invalid configs are logged but don't crash the watcher.
-func (w *IntegrationWatcher) reloadConfig(ctx context.Context) {
+func (w *FileWatcher[T]) reloadConfig(ctx context.Context) {
 	log.Printf("IntegrationWatcher: reloading config from %s", w.config.FilePath)
 
 	// Load new config
-	newConfig, err := LoadIntegrationsFile(w.config.FilePath)
+	newConfig, err := w.load(w.config.FilePath)
 	if err != nil {
 		// Log error but continue watching with previous config
 		log.Printf("IntegrationWatcher: failed to load config (keeping previous config): %v", err)
@@ -234,7 +252,7 @@ func (w *IntegrationWatcher) reloadConfig(ctx context.Context) {
 // Stop gracefully stops the file watcher.
 // Waits for the watch loop to exit with a timeout of 5 seconds.
 // Returns an error if the timeout is exceeded.
-func (w *IntegrationWatcher) Stop() error {
+func (w *FileWatcher[T]) Stop() error {
 	if w.cancel != nil {
 		w.cancel()
 	}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Reference types of a RelationshipRefConfig
+const (
+	// RelationshipRefPath references resources by the names found at a path
+	RelationshipRefPath = "path"
+
+	// RelationshipRefSelector selects resources by the labels found at a path
+	RelationshipRefSelector = "selector"
+
+	// RelationshipRefOwner references resources by objects with kind, name,
+	// namespace and uid fields, as in ownerReferences
+	RelationshipRefOwner = "owner"
+)
+
+// RelationshipsConfig declares relationships of custom resources that have no
+// built-in extractor
+type RelationshipsConfig struct {
+	Extractors []RelationshipExtractorConfig `yaml:"extractors"`
+}
+
+// RelationshipExtractorConfig declares the references of one resource kind
+type RelationshipExtractorConfig struct {
+	// Name identifies the extractor in logs. Defaults to config-<kind>.
+	Name string `yaml:"name,omitempty"`
+
+	Group string `yaml:"group"`
+	Kind  string `yaml:"kind"`
+
+	// Priority orders the extractor among the others matching the kind.
+	// Lower runs earlier. Defaults to running after the built-in extractors.
+	Priority int `yaml:"priority,omitempty"`
+
+	Refs []RelationshipRefConfig `yaml:"refs"`
+}
+
+// RelationshipRefConfig declares a reference from the resource to others
+type RelationshipRefConfig struct {
+	// Type is path (default), selector or owner
+	Type string `yaml:"type,omitempty"`
+
+	// Path locates the reference in the resource, e.g. spec.workloadRef.name
+	// or spec.targets[*].name
+	Path string `yaml:"path"`
+
+	// TargetKind is the kind of the referenced resources. Owner references
+	// may leave it empty to use the kind field of the reference.
+	TargetKind  string `yaml:"targetKind,omitempty"`
+	TargetGroup string `yaml:"targetGroup,omitempty"`
+
+	// Namespace of the referenced resources. Defaults to the namespace of the
+	// resource.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// ClusterScoped marks the referenced resources as cluster-scoped
+	ClusterScoped bool `yaml:"clusterScoped,omitempty"`
+
+	// Edge is the edge type, REFERENCES_SPEC, MANAGES, SELECTS or OWNS.
+	// Defaults to SELECTS for selectors and REFERENCES_SPEC otherwise.
+	Edge string `yaml:"edge,omitempty"`
+
+	// Reverse points the edge from the referenced resource to the resource
+	Reverse bool `yaml:"reverse,omitempty"`
+}
+
+// LoadRelationshipsConfig loads relationship extractors from a YAML file
+func LoadRelationshipsConfig(path string) (*RelationshipsConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator via flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relationships config file %s: %w", path, err)
+	}
+
+	var cfg RelationshipsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse relationships config YAML: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid relationships config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks that the relationships configuration is valid
+func (c *RelationshipsConfig) Validate() error {
+	for i, extractor := range c.Extractors {
+		if extractor.Kind == "" {
+			return fmt.Errorf("extractors[%d]: kind must not be empty", i)
+		}
+		if len(extractor.Refs) == 0 {
+			return fmt.Errorf("extractors[%d]: at least one ref is required", i)
+		}
+
+		for j, ref := range extractor.Refs {
+			if ref.Path == "" {
+				return fmt.Errorf("extractors[%d].refs[%d]: path must not be empty", i, j)
+			}
+			switch ref.Type {
+			case "", RelationshipRefPath, RelationshipRefSelector:
+				if ref.TargetKind == "" {
+					return fmt.Errorf("extractors[%d].refs[%d]: targetKind must not be empty", i, j)
+				}
+			case RelationshipRefOwner:
+			default:
+				return fmt.Errorf("extractors[%d].refs[%d]: invalid type %q (must be path, selector or owner)", i, j, ref.Type)
+			}
+			switch ref.Edge {
+			case "", "REFERENCES_SPEC", "MANAGES", "SELECTS", "OWNS":
+			default:
+				return fmt.Errorf("extractors[%d].refs[%d]: invalid edge %q (must be REFERENCES_SPEC, MANAGES, SELECTS or OWNS)", i, j, ref.Edge)
+			}
+			if ref.ClusterScoped && ref.Namespace != "" {
+				return fmt.Errorf("extractors[%d].refs[%d]: namespace must not be set for cluster-scoped targets", i, j)
+			}
+		}
+	}
+
+	return nil
+}
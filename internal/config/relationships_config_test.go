@@ -0,0 +1,98 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRelationshipsConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		yaml   string
+		errMsg string
+	}{
+		{
+			name: "valid config",
+			yaml: `
+extractors:
+  - group: argoproj.io
+    kind: Rollout
+    refs:
+      - path: spec.workloadRef.name
+        targetKind: Deployment
+        edge: MANAGES
+      - type: selector
+        path: spec.selector
+        targetKind: Pod
+      - type: owner
+        path: spec.analysisRefs[*]
+`,
+		},
+		{
+			name: "missing kind",
+			yaml: `
+extractors:
+  - group: argoproj.io
+    refs:
+      - path: spec.workloadRef.name
+        targetKind: Deployment
+`,
+			errMsg: "kind must not be empty",
+		},
+		{
+			name: "missing target kind",
+			yaml: `
+extractors:
+  - kind: Rollout
+    refs:
+      - path: spec.workloadRef.name
+`,
+			errMsg: "targetKind must not be empty",
+		},
+		{
+			name: "invalid ref type",
+			yaml: `
+extractors:
+  - kind: Rollout
+    refs:
+      - type: annotation
+        path: metadata.annotations
+`,
+			errMsg: "invalid type",
+		},
+		{
+			name: "invalid edge",
+			yaml: `
+extractors:
+  - kind: Rollout
+    refs:
+      - path: spec.workloadRef.name
+        targetKind: Deployment
+        edge: TRIGGERED_BY
+`,
+			errMsg: "invalid edge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config RelationshipsConfig
+			if err := yaml.Unmarshal([]byte(tt.yaml), &config); err != nil {
+				t.Fatalf("Failed to unmarshal YAML: %v", err)
+			}
+
+			err := config.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RetentionConfig configures how long the graph keeps events beyond the
+// single --graph-retention-hours window
+type RetentionConfig struct {
+	// Policies keep the events they match for their own retention instead of
+	// the window. Each event belongs to the first policy that matches it.
+	Policies []RetentionPolicyConfig `yaml:"policies,omitempty"`
+
+	// CompactAfter collapses runs of status-only updates of a resource older
+	// than this into one summary event. Zero (default) disables compaction.
+	CompactAfter Duration `yaml:"compactAfter,omitempty"`
+}
+
+// RetentionPolicyConfig selects events by resource kind, namespace and event
+// type. Empty selectors match everything.
+type RetentionPolicyConfig struct {
+	// Name identifies the policy in logs
+	Name string `yaml:"name"`
+
+	// Source is "change" (resource changes), "k8s" (Kubernetes events) or
+	// empty for both
+	Source string `yaml:"source,omitempty"`
+
+	Kinds      []string `yaml:"kinds,omitempty"`
+	Namespaces []string `yaml:"namespaces,omitempty"`
+
+	// Types are change event types (CREATE, UPDATE, DELETE) or Kubernetes
+	// event types (Normal, Warning), depending on the source
+	Types []string `yaml:"types,omitempty"`
+
+	// Changes selects "spec" changes or "status"-only updates of resources
+	Changes string `yaml:"changes,omitempty"`
+
+	// Retention is how long matching events are kept, e.g. "6h" or "90d"
+	Retention Duration `yaml:"retention"`
+}
+
+// Duration is a time.Duration that also accepts a number of days ("90d")
+type Duration time.Duration
+
+// UnmarshalYAML parses a duration string
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseDuration(value.Value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ParseDuration parses a Go duration string or a number of days ("90d")
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// LoadRetentionConfig loads the retention configuration from a YAML file
+func LoadRetentionConfig(path string) (*RetentionConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator via flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retention config file %s: %w", path, err)
+	}
+
+	var cfg RetentionConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse retention config YAML: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retention config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks that the retention configuration is valid
+func (c *RetentionConfig) Validate() error {
+	if c.CompactAfter < 0 {
+		return fmt.Errorf("compactAfter must not be negative")
+	}
+
+	names := make(map[string]bool)
+	for i, policy := range c.Policies {
+		if policy.Name == "" {
+			return fmt.Errorf("policies[%d]: name must not be empty", i)
+		}
+		if names[policy.Name] {
+			return fmt.Errorf("policies[%d]: duplicate name %q", i, policy.Name)
+		}
+		names[policy.Name] = true
+
+		switch policy.Source {
+		case "", "change", "k8s":
+		default:
+			return fmt.Errorf("policies[%d]: invalid source %q (must be change or k8s)", i, policy.Source)
+		}
+		switch policy.Changes {
+		case "":
+		case "spec", "status":
+			if policy.Source == "k8s" {
+				return fmt.Errorf("policies[%d]: changes only applies to the change source", i)
+			}
+		default:
+			return fmt.Errorf("policies[%d]: invalid changes %q (must be spec or status)", i, policy.Changes)
+		}
+		if policy.Retention <= 0 {
+			return fmt.Errorf("policies[%d]: retention must be positive", i)
+		}
+	}
+
+	return nil
+}
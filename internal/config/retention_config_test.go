@@ -0,0 +1,117 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRetentionConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		yaml   string
+		errMsg string
+	}{
+		{
+			name: "valid config",
+			yaml: `
+compactAfter: 12h
+policies:
+  - name: deployment-spec
+    source: change
+    kinds: [Deployment]
+    changes: spec
+    retention: 90d
+  - name: normal-events
+    source: k8s
+    types: [Normal]
+    retention: 6h
+`,
+		},
+		{
+			name: "missing retention",
+			yaml: `
+policies:
+  - name: pods
+    kinds: [Pod]
+`,
+			errMsg: "retention must be positive",
+		},
+		{
+			name: "changes on k8s events",
+			yaml: `
+policies:
+  - name: events
+    source: k8s
+    changes: status
+    retention: 1h
+`,
+			errMsg: "changes only applies to the change source",
+		},
+		{
+			name: "invalid source",
+			yaml: `
+policies:
+  - name: events
+    source: audit
+    retention: 1h
+`,
+			errMsg: "invalid source",
+		},
+		{
+			name: "duplicate policy names",
+			yaml: `
+policies:
+  - name: pods
+    retention: 1h
+  - name: pods
+    retention: 2h
+`,
+			errMsg: "duplicate name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config RetentionConfig
+			if err := yaml.Unmarshal([]byte(tt.yaml), &config); err != nil {
+				t.Fatalf("Failed to unmarshal YAML: %v", err)
+			}
+
+			err := config.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90d":   90 * 24 * time.Hour,
+		"6h":    6 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	}
+	for input, want := range tests {
+		got, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) failed: %v", input, err)
+		} else if got != want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "d", "1.5d", "soon"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", input)
+		}
+	}
+}
@@ -3,13 +3,81 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // WatcherConfig represents the configuration for watchers
 type WatcherConfig struct {
 	Resources []Resource `yaml:"resources"`
+
+	// AutoDiscovery is set by "resources: auto": every watchable resource the
+	// API server serves is watched instead of an explicit list
+	AutoDiscovery bool `yaml:"-"`
+
+	// Discovery narrows down the API groups watched in auto discovery mode
+	Discovery DiscoveryConfig `yaml:"discovery,omitempty"`
+
+	// Redaction controls how sensitive values are scrubbed from captured objects
+	// before they are written to the graph or the audit log
+	Redaction RedactionConfig `yaml:"redaction,omitempty"`
+}
+
+// ResourcesAuto is the value of "resources" that enables auto discovery
+const ResourcesAuto = "auto"
+
+// DiscoveryConfig selects the API groups watched in auto discovery mode.
+// Patterns are globs or /regular expressions/ matched against the group
+// name; the core group is matched as "core".
+type DiscoveryConfig struct {
+	// IncludeGroups lists the groups to watch, empty includes all groups
+	IncludeGroups []string `yaml:"includeGroups,omitempty"`
+
+	// ExcludeGroups lists groups to skip, even if included
+	ExcludeGroups []string `yaml:"excludeGroups,omitempty"`
+}
+
+// RedactionConfig configures the watcher's sensitive-field redaction.
+// Redaction is enabled by default; the built-in rules cover Secret data,
+// sensitive-looking container env values and the last-applied-configuration
+// annotation.
+type RedactionConfig struct {
+	// Disabled turns off all redaction, including the built-in defaults
+	Disabled bool `yaml:"disabled,omitempty"`
+
+	// DisableDefaults skips the built-in rules so only Rules are applied
+	DisableDefaults bool `yaml:"disableDefaults,omitempty"`
+
+	// EnvNamePatterns are regular expressions matched against container env
+	// variable names; matching entries have their value redacted.
+	// If empty, a built-in pattern list is used (unless DisableDefaults is set).
+	EnvNamePatterns []string `yaml:"envNamePatterns,omitempty"`
+
+	// HashSalt is mixed into the value hashes so that low-entropy secrets
+	// cannot be recovered by hashing candidate values
+	HashSalt string `yaml:"hashSalt,omitempty"`
+
+	// HashSaltFile holds the salt when HashSalt is empty. A random salt is
+	// generated and written to it if the file doesn't exist. Without either,
+	// a random salt is generated at startup, so hashes change on restart.
+	HashSaltFile string `yaml:"hashSaltFile,omitempty"`
+
+	// Rules are additional per-GVK redaction rules
+	Rules []RedactionRule `yaml:"rules,omitempty"`
+}
+
+// RedactionRule redacts the values at the given JSONPath expressions for
+// objects matching the group/kind. An empty Group matches the core group;
+// Group "*" and Kind "*" match every group and kind respectively.
+type RedactionRule struct {
+	Group   string   `yaml:"group"`
+	Version string   `yaml:"version,omitempty"` // Optional, empty matches all versions
+	Kind    string   `yaml:"kind"`
+	Paths   []string `yaml:"paths"`
 }
 
 // Resource represents a single resource to watch
@@ -18,6 +86,51 @@ type Resource struct {
 	Version   string `yaml:"version"`
 	Kind      string `yaml:"kind"`
 	Namespace string `yaml:"namespace,omitempty"` // Optional, empty means cluster-wide
+
+	// LabelSelector restricts the watch to objects matching a Kubernetes
+	// label selector, e.g. "app.kubernetes.io/part-of=checkout"
+	LabelSelector string `yaml:"labelSelector,omitempty"`
+
+	// FieldSelector restricts the watch to objects matching a Kubernetes
+	// field selector, e.g. "type!=helm.sh/release.v1" for Secrets
+	FieldSelector string `yaml:"fieldSelector,omitempty"`
+
+	// ExcludeNamespaces skips objects in matching namespaces. Patterns are
+	// globs ("kube-*"), or regular expressions when enclosed in slashes ("/^team-.+-dev$/").
+	ExcludeNamespaces []string `yaml:"excludeNamespaces,omitempty"`
+
+	// ExcludeLabels skips objects matching any of these label selectors
+	ExcludeLabels []string `yaml:"excludeLabels,omitempty"`
+}
+
+// UnmarshalYAML accepts "resources: auto" in addition to a resource list
+func (wc *WatcherConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain WatcherConfig
+
+	node := *value
+	auto := false
+	if value.Kind == yaml.MappingNode {
+		node.Content = make([]*yaml.Node, 0, len(value.Content))
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key, val := value.Content[i], value.Content[i+1]
+			if key.Value == "resources" && val.Kind == yaml.ScalarNode && val.Tag != "!!null" {
+				if val.Value != ResourcesAuto {
+					return fmt.Errorf("line %d: resources must be a list or %q", val.Line, ResourcesAuto)
+				}
+				auto = true
+				continue
+			}
+			node.Content = append(node.Content, key, val)
+		}
+	}
+
+	var config plain
+	if err := node.Decode(&config); err != nil {
+		return err
+	}
+	*wc = WatcherConfig(config)
+	wc.AutoDiscovery = auto
+	return nil
 }
 
 // LoadWatcherConfig loads the watcher configuration from a YAML file
@@ -41,7 +154,11 @@ func LoadWatcherConfig(path string) (*WatcherConfig, error) {
 
 // Validate checks that the watcher configuration is valid
 func (wc *WatcherConfig) Validate() error {
-	if len(wc.Resources) == 0 {
+	if wc.AutoDiscovery {
+		if err := wc.Discovery.Validate(); err != nil {
+			return fmt.Errorf("invalid discovery config: %w", err)
+		}
+	} else if len(wc.Resources) == 0 {
 		return fmt.Errorf("at least one resource must be specified")
 	}
 
@@ -53,6 +170,114 @@ func (wc *WatcherConfig) Validate() error {
 		if resource.Kind == "" {
 			return fmt.Errorf("resource[%d]: kind must not be empty", i)
 		}
+		if _, err := labels.Parse(resource.LabelSelector); err != nil {
+			return fmt.Errorf("resource[%d]: invalid labelSelector %q: %w", i, resource.LabelSelector, err)
+		}
+		if _, err := fields.ParseSelector(resource.FieldSelector); err != nil {
+			return fmt.Errorf("resource[%d]: invalid fieldSelector %q: %w", i, resource.FieldSelector, err)
+		}
+		for j, pattern := range resource.ExcludeNamespaces {
+			if _, err := NamePattern(pattern); err != nil {
+				return fmt.Errorf("resource[%d].excludeNamespaces[%d]: %w", i, j, err)
+			}
+		}
+		for j, selector := range resource.ExcludeLabels {
+			if _, err := labels.Parse(selector); err != nil || selector == "" {
+				return fmt.Errorf("resource[%d].excludeLabels[%d]: invalid label selector %q", i, j, selector)
+			}
+		}
+	}
+
+	if err := wc.Redaction.Validate(); err != nil {
+		return fmt.Errorf("redaction: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks that the group patterns compile
+func (dc *DiscoveryConfig) Validate() error {
+	for i, pattern := range dc.IncludeGroups {
+		if _, err := NamePattern(pattern); err != nil {
+			return fmt.Errorf("includeGroups[%d]: %w", i, err)
+		}
+	}
+	for i, pattern := range dc.ExcludeGroups {
+		if _, err := NamePattern(pattern); err != nil {
+			return fmt.Errorf("excludeGroups[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MatchesGroup reports whether resources of the API group are watched in
+// auto discovery mode
+func (dc *DiscoveryConfig) MatchesGroup(group string) bool {
+	if group == "" {
+		group = "core"
+	}
+
+	included := len(dc.IncludeGroups) == 0
+	for _, pattern := range dc.IncludeGroups {
+		if re, err := NamePattern(pattern); err == nil && re.MatchString(group) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, pattern := range dc.ExcludeGroups {
+		if re, err := NamePattern(pattern); err == nil && re.MatchString(group) {
+			return false
+		}
+	}
+	return true
+}
+
+// NamePattern compiles a name pattern such as an excludeNamespaces entry. A
+// pattern enclosed in slashes is a regular expression matched against the
+// name, anything else is a glob where * matches any sequence of characters
+// and ? a single one.
+func NamePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern must not be empty")
+	}
+
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		return re, nil
+	}
+
+	glob := regexp.QuoteMeta(pattern)
+	glob = strings.ReplaceAll(glob, `\*`, ".*")
+	glob = strings.ReplaceAll(glob, `\?`, ".")
+	return regexp.Compile("^" + glob + "$")
+}
+
+// Validate checks that the redaction configuration is valid
+func (rc *RedactionConfig) Validate() error {
+	for i, pattern := range rc.EnvNamePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("envNamePatterns[%d]: invalid regular expression %q: %w", i, pattern, err)
+		}
+	}
+
+	for i, rule := range rc.Rules {
+		if rule.Kind == "" {
+			return fmt.Errorf("rules[%d]: kind must not be empty", i)
+		}
+		if len(rule.Paths) == 0 {
+			return fmt.Errorf("rules[%d]: at least one path must be specified", i)
+		}
+		for j, path := range rule.Paths {
+			if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "$") {
+				return fmt.Errorf("rules[%d].paths[%d]: path %q must start with '.' or '$'", i, j, path)
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWatcherConfigValidation_Selectors(t *testing.T) {
+	tests := []struct {
+		name   string
+		yaml   string
+		errMsg string
+	}{
+		{
+			name: "valid selectors",
+			yaml: `
+resources:
+  - version: v1
+    kind: Secret
+    labelSelector: app.kubernetes.io/part-of=checkout
+    fieldSelector: type!=helm.sh/release.v1
+    excludeNamespaces: ["kube-*", "/^team-.+-dev$/"]
+    excludeLabels: ["spectre.dev/ignore"]
+`,
+		},
+		{
+			name: "invalid label selector",
+			yaml: `
+resources:
+  - version: v1
+    kind: Pod
+    labelSelector: "app in (web"
+`,
+			errMsg: "invalid labelSelector",
+		},
+		{
+			name: "invalid field selector",
+			yaml: `
+resources:
+  - version: v1
+    kind: Pod
+    fieldSelector: "status.phase"
+`,
+			errMsg: "invalid fieldSelector",
+		},
+		{
+			name: "invalid namespace regex",
+			yaml: `
+resources:
+  - version: v1
+    kind: Pod
+    excludeNamespaces: ["/team-(/"]
+`,
+			errMsg: "excludeNamespaces[0]",
+		},
+		{
+			name: "empty exclude label",
+			yaml: `
+resources:
+  - version: v1
+    kind: Pod
+    excludeLabels: [""]
+`,
+			errMsg: "excludeLabels[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg WatcherConfig
+			if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			err := cfg.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestNamePattern(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		namespace string
+		expected  bool
+	}{
+		{"kube-*", "kube-system", true},
+		{"kube-*", "my-kube-system", false},
+		{"team-?", "team-a", true},
+		{"team-?", "team-ab", false},
+		{"prod.eu", "prodXeu", false},
+		{"/^team-.+-dev$/", "team-a-dev", true},
+		{"/^team-.+-dev$/", "team-a-prod", false},
+	}
+
+	for _, tt := range tests {
+		re, err := NamePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.namespace); got != tt.expected {
+			t.Errorf("NamePattern(%q).MatchString(%q) = %v, want %v", tt.pattern, tt.namespace, got, tt.expected)
+		}
+	}
+}
+
+func TestWatcherConfig_AutoDiscovery(t *testing.T) {
+	var cfg WatcherConfig
+	err := yaml.Unmarshal([]byte(`
+resources: auto
+discovery:
+  excludeGroups: ["*.metrics.k8s.io", "/^(coordination|events)\\.k8s\\.io$/"]
+redaction:
+  hashSalt: salt
+`), &cfg)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.AutoDiscovery || len(cfg.Resources) != 0 || cfg.Redaction.HashSalt != "salt" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	groups := map[string]bool{
+		"":                       true,
+		"apps":                   true,
+		"custom.metrics.k8s.io":  false,
+		"events.k8s.io":          false,
+		"helm.toolkit.fluxcd.io": true,
+	}
+	for group, expected := range groups {
+		if got := cfg.Discovery.MatchesGroup(group); got != expected {
+			t.Errorf("MatchesGroup(%q) = %v, want %v", group, got, expected)
+		}
+	}
+
+	cfg.Discovery = DiscoveryConfig{IncludeGroups: []string{"core", "apps"}}
+	if !cfg.Discovery.MatchesGroup("") || cfg.Discovery.MatchesGroup("batch") {
+		t.Errorf("includeGroups not applied")
+	}
+
+	var invalid WatcherConfig
+	if err := yaml.Unmarshal([]byte("resources: all"), &invalid); err == nil {
+		t.Errorf("expected error for resources: all")
+	}
+}
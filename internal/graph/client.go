@@ -56,7 +56,29 @@ type Client interface {
 	GraphExists(ctx context.Context, graphName string) (bool, error)
 }
 
-// ClientConfig holds configuration for the FalkorDB client
+// Backend selects the graph storage implementation
+type Backend string
+
+const (
+	// BackendFalkorDB stores the graph in a FalkorDB server
+	BackendFalkorDB Backend = "falkordb"
+	// BackendMemory keeps the graph in process memory. It needs no external
+	// service, but the graph does not survive a restart.
+	BackendMemory Backend = "memory"
+)
+
+// ParseBackend validates a backend name. An empty name selects FalkorDB.
+func ParseBackend(name string) (Backend, error) {
+	switch Backend(name) {
+	case "", BackendFalkorDB:
+		return BackendFalkorDB, nil
+	case BackendMemory:
+		return BackendMemory, nil
+	}
+	return "", fmt.Errorf("unknown graph backend %q (supported: %s, %s)", name, BackendFalkorDB, BackendMemory)
+}
+
+// ClientConfig holds configuration for the graph client
 type ClientConfig struct {
 	Host         string        // FalkorDB host
 	Port         int           // FalkorDB port
@@ -68,6 +90,9 @@ type ClientConfig struct {
 	WriteTimeout time.Duration // write timeout
 	PoolSize     int           // connection pool size
 
+	// Backend selects the storage implementation (default: falkordb)
+	Backend Backend
+
 	// Query cache settings
 	QueryCacheEnabled  bool          // Enable query caching (default: false)
 	QueryCacheMemoryMB int64         // Max cache memory in MB (default: 64)
@@ -86,6 +111,7 @@ func DefaultClientConfig() ClientConfig {
 		ReadTimeout:  120 * time.Second,
 		WriteTimeout: 120 * time.Second,
 		PoolSize:     10,
+		Backend:      BackendFalkorDB,
 
 		// Query cache defaults
 		QueryCacheEnabled:  false,
@@ -94,19 +120,39 @@ func DefaultClientConfig() ClientConfig {
 	}
 }
 
+// cypherClient implements the Client methods that are plain Cypher queries on
+// top of a backend's ExecuteQuery
+type cypherClient struct {
+	execute   func(ctx context.Context, query GraphQuery) (*QueryResult, error)
+	logger    *logging.Logger
+	graphName string
+}
+
 // falkorClient implements the Client interface using FalkorDB Go client
 type falkorClient struct {
+	cypherClient
 	config ClientConfig
 	logger *logging.Logger
 	db     *falkordb.FalkorDB
 	graph  *falkordb.Graph
 }
 
-// NewClient creates a new FalkorDB client, optionally with query caching
+// NewClient creates a new graph client for the configured backend, optionally
+// with query caching
 func NewClient(config ClientConfig) Client {
-	client := &falkorClient{
-		config: config,
-		logger: logging.GetLogger("graph.client"),
+	logger := logging.GetLogger("graph.client")
+
+	var client Client
+	switch config.Backend {
+	case BackendMemory:
+		client = newMemoryClient(config, logger)
+	default:
+		falkor := &falkorClient{
+			config: config,
+			logger: logger,
+		}
+		falkor.cypherClient = cypherClient{execute: falkor.ExecuteQuery, logger: logger, graphName: config.GraphName}
+		client = falkor
 	}
 
 	// Wrap with caching if enabled
@@ -120,7 +166,7 @@ func NewClient(config ClientConfig) Client {
 		cachedClient, err := NewCachedClient(client, cacheConfig, logging.GetLogger("graph.cache"))
 		if err != nil {
 			// Log error but continue without caching
-			client.logger.Warn("Failed to create query cache, continuing without caching: %v", err)
+			logger.Warn("Failed to create query cache, continuing without caching: %v", err)
 			return client
 		}
 		return cachedClient
@@ -192,10 +238,18 @@ func (c *falkorClient) ExecuteQuery(ctx context.Context, query GraphQuery) (*Que
 		options = falkordb.NewQueryOptions().SetTimeout(query.Timeout)
 	}
 
+	// Bind parameters through FalkorDB's CYPHER prefix. Values never become
+	// part of the query text, so the query plan is cached per query shape.
+	// The header is built here rather than by the FalkorDB client, whose
+	// encoder panics on types it does not know and emits Go-only escapes.
+	paramsHeader, err := buildParamsHeader(query.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query parameters: %w", err)
+	}
+
 	// Execute query using FalkorDB client
-	// The FalkorDB client handles parameter substitution internally
 	startTime := time.Now()
-	result, err := c.graph.Query(query.Query, query.Parameters, options)
+	result, err := c.graph.Query(paramsHeader+query.Query, nil, options)
 	executionTime := time.Since(startTime)
 
 	if err != nil {
@@ -246,7 +300,7 @@ func convertFalkorDBResult(result *falkordb.QueryResult) *QueryResult {
 }
 
 // CreateNode creates a node in the graph
-func (c *falkorClient) CreateNode(ctx context.Context, nodeType NodeType, properties interface{}) error {
+func (c *cypherClient) CreateNode(ctx context.Context, nodeType NodeType, properties interface{}) error {
 	// Convert properties to JSON for storage
 	propsJSON, err := json.Marshal(properties)
 	if err != nil {
@@ -259,20 +313,21 @@ func (c *falkorClient) CreateNode(ctx context.Context, nodeType NodeType, proper
 	}
 
 	// Build Cypher CREATE statement
-	propsStr := buildPropertiesString(propsMap)
-	cypherQuery := fmt.Sprintf("CREATE (n:%s %s)", nodeType, propsStr)
+	params := make(map[string]interface{}, len(propsMap))
+	propsPattern := propertiesPattern(propsMap, "prop", params)
+	cypherQuery := fmt.Sprintf("CREATE (n:%s %s)", nodeType, propsPattern)
 
 	query := GraphQuery{
 		Query:      cypherQuery,
-		Parameters: nil,
+		Parameters: params,
 	}
 
-	_, err = c.ExecuteQuery(ctx, query)
+	_, err = c.execute(ctx, query)
 	return err
 }
 
 // CreateEdge creates an edge between two nodes
-func (c *falkorClient) CreateEdge(ctx context.Context, edgeType EdgeType, fromUID, toUID string, properties interface{}) error {
+func (c *cypherClient) CreateEdge(ctx context.Context, edgeType EdgeType, fromUID, toUID string, properties interface{}) error {
 	// Convert properties to JSON
 	propsJSON, err := json.Marshal(properties)
 	if err != nil {
@@ -285,38 +340,36 @@ func (c *falkorClient) CreateEdge(ctx context.Context, edgeType EdgeType, fromUI
 	}
 
 	// Build Cypher MATCH + CREATE statement
-	propsStr := buildPropertiesString(propsMap)
+	params := map[string]interface{}{
+		"fromUID": fromUID,
+		"toUID":   toUID,
+	}
+	propsPattern := propertiesPattern(propsMap, "prop", params)
 	cypherQuery := fmt.Sprintf(
-		"MATCH (a {uid: '%s'}), (b {uid: '%s'}) CREATE (a)-[r:%s %s]->(b)",
-		escapeCypherString(fromUID),
-		escapeCypherString(toUID),
+		"MATCH (a {uid: $fromUID}), (b {uid: $toUID}) CREATE (a)-[r:%s %s]->(b)",
 		edgeType,
-		propsStr,
+		propsPattern,
 	)
 
 	query := GraphQuery{
 		Query:      cypherQuery,
-		Parameters: nil,
+		Parameters: params,
 	}
 
-	_, err = c.ExecuteQuery(ctx, query)
+	_, err = c.execute(ctx, query)
 	return err
 }
 
 // GetNode retrieves a node by UID
-func (c *falkorClient) GetNode(ctx context.Context, nodeType NodeType, uid string) (*Node, error) {
-	cypherQuery := fmt.Sprintf(
-		"MATCH (n:%s {uid: '%s'}) RETURN n",
-		nodeType,
-		escapeCypherString(uid),
-	)
+func (c *cypherClient) GetNode(ctx context.Context, nodeType NodeType, uid string) (*Node, error) {
+	cypherQuery := fmt.Sprintf("MATCH (n:%s {uid: $uid}) RETURN n", nodeType)
 
 	query := GraphQuery{
 		Query:      cypherQuery,
-		Parameters: nil,
+		Parameters: map[string]interface{}{"uid": uid},
 	}
 
-	result, err := c.ExecuteQuery(ctx, query)
+	result, err := c.execute(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -349,20 +402,19 @@ func (c *falkorClient) GetNode(ctx context.Context, nodeType NodeType, uid strin
 }
 
 // DeleteNodesByTimestamp deletes nodes older than the given timestamp
-func (c *falkorClient) DeleteNodesByTimestamp(ctx context.Context, nodeType NodeType, timestampField string, cutoffNs int64) (int, error) {
+func (c *cypherClient) DeleteNodesByTimestamp(ctx context.Context, nodeType NodeType, timestampField string, cutoffNs int64) (int, error) {
 	cypherQuery := fmt.Sprintf(
-		"MATCH (n:%s) WHERE n.%s < %d DETACH DELETE n",
+		"MATCH (n:%s) WHERE n.%s < $cutoff DETACH DELETE n",
 		nodeType,
-		timestampField,
-		cutoffNs,
+		cypherIdentifier(timestampField),
 	)
 
 	query := GraphQuery{
 		Query:      cypherQuery,
-		Parameters: nil,
+		Parameters: map[string]interface{}{"cutoff": cutoffNs},
 	}
 
-	result, err := c.ExecuteQuery(ctx, query)
+	result, err := c.execute(ctx, query)
 	if err != nil {
 		return 0, err
 	}
@@ -371,14 +423,14 @@ func (c *falkorClient) DeleteNodesByTimestamp(ctx context.Context, nodeType Node
 }
 
 // GetGraphStats retrieves overall graph statistics
-func (c *falkorClient) GetGraphStats(ctx context.Context) (*GraphStats, error) {
+func (c *cypherClient) GetGraphStats(ctx context.Context) (*GraphStats, error) {
 	// Query node counts by type
 	nodeCountQuery := `
 		MATCH (n)
 		RETURN labels(n)[0] as type, count(n) as count
 	`
 
-	nodeResult, err := c.ExecuteQuery(ctx, GraphQuery{Query: nodeCountQuery})
+	nodeResult, err := c.execute(ctx, GraphQuery{Query: nodeCountQuery})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query node counts: %w", err)
 	}
@@ -389,7 +441,7 @@ func (c *falkorClient) GetGraphStats(ctx context.Context) (*GraphStats, error) {
 		RETURN type(r) as type, count(r) as count
 	`
 
-	edgeResult, err := c.ExecuteQuery(ctx, GraphQuery{Query: edgeCountQuery})
+	edgeResult, err := c.execute(ctx, GraphQuery{Query: edgeCountQuery})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query edge counts: %w", err)
 	}
@@ -400,7 +452,7 @@ func (c *falkorClient) GetGraphStats(ctx context.Context) (*GraphStats, error) {
 		RETURN min(e.timestamp) as oldest, max(e.timestamp) as newest
 	`
 
-	timestampResult, err := c.ExecuteQuery(ctx, GraphQuery{Query: timestampQuery})
+	timestampResult, err := c.execute(ctx, GraphQuery{Query: timestampQuery})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query timestamps: %w", err)
 	}
@@ -470,8 +522,8 @@ func (c *falkorClient) GetGraphStats(ctx context.Context) (*GraphStats, error) {
 }
 
 // InitializeSchema creates indexes and constraints
-func (c *falkorClient) InitializeSchema(ctx context.Context) error {
-	c.logger.Info("Initializing graph schema for graph: %s", c.config.GraphName)
+func (c *cypherClient) InitializeSchema(ctx context.Context) error {
+	c.logger.Info("Initializing graph schema for graph: %s", c.graphName)
 
 	// FalkorDB indexes are created using:
 	// GRAPH.QUERY graphName "CREATE INDEX ON :Label(property)"
@@ -482,24 +534,29 @@ func (c *falkorClient) InitializeSchema(ctx context.Context) error {
 	// 3. ChangeEvent.id (idempotency)
 	// 4. K8sEvent.timestamp
 	// 5. Composite indexes for namespace graph queries
+	// 6. Full-text indexes over event and error messages (/v1/events/search)
 
 	indexes := []string{
 		// Primary indexes
 		"CREATE INDEX FOR (n:ResourceIdentity) ON (n.uid)",
 		"CREATE INDEX FOR (n:ResourceIdentity) ON (n.kind)",
 		"CREATE INDEX FOR (n:ResourceIdentity) ON (n.namespace)",
+		"CREATE INDEX FOR (n:ResourceIdentity) ON (n.cluster)",
 		"CREATE INDEX FOR (n:ResourceIdentity) ON (n.deleted)",
 		"CREATE INDEX FOR (n:ResourceIdentity) ON (n.firstSeen)",
 		"CREATE INDEX FOR (n:ChangeEvent) ON (n.id)",
 		"CREATE INDEX FOR (n:ChangeEvent) ON (n.timestamp)",
 		"CREATE INDEX FOR (n:ChangeEvent) ON (n.status)",
 		"CREATE INDEX FOR (n:K8sEvent) ON (n.timestamp)",
+		// Full-text indexes
+		"CREATE FULLTEXT INDEX FOR (n:K8sEvent) ON (n.message)",
+		"CREATE FULLTEXT INDEX FOR (n:ChangeEvent) ON (n.errorMessage)",
 		// Dashboard indexes
 		"CREATE INDEX FOR (n:Dashboard) ON (n.uid)",
 	}
 
 	for _, indexQuery := range indexes {
-		_, err := c.ExecuteQuery(ctx, GraphQuery{Query: indexQuery})
+		_, err := c.execute(ctx, GraphQuery{Query: indexQuery})
 		if err != nil {
 			// FalkorDB may return error if index already exists, log but continue
 			c.logger.Warn("Failed to create index (may already exist): %v", err)
@@ -610,102 +667,6 @@ func (c *falkorClient) GraphExists(ctx context.Context, graphName string) (bool,
 
 // Helper functions
 
-// buildPropertiesString converts a map to Cypher property syntax
-// Example: {name: "foo", age: 30} -> {name: 'foo', age: 30}
-func buildPropertiesString(props map[string]interface{}) string {
-	if len(props) == 0 {
-		return ""
-	}
-
-	parts := make([]string, 0, len(props))
-	for key, value := range props {
-		var valueStr string
-		switch v := value.(type) {
-		case string:
-			valueStr = fmt.Sprintf("'%s'", escapeCypherString(v))
-		case bool:
-			valueStr = fmt.Sprintf("%t", v)
-		case int, int64, float64:
-			valueStr = fmt.Sprintf("%v", v)
-		case []string:
-			// Array of strings
-			escaped := make([]string, len(v))
-			for i, s := range v {
-				escaped[i] = fmt.Sprintf("'%s'", escapeCypherString(s))
-			}
-			valueStr = fmt.Sprintf("[%s]", strings.Join(escaped, ", "))
-		default:
-			// For complex types, marshal to JSON string
-			jsonBytes, _ := json.Marshal(v)
-			valueStr = fmt.Sprintf("'%s'", escapeCypherString(string(jsonBytes)))
-		}
-		parts = append(parts, fmt.Sprintf("%s: %s", key, valueStr))
-	}
-
-	return fmt.Sprintf("{%s}", strings.Join(parts, ", "))
-}
-
-// escapeCypherString escapes single quotes in Cypher strings
-func escapeCypherString(s string) string {
-	return strings.ReplaceAll(s, "'", "\\'")
-}
-
-// replaceCypherParameters replaces $param placeholders with actual values
-// It sorts parameters by length (longest first) to avoid partial replacements
-// where one parameter name is a prefix of another (e.g., $deleted and $deletedAt)
-func replaceCypherParameters(query string, params map[string]interface{}) string {
-	result := query
-
-	// Sort parameter keys by length (longest first) to avoid prefix collision
-	// This ensures $deletedAt is processed before $deleted
-	keys := make([]string, 0, len(params))
-	for key := range params {
-		keys = append(keys, key)
-	}
-
-	// Sort by length descending, then alphabetically for stability
-	for i := 0; i < len(keys); i++ {
-		for j := i + 1; j < len(keys); j++ {
-			if len(keys[j]) > len(keys[i]) || (len(keys[j]) == len(keys[i]) && keys[j] < keys[i]) {
-				keys[i], keys[j] = keys[j], keys[i]
-			}
-		}
-	}
-
-	for _, key := range keys {
-		value := params[key]
-		placeholder := "$" + key
-		var replacement string
-
-		switch v := value.(type) {
-		case string:
-			replacement = fmt.Sprintf("'%s'", escapeCypherString(v))
-		case bool:
-			replacement = fmt.Sprintf("%t", v)
-		case int:
-			replacement = fmt.Sprintf("%d", v)
-		case int64:
-			replacement = fmt.Sprintf("%d", v)
-		case float64:
-			replacement = fmt.Sprintf("%f", v)
-		case []string:
-			escaped := make([]string, len(v))
-			for i, s := range v {
-				escaped[i] = fmt.Sprintf("'%s'", escapeCypherString(s))
-			}
-			replacement = fmt.Sprintf("[%s]", strings.Join(escaped, ", "))
-		default:
-			// For complex types, marshal to JSON string
-			jsonBytes, _ := json.Marshal(v)
-			replacement = fmt.Sprintf("'%s'", escapeCypherString(string(jsonBytes)))
-		}
-
-		result = strings.ReplaceAll(result, placeholder, replacement)
-	}
-
-	return result
-}
-
 // parseGraphQueryResult parses the result from GRAPH.QUERY command
 // FalkorDB returns results in a specific format:
 // [
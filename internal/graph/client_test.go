@@ -20,174 +20,6 @@ func TestDefaultClientConfig(t *testing.T) {
 	assert.NotZero(t, config.WriteTimeout)
 }
 
-func TestEscapeCypherString(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "no special characters",
-			input:    "hello world",
-			expected: "hello world",
-		},
-		{
-			name:     "single quote",
-			input:    "it's working",
-			expected: "it\\'s working",
-		},
-		{
-			name:     "multiple quotes",
-			input:    "it's a 'test'",
-			expected: "it\\'s a \\'test\\'",
-		},
-		{
-			name:     "empty string",
-			input:    "",
-			expected: "",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := escapeCypherString(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
-func TestBuildPropertiesString(t *testing.T) {
-	tests := []struct {
-		name     string
-		props    map[string]interface{}
-		contains []string
-	}{
-		{
-			name:     "empty properties",
-			props:    map[string]interface{}{},
-			contains: []string{},
-		},
-		{
-			name: "string property",
-			props: map[string]interface{}{
-				"name": "test",
-			},
-			contains: []string{"name:", "'test'"},
-		},
-		{
-			name: "boolean property",
-			props: map[string]interface{}{
-				"active": true,
-			},
-			contains: []string{"active:", "true"},
-		},
-		{
-			name: "numeric property",
-			props: map[string]interface{}{
-				"count": 42,
-			},
-			contains: []string{"count:", "42"},
-		},
-		{
-			name: "array property",
-			props: map[string]interface{}{
-				"tags": []string{"foo", "bar"},
-			},
-			contains: []string{"tags:", "'foo'", "'bar'"},
-		},
-		{
-			name: "mixed properties",
-			props: map[string]interface{}{
-				"name":   "test",
-				"count":  10,
-				"active": true,
-			},
-			contains: []string{"name:", "count:", "active:"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := buildPropertiesString(tt.props)
-			for _, expected := range tt.contains {
-				assert.Contains(t, result, expected)
-			}
-		})
-	}
-}
-
-func TestReplaceCypherParameters(t *testing.T) {
-	tests := []struct {
-		name     string
-		query    string
-		params   map[string]interface{}
-		expected string
-	}{
-		{
-			name:     "string parameter",
-			query:    "MATCH (n {uid: $uid}) RETURN n",
-			params:   map[string]interface{}{"uid": "pod-123"},
-			expected: "MATCH (n {uid: 'pod-123'}) RETURN n",
-		},
-		{
-			name:     "numeric parameter",
-			query:    "WHERE n.count > $minCount",
-			params:   map[string]interface{}{"minCount": 10},
-			expected: "WHERE n.count > 10",
-		},
-		{
-			name:     "boolean parameter",
-			query:    "WHERE n.active = $active",
-			params:   map[string]interface{}{"active": true},
-			expected: "WHERE n.active = true",
-		},
-		{
-			name:     "multiple parameters",
-			query:    "MATCH (n {uid: $uid}) WHERE n.count > $count RETURN n",
-			params:   map[string]interface{}{"uid": "pod-123", "count": 5},
-			expected: "MATCH (n {uid: 'pod-123'}) WHERE n.count > 5 RETURN n",
-		},
-		{
-			name:     "string array parameter",
-			query:    "WHERE n.tags IN $tags",
-			params:   map[string]interface{}{"tags": []string{"foo", "bar"}},
-			expected: "WHERE n.tags IN ['foo', 'bar']",
-		},
-		{
-			name:     "parameter with quote in value",
-			query:    "MATCH (n {name: $name}) RETURN n",
-			params:   map[string]interface{}{"name": "it's working"},
-			expected: "MATCH (n {name: 'it\\'s working'}) RETURN n",
-		},
-		{
-			name:  "parameters with prefix collision",
-			query: "SET r.deleted = $deleted, r.deletedAt = $deletedAt",
-			params: map[string]interface{}{
-				"deleted":   false,
-				"deletedAt": 0,
-			},
-			expected: "SET r.deleted = false, r.deletedAt = 0",
-		},
-		{
-			name:  "parameters with multiple prefix collisions",
-			query: "SET r.name = $name, r.namespace = $namespace, r.namespacedName = $namespacedName",
-			params: map[string]interface{}{
-				"name":           "test",
-				"namespace":      "default",
-				"namespacedName": "default/test",
-			},
-			expected: "SET r.name = 'test', r.namespace = 'default', r.namespacedName = 'default/test'",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := replaceCypherParameters(tt.query, tt.params)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestParseQueryStats(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,23 @@
+package graph_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/graphtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryClientConformance(t *testing.T) {
+	graphtest.RunConformance(t, func(t *testing.T) graph.Client {
+		config := graph.DefaultClientConfig()
+		config.Backend = graph.BackendMemory
+		config.GraphName = "conformance"
+
+		client := graph.NewClient(config)
+		require.NoError(t, client.Connect(context.Background()))
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	})
+}
@@ -0,0 +1,403 @@
+package cypher
+
+import "strings"
+
+// Query is a parsed Cypher query: a sequence of clauses executed in order
+type Query struct {
+	Clauses []Clause
+}
+
+// IsReadOnly reports whether the query never modifies the graph
+func (q *Query) IsReadOnly() bool {
+	for _, clause := range q.Clauses {
+		switch c := clause.(type) {
+		case *Create, *Merge, *Set, *Remove, *Delete, *CreateIndex:
+			return false
+		case *Call:
+			if !readOnlyProcedures[strings.ToLower(c.Procedure)] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Clause is one clause of a query
+type Clause interface {
+	clause()
+}
+
+// Match is a MATCH or OPTIONAL MATCH clause
+type Match struct {
+	Optional bool
+	Pattern  []*PatternPart
+	Where    Expr
+}
+
+// Unwind expands a list into one row per element
+type Unwind struct {
+	Expr  Expr
+	Alias string
+}
+
+// With projects rows into a new scope, optionally aggregating and filtering them
+type With struct {
+	Projection
+	Where Expr
+}
+
+// Return projects the final result rows
+type Return struct {
+	Projection
+}
+
+// Create creates the nodes and relationships of a pattern
+type Create struct {
+	Pattern []*PatternPart
+}
+
+// Merge matches a pattern or creates it when it does not exist
+type Merge struct {
+	Part     *PatternPart
+	OnCreate []SetItem
+	OnMatch  []SetItem
+}
+
+// Set updates properties
+type Set struct {
+	Items []SetItem
+}
+
+// Remove removes properties
+type Remove struct {
+	Items []*Property
+}
+
+// Delete deletes nodes and relationships. DETACH also deletes the
+// relationships of deleted nodes.
+type Delete struct {
+	Detach bool
+	Exprs  []Expr
+}
+
+// CreateIndex creates an index on node properties. Full-text indexes are
+// queried with db.idx.fulltext.queryNodes, the others serve exact matches.
+type CreateIndex struct {
+	Label      string
+	Properties []string
+	Fulltext   bool
+}
+
+// Call invokes a procedure and binds the columns it yields. Without YIELD
+// all of the procedure's columns are bound.
+type Call struct {
+	Procedure string
+	Args      []Expr
+	Yield     []string
+}
+
+// readOnlyProcedures are the procedures that never modify the graph, by
+// lower-cased name
+var readOnlyProcedures = map[string]bool{
+	"db.idx.fulltext.querynodes": true,
+}
+
+func (*Match) clause()       {}
+func (*Unwind) clause()      {}
+func (*With) clause()        {}
+func (*Return) clause()      {}
+func (*Create) clause()      {}
+func (*Merge) clause()       {}
+func (*Set) clause()         {}
+func (*Remove) clause()      {}
+func (*Delete) clause()      {}
+func (*CreateIndex) clause() {}
+func (*Call) clause()        {}
+
+// Projection is the shared body of WITH and RETURN
+type Projection struct {
+	Distinct bool
+	Star     bool // WITH * / RETURN *
+	Items    []ProjectionItem
+	OrderBy  []SortItem
+	Skip     Expr
+	Limit    Expr
+}
+
+// ProjectionItem is a projected expression and its column name
+type ProjectionItem struct {
+	Expr Expr
+	// Alias is the AS name, or the expression text when no alias is given
+	Alias string
+}
+
+// SortItem is an ORDER BY key
+type SortItem struct {
+	Expr       Expr
+	Descending bool
+}
+
+// SetItem is a single SET assignment
+type SetItem struct {
+	// Property is set for "n.key = value"
+	Property *Property
+	// Variable is set for "n = map" and "n += map"
+	Variable string
+	Merge    bool // +=
+	Value    Expr
+}
+
+// PatternPart is a chain of nodes connected by relationships, optionally
+// bound to a path variable
+type PatternPart struct {
+	PathVariable  string
+	Nodes         []*NodePattern
+	Relationships []*RelationshipPattern // len(Nodes)-1 entries
+}
+
+// NodePattern matches a node
+type NodePattern struct {
+	Variable   string
+	Labels     []string
+	Properties Expr // map literal or parameter, nil if absent
+}
+
+// Direction of a relationship pattern relative to the order of the pattern
+type Direction int
+
+const (
+	DirectionBoth  Direction = iota // (a)-[]-(b)
+	DirectionRight                  // (a)-[]->(b)
+	DirectionLeft                   // (a)<-[]-(b)
+)
+
+// RelationshipPattern matches one relationship, or a chain of them when
+// VarLength is set
+type RelationshipPattern struct {
+	Variable   string
+	Types      []string
+	Direction  Direction
+	Properties Expr
+	VarLength  bool
+	MinHops    int
+	MaxHops    int // -1 for unbounded
+}
+
+// Expr is an expression
+type Expr interface {
+	expr()
+}
+
+// Literal is a constant: nil, bool, int64, float64 or string
+type Literal struct {
+	Value interface{}
+}
+
+// Parameter is a $name reference to a query parameter
+type Parameter struct {
+	Name string
+}
+
+// Variable references a bound variable
+type Variable struct {
+	Name string
+}
+
+// Property is a property lookup, e.g. n.name
+type Property struct {
+	Expr Expr
+	Key  string
+}
+
+// Index is a list or map subscript, e.g. list[0]
+type Index struct {
+	Expr  Expr
+	Index Expr
+}
+
+// Slice is a list range, e.g. list[1..3]. Missing bounds are nil.
+type Slice struct {
+	Expr Expr
+	From Expr
+	To   Expr
+}
+
+// ListLiteral is a list, e.g. [1, 2]
+type ListLiteral struct {
+	Items []Expr
+}
+
+// MapLiteral is a map, e.g. {key: value}
+type MapLiteral struct {
+	Keys   []string
+	Values []Expr
+}
+
+// FunctionCall calls a scalar or aggregating function
+type FunctionCall struct {
+	Name     string // lower case
+	Distinct bool
+	Star     bool // count(*)
+	Args     []Expr
+}
+
+// BinaryOp applies an infix operator. Op is the upper-cased operator:
+// OR, XOR, AND, =, <>, <, >, <=, >=, =~, IN, STARTS WITH, ENDS WITH,
+// CONTAINS, +, -, *, /, %, ^
+type BinaryOp struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+// UnaryOp applies NOT or unary minus
+type UnaryOp struct {
+	Op   string
+	Expr Expr
+}
+
+// IsNull tests for null, or for non-null when Not is set
+type IsNull struct {
+	Expr Expr
+	Not  bool
+}
+
+// HasLabels tests whether a node has all the given labels, e.g. n:Pod
+type HasLabels struct {
+	Expr   Expr
+	Labels []string
+}
+
+// Case is a simple (Test set) or generic CASE expression
+type Case struct {
+	Test  Expr
+	Whens []CaseWhen
+	Else  Expr
+}
+
+// CaseWhen is one WHEN ... THEN ... branch
+type CaseWhen struct {
+	When Expr
+	Then Expr
+}
+
+// ListComprehension is [x IN list WHERE predicate | projection]
+type ListComprehension struct {
+	Variable   string
+	List       Expr
+	Where      Expr
+	Projection Expr
+}
+
+// Quantifier is ALL, ANY, NONE or SINGLE(x IN list WHERE predicate)
+type Quantifier struct {
+	Kind     string // upper case
+	Variable string
+	List     Expr
+	Where    Expr
+}
+
+// PatternPredicate is true when the pattern has at least one match, e.g.
+// WHERE NOT (r)-[:CHANGED]->()
+type PatternPredicate struct {
+	Part *PatternPart
+}
+
+func (*Literal) expr()           {}
+func (*Parameter) expr()         {}
+func (*Variable) expr()          {}
+func (*Property) expr()          {}
+func (*Index) expr()             {}
+func (*Slice) expr()             {}
+func (*ListLiteral) expr()       {}
+func (*MapLiteral) expr()        {}
+func (*FunctionCall) expr()      {}
+func (*BinaryOp) expr()          {}
+func (*UnaryOp) expr()           {}
+func (*IsNull) expr()            {}
+func (*HasLabels) expr()         {}
+func (*Case) expr()              {}
+func (*ListComprehension) expr() {}
+func (*Quantifier) expr()        {}
+func (*PatternPredicate) expr()  {}
+
+// aggregateFunctions are the functions computed over a group of rows
+var aggregateFunctions = map[string]bool{
+	"count":   true,
+	"collect": true,
+	"sum":     true,
+	"avg":     true,
+	"min":     true,
+	"max":     true,
+}
+
+// IsAggregate reports whether name is an aggregating function
+func IsAggregate(name string) bool {
+	return aggregateFunctions[name]
+}
+
+// ContainsAggregate reports whether an expression calls an aggregating function
+func ContainsAggregate(e Expr) bool {
+	found := false
+	Walk(e, func(e Expr) bool {
+		if call, ok := e.(*FunctionCall); ok && IsAggregate(call.Name) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Walk calls fn for e and its sub-expressions in depth-first order until fn
+// returns false
+func Walk(e Expr, fn func(Expr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+	switch e := e.(type) {
+	case *Property:
+		Walk(e.Expr, fn)
+	case *Index:
+		Walk(e.Expr, fn)
+		Walk(e.Index, fn)
+	case *Slice:
+		Walk(e.Expr, fn)
+		Walk(e.From, fn)
+		Walk(e.To, fn)
+	case *ListLiteral:
+		for _, item := range e.Items {
+			Walk(item, fn)
+		}
+	case *MapLiteral:
+		for _, value := range e.Values {
+			Walk(value, fn)
+		}
+	case *FunctionCall:
+		for _, arg := range e.Args {
+			Walk(arg, fn)
+		}
+	case *BinaryOp:
+		Walk(e.Left, fn)
+		Walk(e.Right, fn)
+	case *UnaryOp:
+		Walk(e.Expr, fn)
+	case *IsNull:
+		Walk(e.Expr, fn)
+	case *HasLabels:
+		Walk(e.Expr, fn)
+	case *Case:
+		Walk(e.Test, fn)
+		for _, when := range e.Whens {
+			Walk(when.When, fn)
+			Walk(when.Then, fn)
+		}
+		Walk(e.Else, fn)
+	case *ListComprehension:
+		Walk(e.List, fn)
+		Walk(e.Where, fn)
+		Walk(e.Projection, fn)
+	case *Quantifier:
+		Walk(e.List, fn)
+		Walk(e.Where, fn)
+	}
+}
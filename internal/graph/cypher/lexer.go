@@ -0,0 +1,242 @@
+package cypher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind classifies lexer tokens
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokQuotedIdent
+	tokString
+	tokInteger
+	tokFloat
+	tokParameter
+	tokSymbol
+)
+
+// token is a lexical token with its byte span in the query text
+type token struct {
+	kind  tokenKind
+	text  string // identifier, symbol, parameter name or decoded string literal
+	start int
+	end   int
+}
+
+// is reports whether the token is the given symbol
+func (t token) is(symbol string) bool {
+	return t.kind == tokSymbol && t.text == symbol
+}
+
+// isKeyword reports whether the token is the given keyword. Keywords are
+// case-insensitive and never backtick-quoted.
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, keyword)
+}
+
+// multiCharSymbols are matched before single-character symbols
+var multiCharSymbols = []string{"..", "<=", ">=", "<>", "!=", "=~", "+="}
+
+// lex splits a query into tokens, dropping whitespace and comments
+func lex(input string) ([]token, error) {
+	var tokens []token
+	pos := 0
+
+	for pos < len(input) {
+		r, size := utf8.DecodeRuneInString(input[pos:])
+
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+
+		case strings.HasPrefix(input[pos:], "//"):
+			end := strings.IndexByte(input[pos:], '\n')
+			if end < 0 {
+				pos = len(input)
+			} else {
+				pos += end + 1
+			}
+
+		case strings.HasPrefix(input[pos:], "/*"):
+			end := strings.Index(input[pos+2:], "*/")
+			if end < 0 {
+				return nil, &SyntaxError{Pos: pos, Msg: "unterminated comment"}
+			}
+			pos += end + 4
+
+		case r == '_' || unicode.IsLetter(r):
+			start := pos
+			for pos < len(input) {
+				r, size := utf8.DecodeRuneInString(input[pos:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				pos += size
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: input[start:pos], start: start, end: pos})
+
+		case r == '`':
+			start := pos
+			var b strings.Builder
+			pos++
+			for {
+				end := strings.IndexByte(input[pos:], '`')
+				if end < 0 {
+					return nil, &SyntaxError{Pos: start, Msg: "unterminated quoted identifier"}
+				}
+				b.WriteString(input[pos : pos+end])
+				pos += end + 1
+				// A doubled backtick is an escaped backtick
+				if pos < len(input) && input[pos] == '`' {
+					b.WriteByte('`')
+					pos++
+					continue
+				}
+				break
+			}
+			tokens = append(tokens, token{kind: tokQuotedIdent, text: b.String(), start: start, end: pos})
+
+		case r == '$':
+			start := pos
+			pos++
+			for pos < len(input) {
+				r, size := utf8.DecodeRuneInString(input[pos:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				pos += size
+			}
+			if pos == start+1 {
+				return nil, &SyntaxError{Pos: start, Msg: "missing parameter name after '$'"}
+			}
+			tokens = append(tokens, token{kind: tokParameter, text: input[start+1 : pos], start: start, end: pos})
+
+		case r == '\'' || r == '"':
+			tok, err := lexString(input, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			pos = tok.end
+
+		case r >= '0' && r <= '9':
+			tok := lexNumber(input, pos)
+			tokens = append(tokens, tok)
+			pos = tok.end
+
+		default:
+			matched := false
+			for _, symbol := range multiCharSymbols {
+				if strings.HasPrefix(input[pos:], symbol) {
+					tokens = append(tokens, token{kind: tokSymbol, text: symbol, start: pos, end: pos + len(symbol)})
+					pos += len(symbol)
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			if !strings.ContainsRune("()[]{},.:;|*=<>+-/%^", r) {
+				return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, token{kind: tokSymbol, text: string(r), start: pos, end: pos + size})
+			pos += size
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, start: len(input), end: len(input)})
+	return tokens, nil
+}
+
+// lexString decodes a single- or double-quoted string literal starting at pos
+func lexString(input string, pos int) (token, error) {
+	quote := input[pos]
+	start := pos
+	var b strings.Builder
+	pos++
+
+	for {
+		if pos >= len(input) {
+			return token{}, &SyntaxError{Pos: start, Msg: "unterminated string literal"}
+		}
+		c := input[pos]
+		switch {
+		case c == quote:
+			return token{kind: tokString, text: b.String(), start: start, end: pos + 1}, nil
+		case c == '\\':
+			if pos+1 >= len(input) {
+				return token{}, &SyntaxError{Pos: pos, Msg: "unterminated escape sequence"}
+			}
+			pos++
+			switch input[pos] {
+			case '\\', '\'', '"':
+				b.WriteByte(input[pos])
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if pos+4 >= len(input) {
+					return token{}, &SyntaxError{Pos: pos, Msg: "invalid unicode escape"}
+				}
+				var code rune
+				if _, err := fmt.Sscanf(input[pos+1:pos+5], "%04x", &code); err != nil {
+					return token{}, &SyntaxError{Pos: pos, Msg: "invalid unicode escape"}
+				}
+				b.WriteRune(code)
+				pos += 4
+			default:
+				return token{}, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("invalid escape sequence \\%c", input[pos])}
+			}
+			pos++
+		default:
+			b.WriteByte(c)
+			pos++
+		}
+	}
+}
+
+// lexNumber scans an integer or float literal starting at pos. A ".." after
+// the digits belongs to a range and ends the number.
+func lexNumber(input string, pos int) token {
+	start := pos
+	kind := tokInteger
+	digits := func() {
+		for pos < len(input) && input[pos] >= '0' && input[pos] <= '9' {
+			pos++
+		}
+	}
+
+	digits()
+	if pos+1 < len(input) && input[pos] == '.' && input[pos+1] >= '0' && input[pos+1] <= '9' {
+		kind = tokFloat
+		pos++
+		digits()
+	}
+	if pos < len(input) && (input[pos] == 'e' || input[pos] == 'E') {
+		exp := pos + 1
+		if exp < len(input) && (input[exp] == '+' || input[exp] == '-') {
+			exp++
+		}
+		if exp < len(input) && input[exp] >= '0' && input[exp] <= '9' {
+			kind = tokFloat
+			pos = exp
+			digits()
+		}
+	}
+
+	return token{kind: kind, text: input[start:pos], start: start, end: pos}
+}
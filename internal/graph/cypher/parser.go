@@ -0,0 +1,1164 @@
+// Package cypher parses the subset of the Cypher query language used by
+// Spectre's graph queries.
+package cypher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError reports an invalid or unsupported query
+type SyntaxError struct {
+	Pos int // byte offset in the query
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at offset %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses a Cypher query
+func Parse(query string) (*Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{input: query, tokens: tokens}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// parser is a recursive-descent parser over the token stream
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.peek().start, Msg: fmt.Sprintf(format, args...)}
+}
+
+// describe renders the current token for error messages
+func (p *parser) describe() string {
+	tok := p.peek()
+	if tok.kind == tokEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", p.input[tok.start:tok.end])
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if p.peek().is(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.errorf("expected %q, found %s", symbol, p.describe())
+	}
+	return nil
+}
+
+func (p *parser) acceptKeyword(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if !p.peekAt(i).isKeyword(keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *parser) expectKeyword(keywords ...string) error {
+	if !p.acceptKeyword(keywords...) {
+		return p.errorf("expected %s, found %s", strings.Join(keywords, " "), p.describe())
+	}
+	return nil
+}
+
+// parseName parses an identifier, which may be a backtick-quoted name
+func (p *parser) parseName() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokIdent && tok.kind != tokQuotedIdent {
+		return "", p.errorf("expected a name, found %s", p.describe())
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	q := &Query{}
+	for {
+		p.acceptSymbol(";")
+		if p.peek().kind == tokEOF {
+			break
+		}
+		clause, err := p.parseClause()
+		if err != nil {
+			return nil, err
+		}
+		q.Clauses = append(q.Clauses, clause)
+	}
+
+	if len(q.Clauses) == 0 {
+		return nil, &SyntaxError{Msg: "empty query"}
+	}
+	return q, nil
+}
+
+func (p *parser) parseClause() (Clause, error) {
+	switch {
+	case p.acceptKeyword("OPTIONAL", "MATCH"):
+		return p.parseMatch(true)
+	case p.acceptKeyword("MATCH"):
+		return p.parseMatch(false)
+	case p.acceptKeyword("UNWIND"):
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AS"); err != nil {
+			return nil, err
+		}
+		alias, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		return &Unwind{Expr: expr, Alias: alias}, nil
+	case p.acceptKeyword("WITH"):
+		projection, err := p.parseProjection()
+		if err != nil {
+			return nil, err
+		}
+		with := &With{Projection: *projection}
+		if p.acceptKeyword("WHERE") {
+			if with.Where, err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+		}
+		return with, nil
+	case p.acceptKeyword("RETURN"):
+		projection, err := p.parseProjection()
+		if err != nil {
+			return nil, err
+		}
+		return &Return{Projection: *projection}, nil
+	case p.acceptKeyword("CREATE", "INDEX"):
+		return p.parseCreateIndex(false)
+	case p.acceptKeyword("CREATE", "FULLTEXT", "INDEX"):
+		return p.parseCreateIndex(true)
+	case p.acceptKeyword("CREATE"):
+		pattern, err := p.parsePattern()
+		if err != nil {
+			return nil, err
+		}
+		return &Create{Pattern: pattern}, nil
+	case p.acceptKeyword("MERGE"):
+		return p.parseMerge()
+	case p.acceptKeyword("SET"):
+		items, err := p.parseSetItems()
+		if err != nil {
+			return nil, err
+		}
+		return &Set{Items: items}, nil
+	case p.acceptKeyword("REMOVE"):
+		return p.parseRemove()
+	case p.acceptKeyword("DETACH", "DELETE"):
+		return p.parseDelete(true)
+	case p.acceptKeyword("DELETE"):
+		return p.parseDelete(false)
+	case p.acceptKeyword("CALL"):
+		return p.parseCall()
+	}
+	return nil, p.errorf("unsupported clause %s", p.describe())
+}
+
+func (p *parser) parseMatch(optional bool) (Clause, error) {
+	pattern, err := p.parsePattern()
+	if err != nil {
+		return nil, err
+	}
+	match := &Match{Optional: optional, Pattern: pattern}
+	if p.acceptKeyword("WHERE") {
+		if match.Where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return match, nil
+}
+
+func (p *parser) parseMerge() (Clause, error) {
+	part, err := p.parsePatternPart()
+	if err != nil {
+		return nil, err
+	}
+	merge := &Merge{Part: part}
+	for {
+		switch {
+		case p.acceptKeyword("ON", "CREATE", "SET"):
+			items, err := p.parseSetItems()
+			if err != nil {
+				return nil, err
+			}
+			merge.OnCreate = append(merge.OnCreate, items...)
+		case p.acceptKeyword("ON", "MATCH", "SET"):
+			items, err := p.parseSetItems()
+			if err != nil {
+				return nil, err
+			}
+			merge.OnMatch = append(merge.OnMatch, items...)
+		default:
+			return merge, nil
+		}
+	}
+}
+
+func (p *parser) parseSetItems() ([]SetItem, error) {
+	var items []SetItem
+	for {
+		start := p.pos
+		variable, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+
+		var item SetItem
+		switch {
+		case p.acceptSymbol("."):
+			key, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			item.Property = &Property{Expr: &Variable{Name: variable}, Key: key}
+			if err := p.expectSymbol("="); err != nil {
+				return nil, err
+			}
+		case p.acceptSymbol("+="):
+			item.Variable = variable
+			item.Merge = true
+		case p.acceptSymbol("="):
+			item.Variable = variable
+		default:
+			p.pos = start
+			return nil, p.errorf("unsupported SET item")
+		}
+
+		if item.Value, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+func (p *parser) parseRemove() (Clause, error) {
+	remove := &Remove{}
+	for {
+		variable, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol("."); err != nil {
+			return nil, err
+		}
+		key, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		remove.Items = append(remove.Items, &Property{Expr: &Variable{Name: variable}, Key: key})
+		if !p.acceptSymbol(",") {
+			return remove, nil
+		}
+	}
+}
+
+func (p *parser) parseDelete(detach bool) (Clause, error) {
+	del := &Delete{Detach: detach}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		del.Exprs = append(del.Exprs, expr)
+		if !p.acceptSymbol(",") {
+			return del, nil
+		}
+	}
+}
+
+// parseCreateIndex parses both "CREATE INDEX FOR (n:Label) ON (n.a, n.b)"
+// and the older "CREATE INDEX ON :Label(a, b)", and the same forms of
+// CREATE FULLTEXT INDEX
+func (p *parser) parseCreateIndex(fulltext bool) (Clause, error) {
+	index := &CreateIndex{Fulltext: fulltext}
+
+	if p.acceptKeyword("ON") {
+		if err := p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		label, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		index.Label = label
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		for {
+			property, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			index.Properties = append(index.Properties, property)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		return index, p.expectSymbol(")")
+	}
+
+	if err := p.expectKeyword("FOR"); err != nil {
+		return nil, err
+	}
+	node, err := p.parseNodePattern()
+	if err != nil {
+		return nil, err
+	}
+	if len(node.Labels) != 1 || node.Variable == "" {
+		return nil, p.errorf("index must be defined on a single label")
+	}
+	index.Label = node.Labels[0]
+
+	if err := p.expectKeyword("ON"); err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	for {
+		variable, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if variable != node.Variable {
+			return nil, p.errorf("unknown variable %q in index definition", variable)
+		}
+		if err := p.expectSymbol("."); err != nil {
+			return nil, err
+		}
+		property, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		index.Properties = append(index.Properties, property)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return index, p.expectSymbol(")")
+}
+
+// parseCall parses "CALL name.space.proc(args) YIELD a, b"
+func (p *parser) parseCall() (Clause, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	call := &Call{Procedure: name}
+	for p.acceptSymbol(".") {
+		part, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		call.Procedure += "." + part
+	}
+
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	if !p.acceptSymbol(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("YIELD") {
+		for {
+			column, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			call.Yield = append(call.Yield, column)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	return call, nil
+}
+
+func (p *parser) parseProjection() (*Projection, error) {
+	projection := &Projection{}
+	projection.Distinct = p.acceptKeyword("DISTINCT")
+
+	if p.acceptSymbol("*") {
+		projection.Star = true
+		if !p.acceptSymbol(",") {
+			return p.parseProjectionModifiers(projection)
+		}
+	}
+
+	for {
+		startTok := p.peek()
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		endTok := p.tokens[p.pos-1]
+
+		item := ProjectionItem{Expr: expr}
+		if p.acceptKeyword("AS") {
+			if item.Alias, err = p.parseName(); err != nil {
+				return nil, err
+			}
+		} else if v, ok := expr.(*Variable); ok {
+			item.Alias = v.Name
+		} else {
+			item.Alias = p.input[startTok.start:endTok.end]
+		}
+		projection.Items = append(projection.Items, item)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	return p.parseProjectionModifiers(projection)
+}
+
+func (p *parser) parseProjectionModifiers(projection *Projection) (*Projection, error) {
+	var err error
+	if p.acceptKeyword("ORDER", "BY") {
+		for {
+			item := SortItem{}
+			if item.Expr, err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+			switch {
+			case p.acceptKeyword("DESC"), p.acceptKeyword("DESCENDING"):
+				item.Descending = true
+			case p.acceptKeyword("ASC"), p.acceptKeyword("ASCENDING"):
+			}
+			projection.OrderBy = append(projection.OrderBy, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("SKIP") {
+		if projection.Skip, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		if projection.Limit, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return projection, nil
+}
+
+// Patterns
+
+func (p *parser) parsePattern() ([]*PatternPart, error) {
+	var parts []*PatternPart
+	for {
+		part, err := p.parsePatternPart()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+		if !p.acceptSymbol(",") {
+			return parts, nil
+		}
+	}
+}
+
+func (p *parser) parsePatternPart() (*PatternPart, error) {
+	part := &PatternPart{}
+	if (p.peek().kind == tokIdent || p.peek().kind == tokQuotedIdent) && p.peekAt(1).is("=") {
+		part.PathVariable = p.next().text
+		p.next()
+	}
+
+	node, err := p.parseNodePattern()
+	if err != nil {
+		return nil, err
+	}
+	part.Nodes = append(part.Nodes, node)
+
+	for p.peek().is("-") || (p.peek().is("<") && p.peekAt(1).is("-")) {
+		rel, err := p.parseRelationshipPattern()
+		if err != nil {
+			return nil, err
+		}
+		node, err := p.parseNodePattern()
+		if err != nil {
+			return nil, err
+		}
+		part.Relationships = append(part.Relationships, rel)
+		part.Nodes = append(part.Nodes, node)
+	}
+	return part, nil
+}
+
+func (p *parser) parseNodePattern() (*NodePattern, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	node := &NodePattern{}
+	if tok := p.peek(); tok.kind == tokIdent || tok.kind == tokQuotedIdent {
+		node.Variable = p.next().text
+	}
+	for p.acceptSymbol(":") {
+		label, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		node.Labels = append(node.Labels, label)
+	}
+	if p.peek().is("{") || p.peek().kind == tokParameter {
+		props, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		node.Properties = props
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (p *parser) parseRelationshipPattern() (*RelationshipPattern, error) {
+	rel := &RelationshipPattern{MinHops: 1, MaxHops: 1}
+	left := p.acceptSymbol("<")
+	if err := p.expectSymbol("-"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptSymbol("[") {
+		if tok := p.peek(); tok.kind == tokIdent || tok.kind == tokQuotedIdent {
+			rel.Variable = p.next().text
+		}
+		if p.acceptSymbol(":") {
+			for {
+				relType, err := p.parseName()
+				if err != nil {
+					return nil, err
+				}
+				rel.Types = append(rel.Types, relType)
+				if !p.acceptSymbol("|") {
+					break
+				}
+				p.acceptSymbol(":")
+			}
+		}
+		if p.acceptSymbol("*") {
+			if err := p.parseHops(rel); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek().is("{") || p.peek().kind == tokParameter {
+			props, err := p.parseAtom()
+			if err != nil {
+				return nil, err
+			}
+			rel.Properties = props
+		}
+		if err := p.expectSymbol("]"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectSymbol("-"); err != nil {
+		return nil, err
+	}
+	right := p.acceptSymbol(">")
+
+	switch {
+	case left && right:
+		return nil, p.errorf("relationship cannot point in both directions")
+	case left:
+		rel.Direction = DirectionLeft
+	case right:
+		rel.Direction = DirectionRight
+	default:
+		rel.Direction = DirectionBoth
+	}
+	return rel, nil
+}
+
+// parseHops parses the range after '*' in a variable-length relationship
+func (p *parser) parseHops(rel *RelationshipPattern) error {
+	rel.VarLength = true
+	rel.MinHops, rel.MaxHops = 1, -1
+
+	parseInt := func() (int, bool, error) {
+		if p.peek().kind != tokInteger {
+			return 0, false, nil
+		}
+		n, err := strconv.Atoi(p.next().text)
+		if err != nil {
+			return 0, false, p.errorf("invalid hop count")
+		}
+		return n, true, nil
+	}
+
+	minHops, hasMin, err := parseInt()
+	if err != nil {
+		return err
+	}
+	if hasMin {
+		rel.MinHops = minHops
+	}
+	if p.acceptSymbol("..") {
+		maxHops, hasMax, err := parseInt()
+		if err != nil {
+			return err
+		}
+		if hasMax {
+			rel.MaxHops = maxHops
+		}
+	} else if hasMin {
+		rel.MaxHops = minHops
+	}
+
+	if rel.MaxHops >= 0 && rel.MaxHops < rel.MinHops {
+		return p.errorf("invalid hop range %d..%d", rel.MinHops, rel.MaxHops)
+	}
+	return nil
+}
+
+// Expressions, from lowest to highest precedence
+
+func (p *parser) parseExpr() (Expr, error) {
+	return p.parseBinary(0)
+}
+
+// binaryLevels lists the left-associative operators of each precedence level
+var binaryLevels = [][]string{
+	{"OR"},
+	{"XOR"},
+	{"AND"},
+}
+
+func (p *parser) parseBinary(level int) (Expr, error) {
+	if level == len(binaryLevels) {
+		return p.parseNot()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		matched := ""
+		for _, op := range binaryLevels[level] {
+			if p.peek().isKeyword(op) {
+				matched = op
+				break
+			}
+		}
+		if matched == "" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryOp{Op: matched, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.acceptKeyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryOp{Op: "NOT", Expr: expr}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op string
+		switch tok := p.peek(); {
+		case tok.is("="), tok.is("<>"), tok.is("<"), tok.is(">"), tok.is("<="), tok.is(">="), tok.is("=~"):
+			op = tok.text
+			p.pos++
+		case tok.is("!="):
+			op = "<>"
+			p.pos++
+		case p.acceptKeyword("IN"):
+			op = "IN"
+		case p.acceptKeyword("CONTAINS"):
+			op = "CONTAINS"
+		case p.acceptKeyword("STARTS", "WITH"):
+			op = "STARTS WITH"
+		case p.acceptKeyword("ENDS", "WITH"):
+			op = "ENDS WITH"
+		case p.acceptKeyword("IS", "NOT", "NULL"):
+			left = &IsNull{Expr: left, Not: true}
+			continue
+		case p.acceptKeyword("IS", "NULL"):
+			left = &IsNull{Expr: left}
+			continue
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryOp{Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("+") || p.peek().is("-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryOp{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("*") || p.peek().is("/") || p.peek().is("%") {
+		op := p.next().text
+		right, err := p.parsePower()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryOp{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePower() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptSymbol("^") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryOp{Op: "^", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.acceptSymbol("-") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		// Fold negative number literals so that e.g. -1 stays an integer
+		if lit, ok := expr.(*Literal); ok {
+			switch v := lit.Value.(type) {
+			case int64:
+				return &Literal{Value: -v}, nil
+			case float64:
+				return &Literal{Value: -v}, nil
+			}
+		}
+		return &UnaryOp{Op: "-", Expr: expr}, nil
+	}
+	if p.acceptSymbol("+") {
+		return p.parseUnary()
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (Expr, error) {
+	expr, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.acceptSymbol("."):
+			key, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			expr = &Property{Expr: expr, Key: key}
+
+		case p.acceptSymbol("["):
+			var from Expr
+			if !p.peek().is("..") {
+				if from, err = p.parseExpr(); err != nil {
+					return nil, err
+				}
+			}
+			if p.acceptSymbol("..") {
+				var to Expr
+				if !p.peek().is("]") {
+					if to, err = p.parseExpr(); err != nil {
+						return nil, err
+					}
+				}
+				expr = &Slice{Expr: expr, From: from, To: to}
+			} else {
+				if from == nil {
+					return nil, p.errorf("missing index")
+				}
+				expr = &Index{Expr: expr, Index: from}
+			}
+			if err := p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+
+		case p.peek().is(":"):
+			if _, ok := expr.(*Variable); !ok {
+				return expr, nil
+			}
+			labels := &HasLabels{Expr: expr}
+			for p.acceptSymbol(":") {
+				label, err := p.parseName()
+				if err != nil {
+					return nil, err
+				}
+				labels.Labels = append(labels.Labels, label)
+			}
+			expr = labels
+
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *parser) parseAtom() (Expr, error) {
+	tok := p.peek()
+
+	switch tok.kind {
+	case tokInteger:
+		p.pos++
+		v, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: tok.start, Msg: "integer out of range"}
+		}
+		return &Literal{Value: v}, nil
+	case tokFloat:
+		p.pos++
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: tok.start, Msg: "invalid float"}
+		}
+		return &Literal{Value: v}, nil
+	case tokString:
+		p.pos++
+		return &Literal{Value: tok.text}, nil
+	case tokParameter:
+		p.pos++
+		return &Parameter{Name: tok.text}, nil
+	case tokQuotedIdent:
+		p.pos++
+		return &Variable{Name: tok.text}, nil
+	case tokIdent:
+		return p.parseIdentAtom()
+	}
+
+	switch {
+	case p.acceptSymbol("["):
+		return p.parseListOrComprehension()
+	case p.peek().is("{"):
+		return p.parseMapLiteral()
+	case p.peek().is("("):
+		if part := p.tryPatternPredicate(); part != nil {
+			return &PatternPredicate{Part: part}, nil
+		}
+		p.pos++
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expectSymbol(")")
+	}
+
+	return nil, p.errorf("unexpected %s", p.describe())
+}
+
+// tryPatternPredicate parses a relationship pattern used as an expression,
+// restoring the position if the tokens are not such a pattern
+func (p *parser) tryPatternPredicate() *PatternPart {
+	start := p.pos
+	part, err := p.parsePatternPart()
+	if err != nil || len(part.Relationships) == 0 || part.PathVariable != "" {
+		p.pos = start
+		return nil
+	}
+	return part
+}
+
+func (p *parser) parseIdentAtom() (Expr, error) {
+	tok := p.next()
+	upper := strings.ToUpper(tok.text)
+
+	switch upper {
+	case "TRUE":
+		return &Literal{Value: true}, nil
+	case "FALSE":
+		return &Literal{Value: false}, nil
+	case "NULL":
+		return &Literal{Value: nil}, nil
+	case "CASE":
+		return p.parseCase()
+	}
+
+	if !p.peek().is("(") {
+		return &Variable{Name: tok.text}, nil
+	}
+
+	switch upper {
+	case "ALL", "ANY", "NONE", "SINGLE":
+		p.pos++
+		variable, list, where, err := p.parseFilterExpression()
+		if err != nil {
+			return nil, err
+		}
+		if where == nil {
+			return nil, p.errorf("%s() requires a WHERE predicate", upper)
+		}
+		return &Quantifier{Kind: upper, Variable: variable, List: list, Where: where}, p.expectSymbol(")")
+	case "EXISTS":
+		if part := p.tryExistsPattern(); part != nil {
+			return &PatternPredicate{Part: part}, nil
+		}
+	}
+
+	p.pos++ // (
+	call := &FunctionCall{Name: strings.ToLower(tok.text)}
+	if p.acceptSymbol("*") {
+		if call.Name != "count" {
+			return nil, p.errorf("only count accepts *")
+		}
+		call.Star = true
+		return call, p.expectSymbol(")")
+	}
+	call.Distinct = p.acceptKeyword("DISTINCT")
+	if !p.peek().is(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	return call, p.expectSymbol(")")
+}
+
+// tryExistsPattern parses exists((a)-[]->(b)), restoring the position if the
+// argument is not a pattern
+func (p *parser) tryExistsPattern() *PatternPart {
+	start := p.pos
+	p.pos++ // (
+	if part := p.tryPatternPredicate(); part != nil && p.acceptSymbol(")") {
+		return part
+	}
+	p.pos = start
+	return nil
+}
+
+// parseFilterExpression parses "x IN list [WHERE predicate]"
+func (p *parser) parseFilterExpression() (string, Expr, Expr, error) {
+	variable, err := p.parseName()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if err := p.expectKeyword("IN"); err != nil {
+		return "", nil, nil, err
+	}
+	list, err := p.parseExpr()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var where Expr
+	if p.acceptKeyword("WHERE") {
+		if where, err = p.parseExpr(); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	return variable, list, where, nil
+}
+
+func (p *parser) parseListOrComprehension() (Expr, error) {
+	// [x IN list ...] is a comprehension, anything else a list literal
+	if tok := p.peek(); (tok.kind == tokIdent || tok.kind == tokQuotedIdent) && p.peekAt(1).isKeyword("IN") {
+		variable, list, where, err := p.parseFilterExpression()
+		if err != nil {
+			return nil, err
+		}
+		comprehension := &ListComprehension{Variable: variable, List: list, Where: where}
+		if p.acceptSymbol("|") {
+			if comprehension.Projection, err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+		}
+		return comprehension, p.expectSymbol("]")
+	}
+
+	list := &ListLiteral{}
+	if p.acceptSymbol("]") {
+		return list, nil
+	}
+	for {
+		item, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return list, p.expectSymbol("]")
+}
+
+func (p *parser) parseMapLiteral() (Expr, error) {
+	if err := p.expectSymbol("{"); err != nil {
+		return nil, err
+	}
+	m := &MapLiteral{}
+	if p.acceptSymbol("}") {
+		return m, nil
+	}
+	for {
+		var key string
+		if tok := p.peek(); tok.kind == tokString {
+			key = p.next().text
+		} else {
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			key = name
+		}
+		if err := p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		m.Keys = append(m.Keys, key)
+		m.Values = append(m.Values, value)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return m, p.expectSymbol("}")
+}
+
+func (p *parser) parseCase() (Expr, error) {
+	c := &Case{}
+	var err error
+	if !p.peek().isKeyword("WHEN") {
+		if c.Test, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	for p.acceptKeyword("WHEN") {
+		var when CaseWhen
+		if when.When, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+		if when.Then, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		c.Whens = append(c.Whens, when)
+	}
+	if len(c.Whens) == 0 {
+		return nil, p.errorf("CASE requires at least one WHEN")
+	}
+	if p.acceptKeyword("ELSE") {
+		if c.Else, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return c, p.expectKeyword("END")
+}
@@ -0,0 +1,236 @@
+package cypher
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_MatchReturn(t *testing.T) {
+	q, err := Parse(`
+		MATCH (r:ResourceIdentity {uid: $uid})-[:OWNS*1..3]->(child)
+		WHERE r.deleted = false AND child.kind IN ['Pod', 'ReplicaSet']
+		RETURN DISTINCT child.uid AS uid, count(*) ORDER BY uid DESC SKIP 1 LIMIT 10
+	`)
+	require.NoError(t, err)
+	require.Len(t, q.Clauses, 2)
+	assert.True(t, q.IsReadOnly())
+
+	match, ok := q.Clauses[0].(*Match)
+	require.True(t, ok)
+	require.Len(t, match.Pattern, 1)
+	part := match.Pattern[0]
+	require.Len(t, part.Nodes, 2)
+	require.Len(t, part.Relationships, 1)
+
+	assert.Equal(t, "r", part.Nodes[0].Variable)
+	assert.Equal(t, []string{"ResourceIdentity"}, part.Nodes[0].Labels)
+	props, ok := part.Nodes[0].Properties.(*MapLiteral)
+	require.True(t, ok)
+	assert.Equal(t, []string{"uid"}, props.Keys)
+	assert.Equal(t, &Parameter{Name: "uid"}, props.Values[0])
+
+	rel := part.Relationships[0]
+	assert.Equal(t, []string{"OWNS"}, rel.Types)
+	assert.Equal(t, DirectionRight, rel.Direction)
+	assert.True(t, rel.VarLength)
+	assert.Equal(t, 1, rel.MinHops)
+	assert.Equal(t, 3, rel.MaxHops)
+
+	where, ok := match.Where.(*BinaryOp)
+	require.True(t, ok)
+	assert.Equal(t, "AND", where.Op)
+
+	ret, ok := q.Clauses[1].(*Return)
+	require.True(t, ok)
+	assert.True(t, ret.Distinct)
+	require.Len(t, ret.Items, 2)
+	assert.Equal(t, "uid", ret.Items[0].Alias)
+	assert.Equal(t, "count(*)", ret.Items[1].Alias)
+	require.Len(t, ret.OrderBy, 1)
+	assert.True(t, ret.OrderBy[0].Descending)
+	assert.Equal(t, &Literal{Value: int64(1)}, ret.Skip)
+	assert.Equal(t, &Literal{Value: int64(10)}, ret.Limit)
+}
+
+func TestParse_VarLengthBounds(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		min, max int
+	}{
+		{"(a)-[*]->(b)", 1, -1},
+		{"(a)-[*2]->(b)", 2, 2},
+		{"(a)-[*..4]->(b)", 1, 4},
+		{"(a)-[*0..]->(b)", 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			q, err := Parse("MATCH " + tt.pattern + " RETURN a")
+			require.NoError(t, err)
+			rel := q.Clauses[0].(*Match).Pattern[0].Relationships[0]
+			assert.True(t, rel.VarLength)
+			assert.Equal(t, tt.min, rel.MinHops)
+			assert.Equal(t, tt.max, rel.MaxHops)
+		})
+	}
+}
+
+func TestParse_WriteClauses(t *testing.T) {
+	q, err := Parse(`
+		MERGE (r:ResourceIdentity {uid: $uid})
+		ON CREATE SET r.firstSeen = $ts
+		ON MATCH SET r.lastSeen = $ts
+		SET r += $props
+		REMOVE r.deletedAt
+		WITH r
+		MATCH (r)<-[c:CHANGED]-(e)
+		DETACH DELETE e
+	`)
+	require.NoError(t, err)
+	assert.False(t, q.IsReadOnly())
+	require.Len(t, q.Clauses, 6)
+
+	merge := q.Clauses[0].(*Merge)
+	require.Len(t, merge.OnCreate, 1)
+	require.Len(t, merge.OnMatch, 1)
+	assert.Equal(t, "firstSeen", merge.OnCreate[0].Property.Key)
+
+	set := q.Clauses[1].(*Set)
+	require.Len(t, set.Items, 1)
+	assert.Equal(t, "r", set.Items[0].Variable)
+	assert.True(t, set.Items[0].Merge)
+
+	remove := q.Clauses[2].(*Remove)
+	assert.Equal(t, "deletedAt", remove.Items[0].Key)
+
+	match := q.Clauses[4].(*Match)
+	assert.Equal(t, DirectionLeft, match.Pattern[0].Relationships[0].Direction)
+
+	del := q.Clauses[5].(*Delete)
+	assert.True(t, del.Detach)
+}
+
+func TestParse_CreateIndex(t *testing.T) {
+	for _, query := range []string{
+		"CREATE INDEX FOR (n:ResourceIdentity) ON (n.uid)",
+		"CREATE INDEX ON :ResourceIdentity(uid)",
+	} {
+		q, err := Parse(query)
+		require.NoError(t, err, query)
+		require.Len(t, q.Clauses, 1)
+		assert.Equal(t, &CreateIndex{Label: "ResourceIdentity", Properties: []string{"uid"}}, q.Clauses[0])
+		assert.False(t, q.IsReadOnly())
+	}
+}
+
+func TestParse_CreateFulltextIndex(t *testing.T) {
+	q, err := Parse("CREATE FULLTEXT INDEX FOR (n:K8sEvent) ON (n.message)")
+	require.NoError(t, err)
+	require.Len(t, q.Clauses, 1)
+	assert.Equal(t, &CreateIndex{Label: "K8sEvent", Properties: []string{"message"}, Fulltext: true}, q.Clauses[0])
+	assert.False(t, q.IsReadOnly())
+}
+
+func TestParse_Call(t *testing.T) {
+	q, err := Parse(`
+		CALL db.idx.fulltext.queryNodes('K8sEvent', $query) YIELD node, score
+		RETURN node, score
+	`)
+	require.NoError(t, err)
+	require.Len(t, q.Clauses, 2)
+	assert.True(t, q.IsReadOnly())
+
+	call := q.Clauses[0].(*Call)
+	assert.Equal(t, "db.idx.fulltext.queryNodes", call.Procedure)
+	assert.Equal(t, []Expr{&Literal{Value: "K8sEvent"}, &Parameter{Name: "query"}}, call.Args)
+	assert.Equal(t, []string{"node", "score"}, call.Yield)
+
+	q, err = Parse("CALL db.idx.fulltext.drop('K8sEvent')")
+	require.NoError(t, err)
+	assert.Empty(t, q.Clauses[0].(*Call).Yield)
+	assert.False(t, q.IsReadOnly())
+}
+
+func TestParse_Expressions(t *testing.T) {
+	q, err := Parse(`
+		RETURN 1 + 2 * 3 AS arith,
+		       NOT a.deleted OR b IS NOT NULL AS logic,
+		       [x IN list WHERE x > 1 | x * 2] AS comprehension,
+		       CASE WHEN a.kind = 'Pod' THEN 1 ELSE 0 END AS branch,
+		       list[1..] AS tail,
+		       any(x IN list WHERE x STARTS WITH 'kube-') AS quantified,
+		       'it\'s' AS quoted,
+		       n:Pod AS labelled
+	`)
+	require.NoError(t, err)
+	items := q.Clauses[0].(*Return).Items
+	require.Len(t, items, 8)
+
+	arith := items[0].Expr.(*BinaryOp)
+	assert.Equal(t, "+", arith.Op)
+	assert.Equal(t, "*", arith.Right.(*BinaryOp).Op)
+
+	logic := items[1].Expr.(*BinaryOp)
+	assert.Equal(t, "OR", logic.Op)
+	assert.Equal(t, "NOT", logic.Left.(*UnaryOp).Op)
+	assert.True(t, logic.Right.(*IsNull).Not)
+
+	comprehension := items[2].Expr.(*ListComprehension)
+	assert.Equal(t, "x", comprehension.Variable)
+	assert.NotNil(t, comprehension.Where)
+	assert.NotNil(t, comprehension.Projection)
+
+	assert.Len(t, items[3].Expr.(*Case).Whens, 1)
+
+	slice := items[4].Expr.(*Slice)
+	assert.NotNil(t, slice.From)
+	assert.Nil(t, slice.To)
+
+	assert.Equal(t, "ANY", items[5].Expr.(*Quantifier).Kind)
+	assert.Equal(t, &Literal{Value: "it's"}, items[6].Expr)
+	assert.Equal(t, []string{"Pod"}, items[7].Expr.(*HasLabels).Labels)
+}
+
+func TestParse_PatternPredicate(t *testing.T) {
+	q, err := Parse("MATCH (e:ChangeEvent) WHERE NOT (e)-[:CHANGED]->() RETURN e")
+	require.NoError(t, err)
+	not := q.Clauses[0].(*Match).Where.(*UnaryOp)
+	predicate, ok := not.Expr.(*PatternPredicate)
+	require.True(t, ok)
+	assert.Equal(t, []string{"CHANGED"}, predicate.Part.Relationships[0].Types)
+}
+
+func TestParse_Comments(t *testing.T) {
+	q, err := Parse(`
+		// leading comment
+		MATCH (n) /* inline */ RETURN n // trailing
+	`)
+	require.NoError(t, err)
+	assert.Len(t, q.Clauses, 2)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"empty", ""},
+		{"unknown clause", "FOREACH (x IN [1] | CREATE ())"},
+		{"unterminated string", "RETURN 'abc"},
+		{"unbalanced parenthesis", "MATCH (n RETURN n"},
+		{"trailing input", "RETURN 1 2"},
+		{"both directions", "MATCH (a)<-[]->(b) RETURN a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			require.Error(t, err)
+			var syntaxErr *SyntaxError
+			assert.True(t, errors.As(err, &syntaxErr), "expected a SyntaxError, got %T", err)
+		})
+	}
+}
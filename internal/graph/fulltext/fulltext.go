@@ -0,0 +1,263 @@
+// Package fulltext implements the parts of full-text search that Spectre
+// needs on both graph backends: it tokenizes text the way FalkorDB's
+// full-text indexes (RediSearch) do, parses search input into a query that
+// can be passed to db.idx.fulltext.queryNodes, and matches and highlights
+// text against such a query.
+//
+// The supported query syntax is deliberately small: words must all occur,
+// "quoted phrases" must occur in order and a trailing * makes a word a
+// prefix. Words that contain separators, such as x509:certificate, are
+// searched as phrases.
+package fulltext
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minPrefixLength is the shortest prefix RediSearch expands
+const minPrefixLength = 2
+
+// maxQueryLength bounds the size of a query
+const maxQueryLength = 1024
+
+// separators are the characters RediSearch splits text on, besides white space
+const separators = ",.<>{}[]\"':;!@#$%^&*()-+=~"
+
+// stopwords are the words RediSearch leaves out of its indexes by default
+var stopwords = map[string]bool{
+	"a": true, "is": true, "the": true, "an": true, "and": true, "are": true, "as": true,
+	"at": true, "be": true, "but": true, "by": true, "for": true, "if": true, "in": true,
+	"into": true, "it": true, "no": true, "not": true, "of": true, "on": true, "or": true,
+	"such": true, "that": true, "their": true, "then": true, "there": true, "these": true,
+	"they": true, "this": true, "to": true, "was": true, "will": true, "with": true,
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(separators, r)
+}
+
+// Token is an indexed word of a text
+type Token struct {
+	Term  string // lower-cased
+	Start int    // byte offset of the word in the text
+	End   int
+}
+
+// Tokenize splits text into lower-cased words, leaving out stop words
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		term := strings.ToLower(text[start:end])
+		if !stopwords[term] {
+			tokens = append(tokens, Token{Term: term, Start: start, End: end})
+		}
+		start = -1
+	}
+
+	for i, r := range text {
+		if isSeparator(r) {
+			flush(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	flush(len(text))
+	return tokens
+}
+
+// Clause is a word or phrase of a query
+type Clause struct {
+	Terms  []string // consecutive words, lower-cased
+	Prefix bool     // the clause is a single word that matches as a prefix
+}
+
+// Query is a parsed full-text query. All clauses have to match.
+type Query struct {
+	Clauses []Clause
+}
+
+// Parse parses search input. Backslashes escape separators, so the output
+// of String parses back to the same query.
+func Parse(input string) (*Query, error) {
+	if len(input) > maxQueryLength {
+		return nil, fmt.Errorf("query is longer than %d characters", maxQueryLength)
+	}
+
+	q := &Query{}
+	i := 0
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated phrase at position %d", i+1)
+			}
+			terms := splitTerms(input[i+1 : i+1+end])
+			if len(terms) > 0 {
+				q.Clauses = append(q.Clauses, Clause{Terms: terms})
+			}
+			i += end + 2
+		default:
+			start := i
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if r == '\\' && i+size < len(input) {
+					_, escaped := utf8.DecodeRuneInString(input[i+size:])
+					i += size + escaped
+					continue
+				}
+				if unicode.IsSpace(r) || r == '"' {
+					break
+				}
+				i += size
+			}
+
+			word := input[start:i]
+			prefix := strings.HasSuffix(word, "*") && !strings.HasSuffix(word, `\*`)
+			if prefix {
+				word = word[:len(word)-1]
+			}
+			terms := splitTerms(word)
+			if len(terms) == 0 {
+				continue
+			}
+			clause := Clause{Terms: terms}
+			if prefix && len(terms) == 1 {
+				if utf8.RuneCountInString(terms[0]) < minPrefixLength {
+					return nil, fmt.Errorf("prefix %q is too short", terms[0]+"*")
+				}
+				clause.Prefix = true
+			}
+			q.Clauses = append(q.Clauses, clause)
+		}
+	}
+
+	if len(q.Clauses) == 0 {
+		return nil, fmt.Errorf("query has no searchable words")
+	}
+	return q, nil
+}
+
+// splitTerms tokenizes a word or phrase of a query, honouring escapes
+func splitTerms(s string) []string {
+	var terms []string
+	var b strings.Builder
+	flush := func() {
+		term := strings.ToLower(b.String())
+		b.Reset()
+		if term != "" && !stopwords[term] {
+			terms = append(terms, term)
+		}
+	}
+
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case isSeparator(r):
+			flush()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	return terms
+}
+
+// String renders the query in RediSearch syntax
+func (q *Query) String() string {
+	parts := make([]string, len(q.Clauses))
+	for i, c := range q.Clauses {
+		terms := make([]string, len(c.Terms))
+		for j, term := range c.Terms {
+			terms[j] = escape(term)
+		}
+		switch {
+		case len(terms) > 1:
+			parts[i] = `"` + strings.Join(terms, " ") + `"`
+		case c.Prefix:
+			parts[i] = terms[0] + "*"
+		default:
+			parts[i] = terms[0]
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// escape backslash-escapes the characters RediSearch treats as syntax
+func escape(term string) string {
+	var b strings.Builder
+	for _, r := range term {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Matches returns the spans of tokens matched by each clause, or nil when a
+// clause doesn't match. Each span covers the tokens of one occurrence.
+func (q *Query) Matches(tokens []Token) [][]Span {
+	matches := make([][]Span, len(q.Clauses))
+	for i, c := range q.Clauses {
+		for pos := 0; pos+len(c.Terms) <= len(tokens); pos++ {
+			if c.matchesAt(tokens, pos) {
+				last := tokens[pos+len(c.Terms)-1]
+				matches[i] = append(matches[i], Span{Start: tokens[pos].Start, End: last.End})
+			}
+		}
+		if len(matches[i]) == 0 {
+			return nil
+		}
+	}
+	return matches
+}
+
+// Occurrences counts how often each clause occurs in tokens
+func (q *Query) Occurrences(tokens []Token) []int {
+	counts := make([]int, len(q.Clauses))
+	for i, c := range q.Clauses {
+		for pos := 0; pos+len(c.Terms) <= len(tokens); pos++ {
+			if c.matchesAt(tokens, pos) {
+				counts[i]++
+			}
+		}
+	}
+	return counts
+}
+
+func (c Clause) matchesAt(tokens []Token, pos int) bool {
+	for j, term := range c.Terms {
+		if c.Prefix {
+			if !strings.HasPrefix(tokens[pos+j].Term, term) {
+				return false
+			}
+		} else if tokens[pos+j].Term != term {
+			return false
+		}
+	}
+	return true
+}
+
+// Span is a byte range of a text
+type Span struct {
+	Start int
+	End   int
+}
@@ -0,0 +1,115 @@
+package fulltext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	text := "Back-off pulling image \"nginx:1.27\""
+	var terms []string
+	for _, token := range Tokenize(text) {
+		terms = append(terms, token.Term)
+		assert.Equal(t, token.Term, strings.ToLower(text[token.Start:token.End]))
+	}
+	assert.Equal(t, []string{"back", "off", "pulling", "image", "nginx", "1", "27"}, terms)
+
+	// Stop words are not indexed
+	assert.Empty(t, Tokenize("it is the"))
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"OOMKilled", "oomkilled"},
+		{"failed mount", "failed mount"},
+		{`"back-off restarting"`, `"back off restarting"`},
+		{"x509:certificate", `"x509 certificate"`},
+		{"imagepull*", "imagepull*"},
+		{"the failed", "failed"},
+		{`"restarting  container"  pod*`, `"restarting container" pod*`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, q.String())
+
+			// The rendered query parses back to the same query
+			again, err := Parse(q.String())
+			require.NoError(t, err)
+			assert.Equal(t, q, again)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "no searchable words"},
+		{"the", "no searchable words"},
+		{`"unterminated`, "unterminated phrase"},
+		{"x*", "too short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestQuery_Matches(t *testing.T) {
+	tokens := Tokenize("Back-off restarting failed container api in pod api-7f9c")
+
+	tests := []struct {
+		query   string
+		matches bool
+	}{
+		{"restarting", true},
+		{"RESTARTING failed", true},
+		{`"back-off restarting"`, true},
+		{`"restarting back-off"`, false},
+		{"restart*", true},
+		{"restart", false},
+		{"failed oomkilled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.matches, q.Matches(tokens) != nil)
+		})
+	}
+
+	q, err := Parse("api")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, q.Occurrences(tokens))
+}
+
+func TestHighlight(t *testing.T) {
+	q, err := Parse(`"failed container" restart*`)
+	require.NoError(t, err)
+
+	fragments := Highlight("Back-off restarting failed container", q)
+	assert.Equal(t, []Fragment{
+		{Text: "Back-off "},
+		{Text: "restarting", Match: true},
+		{Text: " "},
+		{Text: "failed container", Match: true},
+	}, fragments)
+
+	// Text without matches is a single fragment
+	assert.Equal(t, []Fragment{{Text: "all good"}}, Highlight("all good", q))
+}
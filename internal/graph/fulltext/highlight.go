@@ -0,0 +1,52 @@
+package fulltext
+
+import "sort"
+
+// Fragment is a piece of a highlighted text
+type Fragment struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// Highlight splits text into fragments, marking the words and phrases that
+// match the query. Concatenating the fragments yields the text.
+func Highlight(text string, q *Query) []Fragment {
+	tokens := Tokenize(text)
+	var spans []Span
+	for _, clause := range q.Clauses {
+		single := &Query{Clauses: []Clause{clause}}
+		if matches := single.Matches(tokens); matches != nil {
+			spans = append(spans, matches[0]...)
+		}
+	}
+	if len(spans) == 0 {
+		return []Fragment{{Text: text}}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	merged := []Span{spans[0]}
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span.Start <= last.End {
+			if span.End > last.End {
+				last.End = span.End
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	var fragments []Fragment
+	pos := 0
+	for _, span := range merged {
+		if span.Start > pos {
+			fragments = append(fragments, Fragment{Text: text[pos:span.Start]})
+		}
+		fragments = append(fragments, Fragment{Text: text[span.Start:span.End], Match: true})
+		pos = span.End
+	}
+	if pos < len(text) {
+		fragments = append(fragments, Fragment{Text: text[pos:]})
+	}
+	return fragments
+}
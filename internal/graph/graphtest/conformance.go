@@ -0,0 +1,535 @@
+// Package graphtest provides a conformance suite that checks a graph.Client
+// implementation against the queries Spectre issues.
+package graphtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewClientFunc returns a connected client with an empty graph. It is called
+// once per subtest and is responsible for cleaning up the client.
+type NewClientFunc func(t *testing.T) graph.Client
+
+// RunConformance runs the conformance suite against the clients returned by
+// newClient. Every backend must pass the same suite, which keeps them
+// interchangeable.
+func RunConformance(t *testing.T, newClient NewClientFunc) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, client graph.Client)
+	}{
+		{"Parameters", testParameters},
+		{"EmptyResult", testEmptyResult},
+		{"ResultTypes", testResultTypes},
+		{"UpsertResourceIdentity", testUpsertResourceIdentity},
+		{"EventChain", testEventChain},
+		{"Aggregation", testAggregation},
+		{"OptionalMatch", testOptionalMatch},
+		{"VariableLengthPaths", testVariableLengthPaths},
+		{"DetachDelete", testDetachDelete},
+		{"GraphStats", testGraphStats},
+		{"FulltextSearch", testFulltextSearch},
+		{"Pipeline", testPipeline},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newClient(t))
+		})
+	}
+}
+
+func execute(t *testing.T, client graph.Client, query string, params map[string]interface{}) *graph.QueryResult {
+	t.Helper()
+	result, err := client.ExecuteQuery(context.Background(), graph.GraphQuery{Query: query, Parameters: params})
+	require.NoError(t, err, "query: %s", query)
+	return result
+}
+
+func executeQuery(t *testing.T, client graph.Client, query graph.GraphQuery) *graph.QueryResult {
+	t.Helper()
+	result, err := client.ExecuteQuery(context.Background(), query)
+	require.NoError(t, err, "query: %s", query.Query)
+	return result
+}
+
+// column returns the values of one column
+func column(result *graph.QueryResult, index int) []interface{} {
+	values := make([]interface{}, len(result.Rows))
+	for i, row := range result.Rows {
+		values[i] = row[index]
+	}
+	return values
+}
+
+func testParameters(t *testing.T, client graph.Client) {
+	result := execute(t, client,
+		"RETURN $s AS s, $i AS i, $f AS f, $b AS b, $n AS n, $list AS list, $map AS map, $quoted AS quoted",
+		map[string]interface{}{
+			"s":      "hello",
+			"i":      42,
+			"f":      1.5,
+			"b":      true,
+			"n":      nil,
+			"list":   []string{"a", "b"},
+			"map":    map[string]interface{}{"key": int64(1)},
+			"quoted": `it's a "test" \ with escapes`,
+		})
+
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []string{"s", "i", "f", "b", "n", "list", "map", "quoted"}, result.Columns)
+	assert.Equal(t, []interface{}{
+		"hello",
+		int64(42),
+		1.5,
+		true,
+		nil,
+		[]interface{}{"a", "b"},
+		map[string]interface{}{"key": int64(1)},
+		`it's a "test" \ with escapes`,
+	}, result.Rows[0])
+}
+
+func testEmptyResult(t *testing.T, client graph.Client) {
+	result := execute(t, client, "MATCH (n:Missing) RETURN n", nil)
+	assert.Empty(t, result.Rows)
+	assert.Empty(t, result.Columns)
+
+	// Aggregating without grouping keys always returns a row
+	result = execute(t, client, "MATCH (n:Missing) RETURN count(n) AS c, collect(n) AS all", nil)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []interface{}{int64(0), []interface{}{}}, result.Rows[0])
+}
+
+func testResultTypes(t *testing.T, client graph.Client) {
+	execute(t, client, "CREATE (:Thing {uid: 'a', n: 1})-[:LINKS {weight: 0.5}]->(:Thing {uid: 'b', n: 2})", nil)
+
+	result := execute(t, client, "MATCH (a:Thing {uid: 'a'})-[r:LINKS]->(b) RETURN a, r, b.uid", nil)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []string{"a", "r", "b.uid"}, result.Columns)
+
+	props, err := graph.ParseNodeFromResult(result.Rows[0][0])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"uid": "a", "n": int64(1)}, props)
+
+	edgeType, edgeProps, err := graph.ParseEdgeFromResult(result.Rows[0][1])
+	require.NoError(t, err)
+	assert.Equal(t, "LINKS", edgeType)
+	assert.Equal(t, map[string]interface{}{"weight": 0.5}, edgeProps)
+
+	assert.Equal(t, "b", result.Rows[0][2])
+}
+
+func testUpsertResourceIdentity(t *testing.T, client graph.Client) {
+	resource := graph.ResourceIdentity{
+		UID:       "pod-1",
+		Cluster:   "prod",
+		Kind:      "Pod",
+		Version:   "v1",
+		Namespace: "default",
+		Name:      "web",
+		Labels:    map[string]string{"app": "web"},
+		FirstSeen: 100,
+		LastSeen:  100,
+	}
+
+	result := executeQuery(t, client, graph.UpsertResourceIdentityQuery(resource))
+	assert.Equal(t, 1, result.Stats.NodesCreated)
+
+	resource.Labels = map[string]string{"app": "web", "tier": "frontend"}
+	resource.FirstSeen = 200
+	resource.LastSeen = 200
+	result = executeQuery(t, client, graph.UpsertResourceIdentityQuery(resource))
+	assert.Equal(t, 0, result.Stats.NodesCreated, "MERGE must match the existing node")
+
+	node := findResource(t, client, "pod-1")
+	assert.Equal(t, int64(100), node.FirstSeen, "firstSeen is only set on create")
+	assert.Equal(t, int64(200), node.LastSeen)
+	assert.Equal(t, "frontend", node.Labels["tier"])
+	assert.False(t, node.Deleted)
+
+	resource.Deleted = true
+	resource.DeletedAt = 300
+	resource.LastSeen = 300
+	executeQuery(t, client, graph.UpsertResourceIdentityQuery(resource))
+
+	// Updates after the deletion do not resurrect the resource
+	resource.Deleted = false
+	resource.DeletedAt = 0
+	resource.LastSeen = 400
+	executeQuery(t, client, graph.UpsertResourceIdentityQuery(resource))
+
+	node = findResource(t, client, "pod-1")
+	assert.True(t, node.Deleted)
+	assert.Equal(t, int64(300), node.DeletedAt)
+	assert.Equal(t, int64(300), node.LastSeen)
+
+	result = execute(t, client, "MATCH (r:ResourceIdentity) RETURN count(r)", nil)
+	assert.Equal(t, int64(1), result.Rows[0][0])
+}
+
+func findResource(t *testing.T, client graph.Client, uid string) graph.ResourceIdentity {
+	t.Helper()
+	result := executeQuery(t, client, graph.FindResourceByUIDQuery(uid))
+	require.Len(t, result.Rows, 1)
+	props, err := graph.ParseNodeFromResult(result.Rows[0][0])
+	require.NoError(t, err)
+	return graph.ParseResourceIdentityFromNode(props)
+}
+
+// seedEventChain creates a resource with three change events linked by
+// CHANGED and PRECEDED_BY edges
+func seedEventChain(t *testing.T, client graph.Client) {
+	executeQuery(t, client, graph.UpsertResourceIdentityQuery(graph.ResourceIdentity{
+		UID: "deploy-1", Kind: "Deployment", Namespace: "default", Name: "web", FirstSeen: 1000, LastSeen: 3000,
+	}))
+	var previous string
+	for i, status := range []string{"Ready", "Warning", "Error"} {
+		id := fmt.Sprintf("event-%d", i)
+		executeQuery(t, client, graph.CreateChangeEventQuery(graph.ChangeEvent{
+			ID:              id,
+			Timestamp:       int64(i+1) * 1000,
+			EventType:       "UPDATE",
+			Status:          status,
+			ContainerIssues: []string{},
+			ImpactScore:     float64(i) / 2,
+			Data:            `{"spec":{"replicas":1}}`,
+		}))
+		executeQuery(t, client, graph.CreateChangedEdgeQuery("deploy-1", id, i))
+		if previous != "" {
+			executeQuery(t, client, graph.CreatePrecededByEdgeQuery(id, previous, 1))
+		}
+		previous = id
+	}
+}
+
+func testEventChain(t *testing.T, client graph.Client) {
+	seedEventChain(t, client)
+
+	// Re-running the edge query must not duplicate the edge
+	result := executeQuery(t, client, graph.CreateChangedEdgeQuery("deploy-1", "event-0", 0))
+	assert.Equal(t, 0, result.Stats.RelationshipsCreated)
+
+	result = executeQuery(t, client, graph.FindChangeEventsByResourceQuery("deploy-1", 1500, 5000))
+	require.Len(t, result.Rows, 2)
+	var statuses []string
+	for _, row := range result.Rows {
+		props, err := graph.ParseNodeFromResult(row[0])
+		require.NoError(t, err)
+		statuses = append(statuses, graph.ParseChangeEventFromNode(props).Status)
+	}
+	assert.Equal(t, []string{"Warning", "Error"}, statuses)
+
+	result = execute(t, client, `
+		MATCH (latest:ChangeEvent {id: 'event-2'})-[:PRECEDED_BY*1..5]->(earlier:ChangeEvent)
+		RETURN earlier.id AS id
+		ORDER BY earlier.timestamp DESC
+	`, nil)
+	assert.Equal(t, []interface{}{"event-1", "event-0"}, column(result, 0))
+
+	// Events that have a successor
+	result = execute(t, client, `
+		MATCH (e:ChangeEvent)
+		WHERE (e)<-[:PRECEDED_BY]-(:ChangeEvent)
+		RETURN e.id ORDER BY e.id
+	`, nil)
+	assert.Equal(t, []interface{}{"event-0", "event-1"}, column(result, 0))
+}
+
+func testAggregation(t *testing.T, client graph.Client) {
+	execute(t, client, `
+		UNWIND $items AS item
+		CREATE (:Item {name: item.name, group: item.group, size: item.size})
+	`, map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"name": "a", "group": "x", "size": 3},
+			{"name": "b", "group": "y", "size": 1},
+			{"name": "c", "group": "x", "size": 2},
+			{"name": "d", "group": "z", "size": 5},
+		},
+	})
+
+	result := execute(t, client, `
+		MATCH (i:Item)
+		WITH i ORDER BY i.name
+		RETURN i.group AS group, count(*) AS total, collect(i.name) AS names, sum(i.size) AS size, max(i.size) AS largest
+		ORDER BY total DESC, group
+	`, nil)
+	assert.Equal(t, [][]interface{}{
+		{"x", int64(2), []interface{}{"a", "c"}, int64(5), int64(3)},
+		{"y", int64(1), []interface{}{"b"}, int64(1), int64(1)},
+		{"z", int64(1), []interface{}{"d"}, int64(5), int64(5)},
+	}, result.Rows)
+
+	result = execute(t, client, `
+		MATCH (i:Item)
+		WITH i.group AS group, count(i) AS total
+		WHERE total > 1
+		RETURN group
+	`, nil)
+	assert.Equal(t, []interface{}{"x"}, column(result, 0))
+
+	result = execute(t, client, `
+		MATCH (i:Item)
+		RETURN DISTINCT i.group AS group
+		ORDER BY group DESC
+		SKIP 1 LIMIT $limit
+	`, map[string]interface{}{"limit": 1})
+	assert.Equal(t, []interface{}{"y"}, column(result, 0))
+
+	result = execute(t, client, `
+		MATCH (i:Item)
+		WHERE i.name IN $names AND NOT i.group = 'y'
+		RETURN [n IN collect(i.name) WHERE n <> 'a' | toUpper(n)] AS names,
+		       CASE WHEN count(*) > 1 THEN 'many' ELSE 'one' END AS amount
+	`, map[string]interface{}{"names": []string{"a", "b", "c"}})
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []interface{}{[]interface{}{"C"}, "many"}, result.Rows[0])
+}
+
+func testOptionalMatch(t *testing.T, client graph.Client) {
+	execute(t, client, "CREATE (:Owner {uid: 'o1'})-[:OWNS]->(:Owned {uid: 'c1'}), (:Owner {uid: 'o2'})", nil)
+
+	result := execute(t, client, `
+		MATCH (o:Owner)
+		OPTIONAL MATCH (o)-[r:OWNS]->(c:Owned)
+		RETURN o.uid, type(r), c.uid, coalesce(c.uid, 'none') AS child
+		ORDER BY o.uid
+	`, nil)
+	assert.Equal(t, [][]interface{}{
+		{"o1", "OWNS", "c1", "c1"},
+		{"o2", nil, nil, "none"},
+	}, result.Rows)
+
+	// WHERE on an OPTIONAL MATCH filters the optional part, not the row
+	result = execute(t, client, `
+		MATCH (o:Owner)
+		OPTIONAL MATCH (o)-[:OWNS]->(c:Owned)
+		WHERE c.uid = 'missing'
+		RETURN o.uid, c
+		ORDER BY o.uid
+	`, nil)
+	assert.Equal(t, [][]interface{}{{"o1", nil}, {"o2", nil}}, result.Rows)
+}
+
+func testVariableLengthPaths(t *testing.T, client graph.Client) {
+	execute(t, client, `
+		CREATE (a:Step {name: 'a'})-[:NEXT {ok: true}]->(b:Step {name: 'b'}),
+		       (b)-[:NEXT {ok: true}]->(c:Step {name: 'c'}),
+		       (c)-[:NEXT {ok: false}]->(d:Step {name: 'd'})
+	`, nil)
+
+	result := execute(t, client, `
+		MATCH p = (:Step {name: 'a'})-[:NEXT*1..3]->(s:Step)
+		WHERE ALL(rel IN relationships(p) WHERE rel.ok)
+		RETURN s.name, length(p)
+		ORDER BY length(p)
+	`, nil)
+	assert.Equal(t, [][]interface{}{{"b", int64(1)}, {"c", int64(2)}}, result.Rows)
+
+	// Undirected matches follow relationships in both directions
+	result = execute(t, client, `
+		MATCH (:Step {name: 'b'})-[:NEXT]-(n:Step)
+		RETURN n.name ORDER BY n.name
+	`, nil)
+	assert.Equal(t, []interface{}{"a", "c"}, column(result, 0))
+
+	result = execute(t, client, `
+		MATCH (s:Step {name: 'd'})<-[:NEXT*]-(prev:Step)
+		RETURN collect(prev.name) AS names
+	`, nil)
+	require.Len(t, result.Rows, 1)
+	names := result.Rows[0][0].([]interface{})
+	sort.Slice(names, func(i, j int) bool { return names[i].(string) < names[j].(string) })
+	assert.Equal(t, []interface{}{"a", "b", "c"}, names)
+}
+
+func testDetachDelete(t *testing.T, client graph.Client) {
+	seedEventChain(t, client)
+
+	result := executeQuery(t, client, graph.DeleteOldChangeEventsQuery(2500))
+	assert.Equal(t, 2, result.Stats.NodesDeleted)
+	// Two CHANGED edges and both PRECEDED_BY edges touch the deleted events
+	assert.Equal(t, 4, result.Stats.RelationshipsDeleted)
+
+	result = execute(t, client, "MATCH (:ResourceIdentity)-[c:CHANGED]->(e:ChangeEvent) RETURN e.id, c.sequenceNumber", nil)
+	assert.Equal(t, [][]interface{}{{"event-2", int64(2)}}, result.Rows)
+
+	// Deleting an unmatched OPTIONAL MATCH variable is a no-op
+	result = execute(t, client, `
+		MATCH (r:ResourceIdentity {uid: 'deploy-1'})
+		OPTIONAL MATCH (r)-[:OWNS]->(child)
+		DETACH DELETE child
+	`, nil)
+	assert.Equal(t, 0, result.Stats.NodesDeleted)
+}
+
+func testGraphStats(t *testing.T, client graph.Client) {
+	require.NoError(t, client.InitializeSchema(context.Background()))
+	seedEventChain(t, client)
+
+	stats, err := client.GetGraphStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.NodeCount)
+	assert.Equal(t, 1, stats.NodesByType[graph.NodeTypeResourceIdentity])
+	assert.Equal(t, 3, stats.NodesByType[graph.NodeTypeChangeEvent])
+	assert.Equal(t, 5, stats.EdgeCount)
+	assert.Equal(t, 3, stats.EdgesByType[graph.EdgeTypeChanged])
+	assert.Equal(t, 2, stats.EdgesByType[graph.EdgeType("PRECEDED_BY")])
+	assert.Equal(t, int64(1000), stats.OldestTimestamp)
+	assert.Equal(t, int64(3000), stats.NewestTimestamp)
+
+	node, err := client.GetNode(context.Background(), graph.NodeTypeResourceIdentity, "deploy-1")
+	require.NoError(t, err)
+	var props map[string]interface{}
+	require.NoError(t, json.Unmarshal(node.Properties, &props))
+	assert.Equal(t, "web", props["name"])
+
+	_, err = client.GetNode(context.Background(), graph.NodeTypeResourceIdentity, "missing")
+	assert.Error(t, err)
+}
+
+// testFulltextSearch queries the full-text indexes created by the schema
+// the way /v1/events/search does
+func testFulltextSearch(t *testing.T, client graph.Client) {
+	require.NoError(t, client.InitializeSchema(context.Background()))
+	execute(t, client, `
+		CREATE (r:ResourceIdentity {uid: 'pod-1', namespace: 'default'})
+		CREATE (r)-[:EMITTED_EVENT]->(:K8sEvent {id: 'e1', timestamp: 1000, message: 'Back-off restarting failed container'})
+		CREATE (r)-[:EMITTED_EVENT]->(:K8sEvent {id: 'e2', timestamp: 2000, message: 'Started container nginx'})
+		CREATE (r)-[:CHANGED]->(:ChangeEvent {id: 'c1', timestamp: 2000, errorMessage: 'x509: certificate signed by unknown authority'})
+	`, nil)
+
+	query := `
+		CALL db.idx.fulltext.queryNodes($label, $query) YIELD node, score
+		WITH node, score
+		WHERE node.timestamp >= $startNs
+		MATCH (r:ResourceIdentity)-->(node)
+		RETURN node.id, score, r.uid
+		ORDER BY score DESC`
+
+	result := execute(t, client, query, map[string]interface{}{"label": "K8sEvent", "query": "container", "startNs": 0})
+	assert.ElementsMatch(t, []interface{}{"e1", "e2"}, column(result, 0))
+	for _, row := range result.Rows {
+		score, ok := row[1].(float64)
+		require.True(t, ok, "score is %T", row[1])
+		assert.Greater(t, score, 0.0)
+		assert.Equal(t, "pod-1", row[2])
+	}
+
+	result = execute(t, client, query, map[string]interface{}{"label": "K8sEvent", "query": `"failed container" restart*`, "startNs": 0})
+	assert.Equal(t, []interface{}{"e1"}, column(result, 0))
+
+	result = execute(t, client, query, map[string]interface{}{"label": "K8sEvent", "query": "container", "startNs": 1500})
+	assert.Equal(t, []interface{}{"e2"}, column(result, 0))
+
+	result = execute(t, client, query, map[string]interface{}{"label": "ChangeEvent", "query": `"x509 certificate"`, "startNs": 0})
+	assert.Equal(t, []interface{}{"c1"}, column(result, 0))
+}
+
+// testPipeline ingests events through the sync pipeline, which issues the
+// write queries of the graph builder and the extractors, and reads them back
+// through the timeline query executor
+func testPipeline(t *testing.T, client graph.Client) {
+	ctx := context.Background()
+	pipeline := sync.NewPipeline(sync.DefaultPipelineConfig(), client)
+	require.NoError(t, pipeline.Start(ctx))
+	t.Cleanup(func() { _ = pipeline.Stop(ctx) })
+
+	base := time.Unix(1_700_000_000, 0)
+	events := []models.Event{
+		resourceEvent("deploy-uid", "Deployment", "apps", "web", "", base, models.EventTypeCreate, nil),
+		resourceEvent("rs-uid", "ReplicaSet", "apps", "web-1", "deploy-uid", base.Add(time.Second), models.EventTypeCreate, nil),
+		resourceEvent("pod-uid", "Pod", "", "web-1-a", "rs-uid", base.Add(2*time.Second), models.EventTypeCreate,
+			map[string]interface{}{"phase": "Running"}),
+		resourceEvent("pod-uid", "Pod", "", "web-1-a", "rs-uid", base.Add(time.Minute), models.EventTypeUpdate,
+			map[string]interface{}{"phase": "Failed"}),
+	}
+	require.NoError(t, pipeline.ProcessBatch(ctx, events))
+
+	result := execute(t, client, `
+		MATCH (d:ResourceIdentity {uid: $uid})-[:OWNS*1..3]->(owned:ResourceIdentity)
+		RETURN owned.kind ORDER BY owned.kind
+	`, map[string]interface{}{"uid": "deploy-uid"})
+	assert.Equal(t, []interface{}{"Pod", "ReplicaSet"}, column(result, 0))
+
+	executor := graph.NewQueryExecutor(client)
+	timeline, err := executor.Execute(ctx, &models.QueryRequest{
+		StartTimestamp: base.Unix() - 60,
+		EndTimestamp:   base.Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	eventsByKind := make(map[string]int)
+	for _, event := range timeline.Events {
+		eventsByKind[event.Resource.Kind]++
+	}
+	assert.Equal(t, map[string]int{"Deployment": 1, "ReplicaSet": 1, "Pod": 2}, eventsByKind)
+
+	timeline, err = executor.Execute(ctx, &models.QueryRequest{
+		StartTimestamp: base.Unix() - 60,
+		EndTimestamp:   base.Add(time.Hour).Unix(),
+		Filters:        models.QueryFilters{Kinds: []string{"Pod"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, timeline.Events, 2)
+	assert.Equal(t, "pod-uid", timeline.Events[0].Resource.UID)
+}
+
+// resourceEvent builds a namespaced resource event, owned by ownerUID if set
+func resourceEvent(uid, kind, group, name, ownerUID string, timestamp time.Time, eventType models.EventType, status map[string]interface{}) models.Event {
+	apiVersion := "v1"
+	if group != "" {
+		apiVersion = group + "/v1"
+	}
+	metadata := map[string]interface{}{
+		"name":      name,
+		"namespace": "default",
+		"uid":       uid,
+	}
+	if ownerUID != "" {
+		metadata["ownerReferences"] = []map[string]interface{}{{
+			"apiVersion": "apps/v1",
+			"kind":       "Owner",
+			"name":       "owner",
+			"uid":        ownerUID,
+			"controller": true,
+		}}
+	}
+	object := map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   metadata,
+	}
+	if status != nil {
+		object["status"] = status
+	}
+	data, _ := json.Marshal(object)
+
+	return models.Event{
+		ID:        fmt.Sprintf("%s-%d", uid, timestamp.UnixNano()),
+		Timestamp: timestamp.UnixNano(),
+		Type:      eventType,
+		Resource: models.ResourceMetadata{
+			Group:     group,
+			Version:   "v1",
+			Kind:      kind,
+			Namespace: "default",
+			Name:      name,
+			UID:       uid,
+		},
+		Data:     data,
+		DataSize: int32(len(data)),
+	}
+}
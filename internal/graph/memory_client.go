@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/moolen/spectre/internal/graph/cypher"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+// parsedQueryCacheSize bounds the number of parsed queries kept by the
+// in-memory backend. Spectre issues a fixed set of query shapes, so a small
+// cache covers all of them.
+const parsedQueryCacheSize = 512
+
+// memoryClient implements the Client interface with an in-process graph. It
+// supports the subset of Cypher that Spectre issues and mirrors FalkorDB's
+// result types, so callers cannot tell the backends apart.
+//
+// Read-only queries run concurrently; a query that writes holds an exclusive
+// lock and is rolled back as a whole if it fails.
+type memoryClient struct {
+	cypherClient
+	config ClientConfig
+	logger *logging.Logger
+
+	mu        sync.RWMutex
+	connected bool
+	graphs    map[string]*memGraph
+
+	parsed *lru.Cache[string, *cypher.Query]
+}
+
+func newMemoryClient(config ClientConfig, logger *logging.Logger) *memoryClient {
+	// lru.New only fails for a non-positive size
+	parsed, _ := lru.New[string, *cypher.Query](parsedQueryCacheSize)
+
+	c := &memoryClient{
+		config: config,
+		logger: logger,
+		graphs: make(map[string]*memGraph),
+		parsed: parsed,
+	}
+	c.cypherClient = cypherClient{execute: c.ExecuteQuery, logger: logger, graphName: config.GraphName}
+	return c
+}
+
+// Connect selects the configured graph, creating it if needed
+func (c *memoryClient) Connect(ctx context.Context) error {
+	c.logger.Info("Using in-memory graph backend (graph: %s)", c.config.GraphName)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.graphs[c.config.GraphName]; !ok {
+		c.graphs[c.config.GraphName] = newMemGraph()
+	}
+	c.connected = true
+	return nil
+}
+
+// Close disconnects the client. The graph stays in memory until the client
+// is garbage collected.
+func (c *memoryClient) Close() error {
+	c.logger.Info("Closing in-memory graph backend")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connected = false
+	return nil
+}
+
+// Ping checks if the client is connected
+func (c *memoryClient) Ping(ctx context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.connected {
+		return fmt.Errorf("client not connected")
+	}
+	return nil
+}
+
+// ExecuteQuery executes a Cypher query against the in-memory graph
+func (c *memoryClient) ExecuteQuery(ctx context.Context, query GraphQuery) (*QueryResult, error) {
+	parsed, err := c.parse(query.Query)
+	if err != nil {
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+
+	params := make(map[string]interface{}, len(query.Parameters))
+	for name, value := range query.Parameters {
+		if params[name], err = parameterValue(value); err != nil {
+			return nil, fmt.Errorf("invalid query parameters: query parameter %q: %w", name, err)
+		}
+	}
+
+	if query.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(query.Timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	readOnly := parsed.IsReadOnly()
+	if readOnly {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+	} else {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	if !c.connected {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	// Like FalkorDB, a deleted graph is recreated by the next write
+	g, ok := c.graphs[c.config.GraphName]
+	if !ok {
+		g = newMemGraph()
+		if !readOnly {
+			c.graphs[c.config.GraphName] = g
+		}
+	}
+
+	startTime := time.Now()
+	tx := newMemTxn(g)
+	columns, rows, err := newEvaluator(ctx, tx, params).execute(parsed)
+	if err != nil {
+		tx.rollback()
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	tx.commit()
+
+	// The FalkorDB client reads column names from the first record, so an
+	// empty result has no columns
+	result := &QueryResult{
+		Columns: []string{},
+		Rows:    [][]interface{}{},
+		Stats:   tx.stats,
+	}
+	if len(rows) > 0 {
+		result.Columns = columns
+		result.Rows = rows
+	}
+	result.Stats.ExecutionTime = time.Since(startTime)
+
+	return result, nil
+}
+
+// parse parses a query, reusing the parse tree of earlier identical queries
+func (c *memoryClient) parse(query string) (*cypher.Query, error) {
+	if parsed, ok := c.parsed.Get(query); ok {
+		return parsed, nil
+	}
+	parsed, err := cypher.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	c.parsed.Add(query, parsed)
+	return parsed, nil
+}
+
+// DeleteGraph completely removes the graph (for testing purposes)
+func (c *memoryClient) DeleteGraph(ctx context.Context) error {
+	return c.DeleteGraphByName(ctx, c.config.GraphName)
+}
+
+// CreateGraph creates a new named graph database
+func (c *memoryClient) CreateGraph(ctx context.Context, graphName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return fmt.Errorf("client not connected")
+	}
+
+	if _, ok := c.graphs[graphName]; !ok {
+		c.graphs[graphName] = newMemGraph()
+		c.logger.Info("Graph '%s' created successfully", graphName)
+	}
+	return nil
+}
+
+// DeleteGraphByName deletes a specific named graph database
+func (c *memoryClient) DeleteGraphByName(ctx context.Context, graphName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return fmt.Errorf("client not connected")
+	}
+
+	if _, ok := c.graphs[graphName]; !ok {
+		c.logger.Debug("Graph '%s' does not exist, nothing to delete", graphName)
+		return nil
+	}
+	delete(c.graphs, graphName)
+	c.logger.Info("Graph '%s' deleted successfully", graphName)
+	return nil
+}
+
+// GraphExists checks if a named graph exists
+func (c *memoryClient) GraphExists(ctx context.Context, graphName string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.connected {
+		return false, fmt.Errorf("client not connected")
+	}
+
+	_, exists := c.graphs[graphName]
+	return exists, nil
+}
@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryClient(t testing.TB) Client {
+	t.Helper()
+
+	config := DefaultClientConfig()
+	config.Backend = BackendMemory
+	client := NewClient(config)
+	require.NoError(t, client.Connect(context.Background()))
+	return client
+}
+
+func countNodes(t *testing.T, client Client) int64 {
+	t.Helper()
+
+	result, err := client.ExecuteQuery(context.Background(), GraphQuery{Query: "MATCH (n) RETURN count(n)"})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	return result.Rows[0][0].(int64)
+}
+
+func TestParseBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Backend
+		wantErr  bool
+	}{
+		{name: "", expected: BackendFalkorDB},
+		{name: "falkordb", expected: BackendFalkorDB},
+		{name: "memory", expected: BackendMemory},
+		{name: "neo4j", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, err := ParseBackend(tt.name)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, backend)
+		})
+	}
+}
+
+func TestMemoryClient_RollsBackFailedQuery(t *testing.T) {
+	client := newTestMemoryClient(t)
+	ctx := context.Background()
+
+	_, err := client.ExecuteQuery(ctx, GraphQuery{Query: "CREATE (:Pod {name: 'a'})"})
+	require.NoError(t, err)
+
+	// The second CREATE fails after the first has run, so neither may persist
+	_, err = client.ExecuteQuery(ctx, GraphQuery{
+		Query: "CREATE (:Pod {name: 'b'}) CREATE (:Pod {labels: {nested: true}})",
+	})
+	require.Error(t, err)
+	assert.Equal(t, int64(1), countNodes(t, client))
+}
+
+func TestMemoryClient_DuplicateIndex(t *testing.T) {
+	client := newTestMemoryClient(t)
+	ctx := context.Background()
+
+	query := GraphQuery{Query: "CREATE INDEX FOR (n:ResourceIdentity) ON (n.uid)"}
+	_, err := client.ExecuteQuery(ctx, query)
+	require.NoError(t, err)
+
+	_, err = client.ExecuteQuery(ctx, query)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already indexed")
+}
+
+func TestMemoryClient_InvalidQuery(t *testing.T) {
+	client := newTestMemoryClient(t)
+
+	_, err := client.ExecuteQuery(context.Background(), GraphQuery{Query: "MATCH (n RETURN n"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query execution failed")
+}
+
+func TestMemoryClient_InvalidParameter(t *testing.T) {
+	client := newTestMemoryClient(t)
+
+	_, err := client.ExecuteQuery(context.Background(), GraphQuery{
+		Query:      "RETURN $value",
+		Parameters: map[string]interface{}{"value": math.NaN()},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid query parameters")
+}
+
+func TestMemoryClient_CanceledContext(t *testing.T) {
+	client := newTestMemoryClient(t)
+	ctx := context.Background()
+
+	_, err := client.ExecuteQuery(ctx, GraphQuery{Query: "UNWIND range(1, 5000) AS i CREATE (:Pod {i: i})"})
+	require.NoError(t, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.ExecuteQuery(canceled, GraphQuery{Query: "MATCH (a:Pod), (b:Pod) RETURN count(*)"})
+	require.Error(t, err)
+}
+
+func TestMemoryClient_NotConnected(t *testing.T) {
+	config := DefaultClientConfig()
+	config.Backend = BackendMemory
+	client := NewClient(config)
+
+	_, err := client.ExecuteQuery(context.Background(), GraphQuery{Query: "RETURN 1"})
+	require.Error(t, err)
+	assert.Error(t, client.Ping(context.Background()))
+
+	require.NoError(t, client.Connect(context.Background()))
+	assert.NoError(t, client.Ping(context.Background()))
+}
+
+func TestMemoryClient_DeleteGraph(t *testing.T) {
+	client := newTestMemoryClient(t)
+	ctx := context.Background()
+
+	_, err := client.ExecuteQuery(ctx, GraphQuery{Query: "CREATE (:Pod)"})
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeleteGraph(ctx))
+	exists, err := client.GraphExists(ctx, DefaultClientConfig().GraphName)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Reads see an empty graph, writes recreate it
+	assert.Equal(t, int64(0), countNodes(t, client))
+	_, err = client.ExecuteQuery(ctx, GraphQuery{Query: "CREATE (:Pod)"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), countNodes(t, client))
+}
+
+func TestMemoryClient_FulltextQuery(t *testing.T) {
+	client := newTestMemoryClient(t)
+	ctx := context.Background()
+
+	_, err := client.ExecuteQuery(ctx, GraphQuery{Query: `
+		CREATE (:K8sEvent {id: 'a', message: 'Back-off restarting failed container'})
+		CREATE (:K8sEvent {id: 'b', message: 'Failed to pull image: back-off pulling image, back-off'})
+		CREATE (:K8sEvent {id: 'c', message: 'Started container'})
+	`})
+	require.NoError(t, err)
+
+	query := GraphQuery{
+		Query: `CALL db.idx.fulltext.queryNodes('K8sEvent', $query) YIELD node, score
+			RETURN node.id, score`,
+		Parameters: map[string]interface{}{"query": "back*"},
+	}
+	_, err = client.ExecuteQuery(ctx, query)
+	require.Error(t, err, "querying requires a full-text index")
+
+	require.NoError(t, client.InitializeSchema(ctx))
+	_, err = client.ExecuteQuery(ctx, GraphQuery{Query: "CREATE FULLTEXT INDEX FOR (n:K8sEvent) ON (n.message)"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already indexed")
+
+	result, err := client.ExecuteQuery(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 2)
+	// The message with more occurrences ranks first
+	assert.Equal(t, "b", result.Rows[0][0])
+	assert.Equal(t, "a", result.Rows[1][0])
+	assert.Greater(t, result.Rows[0][1], result.Rows[1][1])
+
+	query.Parameters["query"] = `"failed container"`
+	result, err = client.ExecuteQuery(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, "a", result.Rows[0][0])
+}
@@ -0,0 +1,1175 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moolen/spectre/internal/graph/cypher"
+)
+
+// bindings maps variable names to values for one row of a query
+type bindings map[string]interface{}
+
+// with returns a copy of the row with name bound to value
+func (b bindings) with(name string, value interface{}) bindings {
+	row := make(bindings, len(b)+1)
+	for k, v := range b {
+		row[k] = v
+	}
+	row[name] = value
+	return row
+}
+
+// evaluator evaluates expressions against a row of bindings
+type evaluator struct {
+	ctx    context.Context
+	tx     *memTxn
+	params map[string]interface{}
+	// group holds the rows of the current group while a projection computes
+	// aggregates; it is nil everywhere else
+	group   []bindings
+	regexps map[string]*regexp.Regexp
+}
+
+func newEvaluator(ctx context.Context, tx *memTxn, params map[string]interface{}) *evaluator {
+	return &evaluator{ctx: ctx, tx: tx, params: params, regexps: make(map[string]*regexp.Regexp)}
+}
+
+// evalGroup evaluates an expression that may contain aggregates over the rows
+// of a group. Non-aggregated sub-expressions are evaluated against row, which
+// holds the grouping keys.
+func (ev *evaluator) evalGroup(e cypher.Expr, row bindings, group []bindings) (interface{}, error) {
+	if group == nil {
+		group = []bindings{}
+	}
+	ev.group = group
+	defer func() { ev.group = nil }()
+	return ev.eval(e, row)
+}
+
+func (ev *evaluator) eval(e cypher.Expr, row bindings) (interface{}, error) {
+	switch e := e.(type) {
+	case *cypher.Literal:
+		return e.Value, nil
+
+	case *cypher.Parameter:
+		value, ok := ev.params[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing parameter '%s'", e.Name)
+		}
+		return value, nil
+
+	case *cypher.Variable:
+		value, ok := row[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("'%s' not defined", e.Name)
+		}
+		return value, nil
+
+	case *cypher.Property:
+		target, err := ev.eval(e.Expr, row)
+		if err != nil {
+			return nil, err
+		}
+		return propertyOf(target, e.Key)
+
+	case *cypher.Index:
+		return ev.evalIndex(e, row)
+
+	case *cypher.Slice:
+		return ev.evalSlice(e, row)
+
+	case *cypher.ListLiteral:
+		list := make([]interface{}, len(e.Items))
+		for i, item := range e.Items {
+			value, err := ev.eval(item, row)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+
+	case *cypher.MapLiteral:
+		m := make(map[string]interface{}, len(e.Keys))
+		for i, key := range e.Keys {
+			value, err := ev.eval(e.Values[i], row)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+
+	case *cypher.FunctionCall:
+		if cypher.IsAggregate(e.Name) {
+			return ev.evalAggregate(e)
+		}
+		args := make([]interface{}, len(e.Args))
+		for i, arg := range e.Args {
+			value, err := ev.eval(arg, row)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		return callFunction(e.Name, args)
+
+	case *cypher.BinaryOp:
+		return ev.evalBinary(e, row)
+
+	case *cypher.UnaryOp:
+		value, err := ev.eval(e.Expr, row)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Op {
+		case "NOT":
+			if value == nil {
+				return nil, nil
+			}
+			b, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("type mismatch: expected Boolean but was %s", typeName(value))
+			}
+			return !b, nil
+		case "-":
+			switch v := value.(type) {
+			case nil:
+				return nil, nil
+			case int64:
+				return -v, nil
+			case float64:
+				return -v, nil
+			}
+			return nil, fmt.Errorf("type mismatch: expected Integer or Float but was %s", typeName(value))
+		}
+		return nil, fmt.Errorf("unsupported operator %s", e.Op)
+
+	case *cypher.IsNull:
+		value, err := ev.eval(e.Expr, row)
+		if err != nil {
+			return nil, err
+		}
+		return (value == nil) != e.Not, nil
+
+	case *cypher.HasLabels:
+		value, err := ev.eval(e.Expr, row)
+		if err != nil {
+			return nil, err
+		}
+		switch n := value.(type) {
+		case nil:
+			return nil, nil
+		case *memNode:
+			for _, label := range e.Labels {
+				if !n.hasLabel(label) {
+					return false, nil
+				}
+			}
+			return true, nil
+		}
+		return nil, fmt.Errorf("type mismatch: expected Node but was %s", typeName(value))
+
+	case *cypher.Case:
+		return ev.evalCase(e, row)
+
+	case *cypher.ListComprehension:
+		return ev.evalComprehension(e, row)
+
+	case *cypher.Quantifier:
+		return ev.evalQuantifier(e, row)
+
+	case *cypher.PatternPredicate:
+		found := false
+		err := ev.matchPart(e.Part, row, func(bindings) (bool, error) {
+			found = true
+			return false, nil
+		})
+		return found, err
+	}
+
+	return nil, fmt.Errorf("unsupported expression %T", e)
+}
+
+// evalPredicate evaluates a WHERE condition; null counts as false
+func (ev *evaluator) evalPredicate(e cypher.Expr, row bindings) (bool, error) {
+	if e == nil {
+		return true, nil
+	}
+	value, err := ev.eval(e, row)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+func propertyOf(target interface{}, key string) (interface{}, error) {
+	switch t := target.(type) {
+	case nil:
+		return nil, nil
+	case *memNode:
+		return t.props[key], nil
+	case *memEdge:
+		return t.props[key], nil
+	case map[string]interface{}:
+		return t[key], nil
+	}
+	return nil, fmt.Errorf("type mismatch: expected Map, Node or Edge but was %s", typeName(target))
+}
+
+func (ev *evaluator) evalIndex(e *cypher.Index, row bindings) (interface{}, error) {
+	target, err := ev.eval(e.Expr, row)
+	if err != nil {
+		return nil, err
+	}
+	index, err := ev.eval(e.Index, row)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil || index == nil {
+		return nil, nil
+	}
+
+	switch t := target.(type) {
+	case []interface{}:
+		i, ok := index.(int64)
+		if !ok {
+			return nil, fmt.Errorf("type mismatch: expected Integer but was %s", typeName(index))
+		}
+		if i < 0 {
+			i += int64(len(t))
+		}
+		if i < 0 || i >= int64(len(t)) {
+			return nil, nil
+		}
+		return t[i], nil
+	case map[string]interface{}, *memNode, *memEdge:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("type mismatch: expected String but was %s", typeName(index))
+		}
+		return propertyOf(t, key)
+	}
+	return nil, fmt.Errorf("type mismatch: expected List or Map but was %s", typeName(target))
+}
+
+func (ev *evaluator) evalSlice(e *cypher.Slice, row bindings) (interface{}, error) {
+	target, err := ev.eval(e.Expr, row)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, nil
+	}
+	list, ok := target.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("type mismatch: expected List but was %s", typeName(target))
+	}
+
+	bound := func(expr cypher.Expr, def int64) (int64, bool, error) {
+		if expr == nil {
+			return def, true, nil
+		}
+		value, err := ev.eval(expr, row)
+		if err != nil || value == nil {
+			return 0, false, err
+		}
+		i, ok := value.(int64)
+		if !ok {
+			return 0, false, fmt.Errorf("type mismatch: expected Integer but was %s", typeName(value))
+		}
+		if i < 0 {
+			i += int64(len(list))
+		}
+		return max(0, min(i, int64(len(list)))), true, nil
+	}
+
+	from, ok, err := bound(e.From, 0)
+	if err != nil || !ok {
+		return nil, err
+	}
+	to, ok, err := bound(e.To, int64(len(list)))
+	if err != nil || !ok {
+		return nil, err
+	}
+	if from >= to {
+		return []interface{}{}, nil
+	}
+	return append([]interface{}(nil), list[from:to]...), nil
+}
+
+func (ev *evaluator) evalBinary(e *cypher.BinaryOp, row bindings) (interface{}, error) {
+	left, err := ev.eval(e.Left, row)
+	if err != nil {
+		return nil, err
+	}
+
+	// AND and OR skip the right side when the left side decides the result
+	switch e.Op {
+	case "AND":
+		if left == false {
+			return false, nil
+		}
+	case "OR":
+		if left == true {
+			return true, nil
+		}
+	}
+
+	right, err := ev.eval(e.Right, row)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Op {
+	case "AND", "OR", "XOR":
+		return logicalOp(e.Op, left, right)
+	case "=":
+		eq, defined := valuesEqual(left, right)
+		if !defined {
+			return nil, nil
+		}
+		return eq, nil
+	case "<>":
+		eq, defined := valuesEqual(left, right)
+		if !defined {
+			return nil, nil
+		}
+		return !eq, nil
+	case "<", ">", "<=", ">=":
+		c, ok := compareValues(left, right)
+		if !ok {
+			return nil, nil
+		}
+		switch e.Op {
+		case "<":
+			return c < 0, nil
+		case ">":
+			return c > 0, nil
+		case "<=":
+			return c <= 0, nil
+		}
+		return c >= 0, nil
+	case "IN":
+		return inList(left, right)
+	case "=~", "STARTS WITH", "ENDS WITH", "CONTAINS":
+		return ev.stringOp(e.Op, left, right)
+	case "+":
+		return addValues(left, right)
+	case "-", "*", "/", "%", "^":
+		return arithmetic(e.Op, left, right)
+	}
+	return nil, fmt.Errorf("unsupported operator %s", e.Op)
+}
+
+func logicalOp(op string, left, right interface{}) (interface{}, error) {
+	for _, v := range []interface{}{left, right} {
+		if _, ok := v.(bool); v != nil && !ok {
+			return nil, fmt.Errorf("type mismatch: expected Boolean but was %s", typeName(v))
+		}
+	}
+
+	switch op {
+	case "AND":
+		if left == false || right == false {
+			return false, nil
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		return true, nil
+	case "OR":
+		if left == true || right == true {
+			return true, nil
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		return false, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	return left.(bool) != right.(bool), nil
+}
+
+func inList(value, list interface{}) (interface{}, error) {
+	if list == nil {
+		return nil, nil
+	}
+	items, ok := list.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("type mismatch: expected List but was %s", typeName(list))
+	}
+	undefined := false
+	for _, item := range items {
+		eq, defined := valuesEqual(value, item)
+		if eq {
+			return true, nil
+		}
+		undefined = undefined || !defined
+	}
+	if undefined {
+		return nil, nil
+	}
+	return false, nil
+}
+
+func (ev *evaluator) stringOp(op string, left, right interface{}) (interface{}, error) {
+	l, lok := left.(string)
+	r, rok := right.(string)
+	if !lok || !rok {
+		return nil, nil
+	}
+
+	switch op {
+	case "STARTS WITH":
+		return strings.HasPrefix(l, r), nil
+	case "ENDS WITH":
+		return strings.HasSuffix(l, r), nil
+	case "CONTAINS":
+		return strings.Contains(l, r), nil
+	}
+
+	// =~ must match the whole string
+	re, ok := ev.regexps[r]
+	if !ok {
+		var err error
+		if re, err = regexp.Compile("^(?:" + r + ")$"); err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", r, err)
+		}
+		ev.regexps[r] = re
+	}
+	return re.MatchString(l), nil
+}
+
+func addValues(left, right interface{}) (interface{}, error) {
+	if l, ok := left.([]interface{}); ok {
+		if r, ok := right.([]interface{}); ok {
+			return append(append([]interface{}(nil), l...), r...), nil
+		}
+		return append(append([]interface{}(nil), l...), right), nil
+	}
+	if r, ok := right.([]interface{}); ok {
+		return append([]interface{}{left}, r...), nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	_, lstr := left.(string)
+	_, rstr := right.(string)
+	if lstr || rstr {
+		l, err := toStringValue(left)
+		if err != nil {
+			return nil, err
+		}
+		r, err := toStringValue(right)
+		if err != nil {
+			return nil, err
+		}
+		return l.(string) + r.(string), nil
+	}
+	return arithmetic("+", left, right)
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if !lok || !rok {
+		bad := left
+		if lok {
+			bad = right
+		}
+		return nil, fmt.Errorf("type mismatch: expected Integer or Float but was %s", typeName(bad))
+	}
+
+	li, lint := left.(int64)
+	ri, rint := right.(int64)
+	if lint && rint && op != "^" {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		return lf / rf, nil
+	case "%":
+		return math.Mod(lf, rf), nil
+	case "^":
+		return math.Pow(lf, rf), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %s", op)
+}
+
+func (ev *evaluator) evalCase(e *cypher.Case, row bindings) (interface{}, error) {
+	var test interface{}
+	if e.Test != nil {
+		var err error
+		if test, err = ev.eval(e.Test, row); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, when := range e.Whens {
+		value, err := ev.eval(when.When, row)
+		if err != nil {
+			return nil, err
+		}
+		matched := truthy(value)
+		if e.Test != nil {
+			matched, _ = valuesEqual(test, value)
+		}
+		if matched {
+			return ev.eval(when.Then, row)
+		}
+	}
+
+	if e.Else == nil {
+		return nil, nil
+	}
+	return ev.eval(e.Else, row)
+}
+
+// iterate evaluates a list expression and calls fn with a row binding
+// variable to each element. A null list yields false.
+func (ev *evaluator) iterate(variable string, listExpr cypher.Expr, row bindings, fn func(item interface{}, scope bindings) error) (bool, error) {
+	value, err := ev.eval(listExpr, row)
+	if err != nil || value == nil {
+		return false, err
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return false, fmt.Errorf("type mismatch: expected List but was %s", typeName(value))
+	}
+	for _, item := range list {
+		if err := fn(item, row.with(variable, item)); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (ev *evaluator) evalComprehension(e *cypher.ListComprehension, row bindings) (interface{}, error) {
+	result := []interface{}{}
+	ok, err := ev.iterate(e.Variable, e.List, row, func(item interface{}, scope bindings) error {
+		keep, err := ev.evalPredicate(e.Where, scope)
+		if err != nil || !keep {
+			return err
+		}
+		if e.Projection != nil {
+			if item, err = ev.eval(e.Projection, scope); err != nil {
+				return err
+			}
+		}
+		result = append(result, item)
+		return nil
+	})
+	if err != nil || !ok {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (ev *evaluator) evalQuantifier(e *cypher.Quantifier, row bindings) (interface{}, error) {
+	var trues, falses, nulls int
+	ok, err := ev.iterate(e.Variable, e.List, row, func(_ interface{}, scope bindings) error {
+		value, err := ev.eval(e.Where, scope)
+		if err != nil {
+			return err
+		}
+		switch value {
+		case true:
+			trues++
+		case false:
+			falses++
+		default:
+			nulls++
+		}
+		return nil
+	})
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	switch e.Kind {
+	case "ALL":
+		if falses > 0 {
+			return false, nil
+		}
+		if nulls > 0 {
+			return nil, nil
+		}
+		return true, nil
+	case "ANY", "NONE":
+		var any interface{} = false
+		if trues > 0 {
+			any = true
+		} else if nulls > 0 {
+			any = nil
+		}
+		if e.Kind == "ANY" || any == nil {
+			return any, nil
+		}
+		return !any.(bool), nil
+	case "SINGLE":
+		if trues > 1 {
+			return false, nil
+		}
+		if nulls > 0 {
+			return nil, nil
+		}
+		return trues == 1, nil
+	}
+	return nil, fmt.Errorf("unsupported quantifier %s", e.Kind)
+}
+
+// evalAggregate computes an aggregating function over the current group
+func (ev *evaluator) evalAggregate(call *cypher.FunctionCall) (interface{}, error) {
+	if ev.group == nil {
+		return nil, fmt.Errorf("invalid use of aggregating function '%s'", call.Name)
+	}
+	if call.Star {
+		return int64(len(ev.group)), nil
+	}
+	if len(call.Args) != 1 {
+		return nil, fmt.Errorf("%s() expects 1 argument, got %d", call.Name, len(call.Args))
+	}
+
+	// Aggregates cannot be nested, so the argument is evaluated per row
+	group := ev.group
+	ev.group = nil
+	defer func() { ev.group = group }()
+
+	values := make([]interface{}, 0, len(group))
+	seen := make(map[string]bool)
+	for _, row := range group {
+		value, err := ev.eval(call.Args[0], row)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		if call.Distinct {
+			key := hashKey(value)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		values = append(values, value)
+	}
+
+	switch call.Name {
+	case "count":
+		return int64(len(values)), nil
+	case "collect":
+		return values, nil
+	case "sum", "avg":
+		var intSum int64
+		var floatSum float64
+		isFloat := false
+		for _, value := range values {
+			switch v := value.(type) {
+			case int64:
+				intSum += v
+				floatSum += float64(v)
+			case float64:
+				isFloat = true
+				floatSum += v
+			default:
+				return nil, fmt.Errorf("type mismatch: %s() expected Integer or Float but was %s", call.Name, typeName(value))
+			}
+		}
+		if call.Name == "avg" {
+			if len(values) == 0 {
+				return nil, nil
+			}
+			return floatSum / float64(len(values)), nil
+		}
+		if isFloat {
+			return floatSum, nil
+		}
+		return intSum, nil
+	case "min", "max":
+		var best interface{}
+		for _, value := range values {
+			if best == nil {
+				best = value
+				continue
+			}
+			c := orderValues(value, best)
+			if (call.Name == "min" && c < 0) || (call.Name == "max" && c > 0) {
+				best = value
+			}
+		}
+		return best, nil
+	}
+	return nil, fmt.Errorf("unknown aggregating function '%s'", call.Name)
+}
+
+// callFunction evaluates a scalar function
+func callFunction(name string, args []interface{}) (interface{}, error) {
+	fn, ok := scalarFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'", name)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("received %d arguments to function '%s'", len(args), name)
+	}
+	// Functions return null for a null first argument unless they handle
+	// nulls themselves
+	if !fn.nullable && len(args) > 0 && args[0] == nil {
+		return nil, nil
+	}
+	return fn.call(args)
+}
+
+type scalarFunction struct {
+	minArgs, maxArgs int // maxArgs -1 for variadic
+	nullable         bool
+	call             func(args []interface{}) (interface{}, error)
+}
+
+var scalarFunctions map[string]scalarFunction
+
+func init() {
+	scalarFunctions = map[string]scalarFunction{
+		"coalesce": {minArgs: 1, maxArgs: -1, nullable: true, call: func(args []interface{}) (interface{}, error) {
+			for _, arg := range args {
+				if arg != nil {
+					return arg, nil
+				}
+			}
+			return nil, nil
+		}},
+		"exists": {minArgs: 1, maxArgs: 1, nullable: true, call: func(args []interface{}) (interface{}, error) {
+			return args[0] != nil, nil
+		}},
+		"timestamp": {call: func([]interface{}) (interface{}, error) {
+			return time.Now().UnixMilli(), nil
+		}},
+
+		// Graph entities
+		"id": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case *memNode:
+				return int64(v.id), nil
+			case *memEdge:
+				return int64(v.id), nil
+			}
+			return nil, expected("Node or Edge", args[0])
+		}},
+		"labels": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			n, ok := args[0].(*memNode)
+			if !ok {
+				return nil, expected("Node", args[0])
+			}
+			labels := make([]interface{}, len(n.labels))
+			for i, label := range n.labels {
+				labels[i] = label
+			}
+			return labels, nil
+		}},
+		"type": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			e, ok := args[0].(*memEdge)
+			if !ok {
+				return nil, expected("Edge", args[0])
+			}
+			return e.relType, nil
+		}},
+		"properties": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case *memNode:
+				return copyProperties(v.props), nil
+			case *memEdge:
+				return copyProperties(v.props), nil
+			case map[string]interface{}:
+				return v, nil
+			}
+			return nil, expected("Map, Node or Edge", args[0])
+		}},
+		"keys": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			var props map[string]interface{}
+			switch v := args[0].(type) {
+			case *memNode:
+				props = v.props
+			case *memEdge:
+				props = v.props
+			case map[string]interface{}:
+				props = v
+			default:
+				return nil, expected("Map, Node or Edge", args[0])
+			}
+			keys := make([]interface{}, 0, len(props))
+			for key := range props {
+				keys = append(keys, key)
+			}
+			return keys, nil
+		}},
+		"startnode": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			e, ok := args[0].(*memEdge)
+			if !ok {
+				return nil, expected("Edge", args[0])
+			}
+			return e.src, nil
+		}},
+		"endnode": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			e, ok := args[0].(*memEdge)
+			if !ok {
+				return nil, expected("Edge", args[0])
+			}
+			return e.dst, nil
+		}},
+		"nodes": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			p, ok := args[0].(*memPath)
+			if !ok {
+				return nil, expected("Path", args[0])
+			}
+			nodes := make([]interface{}, len(p.nodes))
+			for i, n := range p.nodes {
+				nodes[i] = n
+			}
+			return nodes, nil
+		}},
+		"relationships": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			p, ok := args[0].(*memPath)
+			if !ok {
+				return nil, expected("Path", args[0])
+			}
+			edges := make([]interface{}, len(p.edges))
+			for i, e := range p.edges {
+				edges[i] = e
+			}
+			return edges, nil
+		}},
+		"length": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			if p, ok := args[0].(*memPath); ok {
+				return int64(len(p.edges)), nil
+			}
+			return sizeOf(args[0])
+		}},
+
+		// Lists
+		"size": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			return sizeOf(args[0])
+		}},
+		"head": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			list, ok := args[0].([]interface{})
+			if !ok {
+				return nil, expected("List", args[0])
+			}
+			if len(list) == 0 {
+				return nil, nil
+			}
+			return list[0], nil
+		}},
+		"last": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			list, ok := args[0].([]interface{})
+			if !ok {
+				return nil, expected("List", args[0])
+			}
+			if len(list) == 0 {
+				return nil, nil
+			}
+			return list[len(list)-1], nil
+		}},
+		"tail": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			list, ok := args[0].([]interface{})
+			if !ok {
+				return nil, expected("List", args[0])
+			}
+			if len(list) == 0 {
+				return []interface{}{}, nil
+			}
+			return append([]interface{}(nil), list[1:]...), nil
+		}},
+		"reverse": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case []interface{}:
+				out := make([]interface{}, len(v))
+				for i, item := range v {
+					out[len(v)-1-i] = item
+				}
+				return out, nil
+			case string:
+				runes := []rune(v)
+				for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+					runes[i], runes[j] = runes[j], runes[i]
+				}
+				return string(runes), nil
+			}
+			return nil, expected("List or String", args[0])
+		}},
+		"range": {minArgs: 2, maxArgs: 3, call: func(args []interface{}) (interface{}, error) {
+			bounds := make([]int64, 3)
+			bounds[2] = 1
+			for i, arg := range args {
+				v, ok := arg.(int64)
+				if !ok {
+					return nil, expected("Integer", arg)
+				}
+				bounds[i] = v
+			}
+			start, end, step := bounds[0], bounds[1], bounds[2]
+			if step == 0 {
+				return nil, fmt.Errorf("range() step cannot be zero")
+			}
+			list := []interface{}{}
+			for i := start; (step > 0 && i <= end) || (step < 0 && i >= end); i += step {
+				list = append(list, i)
+			}
+			return list, nil
+		}},
+
+		// Strings
+		"tolower":  stringFunction(strings.ToLower),
+		"toupper":  stringFunction(strings.ToUpper),
+		"trim":     stringFunction(strings.TrimSpace),
+		"ltrim":    stringFunction(func(s string) string { return strings.TrimLeft(s, " \t\n\r") }),
+		"rtrim":    stringFunction(func(s string) string { return strings.TrimRight(s, " \t\n\r") }),
+		"tostring": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) { return toStringValue(args[0]) }},
+		"replace": {minArgs: 3, maxArgs: 3, call: func(args []interface{}) (interface{}, error) {
+			s, search, replacement, err := threeStrings(args)
+			if err != nil || args[1] == nil || args[2] == nil {
+				return nil, err
+			}
+			return strings.ReplaceAll(s, search, replacement), nil
+		}},
+		"split": {minArgs: 2, maxArgs: 2, call: func(args []interface{}) (interface{}, error) {
+			s, ok := args[0].(string)
+			sep, sepOK := args[1].(string)
+			if args[1] == nil {
+				return nil, nil
+			}
+			if !ok || !sepOK {
+				return nil, expected("String", args[0])
+			}
+			parts := strings.Split(s, sep)
+			list := make([]interface{}, len(parts))
+			for i, part := range parts {
+				list[i] = part
+			}
+			return list, nil
+		}},
+		"substring": {minArgs: 2, maxArgs: 3, call: func(args []interface{}) (interface{}, error) {
+			s, ok := args[0].(string)
+			if !ok {
+				return nil, expected("String", args[0])
+			}
+			runes := []rune(s)
+			start, ok := args[1].(int64)
+			if !ok || start < 0 {
+				return nil, fmt.Errorf("substring() start must be a non-negative Integer")
+			}
+			start = min(start, int64(len(runes)))
+			end := int64(len(runes))
+			if len(args) == 3 {
+				length, ok := args[2].(int64)
+				if !ok || length < 0 {
+					return nil, fmt.Errorf("substring() length must be a non-negative Integer")
+				}
+				end = min(end, start+length)
+			}
+			return string(runes[start:end]), nil
+		}},
+		"left": {minArgs: 2, maxArgs: 2, call: func(args []interface{}) (interface{}, error) {
+			s, ok := args[0].(string)
+			n, nok := args[1].(int64)
+			if !ok || !nok || n < 0 {
+				return nil, fmt.Errorf("left() expects a String and a non-negative Integer")
+			}
+			runes := []rune(s)
+			return string(runes[:min(n, int64(len(runes)))]), nil
+		}},
+		"right": {minArgs: 2, maxArgs: 2, call: func(args []interface{}) (interface{}, error) {
+			s, ok := args[0].(string)
+			n, nok := args[1].(int64)
+			if !ok || !nok || n < 0 {
+				return nil, fmt.Errorf("right() expects a String and a non-negative Integer")
+			}
+			runes := []rune(s)
+			return string(runes[int64(len(runes))-min(n, int64(len(runes))):]), nil
+		}},
+
+		// Conversions
+		"tointeger": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case int64:
+				return v, nil
+			case float64:
+				return int64(v), nil
+			case string:
+				if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+					return i, nil
+				}
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					return int64(f), nil
+				}
+				return nil, nil
+			}
+			return nil, expected("Integer, Float or String", args[0])
+		}},
+		"tofloat": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case int64:
+				return float64(v), nil
+			case float64:
+				return v, nil
+			case string:
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					return f, nil
+				}
+				return nil, nil
+			}
+			return nil, expected("Integer, Float or String", args[0])
+		}},
+		"toboolean": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case bool:
+				return v, nil
+			case string:
+				switch strings.ToLower(strings.TrimSpace(v)) {
+				case "true":
+					return true, nil
+				case "false":
+					return false, nil
+				}
+				return nil, nil
+			}
+			return nil, expected("Boolean or String", args[0])
+		}},
+
+		// Math
+		"abs": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			switch v := args[0].(type) {
+			case int64:
+				if v < 0 {
+					return -v, nil
+				}
+				return v, nil
+			case float64:
+				return math.Abs(v), nil
+			}
+			return nil, expected("Integer or Float", args[0])
+		}},
+		"ceil":  floatFunction(math.Ceil, true),
+		"floor": floatFunction(math.Floor, true),
+		"round": floatFunction(math.Round, true),
+		"sqrt":  floatFunction(math.Sqrt, false),
+		"sign": {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+			f, ok := toFloat(args[0])
+			if !ok {
+				return nil, expected("Integer or Float", args[0])
+			}
+			switch {
+			case f > 0:
+				return int64(1), nil
+			case f < 0:
+				return int64(-1), nil
+			}
+			return int64(0), nil
+		}},
+	}
+}
+
+func stringFunction(fn func(string) string) scalarFunction {
+	return scalarFunction{minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, expected("String", args[0])
+		}
+		return fn(s), nil
+	}}
+}
+
+// floatFunction wraps a math function. Rounding functions return integer
+// arguments unchanged.
+func floatFunction(fn func(float64) float64, keepIntegers bool) scalarFunction {
+	return scalarFunction{minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) {
+		if i, ok := args[0].(int64); ok && keepIntegers {
+			return i, nil
+		}
+		f, ok := toFloat(args[0])
+		if !ok {
+			return nil, expected("Integer or Float", args[0])
+		}
+		return fn(f), nil
+	}}
+}
+
+func threeStrings(args []interface{}) (string, string, string, error) {
+	var out [3]string
+	for i, arg := range args {
+		if arg == nil {
+			continue
+		}
+		s, ok := arg.(string)
+		if !ok {
+			return "", "", "", expected("String", arg)
+		}
+		out[i] = s
+	}
+	return out[0], out[1], out[2], nil
+}
+
+func sizeOf(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return int64(len(v)), nil
+	case string:
+		return int64(utf8.RuneCountInString(v)), nil
+	}
+	return nil, expected("List or String", v)
+}
+
+func toStringValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return nil, expected("String, Integer, Float or Boolean", v)
+}
+
+func expected(want string, got interface{}) error {
+	return fmt.Errorf("type mismatch: expected %s but was %s", want, typeName(got))
+}
@@ -0,0 +1,1090 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/moolen/spectre/internal/graph/cypher"
+)
+
+// cancelCheckInterval is how many candidates the matcher visits between
+// context cancellation checks
+const cancelCheckInterval = 1024
+
+// execute runs the clauses of a query one after another over a stream of
+// rows and returns the RETURN columns and values
+func (ev *evaluator) execute(q *cypher.Query) ([]string, [][]interface{}, error) {
+	rows := []bindings{{}}
+
+	for i, clause := range q.Clauses {
+		if err := ev.ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		var err error
+		switch c := clause.(type) {
+		case *cypher.Match:
+			rows, err = ev.execMatch(c, rows)
+		case *cypher.Unwind:
+			rows, err = ev.execUnwind(c, rows)
+		case *cypher.With:
+			if rows, _, err = ev.project(rows, &c.Projection); err == nil && c.Where != nil {
+				rows, err = ev.filter(rows, c.Where)
+			}
+		case *cypher.Return:
+			if i != len(q.Clauses)-1 {
+				return nil, nil, fmt.Errorf("RETURN must be the last clause")
+			}
+			var columns []string
+			if rows, columns, err = ev.project(rows, &c.Projection); err != nil {
+				return nil, nil, err
+			}
+			return columns, resultRows(rows, columns), nil
+		case *cypher.Create:
+			rows, err = ev.execCreate(c, rows)
+		case *cypher.Merge:
+			rows, err = ev.execMerge(c, rows)
+		case *cypher.Set:
+			err = ev.forEach(rows, func(row bindings) error { return ev.applySet(c.Items, row) })
+		case *cypher.Remove:
+			err = ev.forEach(rows, func(row bindings) error { return ev.applyRemove(c.Items, row) })
+		case *cypher.Delete:
+			err = ev.forEach(rows, func(row bindings) error { return ev.applyDelete(c, row) })
+		case *cypher.Call:
+			rows, err = ev.execCall(c, rows)
+		case *cypher.CreateIndex:
+			for _, property := range c.Properties {
+				if c.Fulltext {
+					err = ev.tx.createFulltextIndex(c.Label, property)
+				} else {
+					err = ev.tx.createIndex(c.Label, property)
+				}
+				if err != nil {
+					break
+				}
+			}
+		default:
+			err = fmt.Errorf("unsupported clause %T", clause)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return nil, nil, nil
+}
+
+func (ev *evaluator) forEach(rows []bindings, fn func(bindings) error) error {
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ev *evaluator) filter(rows []bindings, where cypher.Expr) ([]bindings, error) {
+	kept := rows[:0]
+	for _, row := range rows {
+		ok, err := ev.evalPredicate(where, row)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			kept = append(kept, row)
+		}
+	}
+	return kept, nil
+}
+
+func resultRows(rows []bindings, columns []string) [][]interface{} {
+	out := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values := make([]interface{}, len(columns))
+		for j, column := range columns {
+			values[j] = resultValue(row[column])
+		}
+		out[i] = values
+	}
+	return out
+}
+
+// Reading clauses
+
+func (ev *evaluator) execMatch(c *cypher.Match, rows []bindings) ([]bindings, error) {
+	var out []bindings
+	for _, row := range rows {
+		matched := false
+		err := ev.matchParts(c.Pattern, row, make(map[*memEdge]bool), func(match bindings) (bool, error) {
+			ok, err := ev.evalPredicate(c.Where, match)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched = true
+				out = append(out, match)
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// OPTIONAL MATCH binds the new variables to null instead of
+		// dropping the row
+		if c.Optional && !matched {
+			nulls := mergeBindings(row, nil)
+			for _, name := range patternVariables(c.Pattern) {
+				if _, bound := row[name]; !bound {
+					nulls[name] = nil
+				}
+			}
+			out = append(out, nulls)
+		}
+	}
+	return out, nil
+}
+
+// patternVariables lists the variables a pattern may bind
+func patternVariables(parts []*cypher.PatternPart) []string {
+	var names []string
+	for _, part := range parts {
+		if part.PathVariable != "" {
+			names = append(names, part.PathVariable)
+		}
+		for _, node := range part.Nodes {
+			if node.Variable != "" {
+				names = append(names, node.Variable)
+			}
+		}
+		for _, rel := range part.Relationships {
+			if rel.Variable != "" {
+				names = append(names, rel.Variable)
+			}
+		}
+	}
+	return names
+}
+
+func (ev *evaluator) execUnwind(c *cypher.Unwind, rows []bindings) ([]bindings, error) {
+	var out []bindings
+	for _, row := range rows {
+		value, err := ev.eval(c.Expr, row)
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			for _, item := range v {
+				out = append(out, row.with(c.Alias, item))
+			}
+		default:
+			out = append(out, row.with(c.Alias, v))
+		}
+	}
+	return out, nil
+}
+
+// projected is a row produced by a projection, together with the input row
+// (or group) it was computed from so ORDER BY can see input variables
+type projected struct {
+	row    bindings
+	source bindings
+	group  []bindings
+}
+
+// project evaluates a WITH or RETURN projection and returns the new rows and
+// their column names
+func (ev *evaluator) project(rows []bindings, p *cypher.Projection) ([]bindings, []string, error) {
+	items := p.Items
+	if p.Star && len(rows) > 0 {
+		var names []string
+		for name := range rows[0] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		star := make([]cypher.ProjectionItem, 0, len(names)+len(items))
+		for _, name := range names {
+			star = append(star, cypher.ProjectionItem{Expr: &cypher.Variable{Name: name}, Alias: name})
+		}
+		items = append(star, items...)
+	}
+
+	columns := make([]string, len(items))
+	aggregating := false
+	for i, item := range items {
+		columns[i] = item.Alias
+		aggregating = aggregating || cypher.ContainsAggregate(item.Expr)
+	}
+
+	var out []projected
+	var err error
+	if aggregating {
+		out, err = ev.projectGroups(rows, items)
+	} else {
+		out, err = ev.projectRows(rows, items)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.Distinct {
+		seen := make(map[string]bool)
+		distinct := out[:0]
+		for _, r := range out {
+			values := make([]interface{}, len(columns))
+			for i, column := range columns {
+				values[i] = r.row[column]
+			}
+			key := hashKey(values)
+			if !seen[key] {
+				seen[key] = true
+				distinct = append(distinct, r)
+			}
+		}
+		out = distinct
+	}
+
+	if len(p.OrderBy) > 0 {
+		if out, err = ev.orderRows(out, p.OrderBy, aggregating); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	skip, err := ev.evalCount(p.Skip, "SKIP")
+	if err != nil {
+		return nil, nil, err
+	}
+	limit, err := ev.evalCount(p.Limit, "LIMIT")
+	if err != nil {
+		return nil, nil, err
+	}
+	if skip > 0 {
+		out = out[min(skip, len(out)):]
+	}
+	if limit >= 0 && limit < len(out) {
+		out = out[:limit]
+	}
+
+	result := make([]bindings, len(out))
+	for i, r := range out {
+		result[i] = r.row
+	}
+	return result, columns, nil
+}
+
+func (ev *evaluator) projectRows(rows []bindings, items []cypher.ProjectionItem) ([]projected, error) {
+	out := make([]projected, 0, len(rows))
+	for _, row := range rows {
+		values := make(bindings, len(items))
+		for _, item := range items {
+			value, err := ev.eval(item.Expr, row)
+			if err != nil {
+				return nil, err
+			}
+			values[item.Alias] = value
+		}
+		out = append(out, projected{row: values, source: row})
+	}
+	return out, nil
+}
+
+// projectGroups groups rows by the non-aggregated items and computes the
+// aggregates per group. Groups keep the order in which they first appear.
+func (ev *evaluator) projectGroups(rows []bindings, items []cypher.ProjectionItem) ([]projected, error) {
+	var keyItems, aggItems []cypher.ProjectionItem
+	for _, item := range items {
+		if cypher.ContainsAggregate(item.Expr) {
+			aggItems = append(aggItems, item)
+		} else {
+			keyItems = append(keyItems, item)
+		}
+	}
+
+	type group struct {
+		keys bindings
+		rows []bindings
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+
+	for _, row := range rows {
+		keys := make(bindings, len(keyItems))
+		values := make([]interface{}, len(keyItems))
+		for i, item := range keyItems {
+			value, err := ev.eval(item.Expr, row)
+			if err != nil {
+				return nil, err
+			}
+			keys[item.Alias] = value
+			values[i] = value
+		}
+		key := hashKey(values)
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{keys: keys}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.rows = append(g.rows, row)
+	}
+
+	// Aggregating without grouping keys always yields one row, e.g. a count
+	// of zero
+	if len(groups) == 0 && len(keyItems) == 0 {
+		groups = append(groups, &group{keys: bindings{}})
+	}
+
+	out := make([]projected, 0, len(groups))
+	for _, g := range groups {
+		// Aggregates see the input variables of the group's first row
+		// overlaid with the grouping keys
+		source := bindings{}
+		if len(g.rows) > 0 {
+			source = g.rows[0]
+		}
+		scope := mergeBindings(source, g.keys)
+
+		values := make(bindings, len(items))
+		for alias, value := range g.keys {
+			values[alias] = value
+		}
+		for _, item := range aggItems {
+			value, err := ev.evalGroup(item.Expr, scope, g.rows)
+			if err != nil {
+				return nil, err
+			}
+			values[item.Alias] = value
+		}
+		out = append(out, projected{row: values, source: source, group: g.rows})
+	}
+	return out, nil
+}
+
+func (ev *evaluator) orderRows(rows []projected, orderBy []cypher.SortItem, aggregating bool) ([]projected, error) {
+	keys := make([][]interface{}, len(rows))
+	for i, r := range rows {
+		scope := mergeBindings(r.source, r.row)
+		keys[i] = make([]interface{}, len(orderBy))
+		for j, item := range orderBy {
+			var value interface{}
+			var err error
+			if aggregating && cypher.ContainsAggregate(item.Expr) {
+				value, err = ev.evalGroup(item.Expr, scope, r.group)
+			} else {
+				value, err = ev.eval(item.Expr, scope)
+			}
+			if err != nil {
+				return nil, err
+			}
+			keys[i][j] = value
+		}
+	}
+
+	index := make([]int, len(rows))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(a, b int) bool {
+		for j, item := range orderBy {
+			c := orderValues(keys[index[a]][j], keys[index[b]][j])
+			if item.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	sorted := make([]projected, len(rows))
+	for i, from := range index {
+		sorted[i] = rows[from]
+	}
+	return sorted, nil
+}
+
+// evalCount evaluates a SKIP or LIMIT expression; -1 means absent
+func (ev *evaluator) evalCount(e cypher.Expr, clause string) (int, error) {
+	if e == nil {
+		return -1, nil
+	}
+	value, err := ev.eval(e, bindings{})
+	if err != nil {
+		return 0, err
+	}
+	n, ok := toInt(value)
+	if !ok || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %v", clause, value)
+	}
+	return int(n), nil
+}
+
+func mergeBindings(base, overlay bindings) bindings {
+	merged := make(bindings, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Pattern matching
+
+// matchPart calls fn for every match of a single pattern part. fn returns
+// false to stop matching.
+func (ev *evaluator) matchPart(part *cypher.PatternPart, row bindings, fn func(bindings) (bool, error)) error {
+	return ev.matchParts([]*cypher.PatternPart{part}, row, make(map[*memEdge]bool), fn)
+}
+
+// matchParts matches comma-separated pattern parts. Relationships are unique
+// across all parts of a clause, as in Cypher.
+func (ev *evaluator) matchParts(parts []*cypher.PatternPart, row bindings, used map[*memEdge]bool, fn func(bindings) (bool, error)) error {
+	_, err := ev.matchPartsFrom(parts, row, used, fn)
+	return err
+}
+
+func (ev *evaluator) matchPartsFrom(parts []*cypher.PatternPart, row bindings, used map[*memEdge]bool, fn func(bindings) (bool, error)) (bool, error) {
+	if len(parts) == 0 {
+		return fn(row)
+	}
+	m := &patternMatcher{
+		ev:        ev,
+		part:      parts[0],
+		row:       mergeBindings(row, nil),
+		nodes:     make([]*memNode, len(parts[0].Nodes)),
+		boundHere: make([]bool, len(parts[0].Nodes)),
+		hops:      make([][]*memEdge, len(parts[0].Relationships)),
+		used:      used,
+		emit: func(match bindings) (bool, error) {
+			return ev.matchPartsFrom(parts[1:], match, used, fn)
+		},
+	}
+	return m.run()
+}
+
+// patternMatcher finds the matches of one pattern part by picking a start
+// node and expanding relationships to the right and then to the left of it.
+// It binds variables in its working row and unbinds them on backtracking.
+type patternMatcher struct {
+	ev        *evaluator
+	part      *cypher.PatternPart
+	row       bindings
+	nodes     []*memNode
+	boundHere []bool
+	hops      [][]*memEdge
+	used      map[*memEdge]bool
+	emit      func(bindings) (bool, error)
+	visited   int
+}
+
+func (m *patternMatcher) run() (bool, error) {
+	start, candidates, err := m.startCandidates()
+	if err != nil {
+		return false, err
+	}
+	props, err := m.patternProps(m.part.Nodes[start].Properties)
+	if err != nil {
+		return false, err
+	}
+
+	for _, n := range candidates {
+		if err := m.tick(); err != nil {
+			return false, err
+		}
+		if !m.bindNode(start, n, props) {
+			continue
+		}
+		cont, err := m.extend(start, start)
+		m.unbindNode(start)
+		if err != nil || !cont {
+			return cont, err
+		}
+	}
+	return true, nil
+}
+
+func (m *patternMatcher) tick() error {
+	m.visited++
+	if m.visited%cancelCheckInterval == 0 {
+		return m.ev.ctx.Err()
+	}
+	return nil
+}
+
+// startCandidates picks the cheapest node of the pattern to start from: a
+// bound variable, then an indexed property lookup, then the smallest label
+func (m *patternMatcher) startCandidates() (int, []*memNode, error) {
+	g := m.ev.tx.graph
+
+	for i, node := range m.part.Nodes {
+		if node.Variable == "" {
+			continue
+		}
+		if value, ok := m.row[node.Variable]; ok {
+			switch v := value.(type) {
+			case nil:
+				return i, nil, nil
+			case *memNode:
+				return i, []*memNode{v}, nil
+			}
+			return 0, nil, fmt.Errorf("variable '%s' is a %s, not a node", node.Variable, typeName(value))
+		}
+	}
+
+	best, bestCandidates := -1, []*memNode(nil)
+	for i, node := range m.part.Nodes {
+		if node.Properties != nil && len(node.Labels) > 0 {
+			// Properties may reference variables bound later in the pattern,
+			// in which case the node cannot use an index
+			if props, err := m.patternProps(node.Properties); err == nil {
+				for _, label := range node.Labels {
+					for key, value := range props {
+						if candidates, ok := g.indexLookup(label, key, value); ok {
+							return i, sortedByID(candidates), nil
+						}
+					}
+				}
+			}
+		}
+		for _, label := range node.Labels {
+			candidates := g.scan(label)
+			if best < 0 || len(candidates) < len(bestCandidates) {
+				best, bestCandidates = i, candidates
+			}
+		}
+	}
+	if best >= 0 {
+		return best, bestCandidates, nil
+	}
+	return 0, g.scan(""), nil
+}
+
+func sortedByID(nodes []*memNode) []*memNode {
+	if len(nodes) < 2 {
+		return nodes
+	}
+	sorted := append([]*memNode(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].id < sorted[j].id })
+	return sorted
+}
+
+// patternProps evaluates the property map of a node or relationship pattern
+func (m *patternMatcher) patternProps(e cypher.Expr) (map[string]interface{}, error) {
+	return m.ev.patternProps(e, m.row)
+}
+
+func (ev *evaluator) patternProps(e cypher.Expr, row bindings) (map[string]interface{}, error) {
+	if e == nil {
+		return nil, nil
+	}
+	value, err := ev.eval(e, row)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return v, nil
+	}
+	return nil, fmt.Errorf("type mismatch: expected Map but was %s", typeName(value))
+}
+
+func propertiesMatch(actual, expected map[string]interface{}) bool {
+	for key, want := range expected {
+		if eq, _ := valuesEqual(actual[key], want); !eq {
+			return false
+		}
+	}
+	return true
+}
+
+// bindNode checks n against the node pattern at position i and binds it
+func (m *patternMatcher) bindNode(i int, n *memNode, props map[string]interface{}) bool {
+	if n == nil || n.deleted {
+		return false
+	}
+	pattern := m.part.Nodes[i]
+	for _, label := range pattern.Labels {
+		if !n.hasLabel(label) {
+			return false
+		}
+	}
+	if !propertiesMatch(n.props, props) {
+		return false
+	}
+	if pattern.Variable != "" {
+		if bound, ok := m.row[pattern.Variable]; ok {
+			if bound != n {
+				return false
+			}
+		} else {
+			m.row[pattern.Variable] = n
+			m.boundHere[i] = true
+		}
+	}
+	m.nodes[i] = n
+	return true
+}
+
+func (m *patternMatcher) unbindNode(i int) {
+	if m.boundHere[i] {
+		delete(m.row, m.part.Nodes[i].Variable)
+		m.boundHere[i] = false
+	}
+	m.nodes[i] = nil
+}
+
+// extend matches the relationships outside the matched span [lo, hi] of
+// nodes, first to the right and then to the left
+func (m *patternMatcher) extend(lo, hi int) (bool, error) {
+	if hi < len(m.part.Nodes)-1 {
+		return m.traverse(hi, true, func() (bool, error) { return m.extend(lo, hi+1) })
+	}
+	if lo > 0 {
+		return m.traverse(lo-1, false, func() (bool, error) { return m.extend(lo-1, hi) })
+	}
+	return m.finish()
+}
+
+func (m *patternMatcher) finish() (bool, error) {
+	if m.part.PathVariable == "" {
+		return m.emit(mergeBindings(m.row, nil))
+	}
+	path := &memPath{nodes: []*memNode{m.nodes[0]}}
+	current := m.nodes[0]
+	for _, hop := range m.hops {
+		for _, e := range hop {
+			current = otherNode(e, current)
+			path.nodes = append(path.nodes, current)
+			path.edges = append(path.edges, e)
+		}
+	}
+	return m.emit(m.row.with(m.part.PathVariable, path))
+}
+
+func otherNode(e *memEdge, n *memNode) *memNode {
+	if e.src == n {
+		return e.dst
+	}
+	return e.src
+}
+
+// traverse matches relationship r, going from node r to node r+1 when
+// forward is set and from node r+1 to node r otherwise
+func (m *patternMatcher) traverse(r int, forward bool, next func() (bool, error)) (bool, error) {
+	rel := m.part.Relationships[r]
+	from, target := r, r+1
+	if !forward {
+		from, target = r+1, r
+	}
+
+	relProps, err := m.patternProps(rel.Properties)
+	if err != nil {
+		return false, err
+	}
+	nodeProps, err := m.patternProps(m.part.Nodes[target].Properties)
+	if err != nil {
+		return false, err
+	}
+
+	if rel.VarLength {
+		if rel.Variable != "" {
+			if _, bound := m.row[rel.Variable]; bound {
+				return false, fmt.Errorf("variable-length relationship '%s' is already bound", rel.Variable)
+			}
+		}
+		return m.expand(r, rel, forward, m.nodes[from], target, nil, relProps, nodeProps, next)
+	}
+
+	var bound *memEdge
+	if rel.Variable != "" {
+		if value, ok := m.row[rel.Variable]; ok {
+			e, isEdge := value.(*memEdge)
+			if !isEdge {
+				return true, nil
+			}
+			bound = e
+		}
+	}
+
+	for _, step := range m.steps(m.nodes[from], rel, forward, relProps) {
+		if bound != nil && step.edge != bound {
+			continue
+		}
+		if err := m.tick(); err != nil {
+			return false, err
+		}
+		if !m.bindNode(target, step.node, nodeProps) {
+			continue
+		}
+		m.used[step.edge] = true
+		m.hops[r] = []*memEdge{step.edge}
+		if rel.Variable != "" && bound == nil {
+			m.row[rel.Variable] = step.edge
+		}
+
+		cont, err := next()
+
+		if rel.Variable != "" && bound == nil {
+			delete(m.row, rel.Variable)
+		}
+		m.hops[r] = nil
+		delete(m.used, step.edge)
+		m.unbindNode(target)
+		if err != nil || !cont {
+			return cont, err
+		}
+	}
+	return true, nil
+}
+
+// expand matches a variable-length relationship with a depth-first search
+func (m *patternMatcher) expand(r int, rel *cypher.RelationshipPattern, forward bool, current *memNode, target int, path []*memEdge, relProps, nodeProps map[string]interface{}, next func() (bool, error)) (bool, error) {
+	if len(path) >= rel.MinHops && m.bindNode(target, current, nodeProps) {
+		m.hops[r] = path
+		if rel.Variable != "" {
+			edges := make([]interface{}, len(path))
+			for i, e := range path {
+				edges[i] = e
+			}
+			m.row[rel.Variable] = edges
+		}
+
+		cont, err := next()
+
+		if rel.Variable != "" {
+			delete(m.row, rel.Variable)
+		}
+		m.hops[r] = nil
+		m.unbindNode(target)
+		if err != nil || !cont {
+			return cont, err
+		}
+	}
+
+	if rel.MaxHops >= 0 && len(path) >= rel.MaxHops {
+		return true, nil
+	}
+
+	for _, step := range m.steps(current, rel, forward, relProps) {
+		if err := m.tick(); err != nil {
+			return false, err
+		}
+		m.used[step.edge] = true
+		cont, err := m.expand(r, rel, forward, step.node, target, append(path[:len(path):len(path)], step.edge), relProps, nodeProps, next)
+		delete(m.used, step.edge)
+		if err != nil || !cont {
+			return cont, err
+		}
+	}
+	return true, nil
+}
+
+// edgeStep is a relationship and the node it leads to
+type edgeStep struct {
+	edge *memEdge
+	node *memNode
+}
+
+// steps lists the unused relationships from n that match rel
+func (m *patternMatcher) steps(n *memNode, rel *cypher.RelationshipPattern, forward bool, props map[string]interface{}) []edgeStep {
+	var steps []edgeStep
+	add := func(edges []*memEdge, outgoing bool) {
+		for _, e := range edges {
+			if e.deleted || m.used[e] || !relTypeMatches(e, rel.Types) || !propertiesMatch(e.props, props) {
+				continue
+			}
+			if outgoing {
+				steps = append(steps, edgeStep{edge: e, node: e.dst})
+			} else {
+				steps = append(steps, edgeStep{edge: e, node: e.src})
+			}
+		}
+	}
+
+	switch rel.Direction {
+	case cypher.DirectionBoth:
+		add(n.out, true)
+		// A self-loop appears in both lists but must only be matched once
+		for _, e := range n.in {
+			if e.src != e.dst {
+				add([]*memEdge{e}, false)
+			}
+		}
+	default:
+		// Following the arrow means leaving through outgoing relationships
+		outgoing := (rel.Direction == cypher.DirectionRight) == forward
+		if outgoing {
+			add(n.out, true)
+		} else {
+			add(n.in, false)
+		}
+	}
+	return steps
+}
+
+func relTypeMatches(e *memEdge, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if e.relType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Writing clauses
+
+func (ev *evaluator) execCreate(c *cypher.Create, rows []bindings) ([]bindings, error) {
+	out := make([]bindings, 0, len(rows))
+	for _, row := range rows {
+		for _, part := range c.Pattern {
+			var err error
+			if row, err = ev.createPart(part, row, false); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (ev *evaluator) execMerge(c *cypher.Merge, rows []bindings) ([]bindings, error) {
+	var out []bindings
+	for _, row := range rows {
+		var matches []bindings
+		err := ev.matchPart(c.Part, row, func(match bindings) (bool, error) {
+			matches = append(matches, match)
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) > 0 {
+			for _, match := range matches {
+				if err := ev.applySet(c.OnMatch, match); err != nil {
+					return nil, err
+				}
+			}
+			out = append(out, matches...)
+			continue
+		}
+
+		created, err := ev.createPart(c.Part, row, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := ev.applySet(c.OnCreate, created); err != nil {
+			return nil, err
+		}
+		out = append(out, created)
+	}
+	return out, nil
+}
+
+// createPart creates the unbound nodes and all relationships of a pattern
+// part and returns the row with the new variables bound
+func (ev *evaluator) createPart(part *cypher.PatternPart, row bindings, merge bool) (bindings, error) {
+	row = mergeBindings(row, nil)
+	nodes := make([]*memNode, len(part.Nodes))
+
+	for i, pattern := range part.Nodes {
+		if pattern.Variable != "" {
+			if value, ok := row[pattern.Variable]; ok {
+				n, isNode := value.(*memNode)
+				if !isNode {
+					return nil, fmt.Errorf("failed to create relationship; endpoint '%s' was not found", pattern.Variable)
+				}
+				nodes[i] = n
+				continue
+			}
+		}
+		props, err := ev.creationProps(pattern.Properties, row, merge)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = ev.tx.createNode(append([]string(nil), pattern.Labels...), props)
+		if pattern.Variable != "" {
+			row[pattern.Variable] = nodes[i]
+		}
+	}
+
+	edges := make([]*memEdge, len(part.Relationships))
+	for i, rel := range part.Relationships {
+		if len(rel.Types) != 1 || rel.VarLength {
+			return nil, fmt.Errorf("exactly one relationship type must be specified for CREATE")
+		}
+		src, dst := nodes[i], nodes[i+1]
+		switch rel.Direction {
+		case cypher.DirectionLeft:
+			src, dst = dst, src
+		case cypher.DirectionBoth:
+			if !merge {
+				return nil, fmt.Errorf("only directed relationships are supported in CREATE")
+			}
+		}
+		props, err := ev.creationProps(rel.Properties, row, merge)
+		if err != nil {
+			return nil, err
+		}
+		if edges[i], err = ev.tx.createEdge(rel.Types[0], src, dst, props); err != nil {
+			return nil, err
+		}
+		if rel.Variable != "" {
+			row[rel.Variable] = edges[i]
+		}
+	}
+
+	if part.PathVariable != "" {
+		row[part.PathVariable] = &memPath{nodes: nodes, edges: edges}
+	}
+	return row, nil
+}
+
+// creationProps evaluates the properties of a pattern element being created
+func (ev *evaluator) creationProps(e cypher.Expr, row bindings, merge bool) (map[string]interface{}, error) {
+	props, err := ev.patternProps(e, row)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range props {
+		if value == nil && merge {
+			return nil, fmt.Errorf("cannot merge node using null property value for '%s'", key)
+		}
+		if err := storableValue(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return props, nil
+}
+
+func (ev *evaluator) applySet(items []cypher.SetItem, row bindings) error {
+	for _, item := range items {
+		value, err := ev.eval(item.Value, row)
+		if err != nil {
+			return err
+		}
+
+		if item.Property != nil {
+			target, err := ev.eval(item.Property.Expr, row)
+			if err != nil {
+				return err
+			}
+			if target == nil {
+				continue
+			}
+			if err := storableValue(item.Property.Key, value); err != nil {
+				return err
+			}
+			if err := ev.tx.setProperty(target, item.Property.Key, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		target := row[item.Variable]
+		if target == nil {
+			continue
+		}
+		var props map[string]interface{}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			props = v
+		case *memNode:
+			props = copyProperties(v.props)
+		case *memEdge:
+			props = copyProperties(v.props)
+		case nil:
+			props = map[string]interface{}{}
+		default:
+			return fmt.Errorf("type mismatch: expected Map but was %s", typeName(value))
+		}
+
+		// "n = map" replaces all properties, "n += map" only updates
+		if !item.Merge {
+			var existing map[string]interface{}
+			switch t := target.(type) {
+			case *memNode:
+				existing = t.props
+			case *memEdge:
+				existing = t.props
+			}
+			var stale []string
+			for key := range existing {
+				if _, ok := props[key]; !ok {
+					stale = append(stale, key)
+				}
+			}
+			for _, key := range stale {
+				if err := ev.tx.setProperty(target, key, nil); err != nil {
+					return err
+				}
+			}
+		}
+		keys := make([]string, 0, len(props))
+		for key := range props {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := storableValue(key, props[key]); err != nil {
+				return err
+			}
+			if err := ev.tx.setProperty(target, key, props[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ev *evaluator) applyRemove(items []*cypher.Property, row bindings) error {
+	for _, item := range items {
+		target, err := ev.eval(item.Expr, row)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			continue
+		}
+		if err := ev.tx.setProperty(target, item.Key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ev *evaluator) applyDelete(c *cypher.Delete, row bindings) error {
+	for _, expr := range c.Exprs {
+		value, err := ev.eval(expr, row)
+		if err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case nil:
+		case *memNode:
+			ev.tx.deleteNode(v)
+		case *memEdge:
+			ev.tx.deleteEdge(v)
+		case *memPath:
+			for _, e := range v.edges {
+				ev.tx.deleteEdge(e)
+			}
+			for _, n := range v.nodes {
+				ev.tx.deleteNode(n)
+			}
+		default:
+			return fmt.Errorf("DELETE expects a Node, Edge or Path but was %s", typeName(value))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/moolen/spectre/internal/graph/cypher"
+	"github.com/moolen/spectre/internal/graph/fulltext"
+)
+
+// memProcedure is a procedure that can be invoked with CALL. It returns one
+// map per yielded row, keyed by column.
+type memProcedure struct {
+	columns []string
+	run     func(g *memGraph, args []interface{}) ([]map[string]interface{}, error)
+}
+
+// memProcedures are the supported procedures by lower-cased name
+var memProcedures = map[string]memProcedure{
+	"db.idx.fulltext.querynodes": {columns: []string{"node", "score"}, run: fulltextQueryNodes},
+}
+
+// execCall invokes the procedure once per row and binds the yielded columns
+func (ev *evaluator) execCall(c *cypher.Call, rows []bindings) ([]bindings, error) {
+	proc, ok := memProcedures[strings.ToLower(c.Procedure)]
+	if !ok {
+		return nil, fmt.Errorf("Procedure `%s` is not registered", c.Procedure)
+	}
+	yield := c.Yield
+	if len(yield) == 0 {
+		yield = proc.columns
+	}
+	for _, name := range yield {
+		if !containsString(proc.columns, name) {
+			return nil, fmt.Errorf("Procedure `%s` does not yield output `%s`", c.Procedure, name)
+		}
+	}
+
+	var out []bindings
+	for _, row := range rows {
+		args := make([]interface{}, len(c.Args))
+		for i, arg := range c.Args {
+			value, err := ev.eval(arg, row)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		results, err := proc.run(ev.tx.graph, args)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			next := row
+			for _, name := range yield {
+				next = next.with(name, result[name])
+			}
+			out = append(out, next)
+		}
+	}
+	return out, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// fulltextQueryNodes implements db.idx.fulltext.queryNodes(label, query). It
+// matches the nodes whose full-text indexed properties contain every clause
+// of the query and scores them with TF-IDF, which ranks like RediSearch's
+// default scorer closely enough for tests and small installations.
+func fulltextQueryNodes(g *memGraph, args []interface{}) ([]map[string]interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("Procedure `db.idx.fulltext.queryNodes` requires 2 arguments, got %d", len(args))
+	}
+	label, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("Type mismatch: expected String but was %s", typeName(args[0]))
+	}
+	input, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("Type mismatch: expected String but was %s", typeName(args[1]))
+	}
+	properties, ok := g.fulltext[label]
+	if !ok {
+		return nil, fmt.Errorf("there is no full-text index on label %s", label)
+	}
+	q, err := fulltext.Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid full-text query: %w", err)
+	}
+
+	type candidate struct {
+		node   *memNode
+		counts []int
+		length int
+	}
+	var candidates []candidate
+	docs := 0
+	df := make([]int, len(q.Clauses))
+	for _, n := range g.byLabel[label] {
+		if n.deleted {
+			continue
+		}
+		c := candidate{node: n, counts: make([]int, len(q.Clauses))}
+		indexed := false
+		for _, property := range properties {
+			text, ok := n.props[property].(string)
+			if !ok {
+				continue
+			}
+			indexed = true
+			tokens := fulltext.Tokenize(text)
+			c.length += len(tokens)
+			for i, count := range q.Occurrences(tokens) {
+				c.counts[i] += count
+			}
+		}
+		if !indexed {
+			continue
+		}
+		docs++
+		matched := true
+		for i, count := range c.counts {
+			if count > 0 {
+				df[i]++
+			} else {
+				matched = false
+			}
+		}
+		if matched {
+			candidates = append(candidates, c)
+		}
+	}
+
+	results := make([]map[string]interface{}, len(candidates))
+	for i, c := range candidates {
+		score := 0.0
+		for j, count := range c.counts {
+			score += float64(count) / float64(c.length) * math.Log(1+float64(docs)/float64(df[j]))
+		}
+		results[i] = map[string]interface{}{"node": c.node, "score": score}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i]["score"].(float64) > results[j]["score"].(float64)
+	})
+	return results, nil
+}
@@ -0,0 +1,403 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// memNode is a node of the in-memory graph
+type memNode struct {
+	id      uint64
+	labels  []string
+	props   map[string]interface{}
+	out     []*memEdge
+	in      []*memEdge
+	deleted bool
+}
+
+// hasLabel reports whether the node carries label
+func (n *memNode) hasLabel(label string) bool {
+	for _, l := range n.labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// memEdge is a relationship of the in-memory graph
+type memEdge struct {
+	id      uint64
+	relType string
+	src     *memNode
+	dst     *memNode
+	props   map[string]interface{}
+	deleted bool
+}
+
+// memPath is a path value produced by a named pattern
+type memPath struct {
+	nodes []*memNode
+	edges []*memEdge
+}
+
+// memGraph stores one named graph. Nodes are kept in creation order so
+// scans return them in the same order as FalkorDB, which scans by node ID.
+type memGraph struct {
+	nodes      []*memNode
+	byLabel    map[string][]*memNode
+	indexes    map[string]map[string]map[string][]*memNode // label -> property -> value key -> nodes
+	fulltext   map[string][]string                         // label -> full-text indexed properties
+	nodeCount  int
+	edgeCount  int
+	nextNodeID uint64
+	nextEdgeID uint64
+	// dirty is set when deleted nodes have to be compacted out of the scan lists
+	dirty bool
+}
+
+func newMemGraph() *memGraph {
+	return &memGraph{
+		byLabel:  make(map[string][]*memNode),
+		indexes:  make(map[string]map[string]map[string][]*memNode),
+		fulltext: make(map[string][]string),
+	}
+}
+
+// scan returns the live nodes carrying label, or all live nodes when label is empty
+func (g *memGraph) scan(label string) []*memNode {
+	if label == "" {
+		return g.nodes
+	}
+	return g.byLabel[label]
+}
+
+// indexLookup returns the candidates for label.property = value, or false if
+// the property is not indexed
+func (g *memGraph) indexLookup(label, property string, value interface{}) ([]*memNode, bool) {
+	index, ok := g.indexes[label][property]
+	if !ok {
+		return nil, false
+	}
+	return index[hashKey(value)], true
+}
+
+// isIndexed reports whether label.property has an index
+func (g *memGraph) isIndexed(label, property string) bool {
+	_, ok := g.indexes[label][property]
+	return ok
+}
+
+func (g *memGraph) indexAdd(n *memNode, property string, value interface{}) {
+	if value == nil {
+		return
+	}
+	for _, label := range n.labels {
+		if index, ok := g.indexes[label][property]; ok {
+			key := hashKey(value)
+			index[key] = append(index[key], n)
+		}
+	}
+}
+
+func (g *memGraph) indexRemove(n *memNode, property string, value interface{}) {
+	if value == nil {
+		return
+	}
+	for _, label := range n.labels {
+		if index, ok := g.indexes[label][property]; ok {
+			key := hashKey(value)
+			index[key] = removeNode(index[key], n)
+			if len(index[key]) == 0 {
+				delete(index, key)
+			}
+		}
+	}
+}
+
+// compact drops deleted nodes from the scan lists
+func (g *memGraph) compact() {
+	if !g.dirty {
+		return
+	}
+	g.nodes = liveNodes(g.nodes)
+	for label, nodes := range g.byLabel {
+		g.byLabel[label] = liveNodes(nodes)
+	}
+	g.dirty = false
+}
+
+func liveNodes(nodes []*memNode) []*memNode {
+	live := nodes[:0]
+	for _, n := range nodes {
+		if !n.deleted {
+			live = append(live, n)
+		}
+	}
+	// Clear the tail so deleted nodes can be garbage collected
+	for i := len(live); i < len(nodes); i++ {
+		nodes[i] = nil
+	}
+	return live
+}
+
+func removeNode(nodes []*memNode, n *memNode) []*memNode {
+	for i, candidate := range nodes {
+		if candidate == n {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
+
+func removeEdge(edges []*memEdge, e *memEdge) []*memEdge {
+	for i, candidate := range edges {
+		if candidate == e {
+			return append(edges[:i], edges[i+1:]...)
+		}
+	}
+	return edges
+}
+
+// memTxn applies the mutations of a single query and records how to undo
+// them, so that a failing query leaves the graph unchanged like it does in
+// FalkorDB
+type memTxn struct {
+	graph *memGraph
+	undo  []func()
+	stats QueryStats
+}
+
+func newMemTxn(g *memGraph) *memTxn {
+	return &memTxn{graph: g}
+}
+
+// commit finalizes the transaction
+func (tx *memTxn) commit() {
+	tx.undo = nil
+	tx.graph.compact()
+}
+
+// rollback reverts all mutations in reverse order
+func (tx *memTxn) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = nil
+	tx.graph.compact()
+}
+
+func (tx *memTxn) createNode(labels []string, props map[string]interface{}) *memNode {
+	g := tx.graph
+	g.nextNodeID++
+	n := &memNode{id: g.nextNodeID - 1, labels: labels, props: make(map[string]interface{}, len(props))}
+
+	g.nodes = append(g.nodes, n)
+	for _, label := range labels {
+		g.byLabel[label] = append(g.byLabel[label], n)
+	}
+	g.nodeCount++
+	for key, value := range props {
+		if value != nil {
+			n.props[key] = value
+			g.indexAdd(n, key, value)
+			tx.stats.PropertiesSet++
+		}
+	}
+	tx.stats.NodesCreated++
+	tx.stats.LabelsAdded += len(labels)
+
+	tx.undo = append(tx.undo, func() {
+		for key, value := range n.props {
+			g.indexRemove(n, key, value)
+		}
+		n.deleted = true
+		g.nodeCount--
+		g.dirty = true
+	})
+	return n
+}
+
+func (tx *memTxn) createEdge(relType string, src, dst *memNode, props map[string]interface{}) (*memEdge, error) {
+	if src.deleted || dst.deleted {
+		return nil, fmt.Errorf("cannot create relationship %s on a deleted node", relType)
+	}
+
+	g := tx.graph
+	g.nextEdgeID++
+	e := &memEdge{id: g.nextEdgeID - 1, relType: relType, src: src, dst: dst, props: make(map[string]interface{}, len(props))}
+	for key, value := range props {
+		if value != nil {
+			e.props[key] = value
+			tx.stats.PropertiesSet++
+		}
+	}
+
+	src.out = append(src.out, e)
+	dst.in = append(dst.in, e)
+	g.edgeCount++
+	tx.stats.RelationshipsCreated++
+
+	tx.undo = append(tx.undo, func() {
+		src.out = removeEdge(src.out, e)
+		dst.in = removeEdge(dst.in, e)
+		e.deleted = true
+		g.edgeCount--
+	})
+	return e, nil
+}
+
+// setProperty sets or, for a nil value, removes a property of a node or edge
+func (tx *memTxn) setProperty(entity interface{}, key string, value interface{}) error {
+	var props map[string]interface{}
+	var node *memNode
+	switch entity := entity.(type) {
+	case *memNode:
+		if entity.deleted {
+			return nil
+		}
+		props, node = entity.props, entity
+	case *memEdge:
+		if entity.deleted {
+			return nil
+		}
+		props = entity.props
+	default:
+		return fmt.Errorf("cannot set property %q on %s", key, typeName(entity))
+	}
+
+	old, existed := props[key]
+	if value == nil {
+		if !existed {
+			return nil
+		}
+		delete(props, key)
+	} else {
+		props[key] = value
+		tx.stats.PropertiesSet++
+	}
+	if node != nil {
+		tx.graph.indexRemove(node, key, old)
+		tx.graph.indexAdd(node, key, value)
+	}
+
+	tx.undo = append(tx.undo, func() {
+		if node != nil {
+			tx.graph.indexRemove(node, key, value)
+		}
+		if existed {
+			props[key] = old
+			if node != nil {
+				tx.graph.indexAdd(node, key, old)
+			}
+		} else {
+			delete(props, key)
+		}
+	})
+	return nil
+}
+
+func (tx *memTxn) deleteEdge(e *memEdge) {
+	if e.deleted {
+		return
+	}
+	g := tx.graph
+	e.src.out = removeEdge(e.src.out, e)
+	e.dst.in = removeEdge(e.dst.in, e)
+	e.deleted = true
+	g.edgeCount--
+	tx.stats.RelationshipsDeleted++
+
+	tx.undo = append(tx.undo, func() {
+		e.deleted = false
+		e.src.out = append(e.src.out, e)
+		e.dst.in = append(e.dst.in, e)
+		sortEdges(e.src.out)
+		sortEdges(e.dst.in)
+		g.edgeCount++
+	})
+}
+
+// deleteNode deletes a node together with its relationships, which is what
+// FalkorDB does for both DELETE and DETACH DELETE
+func (tx *memTxn) deleteNode(n *memNode) {
+	if n.deleted {
+		return
+	}
+	for len(n.out) > 0 {
+		tx.deleteEdge(n.out[0])
+	}
+	for len(n.in) > 0 {
+		tx.deleteEdge(n.in[0])
+	}
+
+	g := tx.graph
+	for key, value := range n.props {
+		g.indexRemove(n, key, value)
+	}
+	n.deleted = true
+	g.nodeCount--
+	g.dirty = true
+	tx.stats.NodesDeleted++
+
+	tx.undo = append(tx.undo, func() {
+		n.deleted = false
+		g.nodeCount++
+		for key, value := range n.props {
+			g.indexAdd(n, key, value)
+		}
+		// Compaction only runs after the transaction, so the node is still
+		// part of the scan lists
+	})
+}
+
+// createIndex indexes label.property for exact-match lookups
+func (tx *memTxn) createIndex(label, property string) error {
+	g := tx.graph
+	if g.isIndexed(label, property) {
+		return fmt.Errorf("attribute '%s' is already indexed", property)
+	}
+	if g.indexes[label] == nil {
+		g.indexes[label] = make(map[string]map[string][]*memNode)
+	}
+	index := make(map[string][]*memNode)
+	for _, n := range g.byLabel[label] {
+		if value, ok := n.props[property]; ok && !n.deleted {
+			key := hashKey(value)
+			index[key] = append(index[key], n)
+		}
+	}
+	g.indexes[label][property] = index
+
+	tx.undo = append(tx.undo, func() {
+		delete(g.indexes[label], property)
+	})
+	return nil
+}
+
+// createFulltextIndex adds label.property to the full-text index of label.
+// Full-text queries tokenize the indexed properties when they run, so there
+// is nothing to build up front.
+func (tx *memTxn) createFulltextIndex(label, property string) error {
+	g := tx.graph
+	for _, indexed := range g.fulltext[label] {
+		if indexed == property {
+			return fmt.Errorf("attribute '%s' is already indexed", property)
+		}
+	}
+	previous := g.fulltext[label]
+	g.fulltext[label] = append(append([]string(nil), previous...), property)
+
+	tx.undo = append(tx.undo, func() {
+		if previous == nil {
+			delete(g.fulltext, label)
+			return
+		}
+		g.fulltext[label] = previous
+	})
+	return nil
+}
+
+func sortEdges(edges []*memEdge) {
+	sort.Slice(edges, func(i, j int) bool { return edges[i].id < edges[j].id })
+}
@@ -0,0 +1,425 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FalkorDB/falkordb-go/v2"
+)
+
+// Values in the in-memory backend are nil, bool, int64, float64, string,
+// []interface{}, map[string]interface{}, *memNode, *memEdge and *memPath.
+
+// typeName names the Cypher type of a value for error messages
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "Null"
+	case bool:
+		return "Boolean"
+	case int64:
+		return "Integer"
+	case float64:
+		return "Float"
+	case string:
+		return "String"
+	case []interface{}:
+		return "List"
+	case map[string]interface{}:
+		return "Map"
+	case *memNode:
+		return "Node"
+	case *memEdge:
+		return "Edge"
+	case *memPath:
+		return "Path"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// toFloat returns a numeric value as float64
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// toInt returns an integer value, accepting floats without a fraction
+func toInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// truthy interprets a predicate result: only true passes a filter
+func truthy(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// valuesEqual implements Cypher equality. The second result is false when
+// the comparison is undefined (null).
+func valuesEqual(a, b interface{}) (equal bool, defined bool) {
+	if a == nil || b == nil {
+		return false, false
+	}
+
+	switch av := a.(type) {
+	case int64, float64:
+		af, _ := toFloat(av)
+		bf, ok := toFloat(b)
+		if !ok {
+			return false, true
+		}
+		if ai, ok := av.(int64); ok {
+			if bi, ok := b.(int64); ok {
+				return ai == bi, true
+			}
+		}
+		return af == bf, true
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv, true
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv, true
+	case *memNode:
+		bv, ok := b.(*memNode)
+		return ok && av == bv, true
+	case *memEdge:
+		bv, ok := b.(*memEdge)
+		return ok && av == bv, true
+	case *memPath:
+		bv, ok := b.(*memPath)
+		return ok && hashKey(av) == hashKey(bv), true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false, true
+		}
+		defined := true
+		for i := range av {
+			eq, def := valuesEqual(av[i], bv[i])
+			if def && !eq {
+				return false, true
+			}
+			defined = defined && def
+		}
+		return defined, defined
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false, true
+		}
+		defined := true
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok {
+				return false, true
+			}
+			eq, def := valuesEqual(value, other)
+			if def && !eq {
+				return false, true
+			}
+			defined = defined && def
+		}
+		return defined, defined
+	}
+	return false, true
+}
+
+// compareValues orders two values for <, >, <= and >=. The second result is
+// false when the values are not comparable, which makes the comparison null.
+func compareValues(a, b interface{}) (int, bool) {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		if ai, ok := a.(int64); ok {
+			if bi, ok := b.(int64); ok {
+				return compareInts(ai, bi), true
+			}
+		}
+		if math.IsNaN(af) || math.IsNaN(bf) {
+			return 0, false
+		}
+		return compareFloats(af, bf), true
+	}
+
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), true
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			return compareBools(av, bv), true
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) && i < len(bv); i++ {
+				c, ok := compareValues(av[i], bv[i])
+				if !ok {
+					return 0, false
+				}
+				if c != 0 {
+					return c, true
+				}
+			}
+			return compareInts(int64(len(av)), int64(len(bv))), true
+		}
+	}
+	return 0, false
+}
+
+// orderRank groups values of different types for ORDER BY, which has to
+// order any two values
+func orderRank(v interface{}) int {
+	switch v.(type) {
+	case map[string]interface{}:
+		return 0
+	case *memNode:
+		return 1
+	case *memEdge:
+		return 2
+	case []interface{}:
+		return 3
+	case *memPath:
+		return 4
+	case string:
+		return 5
+	case bool:
+		return 6
+	case int64, float64:
+		return 7
+	case nil:
+		return 9
+	}
+	return 8
+}
+
+// orderValues is the total order used by ORDER BY, with nulls last in
+// ascending order
+func orderValues(a, b interface{}) int {
+	ra, rb := orderRank(a), orderRank(b)
+	if ra != rb {
+		return compareInts(int64(ra), int64(rb))
+	}
+
+	switch av := a.(type) {
+	case nil:
+		return 0
+	case *memNode:
+		return compareInts(int64(av.id), int64(b.(*memNode).id))
+	case *memEdge:
+		return compareInts(int64(av.id), int64(b.(*memEdge).id))
+	case []interface{}:
+		bv := b.([]interface{})
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if c := orderValues(av[i], bv[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(int64(len(av)), int64(len(bv)))
+	case float64:
+		// NaN sorts after all other numbers
+		if bf, _ := toFloat(b); math.IsNaN(av) || math.IsNaN(bf) {
+			return compareBools(math.IsNaN(av), math.IsNaN(bf))
+		}
+	}
+
+	if c, ok := compareValues(a, b); ok {
+		return c
+	}
+	return strings.Compare(hashKey(a), hashKey(b))
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+// hashKey renders a value as a string that is equal for values that are
+// equal for grouping and DISTINCT purposes
+func hashKey(v interface{}) string {
+	var b strings.Builder
+	writeHashKey(&b, v)
+	return b.String()
+}
+
+func writeHashKey(b *strings.Builder, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int64:
+		b.WriteString("n:")
+		b.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		b.WriteString("n:")
+		if v == math.Trunc(v) && math.Abs(v) < 1e18 {
+			b.WriteString(strconv.FormatInt(int64(v), 10))
+		} else {
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case string:
+		b.WriteString(strconv.Quote(v))
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeHashKey(b, item)
+		}
+		b.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Quote(key))
+			b.WriteByte(':')
+			writeHashKey(b, v[key])
+		}
+		b.WriteByte('}')
+	case *memNode:
+		fmt.Fprintf(b, "node:%d", v.id)
+	case *memEdge:
+		fmt.Fprintf(b, "edge:%d", v.id)
+	case *memPath:
+		b.WriteString("path:")
+		for _, n := range v.nodes {
+			fmt.Fprintf(b, "%d,", n.id)
+		}
+		for _, e := range v.edges {
+			fmt.Fprintf(b, "%d,", e.id)
+		}
+	default:
+		fmt.Fprintf(b, "%T:%v", v, v)
+	}
+}
+
+// storableValue checks that v can be stored as a property, which excludes
+// maps, graph entities and nested lists
+func storableValue(key string, v interface{}) error {
+	switch v := v.(type) {
+	case nil, bool, int64, float64, string:
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			switch item.(type) {
+			case nil, bool, int64, float64, string:
+			default:
+				return fmt.Errorf("property %q: arrays can only contain primitive types, got %s", key, typeName(item))
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("property %q: property values can only be of primitive types or arrays of primitive types, got %s", key, typeName(v))
+}
+
+// resultValue converts a value to what the FalkorDB client returns for it,
+// copying properties so results never alias the stored graph
+func resultValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *memNode:
+		return resultNode(v)
+	case *memEdge:
+		return &falkordb.Edge{
+			ID:         v.id,
+			Relation:   v.relType,
+			Properties: copyProperties(v.props),
+		}
+	case *memPath:
+		path := falkordb.Path{
+			Nodes: make([]*falkordb.Node, len(v.nodes)),
+			Edges: make([]*falkordb.Edge, len(v.edges)),
+		}
+		for i, n := range v.nodes {
+			path.Nodes[i] = resultNode(n)
+		}
+		for i, e := range v.edges {
+			path.Edges[i] = resultValue(e).(*falkordb.Edge)
+		}
+		return path
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = resultValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = resultValue(value)
+		}
+		return m
+	}
+	return v
+}
+
+func resultNode(n *memNode) *falkordb.Node {
+	return &falkordb.Node{
+		ID:         n.id,
+		Labels:     append([]string(nil), n.labels...),
+		Properties: copyProperties(n.props),
+	}
+}
+
+func copyProperties(props map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(props))
+	for key, value := range props {
+		if list, ok := value.([]interface{}); ok {
+			value = append([]interface{}(nil), list...)
+		}
+		out[key] = value
+	}
+	return out
+}
@@ -53,7 +53,8 @@ const (
 
 // ResourceIdentity represents a persistent Kubernetes resource node
 type ResourceIdentity struct {
-	UID       string            `json:"uid"`       // K8s UID (primary key)
+	UID       string            `json:"uid"`       // K8s UID, cluster-qualified if Cluster is set (primary key)
+	Cluster   string            `json:"cluster"`   // cluster name, empty for single-cluster deployments
 	Kind      string            `json:"kind"`      // e.g., "Pod", "Deployment"
 	APIGroup  string            `json:"apiGroup"`  // e.g., "apps", "" for core
 	Version   string            `json:"version"`   // e.g., "v1"
@@ -69,6 +70,7 @@ type ResourceIdentity struct {
 // ChangeEvent represents a state change event node
 type ChangeEvent struct {
 	ID              string   `json:"id"`              // Spectre Event.ID
+	Cluster         string   `json:"cluster"`         // cluster the event was observed in
 	Timestamp       int64    `json:"timestamp"`       // Unix nanoseconds
 	EventType       string   `json:"eventType"`       // CREATE, UPDATE, DELETE
 	Status          string   `json:"status"`          // Ready, Warning, Error, Terminating, Unknown
@@ -79,11 +81,29 @@ type ChangeEvent struct {
 	ReplicasChanged bool     `json:"replicasChanged"` // for controllers
 	ImpactScore     float64  `json:"impactScore"`     // 0.0-1.0
 	Data            string   `json:"data,omitempty"`  // Full resource JSON (for timeline reconstruction)
+	ResourceVersion string   `json:"resourceVersion"` // metadata.resourceVersion of Data
+
+	// Who made the change, set once the change was correlated with a
+	// Kubernetes audit event
+	ActorUser           string `json:"actorUser,omitempty"`
+	ActorServiceAccount string `json:"actorServiceAccount,omitempty"` // namespace/name
+	ActorUserAgent      string `json:"actorUserAgent,omitempty"`
+	ActorSourceIP       string `json:"actorSourceIP,omitempty"`
+
+	// FieldManagers is the JSON encoded map from field path to the manager
+	// that last set the field, summarized from managedFields
+	FieldManagers string `json:"fieldManagers,omitempty"`
+
+	// Set on summary nodes left by compaction, which stand for a run of
+	// status-only updates ending at Timestamp
+	CompactedCount int   `json:"compactedCount,omitempty"` // number of compacted events
+	FirstTimestamp int64 `json:"firstTimestamp,omitempty"` // Unix nanoseconds of the oldest compacted event
 }
 
 // K8sEvent represents a Kubernetes Event object node
 type K8sEvent struct {
 	ID        string `json:"id"`        // Event ID
+	Cluster   string `json:"cluster"`   // cluster the event was observed in
 	Timestamp int64  `json:"timestamp"` // Unix nanoseconds
 	Reason    string `json:"reason"`    // e.g., "FailedScheduling"
 	Message   string `json:"message"`   // event message
@@ -233,6 +253,7 @@ const (
 	EvidenceTypeNamespace  EvidenceType = "namespace"  // Same namespace
 	EvidenceTypeOwnership  EvidenceType = "ownership"  // OwnerReference present
 	EvidenceTypeReconcile  EvidenceType = "reconcile"  // Reconcile event correlation
+	EvidenceTypeReference  EvidenceType = "reference"  // Reference declared in the spec
 )
 
 // EvidenceItem represents a piece of evidence for an inferred relationship
@@ -0,0 +1,293 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parameterNamePattern matches names that can be used as $parameters and map keys without quoting
+var parameterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// buildParamsHeader renders query parameters as FalkorDB's "CYPHER name=value ..."
+// query prefix. FalkorDB binds the values as parameters, so the query text
+// itself stays constant and its execution plan can be cached.
+//
+// Parameters are rendered in sorted order so the same query and parameters
+// always produce the same header.
+func buildParamsHeader(params map[string]interface{}) (string, error) {
+	if len(params) == 0 {
+		return "", nil
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if !parameterNamePattern.MatchString(name) {
+			return "", fmt.Errorf("invalid query parameter name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("CYPHER ")
+	for _, name := range names {
+		literal, err := cypherLiteral(params[name])
+		if err != nil {
+			return "", fmt.Errorf("query parameter %q: %w", name, err)
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(literal)
+		b.WriteByte(' ')
+	}
+	return b.String(), nil
+}
+
+// cypherLiteral renders a Go value as a Cypher literal for the params header.
+// Strings, numbers, booleans, nil, slices and string-keyed maps map to their
+// Cypher counterparts; any other value is stored as its JSON encoding.
+func cypherLiteral(value interface{}) (string, error) {
+	if value == nil {
+		return "null", nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return cypherString(v), nil
+	case json.RawMessage:
+		return cypherString(string(v)), nil
+	case []byte:
+		return cypherString(string(v)), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return "", fmt.Errorf("integer %d overflows int64", rv.Uint())
+		}
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return cypherFloat(rv.Float())
+	case reflect.String:
+		return cypherString(rv.String()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return "null", nil
+		}
+		return cypherLiteral(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return "[]", nil
+		}
+		items := make([]string, rv.Len())
+		for i := range items {
+			item, err := cypherLiteral(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = item
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			return cypherMap(rv)
+		}
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %T: %w", value, err)
+	}
+	return cypherString(string(jsonBytes)), nil
+}
+
+// parameterValue converts a Go value to the value FalkorDB binds for it
+// through the params header, following the same rules as cypherLiteral. It
+// is used by the in-memory backend, which binds parameters without rendering
+// them.
+func parameterValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.RawMessage:
+		return string(v), nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		return v, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("integer %d overflows int64", rv.Uint())
+		}
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(rv.Float()) || math.IsInf(rv.Float(), 0) {
+			return nil, fmt.Errorf("unsupported float value %v", rv.Float())
+		}
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return parameterValue(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			item, err := parameterValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			m := make(map[string]interface{}, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				item, err := parameterValue(iter.Value().Interface())
+				if err != nil {
+					return nil, err
+				}
+				m[iter.Key().String()] = item
+			}
+			return m, nil
+		}
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", value, err)
+	}
+	return string(jsonBytes), nil
+}
+
+// cypherMap renders a string-keyed map as a Cypher map literal with sorted keys
+func cypherMap(rv reflect.Value) (string, error) {
+	keys := make([]string, 0, rv.Len())
+	for _, key := range rv.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		value, err := cypherLiteral(rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface())
+		if err != nil {
+			return "", err
+		}
+		entries[i] = cypherIdentifier(key) + ": " + value
+	}
+	return "{" + strings.Join(entries, ", ") + "}", nil
+}
+
+// cypherFloat renders a float so that it is always parsed back as a float
+func cypherFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("unsupported float value %v", f)
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.ContainsRune(s, '.') {
+		s += ".0"
+	}
+	return s, nil
+}
+
+// cypherString renders s as a double-quoted Cypher string literal. Only the
+// escape sequences the Cypher grammar defines are used: backslash, quote and
+// the common whitespace escapes, plus \uXXXX for other control characters.
+// Invalid UTF-8 is replaced with U+FFFD since it cannot be stored anyway.
+func cypherString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r) // utf8.RuneError for invalid input
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// cypherIdentifier returns name unchanged if it is a plain identifier and
+// backtick-quoted otherwise
+func cypherIdentifier(name string) string {
+	if parameterNamePattern.MatchString(name) {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// propertiesPattern renders props as a Cypher map pattern whose values are
+// bound to parameters prefixed with prefix, e.g. {kind: $p0, name: $p1}.
+// The parameters are added to params.
+func propertiesPattern(props map[string]interface{}, prefix string, params map[string]interface{}) string {
+	if len(props) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		param := fmt.Sprintf("%s%d", prefix, i)
+		params[param] = props[key]
+		entries[i] = fmt.Sprintf("%s: $%s", cypherIdentifier(key), param)
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
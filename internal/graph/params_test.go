@@ -0,0 +1,230 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildParamsHeader(t *testing.T) {
+	header, err := buildParamsHeader(map[string]interface{}{
+		"name":      `it's a "test" \ path`,
+		"count":     int32(3),
+		"deleted":   false,
+		"score":     1.0,
+		"deletedAt": int64(0),
+		"tags":      []string{"a'b", `c"d`},
+		"labels":    map[string]string{"app": "web", "app.kubernetes.io/name": "x"},
+		"missing":   nil,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `CYPHER count=3 deleted=false deletedAt=0 labels={app: "web", `+"`app.kubernetes.io/name`"+`: "x"} `+
+		`missing=null name="it's a \"test\" \\ path" score=1.0 tags=["a'b", "c\"d"] `, header)
+
+	empty, err := buildParamsHeader(nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestBuildParamsHeader_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+	}{
+		{name: "invalid parameter name", params: map[string]interface{}{"bad name": 1}},
+		{name: "injection in parameter name", params: map[string]interface{}{"x=1 MATCH (n) DETACH DELETE n //": 1}},
+		{name: "NaN", params: map[string]interface{}{"f": math.NaN()}},
+		{name: "infinity", params: map[string]interface{}{"f": math.Inf(1)}},
+		{name: "uint overflow", params: map[string]interface{}{"u": uint64(math.MaxUint64)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildParamsHeader(tt.params)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCypherString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "hello", expected: `"hello"`},
+		{input: "it's", expected: `"it's"`},
+		{input: `say "hi"`, expected: `"say \"hi\""`},
+		{input: `C:\path\`, expected: `"C:\\path\\"`},
+		{input: `\'`, expected: `"\\'"`},
+		{input: "line\nbreak\ttab", expected: `"line\nbreak\ttab"`},
+		{input: "bell\a", expected: `"bell\u0007"`},
+		{input: "ünïcödé ☸", expected: `"ünïcödé ☸"`},
+		{input: "bad\xffbyte", expected: "\"bad\uFFFDbyte\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cypherString(tt.input))
+		})
+	}
+}
+
+func TestPropertiesPattern(t *testing.T) {
+	params := map[string]interface{}{"fromUID": "a"}
+	pattern := propertiesPattern(map[string]interface{}{
+		"name":       "x' }) DETACH DELETE (n",
+		"weird key":  1,
+		"apiVersion": "v1",
+	}, "prop", params)
+
+	assert.Equal(t, "{apiVersion: $prop0, name: $prop1, `weird key`: $prop2}", pattern)
+	assert.Equal(t, "x' }) DETACH DELETE (n", params["prop1"])
+	assert.Len(t, params, 4)
+}
+
+// TestSchemaQueryParameters checks that the parameters of every query
+// builder can be encoded
+func TestSchemaQueryParameters(t *testing.T) {
+	queries := []GraphQuery{
+		UpsertResourceIdentityQuery(ResourceIdentity{UID: "u", Labels: map[string]string{"a": "b"}, Deleted: true}),
+		UpsertResourceIdentityQuery(ResourceIdentity{UID: "u"}),
+		CreateChangeEventQuery(ChangeEvent{ID: "e", ContainerIssues: []string{"OOMKilled"}, ImpactScore: 0.5}),
+		CreateK8sEventQuery(K8sEvent{ID: "k", Count: 3}),
+		CreateOwnsEdgeQuery("o", "u", OwnsEdge{Controller: true}),
+		CreateChangedEdgeQuery("u", "e", 1),
+		CreatePrecededByEdgeQuery("e2", "e1", 10),
+		CreateTriggeredByEdgeQuery("e2", "e1", TriggeredByEdge{Confidence: 0.9}),
+		CreateEmittedEventEdgeQuery("u", "k"),
+		CreateScheduledOnEdgeQuery("p", "n", ScheduledOnEdge{}),
+		CreateUsesServiceAccountEdgeQuery("p", "sa"),
+		CreateBindsRoleEdgeQuery("b", "r", BindsRoleEdge{}),
+		CreateGrantsToEdgeQuery("b", "s", GrantsToEdge{}),
+		CreateSelectsEdgeQuery("s", "p", SelectsEdge{SelectorLabels: map[string]string{"app": "web"}}),
+		FindResourceByUIDQuery("u"),
+		FindResourceWithRelationshipsQuery("u"),
+		FindResourceTopologyQuery("u"),
+		FindChangeEventsByResourceQuery("u", 1, 2),
+		FindRootCauseQuery("u", 1, 3, 0.5),
+		CalculateBlastRadiusQuery("u", 1, 1000, []string{"OWNS"}, []string{"MOUNTS"}),
+		DeleteOldChangeEventsQuery(1),
+		DeleteOldK8sEventsQuery(1),
+		GetGraphStatsQuery(),
+		CreateReferencesSpecEdgeQuery("a", "b", ReferencesSpecEdge{}),
+		CreateManagesEdgeQuery("a", "b", ManagesEdge{Evidence: []EvidenceItem{{Type: EvidenceTypeLabel, Value: `"x"`}}}),
+		CreateCreatesObservedEdgeQuery("a", "b", CreatesObservedEdge{}),
+		CreateMountsEdgeQuery("p", "pvc", MountsEdge{}),
+		UpsertDashboardNode(DashboardNode{UID: "d", Tags: []string{"t"}}),
+		FindManagedResourcesQuery("cr", 0.5),
+		FindStaleInferredEdgesQuery(1),
+	}
+
+	for i, query := range queries {
+		_, err := buildParamsHeader(query.Parameters)
+		assert.NoError(t, err, "query %d", i)
+	}
+}
+
+// TestBuildParamsHeader_Encodings checks the literals of values with quotes,
+// backslashes and control characters against the encodings FalkorDB parses
+// back to the original values
+func TestBuildParamsHeader_Encodings(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "plain", value: "web-0", expected: `"web-0"`},
+		{name: "empty", value: "", expected: `""`},
+		{name: "single quote", value: "it's", expected: `"it's"`},
+		{name: "trailing backslash", value: `C:\temp\`, expected: `"C:\\temp\\"`},
+		{name: "escaped quote", value: `\"`, expected: `"\\\""`},
+		{name: "backslash before single quote", value: `\\'`, expected: `"\\\\'"`},
+		{name: "backtick", value: "`", expected: "\"`\""},
+		{name: "injection", value: `"; MATCH (n) DETACH DELETE n //`, expected: `"\"; MATCH (n) DETACH DELETE n //"`},
+		{name: "line breaks", value: "line\nbreak\r\n", expected: `"line\nbreak\r\n"`},
+		{name: "control characters", value: "\x00\x1f\x7f", expected: `"\u0000\u001f\u007f"`},
+		{name: "unicode", value: "ünïcödé ☸", expected: `"ünïcödé ☸"`},
+		{
+			name:     "event data",
+			value:    `{"metadata":{"annotations":{"note":"it's \"quoted\""}}}`,
+			expected: `"{\"metadata\":{\"annotations\":{\"note\":\"it's \\\"quoted\\\"\"}}}"`,
+		},
+		{name: "list", value: []string{`a"b`, `c\d`}, expected: `["a\"b", "c\\d"]`},
+		{name: "map", value: map[string]string{"app": `x"y`, "app.kubernetes.io/name": "z"}, expected: "{app: \"x\\\"y\", `app.kubernetes.io/name`: \"z\"}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, err := buildParamsHeader(map[string]interface{}{"value": tt.value})
+			require.NoError(t, err)
+			assert.Equal(t, "CYPHER value="+tt.expected+" ", header)
+		})
+	}
+}
+
+// FuzzParamsRoundTrip proves that resource names, label values and
+// annotation-bearing event data reach the graph unchanged, whatever quotes,
+// backslashes or line breaks they contain. The literal of each parameter is
+// read back through the Cypher parser of the in-memory backend.
+func FuzzParamsRoundTrip(f *testing.F) {
+	f.Add("web-0", "prod", `{"metadata":{"annotations":{"note":"it's \"quoted\""}}}`)
+	f.Add(`it's`, `C:\temp\`, `\'`)
+	f.Add(`"; MATCH (n) DETACH DELETE n //`, `\"`, "line\nbreak\r\n")
+	f.Add("", "", "")
+	f.Add(`\\\\'`, "`", "\x00\x1f\x7f\u2028")
+
+	client := newTestMemoryClient(f)
+	ctx := context.Background()
+
+	f.Fuzz(func(t *testing.T, name, labelValue, data string) {
+		if !utf8.ValidString(name) || !utf8.ValidString(labelValue) || !utf8.ValidString(data) {
+			t.Skip("Kubernetes objects are valid UTF-8")
+		}
+
+		labels := map[string]string{"app": labelValue, labelValue: name}
+		resourceQuery := UpsertResourceIdentityQuery(ResourceIdentity{
+			UID:       "uid-1",
+			Kind:      "Pod",
+			Namespace: "default",
+			Name:      name,
+			Labels:    labels,
+		})
+		eventQuery := CreateChangeEventQuery(ChangeEvent{ID: "event-1", Data: data, ErrorMessage: name})
+
+		for _, query := range []GraphQuery{resourceQuery, eventQuery} {
+			header, err := buildParamsHeader(query.Parameters)
+			require.NoError(t, err)
+
+			// Nothing in the header may escape the parameter section
+			assert.False(t, strings.ContainsAny(header, "\n\r"), "raw line break in header %q", header)
+
+			for key, value := range query.Parameters {
+				want, ok := value.(string)
+				if !ok {
+					continue
+				}
+				literal, err := cypherLiteral(value)
+				require.NoError(t, err)
+				assert.Contains(t, header, " "+key+"="+literal+" ")
+
+				result, err := client.ExecuteQuery(ctx, GraphQuery{Query: "RETURN " + literal + " AS value"})
+				require.NoError(t, err, "literal %s", literal)
+				require.Len(t, result.Rows, 1)
+				assert.Equal(t, want, result.Rows[0][0], "parameter %s", key)
+
+				if key == "labels" {
+					var gotLabels map[string]string
+					require.NoError(t, json.Unmarshal([]byte(result.Rows[0][0].(string)), &gotLabels))
+					assert.Equal(t, labels, gotLabels)
+				}
+			}
+		}
+	})
+}
@@ -4,12 +4,22 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/moolen/spectre/internal/graph/searchql"
 	"github.com/moolen/spectre/internal/logging"
 	"github.com/moolen/spectre/internal/models"
 )
 
+// timelineQueryVariables are the variables search queries are compiled
+// against in the timeline query
+var timelineQueryVariables = searchql.Variables{
+	Resource:     "r",
+	ChangeEvents: "inRangeEvents",
+	K8sEvents:    "k8sEvents",
+}
+
 // QueryExecutor executes timeline queries against the graph database
 type QueryExecutor struct {
 	client Client
@@ -56,17 +66,24 @@ func (qe *QueryExecutor) ExecutePaginated(ctx context.Context, query *models.Que
 		pageSize = pagination.GetPageSize()
 	}
 
-	// Build Cypher query with pagination
-	cypherQuery := qe.buildTimelineQuery(startTimeNs, endTimeNs, query.Filters, pagination)
+	// Compile the search query, if any
+	var predicate *searchql.Predicate
+	if query.Filters.Query != "" {
+		parsed, err := searchql.Parse(query.Filters.Query)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid query: %w", err)
+		}
+		predicate = parsed.Compile(timelineQueryVariables, "sq")
+	}
 
-	// Execute query
-	result, err := qe.client.ExecuteQuery(ctx, cypherQuery)
+	// Execute the query and convert graph results to events and K8sEvents
+	allEvents, k8sEventsByResource, scanned, err := qe.fetchTimelineEvents(ctx, startTimeNs, endTimeNs, query.Filters, predicate, pagination, pageSize)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to execute graph query: %w", err)
+		return nil, nil, err
+	}
+	if query.Filters.IsScoped() {
+		allEvents = reduceClusterScopedEvents(allEvents, k8sEventsByResource)
 	}
-
-	// Convert graph results to events and K8sEvents
-	allEvents, k8sEventsByResource := qe.parseTimelineResults(result)
 
 	// Group events by resource UID to get actual resource count
 	// This is what BuildResourcesFromEventsWithQueryTime does, but we need to do it here
@@ -151,7 +168,12 @@ func (qe *QueryExecutor) ExecutePaginated(ctx context.Context, query *models.Que
 
 	// Build next cursor from last resource in the page
 	var nextCursor string
-	if hasMore && lastResourceIdx >= 0 && lastResourceIdx < len(resources) {
+	if !hasMore && scanned != nil {
+		// The scan for changed: terms stopped before the page was full; the
+		// next page continues after the last resource checked
+		hasMore = true
+		nextCursor = scanned.Encode()
+	} else if hasMore && lastResourceIdx >= 0 && lastResourceIdx < len(resources) {
 		lastRes := resources[lastResourceIdx]
 		cursor := models.NewResourceCursor(lastRes.kind, lastRes.ns, lastRes.name)
 		nextCursor = cursor.Encode()
@@ -184,6 +206,68 @@ func (qe *QueryExecutor) ExecutePaginated(ctx context.Context, query *models.Que
 	return queryResult, paginationResp, nil
 }
 
+// maxChangedScanRounds bounds the number of queries a timeline page with
+// changed: terms runs to fill the page
+const maxChangedScanRounds = 10
+
+// fetchTimelineEvents runs the timeline query and parses its results. The
+// changed: terms of a search query are checked on the returned events, after
+// the query's LIMIT, so for these the query is repeated after the last
+// resource returned until pageSize+1 resources matched or the resources run
+// out. When maxChangedScanRounds is reached first, the returned cursor points
+// at the last resource that was checked.
+func (qe *QueryExecutor) fetchTimelineEvents(ctx context.Context, startNs, endNs int64, filters models.QueryFilters, predicate *searchql.Predicate, pagination *models.PaginationRequest, pageSize int) ([]models.Event, map[string][]models.K8sEvent, *models.ResourceCursor, error) {
+	var allEvents []models.Event
+	k8sEventsByResource := make(map[string][]models.K8sEvent)
+	matched := make(map[string]bool)
+
+	for round := 1; ; round++ {
+		cypherQuery := qe.buildTimelineQuery(startNs, endNs, filters, predicate, pagination)
+		result, err := qe.client.ExecuteQuery(ctx, cypherQuery)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to execute graph query: %w", err)
+		}
+
+		events, k8sEvents := qe.parseTimelineResults(result)
+		if predicate == nil || len(predicate.Changed) == 0 {
+			return events, k8sEvents, nil, nil
+		}
+
+		events = filterChangedResources(events, k8sEvents, predicate.Changed)
+		allEvents = append(allEvents, events...)
+		for uid, resourceEvents := range k8sEvents {
+			k8sEventsByResource[uid] = resourceEvents
+		}
+		for _, event := range events {
+			matched[event.Resource.UID] = true
+		}
+
+		last := qe.lastResourceCursor(result)
+		limit, _ := cypherQuery.Parameters["limit"].(int)
+		if len(result.Rows) < limit || last == nil || len(matched) > pageSize {
+			return allEvents, k8sEventsByResource, nil, nil
+		}
+		if round == maxChangedScanRounds {
+			return allEvents, k8sEventsByResource, last, nil
+		}
+		pagination = &models.PaginationRequest{PageSize: pageSize, Cursor: last.Encode()}
+	}
+}
+
+// lastResourceCursor returns the cursor after the last resource of a
+// timeline query result
+func (qe *QueryExecutor) lastResourceCursor(result *QueryResult) *models.ResourceCursor {
+	if len(result.Rows) == 0 || len(result.Rows[len(result.Rows)-1]) == 0 {
+		return nil
+	}
+	props, err := ParseNodeFromResult(result.Rows[len(result.Rows)-1][0])
+	if err != nil {
+		qe.logger.Warn("Failed to parse resource node: %v", err)
+		return nil
+	}
+	return models.NewResourceCursor(getStringField(props, "kind"), getStringField(props, "namespace"), getStringField(props, "name"))
+}
+
 // SetSharedCache is a no-op for graph executor (storage-only feature)
 // This method exists to satisfy the QueryExecutor interface
 func (qe *QueryExecutor) SetSharedCache(cache interface{}) {
@@ -192,8 +276,8 @@ func (qe *QueryExecutor) SetSharedCache(cache interface{}) {
 }
 
 // buildTimelineQuery constructs a Cypher query for timeline data
-// Supports multi-value filters and cursor-based pagination
-func (qe *QueryExecutor) buildTimelineQuery(startNs, endNs int64, filters models.QueryFilters, pagination *models.PaginationRequest) GraphQuery {
+// Supports multi-value filters, a compiled search query and cursor-based pagination
+func (qe *QueryExecutor) buildTimelineQuery(startNs, endNs int64, filters models.QueryFilters, predicate *searchql.Predicate, pagination *models.PaginationRequest) GraphQuery {
 	// Base query structure:
 	// 1. Match resources with filters
 	// 2. Optionally match change events in time range
@@ -227,11 +311,44 @@ func (qe *QueryExecutor) buildTimelineQuery(startNs, endNs int64, filters models
 		params["namespaces"] = namespaces
 	}
 
+	if len(filters.Clusters) > 0 {
+		whereConditions = append(whereConditions, "r.cluster IN $clusters")
+		params["clusters"] = filters.Clusters
+	}
+
 	if filters.Group != "" {
 		whereConditions = append(whereConditions, "r.apiGroup = $apiGroup")
 		params["apiGroup"] = filters.Group
 	}
 
+	// Restrict namespaced resources to the caller's tenant scope.
+	// Cluster-scoped resources stay visible and are reduced after parsing.
+	if filters.IsScoped() {
+		whereConditions = append(whereConditions, "(coalesce(r.namespace, '') = '' OR r.namespace IN $allowedNamespaces)")
+		params["allowedNamespaces"] = filters.AllowedNamespaces
+	}
+
+	// Resource conditions of the search query narrow the initial MATCH,
+	// event conditions are applied once the events are collected
+	eventCondition := ""
+	if predicate != nil {
+		whereConditions = append(whereConditions, predicate.Resource...)
+		for name, value := range predicate.Params {
+			params[name] = value
+		}
+		if predicate.Events != "" {
+			eventCondition = "AND (" + predicate.Events + ")"
+		}
+	}
+
+	// The actor filter keeps resources with a change in the window made by a
+	// matching user or service account
+	if filters.Actor != "" {
+		eventCondition += ` AND any(a_e IN inRangeEvents WHERE toLower(coalesce(a_e.actorUser, '')) CONTAINS $actor
+		                                      OR toLower(coalesce(a_e.actorServiceAccount, '')) CONTAINS $actor)`
+		params["actor"] = strings.ToLower(filters.Actor)
+	}
+
 	// Add cursor-based pagination condition
 	// The cursor encodes the last seen (kind, namespace, name)
 	// We fetch resources AFTER this position in sort order
@@ -295,19 +412,20 @@ func (qe *QueryExecutor) buildTimelineQuery(startNs, endNs int64, filters models
 		OPTIONAL MATCH (r)-[:EMITTED_EVENT]->(k:K8sEvent)
 		WHERE k.timestamp >= $startNs AND k.timestamp <= $endNs
 		WITH r, inRangeEvents, prev, collect(DISTINCT k) as k8sEvents
-		WHERE size(inRangeEvents) > 0
-		   OR (prev IS NOT NULL AND (NOT r.deleted OR r.deletedAt >= $startNs))
+		WHERE (size(inRangeEvents) > 0
+		   OR (prev IS NOT NULL AND (NOT r.deleted OR r.deletedAt >= $startNs)))
+		   %s
 		RETURN r,
 		       CASE WHEN prev IS NOT NULL THEN [prev] + inRangeEvents ELSE inRangeEvents END as events,
 		       k8sEvents,
 		       prev IS NOT NULL as hasPreExisting
 		ORDER BY r.kind, r.namespace, r.name
 		LIMIT $limit
-	`, whereClause)
+	`, whereClause, eventCondition)
 
 	qe.logger.Debug("Timeline Cypher query: %s", query)
-	qe.logger.Debug("Timeline query params: startNs=%d, endNs=%d, kinds=%v, namespaces=%v, pageSize=%d, resourceLimit=%d",
-		startNs, endNs, kinds, namespaces, pageSize, resourceLimit)
+	qe.logger.Debug("Timeline query params: startNs=%d, endNs=%d, kinds=%v, namespaces=%v, clusters=%v, allowedNamespaces=%v, pageSize=%d, resourceLimit=%d",
+		startNs, endNs, kinds, namespaces, filters.Clusters, filters.AllowedNamespaces, pageSize, resourceLimit)
 
 	return GraphQuery{
 		Query:      query,
@@ -406,6 +524,78 @@ func (qe *QueryExecutor) parseTimelineResults(result *QueryResult) ([]models.Eve
 	return events, k8sEventsByResource
 }
 
+// reduceClusterScopedEvents replaces the data of cluster-scoped resources with
+// their reduced form and strips K8sEvent messages, which frequently reference
+// namespaced workloads (e.g. Node eviction events). Used for tenant-scoped queries.
+func reduceClusterScopedEvents(events []models.Event, k8sEventsByResource map[string][]models.K8sEvent) []models.Event {
+	for i := range events {
+		if events[i].Resource.Namespace != "" {
+			continue
+		}
+		events[i].Data = models.ReduceResourceData(events[i].Data)
+
+		k8sEvents := k8sEventsByResource[events[i].Resource.UID]
+		for j := range k8sEvents {
+			k8sEvents[j].Message = ""
+			k8sEvents[j].Source = ""
+		}
+	}
+	return events
+}
+
+// filterChangedResources drops the events of resources for which one of the
+// changed: terms of a search query doesn't hold. A term holds when an event in
+// the time window changed one of its paths compared to the version before it.
+func filterChangedResources(events []models.Event, k8sEventsByResource map[string][]models.K8sEvent, changed [][]searchql.Path) []models.Event {
+	eventsByResource := make(map[string][]models.Event)
+	for _, event := range events {
+		eventsByResource[event.Resource.UID] = append(eventsByResource[event.Resource.UID], event)
+	}
+
+	matches := make(map[string]bool, len(eventsByResource))
+	for uid, resourceEvents := range eventsByResource {
+		sort.SliceStable(resourceEvents, func(i, j int) bool {
+			return resourceEvents[i].Timestamp < resourceEvents[j].Timestamp
+		})
+		matches[uid] = true
+		for _, paths := range changed {
+			if !anyPathChanged(resourceEvents, paths) {
+				matches[uid] = false
+				break
+			}
+		}
+	}
+
+	filtered := events[:0]
+	for _, event := range events {
+		if matches[event.Resource.UID] {
+			filtered = append(filtered, event)
+		}
+	}
+	for uid := range k8sEventsByResource {
+		if !matches[uid] {
+			delete(k8sEventsByResource, uid)
+		}
+	}
+	return filtered
+}
+
+// anyPathChanged reports whether an in-range event changed one of the paths.
+// Events must be sorted by timestamp.
+func anyPathChanged(events []models.Event, paths []searchql.Path) bool {
+	for i := 1; i < len(events); i++ {
+		if events[i].PreExisting || len(events[i-1].Data) == 0 || len(events[i].Data) == 0 {
+			continue
+		}
+		for _, path := range paths {
+			if searchql.Changed(events[i-1].Data, events[i].Data, path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // parseK8sEvent converts a K8sEvent graph node to a models.K8sEvent
 func (qe *QueryExecutor) parseK8sEvent(node map[string]interface{}) *models.K8sEvent {
 	eventID := getStringField(node, "id")
@@ -435,6 +625,7 @@ func (qe *QueryExecutor) parseResourceMetadata(node map[string]interface{}) mode
 		Version:   getStringField(node, "version"),
 		Namespace: getStringField(node, "namespace"),
 		Name:      getStringField(node, "name"),
+		Cluster:   getStringField(node, "cluster"),
 		// Labels would need to be parsed from JSON string
 	}
 }
@@ -474,12 +665,15 @@ func (qe *QueryExecutor) parseChangeEvent(node map[string]interface{}, resourceM
 		// or if the data field wasn't stored properly in the graph
 	}
 
+	change := ParseChangeEventFromNode(node)
 	return &models.Event{
-		ID:        eventID,
-		Timestamp: timestamp,
-		Type:      evtType,
-		Resource:  resourceMeta,
-		Data:      data, // Now populated from graph
+		ID:            eventID,
+		Timestamp:     timestamp,
+		Type:          evtType,
+		Resource:      resourceMeta,
+		Data:          data, // Now populated from graph
+		Actor:         ChangeEventActor(change),
+		FieldManagers: ChangeEventFieldManagers(change),
 	}
 }
 
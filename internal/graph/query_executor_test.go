@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedTimelineGraph creates two deployments with a scale-up and an image
+// change, and a pod that was OOM killed
+func seedTimelineGraph(t *testing.T, client Client) {
+	t.Helper()
+
+	_, err := client.ExecuteQuery(context.Background(), GraphQuery{Query: `
+		CREATE (api:ResourceIdentity {uid: 'api', kind: 'Deployment', apiGroup: 'apps', version: 'v1', namespace: 'payments', name: 'api', labels: '{"app":"api"}', deleted: false})
+		CREATE (web:ResourceIdentity {uid: 'web', kind: 'Deployment', apiGroup: 'apps', version: 'v1', namespace: 'shop', name: 'web', labels: '{"app":"web"}', deleted: false})
+		CREATE (pod:ResourceIdentity {uid: 'pod', kind: 'Pod', apiGroup: '', version: 'v1', namespace: 'payments', name: 'api-7f9c', labels: '{"app":"api"}', deleted: false})
+		CREATE (api)-[:CHANGED]->(:ChangeEvent {id: 'api-1', timestamp: 1000000000, eventType: 'CREATE', status: 'Ready', data: '{"spec":{"replicas":1,"image":"api:v1"}}'})
+		CREATE (api)-[:CHANGED]->(:ChangeEvent {id: 'api-2', timestamp: 2000000000, eventType: 'UPDATE', status: 'Ready', data: '{"spec":{"replicas":1,"image":"api:v2"}}'})
+		CREATE (web)-[:CHANGED]->(:ChangeEvent {id: 'web-1', timestamp: 1000000000, eventType: 'CREATE', status: 'Ready', data: '{"spec":{"replicas":1,"image":"web:v1"}}'})
+		CREATE (web)-[:CHANGED]->(:ChangeEvent {id: 'web-2', timestamp: 2000000000, eventType: 'UPDATE', status: 'Ready', data: '{"spec":{"replicas":3,"image":"web:v1"}}'})
+		CREATE (pod)-[:CHANGED]->(:ChangeEvent {id: 'pod-1', timestamp: 2000000000, eventType: 'UPDATE', status: 'Error', containerIssues: ['OOMKilled'], data: '{}'})
+		CREATE (pod)-[:EMITTED_EVENT]->(:K8sEvent {id: 'pod-ev', timestamp: 2000000000, reason: 'BackOff', message: 'Back-off restarting failed container', type: 'Warning'})
+	`})
+	require.NoError(t, err)
+}
+
+func TestQueryExecutor_SearchQuery(t *testing.T) {
+	client := newTestMemoryClient(t)
+	seedTimelineGraph(t, client)
+	executor := NewQueryExecutor(client)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"kind:Deployment", []string{"api", "web"}},
+		{"ns:payments label:app=api", []string{"api", "pod"}},
+		{"status:error issue:OOMKilled", []string{"pod"}},
+		{`reason~backoff OR msg~"back-off"`, []string{"pod"}},
+		{"-kind:Pod (api OR web)", []string{"api", "web"}},
+		{"changed:spec.image", []string{"api"}},
+		{"changed:spec.replicas,spec.image kind:Deployment", []string{"api", "web"}},
+		{"changed:spec.replicas ns:payments", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			result, err := executor.Execute(context.Background(), &models.QueryRequest{
+				StartTimestamp: 0,
+				EndTimestamp:   10,
+				Filters:        models.QueryFilters{Query: tt.query},
+			})
+			require.NoError(t, err)
+
+			var uids []string
+			seen := make(map[string]bool)
+			for _, event := range result.Events {
+				if !seen[event.Resource.UID] {
+					seen[event.Resource.UID] = true
+					uids = append(uids, event.Resource.UID)
+				}
+			}
+			assert.ElementsMatch(t, tt.expected, uids)
+		})
+	}
+}
+
+func TestQueryExecutor_InvalidSearchQuery(t *testing.T) {
+	executor := NewQueryExecutor(newTestMemoryClient(t))
+
+	_, err := executor.Execute(context.Background(), &models.QueryRequest{
+		StartTimestamp: 0,
+		EndTimestamp:   10,
+		Filters:        models.QueryFilters{Query: "status:Broken"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown status")
+}
+
+func TestQueryExecutor_ActorFilter(t *testing.T) {
+	client := newTestMemoryClient(t)
+	seedTimelineGraph(t, client)
+	_, err := client.ExecuteQuery(context.Background(), GraphQuery{Query: `
+		MATCH (e:ChangeEvent {id: 'api-2'})
+		SET e.actorUser = 'alice@example.com', e.actorUserAgent = 'kubectl/v1.30.1'
+	`})
+	require.NoError(t, err)
+	_, err = client.ExecuteQuery(context.Background(), GraphQuery{Query: `
+		MATCH (e:ChangeEvent {id: 'web-2'})
+		SET e.actorUser = 'system:serviceaccount:argocd:argocd-application-controller',
+		    e.actorServiceAccount = 'argocd/argocd-application-controller'
+	`})
+	require.NoError(t, err)
+	executor := NewQueryExecutor(client)
+
+	tests := []struct {
+		actor    string
+		start    int64
+		expected []string
+	}{
+		{"Alice", 0, []string{"api"}},
+		{"argocd/", 0, []string{"web"}},
+		{"example.com", 3, nil}, // the change precedes the window
+		{"bob", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.actor, func(t *testing.T) {
+			result, err := executor.Execute(context.Background(), &models.QueryRequest{
+				StartTimestamp: tt.start,
+				EndTimestamp:   10,
+				Filters:        models.QueryFilters{Actor: tt.actor},
+			})
+			require.NoError(t, err)
+
+			seen := make(map[string]bool)
+			var uids []string
+			for _, event := range result.Events {
+				if !seen[event.Resource.UID] {
+					seen[event.Resource.UID] = true
+					uids = append(uids, event.Resource.UID)
+				}
+				if event.ID == "api-2" {
+					require.NotNil(t, event.Actor)
+					assert.Equal(t, "kubectl/v1.30.1", event.Actor.UserAgent)
+				}
+			}
+			assert.ElementsMatch(t, tt.expected, uids)
+		})
+	}
+}
+
+func TestQueryExecutor_ChangedPagination(t *testing.T) {
+	client := newTestMemoryClient(t)
+
+	// Only every fifth of 20 deployments changed its image, so a single query
+	// for a page of two returns fewer matches than the page holds
+	var changed []string
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("app-%02d", i)
+		image := "v1"
+		if i%5 == 4 {
+			image = "v2"
+			changed = append(changed, name)
+		}
+		_, err := client.ExecuteQuery(context.Background(), GraphQuery{
+			Query: `
+				CREATE (r:ResourceIdentity {uid: $name, kind: 'Deployment', apiGroup: 'apps', version: 'v1', namespace: 'default', name: $name, deleted: false})
+				CREATE (r)-[:CHANGED]->(:ChangeEvent {id: $name + '-1', timestamp: 1000000000, eventType: 'CREATE', status: 'Ready', data: '{"spec":{"image":"v1"}}'})
+				CREATE (r)-[:CHANGED]->(:ChangeEvent {id: $name + '-2', timestamp: 2000000000, eventType: 'UPDATE', status: 'Ready', data: $data})
+			`,
+			Parameters: map[string]interface{}{"name": name, "data": `{"spec":{"image":"` + image + `"}}`},
+		})
+		require.NoError(t, err)
+	}
+	executor := NewQueryExecutor(client)
+
+	var names []string
+	pagination := &models.PaginationRequest{PageSize: 2}
+	for page := 0; page < 5; page++ {
+		result, resp, err := executor.ExecutePaginated(context.Background(), &models.QueryRequest{
+			StartTimestamp: 0,
+			EndTimestamp:   10,
+			Filters:        models.QueryFilters{Query: "changed:spec.image"},
+		}, pagination)
+		require.NoError(t, err)
+
+		seen := make(map[string]bool)
+		for _, event := range result.Events {
+			if !seen[event.Resource.Name] {
+				seen[event.Resource.Name] = true
+				names = append(names, event.Resource.Name)
+			}
+		}
+		assert.Len(t, seen, 2, "page %d", page)
+
+		if !resp.HasMore {
+			break
+		}
+		pagination = &models.PaginationRequest{PageSize: 2, Cursor: resp.NextCursor}
+	}
+	assert.Equal(t, changed, names)
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/moolen/spectre/internal/graph/cypher"
+)
+
+// ErrWriteQuery is returned for queries that would modify the graph
+var ErrWriteQuery = errors.New("query modifies the graph")
+
+// ParseReadOnlyQuery parses a query supplied by a user. It fails with
+// ErrWriteQuery when the query contains a write clause or calls a procedure
+// that may modify the graph, and with a syntax error when the query is
+// outside of the Cypher subset Spectre understands.
+func ParseReadOnlyQuery(query string) (*cypher.Query, error) {
+	parsed, err := cypher.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	if !parsed.IsReadOnly() {
+		return nil, ErrWriteQuery
+	}
+	return parsed, nil
+}
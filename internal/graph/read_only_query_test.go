@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseReadOnlyQuery tests the rejection of queries modifying the graph
+func TestParseReadOnlyQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		err   error
+	}{
+		{"MATCH (n:ResourceIdentity) WHERE NOT n.deleted RETURN n", nil},
+		{"MATCH (n:ResourceIdentity) WHERE n.name = 'app-settings' RETURN n.deletedAt", nil},
+		{"CALL db.idx.fulltext.queryNodes('K8sEvent', 'oom') YIELD node RETURN node", nil},
+		{"MATCH (e:ChangeEvent) WHERE e.eventType = 'DELETE' RETURN e.id", nil},
+		{"MATCH (n:ResourceIdentity) WHERE n.name = 'SET' OR n.name = 'create me' RETURN n.uid", nil},
+		{"MATCH (n) DETACH DELETE n", ErrWriteQuery},
+		{"MATCH (n) set n.name = 'x'", ErrWriteQuery},
+		{"CALL db.idx.fulltext.drop('K8sEvent')", ErrWriteQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseReadOnlyQuery(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseReadOnlyQuery(%q) error = %v, want %v", tt.query, err, tt.err)
+			}
+		})
+	}
+
+	if _, err := ParseReadOnlyQuery("MATCH (n RETURN n"); err == nil {
+		t.Error("ParseReadOnlyQuery should reject invalid queries")
+	}
+}
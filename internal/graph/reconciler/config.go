@@ -12,6 +12,10 @@ type Config struct {
 
 	// BatchSize limits resources checked per cycle per handler.
 	BatchSize int
+
+	// Cluster limits reconciliation to resources of the local cluster when
+	// several clusters share the graph. Empty means untagged resources.
+	Cluster string
 }
 
 // DefaultConfig returns the default reconciler configuration.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -78,7 +79,8 @@ func (p *PodTerminationReconciler) Reconcile(ctx context.Context, input Reconcil
 		for _, resource := range resources {
 			output.ResourcesChecked++
 
-			if existingPods[resource.UID] {
+			// Graph UIDs may be qualified with the cluster name
+			if existingPods[models.LocalUID(resource.UID)] {
 				output.ResourcesStillExist = append(output.ResourcesStillExist, resource.UID)
 			} else {
 				p.logger.Info("Pod %s/%s (UID: %s) no longer exists in Kubernetes, marking as deleted",
@@ -207,13 +207,15 @@ func (r *Reconciler) fetchResourcesForHandler(ctx context.Context, handler Recon
 			MATCH (r:ResourceIdentity)
 			WHERE r.kind = $kind
 			  AND (r.deleted = false OR r.deleted IS NULL)
+			  AND coalesce(r.cluster, '') = $cluster
 			RETURN r.uid as uid, r.kind as kind, r.apiGroup as apiGroup,
 			       r.namespace as namespace, r.name as name
 			LIMIT $limit
 		`,
 		Parameters: map[string]interface{}{
-			"kind":  handler.ResourceKind(),
-			"limit": r.config.BatchSize,
+			"kind":    handler.ResourceKind(),
+			"cluster": r.config.Cluster,
+			"limit":   r.config.BatchSize,
 		},
 	}
 
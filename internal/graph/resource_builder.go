@@ -92,6 +92,7 @@ func (rb *ResourceBuilder) BuildResourcesFromChangeEvents(
 			Kind:      resNode.Kind,
 			Namespace: resNode.Namespace,
 			Name:      resNode.Name,
+			Cluster:   resNode.Cluster,
 		}
 
 		// Build status segments
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/FalkorDB/falkordb-go/v2"
+	"github.com/moolen/spectre/internal/models"
 )
 
 // ParseNodeFromResult extracts node properties from a FalkorDB query result value
@@ -70,6 +71,9 @@ func ParseResourceIdentityFromNode(props map[string]interface{}) ResourceIdentit
 	if namespace, ok := props["namespace"].(string); ok {
 		resource.Namespace = namespace
 	}
+	if cluster, ok := props["cluster"].(string); ok {
+		resource.Cluster = cluster
+	}
 	if name, ok := props["name"].(string); ok {
 		resource.Name = name
 	}
@@ -155,10 +159,62 @@ func ParseChangeEventFromNode(props map[string]interface{}) ChangeEvent {
 	if data, ok := props["data"].(string); ok {
 		event.Data = data
 	}
+	if resourceVersion, ok := props["resourceVersion"].(string); ok {
+		event.ResourceVersion = resourceVersion
+	}
+
+	// Actor, set by audit event correlation
+	event.ActorUser, _ = props["actorUser"].(string)
+	event.ActorServiceAccount, _ = props["actorServiceAccount"].(string)
+	event.ActorUserAgent, _ = props["actorUserAgent"].(string)
+	event.ActorSourceIP, _ = props["actorSourceIP"].(string)
+	event.FieldManagers, _ = props["fieldManagers"].(string)
+
+	// Compaction summary
+	switch count := props["compactedCount"].(type) {
+	case int64:
+		event.CompactedCount = int(count)
+	case float64:
+		event.CompactedCount = int(count)
+	}
+	switch firstTimestamp := props["firstTimestamp"].(type) {
+	case int64:
+		event.FirstTimestamp = firstTimestamp
+	case float64:
+		event.FirstTimestamp = int64(firstTimestamp)
+	}
 
 	return event
 }
 
+// ChangeEventActor returns who made a change, or nil if no audit event was
+// correlated with it
+func ChangeEventActor(event ChangeEvent) *models.Actor {
+	actor := &models.Actor{
+		User:           event.ActorUser,
+		ServiceAccount: event.ActorServiceAccount,
+		UserAgent:      event.ActorUserAgent,
+		SourceIP:       event.ActorSourceIP,
+	}
+	if actor.IsEmpty() {
+		return nil
+	}
+	return actor
+}
+
+// ChangeEventFieldManagers decodes the field manager summary of a change, or
+// returns nil if none was recorded
+func ChangeEventFieldManagers(event ChangeEvent) map[string]string {
+	if event.FieldManagers == "" {
+		return nil
+	}
+	var managers map[string]string
+	if err := json.Unmarshal([]byte(event.FieldManagers), &managers); err != nil {
+		return nil
+	}
+	return managers
+}
+
 // ParseTriggeredByEdge extracts TRIGGERED_BY edge properties
 func ParseTriggeredByEdge(props map[string]interface{}) TriggeredByEdge {
 	edge := TriggeredByEdge{}
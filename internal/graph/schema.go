@@ -40,6 +40,7 @@ func UpsertResourceIdentityQuery(resource ResourceIdentity) GraphQuery {
 	query := `
 		MERGE (r:ResourceIdentity {uid: $uid})
 		ON CREATE SET
+			r.cluster = $cluster,
 			r.kind = $kind,
 			r.apiGroup = $apiGroup,
 			r.version = $version,
@@ -57,6 +58,7 @@ func UpsertResourceIdentityQuery(resource ResourceIdentity) GraphQuery {
 		// This is a deletion - always update to mark as deleted
 		query += `
 		ON MATCH SET
+			r.cluster = CASE WHEN r.cluster IS NULL THEN $cluster ELSE r.cluster END,
 			r.kind = CASE WHEN r.kind IS NULL THEN $kind ELSE r.kind END,
 			r.apiGroup = CASE WHEN r.apiGroup IS NULL THEN $apiGroup ELSE r.apiGroup END,
 			r.version = CASE WHEN r.version IS NULL THEN $version ELSE r.version END,
@@ -73,6 +75,7 @@ func UpsertResourceIdentityQuery(resource ResourceIdentity) GraphQuery {
 		// Also populate core properties if they were not set (placeholder node from OWNS edge creation)
 		query += `
 		ON MATCH SET
+			r.cluster = CASE WHEN r.cluster IS NULL THEN $cluster ELSE r.cluster END,
 			r.kind = CASE WHEN r.kind IS NULL THEN $kind ELSE r.kind END,
 			r.apiGroup = CASE WHEN r.apiGroup IS NULL THEN $apiGroup ELSE r.apiGroup END,
 			r.version = CASE WHEN r.version IS NULL THEN $version ELSE r.version END,
@@ -88,6 +91,7 @@ func UpsertResourceIdentityQuery(resource ResourceIdentity) GraphQuery {
 		Query: query,
 		Parameters: map[string]interface{}{
 			"uid":       resource.UID,
+			"cluster":   resource.Cluster,
 			"kind":      resource.Kind,
 			"apiGroup":  resource.APIGroup,
 			"version":   resource.Version,
@@ -111,6 +115,7 @@ func CreateChangeEventQuery(event ChangeEvent) GraphQuery {
 		Query: `
 			MERGE (e:ChangeEvent {id: $id})
 			ON CREATE SET
+				e.cluster = $cluster,
 				e.timestamp = $timestamp,
 				e.eventType = $eventType,
 				e.status = $status,
@@ -120,10 +125,17 @@ func CreateChangeEventQuery(event ChangeEvent) GraphQuery {
 				e.statusChanged = $statusChanged,
 				e.replicasChanged = $replicasChanged,
 				e.impactScore = $impactScore,
-				e.data = $data
+				e.data = $data,
+				e.resourceVersion = $resourceVersion,
+				e.actorUser = $actorUser,
+				e.actorServiceAccount = $actorServiceAccount,
+				e.actorUserAgent = $actorUserAgent,
+				e.actorSourceIP = $actorSourceIP,
+				e.fieldManagers = $fieldManagers
 		`,
 		Parameters: map[string]interface{}{
 			"id":              event.ID,
+			"cluster":         event.Cluster,
 			"timestamp":       event.Timestamp,
 			"eventType":       event.EventType,
 			"status":          event.Status,
@@ -134,6 +146,13 @@ func CreateChangeEventQuery(event ChangeEvent) GraphQuery {
 			"replicasChanged": event.ReplicasChanged,
 			"impactScore":     event.ImpactScore,
 			"data":            event.Data,
+			"resourceVersion": event.ResourceVersion,
+
+			"actorUser":           event.ActorUser,
+			"actorServiceAccount": event.ActorServiceAccount,
+			"actorUserAgent":      event.ActorUserAgent,
+			"actorSourceIP":       event.ActorSourceIP,
+			"fieldManagers":       event.FieldManagers,
 		},
 	}
 }
@@ -144,6 +163,7 @@ func CreateK8sEventQuery(event K8sEvent) GraphQuery {
 		Query: `
 			MERGE (e:K8sEvent {id: $id})
 			ON CREATE SET
+				e.cluster = $cluster,
 				e.timestamp = $timestamp,
 				e.reason = $reason,
 				e.message = $message,
@@ -153,6 +173,7 @@ func CreateK8sEventQuery(event K8sEvent) GraphQuery {
 		`,
 		Parameters: map[string]interface{}{
 			"id":        event.ID,
+			"cluster":   event.Cluster,
 			"timestamp": event.Timestamp,
 			"reason":    event.Reason,
 			"message":   event.Message,
@@ -457,6 +478,21 @@ func FindChangeEventsByResourceQuery(resourceUID string, startTime, endTime int6
 	}
 }
 
+// FindLatestChangeEventQuery returns the most recent ChangeEvent of a resource
+func FindLatestChangeEventQuery(resourceUID string) GraphQuery {
+	return GraphQuery{
+		Query: `
+			MATCH (r:ResourceIdentity {uid: $resourceUID})-[:CHANGED]->(e:ChangeEvent)
+			RETURN e
+			ORDER BY e.timestamp DESC
+			LIMIT 1
+		`,
+		Parameters: map[string]interface{}{
+			"resourceUID": resourceUID,
+		},
+	}
+}
+
 // FindRootCauseQuery traces backward from a failure to find likely root causes
 func FindRootCauseQuery(resourceUID string, failureTimestamp int64, maxDepth int, minConfidence float64) GraphQuery {
 	// Allow 5 minute tolerance for timestamp matching (increased from 1 minute)
@@ -524,14 +560,29 @@ func FindRootCauseQuery(resourceUID string, failureTimestamp int64, maxDepth int
 	}
 }
 
-// CalculateBlastRadiusQuery finds all resources affected by a change
-func CalculateBlastRadiusQuery(resourceUID string, changeTimestamp, timeWindowMs int64, relationshipTypes []string) GraphQuery {
+// BlastRadiusQueryLimit is the maximum number of paths returned by
+// CalculateBlastRadiusQuery
+const BlastRadiusQueryLimit = 2000
+
+// CalculateBlastRadiusQuery finds resources within three hops of a changed
+// resource that changed themselves within the time window after the change.
+// Impact propagates along forwardTypes from an edge's source to its target and
+// along reverseTypes from its target to its source; only paths whose every hop
+// propagates are returned. Each row carries the path's node UIDs, edge types
+// and edge source UIDs. Rows are ordered by distance, then by first change.
+func CalculateBlastRadiusQuery(resourceUID string, changeTimestamp, timeWindowMs int64, forwardTypes, reverseTypes []string) GraphQuery {
 	// Convert relationship types to Cypher pattern
+	seen := make(map[string]bool)
 	relPattern := ""
-	if len(relationshipTypes) > 0 {
-		relPattern = ":" + relationshipTypes[0]
-		for i := 1; i < len(relationshipTypes); i++ {
-			relPattern += "|" + relationshipTypes[i]
+	for _, relType := range append(append([]string{}, forwardTypes...), reverseTypes...) {
+		if seen[relType] {
+			continue
+		}
+		seen[relType] = true
+		if relPattern == "" {
+			relPattern = ":" + relType
+		} else {
+			relPattern += "|" + relType
 		}
 	}
 
@@ -539,17 +590,24 @@ func CalculateBlastRadiusQuery(resourceUID string, changeTimestamp, timeWindowMs
 
 	query := fmt.Sprintf(`
 		MATCH (triggerResource:ResourceIdentity {uid: $resourceUID})
-		      -[:CHANGED]->(triggerEvent:ChangeEvent)
-		WHERE triggerEvent.timestamp = $changeTimestamp
+		MATCH path = (triggerResource)-[%s*1..3]-(impacted:ResourceIdentity)
+		WHERE impacted.uid <> $resourceUID
+		WITH impacted,
+		     [n IN nodes(path) | n.uid] AS pathUIDs,
+		     [r IN relationships(path) | type(r)] AS relTypes,
+		     [r IN relationships(path) | startNode(r).uid] AS relSources
+		WHERE ALL(i IN range(0, size(relTypes) - 1) WHERE
+		      (relSources[i] = pathUIDs[i] AND relTypes[i] IN $forwardTypes) OR
+		      (relSources[i] <> pathUIDs[i] AND relTypes[i] IN $reverseTypes))
 
-		MATCH (triggerResource)-[rel%s*1..3]->(impacted:ResourceIdentity)
 		MATCH (impacted)-[:CHANGED]->(impactEvent:ChangeEvent)
-		WHERE impactEvent.timestamp > $changeTimestamp
-		  AND impactEvent.timestamp < $changeTimestamp + $timeWindowNs
-		  AND (impactEvent.status = 'Warning' OR impactEvent.status = 'Error')
+		WHERE impactEvent.timestamp >= $changeTimestamp
+		  AND impactEvent.timestamp <= $changeTimestamp + $timeWindowNs
+		WITH impacted, pathUIDs, relTypes, relSources, min(impactEvent.timestamp) AS firstChange
 
-		RETURN impacted, impactEvent, type(rel) as relType, length(relationships(rel)) as distance
-		ORDER BY impactEvent.timestamp
+		RETURN impacted, pathUIDs, relTypes, relSources, firstChange
+		ORDER BY size(relTypes), firstChange
+		LIMIT $limit
 	`, relPattern)
 
 	return GraphQuery{
@@ -558,6 +616,9 @@ func CalculateBlastRadiusQuery(resourceUID string, changeTimestamp, timeWindowMs
 			"resourceUID":     resourceUID,
 			"changeTimestamp": changeTimestamp,
 			"timeWindowNs":    timeWindowNs,
+			"forwardTypes":    forwardTypes,
+			"reverseTypes":    reverseTypes,
+			"limit":           BlastRadiusQueryLimit,
 		},
 	}
 }
@@ -207,9 +207,10 @@ func TestFindRootCauseQuery(t *testing.T) {
 func TestCalculateBlastRadiusQuery(t *testing.T) {
 	changeTimestamp := int64(1703001000000000000)
 	timeWindowMs := int64(300000) // 5 minutes
-	relationshipTypes := []string{"OWNS", "SELECTS"}
+	forwardTypes := []string{"OWNS", "SELECTS"}
+	reverseTypes := []string{"MOUNTS", "SELECTS"}
 
-	query := CalculateBlastRadiusQuery("node-123", changeTimestamp, timeWindowMs, relationshipTypes)
+	query := CalculateBlastRadiusQuery("node-123", changeTimestamp, timeWindowMs, forwardTypes, reverseTypes)
 
 	// Check query structure
 	assert.Contains(t, query.Query, "MATCH")
@@ -225,6 +226,9 @@ func TestCalculateBlastRadiusQuery(t *testing.T) {
 	assert.Equal(t, "node-123", query.Parameters["resourceUID"])
 	assert.Equal(t, changeTimestamp, query.Parameters["changeTimestamp"])
 	assert.Equal(t, timeWindowMs*1_000_000, query.Parameters["timeWindowNs"])
+	assert.Equal(t, forwardTypes, query.Parameters["forwardTypes"])
+	assert.Equal(t, reverseTypes, query.Parameters["reverseTypes"])
+	assert.Contains(t, query.Query, ":OWNS|SELECTS|MOUNTS*1..3")
 }
 
 func TestDeleteOldChangeEventsQuery(t *testing.T) {
@@ -0,0 +1,192 @@
+package searchql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Variables names the Cypher variables a query is compiled against
+type Variables struct {
+	Resource     string // ResourceIdentity node
+	ChangeEvents string // list of ChangeEvent nodes in the time window
+	K8sEvents    string // list of K8sEvent nodes in the time window
+}
+
+// Predicate is a compiled query
+type Predicate struct {
+	// Resource holds conditions that only reference the resource variable.
+	// They all have to hold and can be applied before events are collected.
+	Resource []string
+
+	// Events is the remaining condition, which references the event lists.
+	// Empty when the query has no such terms.
+	Events string
+
+	// Params holds the values referenced by Resource and Events
+	Params map[string]interface{}
+
+	// Changed lists the changed: terms. Each entry holds when any of its
+	// paths changed between two consecutive versions of the resource; the
+	// graph only stores whole objects, so callers check these with Changed
+	// on the returned events.
+	Changed [][]Path
+}
+
+// Compile translates the query into Cypher conditions. Parameter names start
+// with paramPrefix so that they don't clash with the caller's parameters.
+func (q *Query) Compile(vars Variables, paramPrefix string) *Predicate {
+	c := &compiler{
+		vars:   vars,
+		prefix: paramPrefix,
+		pred:   &Predicate{Params: make(map[string]interface{})},
+	}
+
+	var top []Expr
+	if and, ok := q.Expr.(*And); ok {
+		top = and.Exprs
+	} else {
+		top = []Expr{q.Expr}
+	}
+
+	var events []string
+	for _, e := range top {
+		if t, ok := e.(*Term); ok && t.Field == "changed" {
+			paths := make([]Path, len(t.Values))
+			for i, v := range t.Values {
+				paths[i] = ParsePath(v)
+			}
+			c.pred.Changed = append(c.pred.Changed, paths)
+		}
+
+		cond := c.expr(e)
+		if c.resourceOnly(e) {
+			c.pred.Resource = append(c.pred.Resource, cond)
+		} else {
+			events = append(events, cond)
+		}
+	}
+	c.pred.Events = strings.Join(events, " AND ")
+	return c.pred
+}
+
+type compiler struct {
+	vars   Variables
+	prefix string
+	pred   *Predicate
+}
+
+// param registers a value and returns its placeholder
+func (c *compiler) param(value interface{}) string {
+	name := fmt.Sprintf("%s%d", c.prefix, len(c.pred.Params))
+	c.pred.Params[name] = value
+	return "$" + name
+}
+
+func (c *compiler) resourceOnly(e Expr) bool {
+	only := true
+	walk(e, func(t *Term) {
+		if fields[t.Field].scope != ScopeResource {
+			only = false
+		}
+	})
+	return only
+}
+
+func (c *compiler) expr(e Expr) string {
+	switch e := e.(type) {
+	case *Term:
+		return c.term(e)
+	case *Not:
+		return "NOT (" + c.expr(e.Expr) + ")"
+	case *And:
+		return c.join(e.Exprs, " AND ")
+	case *Or:
+		return c.join(e.Exprs, " OR ")
+	}
+	return "true"
+}
+
+func (c *compiler) join(exprs []Expr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = c.expr(e)
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+func (c *compiler) term(t *Term) string {
+	f := fields[t.Field]
+
+	switch f.scope {
+	case ScopeChangeEvent:
+		if f.kind == valuePath {
+			// Only updates can change a field; the exact check runs on the results
+			return fmt.Sprintf("any(sq_e IN %s WHERE sq_e.eventType = 'UPDATE')", c.vars.ChangeEvents)
+		}
+		return fmt.Sprintf("any(sq_e IN %s WHERE %s)", c.vars.ChangeEvents, c.compare("sq_e", f, t))
+	case ScopeK8sEvent:
+		return fmt.Sprintf("any(sq_k IN %s WHERE %s)", c.vars.K8sEvents, c.compare("sq_k", f, t))
+	default:
+		return c.compare(c.vars.Resource, f, t)
+	}
+}
+
+// compare builds the condition of a term on the node held by variable
+func (c *compiler) compare(variable string, f field, t *Term) string {
+	property := variable + "." + f.property
+
+	switch f.kind {
+	case valueBool:
+		values := make([]bool, len(t.Values))
+		for i, v := range t.Values {
+			values[i] = v == "true"
+		}
+		expr := fmt.Sprintf("coalesce(%s, false)", property)
+		if len(values) == 1 {
+			return fmt.Sprintf("%s = %s", expr, c.param(values[0]))
+		}
+		return fmt.Sprintf("%s IN %s", expr, c.param(values))
+	case valueLabel:
+		// Labels are stored as a JSON object, so match the encoded pair
+		conds := make([]string, len(t.Values))
+		for i, v := range t.Values {
+			key, value, hasValue := strings.Cut(v, "=")
+			encodedKey, _ := json.Marshal(key)
+			pattern := string(encodedKey) + ":"
+			if hasValue {
+				encodedValue, _ := json.Marshal(value)
+				pattern += string(encodedValue)
+			}
+			conds[i] = fmt.Sprintf("coalesce(%s, '') CONTAINS %s", property, c.param(pattern))
+		}
+		return anyOf(conds)
+	case valueList:
+		return fmt.Sprintf("any(sq_i IN coalesce(%s, []) WHERE %s)", property, c.match("sq_i", t))
+	default:
+		return c.match(fmt.Sprintf("coalesce(%s, '')", property), t)
+	}
+}
+
+// match compares a string expression with the values of a term
+func (c *compiler) match(expr string, t *Term) string {
+	if t.Op == OpContains {
+		conds := make([]string, len(t.Values))
+		for i, v := range t.Values {
+			conds[i] = fmt.Sprintf("toLower(%s) CONTAINS %s", expr, c.param(strings.ToLower(v)))
+		}
+		return anyOf(conds)
+	}
+
+	if len(t.Values) == 1 {
+		return fmt.Sprintf("%s = %s", expr, c.param(t.Values[0]))
+	}
+	return fmt.Sprintf("%s IN %s", expr, c.param(append([]string(nil), t.Values...)))
+}
+
+func anyOf(conds []string) string {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+	return "(" + strings.Join(conds, " OR ") + ")"
+}
@@ -0,0 +1,69 @@
+package searchql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testVariables = Variables{Resource: "r", ChangeEvents: "events", K8sEvents: "k8s"}
+
+func compile(t *testing.T, query string) *Predicate {
+	t.Helper()
+
+	q, err := Parse(query)
+	require.NoError(t, err)
+	return q.Compile(testVariables, "q")
+}
+
+func TestCompile_ResourceTerms(t *testing.T) {
+	pred := compile(t, "kind:Pod,Deployment ns~pay label:app=api -deleted:true")
+
+	assert.Equal(t, []string{
+		"coalesce(r.kind, '') IN $q0",
+		"toLower(coalesce(r.namespace, '')) CONTAINS $q1",
+		"coalesce(r.labels, '') CONTAINS $q2",
+		"NOT (coalesce(r.deleted, false) = $q3)",
+	}, pred.Resource)
+	assert.Empty(t, pred.Events)
+	assert.Equal(t, map[string]interface{}{
+		"q0": []string{"Pod", "Deployment"},
+		"q1": "pay",
+		"q2": `"app":"api"`,
+		"q3": true,
+	}, pred.Params)
+}
+
+func TestCompile_EventTerms(t *testing.T) {
+	pred := compile(t, "kind:Pod (status:Error OR issue:OOMKilled) reason~OOM")
+
+	assert.Equal(t, []string{"coalesce(r.kind, '') = $q0"}, pred.Resource)
+	assert.Equal(t,
+		"(any(sq_e IN events WHERE coalesce(sq_e.status, '') = $q1) OR "+
+			"any(sq_e IN events WHERE any(sq_i IN coalesce(sq_e.containerIssues, []) WHERE sq_i = $q2))) AND "+
+			"any(sq_k IN k8s WHERE toLower(coalesce(sq_k.reason, '')) CONTAINS $q3)",
+		pred.Events)
+	assert.Equal(t, "oom", pred.Params["q3"])
+}
+
+func TestCompile_Changed(t *testing.T) {
+	pred := compile(t, "changed:spec.replicas,spec.template.spec.containers.image")
+
+	assert.Equal(t, "any(sq_e IN events WHERE sq_e.eventType = 'UPDATE')", pred.Events)
+	assert.Equal(t, [][]Path{{
+		{"spec", "replicas"},
+		{"spec", "template", "spec", "containers", "image"},
+	}}, pred.Changed)
+}
+
+func TestChanged(t *testing.T) {
+	before := []byte(`{"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"a","image":"v1"},{"name":"b","image":"v1"}]}}}}`)
+	after := []byte(`{"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"a","image":"v1"},{"name":"b","image":"v2"}]}}}}`)
+
+	assert.False(t, Changed(before, after, ParsePath("spec.replicas")))
+	assert.True(t, Changed(before, after, ParsePath("spec.template.spec.containers.image")))
+	assert.False(t, Changed(before, after, ParsePath("spec.template.spec.containers.name")))
+	assert.True(t, Changed(before, []byte(`{"spec":{}}`), ParsePath("spec.replicas")))
+	assert.False(t, Changed(before, []byte("not json"), ParsePath("spec.replicas")))
+}
@@ -0,0 +1,170 @@
+package searchql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Record holds a resource and its events as property maps keyed like the
+// graph node properties, for matching a query without the graph
+type Record struct {
+	Resource     map[string]interface{}   // ResourceIdentity properties
+	ChangeEvents []map[string]interface{} // ChangeEvent properties, oldest first
+	K8sEvents    []map[string]interface{} // K8sEvent properties
+
+	// Previous is the JSON data of the resource before the first change
+	// event. A changed: term can only hold for the first event when it is set.
+	Previous []byte
+}
+
+// Match reports whether the record matches the query. It follows the
+// semantics of Compile, except that changed: terms are checked exactly by
+// comparing the data of consecutive change events.
+func (q *Query) Match(r *Record) bool {
+	return matchExpr(q.Expr, r)
+}
+
+func matchExpr(e Expr, r *Record) bool {
+	switch e := e.(type) {
+	case *Term:
+		return matchTerm(e, r)
+	case *Not:
+		return !matchExpr(e.Expr, r)
+	case *And:
+		for _, child := range e.Exprs {
+			if !matchExpr(child, r) {
+				return false
+			}
+		}
+		return true
+	case *Or:
+		for _, child := range e.Exprs {
+			if matchExpr(child, r) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchTerm(t *Term, r *Record) bool {
+	f := fields[t.Field]
+
+	switch f.scope {
+	case ScopeChangeEvent:
+		if f.kind == valuePath {
+			return matchChanged(t, r)
+		}
+		for _, event := range r.ChangeEvents {
+			if compareProperty(event, f, t) {
+				return true
+			}
+		}
+		return false
+	case ScopeK8sEvent:
+		for _, event := range r.K8sEvents {
+			if compareProperty(event, f, t) {
+				return true
+			}
+		}
+		return false
+	default:
+		return compareProperty(r.Resource, f, t)
+	}
+}
+
+// matchChanged reports whether an update changed any of the term's paths
+func matchChanged(t *Term, r *Record) bool {
+	before := r.Previous
+	for _, event := range r.ChangeEvents {
+		after := []byte(stringProperty(event, "data"))
+		if stringProperty(event, "eventType") == "UPDATE" && before != nil {
+			for _, v := range t.Values {
+				if Changed(before, after, ParsePath(v)) {
+					return true
+				}
+			}
+		}
+		before = after
+	}
+	return false
+}
+
+// compareProperty mirrors compiler.compare on a property map
+func compareProperty(props map[string]interface{}, f field, t *Term) bool {
+	switch f.kind {
+	case valueBool:
+		value, _ := props[f.property].(bool)
+		for _, v := range t.Values {
+			if value == (v == "true") {
+				return true
+			}
+		}
+		return false
+	case valueLabel:
+		labels := stringProperty(props, f.property)
+		for _, v := range t.Values {
+			key, value, hasValue := strings.Cut(v, "=")
+			encodedKey, _ := json.Marshal(key)
+			pattern := string(encodedKey) + ":"
+			if hasValue {
+				encodedValue, _ := json.Marshal(value)
+				pattern += string(encodedValue)
+			}
+			if strings.Contains(labels, pattern) {
+				return true
+			}
+		}
+		return false
+	case valueList:
+		for _, item := range listProperty(props, f.property) {
+			if matchValue(item, t) {
+				return true
+			}
+		}
+		return false
+	default:
+		return matchValue(stringProperty(props, f.property), t)
+	}
+}
+
+// matchValue mirrors compiler.match
+func matchValue(s string, t *Term) bool {
+	for _, v := range t.Values {
+		if t.Op == OpContains {
+			if strings.Contains(strings.ToLower(s), strings.ToLower(v)) {
+				return true
+			}
+		} else if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func stringProperty(props map[string]interface{}, name string) string {
+	switch v := props[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func listProperty(props map[string]interface{}, name string) []string {
+	switch v := props[name].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return items
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package searchql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery_Match(t *testing.T) {
+	record := &Record{
+		Resource: map[string]interface{}{
+			"kind": "Pod", "namespace": "payments", "name": "api-7f9c",
+			"labels": `{"app":"api"}`, "deleted": false,
+		},
+		ChangeEvents: []map[string]interface{}{
+			{"eventType": "UPDATE", "status": "Ready", "data": `{"spec":{"image":"v1"}}`},
+			{"eventType": "UPDATE", "status": "Error", "containerIssues": []string{"OOMKilled"}, "data": `{"spec":{"image":"v2"}}`},
+		},
+		K8sEvents: []map[string]interface{}{
+			{"reason": "BackOff", "message": "Back-off restarting failed container", "type": "Warning"},
+		},
+	}
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"kind:Pod ns:payments", true},
+		{"kind:Deployment", false},
+		{"label:app=api -deleted:true", true},
+		{"label:app=web", false},
+		{"status:error issue:OOMKilled", true},
+		{"status:Terminating", false},
+		{`reason~backoff OR msg~"no such"`, true},
+		{"type:Normal", false},
+		{"-kind:Pod (api OR web)", false},
+		{"api", true},
+		{"changed:spec.image", true},
+		{"changed:spec.replicas", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, q.Match(record))
+		})
+	}
+}
+
+func TestQuery_MatchChangedNeedsPrevious(t *testing.T) {
+	q, err := Parse("changed:spec.image")
+	require.NoError(t, err)
+
+	record := &Record{ChangeEvents: []map[string]interface{}{
+		{"eventType": "UPDATE", "data": `{"spec":{"image":"v2"}}`},
+	}}
+	assert.False(t, q.Match(record))
+
+	record.Previous = []byte(`{"spec":{"image":"v1"}}`)
+	assert.True(t, q.Match(record))
+}
@@ -0,0 +1,387 @@
+package searchql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxQueryLength bounds the size of a query. Queries are typed by people and
+// agents, so anything longer is almost certainly a mistake.
+const maxQueryLength = 4096
+
+// Error describes an invalid query
+type Error struct {
+	Pos int // byte offset in the query
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos+1, e.Msg)
+}
+
+// Parse parses a query. Field names are case-insensitive; values are
+// validated against their field and normalized.
+func Parse(input string) (*Query, error) {
+	if len(input) > maxQueryLength {
+		return nil, &Error{Pos: maxQueryLength, Msg: fmt.Sprintf("query is longer than %d characters", maxQueryLength)}
+	}
+
+	p := &parser{input: input}
+	p.skipSpace()
+	if p.eof() {
+		return nil, &Error{Pos: 0, Msg: "query is empty"}
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", string(p.input[p.pos]))
+	}
+
+	if err := checkChangedTerms(expr); err != nil {
+		return nil, err
+	}
+	return &Query{Expr: expr}, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+	depth int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &Error{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// keyword consumes an upper-case keyword followed by a space or parenthesis
+func (p *parser) keyword(word string) bool {
+	if !strings.HasPrefix(p.input[p.pos:], word) {
+		return false
+	}
+	end := p.pos + len(word)
+	if end < len(p.input) && !unicode.IsSpace(rune(p.input[end])) && p.input[end] != '(' {
+		return false
+	}
+	p.pos = end
+	p.skipSpace()
+	return true
+}
+
+// parseOr parses and-expressions separated by OR
+func (p *parser) parseOr() (Expr, error) {
+	var exprs []Expr
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if !p.keyword("OR") {
+			break
+		}
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return &Or{Exprs: exprs}, nil
+}
+
+// parseAnd parses a sequence of unary expressions, optionally separated by AND
+func (p *parser) parseAnd() (Expr, error) {
+	var exprs []Expr
+	for {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		if p.keyword("AND") {
+			if p.eof() || p.input[p.pos] == ')' || p.peekKeyword("OR") {
+				return nil, p.errorf("expected a term after AND")
+			}
+			continue
+		}
+		if p.eof() || p.input[p.pos] == ')' || p.peekKeyword("OR") {
+			break
+		}
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return &And{Exprs: exprs}, nil
+}
+
+// peekKeyword reports whether a keyword follows without consuming it
+func (p *parser) peekKeyword(word string) bool {
+	pos := p.pos
+	ok := p.keyword(word)
+	p.pos = pos
+	return ok
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.eof() {
+		return nil, p.errorf("expected a term")
+	}
+
+	if p.keyword("NOT") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	if p.input[p.pos] == '-' {
+		p.pos++
+		if p.eof() || unicode.IsSpace(rune(p.input[p.pos])) {
+			return nil, p.errorf("expected a term after '-'")
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+
+	if p.input[p.pos] == '(' {
+		if p.depth >= 32 {
+			return nil, p.errorf("parentheses are nested too deeply")
+		}
+		start := p.pos
+		p.pos++
+		p.depth++
+		p.skipSpace()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.eof() || p.input[p.pos] != ')' {
+			return nil, &Error{Pos: start, Msg: "unbalanced parenthesis"}
+		}
+		p.pos++
+		p.depth--
+		p.skipSpace()
+		return expr, nil
+	}
+
+	expr, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	return expr, nil
+}
+
+// parseTerm parses field:values, field~values or a bare value
+func (p *parser) parseTerm() (Expr, error) {
+	start := p.pos
+	name := p.fieldName()
+	if name != "" && !p.eof() && (p.input[p.pos] == ':' || p.input[p.pos] == '~') {
+		op := OpEquals
+		if p.input[p.pos] == '~' {
+			op = OpContains
+		}
+		p.pos++
+
+		canonical := strings.ToLower(name)
+		if alias, ok := fieldAliases[canonical]; ok {
+			canonical = alias
+		}
+		f, ok := fields[canonical]
+		if !ok {
+			return nil, &Error{Pos: start, Msg: fmt.Sprintf("unknown field %q (supported: %s)", name, fieldNames())}
+		}
+
+		valuesPos := p.pos
+		values, err := p.values()
+		if err != nil {
+			return nil, err
+		}
+		term := &Term{Field: canonical, Op: op, Values: values}
+		if err := normalize(term, f); err != nil {
+			return nil, &Error{Pos: valuesPos, Msg: err.Error()}
+		}
+		return term, nil
+	}
+
+	// Not a field: the whole word is a name search
+	p.pos = start
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, p.errorf("expected a term")
+	}
+	return &Term{Field: "name", Op: OpContains, Values: []string{value}}, nil
+}
+
+// fieldName consumes a run of letters, without validating it
+func (p *parser) fieldName() string {
+	start := p.pos
+	for !p.eof() && (unicode.IsLetter(rune(p.input[p.pos])) || p.input[p.pos] == '_') {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// values parses a comma-separated list of values
+func (p *parser) values() ([]string, error) {
+	var values []string
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.eof() || p.input[p.pos] != ',' {
+			return values, nil
+		}
+		p.pos++
+	}
+}
+
+// value parses a quoted string or a run of characters up to a space,
+// parenthesis or comma
+func (p *parser) value() (string, error) {
+	if !p.eof() && p.input[p.pos] == '"' {
+		start := p.pos
+		p.pos++
+		for !p.eof() && p.input[p.pos] != '"' {
+			if p.input[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if p.eof() {
+			return "", &Error{Pos: start, Msg: "unterminated string"}
+		}
+		p.pos++
+		value, err := strconv.Unquote(p.input[start:p.pos])
+		if err != nil {
+			return "", &Error{Pos: start, Msg: "invalid escape sequence in string"}
+		}
+		return value, nil
+	}
+
+	start := p.pos
+	for !p.eof() {
+		c := p.input[p.pos]
+		if unicode.IsSpace(rune(c)) || c == '(' || c == ')' || c == ',' || c == '"' {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected a value")
+	}
+	return p.input[start:p.pos], nil
+}
+
+// normalize validates the values of a term against its field
+func normalize(term *Term, f field) error {
+	switch f.kind {
+	case valueBool:
+		if term.Op == OpContains {
+			return fmt.Errorf("%s does not support ~", term.Field)
+		}
+		for i, v := range term.Values {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%s must be true or false, got %q", term.Field, v)
+			}
+			term.Values[i] = strconv.FormatBool(b)
+		}
+	case valueEnum:
+		if term.Op == OpContains {
+			break
+		}
+		for i, v := range term.Values {
+			canonical := ""
+			for _, allowed := range f.enum {
+				if strings.EqualFold(v, allowed) {
+					canonical = allowed
+				}
+			}
+			if canonical == "" {
+				return fmt.Errorf("unknown %s %q (expected one of %s)", term.Field, v, strings.Join(f.enum, ", "))
+			}
+			term.Values[i] = canonical
+		}
+	case valueLabel:
+		if term.Op == OpContains {
+			return fmt.Errorf("label does not support ~, use label:key or label:key=value")
+		}
+		for _, v := range term.Values {
+			if key, _, _ := strings.Cut(v, "="); key == "" {
+				return fmt.Errorf("label needs a key, got %q", v)
+			}
+		}
+	case valuePath:
+		if term.Op == OpContains {
+			return fmt.Errorf("changed does not support ~")
+		}
+		for _, v := range term.Values {
+			for _, segment := range strings.Split(v, ".") {
+				if segment == "" {
+					return fmt.Errorf("invalid field path %q", v)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkChangedTerms rejects changed: terms that are negated or inside OR.
+// Whether a field changed is decided from the stored resource versions after
+// the graph query ran, which only works for conditions every result must meet.
+func checkChangedTerms(expr Expr) error {
+	var top []Expr
+	if and, ok := expr.(*And); ok {
+		top = and.Exprs
+	} else {
+		top = []Expr{expr}
+	}
+
+	for _, e := range top {
+		if _, ok := e.(*Term); ok {
+			continue
+		}
+		nested := false
+		walk(e, func(t *Term) {
+			if t.Field == "changed" {
+				nested = true
+			}
+		})
+		if nested {
+			return &Error{Pos: 0, Msg: "changed: cannot be negated or combined with OR"}
+		}
+	}
+	return nil
+}
+
+func fieldNames() string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
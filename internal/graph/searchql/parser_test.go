@@ -0,0 +1,96 @@
+package searchql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Terms(t *testing.T) {
+	q, err := Parse(`kind:Pod ns:payments status:error label:app=api reason~"OOM" changed:spec.template.image`)
+	require.NoError(t, err)
+
+	and, ok := q.Expr.(*And)
+	require.True(t, ok)
+	require.Len(t, and.Exprs, 6)
+
+	assert.Equal(t, &Term{Field: "kind", Op: OpEquals, Values: []string{"Pod"}}, and.Exprs[0])
+	assert.Equal(t, &Term{Field: "namespace", Op: OpEquals, Values: []string{"payments"}}, and.Exprs[1])
+	assert.Equal(t, &Term{Field: "status", Op: OpEquals, Values: []string{"Error"}}, and.Exprs[2])
+	assert.Equal(t, &Term{Field: "label", Op: OpEquals, Values: []string{"app=api"}}, and.Exprs[3])
+	assert.Equal(t, &Term{Field: "reason", Op: OpContains, Values: []string{"OOM"}}, and.Exprs[4])
+	assert.Equal(t, &Term{Field: "changed", Op: OpEquals, Values: []string{"spec.template.image"}}, and.Exprs[5])
+}
+
+func TestParse_Operators(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"checkout", "name~checkout"},
+		{"kind:Pod,Deployment", "kind:Pod,Deployment"},
+		{"kind:Pod AND ns:default", "kind:Pod namespace:default"},
+		{"kind:Pod OR kind:Node", "kind:Pod OR kind:Node"},
+		{"kind:Pod (ns:a OR ns:b)", "kind:Pod (namespace:a OR namespace:b)"},
+		{"-ns:kube-system", "-namespace:kube-system"},
+		{"NOT (ns:a OR ns:b)", "-(namespace:a OR namespace:b)"},
+		{`msg~"back-off restarting"`, `message~"back-off restarting"`},
+		{`msg~"say \"hi\""`, `message~"say \"hi\""`},
+		{"deleted:TRUE op:update", "deleted:true op:UPDATE"},
+		{"ORDERS", "name~ORDERS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, q.String())
+
+			// The canonical form parses to the same query
+			again, err := Parse(q.String())
+			require.NoError(t, err)
+			assert.Equal(t, q, again)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"empty", "   "},
+		{"unknown field", "colour:red"},
+		{"missing value", "kind:"},
+		{"unknown enum value", "status:Broken"},
+		{"invalid bool", "deleted:maybe"},
+		{"contains on bool", "deleted~true"},
+		{"label without key", "label:=api"},
+		{"contains on label", "label~app"},
+		{"invalid path", "changed:spec..replicas"},
+		{"unterminated string", `reason~"OOM`},
+		{"unbalanced parenthesis", "(kind:Pod"},
+		{"stray parenthesis", "kind:Pod)"},
+		{"dangling AND", "kind:Pod AND"},
+		{"dangling negation", "- kind:Pod"},
+		{"negated changed", "-changed:spec.replicas"},
+		{"changed in OR", "changed:spec.replicas OR kind:Pod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			require.Error(t, err)
+			var queryErr *Error
+			assert.True(t, errors.As(err, &queryErr), "expected an Error, got %T", err)
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	lines := Fields()
+	assert.Len(t, lines, len(fields))
+	assert.Contains(t, lines, "op: change operation (CREATE, UPDATE, DELETE)")
+}
@@ -0,0 +1,59 @@
+package searchql
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Path is a dotted field path such as spec.template.spec.containers.image.
+// Lists are traversed transparently, so the example covers the image of
+// every container.
+type Path []string
+
+// ParsePath splits a dotted path
+func ParsePath(s string) Path {
+	return strings.Split(s, ".")
+}
+
+func (p Path) String() string {
+	return strings.Join(p, ".")
+}
+
+// Changed reports whether the value at path differs between two JSON
+// encoded versions of an object. A field that is added or removed counts as
+// changed. It returns false when either version can't be decoded.
+func Changed(before, after []byte, path Path) bool {
+	var oldObj, newObj interface{}
+	if err := json.Unmarshal(before, &oldObj); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(after, &newObj); err != nil {
+		return false
+	}
+	return !reflect.DeepEqual(lookup(oldObj, path), lookup(newObj, path))
+}
+
+// lookup returns the value at path, mapping over lists along the way
+func lookup(value interface{}, path Path) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		child, ok := v[path[0]]
+		if !ok {
+			return nil
+		}
+		return lookup(child, path[1:])
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = lookup(item, path)
+		}
+		return values
+	default:
+		return nil
+	}
+}
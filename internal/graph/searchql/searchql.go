@@ -0,0 +1,219 @@
+// Package searchql implements the Spectre query language accepted by the q
+// parameter of the search and timeline APIs, for example
+//
+//	kind:Pod ns:payments status:Error label:app=api reason~"OOM"
+//
+// A query is a sequence of terms that all have to match. Terms can be
+// combined with OR, grouped with parentheses and negated with a leading "-"
+// or NOT. A term is field:value for an exact match or field~value for a
+// case-insensitive substring match. Comma-separated values match any of the
+// values, and values containing spaces, commas or parentheses are quoted. A
+// term without a field, such as "checkout", is short for name~checkout.
+//
+// Resource fields are matched against the ResourceIdentity node. Change event
+// and Kubernetes event fields are matched against the events of the resource
+// in the queried time window: each such term holds when at least one event
+// matches it.
+package searchql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Scope is the graph node a field is matched against
+type Scope int
+
+const (
+	ScopeResource    Scope = iota // ResourceIdentity
+	ScopeChangeEvent              // ChangeEvent in the time window
+	ScopeK8sEvent                 // K8sEvent in the time window
+)
+
+// valueKind controls how a field's values are validated and compared
+type valueKind int
+
+const (
+	valueString valueKind = iota // exact or substring match
+	valueEnum                    // one of a fixed set of values, matched case-insensitively
+	valueBool                    // true or false, exact match only
+	valueLabel                   // key or key=value against the JSON-encoded labels
+	valueList                    // matches any element of a list property
+	valuePath                    // dotted path of a changed field (changed:)
+)
+
+type field struct {
+	scope    Scope
+	property string
+	kind     valueKind
+	enum     []string
+	help     string
+}
+
+var fields = map[string]field{
+	"kind":      {scope: ScopeResource, property: "kind", help: "resource kind, e.g. Pod"},
+	"namespace": {scope: ScopeResource, property: "namespace", help: "resource namespace (alias ns)"},
+	"name":      {scope: ScopeResource, property: "name", help: "resource name"},
+	"group":     {scope: ScopeResource, property: "apiGroup", help: "API group, empty for core resources"},
+	"version":   {scope: ScopeResource, property: "version", help: "API version"},
+	"cluster":   {scope: ScopeResource, property: "cluster", help: "cluster name"},
+	"uid":       {scope: ScopeResource, property: "uid", help: "resource UID"},
+	"label":     {scope: ScopeResource, property: "labels", kind: valueLabel, help: "label key or key=value"},
+	"deleted":   {scope: ScopeResource, property: "deleted", kind: valueBool, help: "whether the resource was deleted"},
+
+	"status":  {scope: ScopeChangeEvent, property: "status", kind: valueEnum, enum: []string{"Ready", "Warning", "Error", "Terminating", "Unknown"}, help: "status after a change"},
+	"op":      {scope: ScopeChangeEvent, property: "eventType", kind: valueEnum, enum: []string{"CREATE", "UPDATE", "DELETE"}, help: "change operation"},
+	"error":   {scope: ScopeChangeEvent, property: "errorMessage", help: "error message extracted from a change"},
+	"issue":   {scope: ScopeChangeEvent, property: "containerIssues", kind: valueList, help: "container issue, e.g. OOMKilled"},
+	"changed": {scope: ScopeChangeEvent, kind: valuePath, help: "dotted path of a field an update changed, e.g. spec.replicas"},
+
+	"reason":  {scope: ScopeK8sEvent, property: "reason", help: "Kubernetes event reason"},
+	"message": {scope: ScopeK8sEvent, property: "message", help: "Kubernetes event message (alias msg)"},
+	"type":    {scope: ScopeK8sEvent, property: "type", kind: valueEnum, enum: []string{"Normal", "Warning"}, help: "Kubernetes event type"},
+}
+
+var fieldAliases = map[string]string{
+	"ns":       "namespace",
+	"apigroup": "group",
+	"msg":      "message",
+}
+
+// Fields describes the supported fields, one "name: description" line each,
+// for help texts such as the MCP tool description
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		f := fields[name]
+		line := name + ": " + f.help
+		if len(f.enum) > 0 {
+			line += " (" + strings.Join(f.enum, ", ") + ")"
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// Op is the comparison of a term
+type Op int
+
+const (
+	OpEquals   Op = iota // field:value
+	OpContains           // field~value
+)
+
+// Expr is a node of a parsed query
+type Expr interface {
+	String() string
+}
+
+// Term compares a field with one or more values
+type Term struct {
+	Field  string // canonical field name
+	Op     Op
+	Values []string // normalized values, at least one
+}
+
+// Not negates an expression
+type Not struct {
+	Expr Expr
+}
+
+// And matches when all expressions match
+type And struct {
+	Exprs []Expr
+}
+
+// Or matches when any expression matches
+type Or struct {
+	Exprs []Expr
+}
+
+func (t *Term) String() string {
+	op := ":"
+	if t.Op == OpContains {
+		op = "~"
+	}
+	values := make([]string, len(t.Values))
+	for i, v := range t.Values {
+		values[i] = quoteValue(v)
+	}
+	return t.Field + op + strings.Join(values, ",")
+}
+
+func (n *Not) String() string {
+	if _, ok := n.Expr.(*Term); ok {
+		return "-" + n.Expr.String()
+	}
+	return "-(" + n.Expr.String() + ")"
+}
+
+func (a *And) String() string { return joinExprs(a.Exprs, " ") }
+
+func (o *Or) String() string { return joinExprs(o.Exprs, " OR ") }
+
+func joinExprs(exprs []Expr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = e.String()
+		if _, ok := e.(*Term); !ok {
+			if _, ok := e.(*Not); !ok {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// quoteValue quotes a value when it would not parse back unquoted
+func quoteValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\r\n(),\"") {
+		return v
+	}
+	return fmt.Sprintf("%q", v)
+}
+
+// Query is a parsed search query
+type Query struct {
+	Expr Expr
+}
+
+// String renders the query in canonical form
+func (q *Query) String() string {
+	return q.Expr.String()
+}
+
+// HasChangedTerms reports whether the query contains changed: terms, which
+// need the previous version of a resource to be evaluated
+func (q *Query) HasChangedTerms() bool {
+	found := false
+	walk(q.Expr, func(t *Term) {
+		if t.Field == "changed" {
+			found = true
+		}
+	})
+	return found
+}
+
+func walk(e Expr, fn func(*Term)) {
+	switch e := e.(type) {
+	case *Term:
+		fn(e)
+	case *Not:
+		walk(e.Expr, fn)
+	case *And:
+		for _, child := range e.Exprs {
+			walk(child, fn)
+		}
+	case *Or:
+		for _, child := range e.Exprs {
+			walk(child, fn)
+		}
+	}
+}
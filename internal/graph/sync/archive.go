@@ -0,0 +1,121 @@
+package sync
+
+import (
+	"encoding/json"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// archivedEvents converts rows of (event node, resource node) back into the
+// events they were built from, so that they can be archived and re-imported.
+// It returns the events together with the IDs of their nodes.
+func archivedEvents(source string, rows [][]interface{}) ([]models.Event, []string) {
+	events := make([]models.Event, 0, len(rows))
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		eventProps, err := graph.ParseNodeFromResult(row[0])
+		if err != nil {
+			continue
+		}
+		resourceProps, err := graph.ParseNodeFromResult(row[1])
+		if err != nil {
+			continue
+		}
+
+		var event models.Event
+		if source == RetentionSourceK8s {
+			event = archivedK8sEvent(eventProps, resourceProps)
+		} else {
+			event = archivedChangeEvent(eventProps, resourceProps)
+		}
+		if event.ID == "" {
+			continue
+		}
+		events = append(events, event)
+		ids = append(ids, event.ID)
+	}
+	return events, ids
+}
+
+// archivedChangeEvent rebuilds the resource event of a ChangeEvent node
+func archivedChangeEvent(eventProps, resourceProps map[string]interface{}) models.Event {
+	change := graph.ParseChangeEventFromNode(eventProps)
+	event := models.Event{
+		ID:            change.ID,
+		Timestamp:     change.Timestamp,
+		Type:          models.EventType(change.EventType),
+		Resource:      archivedResource(resourceProps),
+		Actor:         graph.ChangeEventActor(change),
+		FieldManagers: graph.ChangeEventFieldManagers(change),
+	}
+	if change.Data != "" {
+		event.Data = json.RawMessage(change.Data)
+	}
+	return event
+}
+
+// archivedK8sEvent rebuilds a Kubernetes Event object from a K8sEvent node.
+// Only the fields kept in the graph are restored; involvedObject points to
+// the resource the event was attached to.
+func archivedK8sEvent(eventProps, resourceProps map[string]interface{}) models.Event {
+	id, _ := eventProps["id"].(string)
+	resource := archivedResource(resourceProps)
+
+	apiVersion := resource.Version
+	if resource.Group != "" {
+		apiVersion = resource.Group + "/" + resource.Version
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata": map[string]interface{}{
+			"name":      id,
+			"namespace": resource.Namespace,
+		},
+		"reason":  eventProps["reason"],
+		"message": eventProps["message"],
+		"type":    eventProps["type"],
+		"count":   toInt64(eventProps["count"]),
+		"source":  map[string]interface{}{"component": eventProps["source"]},
+		"involvedObject": map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       resource.Kind,
+			"namespace":  resource.Namespace,
+			"name":       resource.Name,
+			"uid":        models.LocalUID(resource.UID),
+		},
+	})
+
+	return models.Event{
+		ID:        id,
+		Timestamp: toInt64(eventProps["timestamp"]),
+		Type:      models.EventTypeCreate,
+		Resource: models.ResourceMetadata{
+			Version:           "v1",
+			Kind:              "Event",
+			Namespace:         resource.Namespace,
+			Name:              id,
+			UID:               id,
+			InvolvedObjectUID: resource.UID,
+			Cluster:           resource.Cluster,
+		},
+		Data: data,
+	}
+}
+
+func archivedResource(props map[string]interface{}) models.ResourceMetadata {
+	resource := graph.ParseResourceIdentityFromNode(props)
+	return models.ResourceMetadata{
+		Group:     resource.APIGroup,
+		Version:   resource.Version,
+		Kind:      resource.Kind,
+		Namespace: resource.Namespace,
+		Name:      resource.Name,
+		UID:       resource.UID,
+		Cluster:   resource.Cluster,
+	}
+}
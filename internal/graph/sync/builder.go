@@ -45,8 +45,10 @@ func NewGraphBuilder() GraphBuilder {
 	}
 }
 
-// NewGraphBuilderWithClient creates a new graph builder with client access
-func NewGraphBuilderWithClient(client graph.Client) GraphBuilder {
+// NewGraphBuilderWithClient creates a new graph builder with client access.
+// Additional extractors, e.g. configured ones, are registered after the
+// built-in extractors.
+func NewGraphBuilderWithClient(client graph.Client, additional ...extractors.RelationshipExtractor) GraphBuilder {
 	// Create resource lookup adapter
 	lookup := extractors.NewGraphClientLookup(client)
 
@@ -79,6 +81,10 @@ func NewGraphBuilderWithClient(client graph.Client) GraphBuilder {
 	registry.Register(certmanager.NewCertificateExtractor())        // Certificate→Issuer/ClusterIssuer, Certificate→Secret
 	registry.Register(externalsecrets.NewExternalSecretExtractor()) // ExternalSecret→SecretStore/ClusterSecretStore, ExternalSecret→Secret
 
+	for _, extractor := range additional {
+		registry.Register(extractor)
+	}
+
 	return &graphBuilder{
 		logger:            logging.GetLogger("graph.sync.builder"),
 		client:            client,
@@ -280,6 +286,7 @@ func (b *graphBuilder) buildResourceIdentityNode(event models.Event) graph.Resou
 
 	resource := graph.ResourceIdentity{
 		UID:       event.Resource.UID,
+		Cluster:   event.Resource.Cluster,
 		Kind:      event.Resource.Kind,
 		APIGroup:  event.Resource.Group,
 		Version:   event.Resource.Version,
@@ -381,8 +388,10 @@ func (b *graphBuilder) extractInvolvedObjectMetadata(event models.Event) *graph.
 
 	// Create ResourceIdentity node
 	// Use event timestamp as both firstSeen and lastSeen since we don't have resource events
+	// The involvedObject lives in the same cluster as the Event itself
 	return &graph.ResourceIdentity{
-		UID:       uid,
+		UID:       models.QualifyUID(event.Resource.Cluster, uid),
+		Cluster:   event.Resource.Cluster,
 		Kind:      kind,
 		APIGroup:  group,
 		Version:   version,
@@ -449,8 +458,9 @@ func (b *graphBuilder) buildChangeEventNode(event models.Event) graph.ChangeEven
 		// Unknown event type, keep defaults
 	}
 
-	return graph.ChangeEvent{
+	changeEvent := graph.ChangeEvent{
 		ID:              event.ID,
+		Cluster:         event.Resource.Cluster,
 		Timestamp:       event.Timestamp,
 		EventType:       string(event.Type),
 		Status:          status,
@@ -460,8 +470,23 @@ func (b *graphBuilder) buildChangeEventNode(event models.Event) graph.ChangeEven
 		StatusChanged:   statusChanged,
 		ReplicasChanged: replicasChanged,
 		ImpactScore:     b.calculateImpactScore(status, containerIssues),
-		Data:            string(event.Data), // Store full resource data for timeline reconstruction
+		Data:            string(event.Data),             // Store full resource data for timeline reconstruction
+		ResourceVersion: resourceData.ResourceVersion(), // audit events are correlated by it
+	}
+
+	// Imported events may carry the actor correlated on another instance
+	if event.Actor != nil {
+		changeEvent.ActorUser = event.Actor.User
+		changeEvent.ActorServiceAccount = event.Actor.ServiceAccount
+		changeEvent.ActorUserAgent = event.Actor.UserAgent
+		changeEvent.ActorSourceIP = event.Actor.SourceIP
 	}
+	if len(event.FieldManagers) > 0 {
+		if managers, err := json.Marshal(event.FieldManagers); err == nil {
+			changeEvent.FieldManagers = string(managers)
+		}
+	}
+	return changeEvent
 }
 
 // detectChanges compares current event with previous event to detect what changed
@@ -763,6 +788,7 @@ func (b *graphBuilder) buildK8sEventNode(event models.Event) (graph.K8sEvent, er
 
 	return graph.K8sEvent{
 		ID:        event.ID,
+		Cluster:   event.Resource.Cluster,
 		Timestamp: event.Timestamp,
 		Reason:    reason,
 		Message:   message,
@@ -802,6 +828,9 @@ func (b *graphBuilder) buildEmittedEventEdge(resourceUID, k8sEventID string) gra
 func (b *graphBuilder) extractOwnershipRelationships(ownedUID string, resourceData map[string]interface{}) []graph.Edge {
 	edges := []graph.Edge{}
 
+	// Owners always live in the same cluster as the owned resource
+	cluster := models.ClusterOfUID(ownedUID)
+
 	metadata, ok := resourceData["metadata"].(map[string]interface{})
 	if !ok {
 		return edges
@@ -847,7 +876,7 @@ func (b *graphBuilder) extractOwnershipRelationships(ownedUID string, resourceDa
 
 		edge := graph.Edge{
 			Type:       graph.EdgeTypeOwns,
-			FromUID:    ownerUID,
+			FromUID:    models.QualifyUID(cluster, ownerUID),
 			ToUID:      ownedUID,
 			Properties: propsJSON,
 		}
@@ -916,7 +945,7 @@ func (b *graphBuilder) extractSelectorRelationships(selectorUID, kind string, re
 	}
 
 	// Query for Pods matching these labels
-	matchingPodUIDs, err := b.findPodsMatchingLabels(context.Background(), selector, namespace)
+	matchingPodUIDs, err := b.findPodsMatchingLabels(context.Background(), selector, models.ClusterOfUID(selectorUID), namespace)
 	if err != nil {
 		b.logger.Debug("Failed to find Pods matching selector for %s %s: %v", kind, selectorUID, err)
 		return edges
@@ -944,10 +973,10 @@ func (b *graphBuilder) extractSelectorRelationships(selectorUID, kind string, re
 }
 
 // findPodsMatchingLabels queries the graph for Pods with labels matching the selector.
-// It queries all Pods in the namespace and filters by label selector in-memory.
+// It queries all Pods of the cluster in the namespace and filters by label selector in-memory.
 // In-memory filtering is used because Cypher JSON substring matching is unreliable
 // with special characters in label keys (e.g., 'app.kubernetes.io/name').
-func (b *graphBuilder) findPodsMatchingLabels(ctx context.Context, selector map[string]string, namespace string) ([]string, error) {
+func (b *graphBuilder) findPodsMatchingLabels(ctx context.Context, selector map[string]string, cluster, namespace string) ([]string, error) {
 	var query graph.GraphQuery
 
 	if namespace != "" {
@@ -955,12 +984,14 @@ func (b *graphBuilder) findPodsMatchingLabels(ctx context.Context, selector map[
 			Query: `
 				MATCH (p:ResourceIdentity {kind: $kind, namespace: $namespace})
 				WHERE NOT p.deleted
+				  AND coalesce(p.cluster, '') = $cluster
 				RETURN p.uid as uid, p.labels as labels
 				LIMIT 100
 			`,
 			Parameters: map[string]interface{}{
 				"kind":      "Pod",
 				"namespace": namespace,
+				"cluster":   cluster,
 			},
 		}
 	} else {
@@ -968,11 +999,13 @@ func (b *graphBuilder) findPodsMatchingLabels(ctx context.Context, selector map[
 			Query: `
 				MATCH (p:ResourceIdentity {kind: $kind})
 				WHERE NOT p.deleted
+				  AND coalesce(p.cluster, '') = $cluster
 				RETURN p.uid as uid, p.labels as labels
 				LIMIT 100
 			`,
 			Parameters: map[string]interface{}{
-				"kind": "Pod",
+				"kind":    "Pod",
+				"cluster": cluster,
 			},
 		}
 	}
@@ -1030,7 +1063,7 @@ func (b *graphBuilder) extractSchedulingRelationship(podUID string, resourceData
 	}
 
 	// Query the graph for the Node with this name
-	nodeUID, err := b.findNodeUIDByName(context.Background(), nodeName)
+	nodeUID, err := b.findNodeUIDByName(context.Background(), models.ClusterOfUID(podUID), nodeName)
 	if err != nil {
 		b.logger.Debug("Failed to find Node UID for name %s: %v", nodeName, err)
 		return nil
@@ -1082,28 +1115,30 @@ func (b *graphBuilder) extractSchedulingRelationship(podUID string, resourceData
 }
 
 // findNodeUIDByName queries the graph for a Node with the given name and returns its UID
-func (b *graphBuilder) findNodeUIDByName(ctx context.Context, nodeName string) (string, error) {
-	return b.findResourceUIDByName(ctx, "Node", nodeName, "")
+func (b *graphBuilder) findNodeUIDByName(ctx context.Context, cluster, nodeName string) (string, error) {
+	return b.findResourceUIDByName(ctx, cluster, "Node", nodeName, "")
 }
 
 // findPVCUIDByName queries the graph for a PVC with the given name and namespace and returns its UID
-func (b *graphBuilder) findPVCUIDByName(ctx context.Context, pvcName, namespace string) (string, error) {
-	return b.findResourceUIDByName(ctx, "PersistentVolumeClaim", pvcName, namespace)
+func (b *graphBuilder) findPVCUIDByName(ctx context.Context, cluster, pvcName, namespace string) (string, error) {
+	return b.findResourceUIDByName(ctx, cluster, "PersistentVolumeClaim", pvcName, namespace)
 }
 
 // findServiceAccountUIDByName queries the graph for a ServiceAccount with the given name and namespace and returns its UID
-func (b *graphBuilder) findServiceAccountUIDByName(ctx context.Context, saName, namespace string) (string, error) {
-	return b.findResourceUIDByName(ctx, "ServiceAccount", saName, namespace)
+func (b *graphBuilder) findServiceAccountUIDByName(ctx context.Context, cluster, saName, namespace string) (string, error) {
+	return b.findResourceUIDByName(ctx, cluster, "ServiceAccount", saName, namespace)
 }
 
-// findResourceUIDByName is a generic helper to find a resource UID by kind, name, and optionally namespace
-func (b *graphBuilder) findResourceUIDByName(ctx context.Context, kind, name, namespace string) (string, error) {
+// findResourceUIDByName is a generic helper to find a resource UID by cluster, kind, name, and optionally namespace.
+// Names are only unique within a cluster, so the lookup never crosses cluster boundaries.
+func (b *graphBuilder) findResourceUIDByName(ctx context.Context, cluster, kind, name, namespace string) (string, error) {
 	// Build query based on whether namespace is required
 	var query graph.GraphQuery
 	if namespace != "" {
 		query = graph.GraphQuery{
 			Query: `
 				MATCH (n:ResourceIdentity {kind: $kind, name: $name, namespace: $namespace})
+				WHERE coalesce(n.cluster, '') = $cluster
 				RETURN n.uid as uid
 				LIMIT 1
 			`,
@@ -1111,18 +1146,21 @@ func (b *graphBuilder) findResourceUIDByName(ctx context.Context, kind, name, na
 				"kind":      kind,
 				"name":      name,
 				"namespace": namespace,
+				"cluster":   cluster,
 			},
 		}
 	} else {
 		query = graph.GraphQuery{
 			Query: `
 				MATCH (n:ResourceIdentity {kind: $kind, name: $name})
+				WHERE coalesce(n.cluster, '') = $cluster
 				RETURN n.uid as uid
 				LIMIT 1
 			`,
 			Parameters: map[string]interface{}{
-				"kind": kind,
-				"name": name,
+				"kind":    kind,
+				"name":    name,
+				"cluster": cluster,
 			},
 		}
 	}
@@ -1207,7 +1245,7 @@ func (b *graphBuilder) extractVolumeRelationships(podUID string, resourceData ma
 		}
 
 		// Look up PVC UID by name and namespace
-		pvcUID, err := b.findPVCUIDByName(context.Background(), claimName, namespace)
+		pvcUID, err := b.findPVCUIDByName(context.Background(), models.ClusterOfUID(podUID), claimName, namespace)
 		if err != nil {
 			b.logger.Debug("Failed to find PVC UID for %s/%s: %v", namespace, claimName, err)
 			continue
@@ -1323,7 +1361,7 @@ func (b *graphBuilder) extractServiceAccountRelationship(podUID string, resource
 	}
 
 	// Look up ServiceAccount UID by name and namespace
-	saUID, err := b.findServiceAccountUIDByName(context.Background(), serviceAccountName, namespace)
+	saUID, err := b.findServiceAccountUIDByName(context.Background(), models.ClusterOfUID(podUID), serviceAccountName, namespace)
 	if err != nil {
 		b.logger.Debug("Failed to find ServiceAccount UID for %s/%s: %v", namespace, serviceAccountName, err)
 		return nil
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync/extractors"
 	"github.com/moolen/spectre/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -174,6 +175,116 @@ func TestGraphBuilder_ExtractOwnership(t *testing.T) {
 	assert.True(t, props.BlockOwnerDeletion)
 }
 
+func TestGraphBuilder_ClusterQualifiedUIDs(t *testing.T) {
+	builder := NewGraphBuilder()
+	ctx := context.Background()
+
+	podData, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"ownerReferences": []interface{}{
+				map[string]interface{}{
+					"uid":  "rs-123",
+					"kind": "ReplicaSet",
+					"name": "frontend-rs",
+				},
+			},
+		},
+	})
+
+	event := models.Event{
+		ID:        "event-1",
+		Timestamp: time.Now().UnixNano(),
+		Type:      models.EventTypeCreate,
+		Resource: models.ResourceMetadata{
+			UID:       "pod-123",
+			Kind:      "Pod",
+			Version:   "v1",
+			Namespace: "default",
+			Name:      "frontend-abc",
+		},
+		Data: podData,
+	}
+	event.Resource.SetCluster("prod-eu-1")
+
+	update, err := builder.BuildFromEvent(ctx, event)
+	require.NoError(t, err)
+	require.Len(t, update.ResourceNodes, 1)
+	assert.Equal(t, "prod-eu-1:pod-123", update.ResourceNodes[0].UID)
+	assert.Equal(t, "prod-eu-1", update.ResourceNodes[0].Cluster)
+	require.Len(t, update.EventNodes, 1)
+	assert.Equal(t, "prod-eu-1", update.EventNodes[0].Cluster)
+
+	// Owners are resolved within the same cluster
+	edges, err := builder.ExtractRelationships(ctx, event)
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, "prod-eu-1:rs-123", edges[0].FromUID)
+	assert.Equal(t, "prod-eu-1:pod-123", edges[0].ToUID)
+}
+
+func TestPipeline_ConfiguredExtractors(t *testing.T) {
+	clientConfig := graph.DefaultClientConfig()
+	clientConfig.Backend = graph.BackendMemory
+	client := graph.NewClient(clientConfig)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.InitializeSchema(ctx))
+
+	extractor, err := extractors.NewConfigExtractor(extractors.RelationshipRule{
+		Group: "argoproj.io",
+		Kind:  "Rollout",
+		Refs: []extractors.ReferenceRule{
+			{Path: "spec.workloadRef.name", TargetKind: "Deployment", TargetGroup: "apps", Edge: graph.EdgeTypeManages},
+			{Type: extractors.ReferenceTypeSelector, Path: "spec.selector", TargetKind: "Pod"},
+		},
+	})
+	require.NoError(t, err)
+	pipelineConfig := DefaultPipelineConfig()
+	pipelineConfig.Extractors = append(pipelineConfig.Extractors, extractor)
+	pipeline := NewPipeline(pipelineConfig, client)
+
+	resource := func(id, group, kind, name string, object map[string]interface{}) models.Event {
+		object["metadata"] = map[string]interface{}{
+			"name": name, "namespace": "shop", "uid": name + "-uid", "labels": map[string]interface{}{"app": "checkout"},
+		}
+		data, _ := json.Marshal(object)
+		return models.Event{
+			ID:        id,
+			Timestamp: time.Now().UnixNano(),
+			Type:      models.EventTypeCreate,
+			Resource: models.ResourceMetadata{
+				UID: name + "-uid", Group: group, Version: "v1", Kind: kind, Namespace: "shop", Name: name,
+			},
+			Data: data,
+		}
+	}
+	require.NoError(t, pipeline.ProcessBatch(ctx, []models.Event{
+		resource("e1", "apps", "Deployment", "checkout", map[string]interface{}{}),
+		resource("e2", "", "Pod", "checkout-abc", map[string]interface{}{}),
+	}))
+	require.NoError(t, pipeline.ProcessBatch(ctx, []models.Event{
+		resource("e3", "argoproj.io", "Rollout", "rollout", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"workloadRef": map[string]interface{}{"kind": "Deployment", "name": "checkout"},
+				"selector":    map[string]interface{}{"matchLabels": map[string]interface{}{"app": "checkout"}},
+			},
+		}),
+	}))
+
+	for edgeType, target := range map[graph.EdgeType]string{
+		graph.EdgeTypeManages: "checkout-uid",
+		graph.EdgeTypeSelects: "checkout-abc-uid",
+	} {
+		result, err := client.ExecuteQuery(ctx, graph.GraphQuery{
+			Query:      `MATCH (r:ResourceIdentity {uid: $uid})-[:` + string(edgeType) + `]->(t:ResourceIdentity) RETURN t.uid`,
+			Parameters: map[string]interface{}{"uid": "rollout-uid"},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 1, edgeType)
+		assert.Equal(t, target, result.Rows[0][0], edgeType)
+	}
+}
+
 func TestGraphBuilder_CalculateImpactScore(t *testing.T) {
 	builder := NewGraphBuilder().(*graphBuilder)
 
@@ -2,7 +2,12 @@ package sync
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/moolen/spectre/internal/logging"
@@ -17,6 +22,12 @@ type causalityEngine struct {
 	heuristics []CausalityHeuristic
 	maxLag     time.Duration
 	minConfidence float64
+
+	// Configured heuristics, tried before the built-in ones, and the number
+	// of links inferred per heuristic. Rules are replaced on config reloads.
+	mu    sync.RWMutex
+	rules []CausalityHeuristic
+	links map[string]int64
 }
 
 // NewCausalityEngine creates a new causality inference engine
@@ -26,14 +37,31 @@ func NewCausalityEngine(maxLag time.Duration, minConfidence float64) CausalityEn
 		maxLag:        maxLag,
 		minConfidence: minConfidence,
 		heuristics:    []CausalityHeuristic{},
+		links:         make(map[string]int64),
 	}
 
 	// Register default heuristics
 	engine.registerDefaultHeuristics()
+	for i := range engine.heuristics {
+		engine.heuristics[i].Source = HeuristicSourceBuiltin
+	}
 
 	return engine
 }
 
+// BuiltinHeuristicNames returns the names of the built-in heuristics, which
+// configured heuristics can't reuse
+func BuiltinHeuristicNames() []string {
+	engine := &causalityEngine{}
+	engine.registerDefaultHeuristics()
+
+	names := make([]string, len(engine.heuristics))
+	for i, heuristic := range engine.heuristics {
+		names[i] = heuristic.Name
+	}
+	return names
+}
+
 // InferCausality analyzes events and creates TRIGGERED_BY edges
 func (e *causalityEngine) InferCausality(ctx context.Context, events []models.Event) ([]CausalityLink, error) {
 	if len(events) < 2 {
@@ -74,6 +102,14 @@ func (e *causalityEngine) InferCausality(ctx context.Context, events []models.Ev
 		}
 	}
 
+	if len(links) > 0 {
+		e.mu.Lock()
+		for _, link := range links {
+			e.links[link.HeuristicUsed]++
+		}
+		e.mu.Unlock()
+	}
+
 	e.logger.Info("Inferred %d causality links from %d events", len(links), len(events))
 	return links, nil
 }
@@ -89,7 +125,10 @@ func (e *causalityEngine) AnalyzePair(ctx context.Context, cause, effect models.
 	lagMs := lagNs / 1_000_000
 
 	// Try each heuristic
-	for _, heuristic := range e.heuristics {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+	for _, heuristic := range slices.Concat(rules, e.heuristics) {
 		// Check lag bounds
 		if lagMs < heuristic.MinLagMs || lagMs > heuristic.MaxLagMs {
 			continue
@@ -113,7 +152,119 @@ func (e *causalityEngine) AnalyzePair(ctx context.Context, cause, effect models.
 
 // GetHeuristics returns the configured causality heuristics
 func (e *causalityEngine) GetHeuristics() []CausalityHeuristic {
-	return e.heuristics
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return slices.Concat(e.rules, e.heuristics)
+}
+
+// SetRules replaces the heuristics declared in configuration
+func (e *causalityEngine) SetRules(rules []HeuristicRule) error {
+	heuristics := make([]CausalityHeuristic, 0, len(rules))
+	for _, rule := range rules {
+		for _, builtin := range e.heuristics {
+			if builtin.Name == rule.Name {
+				return fmt.Errorf("heuristic %q conflicts with a built-in heuristic", rule.Name)
+			}
+		}
+		if rule.MaxLag > e.maxLag {
+			return fmt.Errorf("heuristic %q: maxLag %v exceeds the causality lag window of %v", rule.Name, rule.MaxLag, e.maxLag)
+		}
+		heuristics = append(heuristics, ruleHeuristic(rule))
+	}
+
+	e.mu.Lock()
+	e.rules = heuristics
+	e.mu.Unlock()
+
+	e.logger.Info("Loaded %d configured causality heuristics", len(heuristics))
+	return nil
+}
+
+// GetHeuristicStats returns the active heuristics and their link counts
+func (e *causalityEngine) GetHeuristicStats() []HeuristicStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	stats := make([]HeuristicStats, 0, len(e.rules)+len(e.heuristics))
+	for _, heuristic := range slices.Concat(e.rules, e.heuristics) {
+		stats = append(stats, HeuristicStats{
+			Name:        heuristic.Name,
+			Description: heuristic.Description,
+			Source:      heuristic.Source,
+			MinLagMs:    heuristic.MinLagMs,
+			MaxLagMs:    heuristic.MaxLagMs,
+			Confidence:  heuristic.Confidence,
+			Links:       e.links[heuristic.Name],
+		})
+	}
+	return stats
+}
+
+// ruleHeuristic turns a configured rule into a heuristic
+func ruleHeuristic(rule HeuristicRule) CausalityHeuristic {
+	description := rule.Description
+	if description == "" {
+		description = fmt.Sprintf("%s triggered %s", kindsOrAny(rule.CauseKinds), kindsOrAny(rule.EffectKinds))
+	}
+	return CausalityHeuristic{
+		Name:        rule.Name,
+		Description: description,
+		MinLagMs:    rule.MinLag.Milliseconds(),
+		MaxLagMs:    rule.MaxLag.Milliseconds(),
+		Confidence:  rule.Confidence,
+		Source:      HeuristicSourceConfig,
+		Apply: func(cause, effect models.Event) bool {
+			if !matchesRule(cause, rule.CauseKinds, rule.CauseTypes) ||
+				!matchesRule(effect, rule.EffectKinds, rule.EffectTypes) {
+				return false
+			}
+			if rule.SameNamespace && cause.Resource.Namespace != effect.Resource.Namespace {
+				return false
+			}
+			if rule.OwnedByCause && !isOwnedBy(effect, cause) {
+				return false
+			}
+			return true
+		},
+	}
+}
+
+func matchesRule(event models.Event, kinds, types []string) bool {
+	if len(kinds) > 0 && !slices.Contains(kinds, event.Resource.Kind) {
+		return false
+	}
+	return len(types) == 0 || slices.Contains(types, string(event.Type))
+}
+
+func kindsOrAny(kinds []string) string {
+	if len(kinds) == 0 {
+		return "Resource"
+	}
+	return strings.Join(kinds, "/")
+}
+
+// isOwnedBy reports whether the effect's resource has an owner reference to
+// the cause's resource
+func isOwnedBy(effect, cause models.Event) bool {
+	var object struct {
+		Metadata struct {
+			OwnerReferences []struct {
+				UID string `json:"uid"`
+			} `json:"ownerReferences"`
+		} `json:"metadata"`
+	}
+	if len(effect.Data) == 0 || json.Unmarshal(effect.Data, &object) != nil {
+		return false
+	}
+
+	// Owners live in the cluster of the owned resource
+	cluster := models.ClusterOfUID(effect.Resource.UID)
+	for _, ref := range object.Metadata.OwnerReferences {
+		if models.QualifyUID(cluster, ref.UID) == cause.Resource.UID {
+			return true
+		}
+	}
+	return false
 }
 
 // registerDefaultHeuristics registers the default causality heuristics
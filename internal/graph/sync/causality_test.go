@@ -227,3 +227,86 @@ func TestCausalityEngine_Heuristics(t *testing.T) {
 	assert.True(t, heuristicNames["same-resource-transition"])
 	assert.True(t, heuristicNames["config-change-restart"])
 }
+
+func TestBuiltinHeuristicNames(t *testing.T) {
+	engine := NewCausalityEngine(5*time.Minute, 0.5)
+
+	names := make([]string, 0, len(engine.GetHeuristics()))
+	for _, heuristic := range engine.GetHeuristics() {
+		names = append(names, heuristic.Name)
+	}
+	assert.Equal(t, names, BuiltinHeuristicNames())
+}
+
+func TestCausalityEngine_Rules(t *testing.T) {
+	engine := NewCausalityEngine(5*time.Minute, 0.5)
+	ctx := context.Background()
+
+	require.NoError(t, engine.SetRules([]HeuristicRule{{
+		Name:          "db-operator-restart",
+		CauseKinds:    []string{"PostgresCluster"},
+		CauseTypes:    []string{"UPDATE"},
+		EffectKinds:   []string{"StatefulSet"},
+		EffectTypes:   []string{"UPDATE"},
+		SameNamespace: true,
+		OwnedByCause:  true,
+		MaxLag:        2 * time.Minute,
+		Confidence:    0.85,
+	}}))
+
+	now := time.Now()
+	cause := models.Event{
+		ID:        "db-update",
+		Timestamp: now.UnixNano(),
+		Type:      models.EventTypeUpdate,
+		Resource:  models.ResourceMetadata{UID: "db-uid", Kind: "PostgresCluster", Namespace: "data", Name: "db"},
+	}
+	owned := models.Event{
+		ID:        "sts-update",
+		Timestamp: now.Add(20 * time.Second).UnixNano(),
+		Type:      models.EventTypeUpdate,
+		Resource:  models.ResourceMetadata{UID: "sts-uid", Kind: "StatefulSet", Namespace: "data", Name: "db"},
+		Data:      []byte(`{"metadata":{"ownerReferences":[{"kind":"PostgresCluster","name":"db","uid":"db-uid"}]}}`),
+	}
+
+	// Tried before error-propagation, which matches any pair of updates
+	link, err := engine.AnalyzePair(ctx, cause, owned)
+	require.NoError(t, err)
+	require.NotNil(t, link)
+	assert.Equal(t, "db-operator-restart", link.HeuristicUsed)
+	assert.Equal(t, "PostgresCluster triggered StatefulSet", link.Reason)
+	assert.Equal(t, 0.85, link.Confidence)
+
+	// Not owned by the cause
+	unowned := owned
+	unowned.Data = []byte(`{"metadata":{}}`)
+	link, err = engine.AnalyzePair(ctx, cause, unowned)
+	require.NoError(t, err)
+	require.NotNil(t, link)
+	assert.Equal(t, "error-propagation", link.HeuristicUsed)
+
+	links, err := engine.InferCausality(ctx, []models.Event{cause, owned})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+
+	stats := engine.GetHeuristicStats()
+	require.Len(t, stats, len(engine.GetHeuristics()))
+	assert.Equal(t, "db-operator-restart", stats[0].Name)
+	assert.Equal(t, HeuristicSourceConfig, stats[0].Source)
+	assert.Equal(t, int64(120_000), stats[0].MaxLagMs)
+	assert.Equal(t, int64(1), stats[0].Links)
+	assert.Equal(t, HeuristicSourceBuiltin, stats[1].Source)
+
+	// Built-in heuristics cannot be replaced, the previous rules stay active
+	err = engine.SetRules([]HeuristicRule{{Name: "deployment-rollout", MaxLag: time.Minute, Confidence: 0.5}})
+	assert.Error(t, err)
+	assert.Equal(t, "db-operator-restart", engine.GetHeuristics()[0].Name)
+
+	// Rules can't look further back than the lag window
+	err = engine.SetRules([]HeuristicRule{{Name: "slow-operator", MaxLag: 10 * time.Minute, Confidence: 0.5}})
+	assert.Error(t, err)
+
+	// Reloading keeps the link counts
+	require.NoError(t, engine.SetRules([]HeuristicRule{{Name: "db-operator-restart", MaxLag: time.Minute, Confidence: 0.9}}))
+	assert.Equal(t, int64(1), engine.GetHeuristicStats()[0].Links)
+}
@@ -0,0 +1,260 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// compactionEvent is a ChangeEvent considered for compaction
+type compactionEvent struct {
+	id             string
+	timestamp      int64
+	statusOnly     bool
+	compactedCount int64 // events the node already stands for, 0 if it isn't a summary
+	firstTimestamp int64
+}
+
+// Compact collapses each run of consecutive status-only ChangeEvents of a
+// resource into its newest event, which becomes a summary node holding the
+// number of events it replaces (compactedCount) and the timestamp of the
+// oldest one (firstTimestamp). The summary node keeps the newest status and
+// data. PRECEDED_BY edges are re-linked so the chain skips the removed events;
+// their causality edges are dropped. The removed events are archived first
+// when an archive is configured. Resources are compacted in batches of
+// compactionBatchSize, in the order of their UIDs.
+func (r *retentionManager) Compact(ctx context.Context) (int, error) {
+	if r.compactionAge <= 0 {
+		return 0, nil
+	}
+	cutoffNs := time.Now().Add(-r.compactionAge).UnixNano()
+
+	removed := 0
+	afterUID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+
+		result, err := r.client.ExecuteQuery(ctx, graph.GraphQuery{
+			Query: `
+				MATCH (r:ResourceIdentity)-[:CHANGED]->(e:ChangeEvent)
+				WHERE e.timestamp < $cutoffNs AND r.uid > $afterUID
+				WITH DISTINCT r.uid AS uid
+				ORDER BY uid
+				LIMIT $batchSize
+				RETURN uid
+			`,
+			Parameters: map[string]interface{}{
+				"cutoffNs":  cutoffNs,
+				"afterUID":  afterUID,
+				"batchSize": r.compactionBatchSize,
+			},
+		})
+		if err != nil {
+			return removed, fmt.Errorf("failed to query resources to compact: %w", err)
+		}
+
+		uids := make([]string, 0, len(result.Rows))
+		for _, row := range result.Rows {
+			if len(row) == 0 {
+				continue
+			}
+			if uid, ok := row[0].(string); ok {
+				uids = append(uids, uid)
+			}
+		}
+		if len(uids) == 0 {
+			break
+		}
+
+		count, err := r.compactResources(ctx, uids, cutoffNs)
+		removed += count
+		if err != nil {
+			return removed, err
+		}
+		if len(result.Rows) < r.compactionBatchSize {
+			break
+		}
+		afterUID = uids[len(uids)-1]
+	}
+
+	if removed > 0 {
+		r.logger.Info("Compacted %d status-only change events", removed)
+	}
+	return removed, nil
+}
+
+// compactResources compacts the change events older than the cutoff of a
+// batch of resources
+func (r *retentionManager) compactResources(ctx context.Context, uids []string, cutoffNs int64) (int, error) {
+	result, err := r.client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (r:ResourceIdentity)-[:CHANGED]->(e:ChangeEvent)
+			WHERE r.uid IN $uids AND e.timestamp < $cutoffNs
+			RETURN r.uid, e.id, e.timestamp, e.eventType, e.configChanged, e.compactedCount, e.firstTimestamp
+			ORDER BY r.uid, e.timestamp
+		`,
+		Parameters: map[string]interface{}{
+			"uids":     uids,
+			"cutoffNs": cutoffNs,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query change events: %w", err)
+	}
+
+	removed := 0
+	var run []compactionEvent
+	flush := func() error {
+		if len(run) > 1 {
+			if err := r.compactRun(ctx, run); err != nil {
+				return err
+			}
+			removed += len(run) - 1
+		}
+		run = run[:0]
+		return nil
+	}
+
+	lastUID := ""
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		uid, _ := row[0].(string)
+		id, _ := row[1].(string)
+		eventType, _ := row[3].(string)
+		configChanged, _ := row[4].(bool)
+		event := compactionEvent{
+			id:             id,
+			timestamp:      toInt64(row[2]),
+			statusOnly:     eventType == string(models.EventTypeUpdate) && !configChanged,
+			compactedCount: toInt64(row[5]),
+			firstTimestamp: toInt64(row[6]),
+		}
+
+		if uid != lastUID || !event.statusOnly {
+			if err := flush(); err != nil {
+				return removed, err
+			}
+			lastUID = uid
+		}
+		if event.statusOnly {
+			run = append(run, event)
+		}
+	}
+	if err := flush(); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
+
+// compactRun merges a run of status-only events, oldest first, into the last one
+func (r *retentionManager) compactRun(ctx context.Context, run []compactionEvent) error {
+	first, keep := run[0], run[len(run)-1]
+
+	count := int64(0)
+	firstTimestamp := first.timestamp
+	for _, event := range run {
+		count += max(event.compactedCount, 1)
+		if event.firstTimestamp > 0 {
+			firstTimestamp = min(firstTimestamp, event.firstTimestamp)
+		}
+	}
+
+	removeIDs := make([]string, 0, len(run)-1)
+	for _, event := range run[:len(run)-1] {
+		removeIDs = append(removeIDs, event.id)
+	}
+
+	if r.archive != nil {
+		result, err := r.client.ExecuteQuery(ctx, graph.GraphQuery{
+			Query: `
+				MATCH (e:ChangeEvent)
+				WHERE e.id IN $ids
+				OPTIONAL MATCH (r:ResourceIdentity)-[:CHANGED]->(e)
+				RETURN e, r
+			`,
+			Parameters: map[string]interface{}{
+				"ids": removeIDs,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query compacted events: %w", err)
+		}
+		events, _ := archivedEvents(RetentionSourceChange, result.Rows)
+		if err := r.archive.Archive(ctx, events); err != nil {
+			return fmt.Errorf("failed to archive compacted events: %w", err)
+		}
+	}
+
+	// Link the summary to the event preceding the run
+	result, err := r.client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (first:ChangeEvent {id: $firstID})-[p:PRECEDED_BY]->(previous:ChangeEvent)
+			RETURN previous.id, p.durationMs
+		`,
+		Parameters: map[string]interface{}{
+			"firstID": first.id,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query predecessor of %s: %w", first.id, err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		previousID, _ := row[0].(string)
+		if _, err := r.client.ExecuteQuery(ctx, graph.CreatePrecededByEdgeQuery(keep.id, previousID, toInt64(row[1]))); err != nil {
+			return fmt.Errorf("failed to link %s to %s: %w", keep.id, previousID, err)
+		}
+	}
+
+	_, err = r.client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (e:ChangeEvent {id: $keepID})
+			SET e.compactedCount = $count, e.firstTimestamp = $firstTimestamp
+		`,
+		Parameters: map[string]interface{}{
+			"keepID":         keep.id,
+			"count":          count,
+			"firstTimestamp": firstTimestamp,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update summary event %s: %w", keep.id, err)
+	}
+
+	_, err = r.client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (e:ChangeEvent)
+			WHERE e.id IN $ids
+			DETACH DELETE e
+		`,
+		Parameters: map[string]interface{}{
+			"ids": removeIDs,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete compacted events: %w", err)
+	}
+	return nil
+}
+
+// toInt64 converts a numeric query result value, 0 for null
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
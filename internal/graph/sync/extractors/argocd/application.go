@@ -236,6 +236,7 @@ func (e *ArgoCDApplicationExtractor) extractManagedResources(
 		Query: `
 			MATCH (r:ResourceIdentity)
 			WHERE NOT r.deleted
+			  AND coalesce(r.cluster, '') = $cluster
 			  AND r.labels CONTAINS $labelQuery
 			OPTIONAL MATCH (owner:ResourceIdentity)-[:OWNS]->(r)
 			WITH r, owner
@@ -244,6 +245,7 @@ func (e *ArgoCDApplicationExtractor) extractManagedResources(
 		`,
 		Parameters: map[string]interface{}{
 			"labelQuery": fmt.Sprintf(`%q:%q`, argoCDInstanceLabel, appName),
+			"cluster":    event.Resource.Cluster,
 		},
 	}
 
@@ -0,0 +1,474 @@
+package extractors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// DefaultConfigExtractorPriority runs configured extractors after the
+// built-in ones
+const DefaultConfigExtractorPriority = 300
+
+// ReferenceType selects how a ReferenceRule finds its targets
+type ReferenceType string
+
+const (
+	// ReferenceTypePath looks up resources by the names found at the path
+	ReferenceTypePath ReferenceType = "path"
+
+	// ReferenceTypeSelector selects resources by the labels found at the
+	// path, either a label map or a LabelSelector with matchLabels
+	ReferenceTypeSelector ReferenceType = "selector"
+
+	// ReferenceTypeOwner looks up resources by the objects found at the path,
+	// which have kind, name, namespace and uid fields like ownerReferences
+	ReferenceTypeOwner ReferenceType = "owner"
+)
+
+// RelationshipRule declares the references of a resource kind, so that
+// relationships of custom resources can be modelled without a Go extractor
+type RelationshipRule struct {
+	Name     string
+	Group    string
+	Kind     string
+	Priority int
+	Refs     []ReferenceRule
+}
+
+// ReferenceRule declares a reference from a resource to others
+type ReferenceRule struct {
+	Type ReferenceType
+
+	// Path locates the reference: dot-separated fields, [N] for list
+	// elements, [*] for all of them and ['key'] for keys containing dots
+	Path string
+
+	// TargetKind and TargetGroup restrict the referenced resources. Owner
+	// references without TargetKind use the kind of the reference.
+	TargetKind  string
+	TargetGroup string
+
+	// Namespace of the referenced resources, the resource's own if empty
+	Namespace     string
+	ClusterScoped bool
+
+	Edge graph.EdgeType
+
+	// Reverse points the edge from the referenced resource to the resource
+	Reverse bool
+}
+
+// ConfigExtractor extracts the relationships declared by a RelationshipRule
+type ConfigExtractor struct {
+	*BaseExtractor
+	group string
+	kind  string
+	refs  []compiledReference
+}
+
+type compiledReference struct {
+	ReferenceRule
+	segments []pathSegment
+}
+
+// NewConfigExtractor creates an extractor for a RelationshipRule
+func NewConfigExtractor(rule RelationshipRule) (*ConfigExtractor, error) {
+	if rule.Kind == "" {
+		return nil, fmt.Errorf("kind must not be empty")
+	}
+
+	name := rule.Name
+	if name == "" {
+		name = "config-" + strings.ToLower(rule.Kind)
+	}
+	priority := rule.Priority
+	if priority == 0 {
+		priority = DefaultConfigExtractorPriority
+	}
+
+	refs := make([]compiledReference, 0, len(rule.Refs))
+	for i, ref := range rule.Refs {
+		segments, err := parsePath(ref.Path)
+		if err != nil {
+			return nil, fmt.Errorf("refs[%d]: invalid path %q: %w", i, ref.Path, err)
+		}
+
+		if ref.Type == "" {
+			ref.Type = ReferenceTypePath
+		}
+		switch ref.Type {
+		case ReferenceTypePath, ReferenceTypeSelector:
+			if ref.TargetKind == "" {
+				return nil, fmt.Errorf("refs[%d]: targetKind must not be empty", i)
+			}
+		case ReferenceTypeOwner:
+		default:
+			return nil, fmt.Errorf("refs[%d]: unknown reference type %q", i, ref.Type)
+		}
+
+		if ref.Edge == "" {
+			ref.Edge = graph.EdgeTypeReferencesSpec
+			if ref.Type == ReferenceTypeSelector {
+				ref.Edge = graph.EdgeTypeSelects
+			}
+		}
+		switch ref.Edge {
+		case graph.EdgeTypeReferencesSpec, graph.EdgeTypeManages, graph.EdgeTypeSelects, graph.EdgeTypeOwns:
+		default:
+			return nil, fmt.Errorf("refs[%d]: unsupported edge type %q", i, ref.Edge)
+		}
+
+		refs = append(refs, compiledReference{ReferenceRule: ref, segments: segments})
+	}
+
+	return &ConfigExtractor{
+		BaseExtractor: NewBaseExtractor(name, priority),
+		group:         rule.Group,
+		kind:          rule.Kind,
+		refs:          refs,
+	}, nil
+}
+
+// Matches checks if the event is of the configured kind
+func (e *ConfigExtractor) Matches(event models.Event) bool {
+	return event.Resource.Kind == e.kind && event.Resource.Group == e.group
+}
+
+// ExtractRelationships resolves the configured references of the resource
+func (e *ConfigExtractor) ExtractRelationships(
+	ctx context.Context,
+	event models.Event,
+	lookup ResourceLookup,
+) ([]graph.Edge, error) {
+	// Delete events may come without the object
+	if len(event.Data) == 0 {
+		return nil, nil
+	}
+
+	edges := []graph.Edge{}
+
+	var resource map[string]interface{}
+	if err := json.Unmarshal(event.Data, &resource); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", e.kind, err)
+	}
+
+	for _, ref := range e.refs {
+		for _, value := range resolvePath(resource, ref.segments) {
+			switch ref.Type {
+			case ReferenceTypeSelector:
+				// Deleted resources no longer select anything
+				if event.Type == models.EventTypeDelete {
+					continue
+				}
+				selected, err := e.extractSelectorEdges(ctx, event, ref, value, lookup)
+				if err != nil {
+					e.Logger().Warn("Failed to resolve selector at %s: %v", ref.Path, err)
+					continue
+				}
+				edges = append(edges, selected...)
+
+			case ReferenceTypeOwner:
+				if edge := e.extractOwnerEdge(ctx, event, ref, value, lookup); edge != nil {
+					edges = append(edges, *edge)
+				}
+
+			default:
+				name, ok := value.(string)
+				if !ok || name == "" {
+					continue
+				}
+				target := findTarget(ctx, lookup, ref, targetNamespace(ref, event), ref.TargetKind, name)
+				if target == nil {
+					continue
+				}
+				if edge := ValidEdgeOrNil(e.createEdge(event, ref, target, nil, false)); edge != nil {
+					edges = append(edges, *edge)
+				}
+			}
+		}
+	}
+
+	return edges, nil
+}
+
+// extractSelectorEdges creates edges to the resources selected by labels
+func (e *ConfigExtractor) extractSelectorEdges(
+	ctx context.Context,
+	event models.Event,
+	ref compiledReference,
+	value interface{},
+	lookup ResourceLookup,
+) ([]graph.Edge, error) {
+	selector, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	if matchLabels, ok := GetNestedMap(selector, "matchLabels"); ok {
+		selector = matchLabels
+	}
+	selectorLabels := ParseLabelsFromMap(selector)
+	if len(selectorLabels) == 0 {
+		// An empty selector would select every resource of the kind
+		return nil, nil
+	}
+
+	params := map[string]interface{}{
+		"kind":    ref.TargetKind,
+		"cluster": event.Resource.Cluster,
+	}
+	conditions := []string{
+		"r.kind = $kind",
+		"coalesce(r.cluster, '') = $cluster",
+		"NOT r.deleted",
+	}
+	if !ref.ClusterScoped {
+		params["namespace"] = targetNamespace(ref, event)
+		conditions = append(conditions, "r.namespace = $namespace")
+	}
+	if ref.TargetGroup != "" {
+		params["apiGroup"] = ref.TargetGroup
+		conditions = append(conditions, "r.apiGroup = $apiGroup")
+	}
+	conditions = append(conditions, BuildLabelQuery(selectorLabels, "r", params))
+
+	result, err := lookup.QueryGraph(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (r:ResourceIdentity)
+			WHERE ` + strings.Join(conditions, "\n\t\t\t  AND ") + `
+			RETURN r
+			LIMIT 500
+		`,
+		Parameters: params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s resources: %w", ref.TargetKind, err)
+	}
+
+	edges := make([]graph.Edge, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		target, err := parseResourceIdentity(row[0])
+		if err != nil {
+			target = &graph.ResourceIdentity{UID: ExtractUID(row), Kind: ref.TargetKind}
+		}
+		if target.UID == "" || target.UID == event.Resource.UID {
+			continue
+		}
+		if edge := ValidEdgeOrNil(e.createEdge(event, ref, target, selectorLabels, false)); edge != nil {
+			edges = append(edges, *edge)
+		}
+	}
+	return edges, nil
+}
+
+// extractOwnerEdge creates an edge to the resource an owner-style reference
+// points to. References with a uid are resolved by uid, others by name.
+func (e *ConfigExtractor) extractOwnerEdge(
+	ctx context.Context,
+	event models.Event,
+	ref compiledReference,
+	value interface{},
+	lookup ResourceLookup,
+) *graph.Edge {
+	reference, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	kind, _ := GetNestedString(reference, "kind")
+	if kind == "" {
+		kind = ref.TargetKind
+	}
+	if kind == "" || (ref.TargetKind != "" && kind != ref.TargetKind) {
+		return nil
+	}
+
+	var target *graph.ResourceIdentity
+	if uid, ok := GetNestedString(reference, "uid"); ok && uid != "" {
+		if resource, err := lookup.FindResourceByUID(ctx, models.QualifyUID(event.Resource.Cluster, uid)); err == nil && resource != nil {
+			if ref.TargetGroup == "" || resource.APIGroup == ref.TargetGroup {
+				target = resource
+			}
+		}
+	}
+	if target == nil {
+		name, ok := GetNestedString(reference, "name")
+		if !ok || name == "" {
+			return nil
+		}
+		namespace := targetNamespace(ref, event)
+		if ns, ok := GetNestedString(reference, "namespace"); ok && ns != "" && !ref.ClusterScoped {
+			namespace = ns
+		}
+		target = findTarget(ctx, lookup, ref, namespace, kind, name)
+	}
+	if target == nil {
+		return nil
+	}
+
+	controller, _ := GetNestedField(reference, "controller")
+	isController, _ := controller.(bool)
+	return ValidEdgeOrNil(e.createEdge(event, ref, target, nil, isController))
+}
+
+// createEdge creates the configured edge between the resource and target
+func (e *ConfigExtractor) createEdge(
+	event models.Event,
+	ref compiledReference,
+	target *graph.ResourceIdentity,
+	selectorLabels map[string]string,
+	controller bool,
+) graph.Edge {
+	fromUID, toUID := event.Resource.UID, target.UID
+	if ref.Reverse {
+		fromUID, toUID = toUID, fromUID
+	}
+
+	switch ref.Edge {
+	case graph.EdgeTypeManages:
+		evidence := CreateEvidenceItem(graph.EvidenceTypeReference,
+			fmt.Sprintf("%s references %s/%s", ref.Path, target.Kind, target.Name), 1.0)
+		return e.CreateInferredEdge(graph.EdgeTypeManages, fromUID, toUID, 1.0, []graph.EvidenceItem{evidence})
+	case graph.EdgeTypeSelects:
+		return e.CreateObservedEdge(graph.EdgeTypeSelects, fromUID, toUID, graph.SelectsEdge{SelectorLabels: selectorLabels})
+	case graph.EdgeTypeOwns:
+		return e.CreateObservedEdge(graph.EdgeTypeOwns, fromUID, toUID, graph.OwnsEdge{Controller: controller})
+	default:
+		return e.CreateReferencesSpecEdge(fromUID, toUID, ref.Path, target.Kind, target.Name, target.Namespace)
+	}
+}
+
+// findTarget looks up a referenced resource by name. It returns nil if the
+// resource is unknown or of another group than the reference expects.
+func findTarget(
+	ctx context.Context,
+	lookup ResourceLookup,
+	ref compiledReference,
+	namespace, kind, name string,
+) *graph.ResourceIdentity {
+	target, err := lookup.FindResourceByNamespace(ctx, namespace, kind, name)
+	if err != nil || target == nil {
+		return nil
+	}
+	if ref.TargetGroup != "" && target.APIGroup != ref.TargetGroup {
+		return nil
+	}
+	return target
+}
+
+// targetNamespace returns the namespace referenced resources are looked up in
+func targetNamespace(ref compiledReference, event models.Event) string {
+	switch {
+	case ref.ClusterScoped:
+		return ""
+	case ref.Namespace != "":
+		return ref.Namespace
+	default:
+		return event.Resource.Namespace
+	}
+}
+
+// pathSegment is a field name, a list index or a wildcard
+type pathSegment struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parsePath parses a JSONPath-like path such as
+// spec.targets[*].name or metadata.annotations['example.com/owner']
+func parsePath(path string) ([]pathSegment, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	var segments []pathSegment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' || rest[0] == '[' {
+				return nil, fmt.Errorf("empty field name")
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated bracket")
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			switch {
+			case inner == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, pathSegment{field: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index %q", inner)
+				}
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, pathSegment{field: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path is empty")
+	}
+	return segments, nil
+}
+
+// resolvePath returns the values found at the path. Wildcards yield a value
+// per list element or map entry.
+func resolvePath(value interface{}, segments []pathSegment) []interface{} {
+	if value == nil {
+		return nil
+	}
+	if len(segments) == 0 {
+		return []interface{}{value}
+	}
+
+	segment, rest := segments[0], segments[1:]
+	switch {
+	case segment.wildcard:
+		var values []interface{}
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				values = append(values, resolvePath(item, rest)...)
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				values = append(values, resolvePath(item, rest)...)
+			}
+		}
+		return values
+	case segment.isIndex:
+		items, ok := value.([]interface{})
+		if !ok || segment.index >= len(items) {
+			return nil
+		}
+		return resolvePath(items[segment.index], rest)
+	default:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		return resolvePath(obj[segment.field], rest)
+	}
+}
@@ -0,0 +1,257 @@
+package extractors
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rolloutEvent(t *testing.T, spec map[string]interface{}) models.Event {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"name":      "checkout",
+			"namespace": "shop",
+			"uid":       "rollout-uid",
+		},
+		"spec": spec,
+	})
+	require.NoError(t, err)
+
+	return models.Event{
+		ID:   "event-1",
+		Type: models.EventTypeUpdate,
+		Resource: models.ResourceMetadata{
+			UID:       "rollout-uid",
+			Group:     "argoproj.io",
+			Version:   "v1alpha1",
+			Kind:      "Rollout",
+			Namespace: "shop",
+			Name:      "checkout",
+		},
+		Data: data,
+	}
+}
+
+func TestConfigExtractor_Matches(t *testing.T) {
+	extractor, err := NewConfigExtractor(RelationshipRule{
+		Group: "argoproj.io",
+		Kind:  "Rollout",
+		Refs:  []ReferenceRule{{Path: "spec.workloadRef.name", TargetKind: "Deployment"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "config-rollout", extractor.Name())
+	assert.Equal(t, DefaultConfigExtractorPriority, extractor.Priority())
+	assert.True(t, extractor.Matches(models.Event{Resource: models.ResourceMetadata{Group: "argoproj.io", Kind: "Rollout"}}))
+	assert.False(t, extractor.Matches(models.Event{Resource: models.ResourceMetadata{Group: "apps", Kind: "Rollout"}}))
+}
+
+func TestConfigExtractor_PathReferences(t *testing.T) {
+	extractor, err := NewConfigExtractor(RelationshipRule{
+		Group: "argoproj.io",
+		Kind:  "Rollout",
+		Refs: []ReferenceRule{
+			{Path: "spec.workloadRef.name", TargetKind: "Deployment", Edge: graph.EdgeTypeManages},
+			{Path: "spec.strategy.canary.trafficRouting.services[*]", TargetKind: "Service"},
+			{Path: "spec.analysis.templates[0].templateName", TargetKind: "ClusterAnalysisTemplate", ClusterScoped: true},
+		},
+	})
+	require.NoError(t, err)
+
+	lookup := NewMockResourceLookup()
+	lookup.AddResource(&graph.ResourceIdentity{UID: "deploy-uid", Kind: "Deployment", APIGroup: "apps", Namespace: "shop", Name: "checkout"})
+	lookup.AddResource(&graph.ResourceIdentity{UID: "stable-uid", Kind: "Service", Namespace: "shop", Name: "checkout-stable"})
+	lookup.AddResource(&graph.ResourceIdentity{UID: "template-uid", Kind: "ClusterAnalysisTemplate", Name: "success-rate"})
+
+	event := rolloutEvent(t, map[string]interface{}{
+		"workloadRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "checkout"},
+		"strategy": map[string]interface{}{
+			"canary": map[string]interface{}{
+				"trafficRouting": map[string]interface{}{
+					// The canary service does not exist yet
+					"services": []interface{}{"checkout-stable", "checkout-canary"},
+				},
+			},
+		},
+		"analysis": map[string]interface{}{
+			"templates": []interface{}{map[string]interface{}{"templateName": "success-rate"}},
+		},
+	})
+
+	edges, err := extractor.ExtractRelationships(context.Background(), event, lookup)
+	require.NoError(t, err)
+	require.Len(t, edges, 3)
+
+	assert.Equal(t, graph.EdgeTypeManages, edges[0].Type)
+	assert.Equal(t, "rollout-uid", edges[0].FromUID)
+	assert.Equal(t, "deploy-uid", edges[0].ToUID)
+	var manages graph.ManagesEdge
+	require.NoError(t, json.Unmarshal(edges[0].Properties, &manages))
+	assert.Equal(t, 1.0, manages.Confidence)
+	require.Len(t, manages.Evidence, 1)
+	assert.Equal(t, graph.EvidenceTypeReference, manages.Evidence[0].Type)
+
+	assert.Equal(t, graph.EdgeTypeReferencesSpec, edges[1].Type)
+	assert.Equal(t, "stable-uid", edges[1].ToUID)
+	var refProps graph.ReferencesSpecEdge
+	require.NoError(t, json.Unmarshal(edges[1].Properties, &refProps))
+	assert.Equal(t, "spec.strategy.canary.trafficRouting.services[*]", refProps.FieldPath)
+	assert.Equal(t, "checkout-stable", refProps.RefName)
+
+	assert.Equal(t, "template-uid", edges[2].ToUID)
+}
+
+func TestConfigExtractor_SelectorReferences(t *testing.T) {
+	extractor, err := NewConfigExtractor(RelationshipRule{
+		Group: "argoproj.io",
+		Kind:  "Rollout",
+		Refs:  []ReferenceRule{{Type: ReferenceTypeSelector, Path: "spec.selector", TargetKind: "Pod"}},
+	})
+	require.NoError(t, err)
+
+	lookup := NewMockResourceLookup()
+	lookup.SetQueryResult(&graph.QueryResult{
+		Columns: []string{"r"},
+		Rows:    [][]interface{}{{"pod-1"}, {"pod-2"}},
+	})
+
+	event := rolloutEvent(t, map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{"app": "checkout"},
+		},
+	})
+
+	edges, err := extractor.ExtractRelationships(context.Background(), event, lookup)
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+	assert.Equal(t, graph.EdgeTypeSelects, edges[0].Type)
+	assert.Equal(t, "pod-1", edges[0].ToUID)
+
+	var props graph.SelectsEdge
+	require.NoError(t, json.Unmarshal(edges[0].Properties, &props))
+	assert.Equal(t, map[string]string{"app": "checkout"}, props.SelectorLabels)
+
+	// Deleted resources select nothing
+	event.Type = models.EventTypeDelete
+	edges, err = extractor.ExtractRelationships(context.Background(), event, lookup)
+	require.NoError(t, err)
+	assert.Empty(t, edges)
+}
+
+func TestConfigExtractor_OwnerReferences(t *testing.T) {
+	extractor, err := NewConfigExtractor(RelationshipRule{
+		Group: "argoproj.io",
+		Kind:  "Rollout",
+		Refs: []ReferenceRule{
+			{Type: ReferenceTypeOwner, Path: "spec.owners[*]", Edge: graph.EdgeTypeOwns, Reverse: true},
+		},
+	})
+	require.NoError(t, err)
+
+	lookup := NewMockResourceLookup()
+	lookup.AddResource(&graph.ResourceIdentity{UID: "app-uid", Kind: "Application", Namespace: "argocd", Name: "shop"})
+	lookup.AddResource(&graph.ResourceIdentity{UID: "team-uid", Kind: "Team", Namespace: "shop", Name: "payments"})
+
+	event := rolloutEvent(t, map[string]interface{}{
+		"owners": []interface{}{
+			// Resolved by uid
+			map[string]interface{}{"kind": "Application", "name": "renamed", "uid": "app-uid", "controller": true},
+			// Resolved by name in the rollout's namespace
+			map[string]interface{}{"kind": "Team", "name": "payments"},
+			// Unknown
+			map[string]interface{}{"kind": "Team", "name": "missing"},
+		},
+	})
+
+	edges, err := extractor.ExtractRelationships(context.Background(), event, lookup)
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+
+	assert.Equal(t, graph.EdgeTypeOwns, edges[0].Type)
+	assert.Equal(t, "app-uid", edges[0].FromUID)
+	assert.Equal(t, "rollout-uid", edges[0].ToUID)
+	var props graph.OwnsEdge
+	require.NoError(t, json.Unmarshal(edges[0].Properties, &props))
+	assert.True(t, props.Controller)
+
+	assert.Equal(t, "team-uid", edges[1].FromUID)
+}
+
+func TestConfigExtractor_EmptyData(t *testing.T) {
+	extractor, err := NewConfigExtractor(RelationshipRule{
+		Group: "argoproj.io",
+		Kind:  "Rollout",
+		Refs:  []ReferenceRule{{Path: "spec.workloadRef.name", TargetKind: "Deployment"}},
+	})
+	require.NoError(t, err)
+
+	event := rolloutEvent(t, nil)
+	event.Type = models.EventTypeDelete
+	event.Data = nil
+
+	edges, err := extractor.ExtractRelationships(context.Background(), event, NewMockResourceLookup())
+	require.NoError(t, err)
+	assert.Empty(t, edges)
+}
+
+func TestNewConfigExtractor_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  ReferenceRule
+	}{
+		{name: "unterminated bracket", ref: ReferenceRule{Path: "spec.refs[*", TargetKind: "Secret"}},
+		{name: "invalid index", ref: ReferenceRule{Path: "spec.refs[-1]", TargetKind: "Secret"}},
+		{name: "empty field", ref: ReferenceRule{Path: "spec..name", TargetKind: "Secret"}},
+		{name: "missing target kind", ref: ReferenceRule{Path: "spec.secretName"}},
+		{name: "unsupported edge", ref: ReferenceRule{Path: "spec.secretName", TargetKind: "Secret", Edge: graph.EdgeTypeChanged}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConfigExtractor(RelationshipRule{Kind: "Rollout", Refs: []ReferenceRule{tt.ref}})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"example.com/owner": "team-a"},
+		},
+		"spec": map[string]interface{}{
+			"targets": []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"name": "b"},
+			},
+		},
+	}
+
+	tests := []struct {
+		path     string
+		expected []interface{}
+	}{
+		{path: "spec.targets[*].name", expected: []interface{}{"a", "b"}},
+		{path: "$.spec.targets[1].name", expected: []interface{}{"b"}},
+		{path: "metadata.annotations['example.com/owner']", expected: []interface{}{"team-a"}},
+		{path: "spec.targets[2].name", expected: nil},
+		{path: "spec.missing", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			segments, err := parsePath(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolvePath(obj, segments))
+		})
+	}
+}
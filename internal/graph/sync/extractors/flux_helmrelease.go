@@ -263,6 +263,7 @@ func (e *FluxHelmReleaseExtractor) extractManagedResources(
 		Query: `
 			MATCH (r:ResourceIdentity)
 			WHERE (r.namespace = $namespace OR r.namespace = "")
+			  AND coalesce(r.cluster, '') = $cluster
 			  AND NOT r.deleted
 			  AND r.uid <> $helmReleaseUID
 			OPTIONAL MATCH (owner:ResourceIdentity)-[:OWNS]->(r)
@@ -273,6 +274,7 @@ func (e *FluxHelmReleaseExtractor) extractManagedResources(
 		`,
 		Parameters: map[string]interface{}{
 			"namespace":      targetNamespace,
+			"cluster":        event.Resource.Cluster,
 			"helmReleaseUID": event.Resource.UID,
 		},
 	}
@@ -149,6 +149,7 @@ func (e *FluxKustomizationExtractor) extractManagedResources(
 		Query: `
 			MATCH (r:ResourceIdentity)
 			WHERE (r.namespace = $namespace OR r.namespace = "")
+			  AND coalesce(r.cluster, '') = $cluster
 			  AND NOT r.deleted
 			  AND r.uid <> $kustomizationUID
 			OPTIONAL MATCH (owner:ResourceIdentity)-[:OWNS]->(r)
@@ -159,6 +160,7 @@ func (e *FluxKustomizationExtractor) extractManagedResources(
 		`,
 		Parameters: map[string]interface{}{
 			"namespace":         targetNamespace,
+			"cluster":           event.Resource.Cluster,
 			"kustomizationUID": event.Resource.UID,
 		},
 	}
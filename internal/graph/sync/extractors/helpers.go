@@ -2,6 +2,8 @@ package extractors
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/moolen/spectre/internal/graph"
@@ -135,9 +137,10 @@ func ParseLabelsFromMap(labelsInterface interface{}) map[string]string {
 }
 
 // BuildLabelQuery creates a Cypher query fragment for label matching
-// Returns a WHERE clause fragment
+// Returns a WHERE clause fragment whose values are bound as parameters
 // nodeAlias: the node variable name to use (e.g., "p", "r")
-func BuildLabelQuery(labels map[string]string, nodeAlias string) string {
+// params: the query parameters, the label parameters are added to it
+func BuildLabelQuery(labels map[string]string, nodeAlias string, params map[string]interface{}) string {
 	if len(labels) == 0 {
 		return ""
 	}
@@ -146,10 +149,20 @@ func BuildLabelQuery(labels map[string]string, nodeAlias string) string {
 		nodeAlias = "r"
 	}
 
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	conditions := make([]string, 0, len(labels))
-	for key, value := range labels {
+	for i, key := range keys {
 		// JSON substring matching for labels stored as JSON
-		conditions = append(conditions, nodeAlias+`.labels CONTAINS '"`+key+`":"`+value+`"'`)
+		param := fmt.Sprintf("label%d", i)
+		keyJSON, _ := json.Marshal(key)
+		valueJSON, _ := json.Marshal(labels[key])
+		params[param] = string(keyJSON) + ":" + string(valueJSON)
+		conditions = append(conditions, nodeAlias+".labels CONTAINS $"+param)
 	}
 
 	return strings.Join(conditions, " AND ")
@@ -11,6 +11,9 @@ import (
 // graphClientLookup implements ResourceLookup using graph.Client
 type graphClientLookup struct {
 	client graph.Client
+
+	// cluster restricts name-based lookups to resources of a single cluster
+	cluster string
 }
 
 // NewGraphClientLookup creates a ResourceLookup adapter for graph.Client
@@ -18,6 +21,17 @@ func NewGraphClientLookup(client graph.Client) ResourceLookup {
 	return &graphClientLookup{client: client}
 }
 
+// ForCluster returns a ResourceLookup whose name-based lookups only resolve
+// resources of the given cluster. Lookups that are not backed by the graph
+// client are returned unchanged.
+func ForCluster(lookup ResourceLookup, cluster string) ResourceLookup {
+	l, ok := lookup.(*graphClientLookup)
+	if !ok || l.cluster == cluster {
+		return lookup
+	}
+	return &graphClientLookup{client: l.client, cluster: cluster}
+}
+
 func (l *graphClientLookup) FindResourceByUID(ctx context.Context, uid string) (*graph.ResourceIdentity, error) {
 	query := graph.FindResourceByUIDQuery(uid)
 	result, err := l.client.ExecuteQuery(ctx, query)
@@ -53,6 +67,7 @@ func (l *graphClientLookup) FindResourceByNamespace(ctx context.Context, namespa
 			WHERE r.namespace = $namespace
 			  AND r.kind = $kind
 			  AND r.name = $name
+			  AND coalesce(r.cluster, '') = $cluster
 			RETURN r
 			LIMIT 1
 		`,
@@ -60,6 +75,7 @@ func (l *graphClientLookup) FindResourceByNamespace(ctx context.Context, namespa
 			"namespace": namespace,
 			"kind":      kind,
 			"name":      name,
+			"cluster":   l.cluster,
 		},
 	}
 
@@ -154,6 +170,9 @@ func parseResourceIdentity(data interface{}) (*graph.ResourceIdentity, error) {
 	if namespace, ok := props["namespace"].(string); ok {
 		resource.Namespace = namespace
 	}
+	if cluster, ok := props["cluster"].(string); ok {
+		resource.Cluster = cluster
+	}
 	if name, ok := props["name"].(string); ok {
 		resource.Name = name
 	}
@@ -81,30 +81,35 @@ func (e *NetworkPolicyExtractor) ExtractRelationships(
 				MATCH (p:ResourceIdentity)
 				WHERE p.kind = 'Pod'
 				  AND p.namespace = $namespace
+				  AND coalesce(p.cluster, '') = $cluster
 				  AND NOT p.deleted
 				RETURN p.uid
 				LIMIT 500
 			`,
 			Parameters: map[string]interface{}{
 				"namespace": event.Resource.Namespace,
+				"cluster":   event.Resource.Cluster,
 			},
 		}
 	} else {
 		// Selector with labels
-		labelQuery := extractors.BuildLabelQuery(selectorLabels, "p")
+		params := map[string]interface{}{
+			"namespace": event.Resource.Namespace,
+			"cluster":   event.Resource.Cluster,
+		}
+		labelQuery := extractors.BuildLabelQuery(selectorLabels, "p", params)
 		query = graph.GraphQuery{
 			Query: `
 				MATCH (p:ResourceIdentity)
 				WHERE p.kind = 'Pod'
 				  AND p.namespace = $namespace
+				  AND coalesce(p.cluster, '') = $cluster
 				  AND NOT p.deleted
 				  AND ` + labelQuery + `
 				RETURN p.uid
 				LIMIT 500
 			`,
-			Parameters: map[string]interface{}{
-				"namespace": event.Resource.Namespace,
-			},
+			Parameters: params,
 		}
 	}
 
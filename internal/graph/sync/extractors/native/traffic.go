@@ -59,7 +59,7 @@ func (e *ServiceExtractor) ExtractRelationships(
 		return edges, nil
 	}
 
-	// Query for ALL Pods in the same namespace
+	// Query for ALL Pods in the same cluster and namespace
 	// We'll filter by label selector in-memory since Cypher JSON substring matching
 	// is unreliable with special characters in label keys (e.g., "app.kubernetes.io/name")
 	query := graph.GraphQuery{
@@ -67,11 +67,13 @@ func (e *ServiceExtractor) ExtractRelationships(
 			MATCH (r:ResourceIdentity)
 			WHERE r.kind = 'Pod'
 			  AND r.namespace = $namespace
+			  AND coalesce(r.cluster, '') = $cluster
 			RETURN r.uid, r.labels, r.deleted
 			LIMIT 500
 		`,
 		Parameters: map[string]interface{}{
 			"namespace": event.Resource.Namespace,
+			"cluster":   event.Resource.Cluster,
 		},
 	}
 
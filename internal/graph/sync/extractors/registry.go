@@ -41,6 +41,9 @@ func (r *ExtractorRegistry) Register(extractor RelationshipExtractor) {
 func (r *ExtractorRegistry) Extract(ctx context.Context, event models.Event) ([]graph.Edge, error) {
 	var allEdges []graph.Edge
 
+	// Name-based lookups must never resolve to a resource of another cluster
+	lookup := ForCluster(r.lookup, event.Resource.Cluster)
+
 	for _, extractor := range r.extractors {
 		if !extractor.Matches(event) {
 			continue
@@ -49,7 +52,7 @@ func (r *ExtractorRegistry) Extract(ctx context.Context, event models.Event) ([]
 		r.logger.Debug("Applying extractor %s to %s/%s/%s",
 			extractor.Name(), event.Resource.Namespace, event.Resource.Kind, event.Resource.Name)
 
-		edges, err := extractor.ExtractRelationships(ctx, event, r.lookup)
+		edges, err := extractor.ExtractRelationships(ctx, event, lookup)
 		if err != nil {
 			// Log but continue - partial extraction is acceptable
 			r.logger.Warn("Extractor %s failed for event %s: %v", extractor.Name(), event.ID, err)
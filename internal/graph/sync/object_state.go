@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ObjectStateReader reads the last recorded state of resources from the
+// graph. The watcher diffs its initial list after a restart against it, so
+// that only the objects that changed while it was down are written again.
+type ObjectStateReader struct {
+	client  graph.Client
+	cluster string
+}
+
+// NewObjectStateReader creates a reader for the resources of a cluster,
+// empty for single-cluster installations
+func NewObjectStateReader(client graph.Client, cluster string) *ObjectStateReader {
+	return &ObjectStateReader{
+		client:  client,
+		cluster: cluster,
+	}
+}
+
+// LastKnownObjects returns the resources of a kind that are not deleted,
+// keyed by their Kubernetes UID. Objects are decoded from the latest change
+// event of the resource. Resources without change events, such as
+// Kubernetes Events, are returned with their identity only and carry no
+// resourceVersion. Resources written before clusters were recorded have no
+// cluster property and belong to the single-cluster installation.
+func (r *ObjectStateReader) LastKnownObjects(ctx context.Context, gvk schema.GroupVersionKind) (map[string]*unstructured.Unstructured, error) {
+	result, err := r.client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			MATCH (r:ResourceIdentity)
+			WHERE coalesce(r.apiGroup, '') = $group AND r.kind = $kind
+			  AND coalesce(r.cluster, '') = $cluster
+			  AND coalesce(r.deleted, false) = false
+			OPTIONAL MATCH (r)-[:CHANGED]->(e:ChangeEvent)
+			WITH r, max(e.timestamp) AS latest
+			OPTIONAL MATCH (r)-[:CHANGED]->(last:ChangeEvent)
+			WHERE last.timestamp = latest
+			RETURN r.uid, r.namespace, r.name, r.labels, last.data
+		`,
+		Parameters: map[string]interface{}{
+			"group":   gvk.Group,
+			"kind":    gvk.Kind,
+			"cluster": r.cluster,
+		},
+		Timeout: 30000, // 30 seconds - kinds such as Pods can have many resources
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recorded %s: %w", gvk.Kind, err)
+	}
+
+	objects := make(map[string]*unstructured.Unstructured, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		uid, _ := row[0].(string)
+		if uid == "" {
+			continue
+		}
+		uid = models.LocalUID(uid)
+
+		if data, _ := row[4].(string); data != "" {
+			obj := &unstructured.Unstructured{}
+			if err := json.Unmarshal([]byte(data), &obj.Object); err == nil {
+				obj.SetGroupVersionKind(gvk)
+				objects[uid] = obj
+				continue
+			}
+		}
+
+		// Identity only, e.g. Kubernetes Events which are stored without their object
+		namespace, _ := row[1].(string)
+		name, _ := row[2].(string)
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetUID(types.UID(uid))
+		if labelsJSON, _ := row[3].(string); labelsJSON != "" {
+			var labels map[string]string
+			if err := json.Unmarshal([]byte(labelsJSON), &labels); err == nil && len(labels) > 0 {
+				obj.SetLabels(labels)
+			}
+		}
+		objects[uid] = obj
+	}
+	return objects, nil
+}
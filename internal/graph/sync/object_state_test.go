@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func stateEvent(id string, eventType models.EventType, kind, uid, resourceVersion string, at int64) models.Event {
+	data, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name": uid, "namespace": "default", "uid": uid, "resourceVersion": resourceVersion,
+		},
+	})
+	return models.Event{
+		ID:        id,
+		Timestamp: at,
+		Type:      eventType,
+		Resource:  models.ResourceMetadata{Version: "v1", Kind: kind, Namespace: "default", Name: uid, UID: uid},
+		Data:      data,
+	}
+}
+
+func TestObjectStateReader_LastKnownObjects(t *testing.T) {
+	client := newRetentionTestClient(t)
+	pipeline := NewPipeline(DefaultPipelineConfig(), client)
+
+	now := time.Now().UnixNano()
+	require.NoError(t, pipeline.ProcessBatch(context.Background(), []models.Event{
+		stateEvent("e1", models.EventTypeCreate, "Pod", "pod-1", "10", now),
+		stateEvent("e2", models.EventTypeUpdate, "Pod", "pod-1", "12", now+1),
+		stateEvent("e3", models.EventTypeCreate, "Pod", "pod-2", "11", now+2),
+		stateEvent("e4", models.EventTypeDelete, "Pod", "pod-2", "13", now+3),
+		stateEvent("e5", models.EventTypeCreate, "Service", "svc-1", "14", now+4),
+	}))
+
+	objects, err := NewObjectStateReader(client, "").LastKnownObjects(context.Background(), schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.Contains(t, objects, "pod-1")
+	assert.Equal(t, "12", objects["pod-1"].GetResourceVersion())
+	assert.Equal(t, "Pod", objects["pod-1"].GetKind())
+
+	// Resources of other clusters are not returned
+	objects, err = NewObjectStateReader(client, "prod").LastKnownObjects(context.Background(), schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestObjectStateReader_LegacyNodes(t *testing.T) {
+	client := newRetentionTestClient(t)
+
+	// Nodes written by older versions have no cluster and no deleted property
+	_, err := client.ExecuteQuery(context.Background(), graph.GraphQuery{Query: `
+		CREATE (p:ResourceIdentity {uid: 'pod-1', kind: 'Pod', apiGroup: '', version: 'v1', namespace: 'default', name: 'pod-1'})
+		CREATE (p)-[:CHANGED]->(:ChangeEvent {id: 'e1', timestamp: 1, eventType: 'CREATE', data: '{"metadata":{"name":"pod-1","namespace":"default","uid":"pod-1","resourceVersion":"7"}}'})
+		CREATE (:ResourceIdentity {uid: 'pod-2', kind: 'Pod', apiGroup: '', version: 'v1', namespace: 'default', name: 'pod-2', deleted: true})
+	`})
+	require.NoError(t, err)
+
+	objects, err := NewObjectStateReader(client, "").LastKnownObjects(context.Background(), schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.Contains(t, objects, "pod-1")
+	assert.Equal(t, "7", objects["pod-1"].GetResourceVersion())
+}
@@ -21,8 +21,13 @@ type pipeline struct {
 	builder   GraphBuilder
 	causality CausalityEngine
 	retention RetentionManager
+	updates   *broadcaster
 	logger    *logging.Logger
 
+	// rulesErr is the error of loading the configured causality heuristics,
+	// returned by Start
+	rulesErr error
+
 	// Statistics (atomic counters)
 	stats     PipelineStats
 	statsLock sync.RWMutex
@@ -39,13 +44,18 @@ func NewPipeline(config PipelineConfig, client graph.Client) Pipeline {
 		config:    config,
 		client:    client,
 		schema:    graph.NewSchema(client),
-		builder:   NewGraphBuilderWithClient(client), // Pass client to builder for Node lookups
+		builder:   NewGraphBuilderWithClient(client, config.Extractors...), // Pass client to builder for Node lookups
 		causality: NewCausalityEngine(config.CausalityMaxLag, config.CausalityMinConfidence),
-		retention: NewRetentionManager(client, config.RetentionWindow),
+		retention: NewRetentionManagerWithPolicies(client, config.RetentionWindow, config.RetentionPolicies, config.CompactionAge, config.Archive),
+		updates:   newBroadcaster(),
 		logger:    logging.GetLogger("graph.sync.pipeline"),
 		stats:     PipelineStats{},
 	}
 
+	if len(config.CausalityRules) > 0 {
+		p.rulesErr = p.causality.SetRules(config.CausalityRules)
+	}
+
 	return p
 }
 
@@ -53,6 +63,10 @@ func NewPipeline(config PipelineConfig, client graph.Client) Pipeline {
 func (p *pipeline) Start(ctx context.Context) error {
 	p.logger.Info("Starting graph sync pipeline")
 
+	if p.rulesErr != nil {
+		return fmt.Errorf("failed to load causality heuristics: %w", p.rulesErr)
+	}
+
 	p.ctx, p.cancel = context.WithCancel(ctx)
 
 	// Initialize graph schema
@@ -133,6 +147,7 @@ func (p *pipeline) ProcessEvent(ctx context.Context, event models.Event) error {
 		atomic.AddInt64(&p.stats.Errors, 1)
 		return fmt.Errorf("failed to apply graph update: %w", err)
 	}
+	p.updates.publish([]*GraphUpdate{update})
 
 	// Update stats
 	p.statsLock.Lock()
@@ -167,12 +182,16 @@ func (p *pipeline) ProcessBatch(ctx context.Context, events []models.Event) erro
 	phase1Start := time.Now()
 	p.logger.Debug("Phase 1: Creating %d resource nodes", len(events))
 
+	// failed collects the events that were not fully written
+	failed := make(map[string]error)
+
 	nodeUpdates := make([]*GraphUpdate, 0, len(events))
 	for _, event := range events {
 		update, err := p.builder.BuildResourceNodes(event)
 		if err != nil {
 			p.logger.Warn("Failed to build nodes for event %s: %v", event.ID, err)
 			atomic.AddInt64(&p.stats.Errors, 1)
+			failed[event.ID] = err
 			continue
 		}
 		nodeUpdates = append(nodeUpdates, update)
@@ -180,13 +199,16 @@ func (p *pipeline) ProcessBatch(ctx context.Context, events []models.Event) erro
 
 	// Apply all node updates
 	nodesCreated := 0
+	applied := make([]*GraphUpdate, 0, len(nodeUpdates))
 	for _, update := range nodeUpdates {
 		if err := p.applyGraphUpdate(ctx, update); err != nil {
 			p.logger.Warn("Failed to apply node update for event %s: %v", update.SourceEventID, err)
 			atomic.AddInt64(&p.stats.Errors, 1)
+			failed[update.SourceEventID] = err
 			continue
 		}
 		nodesCreated++
+		applied = append(applied, update)
 	}
 
 	phase1Duration := time.Since(phase1Start)
@@ -203,6 +225,9 @@ func (p *pipeline) ProcessBatch(ctx context.Context, events []models.Event) erro
 		if err != nil {
 			p.logger.Warn("Failed to extract relationships for event %s: %v", event.ID, err)
 			atomic.AddInt64(&p.stats.Errors, 1)
+			if _, ok := failed[event.ID]; !ok {
+				failed[event.ID] = err
+			}
 			continue
 		}
 		totalEdges += len(update.Edges)
@@ -215,6 +240,9 @@ func (p *pipeline) ProcessBatch(ctx context.Context, events []models.Event) erro
 		if err := p.applyGraphUpdate(ctx, update); err != nil {
 			p.logger.Warn("Failed to apply edge update for event %s: %v", update.SourceEventID, err)
 			atomic.AddInt64(&p.stats.Errors, 1)
+			if _, ok := failed[update.SourceEventID]; !ok {
+				failed[update.SourceEventID] = err
+			}
 			continue
 		}
 		edgesCreated += len(update.Edges)
@@ -245,12 +273,28 @@ func (p *pipeline) ProcessBatch(ctx context.Context, events []models.Event) erro
 	p.statsLock.Unlock()
 	p.updateProcessingRate()
 
+	// Notify subscribers once the batch is complete
+	p.updates.publish(applied)
+
 	totalDuration := time.Since(start)
 	p.logger.Info("Batch complete: %d events processed in %v (Phase1: %v, Phase2: %v)",
 		len(events), totalDuration, phase1Duration, phase2Duration)
+	if len(failed) > 0 {
+		return &BatchError{Failed: failed, Total: len(events)}
+	}
 	return nil
 }
 
+// Subscribe returns a subscription to the node updates written to the graph
+func (p *pipeline) Subscribe() *Subscription {
+	return p.updates.subscribe()
+}
+
+// GetCausalityEngine returns the engine inferring TRIGGERED_BY edges
+func (p *pipeline) GetCausalityEngine() CausalityEngine {
+	return p.causality
+}
+
 // GetStats returns pipeline statistics
 func (p *pipeline) GetStats() PipelineStats {
 	p.statsLock.RLock()
@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriteClient fails writing the change event with the given ID
+type failingWriteClient struct {
+	graph.Client
+	failID string
+}
+
+func (c *failingWriteClient) ExecuteQuery(ctx context.Context, query graph.GraphQuery) (*graph.QueryResult, error) {
+	if strings.Contains(query.Query, "MERGE (e:ChangeEvent {id: $id})") && query.Parameters["id"] == c.failID {
+		return nil, errors.New("write timed out")
+	}
+	return c.Client.ExecuteQuery(ctx, query)
+}
+
+func TestPipeline_ProcessBatchReportsFailedEvents(t *testing.T) {
+	client := &failingWriteClient{Client: newRetentionTestClient(t), failID: "e2"}
+	p := NewPipeline(DefaultPipelineConfig(), client)
+
+	now := time.Now().UnixNano()
+	var events []models.Event
+	for i, name := range []string{"api", "worker", "cron"} {
+		data, _ := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": "default", "uid": name},
+		})
+		events = append(events, models.Event{
+			ID:        []string{"e1", "e2", "e3"}[i],
+			Timestamp: now + int64(i),
+			Type:      models.EventTypeCreate,
+			Resource:  models.ResourceMetadata{Version: "v1", Kind: "Pod", Namespace: "default", Name: name, UID: name},
+			Data:      data,
+		})
+	}
+
+	err := p.ProcessBatch(context.Background(), events)
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 3, batchErr.Total)
+	require.Len(t, batchErr.Failed, 1)
+	assert.Contains(t, batchErr.Failed, "e2")
+	assert.Contains(t, err.Error(), "failed to write 1 of 3 events")
+
+	// The other events of the batch are written
+	assert.Equal(t, []string{"e1", "e3"}, eventIDs(t, client.Client, "ChangeEvent"))
+}
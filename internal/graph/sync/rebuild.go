@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -41,6 +42,30 @@ type RebuildOptions struct {
 
 	// IncludeK8sEvents determines if K8s Event objects should be included
 	IncludeK8sEvents bool
+
+	// Progress is called after each batch (optional)
+	Progress func(RebuildProgress)
+}
+
+// RebuildProgress reports how far a rebuild has come
+type RebuildProgress struct {
+	// Kind is the kind being rebuilt by PartialRebuild, empty for Rebuild
+	Kind string
+
+	// Processed is the number of events handled so far, including failed ones
+	Processed int
+
+	// Total is the number of events to process for Kind
+	Total int
+
+	// Failed is the number of events of Kind in batches the pipeline rejected
+	Failed int
+}
+
+func (o RebuildOptions) report(progress RebuildProgress) {
+	if o.Progress != nil {
+		o.Progress(progress)
+	}
 }
 
 // DefaultRebuildOptions returns default rebuild options
@@ -82,6 +107,7 @@ func (r *Rebuilder) Rebuild(ctx context.Context, opts RebuildOptions) error {
 
 	// Process events in batches
 	totalEvents := len(result.Events)
+	failed := 0
 	for i := 0; i < totalEvents; i += opts.BatchSize {
 		end := i + opts.BatchSize
 		if end > totalEvents {
@@ -102,6 +128,7 @@ func (r *Rebuilder) Rebuild(ctx context.Context, opts RebuildOptions) error {
 		}
 
 		if len(batch) == 0 {
+			opts.report(RebuildProgress{Processed: end, Total: totalEvents, Failed: failed})
 			continue
 		}
 
@@ -110,9 +137,10 @@ func (r *Rebuilder) Rebuild(ctx context.Context, opts RebuildOptions) error {
 		// Process batch through pipeline
 		if err := r.pipeline.ProcessBatch(ctx, batch); err != nil {
 			r.logger.Warn("Failed to process batch %d-%d: %v", i+1, end, err)
-			// Continue processing remaining batches
-			continue
+			failed += failedEvents(err, len(batch))
 		}
+		// Continue processing remaining batches
+		opts.report(RebuildProgress{Processed: end, Total: totalEvents, Failed: failed})
 	}
 
 	r.logger.Info("Graph rebuild complete: processed %d events", totalEvents)
@@ -179,6 +207,7 @@ func (r *Rebuilder) PartialRebuild(ctx context.Context, kinds []string, opts Reb
 		}
 
 		// Process in batches
+		failed := 0
 		for i := 0; i < len(result.Events); i += opts.BatchSize {
 			end := i + opts.BatchSize
 			if end > len(result.Events) {
@@ -189,10 +218,15 @@ func (r *Rebuilder) PartialRebuild(ctx context.Context, kinds []string, opts Reb
 
 			if err := r.pipeline.ProcessBatch(ctx, batch); err != nil {
 				r.logger.Warn("Failed to process batch for kind %s: %v", kind, err)
+				batchFailed := failedEvents(err, len(batch))
+				failed += batchFailed
+				totalProcessed += len(batch) - batchFailed
+				opts.report(RebuildProgress{Kind: kind, Processed: end, Total: len(result.Events), Failed: failed})
 				continue
 			}
 
 			totalProcessed += len(batch)
+			opts.report(RebuildProgress{Kind: kind, Processed: end, Total: len(result.Events), Failed: failed})
 		}
 	}
 
@@ -201,3 +235,13 @@ func (r *Rebuilder) PartialRebuild(ctx context.Context, kinds []string, opts Reb
 
 	return nil
 }
+
+// failedEvents returns the number of events of a batch that ProcessBatch
+// failed to write
+func failedEvents(err error, batchSize int) int {
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return len(batchErr.Failed)
+	}
+	return batchSize
+}
@@ -0,0 +1,68 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticQuerier returns the events that match the request filters
+type staticQuerier struct {
+	events []models.Event
+}
+
+func (q *staticQuerier) Query(ctx context.Context, request models.QueryRequest) (*models.QueryResult, error) {
+	var events []models.Event
+	for _, event := range q.events {
+		if request.Filters.Matches(event.Resource) {
+			events = append(events, event)
+		}
+	}
+	return &models.QueryResult{Events: events, Count: int32(len(events))}, nil // #nosec G115 -- test data
+}
+
+func rebuildEvents(kind string, n int) []models.Event {
+	now := time.Now().UnixNano()
+	events := make([]models.Event, 0, n)
+	for i := 0; i < n; i++ {
+		uid := fmt.Sprintf("%s-%d", kind, i)
+		data, _ := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": uid, "namespace": "default", "uid": uid},
+		})
+		events = append(events, models.Event{
+			ID:        "event-" + uid,
+			Timestamp: now + int64(i),
+			Type:      models.EventTypeCreate,
+			Resource:  models.ResourceMetadata{Version: "v1", Kind: kind, Namespace: "default", Name: uid, UID: uid},
+			Data:      data,
+		})
+	}
+	return events
+}
+
+func TestRebuilder_Progress(t *testing.T) {
+	querier := &staticQuerier{events: append(rebuildEvents("Pod", 5), rebuildEvents("Service", 2)...)}
+	rebuilder := NewRebuilder(querier, newTestPipeline(t))
+
+	var progress []RebuildProgress
+	opts := DefaultRebuildOptions()
+	opts.BatchSize = 2
+	opts.Progress = func(p RebuildProgress) {
+		progress = append(progress, p)
+	}
+
+	require.NoError(t, rebuilder.Rebuild(context.Background(), opts))
+	require.Len(t, progress, 4)
+	assert.Equal(t, RebuildProgress{Processed: 2, Total: 7}, progress[0])
+	assert.Equal(t, RebuildProgress{Processed: 7, Total: 7}, progress[3])
+
+	progress = nil
+	require.NoError(t, rebuilder.PartialRebuild(context.Background(), []string{"Service"}, opts))
+	assert.Equal(t, []RebuildProgress{{Kind: "Service", Processed: 2, Total: 2}}, progress)
+}
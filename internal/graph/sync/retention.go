@@ -3,31 +3,127 @@ package sync
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/moolen/spectre/internal/graph"
 	"github.com/moolen/spectre/internal/logging"
 )
 
+// Sources of the events a retention policy applies to
+const (
+	RetentionSourceChange = "change" // ChangeEvent nodes
+	RetentionSourceK8s    = "k8s"    // K8sEvent nodes
+)
+
+// Kinds of ChangeEvents a retention policy can select
+const (
+	RetentionChangesSpec   = "spec"   // changes of the resource's configuration
+	RetentionChangesStatus = "status" // updates that only changed the status
+)
+
+// RetentionPolicy keeps a subset of the events for a different time than the
+// retention window. Empty selectors match everything.
+type RetentionPolicy struct {
+	Name       string
+	Source     string   // RetentionSourceChange, RetentionSourceK8s or empty for both
+	Kinds      []string // kinds of the resources the events belong to
+	Namespaces []string
+	Types      []string // ChangeEvent eventType (CREATE, UPDATE, DELETE) or K8sEvent type (Normal, Warning)
+	Changes    string   // RetentionChangesSpec or RetentionChangesStatus, ChangeEvents only
+	Retention  time.Duration
+}
+
+// appliesTo reports whether the policy can match events of the given source
+func (p RetentionPolicy) appliesTo(source string) bool {
+	if p.Changes != "" && source != RetentionSourceChange {
+		return false
+	}
+	return p.Source == "" || p.Source == source
+}
+
+// condition returns the Cypher predicate selecting the policy's events, with
+// the resource bound to r and the event to e
+func (p RetentionPolicy) condition(source, suffix string, params map[string]interface{}) string {
+	var conditions []string
+	if len(p.Kinds) > 0 {
+		params["kinds"+suffix] = p.Kinds
+		conditions = append(conditions, "coalesce(r.kind, '') IN $kinds"+suffix)
+	}
+	if len(p.Namespaces) > 0 {
+		params["namespaces"+suffix] = p.Namespaces
+		conditions = append(conditions, "coalesce(r.namespace, '') IN $namespaces"+suffix)
+	}
+	if len(p.Types) > 0 {
+		params["types"+suffix] = p.Types
+		if source == RetentionSourceK8s {
+			conditions = append(conditions, "e.type IN $types"+suffix)
+		} else {
+			conditions = append(conditions, "e.eventType IN $types"+suffix)
+		}
+	}
+	switch p.Changes {
+	case RetentionChangesSpec:
+		conditions = append(conditions, "coalesce(e.configChanged, false) = true")
+	case RetentionChangesStatus:
+		conditions = append(conditions, "e.eventType = 'UPDATE' AND coalesce(e.configChanged, false) = false")
+	}
+
+	if len(conditions) == 0 {
+		return "true"
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")"
+}
+
+const (
+	// defaultArchiveBatchSize is the number of expired events archived and
+	// deleted at a time
+	defaultArchiveBatchSize = 1000
+
+	// defaultCompactionBatchSize is the number of resources whose change
+	// events are compacted at a time
+	defaultCompactionBatchSize = 500
+)
+
 // retentionManager implements the RetentionManager interface
 type retentionManager struct {
-	client          graph.Client
-	logger          *logging.Logger
-	retentionWindow time.Duration
+	client           graph.Client
+	logger           *logging.Logger
+	retentionWindow  time.Duration
+	policies         []RetentionPolicy
+	compactionAge    time.Duration
+	archive          EventArchive
+	archiveBatchSize int
+
+	compactionBatchSize int
 }
 
 // NewRetentionManager creates a new retention manager
 func NewRetentionManager(client graph.Client, retentionWindow time.Duration) RetentionManager {
+	return NewRetentionManagerWithPolicies(client, retentionWindow, nil, 0, nil)
+}
+
+// NewRetentionManagerWithPolicies creates a retention manager that keeps the
+// events matched by a policy for the policy's retention instead of the window,
+// and compacts status-only ChangeEvents older than compactionAge (0 disables).
+// With an archive, events are archived before they are deleted.
+func NewRetentionManagerWithPolicies(client graph.Client, retentionWindow time.Duration, policies []RetentionPolicy, compactionAge time.Duration, archive EventArchive) RetentionManager {
 	return &retentionManager{
-		client:          client,
-		logger:          logging.GetLogger("graph.sync.retention"),
-		retentionWindow: retentionWindow,
+		client:           client,
+		logger:           logging.GetLogger("graph.sync.retention"),
+		retentionWindow:  retentionWindow,
+		policies:         policies,
+		compactionAge:    compactionAge,
+		archive:          archive,
+		archiveBatchSize: defaultArchiveBatchSize,
+
+		compactionBatchSize: defaultCompactionBatchSize,
 	}
 }
 
 // Cleanup removes data older than the retention window
 func (r *retentionManager) Cleanup(ctx context.Context) error {
-	r.logger.Info("Starting retention cleanup (window: %v)", r.retentionWindow)
+	r.logger.Info("Starting retention cleanup (window: %v, policies: %d)", r.retentionWindow, len(r.policies))
 
 	cutoffTime := time.Now().Add(-r.retentionWindow)
 	cutoffNs := cutoffTime.UnixNano()
@@ -44,6 +140,12 @@ func (r *retentionManager) Cleanup(ctx context.Context) error {
 		return fmt.Errorf("failed to cleanup k8s events: %w", err)
 	}
 
+	if r.compactionAge > 0 {
+		if _, err := r.Compact(ctx); err != nil {
+			r.logger.Warn("Failed to compact change events: %v", err)
+		}
+	}
+
 	// Clean up orphaned ResourceIdentity nodes (optional)
 	// These are resources that have no events and were deleted
 	orphanCount, err := r.cleanupOrphanedResources(ctx, cutoffNs)
@@ -70,20 +172,148 @@ func (r *retentionManager) SetRetentionWindow(duration time.Duration) {
 
 // cleanupChangeEvents deletes ChangeEvent nodes older than cutoff
 func (r *retentionManager) cleanupChangeEvents(ctx context.Context, cutoffNs int64) (int, error) {
-	query := graph.DeleteOldChangeEventsQuery(cutoffNs)
-
-	result, err := r.client.ExecuteQuery(ctx, query)
-	if err != nil {
-		return 0, err
+	if r.archive == nil && !r.hasPolicies(RetentionSourceChange) {
+		return r.deleteNodes(ctx, graph.DeleteOldChangeEventsQuery(cutoffNs))
 	}
-
-	return result.Stats.NodesDeleted, nil
+	return r.cleanupWithPolicies(ctx, RetentionSourceChange, cutoffNs)
 }
 
 // cleanupK8sEvents deletes K8sEvent nodes older than cutoff
 func (r *retentionManager) cleanupK8sEvents(ctx context.Context, cutoffNs int64) (int, error) {
-	query := graph.DeleteOldK8sEventsQuery(cutoffNs)
+	if r.archive == nil && !r.hasPolicies(RetentionSourceK8s) {
+		return r.deleteNodes(ctx, graph.DeleteOldK8sEventsQuery(cutoffNs))
+	}
+	return r.cleanupWithPolicies(ctx, RetentionSourceK8s, cutoffNs)
+}
+
+func (r *retentionManager) hasPolicies(source string) bool {
+	for _, policy := range r.policies {
+		if policy.appliesTo(source) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupWithPolicies expires the events of a source in one pass per policy,
+// each with the policy's cutoff, and a final pass applying the retention
+// window to the events no policy matches. An event belongs to the first
+// policy that matches it.
+func (r *retentionManager) cleanupWithPolicies(ctx context.Context, source string, defaultCutoffNs int64) (int, error) {
+	now := time.Now()
+	params := make(map[string]interface{})
+	var earlier []string
+	deleted := 0
+
+	pass := func(condition string, cutoffNs int64) error {
+		passParams := map[string]interface{}{"cutoffNs": cutoffNs}
+		for k, v := range params {
+			passParams[k] = v
+		}
+		count, err := r.expire(ctx, source, condition, passParams)
+		deleted += count
+		return err
+	}
+
+	for i, policy := range r.policies {
+		if !policy.appliesTo(source) {
+			continue
+		}
+		condition := policy.condition(source, fmt.Sprint(i), params)
+		where := condition
+		for _, previous := range earlier {
+			where += " AND NOT " + previous
+		}
+		earlier = append(earlier, condition)
+
+		if err := pass(where, now.Add(-policy.Retention).UnixNano()); err != nil {
+			return deleted, fmt.Errorf("policy %q: %w", policy.Name, err)
+		}
+	}
+
+	where := "true"
+	if len(earlier) > 0 {
+		where = "NOT " + strings.Join(earlier, " AND NOT ")
+	}
+	if err := pass(where, defaultCutoffNs); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
+
+// expire deletes the events of a source older than $cutoffNs that match the
+// condition. With an archive configured, the events are archived and deleted
+// in batches, oldest first, so that a failure loses at most one batch of
+// work; a batch archived again after a crash replaces its earlier segment.
+func (r *retentionManager) expire(ctx context.Context, source, condition string, params map[string]interface{}) (int, error) {
+	label, edge := "ChangeEvent", "CHANGED"
+	if source == RetentionSourceK8s {
+		label, edge = "K8sEvent", "EMITTED_EVENT"
+	}
+	match := fmt.Sprintf(`
+		MATCH (e:%s)
+		WHERE e.timestamp < $cutoffNs
+		OPTIONAL MATCH (r:ResourceIdentity)-[:%s]->(e)
+		WITH e, r
+		WHERE %s
+	`, label, edge, condition)
+
+	if r.archive == nil {
+		return r.deleteNodes(ctx, graph.GraphQuery{
+			Query:      match + "DETACH DELETE e",
+			Parameters: params,
+		})
+	}
+
+	batchParams := map[string]interface{}{"batchSize": r.archiveBatchSize}
+	for k, v := range params {
+		batchParams[k] = v
+	}
+
+	deleted := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		result, err := r.client.ExecuteQuery(ctx, graph.GraphQuery{
+			Query:      match + "RETURN e, r ORDER BY e.timestamp, e.id LIMIT $batchSize",
+			Parameters: batchParams,
+		})
+		if err != nil {
+			return deleted, err
+		}
+
+		events, ids := archivedEvents(source, result.Rows)
+		if len(ids) == 0 {
+			// Nothing left, or only events that can't be archived
+			return deleted, nil
+		}
+		if err := r.archive.Archive(ctx, events); err != nil {
+			return deleted, fmt.Errorf("failed to archive expiring events: %w", err)
+		}
+
+		count, err := r.deleteNodes(ctx, graph.GraphQuery{
+			Query: fmt.Sprintf(`
+				MATCH (e:%s)
+				WHERE e.id IN $ids
+				DETACH DELETE e
+			`, label),
+			Parameters: map[string]interface{}{
+				"ids": ids,
+			},
+		})
+		deleted += count
+		if err != nil {
+			return deleted, err
+		}
+		if len(result.Rows) < r.archiveBatchSize {
+			return deleted, nil
+		}
+	}
+}
 
+func (r *retentionManager) deleteNodes(ctx context.Context, query graph.GraphQuery) (int, error) {
 	result, err := r.client.ExecuteQuery(ctx, query)
 	if err != nil {
 		return 0, err
@@ -0,0 +1,274 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetentionTestClient(t *testing.T) graph.Client {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	require.NoError(t, client.Connect(context.Background()))
+	require.NoError(t, client.InitializeSchema(context.Background()))
+	return client
+}
+
+func execute(t *testing.T, client graph.Client, query graph.GraphQuery) *graph.QueryResult {
+	t.Helper()
+
+	result, err := client.ExecuteQuery(context.Background(), query)
+	require.NoError(t, err)
+	return result
+}
+
+func seedResource(t *testing.T, client graph.Client, uid, kind, namespace string) {
+	execute(t, client, graph.UpsertResourceIdentityQuery(graph.ResourceIdentity{
+		UID: uid, Kind: kind, Namespace: namespace, Name: uid, Labels: map[string]string{},
+	}))
+}
+
+func seedChangeEvent(t *testing.T, client graph.Client, resourceUID, id, eventType string, configChanged bool, age time.Duration) {
+	execute(t, client, graph.CreateChangeEventQuery(graph.ChangeEvent{
+		ID:              id,
+		Timestamp:       time.Now().Add(-age).UnixNano(),
+		EventType:       eventType,
+		Status:          "Ready",
+		ContainerIssues: []string{},
+		ConfigChanged:   configChanged,
+	}))
+	execute(t, client, graph.CreateChangedEdgeQuery(resourceUID, id, 0))
+}
+
+func seedK8sEvent(t *testing.T, client graph.Client, resourceUID, id, eventType string, age time.Duration) {
+	execute(t, client, graph.CreateK8sEventQuery(graph.K8sEvent{
+		ID:        id,
+		Timestamp: time.Now().Add(-age).UnixNano(),
+		Type:      eventType,
+	}))
+	execute(t, client, graph.CreateEmittedEventEdgeQuery(resourceUID, id))
+}
+
+func eventIDs(t *testing.T, client graph.Client, label string) []string {
+	t.Helper()
+
+	result := execute(t, client, graph.GraphQuery{
+		Query: fmt.Sprintf("MATCH (e:%s) RETURN e.id", label),
+	})
+	ids := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		ids = append(ids, row[0].(string))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestRetentionManager_Policies(t *testing.T) {
+	client := newRetentionTestClient(t)
+	seedResource(t, client, "deploy", "Deployment", "payments")
+	seedResource(t, client, "pod", "Pod", "payments")
+
+	day := 24 * time.Hour
+	seedChangeEvent(t, client, "deploy", "deploy-spec-old", "UPDATE", true, 100*day)
+	seedChangeEvent(t, client, "deploy", "deploy-spec", "UPDATE", true, 30*day)
+	seedChangeEvent(t, client, "deploy", "deploy-status", "UPDATE", false, 30*day)
+	seedChangeEvent(t, client, "pod", "pod-create", "CREATE", false, 3*day)
+	seedChangeEvent(t, client, "pod", "pod-status-old", "UPDATE", false, 3*day)
+	seedChangeEvent(t, client, "pod", "pod-status", "UPDATE", false, day)
+	seedK8sEvent(t, client, "pod", "normal-old", "Normal", 12*time.Hour)
+	seedK8sEvent(t, client, "pod", "normal", "Normal", time.Hour)
+	seedK8sEvent(t, client, "pod", "warning", "Warning", 12*time.Hour)
+
+	r := NewRetentionManagerWithPolicies(client, 7*day, []RetentionPolicy{
+		{Name: "deployment-spec", Source: RetentionSourceChange, Kinds: []string{"Deployment"}, Changes: RetentionChangesSpec, Retention: 90 * day},
+		{Name: "pod-status", Kinds: []string{"Pod"}, Changes: RetentionChangesStatus, Retention: 2 * day},
+		{Name: "normal-events", Source: RetentionSourceK8s, Types: []string{"Normal"}, Retention: 6 * time.Hour},
+	}, 0, nil)
+	require.NoError(t, r.Cleanup(context.Background()))
+
+	// The Deployment's status change falls back to the 7 day window; the Pod's
+	// CREATE isn't a status change and is kept by the window too
+	assert.Equal(t, []string{"deploy-spec", "pod-create", "pod-status"}, eventIDs(t, client, "ChangeEvent"))
+	assert.Equal(t, []string{"normal", "warning"}, eventIDs(t, client, "K8sEvent"))
+}
+
+func TestRetentionManager_FirstPolicyWins(t *testing.T) {
+	client := newRetentionTestClient(t)
+	seedResource(t, client, "pod", "Pod", "payments")
+	seedChangeEvent(t, client, "pod", "pod-status", "UPDATE", false, 3*time.Hour)
+
+	r := NewRetentionManagerWithPolicies(client, time.Hour, []RetentionPolicy{
+		{Name: "payments", Namespaces: []string{"payments"}, Retention: 24 * time.Hour},
+		{Name: "pods", Kinds: []string{"Pod"}, Retention: time.Hour},
+	}, 0, nil)
+	require.NoError(t, r.Cleanup(context.Background()))
+
+	assert.Equal(t, []string{"pod-status"}, eventIDs(t, client, "ChangeEvent"))
+}
+
+func TestRetentionManager_Compact(t *testing.T) {
+	client := newRetentionTestClient(t)
+	seedResource(t, client, "pod", "Pod", "payments")
+
+	// create, flap, flap, flap, spec change, flap, flap, flap (recent)
+	events := []struct {
+		id            string
+		eventType     string
+		configChanged bool
+		age           time.Duration
+	}{
+		{"e0", "CREATE", false, 10 * time.Hour},
+		{"e1", "UPDATE", false, 9 * time.Hour},
+		{"e2", "UPDATE", false, 8 * time.Hour},
+		{"e3", "UPDATE", false, 7 * time.Hour},
+		{"e4", "UPDATE", true, 6 * time.Hour},
+		{"e5", "UPDATE", false, 5 * time.Hour},
+		{"e6", "UPDATE", false, 4 * time.Hour},
+		{"e7", "UPDATE", false, 10 * time.Minute},
+	}
+	for i, event := range events {
+		seedChangeEvent(t, client, "pod", event.id, event.eventType, event.configChanged, event.age)
+		if i > 0 {
+			execute(t, client, graph.CreatePrecededByEdgeQuery(event.id, events[i-1].id, int64(i)))
+		}
+	}
+
+	r := NewRetentionManagerWithPolicies(client, 7*24*time.Hour, nil, time.Hour, nil)
+	removed, err := r.Compact(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+	assert.Equal(t, []string{"e0", "e3", "e4", "e6", "e7"}, eventIDs(t, client, "ChangeEvent"))
+
+	result := execute(t, client, graph.GraphQuery{
+		Query: "MATCH (e:ChangeEvent {id: 'e3'}) RETURN e",
+	})
+	props, err := graph.ParseNodeFromResult(result.Rows[0][0])
+	require.NoError(t, err)
+	summary := graph.ParseChangeEventFromNode(props)
+	assert.Equal(t, 3, summary.CompactedCount)
+	assert.Less(t, summary.FirstTimestamp, summary.Timestamp)
+
+	// The PRECEDED_BY chain skips the removed events
+	result = execute(t, client, graph.GraphQuery{
+		Query: `
+			MATCH (current:ChangeEvent)-[p:PRECEDED_BY]->(previous:ChangeEvent)
+			RETURN current.id, previous.id, p.durationMs
+			ORDER BY current.id
+		`,
+	})
+	var chain []string
+	for _, row := range result.Rows {
+		chain = append(chain, fmt.Sprintf("%s->%s", row[0], row[1]))
+	}
+	assert.Equal(t, []string{"e3->e0", "e4->e3", "e6->e4", "e7->e6"}, chain)
+	assert.EqualValues(t, 1, result.Rows[0][2], "the summary keeps the gap before the run")
+
+	// Compacting again merges a summary with later status-only events
+	seedChangeEvent(t, client, "pod", "e3b", "UPDATE", false, 6*time.Hour+30*time.Minute)
+	removed, err = r.Compact(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	result = execute(t, client, graph.GraphQuery{
+		Query: "MATCH (e:ChangeEvent {id: 'e3b'}) RETURN e",
+	})
+	props, err = graph.ParseNodeFromResult(result.Rows[0][0])
+	require.NoError(t, err)
+	merged := graph.ParseChangeEventFromNode(props)
+	assert.Equal(t, 4, merged.CompactedCount)
+	assert.Equal(t, summary.FirstTimestamp, merged.FirstTimestamp)
+}
+
+func TestRetentionManager_CompactBatches(t *testing.T) {
+	client := newRetentionTestClient(t)
+	for _, uid := range []string{"pod-a", "pod-b", "pod-c"} {
+		seedResource(t, client, uid, "Pod", "payments")
+		for i := 0; i < 3; i++ {
+			seedChangeEvent(t, client, uid, fmt.Sprintf("%s-%d", uid, i), "UPDATE", false, time.Duration(5-i)*time.Hour)
+		}
+	}
+
+	r := NewRetentionManagerWithPolicies(client, 7*24*time.Hour, nil, time.Hour, nil).(*retentionManager)
+	r.compactionBatchSize = 2
+	removed, err := r.Compact(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 6, removed)
+	assert.Equal(t, []string{"pod-a-2", "pod-b-2", "pod-c-2"}, eventIDs(t, client, "ChangeEvent"))
+}
+
+type fakeArchive struct {
+	events  []models.Event
+	batches int
+}
+
+func (a *fakeArchive) Archive(ctx context.Context, events []models.Event) error {
+	a.events = append(a.events, events...)
+	a.batches++
+	return nil
+}
+
+func TestRetentionManager_Archive(t *testing.T) {
+	client := newRetentionTestClient(t)
+	seedResource(t, client, "pod", "Pod", "payments")
+	seedChangeEvent(t, client, "pod", "pod-old", "UPDATE", false, 3*time.Hour)
+	seedChangeEvent(t, client, "pod", "pod-new", "UPDATE", false, 10*time.Minute)
+	seedK8sEvent(t, client, "pod", "event-old", "Warning", 3*time.Hour)
+
+	archive := &fakeArchive{}
+	r := NewRetentionManagerWithPolicies(client, time.Hour, nil, 0, archive)
+	require.NoError(t, r.Cleanup(context.Background()))
+
+	assert.Equal(t, []string{"pod-new"}, eventIDs(t, client, "ChangeEvent"))
+	assert.Empty(t, eventIDs(t, client, "K8sEvent"))
+
+	require.Len(t, archive.events, 2)
+	byID := make(map[string]models.Event)
+	for _, event := range archive.events {
+		byID[event.ID] = event
+	}
+
+	change := byID["pod-old"]
+	assert.Equal(t, models.EventTypeUpdate, change.Type)
+	assert.Equal(t, "Pod", change.Resource.Kind)
+	assert.Equal(t, "payments", change.Resource.Namespace)
+	assert.Equal(t, "pod", change.Resource.UID)
+
+	k8sEvent := byID["event-old"]
+	assert.Equal(t, "Event", k8sEvent.Resource.Kind)
+	assert.Equal(t, "pod", k8sEvent.Resource.InvolvedObjectUID)
+	assert.Contains(t, string(k8sEvent.Data), `"type":"Warning"`)
+}
+
+func TestRetentionManager_ArchiveBatches(t *testing.T) {
+	client := newRetentionTestClient(t)
+	seedResource(t, client, "pod", "Pod", "payments")
+	for i := 0; i < 5; i++ {
+		seedChangeEvent(t, client, "pod", fmt.Sprintf("pod-old-%d", i), "UPDATE", false, time.Duration(5-i)*time.Hour)
+	}
+	seedChangeEvent(t, client, "pod", "pod-new", "UPDATE", false, 10*time.Minute)
+
+	archive := &fakeArchive{}
+	r := NewRetentionManagerWithPolicies(client, time.Hour, nil, 0, archive).(*retentionManager)
+	r.archiveBatchSize = 2
+	require.NoError(t, r.Cleanup(context.Background()))
+
+	assert.Equal(t, []string{"pod-new"}, eventIDs(t, client, "ChangeEvent"))
+	assert.Equal(t, 3, archive.batches)
+
+	// Oldest first, each event archived once
+	var ids []string
+	for _, event := range archive.events {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"pod-old-0", "pod-old-1", "pod-old-2", "pod-old-3", "pod-old-4"}, ids)
+}
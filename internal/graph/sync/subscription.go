@@ -0,0 +1,91 @@
+package sync
+
+import (
+	"errors"
+	"sync"
+)
+
+// subscriptionBuffer is the number of batches a subscriber may lag behind
+const subscriptionBuffer = 64
+
+// ErrSubscriptionOverflow is reported by subscriptions that were closed
+// because their consumer didn't keep up with the pipeline
+var ErrSubscriptionOverflow = errors.New("subscriber fell behind the sync pipeline")
+
+// Subscription receives the node updates of the batches the pipeline writes
+// to the graph. Each value on C holds the updates of one batch in event order.
+type Subscription struct {
+	C <-chan []*GraphUpdate
+
+	ch     chan []*GraphUpdate
+	b      *broadcaster
+	err    error
+	closed bool
+}
+
+// Close stops the subscription and closes C
+func (s *Subscription) Close() {
+	s.b.remove(s, nil)
+}
+
+// Err returns why C was closed, or nil while the subscription is active or
+// after Close
+func (s *Subscription) Err() error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	return s.err
+}
+
+// broadcaster fans processed batches out to subscriptions. Publishing never
+// blocks: a subscription whose buffer is full is closed with
+// ErrSubscriptionOverflow, so that one slow client can't stall the sync.
+type broadcaster struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subs: make(map[*Subscription]struct{})}
+}
+
+func (b *broadcaster) subscribe() *Subscription {
+	ch := make(chan []*GraphUpdate, subscriptionBuffer)
+	sub := &Subscription{C: ch, ch: ch, b: b}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+func (b *broadcaster) remove(sub *Subscription, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeLocked(sub, err)
+}
+
+func (b *broadcaster) closeLocked(sub *Subscription, err error) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	sub.err = err
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+func (b *broadcaster) publish(updates []*GraphUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub.ch <- updates:
+		default:
+			b.closeLocked(sub, ErrSubscriptionOverflow)
+		}
+	}
+}
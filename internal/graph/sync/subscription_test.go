@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPipeline(t *testing.T) Pipeline {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	require.NoError(t, client.Connect(context.Background()))
+	require.NoError(t, client.InitializeSchema(context.Background()))
+
+	return NewPipeline(DefaultPipelineConfig(), client)
+}
+
+func TestPipeline_Subscribe(t *testing.T) {
+	p := newTestPipeline(t)
+	sub := p.Subscribe()
+	defer sub.Close()
+
+	now := time.Now().UnixNano()
+	data, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "api", "namespace": "default", "uid": "pod-1"},
+	})
+	events := []models.Event{
+		{
+			ID:        "e1",
+			Timestamp: now,
+			Type:      models.EventTypeCreate,
+			Resource:  models.ResourceMetadata{Version: "v1", Kind: "Pod", Namespace: "default", Name: "api", UID: "pod-1"},
+			Data:      data,
+		},
+		{
+			ID:        "e2",
+			Timestamp: now + 1,
+			Type:      models.EventTypeUpdate,
+			Resource:  models.ResourceMetadata{Version: "v1", Kind: "Pod", Namespace: "default", Name: "api", UID: "pod-1"},
+			Data:      data,
+		},
+	}
+	require.NoError(t, p.ProcessBatch(context.Background(), events))
+
+	select {
+	case updates := <-sub.C:
+		require.Len(t, updates, 2)
+		assert.Equal(t, "e1", updates[0].SourceEventID)
+		assert.Equal(t, "e2", updates[1].SourceEventID)
+		assert.Equal(t, "pod-1", updates[1].ResourceNodes[0].UID)
+		require.Len(t, updates[1].EventNodes, 1)
+		assert.Equal(t, "UPDATE", updates[1].EventNodes[0].EventType)
+	default:
+		t.Fatal("expected the batch to be published")
+	}
+}
+
+func TestSubscription_Overflow(t *testing.T) {
+	b := newBroadcaster()
+	slow := b.subscribe()
+	fast := b.subscribe()
+	defer fast.Close()
+
+	update := []*GraphUpdate{{SourceEventID: "e"}}
+	for i := 0; i <= subscriptionBuffer; i++ {
+		b.publish(update)
+		<-fast.C
+	}
+
+	// The slow subscription is closed once its buffer overflows
+	received := 0
+	for range slow.C {
+		received++
+	}
+	assert.Equal(t, subscriptionBuffer, received)
+	assert.ErrorIs(t, slow.Err(), ErrSubscriptionOverflow)
+
+	// The other subscription keeps receiving
+	b.publish(update)
+	assert.Len(t, <-fast.C, 1)
+	assert.NoError(t, fast.Err())
+}
+
+func TestSubscription_Close(t *testing.T) {
+	b := newBroadcaster()
+	sub := b.subscribe()
+	sub.Close()
+	sub.Close()
+
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	assert.NoError(t, sub.Err())
+
+	// Publishing after Close doesn't reach the subscription
+	b.publish([]*GraphUpdate{{SourceEventID: "e"}})
+	assert.Empty(t, b.subs)
+}
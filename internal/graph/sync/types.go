@@ -2,9 +2,12 @@ package sync
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync/extractors"
 	"github.com/moolen/spectre/internal/models"
 )
 
@@ -19,9 +22,17 @@ type Pipeline interface {
 	// ProcessEvent processes a single event (used for manual sync)
 	ProcessEvent(ctx context.Context, event models.Event) error
 
-	// ProcessBatch processes a batch of events
+	// ProcessBatch processes a batch of events. When some events can't be
+	// written, the others are still written and a *BatchError is returned.
 	ProcessBatch(ctx context.Context, events []models.Event) error
 
+	// Subscribe returns a subscription to the node updates of processed
+	// batches, for following changes as they are written to the graph
+	Subscribe() *Subscription
+
+	// GetCausalityEngine returns the engine inferring TRIGGERED_BY edges
+	GetCausalityEngine() CausalityEngine
+
 	// GetStats returns pipeline statistics
 	GetStats() PipelineStats
 }
@@ -78,13 +89,26 @@ type CausalityEngine interface {
 
 	// GetHeuristics returns the configured causality heuristics
 	GetHeuristics() []CausalityHeuristic
+
+	// SetRules replaces the heuristics declared in configuration, keeping
+	// the built-in ones
+	SetRules(rules []HeuristicRule) error
+
+	// GetHeuristicStats returns the active heuristics and the number of
+	// links each one inferred
+	GetHeuristicStats() []HeuristicStats
 }
 
 // RetentionManager handles cleanup of old graph data
 type RetentionManager interface {
-	// Cleanup removes data older than the retention window
+	// Cleanup removes data older than the retention window and its policies,
+	// then compacts what is left
 	Cleanup(ctx context.Context) error
 
+	// Compact collapses runs of status-only ChangeEvents older than the
+	// compaction age into summary nodes and returns the number of removed events
+	Compact(ctx context.Context) (int, error)
+
 	// GetRetentionWindow returns the current retention window
 	GetRetentionWindow() time.Duration
 
@@ -92,6 +116,13 @@ type RetentionManager interface {
 	SetRetentionWindow(duration time.Duration)
 }
 
+// EventArchive keeps events that retention removes from the graph
+type EventArchive interface {
+	// Archive stores the events before they are deleted. Retention keeps the
+	// events in the graph when it fails.
+	Archive(ctx context.Context, events []models.Event) error
+}
+
 // EventBatch represents a batch of events to process
 type EventBatch struct {
 	Events    []models.Event
@@ -99,6 +130,25 @@ type EventBatch struct {
 	BatchID   string
 }
 
+// BatchError is returned by ProcessBatch when some events of a batch could not
+// be written to the graph. The other events of the batch were written.
+type BatchError struct {
+	// Failed maps the IDs of the events that were not written to their error
+	Failed map[string]error
+	// Total is the number of events in the batch
+	Total int
+}
+
+// Error implements the error interface
+func (e *BatchError) Error() string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("failed to write %d of %d events (first %s: %v)", len(e.Failed), e.Total, ids[0], e.Failed[ids[0]])
+}
+
 // GraphUpdate represents changes to apply to the graph
 type GraphUpdate struct {
 	// Nodes to create or update
@@ -132,6 +182,40 @@ type CausalityHeuristic struct {
 	MaxLagMs    int64   // Maximum time lag to consider
 	Confidence  float64 // Base confidence score
 	Apply       func(cause, effect models.Event) bool
+	Source      string // HeuristicSourceBuiltin or HeuristicSourceConfig
+}
+
+// Sources of causality heuristics
+const (
+	HeuristicSourceBuiltin = "builtin"
+	HeuristicSourceConfig  = "config"
+)
+
+// HeuristicRule is a causality heuristic declared in configuration. Empty
+// kind and type selectors match everything.
+type HeuristicRule struct {
+	Name          string
+	Description   string
+	CauseKinds    []string
+	CauseTypes    []string // CREATE, UPDATE, DELETE
+	EffectKinds   []string
+	EffectTypes   []string
+	SameNamespace bool // cause and effect in the same namespace
+	OwnedByCause  bool // effect has an owner reference to the cause
+	MinLag        time.Duration
+	MaxLag        time.Duration
+	Confidence    float64
+}
+
+// HeuristicStats describes an active causality heuristic
+type HeuristicStats struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Source      string  `json:"source"`
+	MinLagMs    int64   `json:"minLagMs"`
+	MaxLagMs    int64   `json:"maxLagMs"`
+	Confidence  float64 `json:"confidence"`
+	Links       int64   `json:"links"` // TRIGGERED_BY links inferred since startup
 }
 
 // PipelineStats tracks sync pipeline metrics
@@ -166,12 +250,19 @@ type PipelineConfig struct {
 	WorkerCount  int           // Number of parallel workers
 
 	// Retention
-	RetentionWindow time.Duration // How long to keep events in graph
+	RetentionWindow   time.Duration     // How long to keep events in graph
+	RetentionPolicies []RetentionPolicy // Overrides of RetentionWindow, first match wins
+	CompactionAge     time.Duration     // Compact status-only ChangeEvents older than this (0 disables)
+	Archive           EventArchive      // Receives expiring events before they are deleted (optional)
 
 	// Causality inference
-	EnableCausality        bool          // Enable causality inference
-	CausalityMaxLag        time.Duration // Max time lag for causality
-	CausalityMinConfidence float64       // Min confidence to create edge
+	EnableCausality        bool            // Enable causality inference
+	CausalityMaxLag        time.Duration   // Max time lag for causality
+	CausalityMinConfidence float64         // Min confidence to create edge
+	CausalityRules         []HeuristicRule // Heuristics tried before the built-in ones
+
+	// Relationship extraction
+	Extractors []extractors.RelationshipExtractor // Registered in addition to the built-in extractors
 
 	// Performance
 	EnableAsync   bool          // Process events asynchronously
@@ -221,6 +221,9 @@ func parseResourceIdentity(node map[string]interface{}) *graph.ResourceIdentity
 	if namespace, ok := node["namespace"].(string); ok {
 		resource.Namespace = namespace
 	}
+	if cluster, ok := node["cluster"].(string); ok {
+		resource.Cluster = cluster
+	}
 	if name, ok := node["name"].(string); ok {
 		resource.Name = name
 	}
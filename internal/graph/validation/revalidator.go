@@ -306,6 +306,9 @@ func (r *EdgeRevalidator) validateEvidenceItem(
 	case graph.EvidenceTypeReconcile:
 		// Reconcile correlation is historical - no revalidation needed
 		return true
+	case graph.EvidenceTypeReference:
+		// Spec references are extracted again on every change of the source
+		return true
 	default:
 		// Unknown evidence types pass by default (forward compatibility)
 		r.logger.Debug("Unknown evidence type %s, assuming valid", evidence.Type)
@@ -114,7 +114,7 @@ func (m *mockAlertGraphClient) queryAlertsForOverview(query graph.GraphQuery) (*
 		}
 
 		// Apply label filters if present
-		if !m.matchesLabelFilters(alert, query.Query) {
+		if !m.matchesLabelFilters(alert, query) {
 			continue
 		}
 
@@ -214,12 +214,11 @@ func (m *mockAlertGraphClient) queryAlertsForTools(query graph.GraphQuery) (*gra
 	}, nil
 }
 
-// matchesLabelFilters checks if alert matches label filters in query string
-func (m *mockAlertGraphClient) matchesLabelFilters(alert mockAlertNode, query string) bool {
+// matchesLabelFilters checks if alert matches the label filters bound as query parameters
+func (m *mockAlertGraphClient) matchesLabelFilters(alert mockAlertNode, query graph.GraphQuery) bool {
 	// Check cluster filter
-	if strings.Contains(query, "a.labels CONTAINS '\"cluster\":") {
-		// Extract cluster value from filter (simplified)
-		// In real query: a.labels CONTAINS '"cluster":"prod"'
+	if strings.Contains(query.Query, "a.labels CONTAINS $clusterLabel") {
+		// In real query: a.labels CONTAINS $clusterLabel with $clusterLabel = "cluster":"prod"
 		// We just check if alert has that label value
 		if cluster := alert.Labels["cluster"]; cluster == "" {
 			return false
@@ -227,20 +226,13 @@ func (m *mockAlertGraphClient) matchesLabelFilters(alert mockAlertNode, query st
 	}
 
 	// Check severity filter (case-insensitive)
-	if strings.Contains(query, "toLower(a.labels) CONTAINS '\"severity\":") {
-		// Extract the severity value from the query
-		// Pattern: toLower(a.labels) CONTAINS '"severity":"critical"'
-		start := strings.Index(query, "toLower(a.labels) CONTAINS '\"severity\":\"")
-		if start != -1 {
-			start += len("toLower(a.labels) CONTAINS '\"severity\":\"")
-			end := strings.Index(query[start:], "\"")
-			if end != -1 {
-				wantedSeverity := strings.ToLower(query[start : start+end])
-				alertSeverity := strings.ToLower(alert.Labels["severity"])
-				if alertSeverity != wantedSeverity {
-					return false
-				}
-			}
+	if strings.Contains(query.Query, "toLower(a.labels) CONTAINS $severityLabel") {
+		// Pattern: $severityLabel = "severity":"critical"
+		filter, _ := query.Parameters["severityLabel"].(string)
+		wantedSeverity := strings.Trim(strings.TrimPrefix(filter, `"severity":`), `"`)
+		alertSeverity := strings.ToLower(alert.Labels["severity"])
+		if alertSeverity != wantedSeverity {
+			return false
 		}
 	}
 
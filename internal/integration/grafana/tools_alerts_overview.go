@@ -125,21 +125,22 @@ func (t *AlertsOverviewTool) queryAlerts(ctx context.Context, params AlertsOverv
 	// Add label-based filters if specified
 	// Labels are stored as JSON string, so we use string matching
 	labelFilters := []string{}
-
-	if params.Cluster != "" {
-		labelFilters = append(labelFilters, fmt.Sprintf("a.labels CONTAINS '\"cluster\":\"%s\"'", params.Cluster))
-	}
-	if params.Service != "" {
-		labelFilters = append(labelFilters, fmt.Sprintf("a.labels CONTAINS '\"service\":\"%s\"'", params.Service))
-	}
-	if params.Namespace != "" {
-		labelFilters = append(labelFilters, fmt.Sprintf("a.labels CONTAINS '\"namespace\":\"%s\"'", params.Namespace))
-	}
-	if params.Severity != "" {
-		// Severity normalization: match case-insensitively
-		labelFilters = append(labelFilters, fmt.Sprintf("toLower(a.labels) CONTAINS '\"severity\":\"%s\"'", params.Severity))
+	addLabelFilter := func(key, value, expr string) {
+		if value == "" {
+			return
+		}
+		param := key + "Label"
+		valueJSON, _ := json.Marshal(value)
+		queryParams[param] = fmt.Sprintf("%q:%s", key, valueJSON)
+		labelFilters = append(labelFilters, fmt.Sprintf("%s CONTAINS $%s", expr, param))
 	}
 
+	addLabelFilter("cluster", params.Cluster, "a.labels")
+	addLabelFilter("service", params.Service, "a.labels")
+	addLabelFilter("namespace", params.Namespace, "a.labels")
+	// Severity normalization: match case-insensitively
+	addLabelFilter("severity", strings.ToLower(params.Severity), "toLower(a.labels)")
+
 	// Append label filters to query
 	for _, filter := range labelFilters {
 		query += fmt.Sprintf(" AND %s", filter)
@@ -0,0 +1,453 @@
+package kubeaudit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// correlateInterval is how often pending audit events are retried. The
+	// watch event of a change usually reaches the graph after its audit event.
+	correlateInterval = 5 * time.Second
+
+	// pendingTTL is how long an audit event is retried before it is dropped,
+	// e.g. because the resource isn't watched or the update was a no-op
+	pendingTTL = 2 * time.Minute
+
+	// maxPending bounds the audit events waiting for their change
+	maxPending = 10000
+
+	// clockSkew and watchDelay bound the capture time of the change relative
+	// to the request: it is captured after the write, once the watch event
+	// arrived, on a clock that may differ slightly from the API server's
+	clockSkew  = 5 * time.Second
+	watchDelay = time.Minute
+)
+
+// Correlator records the actors of audit events on the ChangeEvents of the
+// changes they made. Changes are matched by the object UID and resourceVersion
+// where the audit event contains the response object (RequestResponse level);
+// otherwise by the object, the kind of change and the time of the request.
+type Correlator struct {
+	client  graph.Client
+	cluster string
+	logger  *logging.Logger
+
+	mu      sync.Mutex
+	pending []pendingEvent
+	// queued holds the audit IDs of the pending events
+	queued map[string]bool
+	// done holds the audit IDs correlated within pendingTTL, so events
+	// delivered twice aren't recorded on a second change
+	done map[string]time.Time
+
+	// Lifecycle
+	running bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// pendingEvent is an audit event waiting for its change to reach the graph
+type pendingEvent struct {
+	cluster  string
+	event    Event
+	received time.Time
+}
+
+// NewCorrelator creates a correlator for audit events of the given cluster
+// ("" for single-cluster setups)
+func NewCorrelator(client graph.Client, cluster string) *Correlator {
+	return &Correlator{
+		client:  client,
+		cluster: cluster,
+		logger:  logging.GetLogger("kubeaudit"),
+		queued:  make(map[string]bool),
+		done:    make(map[string]time.Time),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Cluster returns the cluster audit events are attributed to by default
+func (c *Correlator) Cluster() string {
+	return c.cluster
+}
+
+// Ingest queues the audit events of a cluster for correlation and returns
+// the number of events that record a change. Other events are ignored.
+func (c *Correlator) Ingest(cluster string, events []Event) int {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	accepted := 0
+	for _, event := range events {
+		if !event.IsChange() {
+			continue
+		}
+		if _, ok := c.done[event.AuditID]; ok || c.queued[event.AuditID] {
+			continue
+		}
+		accepted++
+		c.queued[event.AuditID] = true
+		c.pending = append(c.pending, pendingEvent{cluster: cluster, event: event, received: now})
+	}
+	if overflow := len(c.pending) - maxPending; overflow > 0 {
+		c.logger.Warn("Dropping %d audit events, more than %d are waiting for their change", overflow, maxPending)
+		for _, p := range c.pending[:overflow] {
+			delete(c.queued, p.event.AuditID)
+		}
+		c.pending = append(c.pending[:0], c.pending[overflow:]...)
+	}
+	return accepted
+}
+
+// Name implements lifecycle.Component
+func (c *Correlator) Name() string {
+	return "kubeaudit.correlator"
+}
+
+// Start implements lifecycle.Component
+func (c *Correlator) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running {
+		return nil
+	}
+	c.running = true
+	c.stopCh = make(chan struct{})
+
+	c.logger.Info("Starting audit event correlation")
+	c.wg.Add(1)
+	go c.runLoop(ctx)
+	return nil
+}
+
+// Stop implements lifecycle.Component
+func (c *Correlator) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.running {
+		c.mu.Unlock()
+		return nil
+	}
+	c.running = false
+	close(c.stopCh)
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		c.logger.Info("Audit event correlation stopped")
+		return nil
+	case <-ctx.Done():
+		c.logger.Warn("Audit event correlation shutdown timeout")
+		return ctx.Err()
+	}
+}
+
+func (c *Correlator) runLoop(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(correlateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.Correlate(ctx)
+		case <-c.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Correlate records the actors of the pending audit events whose change is in
+// the graph. Events that are still unmatched after pendingTTL are dropped.
+func (c *Correlator) Correlate(ctx context.Context) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	now := time.Now()
+	matched, unmatched := []pendingEvent(nil), pending
+	if len(pending) > 0 {
+		var err error
+		matched, unmatched, err = c.correlate(ctx, pending)
+		if err != nil {
+			c.logger.Warn("Failed to correlate %d audit events: %v", len(pending), err)
+			matched, unmatched = nil, pending
+		}
+	}
+
+	var retry []pendingEvent
+	expired := 0
+	c.mu.Lock()
+	for _, p := range matched {
+		delete(c.queued, p.event.AuditID)
+		c.done[p.event.AuditID] = now
+	}
+	for _, p := range unmatched {
+		if now.Sub(p.received) > pendingTTL {
+			expired++
+			delete(c.queued, p.event.AuditID)
+			continue
+		}
+		retry = append(retry, p)
+	}
+	c.pending = append(retry, c.pending...)
+	for id, at := range c.done {
+		if now.Sub(at) > pendingTTL {
+			delete(c.done, id)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(matched) > 0 || expired > 0 {
+		c.logger.Debug("Correlated %d audit events, %d without a recorded change, %d pending", len(matched), expired, len(retry))
+	}
+}
+
+// candidate is a ChangeEvent that may have been made by an audited request
+type candidate struct {
+	id              string
+	kind            string
+	timestamp       int64
+	eventType       string
+	resourceVersion string
+	hasActor        bool
+}
+
+// correlate records the actors of audit events on their changes and returns
+// the events whose change was found and those whose change isn't in the graph
+// (yet). The changes of all events are looked up and updated in one go.
+func (c *Correlator) correlate(ctx context.Context, pending []pendingEvent) ([]pendingEvent, []pendingEvent, error) {
+	candidates, err := c.candidates(ctx, pending)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A change is attributed to one request only, also among the requests
+	// correlated together
+	claimed := make(map[string]bool)
+	var matched, unmatched []pendingEvent
+	actors := make([]map[string]interface{}, 0)
+	for i, p := range pending {
+		for j := range candidates[i] {
+			if claimed[candidates[i][j].id] {
+				candidates[i][j].hasActor = true
+			}
+		}
+		change := matchChange(&p.event, candidates[i])
+		if change == nil {
+			unmatched = append(unmatched, p)
+			continue
+		}
+		claimed[change.id] = true
+		matched = append(matched, p)
+
+		actor := p.event.Actor()
+		actors = append(actors, map[string]interface{}{
+			"id":             change.id,
+			"user":           actor.User,
+			"serviceAccount": actor.ServiceAccount,
+			"userAgent":      actor.UserAgent,
+			"sourceIP":       actor.SourceIP,
+		})
+	}
+	if len(actors) == 0 {
+		return nil, unmatched, nil
+	}
+
+	_, err = c.client.ExecuteQuery(ctx, graph.GraphQuery{
+		Query: `
+			UNWIND $actors AS actor
+			MATCH (e:ChangeEvent {id: actor.id})
+			SET e.actorUser = actor.user,
+			    e.actorServiceAccount = actor.serviceAccount,
+			    e.actorUserAgent = actor.userAgent,
+			    e.actorSourceIP = actor.sourceIP
+		`,
+		Parameters: map[string]interface{}{
+			"actors": actors,
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record actors: %w", err)
+	}
+	return matched, unmatched, nil
+}
+
+// candidates returns, for each pending event, the changes of the audited
+// object around the time of the request. Objects are looked up by UID when the
+// audit event has it, otherwise by name.
+func (c *Correlator) candidates(ctx context.Context, pending []pendingEvent) ([][]candidate, error) {
+	candidates := make([][]candidate, len(pending))
+	byName := make(map[int]bool)
+
+	uidLookups := make([]map[string]interface{}, 0)
+	nameLookups := make([]map[string]interface{}, 0)
+	for i, p := range pending {
+		ref := p.event.ObjectRef
+		uid, _ := p.event.responseObjectMeta()
+		if uid == "" {
+			uid = ref.UID
+		}
+
+		lookup := map[string]interface{}{
+			"key":  i,
+			"from": p.event.RequestReceivedTimestamp.Add(-clockSkew).UnixNano(),
+			"to":   p.event.StageTimestamp.Add(watchDelay).UnixNano(),
+		}
+		if uid != "" {
+			lookup["uid"] = models.QualifyUID(p.cluster, uid)
+			uidLookups = append(uidLookups, lookup)
+			continue
+		}
+		lookup["apiGroup"] = ref.APIGroup
+		lookup["name"] = ref.Name
+		lookup["namespace"] = ref.Namespace
+		lookup["cluster"] = p.cluster
+		nameLookups = append(nameLookups, lookup)
+		byName[i] = true
+	}
+
+	queries := []struct {
+		lookups []map[string]interface{}
+		match   string
+	}{
+		{uidLookups, `
+			MATCH (r:ResourceIdentity {uid: lookup.uid})-[:CHANGED]->(e:ChangeEvent)
+			WHERE e.timestamp >= lookup.from AND e.timestamp <= lookup.to`},
+		{nameLookups, `
+			MATCH (r:ResourceIdentity {name: lookup.name})-[:CHANGED]->(e:ChangeEvent)
+			WHERE r.apiGroup = lookup.apiGroup
+			  AND coalesce(r.namespace, '') = lookup.namespace
+			  AND coalesce(r.cluster, '') = lookup.cluster
+			  AND e.timestamp >= lookup.from AND e.timestamp <= lookup.to`},
+	}
+	for _, q := range queries {
+		if len(q.lookups) == 0 {
+			continue
+		}
+		result, err := c.client.ExecuteQuery(ctx, graph.GraphQuery{
+			Query: `
+				UNWIND $lookups AS lookup` + q.match + `
+				RETURN lookup.key, r.kind, e.id, e.timestamp, e.eventType,
+				       coalesce(e.resourceVersion, ''), coalesce(e.actorUser, '')
+			`,
+			Parameters: map[string]interface{}{
+				"lookups": q.lookups,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query changes: %w", err)
+		}
+
+		for _, row := range result.Rows {
+			if len(row) < 7 {
+				continue
+			}
+			key := int(toInt64(row[0]))
+			if key < 0 || key >= len(pending) {
+				continue
+			}
+			cand := candidate{timestamp: toInt64(row[3])}
+			cand.kind, _ = row[1].(string)
+			cand.id, _ = row[2].(string)
+			cand.eventType, _ = row[4].(string)
+			cand.resourceVersion, _ = row[5].(string)
+			actorUser, _ := row[6].(string)
+			cand.hasActor = actorUser != ""
+			if byName[key] && !kindMatchesResource(cand.kind, pending[key].event.ObjectRef.Resource) {
+				continue
+			}
+			candidates[key] = append(candidates[key], cand)
+		}
+	}
+	return candidates, nil
+}
+
+// toInt64 converts a numeric query result value
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+// matchChange picks the change made by the audited request. With the
+// resourceVersion of the response object, only the change with that version
+// matches. Otherwise it is the first change of a fitting type that no other
+// request was correlated with.
+func matchChange(event *Event, candidates []candidate) *candidate {
+	if _, resourceVersion := event.responseObjectMeta(); resourceVersion != "" {
+		for i := range candidates {
+			if candidates[i].resourceVersion == resourceVersion {
+				return &candidates[i]
+			}
+		}
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].timestamp < candidates[j].timestamp
+	})
+	eventTypes := changeEventTypes(event)
+	for i := range candidates {
+		if !candidates[i].hasActor && eventTypes[candidates[i].eventType] {
+			return &candidates[i]
+		}
+	}
+	return nil
+}
+
+// changeEventTypes returns the ChangeEvent types a request may have caused
+func changeEventTypes(event *Event) map[string]bool {
+	create, update, remove := string(models.EventTypeCreate), string(models.EventTypeUpdate), string(models.EventTypeDelete)
+
+	if event.ObjectRef.Subresource == "eviction" {
+		// An eviction deletes the pod, or sets its deletionTimestamp
+		return map[string]bool{update: true, remove: true}
+	}
+	switch event.Verb {
+	case "create":
+		return map[string]bool{create: true}
+	case "delete":
+		// Objects with finalizers are updated with a deletionTimestamp first
+		return map[string]bool{update: true, remove: true}
+	case "patch":
+		// Server-side apply creates missing objects
+		return map[string]bool{create: true, update: true}
+	default:
+		return map[string]bool{update: true}
+	}
+}
+
+// kindMatchesResource reports whether a kind is served as the given resource,
+// e.g. Deployment as deployments
+func kindMatchesResource(kind, resource string) bool {
+	plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Kind: kind})
+	return plural.Resource == resource
+}
@@ -0,0 +1,181 @@
+package kubeaudit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestClient(t *testing.T) graph.Client {
+	t.Helper()
+
+	config := graph.DefaultClientConfig()
+	config.Backend = graph.BackendMemory
+	client := graph.NewClient(config)
+	require.NoError(t, client.Connect(context.Background()))
+	require.NoError(t, client.InitializeSchema(context.Background()))
+	return client
+}
+
+func deploymentEvent(id string, eventType models.EventType, timestamp time.Time, resourceVersion string) models.Event {
+	data, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name": "api", "namespace": "payments", "uid": "api-uid", "resourceVersion": resourceVersion,
+		},
+		"spec": map[string]interface{}{"replicas": 1},
+	})
+	return models.Event{
+		ID:        id,
+		Timestamp: timestamp.UnixNano(),
+		Type:      eventType,
+		Resource: models.ResourceMetadata{
+			Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "payments", Name: "api", UID: "api-uid",
+		},
+		Data: data,
+	}
+}
+
+func auditEvent(id, verb, user string, received time.Time) Event {
+	return Event{
+		AuditID:                  id,
+		Stage:                    StageResponseComplete,
+		Verb:                     verb,
+		User:                     authenticationv1.UserInfo{Username: user},
+		SourceIPs:                []string{"10.0.0.7", "10.0.0.1"},
+		UserAgent:                "kubectl/v1.30.1",
+		ObjectRef:                &ObjectReference{Resource: "deployments", APIGroup: "apps", Namespace: "payments", Name: "api"},
+		ResponseStatus:           &metav1.Status{Code: 200},
+		RequestReceivedTimestamp: metav1.NewMicroTime(received),
+		StageTimestamp:           metav1.NewMicroTime(received.Add(10 * time.Millisecond)),
+	}
+}
+
+func changeEvent(t *testing.T, client graph.Client, id string) graph.ChangeEvent {
+	t.Helper()
+
+	result, err := client.ExecuteQuery(context.Background(), graph.GraphQuery{
+		Query:      `MATCH (e:ChangeEvent {id: $id}) RETURN e`,
+		Parameters: map[string]interface{}{"id": id},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	props, err := graph.ParseNodeFromResult(result.Rows[0][0])
+	require.NoError(t, err)
+	return graph.ParseChangeEventFromNode(props)
+}
+
+func TestCorrelator_RecordsActors(t *testing.T) {
+	client := newTestClient(t)
+	pipeline := sync.NewPipeline(sync.DefaultPipelineConfig(), client)
+	correlator := NewCorrelator(client, "")
+
+	now := time.Now()
+	created := now.Add(-time.Minute)
+	require.NoError(t, pipeline.ProcessBatch(context.Background(), []models.Event{
+		deploymentEvent("create", models.EventTypeCreate, created, "100"),
+		deploymentEvent("scale", models.EventTypeUpdate, now.Add(-30*time.Second), "105"),
+	}))
+
+	// Logged at the Metadata level: matched by object, verb and time
+	create := auditEvent("a1", "create", "alice@example.com", created.Add(-50*time.Millisecond))
+
+	// Logged at the RequestResponse level: matched by resourceVersion
+	scale := auditEvent("a2", "patch", "system:serviceaccount:argocd:argocd-application-controller", created)
+	scale.ObjectRef.Subresource = "scale"
+	scale.ResponseObject = json.RawMessage(`{"kind":"Scale","metadata":{"uid":"api-uid","resourceVersion":"105"}}`)
+
+	// Not a change
+	get := auditEvent("a3", "get", "bob", now)
+	denied := auditEvent("a4", "delete", "mallory", now)
+	denied.ResponseStatus.Code = 403
+
+	assert.Equal(t, 2, correlator.Ingest("", []Event{create, scale, get, denied}))
+	correlator.Correlate(context.Background())
+
+	change := changeEvent(t, client, "create")
+	assert.Equal(t, "alice@example.com", change.ActorUser)
+	assert.Equal(t, "kubectl/v1.30.1", change.ActorUserAgent)
+	assert.Equal(t, "10.0.0.7", change.ActorSourceIP)
+	assert.Equal(t, "100", change.ResourceVersion)
+
+	change = changeEvent(t, client, "scale")
+	assert.Equal(t, "argocd/argocd-application-controller", change.ActorServiceAccount)
+
+	// A second delivery is ignored
+	assert.Equal(t, 0, correlator.Ingest("", []Event{create}))
+}
+
+func TestCorrelator_WaitsForChange(t *testing.T) {
+	client := newTestClient(t)
+	pipeline := sync.NewPipeline(sync.DefaultPipelineConfig(), client)
+	correlator := NewCorrelator(client, "")
+
+	now := time.Now()
+	update := auditEvent("a1", "update", "alice@example.com", now)
+	correlator.Ingest("", []Event{update})
+	correlator.Correlate(context.Background())
+	assert.Len(t, correlator.pending, 1)
+
+	// The watch event arrives after the audit event
+	require.NoError(t, pipeline.ProcessBatch(context.Background(), []models.Event{
+		deploymentEvent("create", models.EventTypeCreate, now.Add(-time.Hour), "1"),
+		deploymentEvent("update", models.EventTypeUpdate, now.Add(200*time.Millisecond), "2"),
+	}))
+	correlator.Correlate(context.Background())
+	assert.Empty(t, correlator.pending)
+
+	assert.Equal(t, "alice@example.com", changeEvent(t, client, "update").ActorUser)
+	assert.Empty(t, changeEvent(t, client, "create").ActorUser)
+}
+
+// countingClient counts the queries executed against the graph
+type countingClient struct {
+	graph.Client
+	queries int
+}
+
+func (c *countingClient) ExecuteQuery(ctx context.Context, query graph.GraphQuery) (*graph.QueryResult, error) {
+	c.queries++
+	return c.Client.ExecuteQuery(ctx, query)
+}
+
+func TestCorrelator_CorrelatesInBatches(t *testing.T) {
+	client := &countingClient{Client: newTestClient(t)}
+	pipeline := sync.NewPipeline(sync.DefaultPipelineConfig(), client.Client)
+	correlator := NewCorrelator(client, "")
+
+	now := time.Now()
+	require.NoError(t, pipeline.ProcessBatch(context.Background(), []models.Event{
+		deploymentEvent("create", models.EventTypeCreate, now.Add(-time.Hour), "1"),
+		deploymentEvent("u1", models.EventTypeUpdate, now.Add(-20*time.Second), "2"),
+		deploymentEvent("u2", models.EventTypeUpdate, now.Add(-19*time.Second), "3"),
+	}))
+
+	// Both updates fall into the time window of both requests
+	alice := auditEvent("a1", "update", "alice@example.com", now.Add(-21*time.Second))
+	bob := auditEvent("a2", "update", "bob@example.com", now.Add(-20500*time.Millisecond))
+	assert.Equal(t, 2, correlator.Ingest("", []Event{alice, bob}))
+
+	// A second delivery of a pending event is ignored
+	assert.Equal(t, 0, correlator.Ingest("", []Event{alice}))
+	assert.Len(t, correlator.pending, 2)
+
+	correlator.Correlate(context.Background())
+	assert.Empty(t, correlator.pending)
+	assert.Equal(t, 2, client.queries, "one lookup and one update for all events")
+
+	assert.Equal(t, "alice@example.com", changeEvent(t, client, "u1").ActorUser)
+	assert.Equal(t, "bob@example.com", changeEvent(t, client, "u2").ActorUser)
+	assert.Empty(t, changeEvent(t, client, "create").ActorUser)
+}
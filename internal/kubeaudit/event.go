@@ -0,0 +1,121 @@
+// Package kubeaudit ingests Kubernetes API server audit events and records the
+// actor of each change on the ChangeEvent the watcher captured for it.
+package kubeaudit
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/moolen/spectre/internal/models"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StageResponseComplete is the audit stage sent once the response was written
+const StageResponseComplete = "ResponseComplete"
+
+// serviceAccountPrefix is the user name prefix of service account users
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// Event is a Kubernetes audit event (audit.k8s.io/v1), reduced to the fields
+// used to correlate it with a change
+type Event struct {
+	AuditID                  string                     `json:"auditID"`
+	Stage                    string                     `json:"stage"`
+	Verb                     string                     `json:"verb"`
+	User                     authenticationv1.UserInfo  `json:"user"`
+	ImpersonatedUser         *authenticationv1.UserInfo `json:"impersonatedUser,omitempty"`
+	SourceIPs                []string                   `json:"sourceIPs,omitempty"`
+	UserAgent                string                     `json:"userAgent,omitempty"`
+	ObjectRef                *ObjectReference           `json:"objectRef,omitempty"`
+	ResponseStatus           *metav1.Status             `json:"responseStatus,omitempty"`
+	ResponseObject           json.RawMessage            `json:"responseObject,omitempty"`
+	RequestReceivedTimestamp metav1.MicroTime           `json:"requestReceivedTimestamp"`
+	StageTimestamp           metav1.MicroTime           `json:"stageTimestamp"`
+}
+
+// ObjectReference is the object an audited request is about
+type ObjectReference struct {
+	Resource        string `json:"resource,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name,omitempty"`
+	UID             string `json:"uid,omitempty"`
+	APIGroup        string `json:"apiGroup,omitempty"`
+	APIVersion      string `json:"apiVersion,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	Subresource     string `json:"subresource,omitempty"`
+}
+
+// EventList is the body the audit webhook backend posts
+type EventList struct {
+	Kind       string  `json:"kind,omitempty"`
+	APIVersion string  `json:"apiVersion,omitempty"`
+	Items      []Event `json:"items"`
+}
+
+// ignoredSubresources don't change the object they belong to
+var ignoredSubresources = map[string]bool{
+	"attach":      true,
+	"exec":        true,
+	"log":         true,
+	"portforward": true,
+	"proxy":       true,
+	"token":       true,
+}
+
+// IsChange reports whether the event records a successful write to a named
+// object, the only events that can be correlated with a ChangeEvent
+func (e *Event) IsChange() bool {
+	if e.Stage != StageResponseComplete || e.ObjectRef == nil || e.ObjectRef.Name == "" {
+		return false
+	}
+	if ignoredSubresources[e.ObjectRef.Subresource] {
+		return false
+	}
+	switch e.Verb {
+	case "create", "update", "patch", "delete":
+	default:
+		return false
+	}
+	if e.ResponseStatus != nil && (e.ResponseStatus.Code < 200 || e.ResponseStatus.Code >= 300) {
+		return false
+	}
+	return true
+}
+
+// Actor returns who made the request. The authenticated user is recorded,
+// not a user it impersonated.
+func (e *Event) Actor() models.Actor {
+	actor := models.Actor{
+		User:      e.User.Username,
+		UserAgent: e.UserAgent,
+	}
+	if len(e.SourceIPs) > 0 {
+		actor.SourceIP = e.SourceIPs[0]
+	}
+	if rest, ok := strings.CutPrefix(e.User.Username, serviceAccountPrefix); ok {
+		if namespace, name, ok := strings.Cut(rest, ":"); ok {
+			actor.ServiceAccount = namespace + "/" + name
+		}
+	}
+	return actor
+}
+
+// responseObjectMeta returns the UID and resourceVersion of the object in the
+// response. They are only logged at the RequestResponse audit level.
+func (e *Event) responseObjectMeta() (uid, resourceVersion string) {
+	if len(e.ResponseObject) == 0 {
+		return "", ""
+	}
+	var object struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			UID             string `json:"uid"`
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(e.ResponseObject, &object); err != nil || object.Kind == "Status" {
+		return "", ""
+	}
+	return object.Metadata.UID, object.Metadata.ResourceVersion
+}
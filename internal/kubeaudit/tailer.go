@@ -0,0 +1,243 @@
+package kubeaudit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/moolen/spectre/internal/logging"
+)
+
+// tailInterval is how often the audit log is checked for new lines
+const tailInterval = time.Second
+
+// Sink receives the audit events read from a log
+type Sink interface {
+	Ingest(cluster string, events []Event) int
+}
+
+// FileTailer follows an API server audit log (--audit-log-path, one JSON
+// event per line) and passes new events to a sink. It starts at the end of
+// the file and follows it across rotations and truncations.
+type FileTailer struct {
+	path    string
+	cluster string
+	sink    Sink
+	logger  *logging.Logger
+
+	file    *os.File
+	reader  *bufio.Reader
+	partial []byte // last line, not terminated yet
+
+	// Lifecycle
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewFileTailer creates a tailer for the audit log at path, attributing its
+// events to the given cluster
+func NewFileTailer(path, cluster string, sink Sink) *FileTailer {
+	return &FileTailer{
+		path:    path,
+		cluster: cluster,
+		sink:    sink,
+		logger:  logging.GetLogger("kubeaudit.tailer"),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Name implements lifecycle.Component
+func (t *FileTailer) Name() string {
+	return "kubeaudit.tailer"
+}
+
+// Start implements lifecycle.Component
+func (t *FileTailer) Start(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.running {
+		return nil
+	}
+	t.running = true
+	t.stopCh = make(chan struct{})
+
+	// Only new requests are of interest, their changes are still being recorded
+	if err := t.open(io.SeekEnd); err != nil {
+		t.logger.Warn("Audit log %s not readable yet: %v", t.path, err)
+	}
+
+	t.logger.Info("Following Kubernetes audit log %s", t.path)
+	t.wg.Add(1)
+	go t.runLoop(ctx)
+	return nil
+}
+
+// Stop implements lifecycle.Component
+func (t *FileTailer) Stop(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.running {
+		t.mu.Unlock()
+		return nil
+	}
+	t.running = false
+	close(t.stopCh)
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.close()
+		t.logger.Info("Audit log tailer stopped")
+		return nil
+	case <-ctx.Done():
+		t.logger.Warn("Audit log tailer shutdown timeout")
+		return ctx.Err()
+	}
+}
+
+func (t *FileTailer) runLoop(ctx context.Context) {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(tailInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.poll(); err != nil {
+				t.logger.Warn("Failed to read audit log %s: %v", t.path, err)
+			}
+		case <-t.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll reads the lines appended since the last poll. When the log was rotated
+// the rest of the old file is read before switching to the new one.
+func (t *FileTailer) poll() error {
+	if t.file == nil {
+		// A log created after start is read from the beginning
+		if err := t.open(io.SeekStart); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := t.readLines(); err != nil {
+		return err
+	}
+
+	rotated, err := t.rotated()
+	if err != nil || !rotated {
+		return err
+	}
+	t.logger.Info("Audit log %s was rotated, reopening", t.path)
+	t.close()
+	if err := t.open(io.SeekStart); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if t.file == nil {
+		return nil
+	}
+	return t.readLines()
+}
+
+// rotated reports whether the path now refers to another file, or the file
+// was truncated
+func (t *FileTailer) rotated() (bool, error) {
+	current, err := os.Stat(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	opened, err := t.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if !os.SameFile(current, opened) {
+		return true, nil
+	}
+	offset, err := t.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+	return current.Size() < offset-int64(t.reader.Buffered()), nil
+}
+
+// readLines passes the complete lines available to the sink
+func (t *FileTailer) readLines() error {
+	var events []Event
+	for {
+		line, err := t.reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			t.partial = append(t.partial, line...)
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(t.partial) > 0 {
+			line = append(t.partial, line...)
+			t.partial = nil
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.logger.Debug("Skipping malformed audit log line: %v", err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	if len(events) > 0 {
+		t.sink.Ingest(t.cluster, events)
+	}
+	return nil
+}
+
+func (t *FileTailer) open(whence int) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, whence); err != nil {
+		_ = file.Close()
+		return err
+	}
+	t.file = file
+	t.reader = bufio.NewReader(file)
+	t.partial = nil
+	return nil
+}
+
+func (t *FileTailer) close() {
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file = nil
+		t.reader = nil
+	}
+}
@@ -0,0 +1,73 @@
+package kubeaudit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink collects the audit IDs it receives
+type recordingSink struct {
+	ids []string
+}
+
+func (s *recordingSink) Ingest(cluster string, events []Event) int {
+	for _, event := range events {
+		s.ids = append(s.ids, event.AuditID)
+	}
+	return len(events)
+}
+
+func appendLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	for _, line := range lines {
+		_, err := file.WriteString(line)
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+}
+
+func auditLine(id string) string {
+	return fmt.Sprintf(`{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":%q,"stage":"ResponseComplete","verb":"update"}`+"\n", id)
+}
+
+func TestFileTailer_FollowsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	appendLines(t, path, auditLine("old"))
+
+	sink := &recordingSink{}
+	tailer := NewFileTailer(path, "", sink)
+	require.NoError(t, tailer.open(io.SeekEnd))
+	defer tailer.close()
+
+	// Lines are passed on once they are complete
+	appendLines(t, path, auditLine("a1"), `{"auditID":"a2",`)
+	require.NoError(t, tailer.poll())
+	assert.Equal(t, []string{"a1"}, sink.ids)
+
+	appendLines(t, path, `"stage":"ResponseComplete"}`+"\n", "not json\n")
+	require.NoError(t, tailer.poll())
+	assert.Equal(t, []string{"a1", "a2"}, sink.ids)
+
+	// The rest of the rotated file is read before the new one
+	appendLines(t, path, auditLine("a3"))
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendLines(t, path, auditLine("b1"))
+	require.NoError(t, tailer.poll())
+	assert.Equal(t, []string{"a1", "a2", "a3", "b1"}, sink.ids)
+
+	// Truncation starts over
+	require.NoError(t, os.Truncate(path, 0))
+	require.NoError(t, tailer.poll())
+	appendLines(t, path, auditLine("c1"))
+	require.NoError(t, tailer.poll())
+	assert.Equal(t, []string{"a1", "a2", "a3", "b1", "c1"}, sink.ids)
+}
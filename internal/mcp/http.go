@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/moolen/spectre/internal/api"
+)
+
+// Methods mcp-go doesn't route
+const (
+	methodResourcesSubscribe   mcp.MCPMethod = "resources/subscribe"
+	methodResourcesUnsubscribe mcp.MCPMethod = "resources/unsubscribe"
+)
+
+// sessionIDPrefix prefixes the session IDs handed out on initialize, matching
+// the format used by mcp-go
+const sessionIDPrefix = "mcp-session-"
+
+// HTTPMiddleware adds resource subscriptions to the streamable HTTP transport.
+// The transport runs stateless, so the middleware hands out a session ID on
+// initialize; clients send it with resources/subscribe and when opening the
+// notification stream (GET), which registers the session. resources/subscribe
+// and resources/unsubscribe are answered here since mcp-go doesn't route them.
+func (s *SpectreServer) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+
+		switch r.Method {
+		case http.MethodDelete:
+			if sessionID != "" && s.notifier != nil {
+				s.notifier.RemoveSession(sessionID)
+			}
+			next.ServeHTTP(w, r)
+			return
+		case http.MethodPost:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message struct {
+			ID     mcp.RequestId `json:"id"`
+			Method mcp.MCPMethod `json:"method"`
+			Params struct {
+				URI string `json:"uri"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(body, &message); err != nil {
+			// Let the transport report the malformed message
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch message.Method {
+		case mcp.MethodInitialize:
+			if sessionID == "" {
+				w.Header().Set(server.HeaderKeySessionID, sessionIDPrefix+uuid.New().String())
+			}
+			next.ServeHTTP(w, r)
+		case methodResourcesSubscribe, methodResourcesUnsubscribe:
+			s.handleSubscription(r.Context(), w, sessionID, message.ID, message.Method, message.Params.URI)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// handleSubscription answers resources/subscribe and resources/unsubscribe
+func (s *SpectreServer) handleSubscription(ctx context.Context, w http.ResponseWriter, sessionID string, id mcp.RequestId, method mcp.MCPMethod, uri string) {
+	respond := func(response any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response)
+	}
+	fail := func(code int, message string) {
+		respond(mcp.NewJSONRPCError(id, code, message, nil))
+	}
+
+	switch {
+	case s.notifier == nil:
+		fail(mcp.METHOD_NOT_FOUND, "resource subscriptions are not available")
+		return
+	case sessionID == "":
+		fail(mcp.INVALID_REQUEST, "resource subscriptions require the "+server.HeaderKeySessionID+" header returned by initialize")
+		return
+	case uri == "":
+		fail(mcp.INVALID_PARAMS, "uri is required")
+		return
+	}
+
+	if method == methodResourcesUnsubscribe {
+		s.notifier.Unsubscribe(sessionID, uri)
+		respond(mcp.NewJSONRPCResultResponse(id, mcp.EmptyResult{}))
+		return
+	}
+
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		fail(mcp.INVALID_PARAMS, err.Error())
+		return
+	}
+	if err := s.checkAccess(ctx, ref); err != nil {
+		fail(mcp.INVALID_PARAMS, err.Error())
+		return
+	}
+	if err := s.notifier.Subscribe(sessionID, uri); err != nil {
+		fail(mcp.INVALID_PARAMS, err.Error())
+		return
+	}
+	respond(mcp.NewJSONRPCResultResponse(id, mcp.EmptyResult{}))
+}
+
+// checkAccess rejects subscriptions to resources outside of the caller's
+// tenant scope
+func (s *SpectreServer) checkAccess(ctx context.Context, ref resourceRef) error {
+	if ref.template == NamespaceGraphURITemplate {
+		return api.CheckNamespaceAccess(ctx, ref.value)
+	}
+	return s.graphService.CheckResourceAccess(ctx, ref.value)
+}
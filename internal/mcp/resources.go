@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	namespacegraph "github.com/moolen/spectre/internal/analysis/namespace_graph"
+	"github.com/moolen/spectre/internal/mcp/tools"
+)
+
+// URI templates of the resources exposed by the server. Cluster-qualified
+// UIDs (cluster:uid) must be percent-encoded.
+const (
+	ResourceURITemplate         = "spectre://resource/{uid}"
+	ResourceTimelineURITemplate = "spectre://resource/{uid}/timeline"
+	NamespaceGraphURITemplate   = "spectre://namespace/{namespace}/graph"
+
+	// IncidentsURI lists the resources in Error or Warning state
+	IncidentsURI = "spectre://incidents"
+)
+
+// incidentsWindow is the time range the incidents resource reports on
+const incidentsWindow = time.Hour
+
+// resourceTemplates are the parsed URI templates, used to resolve the URIs
+// clients subscribe to
+var resourceTemplates = map[string]mcp.ResourceTemplate{
+	ResourceURITemplate:         mcp.NewResourceTemplate(ResourceURITemplate, "resource"),
+	ResourceTimelineURITemplate: mcp.NewResourceTemplate(ResourceTimelineURITemplate, "resource_timeline"),
+	NamespaceGraphURITemplate:   mcp.NewResourceTemplate(NamespaceGraphURITemplate, "namespace_graph"),
+}
+
+// resourceRef identifies what a resource URI points to
+type resourceRef struct {
+	template string // one of the URI templates
+	value    string // the UID or namespace
+}
+
+// key identifies the ref independently of how its URI was encoded
+func (r resourceRef) key() string {
+	return r.template + "|" + r.value
+}
+
+// parseResourceURI resolves a URI against the resource templates
+func parseResourceURI(uri string) (resourceRef, error) {
+	if uri == IncidentsURI {
+		return resourceRef{template: IncidentsURI}, nil
+	}
+	for name, template := range resourceTemplates {
+		if !template.URITemplate.Regexp().MatchString(uri) {
+			continue
+		}
+		for _, value := range template.URITemplate.Match(uri) {
+			if v := value.String(); v != "" {
+				return resourceRef{template: name, value: v}, nil
+			}
+		}
+	}
+	return resourceRef{}, fmt.Errorf("unknown resource URI %q", uri)
+}
+
+func (s *SpectreServer) registerResources() {
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(ResourceURITemplate, "resource",
+			mcp.WithTemplateDescription("Latest state of a resource by UID: identity, status and the full object of its most recent change"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(ResourceTimelineURITemplate, "resource_timeline",
+			mcp.WithTemplateDescription("Field-level changes and status transitions of a resource by UID over the last hour"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResourceTimeline,
+	)
+
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(NamespaceGraphURITemplate, "namespace_graph",
+			mcp.WithTemplateDescription("Current resources, relationships and anomalies of a namespace"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readNamespaceGraph,
+	)
+
+	s.mcpServer.AddResource(
+		mcp.NewResource(IncidentsURI, "incidents",
+			mcp.WithResourceDescription("Resources in Error or Warning state over the last hour with how long they have been failing, as returned by cluster_health"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readIncidents,
+	)
+}
+
+func (s *SpectreServer) readResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ref, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.graphService.GetResourceState(ctx, ref.value)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceContents(request.Params.URI, state)
+}
+
+func (s *SpectreServer) readResourceTimeline(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ref, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same view as the resource_timeline_changes tool with its defaults
+	input, err := json.Marshal(tools.ResourceTimelineChangesInput{ResourceUIDs: []string{ref.value}})
+	if err != nil {
+		return nil, err
+	}
+	result, err := tools.NewResourceTimelineChangesTool(s.timelineService).Execute(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceContents(request.Params.URI, result)
+}
+
+func (s *SpectreServer) readNamespaceGraph(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ref, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.graphService.AnalyzeNamespaceGraph(ctx, namespacegraph.AnalyzeInput{
+		Namespace:        ref.value,
+		Timestamp:        time.Now().UnixNano(),
+		IncludeAnomalies: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceContents(request.Params.URI, result)
+}
+
+func (s *SpectreServer) readIncidents(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	// Same view as the cluster_health tool over the window
+	now := time.Now()
+	input, err := json.Marshal(tools.ClusterHealthInput{
+		StartTime: now.Add(-incidentsWindow).Unix(),
+		EndTime:   now.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := tools.NewClusterHealthTool(s.timelineService).Execute(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceContents(request.Params.URI, result)
+}
+
+// jsonResourceContents formats a result as the JSON text contents of a resource
+func jsonResourceContents(uri string, result interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format resource: %w", err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
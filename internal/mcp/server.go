@@ -8,6 +8,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/graph/sync"
 	"github.com/moolen/spectre/internal/integration"
 	"github.com/moolen/spectre/internal/mcp/tools"
 )
@@ -22,6 +23,7 @@ type SpectreServer struct {
 	mcpServer       *server.MCPServer
 	timelineService *api.TimelineService
 	graphService    *api.GraphService
+	notifier        *ResourceNotifier // nil without a sync pipeline
 	tools           map[string]Tool
 	version         string
 }
@@ -31,6 +33,7 @@ type ServerOptions struct {
 	Version         string
 	TimelineService *api.TimelineService     // Required: Direct service for tools
 	GraphService    *api.GraphService        // Required: Direct graph service for tools
+	Pipeline        sync.Pipeline            // Optional: Enables resource subscriptions
 }
 
 // NewSpectreServerWithOptions creates a new Spectre MCP server with services
@@ -44,11 +47,15 @@ func NewSpectreServerWithOptions(opts ServerOptions) (*SpectreServer, error) {
 	}
 
 	// Create mcp-go server with capabilities
+	hooks := &server.Hooks{}
 	mcpServer := server.NewMCPServer(
 		"Spectre MCP Server",
 		opts.Version,
 		server.WithToolCapabilities(false), // No tool subscription for now
 		server.WithLogging(),               // Enable logging capability
+		// Resources can be subscribed to when following a sync pipeline
+		server.WithResourceCapabilities(opts.Pipeline != nil, false),
+		server.WithHooks(hooks),
 	)
 
 	s := &SpectreServer{
@@ -59,9 +66,18 @@ func NewSpectreServerWithOptions(opts ServerOptions) (*SpectreServer, error) {
 		version:         opts.Version,
 	}
 
+	// Follow the sync pipeline to notify resource subscribers
+	if opts.Pipeline != nil {
+		s.notifier = newResourceNotifier(mcpServer, opts.Pipeline)
+		s.notifier.addHooks(hooks)
+	}
+
 	// Register tools
 	s.registerTools()
 
+	// Register resource templates
+	s.registerResources()
+
 	// Register prompts
 	s.registerPrompts()
 
@@ -89,6 +105,10 @@ func (s *SpectreServer) registerTools() {
 					"type":        "string",
 					"description": "Optional: filter by Kubernetes namespace",
 				},
+				"cluster": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: filter by cluster name (multi-cluster deployments)",
+				},
 				"max_resources": map[string]interface{}{
 					"type":        "integer",
 					"description": "Optional: max resources to list per status (default 100, max 500)",
@@ -127,6 +147,10 @@ func (s *SpectreServer) registerTools() {
 					"type":        "integer",
 					"description": "Optional: max changes per resource (default 50, max 200)",
 				},
+				"actor": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only return changes made by a user or service account containing this value (e.g. 'alice', 'argocd/argocd-application-controller'). Requires Kubernetes audit events to be ingested",
+				},
 			},
 			"required": []string{"resource_uids"},
 		},
@@ -152,6 +176,10 @@ func (s *SpectreServer) registerTools() {
 					"type":        "string",
 					"description": "Optional: Kubernetes namespace to filter by",
 				},
+				"cluster": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: cluster name to filter by (multi-cluster deployments)",
+				},
 				"start_time": map[string]interface{}{
 					"type":        "integer",
 					"description": "Start timestamp (Unix seconds or milliseconds)",
@@ -169,6 +197,35 @@ func (s *SpectreServer) registerTools() {
 		},
 	)
 
+	// Register search_resources tool (uses TimelineService directly)
+	s.registerTool(
+		"search_resources",
+		tools.SearchResourcesDescription(),
+		tools.NewSearchResourcesTool(s.timelineService),
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Spectre query language expression, e.g. 'kind:Pod ns:payments status:Error'",
+				},
+				"start_time": map[string]interface{}{
+					"type":        "integer",
+					"description": "Start timestamp (Unix seconds or milliseconds)",
+				},
+				"end_time": map[string]interface{}{
+					"type":        "integer",
+					"description": "End timestamp (Unix seconds or milliseconds)",
+				},
+				"max_results": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: max resources to return (default 50, max 200)",
+				},
+			},
+			"required": []string{"query", "start_time", "end_time"},
+		},
+	)
+
 	// Register detect_anomalies tool (uses GraphService and TimelineService directly)
 	s.registerTool(
 		"detect_anomalies",
@@ -194,6 +251,48 @@ func (s *SpectreServer) registerTools() {
 		},
 	)
 
+	// Register find_resources tool (uses GraphService directly)
+	s.registerTool(
+		"find_resources",
+		"Find resources by a fuzzy description such as 'checkout api pod in prod' and return their UIDs for the graph tools. Words are matched against names (typos tolerated), label values, namespaces and clusters; kinds and kubectl short names (po, deploy, svc) select kinds and key=value words require a label. Deleted resources within retention are included.",
+		tools.NewFindResourcesTool(s.graphService),
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Free-text description of the resource, e.g. 'checkout api pod in prod' or 'app=checkout deploy'",
+				},
+				"kind": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: restrict to a kind, e.g. Pod or deploy",
+				},
+				"namespace": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: restrict to a namespace",
+				},
+				"cluster": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: restrict to a cluster",
+				},
+				"labels": map[string]interface{}{
+					"type":                 "object",
+					"description":          "Optional: labels the resource must have",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+				"includeDeleted": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: include deleted resources (default: true)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: maximum number of matches to return (default: 10, max: 50)",
+				},
+			},
+			"required": []string{"query"},
+		},
+	)
+
 	// Register causal_paths tool (uses GraphService directly)
 	s.registerTool(
 		"causal_paths",
@@ -226,6 +325,113 @@ func (s *SpectreServer) registerTools() {
 			"required": []string{"resourceUID", "failureTimestamp"},
 		},
 	)
+
+	// Register blast_radius tool (uses GraphService directly)
+	s.registerTool(
+		"blast_radius",
+		"Find the downstream resources that degraded after a change to a resource (e.g. a ConfigMap edit or a Node cordon). Returns impacted resources ranked by impact, grouped by relationship type and hop distance, with their anomalies.",
+		tools.NewBlastRadiusTool(s.graphService),
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"resourceUID": map[string]interface{}{
+					"type":        "string",
+					"description": "The UID of the resource that changed",
+				},
+				"changeTimestamp": map[string]interface{}{
+					"type":        "integer",
+					"description": "Unix timestamp (seconds or nanoseconds) of the change",
+				},
+				"windowMinutes": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: how long after the change to attribute degradation to it, in minutes (default: 15, max: 360)",
+				},
+				"maxDepth": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: maximum hops from the changed resource (default: 3, max: 3)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: maximum number of impacted resources to return (default: 50, max: 500)",
+				},
+			},
+			"required": []string{"resourceUID", "changeTimestamp"},
+		},
+	)
+
+	// Register graph_query tool (uses GraphService directly)
+	s.registerTool(
+		"graph_query",
+		tools.GraphQueryDescription(),
+		tools.NewGraphQueryTool(s.graphService),
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Read-only Cypher query, e.g. 'MATCH (p:ResourceIdentity {kind: $kind}) RETURN p.name'. Mutually exclusive with savedQuery",
+				},
+				"savedQuery": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of a saved query to run instead of query",
+				},
+				"parameters": map[string]interface{}{
+					"type":        "object",
+					"description": "Optional: query parameters referenced as $name",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: maximum number of rows to return (default: 100, max: 1000)",
+				},
+				"timeoutSeconds": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: query timeout in seconds (default: 10, max: 60)",
+				},
+			},
+		},
+	)
+
+	// Register cluster_snapshot tool (uses GraphService directly)
+	s.registerTool(
+		"cluster_snapshot",
+		"Reconstruct the state of a cluster or namespace at a point in time: every resource alive at that instant with its latest recorded state. Use format=yaml to get cleaned manifests for diffing against the live cluster or applying to a test cluster",
+		tools.NewClusterSnapshotTool(s.graphService),
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"at": map[string]interface{}{
+					"type":        "integer",
+					"description": "Point in time of the snapshot (Unix seconds or nanoseconds)",
+				},
+				"namespace": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: restrict the snapshot to a namespace",
+				},
+				"cluster": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: restrict the snapshot to a cluster",
+				},
+				"kinds": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Optional: restrict the snapshot to these kinds (default: all kinds except Event)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional: maximum number of resources (default: 1000, max: 5000)",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: json for resource states, yaml for applyable manifests (default: json)",
+				},
+				"includeStatus": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: keep the status of resources in YAML manifests (default: false)",
+				},
+			},
+			"required": []string{"at"},
+		},
+	)
 }
 
 func (s *SpectreServer) registerTool(name, description string, tool Tool, inputSchema map[string]interface{}) {
@@ -362,6 +568,12 @@ func (s *SpectreServer) GetMCPServer() *server.MCPServer {
 	return s.mcpServer
 }
 
+// GetResourceNotifier returns the component notifying resource subscribers,
+// or nil when the server was created without a sync pipeline
+func (s *SpectreServer) GetResourceNotifier() *ResourceNotifier {
+	return s.notifier
+}
+
 // MCPToolRegistry adapts the integration.ToolRegistry interface to the mcp-go server.
 // It allows integrations to register tools dynamically during startup.
 type MCPToolRegistry struct {
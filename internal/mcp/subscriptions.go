@@ -0,0 +1,369 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gosync "sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+const (
+	// maxSubscriptionsPerSession bounds the resources one session can follow
+	maxSubscriptionsPerSession = 100
+
+	// sessionGracePeriod is how long the subscriptions of a session without a
+	// notification stream are kept, so that clients can reconnect
+	sessionGracePeriod = 5 * time.Minute
+
+	// sweepInterval is how often subscriptions of gone sessions are dropped
+	sweepInterval = time.Minute
+)
+
+// ResourceNotifier tracks the resources MCP sessions subscribed to and sends
+// notifications/resources/updated when the sync pipeline writes a change
+// affecting them
+type ResourceNotifier struct {
+	mcpServer *server.MCPServer
+	pipeline  sync.Pipeline
+	logger    *logging.Logger
+
+	mu       gosync.Mutex
+	sessions map[string]*sessionSubscriptions
+	refs     map[string]map[string]bool // ref key -> IDs of subscribed sessions
+
+	running bool
+	stopCh  chan struct{}
+	wg      gosync.WaitGroup
+}
+
+// sessionSubscriptions holds the subscriptions of one session
+type sessionSubscriptions struct {
+	uris      map[string]resourceRef // subscribed URI -> ref
+	live      bool                   // the session is registered and can receive notifications
+	idleSince time.Time
+}
+
+func newResourceNotifier(mcpServer *server.MCPServer, pipeline sync.Pipeline) *ResourceNotifier {
+	return &ResourceNotifier{
+		mcpServer: mcpServer,
+		pipeline:  pipeline,
+		logger:    logging.GetLogger("mcp.subscriptions"),
+		sessions:  make(map[string]*sessionSubscriptions),
+		refs:      make(map[string]map[string]bool),
+	}
+}
+
+// addHooks follows the sessions registered with the MCP server, which are the
+// sessions notifications can be delivered to
+func (n *ResourceNotifier) addHooks(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		n.setLive(session.SessionID(), true)
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		n.setLive(session.SessionID(), false)
+	})
+}
+
+// Name implements lifecycle.Component
+func (n *ResourceNotifier) Name() string {
+	return "mcp.subscriptions"
+}
+
+// Start implements lifecycle.Component
+func (n *ResourceNotifier) Start(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.running {
+		return nil
+	}
+	n.running = true
+	n.stopCh = make(chan struct{})
+
+	n.logger.Info("Starting MCP resource notifications")
+	n.wg.Add(1)
+	go n.runLoop(ctx)
+	return nil
+}
+
+// Stop implements lifecycle.Component
+func (n *ResourceNotifier) Stop(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.running {
+		n.mu.Unlock()
+		return nil
+	}
+	n.running = false
+	close(n.stopCh)
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		n.logger.Info("MCP resource notifications stopped")
+		return nil
+	case <-ctx.Done():
+		n.logger.Warn("MCP resource notifications shutdown timeout")
+		return ctx.Err()
+	}
+}
+
+func (n *ResourceNotifier) runLoop(ctx context.Context) {
+	defer n.wg.Done()
+
+	sub := n.pipeline.Subscribe()
+	defer func() { sub.Close() }()
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.stopCh:
+			return
+		case <-ticker.C:
+			n.sweep(time.Now())
+		case updates, ok := <-sub.C:
+			if ok {
+				n.notify(updates)
+				continue
+			}
+			// Batches were dropped while falling behind; subscribers may
+			// have missed changes, so they are told to re-read everything
+			n.logger.Warn("Resource notifications fell behind the sync pipeline: %v", sub.Err())
+			sub = n.pipeline.Subscribe()
+			n.notifyAll()
+		}
+	}
+}
+
+// Subscribe adds a subscription of a session to a resource URI
+func (n *ResourceNotifier) Subscribe(sessionID, uri string) error {
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	subs, ok := n.sessions[sessionID]
+	if !ok {
+		subs = &sessionSubscriptions{uris: make(map[string]resourceRef), idleSince: time.Now()}
+		n.sessions[sessionID] = subs
+	}
+	if _, ok := subs.uris[uri]; ok {
+		return nil
+	}
+	if len(subs.uris) >= maxSubscriptionsPerSession {
+		return fmt.Errorf("a session can subscribe to at most %d resources", maxSubscriptionsPerSession)
+	}
+
+	subs.uris[uri] = ref
+	if n.refs[ref.key()] == nil {
+		n.refs[ref.key()] = make(map[string]bool)
+	}
+	n.refs[ref.key()][sessionID] = true
+	return nil
+}
+
+// Unsubscribe removes a subscription of a session
+func (n *ResourceNotifier) Unsubscribe(sessionID, uri string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	subs, ok := n.sessions[sessionID]
+	if !ok {
+		return
+	}
+	if _, ok := subs.uris[uri]; !ok {
+		return
+	}
+	delete(subs.uris, uri)
+	n.unindexLocked(sessionID, subs)
+	if len(subs.uris) == 0 && !subs.live {
+		delete(n.sessions, sessionID)
+	}
+}
+
+// RemoveSession drops all subscriptions of a session
+func (n *ResourceNotifier) RemoveSession(sessionID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	subs, ok := n.sessions[sessionID]
+	if !ok {
+		return
+	}
+	subs.uris = nil
+	n.unindexLocked(sessionID, subs)
+	delete(n.sessions, sessionID)
+}
+
+// unindexLocked removes the session from the refs it no longer subscribes to
+func (n *ResourceNotifier) unindexLocked(sessionID string, subs *sessionSubscriptions) {
+	remaining := make(map[string]bool, len(subs.uris))
+	for _, ref := range subs.uris {
+		remaining[ref.key()] = true
+	}
+	for key, sessions := range n.refs {
+		if !sessions[sessionID] || remaining[key] {
+			continue
+		}
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(n.refs, key)
+		}
+	}
+}
+
+func (n *ResourceNotifier) setLive(sessionID string, live bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	subs, ok := n.sessions[sessionID]
+	if !ok {
+		if !live {
+			return
+		}
+		subs = &sessionSubscriptions{uris: make(map[string]resourceRef)}
+		n.sessions[sessionID] = subs
+	}
+	subs.live = live
+	if !live {
+		if len(subs.uris) == 0 {
+			delete(n.sessions, sessionID)
+			return
+		}
+		subs.idleSince = time.Now()
+	}
+}
+
+// sweep drops the subscriptions of sessions that had no notification stream
+// for longer than the grace period
+func (n *ResourceNotifier) sweep(now time.Time) {
+	n.mu.Lock()
+	var expired []string
+	for sessionID, subs := range n.sessions {
+		if !subs.live && now.Sub(subs.idleSince) > sessionGracePeriod {
+			expired = append(expired, sessionID)
+		}
+	}
+	n.mu.Unlock()
+
+	for _, sessionID := range expired {
+		n.logger.Debug("Dropping subscriptions of idle MCP session %s", sessionID)
+		n.RemoveSession(sessionID)
+	}
+}
+
+// notification is a resource update to send to a session
+type notification struct {
+	sessionID string
+	uri       string
+}
+
+// notify sends a notification for every subscribed URI a batch changed. The
+// sessions are looked up in the refs index, so batches that change nothing
+// subscribed don't visit every session.
+func (n *ResourceNotifier) notify(updates []*sync.GraphUpdate) {
+	changed := changedRefs(updates)
+	if len(changed) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	var pending []notification
+	for key := range changed {
+		for sessionID := range n.refs[key] {
+			subs, ok := n.sessions[sessionID]
+			if !ok || !subs.live {
+				continue
+			}
+			for uri, ref := range subs.uris {
+				if ref.key() == key {
+					pending = append(pending, notification{sessionID: sessionID, uri: uri})
+				}
+			}
+		}
+	}
+	n.mu.Unlock()
+
+	n.deliver(pending)
+}
+
+// notifyAll sends a notification for every subscribed URI
+func (n *ResourceNotifier) notifyAll() {
+	n.mu.Lock()
+	var pending []notification
+	for sessionID, subs := range n.sessions {
+		if !subs.live {
+			continue
+		}
+		for uri := range subs.uris {
+			pending = append(pending, notification{sessionID: sessionID, uri: uri})
+		}
+	}
+	n.mu.Unlock()
+
+	n.deliver(pending)
+}
+
+func (n *ResourceNotifier) deliver(pending []notification) {
+	for _, p := range pending {
+		err := n.mcpServer.SendNotificationToSpecificClient(p.sessionID, mcp.MethodNotificationResourceUpdated,
+			map[string]any{"uri": p.uri})
+		if err != nil && !errors.Is(err, server.ErrSessionNotFound) {
+			n.logger.Debug("Failed to notify MCP session %s about %s: %v", p.sessionID, p.uri, err)
+		}
+	}
+}
+
+// changedRefs returns the keys of the resource refs a batch changed. A change
+// of a resource updates the resource, its timeline and the graph of its
+// namespace, and the incidents when the resource is failing or its status
+// changed; a Kubernetes Event only the timeline of the object it's about.
+func changedRefs(updates []*sync.GraphUpdate) map[string]bool {
+	changed := make(map[string]bool)
+	mark := func(template, value string) {
+		if value != "" {
+			changed[resourceRef{template: template, value: value}.key()] = true
+		}
+	}
+
+	for _, update := range updates {
+		if len(update.ResourceNodes) == 0 {
+			continue
+		}
+		if len(update.EventNodes) > 0 {
+			resource := update.ResourceNodes[0]
+			mark(ResourceURITemplate, resource.UID)
+			mark(ResourceTimelineURITemplate, resource.UID)
+			mark(NamespaceGraphURITemplate, resource.Namespace)
+			for _, event := range update.EventNodes {
+				if event.StatusChanged || event.Status == "Error" || event.Status == "Warning" {
+					changed[resourceRef{template: IncidentsURI}.key()] = true
+				}
+			}
+			continue
+		}
+		// The identity built from the Event's involvedObject follows the
+		// Event's own identity
+		if len(update.K8sEventNodes) > 0 && len(update.ResourceNodes) > 1 {
+			mark(ResourceTimelineURITemplate, update.ResourceNodes[1].UID)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,299 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/sync"
+	"github.com/moolen/spectre/internal/logging"
+)
+
+// fakeSession is a client session collecting the notifications sent to it
+type fakeSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newFakeSession(id string) *fakeSession {
+	return &fakeSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func (f *fakeSession) Initialize()       {}
+func (f *fakeSession) Initialized() bool { return true }
+func (f *fakeSession) SessionID() string { return f.id }
+func (f *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return f.notifications
+}
+
+// updatedURIs drains the resource update notifications of the session
+func (f *fakeSession) updatedURIs() []string {
+	var uris []string
+	for {
+		select {
+		case n := <-f.notifications:
+			if n.Method == mcp.MethodNotificationResourceUpdated {
+				uris = append(uris, n.Params.AdditionalFields["uri"].(string))
+			}
+		default:
+			sort.Strings(uris)
+			return uris
+		}
+	}
+}
+
+func newTestSubscriptionServer(t *testing.T) *SpectreServer {
+	t.Helper()
+
+	hooks := &server.Hooks{}
+	mcpServer := server.NewMCPServer("test", "1.0.0-test", server.WithHooks(hooks))
+	s := &SpectreServer{
+		mcpServer:    mcpServer,
+		graphService: api.NewGraphService(nil, logging.GetLogger("test"), nil),
+		notifier:     newResourceNotifier(mcpServer, nil),
+		tools:        make(map[string]Tool),
+	}
+	s.notifier.addHooks(hooks)
+	return s
+}
+
+func TestParseResourceURI(t *testing.T) {
+	tests := []struct {
+		uri      string
+		template string
+		value    string
+	}{
+		{"spectre://resource/abc-123", ResourceURITemplate, "abc-123"},
+		{"spectre://resource/prod%3Aabc-123", ResourceURITemplate, "prod:abc-123"},
+		{"spectre://resource/abc-123/timeline", ResourceTimelineURITemplate, "abc-123"},
+		{"spectre://namespace/shop/graph", NamespaceGraphURITemplate, "shop"},
+		{"spectre://incidents", IncidentsURI, ""},
+	}
+
+	for _, tt := range tests {
+		ref, err := parseResourceURI(tt.uri)
+		if err != nil {
+			t.Fatalf("parseResourceURI(%q) failed: %v", tt.uri, err)
+		}
+		if ref.template != tt.template || ref.value != tt.value {
+			t.Errorf("parseResourceURI(%q) = %+v, want %s %s", tt.uri, ref, tt.template, tt.value)
+		}
+	}
+
+	for _, uri := range []string{"spectre://resource/", "spectre://pods/abc", "https://example.com"} {
+		if _, err := parseResourceURI(uri); err == nil {
+			t.Errorf("parseResourceURI(%q) should fail", uri)
+		}
+	}
+}
+
+func TestResourceNotifier_Notify(t *testing.T) {
+	s := newTestSubscriptionServer(t)
+	notifier := s.notifier
+
+	pinned := newFakeSession("pinned")
+	other := newFakeSession("other")
+	for _, session := range []*fakeSession{pinned, other} {
+		if err := s.mcpServer.RegisterSession(context.Background(), session); err != nil {
+			t.Fatalf("RegisterSession failed: %v", err)
+		}
+	}
+
+	for _, uri := range []string{
+		"spectre://resource/deploy-1",
+		"spectre://resource/pod-1/timeline",
+		"spectre://namespace/shop/graph",
+	} {
+		if err := notifier.Subscribe("pinned", uri); err != nil {
+			t.Fatalf("Subscribe(%q) failed: %v", uri, err)
+		}
+	}
+	if err := notifier.Subscribe("other", "spectre://resource/unrelated"); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	// The Deployment changes and the Pod receives a Kubernetes Event
+	deploy := graph.ResourceIdentity{UID: "deploy-1", Kind: "Deployment", Namespace: "shop", Name: "api"}
+	event := graph.ResourceIdentity{UID: "event-1", Kind: "Event", Namespace: "shop", Name: "api-1.1"}
+	pod := graph.ResourceIdentity{UID: "pod-1", Kind: "Pod", Namespace: "shop", Name: "api-1"}
+	notifier.notify([]*sync.GraphUpdate{
+		{ResourceNodes: []graph.ResourceIdentity{deploy}, EventNodes: []graph.ChangeEvent{{ID: "ce-1"}}},
+		{ResourceNodes: []graph.ResourceIdentity{event, pod}, K8sEventNodes: []graph.K8sEvent{{ID: "ev-1"}}},
+	})
+
+	got := strings.Join(pinned.updatedURIs(), ",")
+	want := "spectre://namespace/shop/graph,spectre://resource/deploy-1,spectre://resource/pod-1/timeline"
+	if got != want {
+		t.Errorf("pinned session notified about %s, want %s", got, want)
+	}
+	if uris := other.updatedURIs(); len(uris) != 0 {
+		t.Errorf("other session should not be notified, got %v", uris)
+	}
+
+	// After unsubscribing, changes are no longer notified
+	notifier.Unsubscribe("pinned", "spectre://resource/deploy-1")
+	notifier.notify([]*sync.GraphUpdate{
+		{ResourceNodes: []graph.ResourceIdentity{deploy}, EventNodes: []graph.ChangeEvent{{ID: "ce-2"}}},
+	})
+	got = strings.Join(pinned.updatedURIs(), ",")
+	if got != "spectre://namespace/shop/graph" {
+		t.Errorf("pinned session notified about %s after unsubscribing", got)
+	}
+}
+
+func TestResourceNotifier_Incidents(t *testing.T) {
+	s := newTestSubscriptionServer(t)
+	notifier := s.notifier
+
+	session := newFakeSession("oncall")
+	if err := s.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+	if err := notifier.Subscribe("oncall", IncidentsURI); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	pod := graph.ResourceIdentity{UID: "pod-1", Kind: "Pod", Namespace: "shop", Name: "api-1"}
+	tests := []struct {
+		name     string
+		event    graph.ChangeEvent
+		notified bool
+	}{
+		{"healthy update", graph.ChangeEvent{ID: "ce-1", Status: "Ready"}, false},
+		{"failing", graph.ChangeEvent{ID: "ce-2", Status: "Error", StatusChanged: true}, true},
+		{"still failing", graph.ChangeEvent{ID: "ce-3", Status: "Warning"}, true},
+		{"recovered", graph.ChangeEvent{ID: "ce-4", Status: "Ready", StatusChanged: true}, true},
+	}
+	for _, tt := range tests {
+		notifier.notify([]*sync.GraphUpdate{
+			{ResourceNodes: []graph.ResourceIdentity{pod}, EventNodes: []graph.ChangeEvent{tt.event}},
+		})
+		uris := session.updatedURIs()
+		if tt.notified && (len(uris) != 1 || uris[0] != IncidentsURI) {
+			t.Errorf("%s: expected an incidents notification, got %v", tt.name, uris)
+		}
+		if !tt.notified && len(uris) != 0 {
+			t.Errorf("%s: expected no notification, got %v", tt.name, uris)
+		}
+	}
+}
+
+func TestResourceNotifier_SessionLifecycle(t *testing.T) {
+	s := newTestSubscriptionServer(t)
+	notifier := s.notifier
+
+	if err := notifier.Subscribe("gone", "spectre://resource/deploy-1"); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if err := notifier.Subscribe("gone", "spectre://pods/deploy-1"); err == nil {
+		t.Error("Subscribe to an unknown URI should fail")
+	}
+
+	// Subscriptions survive a reconnect within the grace period
+	session := newFakeSession("gone")
+	if err := s.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+	s.mcpServer.UnregisterSession(context.Background(), "gone")
+	notifier.sweep(time.Now())
+	if _, ok := notifier.sessions["gone"]; !ok {
+		t.Fatal("subscriptions should be kept during the grace period")
+	}
+
+	notifier.sweep(time.Now().Add(sessionGracePeriod + time.Second))
+	if len(notifier.sessions) != 0 || len(notifier.refs) != 0 {
+		t.Errorf("subscriptions of the idle session should be dropped, got %d sessions and %d refs",
+			len(notifier.sessions), len(notifier.refs))
+	}
+
+	for i := 0; i < maxSubscriptionsPerSession; i++ {
+		if err := notifier.Subscribe("busy", fmt.Sprintf("spectre://resource/uid-%d", i)); err != nil {
+			t.Fatalf("Subscribe %d failed: %v", i, err)
+		}
+	}
+	if err := notifier.Subscribe("busy", "spectre://resource/one-too-many"); err == nil {
+		t.Error("Subscribe beyond the limit should fail")
+	}
+}
+
+func TestSpectreServer_HTTPMiddleware(t *testing.T) {
+	s := newTestSubscriptionServer(t)
+	transport := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	})
+	handler := s.HTTPMiddleware(transport)
+
+	post := func(sessionID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set(server.HeaderKeySessionID, sessionID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	rpcError := func(rec *httptest.ResponseRecorder) *mcp.JSONRPCErrorDetails {
+		var response struct {
+			Error *mcp.JSONRPCErrorDetails `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid JSON-RPC response %q: %v", rec.Body.String(), err)
+		}
+		return response.Error
+	}
+
+	// Initialize hands out a session ID
+	rec := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	sessionID := rec.Header().Get(server.HeaderKeySessionID)
+	if !strings.HasPrefix(sessionID, sessionIDPrefix) {
+		t.Fatalf("initialize should return a session ID, got %q", sessionID)
+	}
+
+	// Subscribing requires the session ID
+	rec = post("", `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"spectre://resource/deploy-1"}}`)
+	if e := rpcError(rec); e == nil || e.Code != mcp.INVALID_REQUEST {
+		t.Errorf("subscribe without session should fail with INVALID_REQUEST, got %s", rec.Body.String())
+	}
+
+	rec = post(sessionID, `{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{"uri":"spectre://nothing"}}`)
+	if e := rpcError(rec); e == nil || e.Code != mcp.INVALID_PARAMS {
+		t.Errorf("subscribe to an unknown URI should fail with INVALID_PARAMS, got %s", rec.Body.String())
+	}
+
+	rec = post(sessionID, `{"jsonrpc":"2.0","id":4,"method":"resources/subscribe","params":{"uri":"spectre://resource/deploy-1"}}`)
+	if e := rpcError(rec); e != nil {
+		t.Fatalf("subscribe failed: %s", e.Message)
+	}
+	if !strings.Contains(rec.Body.String(), `"id":4`) {
+		t.Errorf("response should carry the request ID, got %s", rec.Body.String())
+	}
+	if len(s.notifier.sessions[sessionID].uris) != 1 {
+		t.Errorf("session should have one subscription")
+	}
+
+	rec = post(sessionID, `{"jsonrpc":"2.0","id":5,"method":"resources/unsubscribe","params":{"uri":"spectre://resource/deploy-1"}}`)
+	if e := rpcError(rec); e != nil {
+		t.Fatalf("unsubscribe failed: %s", e.Message)
+	}
+	if len(s.notifier.sessions) != 0 {
+		t.Errorf("unsubscribing the last resource should drop the session")
+	}
+
+	// Other messages reach the transport
+	rec = post(sessionID, `{"jsonrpc":"2.0","id":6,"method":"tools/list"}`)
+	if rec.Body.String() != `{"jsonrpc":"2.0","id":1,"result":{}}` {
+		t.Errorf("tools/list should be passed to the transport, got %s", rec.Body.String())
+	}
+}
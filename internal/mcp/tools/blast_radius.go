@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	blastradius "github.com/moolen/spectre/internal/analysis/blast_radius"
+	"github.com/moolen/spectre/internal/api"
+)
+
+// BlastRadiusTool implements blast radius analysis using GraphService
+type BlastRadiusTool struct {
+	graphService *api.GraphService
+}
+
+// NewBlastRadiusTool creates a new blast radius tool with GraphService
+func NewBlastRadiusTool(graphService *api.GraphService) *BlastRadiusTool {
+	return &BlastRadiusTool{
+		graphService: graphService,
+	}
+}
+
+// BlastRadiusInput defines the input parameters for MCP
+type BlastRadiusInput struct {
+	ResourceUID     string `json:"resourceUID"`
+	ChangeTimestamp int64  `json:"changeTimestamp"`         // Unix seconds or nanoseconds
+	WindowMinutes   int    `json:"windowMinutes,omitempty"` // Optional: window after the change in minutes (default: 15)
+	MaxDepth        int    `json:"maxDepth,omitempty"`      // Optional: max hops from the change (default: 3)
+	Limit           int    `json:"limit,omitempty"`         // Optional: max impacted resources (default: 50)
+}
+
+// Execute runs the blast radius analysis (implements Tool interface)
+func (t *BlastRadiusTool) Execute(ctx context.Context, input json.RawMessage) (interface{}, error) {
+	var params BlastRadiusInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	// Validate required fields
+	if params.ResourceUID == "" {
+		return nil, fmt.Errorf("resourceUID is required")
+	}
+	if params.ChangeTimestamp == 0 {
+		return nil, fmt.Errorf("changeTimestamp is required")
+	}
+
+	// Defaults and limits are applied by the analyzer
+	serviceInput := blastradius.BlastRadiusInput{
+		ResourceUID:     params.ResourceUID,
+		ChangeTimestamp: normalizeTimestamp(params.ChangeTimestamp),
+		WindowNs:        int64(params.WindowMinutes) * int64(time.Minute),
+		MaxDepth:        params.MaxDepth,
+		Limit:           params.Limit,
+	}
+	response, err := t.graphService.AnalyzeBlastRadius(ctx, serviceInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze blast radius: %w", err)
+	}
+	return response, nil
+}
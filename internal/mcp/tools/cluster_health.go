@@ -40,6 +40,7 @@ type ClusterHealthInput struct {
 	StartTime    int64  `json:"start_time"`
 	EndTime      int64  `json:"end_time"`
 	Namespace    string `json:"namespace,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
 	MaxResources int    `json:"max_resources,omitempty"` // Max resources to list per status, default 100, max 500
 }
 
@@ -64,6 +65,7 @@ type Issue struct {
 	ResourceUID       string `json:"resource_uid"` // UID for use with other tools (resource_timeline_changes, detect_anomalies, causal_paths)
 	Kind              string `json:"kind"`
 	Namespace         string `json:"namespace"`
+	Cluster           string `json:"cluster,omitempty"`
 	Name              string `json:"name"`
 	CurrentStatus     string `json:"current_status"`
 	ErrorDuration     int64  `json:"error_duration_seconds"`
@@ -121,6 +123,9 @@ func (t *ClusterHealthTool) Execute(ctx context.Context, input json.RawMessage)
 	if params.Namespace != "" {
 		filterParams["namespace"] = []string{params.Namespace}
 	}
+	if params.Cluster != "" {
+		filterParams["cluster"] = []string{params.Cluster}
+	}
 
 	// Use TimelineService to parse and execute query
 	startStr := fmt.Sprintf("%d", startTime)
@@ -211,6 +216,7 @@ func analyzeHealth(response *models.SearchResponse, maxResources int) *ClusterHe
 				ResourceUID:       resource.ID,
 				Kind:              resource.Kind,
 				Namespace:         resource.Namespace,
+				Cluster:           resource.Cluster,
 				Name:              resource.Name,
 				CurrentStatus:     currentStatus,
 				ErrorDuration:     errorDuration,
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/moolen/spectre/internal/analysis/snapshot"
+	"github.com/moolen/spectre/internal/api"
+)
+
+// ClusterSnapshotTool implements point-in-time snapshots using GraphService
+type ClusterSnapshotTool struct {
+	graphService *api.GraphService
+}
+
+// NewClusterSnapshotTool creates a new cluster snapshot tool with GraphService
+func NewClusterSnapshotTool(graphService *api.GraphService) *ClusterSnapshotTool {
+	return &ClusterSnapshotTool{
+		graphService: graphService,
+	}
+}
+
+// ClusterSnapshotInput defines the input parameters for MCP
+type ClusterSnapshotInput struct {
+	At            int64    `json:"at"`
+	Namespace     string   `json:"namespace,omitempty"`     // Optional: restrict to a namespace
+	Cluster       string   `json:"cluster,omitempty"`       // Optional: restrict to a cluster
+	Kinds         []string `json:"kinds,omitempty"`         // Optional: restrict to kinds (default: all but Events)
+	Limit         int      `json:"limit,omitempty"`         // Optional: max resources (default: 1000)
+	Format        string   `json:"format,omitempty"`        // Optional: json or yaml (default: json)
+	IncludeStatus bool     `json:"includeStatus,omitempty"` // Optional: keep the status in YAML manifests
+}
+
+// ClusterSnapshotManifests is the result of a snapshot rendered as YAML
+type ClusterSnapshotManifests struct {
+	At        int64  `json:"at"`
+	Namespace string `json:"namespace,omitempty"`
+	Count     int    `json:"count"`
+	Truncated bool   `json:"truncated"`
+	Manifests string `json:"manifests"`
+}
+
+// Execute reconstructs the snapshot (implements Tool interface)
+func (t *ClusterSnapshotTool) Execute(ctx context.Context, input json.RawMessage) (interface{}, error) {
+	var params ClusterSnapshotInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	// Validate required fields
+	if params.At <= 0 {
+		return nil, fmt.Errorf("at is required")
+	}
+	if params.Format != "" && params.Format != "json" && params.Format != "yaml" {
+		return nil, fmt.Errorf("format must be json or yaml")
+	}
+
+	// Defaults and limits are applied by the reconstructor
+	serviceInput := snapshot.SnapshotInput{
+		At:        normalizeTimestamp(params.At),
+		Namespace: params.Namespace,
+		Cluster:   params.Cluster,
+		Kinds:     params.Kinds,
+		Limit:     params.Limit,
+	}
+	response, err := t.graphService.Snapshot(ctx, serviceInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct snapshot: %w", err)
+	}
+
+	if params.Format != "yaml" {
+		return response, nil
+	}
+	manifests, err := snapshot.RenderManifests(response, params.IncludeStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render manifests: %w", err)
+	}
+	return &ClusterSnapshotManifests{
+		At:        response.At,
+		Namespace: response.Namespace,
+		Count:     response.Count,
+		Truncated: response.Truncated,
+		Manifests: string(manifests),
+	}, nil
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	resourcelookup "github.com/moolen/spectre/internal/analysis/resource_lookup"
+	"github.com/moolen/spectre/internal/api"
+)
+
+// FindResourcesTool implements fuzzy resource lookup using GraphService
+type FindResourcesTool struct {
+	graphService *api.GraphService
+}
+
+// NewFindResourcesTool creates a new find resources tool with GraphService
+func NewFindResourcesTool(graphService *api.GraphService) *FindResourcesTool {
+	return &FindResourcesTool{
+		graphService: graphService,
+	}
+}
+
+// FindResourcesInput defines the input parameters for MCP
+type FindResourcesInput struct {
+	Query          string            `json:"query"`
+	Kind           string            `json:"kind,omitempty"`           // Optional: restrict to a kind, aliases accepted
+	Namespace      string            `json:"namespace,omitempty"`      // Optional: restrict to a namespace
+	Cluster        string            `json:"cluster,omitempty"`        // Optional: restrict to a cluster
+	Labels         map[string]string `json:"labels,omitempty"`         // Optional: require these labels
+	IncludeDeleted *bool             `json:"includeDeleted,omitempty"` // Optional: include deleted resources (default: true)
+	Limit          int               `json:"limit,omitempty"`          // Optional: max matches (default: 10)
+}
+
+// Execute runs the resource lookup (implements Tool interface)
+func (t *FindResourcesTool) Execute(ctx context.Context, input json.RawMessage) (interface{}, error) {
+	var params FindResourcesInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	// Validate required fields
+	if strings.TrimSpace(params.Query) == "" && params.Kind == "" && params.Namespace == "" &&
+		params.Cluster == "" && len(params.Labels) == 0 {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	// Defaults and limits are applied by the finder
+	serviceInput := resourcelookup.FindInput{
+		Query:          params.Query,
+		Kind:           params.Kind,
+		Namespace:      params.Namespace,
+		Cluster:        params.Cluster,
+		Labels:         params.Labels,
+		ExcludeDeleted: params.IncludeDeleted != nil && !*params.IncludeDeleted,
+		Limit:          params.Limit,
+	}
+	response, err := t.graphService.FindResources(ctx, serviceInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resources: %w", err)
+	}
+	return response, nil
+}
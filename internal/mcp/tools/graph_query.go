@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	graphquery "github.com/moolen/spectre/internal/analysis/graph_query"
+	"github.com/moolen/spectre/internal/api"
+)
+
+// GraphQueryTool implements read-only graph queries using GraphService
+type GraphQueryTool struct {
+	graphService *api.GraphService
+}
+
+// NewGraphQueryTool creates a new graph query tool with GraphService
+func NewGraphQueryTool(graphService *api.GraphService) *GraphQueryTool {
+	return &GraphQueryTool{
+		graphService: graphService,
+	}
+}
+
+// GraphQueryDescription is the tool description, listing the saved queries
+func GraphQueryDescription() string {
+	var saved []string
+	for _, q := range graphquery.SavedQueries() {
+		var params []string
+		for _, p := range q.Parameters {
+			params = append(params, p.Name)
+		}
+		saved = append(saved, fmt.Sprintf("%s(%s): %s", q.Name, strings.Join(params, ", "), q.Description))
+	}
+	return "Run a read-only Cypher query against the resource graph for questions the other tools don't cover. " +
+		"Nodes are ResourceIdentity (uid, kind, namespace, name, labels, deleted, firstSeen, lastSeen), ChangeEvent and K8sEvent; " +
+		"relationships include OWNS, CHANGED, EMITTED_EVENT, SELECTS, SCHEDULED_ON, MOUNTS, USES_SERVICE_ACCOUNT, REFERENCES_SPEC and MANAGES. " +
+		"Write clauses are rejected and rows are limited. Namespace-restricted callers must add v.namespace IN $allowedNamespaces " +
+		"for every matched node. Saved queries: " + strings.Join(saved, "; ")
+}
+
+// GraphQueryInput defines the input parameters for MCP
+type GraphQueryInput struct {
+	Query          string                 `json:"query,omitempty"`
+	SavedQuery     string                 `json:"savedQuery,omitempty"`
+	Parameters     map[string]interface{} `json:"parameters,omitempty"`
+	Limit          int                    `json:"limit,omitempty"`          // Optional: max rows (default: 100)
+	TimeoutSeconds int                    `json:"timeoutSeconds,omitempty"` // Optional: query timeout (default: 10)
+}
+
+// Execute runs the graph query (implements Tool interface)
+func (t *GraphQueryTool) Execute(ctx context.Context, input json.RawMessage) (interface{}, error) {
+	var params GraphQueryInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	// Validate required fields
+	if params.Query == "" && params.SavedQuery == "" {
+		return nil, fmt.Errorf("query or savedQuery is required")
+	}
+
+	// Defaults and limits are applied by the executor
+	serviceInput := graphquery.QueryInput{
+		Query:          params.Query,
+		SavedQuery:     params.SavedQuery,
+		Parameters:     params.Parameters,
+		Limit:          params.Limit,
+		TimeoutSeconds: params.TimeoutSeconds,
+	}
+	response, err := t.graphService.QueryGraph(ctx, serviceInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run graph query: %w", err)
+	}
+	return response, nil
+}
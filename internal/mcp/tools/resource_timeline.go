@@ -28,6 +28,7 @@ type ResourceTimelineInput struct {
 	ResourceKind string `json:"resource_kind"`
 	ResourceName string `json:"resource_name,omitempty"` // "*" for all
 	Namespace    string `json:"namespace,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
 	StartTime    int64  `json:"start_time"`
 	EndTime      int64  `json:"end_time"`
 	MaxResults   int    `json:"max_results,omitempty"` // Max resources to return when using "*", default 20, max 100
@@ -38,6 +39,7 @@ type ResourceTimelineEvidence struct {
 	ResourceUID          string             `json:"resource_uid"` // UID for use with other tools (UUID only)
 	Kind                 string             `json:"kind"`
 	Namespace            string             `json:"namespace"`
+	Cluster              string             `json:"cluster,omitempty"`
 	Name                 string             `json:"name"`
 	CurrentStatus        string             `json:"current_status"`
 	CurrentMessage       string             `json:"current_message"`
@@ -115,6 +117,9 @@ func (t *ResourceTimelineTool) Execute(ctx context.Context, input json.RawMessag
 	if params.Namespace != "" {
 		filterParams["namespace"] = []string{params.Namespace}
 	}
+	if params.Cluster != "" {
+		filterParams["cluster"] = []string{params.Cluster}
+	}
 
 	// Use TimelineService to parse and execute query
 	startStr := fmt.Sprintf("%d", startTime)
@@ -175,6 +180,7 @@ func (t *ResourceTimelineTool) buildResourceTimelineEvidence(resource *models.Re
 		ResourceUID:          resource.ID,
 		Kind:                 resource.Kind,
 		Namespace:            resource.Namespace,
+		Cluster:              resource.Cluster,
 		Name:                 resource.Name,
 		TimelineStart:        timelineStart,
 		TimelineEnd:          timelineEnd,
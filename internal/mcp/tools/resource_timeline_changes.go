@@ -63,6 +63,10 @@ type ResourceTimelineChangesInput struct {
 	// - "spec_only": returns only spec changes (excludes .status.* paths)
 	// - "status_only": returns only status changes (only .status.* paths)
 	ChangeFilter string `json:"change_filter,omitempty"`
+
+	// Actor only returns changes made by a user or service account containing
+	// the value, e.g. "alice" or "argocd/argocd-application-controller" (optional)
+	Actor string `json:"actor,omitempty"`
 }
 
 // ResourceTimelineChangesOutput represents the output of resource_timeline_changes tool
@@ -97,10 +101,22 @@ type ResourceTimelineEntry struct {
 	// ChangeCount is the total number of changes detected
 	ChangeCount int `json:"change_count"`
 
+	// ChangedBy lists who made the changes, where known from audit events
+	ChangedBy []ChangeActor `json:"changed_by,omitempty"`
+
 	// Error is set if this resource could not be found or processed
 	Error string `json:"error,omitempty"`
 }
 
+// ChangeActor records who made a change
+type ChangeActor struct {
+	Timestamp      int64  `json:"timestamp"`
+	User           string `json:"user,omitempty"`
+	ServiceAccount string `json:"service_account,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	SourceIP       string `json:"source_ip,omitempty"`
+}
+
 // StatusConditionSummary provides a condensed view of status condition changes
 type StatusConditionSummary struct {
 	// CurrentStatus is the latest status (Ready, Warning, Error, etc.)
@@ -190,7 +206,11 @@ func (t *ResourceTimelineChangesTool) Execute(ctx context.Context, input json.Ra
 	endStr := fmt.Sprintf("%d", endTime)
 
 	// Parse query parameters using TimelineService
-	query, err := t.timelineService.ParseQueryParameters(ctx, startStr, endStr, map[string][]string{})
+	filterParams := map[string][]string{}
+	if params.Actor != "" {
+		filterParams["actor"] = []string{params.Actor}
+	}
+	query, err := t.timelineService.ParseQueryParameters(ctx, startStr, endStr, filterParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse query parameters: %w", err)
 	}
@@ -227,7 +247,7 @@ func (t *ResourceTimelineChangesTool) Execute(ctx context.Context, input json.Ra
 		}
 		foundUIDs[resource.ID] = true
 
-		entry := t.processResource(resource, maxChanges, params.IncludeFullSnapshot, changeFilter)
+		entry := t.processResource(resource, maxChanges, params.IncludeFullSnapshot, changeFilter, params.Actor)
 		output.Resources = append(output.Resources, entry)
 		output.Summary.TotalChanges += entry.ChangeCount
 
@@ -238,11 +258,15 @@ func (t *ResourceTimelineChangesTool) Execute(ctx context.Context, input json.Ra
 	}
 
 	// Add entries for UIDs that were not found
+	notFound := "resource not found in time window"
+	if params.Actor != "" {
+		notFound = fmt.Sprintf("resource not found or not changed by actor %q in time window", params.Actor)
+	}
 	for _, uid := range params.ResourceUIDs {
 		if !foundUIDs[uid] {
 			output.Resources = append(output.Resources, ResourceTimelineEntry{
 				UID:   uid,
-				Error: "resource not found in time window",
+				Error: notFound,
 			})
 			output.Summary.ResourcesNotFound++
 		}
@@ -254,8 +278,9 @@ func (t *ResourceTimelineChangesTool) Execute(ctx context.Context, input json.Ra
 	return output, nil
 }
 
-// processResource computes semantic changes for a single resource
-func (t *ResourceTimelineChangesTool) processResource(resource models.Resource, maxChanges int, includeSnapshot bool, changeFilter string) ResourceTimelineEntry {
+// processResource computes semantic changes for a single resource. With an
+// actor, only the changes made by that actor are included.
+func (t *ResourceTimelineChangesTool) processResource(resource models.Resource, maxChanges int, includeSnapshot bool, changeFilter, actor string) ResourceTimelineEntry {
 	entry := ResourceTimelineEntry{
 		UID:       resource.ID,
 		Kind:      resource.Kind,
@@ -288,11 +313,35 @@ func (t *ResourceTimelineChangesTool) processResource(resource models.Resource,
 		}
 	}
 
-	// Collect all diffs between consecutive segments
+	// Record who made the changes
+	for _, segment := range segments {
+		if segment.Actor == nil || (actor != "" && !segment.Actor.Matches(actor)) {
+			continue
+		}
+		entry.ChangedBy = append(entry.ChangedBy, ChangeActor{
+			Timestamp:      segment.StartTime,
+			User:           segment.Actor.User,
+			ServiceAccount: segment.Actor.ServiceAccount,
+			UserAgent:      segment.Actor.UserAgent,
+			SourceIP:       segment.Actor.SourceIP,
+		})
+	}
+
+	// Collect all diffs between consecutive segments. Segments only carry
+	// the field managers when they changed.
 	var allDiffs []analysis.EventDiff
+	managers := segments[0].FieldManagers
 	for i := 1; i < len(segments); i++ {
 		prevSegment := segments[i-1]
 		currSegment := segments[i]
+		if currSegment.FieldManagers != nil {
+			managers = currSegment.FieldManagers
+		}
+
+		// Skip changes made by others when filtering by actor
+		if actor != "" && !currSegment.Actor.Matches(actor) {
+			continue
+		}
 
 		// Compute JSON diff between segments
 		diffs, err := analysis.ComputeJSONDiff(prevSegment.ResourceData, currSegment.ResourceData)
@@ -302,6 +351,7 @@ func (t *ResourceTimelineChangesTool) processResource(resource models.Resource,
 
 		// Filter noisy paths
 		diffs = analysis.FilterNoisyPaths(diffs)
+		analysis.AnnotateFieldManagers(diffs, managers)
 
 		// Apply change filter based on path
 		diffs = filterDiffsByPath(diffs, changeFilter)
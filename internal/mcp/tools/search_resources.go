@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moolen/spectre/internal/api"
+	"github.com/moolen/spectre/internal/graph/searchql"
+)
+
+// SearchResourcesTool implements the search_resources MCP tool
+type SearchResourcesTool struct {
+	timelineService *api.TimelineService
+}
+
+// NewSearchResourcesTool creates a new search_resources tool using TimelineService
+func NewSearchResourcesTool(timelineService *api.TimelineService) *SearchResourcesTool {
+	return &SearchResourcesTool{
+		timelineService: timelineService,
+	}
+}
+
+// SearchResourcesDescription is the tool description, listing the query fields
+func SearchResourcesDescription() string {
+	return "Search resources with the Spectre query language, e.g. " +
+		`kind:Pod ns:payments status:Error label:app=api reason~"OOM" changed:spec.template.spec.containers.image. ` +
+		"Terms are field:value (exact) or field~value (case-insensitive substring), comma-separated values match any, " +
+		"terms are ANDed unless joined with OR, '-' or NOT negates, parentheses group and a bare word searches names. " +
+		"Event fields match when any change or Kubernetes event in the time window matches. Fields: " +
+		strings.Join(searchql.Fields(), "; ")
+}
+
+// SearchResourcesInput represents the input for search_resources tool
+type SearchResourcesInput struct {
+	Query      string `json:"query"`
+	StartTime  int64  `json:"start_time"`
+	EndTime    int64  `json:"end_time"`
+	MaxResults int    `json:"max_results,omitempty"` // default 50, max 200
+}
+
+// SearchResourcesMatch is a resource matching the query
+type SearchResourcesMatch struct {
+	ResourceUID    string `json:"resource_uid"`
+	Kind           string `json:"kind"`
+	Namespace      string `json:"namespace"`
+	Cluster        string `json:"cluster,omitempty"`
+	Name           string `json:"name"`
+	CurrentStatus  string `json:"current_status"`
+	CurrentMessage string `json:"current_message,omitempty"`
+	EventCount     int    `json:"event_count"`
+}
+
+// SearchResourcesOutput represents the output of search_resources tool
+type SearchResourcesOutput struct {
+	Query           string                 `json:"query"`
+	Resources       []SearchResourcesMatch `json:"resources"`
+	TotalMatches    int                    `json:"total_matches"`
+	Truncated       bool                   `json:"truncated"`
+	ExecutionTimeMs int64                  `json:"execution_time_ms"`
+}
+
+// Execute runs the search_resources tool
+func (t *SearchResourcesTool) Execute(ctx context.Context, input json.RawMessage) (interface{}, error) {
+	var params SearchResourcesInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	if strings.TrimSpace(params.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	startTime := params.StartTime
+	endTime := params.EndTime
+
+	// Convert milliseconds to seconds if needed
+	if startTime > 10000000000 {
+		startTime /= 1000
+	}
+	if endTime > 10000000000 {
+		endTime /= 1000
+	}
+
+	if startTime >= endTime {
+		return nil, fmt.Errorf("start_time must be before end_time")
+	}
+
+	start := time.Now()
+
+	startStr := fmt.Sprintf("%d", startTime)
+	endStr := fmt.Sprintf("%d", endTime)
+	filterParams := map[string][]string{"q": {params.Query}}
+
+	query, err := t.timelineService.ParseQueryParameters(ctx, startStr, endStr, filterParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	queryResult, eventResult, err := t.timelineService.ExecuteConcurrentQueries(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search query: %w", err)
+	}
+
+	response := t.timelineService.BuildTimelineResponse(queryResult, eventResult)
+
+	matches := make([]SearchResourcesMatch, 0, len(response.Resources))
+	for _, resource := range response.Resources {
+		match := SearchResourcesMatch{
+			ResourceUID: resource.ID,
+			Kind:        resource.Kind,
+			Namespace:   resource.Namespace,
+			Cluster:     resource.Cluster,
+			Name:        resource.Name,
+			EventCount:  len(resource.Events),
+		}
+		if len(resource.StatusSegments) > 0 {
+			last := resource.StatusSegments[len(resource.StatusSegments)-1]
+			match.CurrentStatus = last.Status
+			match.CurrentMessage = TruncateMessage(last.Message, 128, 128)
+		}
+		matches = append(matches, match)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Kind != matches[j].Kind {
+			return matches[i].Kind < matches[j].Kind
+		}
+		if matches[i].Namespace != matches[j].Namespace {
+			return matches[i].Namespace < matches[j].Namespace
+		}
+		return matches[i].Name < matches[j].Name
+	})
+
+	// Apply default limit: 50 (default), max 200
+	maxResults := ApplyDefaultLimit(params.MaxResults, 50, 200)
+	output := &SearchResourcesOutput{
+		Query:        query.Filters.Query,
+		TotalMatches: len(matches),
+	}
+	if len(matches) > maxResults {
+		matches = matches[:maxResults]
+		output.Truncated = true
+	}
+	output.Resources = matches
+	output.ExecutionTimeMs = time.Since(start).Milliseconds()
+
+	return output, nil
+}
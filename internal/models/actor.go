@@ -0,0 +1,36 @@
+package models
+
+import "strings"
+
+// Actor identifies who made a resource change, taken from the Kubernetes
+// API server audit event of the request
+type Actor struct {
+	// User is the authenticated user name, e.g. "alice@example.com" or
+	// "system:serviceaccount:argocd:argocd-application-controller"
+	User string `json:"user,omitempty"`
+
+	// ServiceAccount is "namespace/name" if the user is a service account
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// UserAgent is the user agent of the client, e.g. "kubectl/v1.30.1"
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// SourceIP is the first source IP of the request
+	SourceIP string `json:"sourceIP,omitempty"`
+}
+
+// IsEmpty reports whether nothing is known about the actor
+func (a *Actor) IsEmpty() bool {
+	return a == nil || (a.User == "" && a.ServiceAccount == "" && a.UserAgent == "" && a.SourceIP == "")
+}
+
+// Matches reports whether the user or service account contains the filter,
+// ignoring case
+func (a *Actor) Matches(filter string) bool {
+	if a == nil {
+		return false
+	}
+	filter = strings.ToLower(filter)
+	return strings.Contains(strings.ToLower(a.User), filter) ||
+		strings.Contains(strings.ToLower(a.ServiceAccount), filter)
+}
@@ -17,6 +17,7 @@ type Resource struct {
 	Kind           string          `json:"kind"`
 	Namespace      string          `json:"namespace"`
 	Name           string          `json:"name"`
+	Cluster        string          `json:"cluster,omitempty"`
 	StatusSegments []StatusSegment `json:"statusSegments,omitempty"`
 	Events         []K8sEvent      `json:"events,omitempty"`
 	PreExisting    bool            `json:"preExisting"` // true if resource existed before query start time
@@ -29,6 +30,11 @@ type StatusSegment struct {
 	Status       string          `json:"status"` // Ready, Warning, Error, Terminating, Unknown
 	Message      string          `json:"message,omitempty"`
 	ResourceData json.RawMessage `json:"resourceData,omitempty"`
+	Actor        *Actor          `json:"actor,omitempty"` // who made the change starting the segment
+
+	// FieldManagers maps field paths to the manager that last set them. It
+	// is only set when it differs from the previous segment's.
+	FieldManagers map[string]string `json:"fieldManagers,omitempty"`
 }
 
 // K8sEvent represents a Kubernetes Event (Kind=Event) associated with a resource
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ClusterUIDSeparator separates the cluster name from the Kubernetes UID in a
+// cluster-qualified UID (e.g., "prod-eu-1:7c9e6679-7425-40de-944b-e07fc1f90ae7").
+// Cluster names are DNS labels, so they never contain the separator.
+const ClusterUIDSeparator = ":"
+
+// clusterNamePattern matches a DNS-1123 label, the same rules as namespace names
+var clusterNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidateClusterName checks that a cluster name is a valid DNS-1123 label
+func ValidateClusterName(cluster string) error {
+	if cluster == "" {
+		return NewValidationError("cluster name must not be empty")
+	}
+	if len(cluster) > 63 {
+		return NewValidationError("cluster name must be 63 characters or less")
+	}
+	if !clusterNamePattern.MatchString(cluster) {
+		return NewValidationError("invalid cluster name %q: must be a lowercase DNS label", cluster)
+	}
+	return nil
+}
+
+// QualifyUID prefixes a Kubernetes UID with its cluster so that resources of
+// different clusters never collide in a shared graph. UIDs of resources
+// without a cluster, empty UIDs and already qualified UIDs are returned as is.
+func QualifyUID(cluster, uid string) string {
+	if cluster == "" || uid == "" || strings.HasPrefix(uid, cluster+ClusterUIDSeparator) {
+		return uid
+	}
+	return cluster + ClusterUIDSeparator + uid
+}
+
+// ClusterOfUID returns the cluster name of a cluster-qualified UID, or "" if
+// the UID is not qualified
+func ClusterOfUID(uid string) string {
+	if i := strings.LastIndex(uid, ClusterUIDSeparator); i >= 0 {
+		return uid[:i]
+	}
+	return ""
+}
+
+// LocalUID returns the Kubernetes UID part of a possibly cluster-qualified UID
+func LocalUID(uid string) string {
+	if i := strings.LastIndex(uid, ClusterUIDSeparator); i >= 0 {
+		return uid[i+1:]
+	}
+	return uid
+}
+
+// SetCluster tags the resource with the cluster it was observed in and
+// qualifies its UIDs with the cluster name
+func (r *ResourceMetadata) SetCluster(cluster string) {
+	r.Cluster = cluster
+	r.UID = QualifyUID(cluster, r.UID)
+	r.InvolvedObjectUID = QualifyUID(cluster, r.InvolvedObjectUID)
+}
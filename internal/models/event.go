@@ -31,7 +31,7 @@ type Event struct {
 	// Resource contains metadata about the affected resource
 	Resource ResourceMetadata `json:"resource"`
 
-	// Data is the full Kubernetes resource object (managedFields removed)
+	// Data is the full Kubernetes resource object (managedFields removed, sensitive values redacted)
 	// Null for DELETE events
 	Data json.RawMessage `json:"data,omitempty"`
 
@@ -44,8 +44,21 @@ type Event struct {
 	// PreExisting indicates this event occurred before the query time window
 	// and is included to provide context about the resource state at window start
 	PreExisting bool `json:"preExisting,omitempty"`
+
+	// Actor is who made the change, if it was correlated with a Kubernetes
+	// audit event
+	Actor *Actor `json:"actor,omitempty"`
+
+	// FieldManagers maps field paths (in diff notation, e.g.
+	// "spec.template.spec.containers[name=app].image") to the field manager
+	// that last set them, summarized from metadata.managedFields
+	FieldManagers map[string]string `json:"fieldManagers,omitempty"`
 }
 
+// ListKeyFields are the fields list elements are identified by in diff
+// paths ("containers[name=app]"), in order of preference
+var ListKeyFields = []string{"name", "containerPort", "port", "type", "key"}
+
 // Validate checks that the event has all required fields and is well-formed
 func (e *Event) Validate() error {
 	// Validate timestamp
@@ -10,6 +10,17 @@ import (
 
 // MarshalProtobuf serializes Event to protobuf binary format
 func (e *Event) MarshalProtobuf() ([]byte, error) {
+	pbEvent, err := e.ToProto()
+	if err != nil {
+		return nil, err
+	}
+
+	// Serialize to protobuf binary
+	return proto.Marshal(pbEvent)
+}
+
+// ToProto converts the Event to its protobuf representation
+func (e *Event) ToProto() (*pb.Event, error) {
 	// Convert Go Event to protobuf Event
 	pbEvent := &pb.Event{
 		Id:             e.ID,
@@ -41,11 +52,11 @@ func (e *Event) MarshalProtobuf() ([]byte, error) {
 			Name:              e.Resource.Name,
 			Uid:               e.Resource.UID,
 			InvolvedObjectUid: e.Resource.InvolvedObjectUID,
+			Cluster:           e.Resource.Cluster,
 		}
 	}
 
-	// Serialize to protobuf binary
-	return proto.Marshal(pbEvent)
+	return pbEvent, nil
 }
 
 // UnmarshalProtobuf deserializes Event from protobuf binary format
@@ -56,6 +67,11 @@ func (e *Event) UnmarshalProtobuf(data []byte) error {
 		return fmt.Errorf("failed to unmarshal protobuf event: %w", err)
 	}
 
+	return e.FromProto(pbEvent)
+}
+
+// FromProto populates the Event from its protobuf representation
+func (e *Event) FromProto(pbEvent *pb.Event) error {
 	// Convert protobuf Event to Go Event
 	e.ID = pbEvent.Id
 	e.Timestamp = pbEvent.Timestamp
@@ -87,6 +103,7 @@ func (e *Event) UnmarshalProtobuf(data []byte) error {
 			Name:              pbEvent.Resource.Name,
 			UID:               pbEvent.Resource.Uid,
 			InvolvedObjectUID: pbEvent.Resource.InvolvedObjectUid,
+			Cluster:           pbEvent.Resource.Cluster,
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 const (
@@ -100,3 +101,17 @@ func NewResourceCursor(kind, namespace, name string) *ResourceCursor {
 		Name:      name,
 	}
 }
+
+// Compare orders the cursor against a resource in (kind, namespace, name)
+// order. It returns a negative number when the resource comes after the
+// cursor, zero when it is the cursor's resource and a positive number
+// otherwise.
+func (c *ResourceCursor) Compare(kind, namespace, name string) int {
+	if c.Kind != kind {
+		return strings.Compare(c.Kind, kind)
+	}
+	if c.Namespace != namespace {
+		return strings.Compare(c.Namespace, namespace)
+	}
+	return strings.Compare(c.Name, name)
+}
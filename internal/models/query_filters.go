@@ -25,13 +25,48 @@ type QueryFilters struct {
 	// Namespaces is the list of namespaces to filter (empty = match all)
 	// Takes precedence over Namespace if both are set
 	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Clusters is the list of clusters to filter (empty = match all)
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Query is a search expression in the Spectre query language (see
+	// package searchql), applied in addition to the other filters.
+	// It is evaluated by the graph query executor and ignored by Matches.
+	Query string `json:"query,omitempty"`
+
+	// Actor keeps resources changed in the time window by a user or service
+	// account containing the value (see Actor.Matches). Like Query, it is
+	// evaluated by the query executors and ignored by Matches.
+	Actor string `json:"actor,omitempty"`
+
+	// AllowedNamespaces is the tenant scope of the caller, set by the API layer
+	// from the authenticated identity and never from request input.
+	// nil means unrestricted. Otherwise namespaced resources outside the list
+	// are excluded while cluster-scoped resources are returned in reduced form.
+	AllowedNamespaces []string `json:"-"`
+}
+
+// IsScoped reports whether results are restricted to a tenant namespace scope
+func (f *QueryFilters) IsScoped() bool {
+	return f.AllowedNamespaces != nil
+}
+
+// AllowsNamespace reports whether a resource in the namespace is visible under
+// the tenant scope. Cluster-scoped resources (empty namespace) are always visible.
+func (f *QueryFilters) AllowsNamespace(namespace string) bool {
+	if !f.IsScoped() || namespace == "" {
+		return true
+	}
+	return containsString(f.AllowedNamespaces, namespace)
 }
 
 // IsEmpty checks if no filters are specified
 func (f *QueryFilters) IsEmpty() bool {
 	return f.Group == "" && f.Version == "" &&
 		f.Kind == "" && f.Namespace == "" &&
-		len(f.Kinds) == 0 && len(f.Namespaces) == 0
+		len(f.Kinds) == 0 && len(f.Namespaces) == 0 &&
+		len(f.Clusters) == 0 && f.Query == "" && f.Actor == "" &&
+		!f.IsScoped()
 }
 
 // GetKinds returns the effective list of kinds to filter by
@@ -83,6 +118,16 @@ func (f *QueryFilters) Matches(resource ResourceMetadata) bool {
 		return false
 	}
 
+	// Check cluster filter
+	if len(f.Clusters) > 0 && !containsString(f.Clusters, resource.Cluster) {
+		return false
+	}
+
+	// Check tenant scope
+	if !f.AllowsNamespace(resource.Namespace) {
+		return false
+	}
+
 	return true
 }
 
@@ -115,6 +160,18 @@ func (f *QueryFilters) String() string {
 	if len(namespaces) > 0 {
 		result += "namespaces=" + joinStrings(namespaces, ",") + " "
 	}
+	if len(f.Clusters) > 0 {
+		result += "clusters=" + joinStrings(f.Clusters, ",") + " "
+	}
+	if f.Query != "" {
+		result += "query=" + f.Query + " "
+	}
+	if f.Actor != "" {
+		result += "actor=" + f.Actor + " "
+	}
+	if f.IsScoped() {
+		result += "allowedNamespaces=" + joinStrings(f.AllowedNamespaces, ",") + " "
+	}
 	if result == "" {
 		return "(no filters)"
 	}
@@ -0,0 +1,76 @@
+package models
+
+import "encoding/json"
+
+// reducedMetadataFields are the metadata fields kept in the reduced form of a resource
+var reducedMetadataFields = []string{"name", "uid", "creationTimestamp", "deletionTimestamp"}
+
+// reducedStatusFields are the status fields kept in the reduced form of a resource.
+// They are enough to infer health without exposing addresses, images or node info.
+var reducedStatusFields = []string{"phase", "conditions"}
+
+// ReduceResourceObject returns the reduced form of a Kubernetes object as shown
+// to tenants for cluster-scoped resources (e.g. Nodes, ClusterRoles): identity,
+// timestamps and health status, without spec, rules, labels or annotations.
+func ReduceResourceObject(obj map[string]interface{}) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+
+	reduced := make(map[string]interface{})
+	for _, key := range []string{"apiVersion", "kind"} {
+		if v, ok := obj[key]; ok {
+			reduced[key] = v
+		}
+	}
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		reduced["metadata"] = copyFields(metadata, reducedMetadataFields)
+	}
+
+	if status, ok := obj["status"].(map[string]interface{}); ok {
+		if conditions, ok := status["conditions"].([]interface{}); ok {
+			// Condition messages may reference workloads in other namespaces
+			reducedConditions := make([]interface{}, 0, len(conditions))
+			for _, c := range conditions {
+				if cond, ok := c.(map[string]interface{}); ok {
+					reducedConditions = append(reducedConditions, copyFields(cond, []string{"type", "status", "reason", "lastTransitionTime"}))
+				}
+			}
+			status = map[string]interface{}{"phase": status["phase"], "conditions": reducedConditions}
+		}
+		reduced["status"] = copyFields(status, reducedStatusFields)
+	}
+
+	return reduced
+}
+
+// ReduceResourceData applies ReduceResourceObject to a JSON encoded object.
+// Data that cannot be decoded is dropped entirely.
+func ReduceResourceData(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+
+	reduced, err := json.Marshal(ReduceResourceObject(obj))
+	if err != nil {
+		return nil
+	}
+	return reduced
+}
+
+// copyFields returns a new map with the given keys copied from src, skipping nil values
+func copyFields(src map[string]interface{}, keys []string) map[string]interface{} {
+	dst := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if v, ok := src[key]; ok && v != nil {
+			dst[key] = v
+		}
+	}
+	return dst
+}
@@ -19,9 +19,13 @@ type ResourceMetadata struct {
 	// Name is the resource name
 	Name string `json:"name"`
 
-	// UID is the unique identifier within the cluster
+	// UID is the unique identifier within the cluster. For resources with a
+	// Cluster it is qualified with the cluster name (see QualifyUID).
 	UID string `json:"uid"`
 
+	// Cluster is the name of the cluster the resource lives in ("" for single-cluster deployments)
+	Cluster string `json:"cluster,omitempty"`
+
 	// InvolvedObjectUID links Kubernetes Event objects to the resource they describe
 	InvolvedObjectUID string `json:"involvedObjectUid,omitempty"`
 }
@@ -3,9 +3,14 @@ package watcher
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/moolen/spectre/internal/logging"
 	"github.com/moolen/spectre/internal/models"
@@ -19,32 +24,72 @@ type AuditLogWriter interface {
 	Close() error
 }
 
-// FileAuditLogWriter implements AuditLogWriter using a JSONL file
+// auditLogSegmentTimeFormat names rotated audit log segments. It sorts in
+// time order and holds no characters that are invalid in file names.
+const auditLogSegmentTimeFormat = "2006-01-02T15-04-05.000"
+
+// FileAuditLogWriter implements AuditLogWriter using a JSONL file.
+//
+// With a maximum size, the file is rotated before a write would exceed it: it
+// is renamed to <name>-<timestamp><ext> (audit-2026-10-16T12-00-00.000.jsonl)
+// and a new file is started. AuditLogQuerier reads the rotated segments along
+// with the live file.
 type FileAuditLogWriter struct {
+	path        string
+	maxSize     int64
+	maxSegments int
+
 	file   *os.File
 	writer *bufio.Writer
+	size   int64
 	mutex  sync.Mutex
 	logger *logging.Logger
 }
 
-// NewFileAuditLogWriter creates a new file-based audit log writer
+// NewFileAuditLogWriter creates a new file-based audit log writer that never
+// rotates the file
 func NewFileAuditLogWriter(filePath string) (*FileAuditLogWriter, error) {
+	return NewFileAuditLogWriterWithRotation(filePath, 0, 0)
+}
+
+// NewFileAuditLogWriterWithRotation creates a file-based audit log writer that
+// rotates the file once it reaches maxSize bytes, keeping at most maxSegments
+// rotated segments. A maxSize of 0 disables rotation, a maxSegments of 0 keeps
+// every segment.
+func NewFileAuditLogWriterWithRotation(filePath string, maxSize int64, maxSegments int) (*FileAuditLogWriter, error) {
+	writer := &FileAuditLogWriter{
+		path:        filePath,
+		maxSize:     maxSize,
+		maxSegments: maxSegments,
+		logger:      logging.GetLogger("audit_log"),
+	}
+	if err := writer.open(); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// open opens the audit log file for appending
+func (w *FileAuditLogWriter) open() error {
 	// Open file for appending (create if doesn't exist)
 	// Use restrictive permissions for audit log file
 	// filePath is user-provided configuration for watcher audit log
 	// #nosec G304 -- Audit log path is intentionally configurable by user
-	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	file, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+		return fmt.Errorf("failed to open audit log file: %w", err)
 	}
-
-	writer := &FileAuditLogWriter{
-		file:   file,
-		writer: bufio.NewWriter(file),
-		logger: logging.GetLogger("audit_log"),
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat audit log file: %w", err)
 	}
 
-	return writer, nil
+	w.file = file
+	w.writer = bufio.NewWriter(file)
+	w.size = info.Size()
+	return nil
 }
 
 // WriteEvent writes an event to the audit log in JSONL format
@@ -58,6 +103,14 @@ func (w *FileAuditLogWriter) WriteEvent(event *models.Event) error {
 		return fmt.Errorf("failed to marshal event to JSON: %w", err)
 	}
 
+	// Rotate before the line would take the file past its maximum size
+	lineSize := int64(len(jsonData)) + 1
+	if w.maxSize > 0 && w.size > 0 && w.size+lineSize > w.maxSize {
+		if err := w.rotate(); err != nil {
+			w.logger.Warn("Failed to rotate audit log: %v", err)
+		}
+	}
+
 	// Write JSON line
 	if _, err := w.writer.Write(jsonData); err != nil {
 		return fmt.Errorf("failed to write event to audit log: %w", err)
@@ -72,10 +125,75 @@ func (w *FileAuditLogWriter) WriteEvent(event *models.Event) error {
 	if err := w.writer.Flush(); err != nil {
 		return fmt.Errorf("failed to flush audit log: %w", err)
 	}
+	w.size += lineSize
 
 	return nil
 }
 
+// rotate renames the audit log file to a timestamped segment, starts a new
+// file and removes the oldest segments beyond maxSegments. If the file can't
+// be renamed, writing continues to it.
+func (w *FileAuditLogWriter) rotate() error {
+	if err := w.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush audit log: %w", err)
+	}
+
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(w.path, ext) + "-"
+	stamp := time.Now().UTC().Format(auditLogSegmentTimeFormat)
+	segment := prefix + stamp + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(segment); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		// Rotated within the same millisecond; "_" sorts after the extension
+		segment = fmt.Sprintf("%s%s_%d%s", prefix, stamp, i, ext)
+	}
+
+	if err := os.Rename(w.path, segment); err != nil {
+		return fmt.Errorf("failed to rename audit log: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		w.logger.Warn("Failed to close rotated audit log %s: %v", segment, err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.logger.Info("Rotated audit log to %s", segment)
+
+	if w.maxSegments > 0 {
+		w.pruneSegments(prefix, ext)
+	}
+	return nil
+}
+
+// pruneSegments removes the oldest rotated segments beyond maxSegments
+func (w *FileAuditLogWriter) pruneSegments(prefix, ext string) {
+	dir, namePrefix := filepath.Split(prefix)
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		w.logger.Warn("Failed to list audit log segments: %v", err)
+		return
+	}
+
+	var segments []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, namePrefix) && strings.HasSuffix(name, ext) {
+			segments = append(segments, filepath.Join(dir, name))
+		}
+	}
+
+	// Segment names hold their rotation time, so they sort oldest first
+	sort.Strings(segments)
+	for len(segments) > w.maxSegments {
+		if err := os.Remove(segments[0]); err != nil {
+			w.logger.Warn("Failed to remove audit log segment %s: %v", segments[0], err)
+		}
+		segments = segments[1:]
+	}
+}
+
 // Close closes the audit log writer and flushes any pending writes
 func (w *FileAuditLogWriter) Close() error {
 	w.mutex.Lock()
@@ -103,4 +221,3 @@ func (w *FileAuditLogWriter) Close() error {
 
 	return nil
 }
-
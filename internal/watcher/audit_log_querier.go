@@ -0,0 +1,207 @@
+package watcher
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/moolen/spectre/internal/models"
+)
+
+// maxAuditLogLine bounds the size of a single event in the audit log
+const maxAuditLogLine = 16 * 1024 * 1024
+
+// AuditLogQuerier answers event queries from the files written by
+// FileAuditLogWriter. It implements sync.StorageQuerier, so the graph can be
+// rebuilt from the audit log.
+//
+// Besides the log file itself, rotated segments next to it are read too:
+// files named <log>.<suffix> (logrotate: audit.jsonl.1, audit.jsonl.2.gz) or
+// <name>-<suffix><ext> (audit-2026-10-16T12-00-00.jsonl). If the path is a
+// directory, every file in it is a segment. Segments ending in .gz are
+// decompressed.
+type AuditLogQuerier struct {
+	path   string
+	logger *logging.Logger
+}
+
+// NewAuditLogQuerier creates a querier over the audit log at path
+func NewAuditLogQuerier(path string) *AuditLogQuerier {
+	return &AuditLogQuerier{
+		path:   path,
+		logger: logging.GetLogger("audit_log"),
+	}
+}
+
+// Segments returns the files of the audit log, oldest first
+func (q *AuditLogQuerier) Segments() ([]string, error) {
+	info, err := os.Stat(q.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var candidates []string
+	if info.IsDir() {
+		entries, err := os.ReadDir(q.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log directory: %w", err)
+		}
+		for _, entry := range entries {
+			candidates = append(candidates, filepath.Join(q.path, entry.Name()))
+		}
+	} else {
+		dir, base := filepath.Split(q.path)
+		ext := filepath.Ext(base)
+		rotatedPrefix := strings.TrimSuffix(base, ext) + "-"
+
+		entries, err := os.ReadDir(filepath.Clean(dir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log directory: %w", err)
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			rotated := strings.HasPrefix(name, rotatedPrefix) &&
+				(strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz"))
+			if name == base || strings.HasPrefix(name, base+".") || rotated {
+				candidates = append(candidates, filepath.Join(dir, name))
+			}
+		}
+	}
+
+	type segment struct {
+		path    string
+		modTime time.Time
+	}
+	var segments []segment
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		segments = append(segments, segment{path: candidate, modTime: info.ModTime()})
+	}
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].modTime.Before(segments[j].modTime)
+	})
+
+	paths := make([]string, 0, len(segments))
+	for _, s := range segments {
+		paths = append(paths, s.path)
+	}
+	return paths, nil
+}
+
+// Query returns the events of the audit log matching the request, ordered by
+// timestamp. Events found in several segments are returned once.
+func (q *AuditLogQuerier) Query(ctx context.Context, request models.QueryRequest) (*models.QueryResult, error) {
+	start := time.Now()
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	startNs := request.StartTimestamp * 1e9
+	endNs := request.EndTimestamp * 1e9
+
+	segments, err := q.Segments()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	events := []models.Event{}
+	for _, path := range segments {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		read, skipped, err := q.readSegment(path, func(event models.Event) {
+			if event.Timestamp < startNs || event.Timestamp > endNs || !request.Filters.Matches(event.Resource) {
+				return
+			}
+			if event.ID != "" {
+				if seen[event.ID] {
+					return
+				}
+				seen[event.ID] = true
+			}
+			events = append(events, event)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log segment %s: %w", path, err)
+		}
+		if skipped > 0 {
+			q.logger.Warn("Skipped %d malformed lines in audit log segment %s", skipped, path)
+		}
+		q.logger.Debug("Read %d events from audit log segment %s", read, path)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+
+	return &models.QueryResult{
+		Events:          events,
+		Count:           int32(len(events)),                      // #nosec G115 -- bounded by the audit log size
+		ExecutionTimeMs: int32(time.Since(start).Milliseconds()), // #nosec G115 -- query durations are small
+		FilesSearched:   int32(len(segments)),                    // #nosec G115 -- number of segments is small
+		QueryStartTime:  startNs,
+		QueryEndTime:    endNs,
+	}, nil
+}
+
+// readSegment calls fn for every event of a segment. It returns the number of
+// events read and of lines that couldn't be parsed. A truncated last line,
+// as left by a crash while writing, counts as malformed.
+func (q *AuditLogQuerier) readSegment(path string, fn func(models.Event)) (int, int, error) {
+	file, err := os.Open(path) // #nosec G304 -- segments are listed from the configured audit log path
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+		reader = gz
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditLogLine)
+
+	read, skipped := 0, 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var event models.Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			skipped++
+			continue
+		}
+		read++
+		fn(event)
+	}
+	if err := scanner.Err(); err != nil {
+		return read, skipped, err
+	}
+	return read, skipped, nil
+}
@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAuditLog(t *testing.T, path string, events ...models.Event) {
+	t.Helper()
+
+	writer, err := NewFileAuditLogWriter(path)
+	require.NoError(t, err)
+	for i := range events {
+		require.NoError(t, writer.WriteEvent(&events[i]))
+	}
+	require.NoError(t, writer.Close())
+}
+
+func auditEvent(id, kind string, at time.Time) models.Event {
+	return models.Event{
+		ID:        id,
+		Timestamp: at.UnixNano(),
+		Type:      models.EventTypeUpdate,
+		Resource:  models.ResourceMetadata{Version: "v1", Kind: kind, Namespace: "default", Name: id, UID: id},
+		Data:      []byte(`{"kind":"` + kind + `"}`),
+	}
+}
+
+func gzipFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	data, err := os.ReadFile(src)
+	require.NoError(t, err)
+	out, err := os.Create(dst)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(out)
+	_, err = gz.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, out.Close())
+	require.NoError(t, os.Remove(src))
+}
+
+func TestAuditLogQuerier_RotatedSegments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	now := time.Now()
+
+	// Oldest segment, rotated and compressed by logrotate
+	writeAuditLog(t, path+".2", auditEvent("a", "Pod", now.Add(-5*time.Hour)))
+	gzipFile(t, path+".2", path+".2.gz")
+	// Rotated segment repeating an event of the live log
+	writeAuditLog(t, path+".1",
+		auditEvent("b", "Deployment", now.Add(-3*time.Hour)),
+		auditEvent("c", "Pod", now.Add(-2*time.Hour)),
+	)
+	writeAuditLog(t, path,
+		auditEvent("c", "Pod", now.Add(-2*time.Hour)),
+		auditEvent("d", "Pod", now.Add(-time.Hour)),
+	)
+	// A crash left a truncated line; unrelated files are ignored
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":"e","timest`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "watcher.yaml"), []byte("resources: []"), 0o600))
+
+	end := now.Add(time.Minute)
+	querier := NewAuditLogQuerier(path)
+	segments, err := querier.Segments()
+	require.NoError(t, err)
+	assert.Len(t, segments, 3)
+
+	result, err := querier.Query(context.Background(), models.QueryRequest{
+		StartTimestamp: now.Add(-4 * time.Hour).Unix(),
+		EndTimestamp:   end.Unix(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d"}, ids(result.Events))
+	assert.Equal(t, int32(3), result.Count)
+
+	result, err = querier.Query(context.Background(), models.QueryRequest{
+		StartTimestamp: now.Add(-6 * time.Hour).Unix(),
+		EndTimestamp:   end.Unix(),
+		Filters:        models.QueryFilters{Kind: "Pod"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c", "d"}, ids(result.Events))
+}
+
+func TestAuditLogQuerier_Directory(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeAuditLog(t, filepath.Join(dir, "audit-1.jsonl"), auditEvent("a", "Pod", now.Add(-time.Hour)))
+	writeAuditLog(t, filepath.Join(dir, "audit-2.jsonl"), auditEvent("b", "Pod", now.Add(-2*time.Hour)))
+
+	result, err := NewAuditLogQuerier(dir).Query(context.Background(), models.QueryRequest{
+		StartTimestamp: now.Add(-3 * time.Hour).Unix(),
+		EndTimestamp:   now.Add(time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, ids(result.Events))
+}
+
+func TestAuditLogQuerier_MissingLog(t *testing.T) {
+	_, err := NewAuditLogQuerier(filepath.Join(t.TempDir(), "missing.jsonl")).Query(context.Background(), models.QueryRequest{})
+	assert.Error(t, err)
+}
+
+func ids(events []models.Event) []string {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		result = append(result, event.ID)
+	}
+	return result
+}
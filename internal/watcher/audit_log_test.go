@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAuditLogWriter_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	now := time.Now()
+
+	var events []models.Event
+	for i := 0; i < 5; i++ {
+		events = append(events, auditEvent(fmt.Sprintf("e%d", i), "Pod", now.Add(time.Duration(i-5)*time.Minute)))
+	}
+	line, err := json.Marshal(events[0])
+	require.NoError(t, err)
+
+	// Every file holds two events
+	writer, err := NewFileAuditLogWriterWithRotation(path, int64(2*(len(line)+1)), 0)
+	require.NoError(t, err)
+	for i := range events {
+		require.NoError(t, writer.WriteEvent(&events[i]))
+	}
+	require.NoError(t, writer.Close())
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, rotated, 2)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.EqualValues(t, len(line)+1, info.Size())
+
+	// The querier reads the rotated segments along with the live file
+	querier := NewAuditLogQuerier(path)
+	segments, err := querier.Segments()
+	require.NoError(t, err)
+	assert.Len(t, segments, 3)
+	result, err := querier.Query(context.Background(), models.QueryRequest{
+		StartTimestamp: now.Add(-time.Hour).Unix(),
+		EndTimestamp:   now.Add(time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e0", "e1", "e2", "e3", "e4"}, ids(result.Events))
+
+	// Reopening continues the live file and prunes the oldest segments
+	writer, err = NewFileAuditLogWriterWithRotation(path, int64(2*(len(line)+1)), 1)
+	require.NoError(t, err)
+	for i := range events[:2] {
+		require.NoError(t, writer.WriteEvent(&events[i]))
+	}
+	require.NoError(t, writer.Close())
+
+	remaining, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Greater(t, remaining[0], rotated[1])
+}
@@ -0,0 +1,117 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint is the last resourceVersion seen for a watched GVR
+type Checkpoint struct {
+	ResourceVersion string `json:"resourceVersion"`
+
+	// Filter identifies the watch filter the resourceVersion was observed
+	// with. A watch is only resumed if the configured filter is unchanged.
+	Filter string `json:"filter,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CheckpointStore keeps the last seen resourceVersion per watched GVR so that
+// restarted watches resume where they stopped instead of relisting. With a
+// path, checkpoints are persisted on Flush and survive restarts of Spectre.
+type CheckpointStore struct {
+	path string // empty keeps checkpoints in memory only
+
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+	dirty       bool
+}
+
+// NewCheckpointStore creates a checkpoint store persisted at path, loading
+// existing checkpoints. An empty path keeps checkpoints in memory only.
+func NewCheckpointStore(path string) (*CheckpointStore, error) {
+	s := &CheckpointStore{
+		path:        path,
+		checkpoints: make(map[string]Checkpoint),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is configured by the operator
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoints %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &s.checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoints %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Get returns the checkpoint of a GVR
+func (s *CheckpointStore) Get(key string) (Checkpoint, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint, ok := s.checkpoints[key]
+	return checkpoint, ok
+}
+
+// Set records the checkpoint of a GVR
+func (s *CheckpointStore) Set(key string, checkpoint Checkpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[key] = checkpoint
+	s.dirty = true
+}
+
+// Flush writes the checkpoints to disk if they changed since the last flush
+func (s *CheckpointStore) Flush() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	data, err := json.MarshalIndent(s.checkpoints, "", "  ")
+	s.dirty = false
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+
+	if err := s.write(data); err != nil {
+		// Retry on the next flush
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// write replaces the checkpoint file atomically, so a crash never leaves a
+// truncated file behind
+func (s *CheckpointStore) write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write checkpoints: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write checkpoints: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package watcher
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moolen/spectre/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// discoveryAliases are resources served under a second group. They are
+// skipped in auto discovery mode, the objects are captured via the original.
+var discoveryAliases = map[string]bool{
+	"events.k8s.io/events": true, // core/v1 events
+}
+
+// WatchStatus describes an active watcher
+type WatchStatus struct {
+	Group      string `json:"group"`
+	Version    string `json:"version"`
+	Resource   string `json:"resource"`
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+
+	// LabelSelector and FieldSelector are the selectors passed to the API server
+	LabelSelector string `json:"labelSelector,omitempty"`
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// Since is when the watcher was started
+	Since time.Time `json:"since"`
+}
+
+// PendingResource is a configured resource the API server doesn't serve (yet)
+type PendingResource struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// WatchSet is the set of resources the watcher records
+type WatchSet struct {
+	AutoDiscovery bool              `json:"autoDiscovery"`
+	Watches       []WatchStatus     `json:"watches"`
+	Pending       []PendingResource `json:"pending"`
+}
+
+// ActiveWatches returns the active watchers, sorted by group and resource,
+// and the configured resources still waiting for their CRD
+func (w *Watcher) ActiveWatches() WatchSet {
+	set := WatchSet{
+		Watches: []WatchStatus{},
+		Pending: []PendingResource{},
+	}
+
+	w.watchersMutex.RLock()
+	set.AutoDiscovery = w.discovery != nil
+	for _, status := range w.watchStatus {
+		set.Watches = append(set.Watches, status)
+	}
+	w.watchersMutex.RUnlock()
+	sort.Slice(set.Watches, func(i, j int) bool {
+		a, b := set.Watches[i], set.Watches[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Version < b.Version
+	})
+
+	w.pendingMutex.RLock()
+	for _, resource := range w.pendingResources {
+		set.Pending = append(set.Pending, PendingResource{
+			Group:   resource.Group,
+			Version: resource.Version,
+			Kind:    resource.Kind,
+		})
+	}
+	w.pendingMutex.RUnlock()
+
+	return set
+}
+
+// discoverResources lists the watchable resources in the preferred version of
+// every API group selected by the discovery config. If some groups could not
+// be discovered, e.g. because an aggregated API is down, the remaining groups
+// are returned and complete is false.
+func (w *Watcher) discoverResources(cfg config.DiscoveryConfig) (gvrMap map[string]*watchedGVR, complete bool, err error) {
+	lists, err := w.discoveryClient.ServerPreferredResources()
+	if err != nil {
+		if len(lists) == 0 {
+			return nil, false, err
+		}
+		w.logger.Warn("API discovery incomplete, skipping unavailable groups: %v", err)
+		return discoveredGVRs(lists, cfg), false, nil
+	}
+	return discoveredGVRs(lists, cfg), true, nil
+}
+
+// discoveredGVRs selects the resources that support list and watch from
+// discovery results, keyed by "group/version/resource"
+func discoveredGVRs(lists []*metav1.APIResourceList, cfg config.DiscoveryConfig) map[string]*watchedGVR {
+	gvrMap := make(map[string]*watchedGVR)
+
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || !cfg.MatchesGroup(gv.Group) {
+			continue
+		}
+
+		for _, apiResource := range list.APIResources {
+			// Skip subresources such as pods/log
+			if strings.Contains(apiResource.Name, "/") {
+				continue
+			}
+			verbs := apiResource.Verbs
+			if !containsVerb(verbs, "list") || !containsVerb(verbs, "watch") {
+				continue
+			}
+			if discoveryAliases[gv.Group+"/"+apiResource.Name] {
+				continue
+			}
+
+			gvr := gv.WithResource(apiResource.Name)
+			key := gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+			gvrMap[key] = &watchedGVR{
+				gvr:        gvr,
+				namespaced: apiResource.Namespaced,
+				kind:       apiResource.Kind,
+				resources: []config.Resource{{
+					Group:   gv.Group,
+					Version: gv.Version,
+					Kind:    apiResource.Kind,
+				}},
+			}
+		}
+	}
+
+	return gvrMap
+}
+
+func containsVerb(verbs metav1.Verbs, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// syncDiscoveredResources starts watchers for resources that appeared since
+// the last discovery and stops the watchers of resources that were removed,
+// e.g. when a CRD is uninstalled. It does nothing outside auto discovery mode.
+func (w *Watcher) syncDiscoveredResources(ctx context.Context) {
+	w.loadMutex.Lock()
+	defer w.loadMutex.Unlock()
+
+	if w.discovery == nil {
+		return
+	}
+
+	gvrMap, complete, err := w.discoverResources(*w.discovery)
+	if err != nil {
+		w.logger.Warn("API discovery failed, keeping current watchers: %v", err)
+		return
+	}
+
+	// Stop watchers of removed resources. If discovery was incomplete, a
+	// missing resource may only be unavailable for now, so nothing is stopped.
+	var removed []string
+	w.watchersMutex.Lock()
+	for key, cancel := range w.watchers {
+		if _, exists := gvrMap[key]; exists || !complete {
+			continue
+		}
+		cancel()
+		delete(w.watchers, key)
+		delete(w.watchStatus, key)
+		removed = append(removed, key)
+	}
+	w.watchersMutex.Unlock()
+
+	w.filtersMutex.Lock()
+	for _, key := range removed {
+		delete(w.watchFilters, key)
+	}
+	w.filtersMutex.Unlock()
+
+	for _, key := range removed {
+		w.logger.Info("Resource no longer served, stopped watcher: %s", key)
+	}
+
+	// Start watchers for new resources
+	for key, info := range gvrMap {
+		w.watchersMutex.RLock()
+		_, exists := w.watchers[key]
+		w.watchersMutex.RUnlock()
+		if exists {
+			continue
+		}
+
+		w.startWatchedGVR(ctx, key, info)
+		w.logger.Info("Discovered new resource, started watcher: %s", key)
+	}
+}
@@ -0,0 +1,147 @@
+package watcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moolen/spectre/internal/config"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// preferredDiscovery serves fixed preferred resources, the fake discovery
+// client returns none
+type preferredDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	lists []*metav1.APIResourceList
+}
+
+func (d *preferredDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return d.lists, nil
+}
+
+type nopHandler struct{}
+
+func (nopHandler) OnAdd(runtime.Object) error                    { return nil }
+func (nopHandler) OnUpdate(runtime.Object, runtime.Object) error { return nil }
+func (nopHandler) OnDelete(runtime.Object) error                 { return nil }
+
+var watchVerbs = metav1.Verbs{"get", "list", "watch"}
+
+func discoveryLists(withCRD bool) []*metav1.APIResourceList {
+	lists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: watchVerbs},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+				{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: metav1.Verbs{"create"}},
+				{Name: "events", Kind: "Event", Namespaced: true, Verbs: watchVerbs},
+			},
+		},
+		{
+			GroupVersion: "events.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "events", Kind: "Event", Namespaced: true, Verbs: watchVerbs},
+			},
+		},
+		{
+			GroupVersion: "metrics.k8s.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "PodMetrics", Namespaced: true, Verbs: watchVerbs},
+			},
+		},
+	}
+	if withCRD {
+		lists = append(lists, &metav1.APIResourceList{
+			GroupVersion: "helm.toolkit.fluxcd.io/v2",
+			APIResources: []metav1.APIResource{
+				{Name: "helmreleases", Kind: "HelmRelease", Namespaced: true, Verbs: watchVerbs},
+			},
+		})
+	}
+	return lists
+}
+
+func TestDiscoveredGVRs(t *testing.T) {
+	gvrMap := discoveredGVRs(discoveryLists(true), config.DiscoveryConfig{
+		ExcludeGroups: []string{"*.metrics.k8s.io", "metrics.k8s.io"},
+	})
+
+	keys := make([]string, 0, len(gvrMap))
+	for key := range gvrMap {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"/v1/pods", "/v1/events", "helm.toolkit.fluxcd.io/v2/helmreleases"}, keys)
+
+	release := gvrMap["helm.toolkit.fluxcd.io/v2/helmreleases"]
+	assert.Equal(t, "HelmRelease", release.kind)
+	assert.True(t, release.namespaced)
+
+	coreOnly := discoveredGVRs(discoveryLists(true), config.DiscoveryConfig{IncludeGroups: []string{"core"}})
+	assert.Len(t, coreOnly, 2)
+}
+
+func TestWatcher_SyncDiscoveredResources(t *testing.T) {
+	discovery := &preferredDiscovery{
+		FakeDiscovery: &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}},
+		lists:         discoveryLists(false),
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}:                                          "PodList",
+		{Version: "v1", Resource: "events"}:                                        "EventList",
+		{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}:            "PodMetricsList",
+		{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}: "HelmReleaseList",
+	})
+	w := &Watcher{
+		dynamicClient:   dynamicClient,
+		discoveryClient: discovery,
+		stopChan:        make(chan struct{}),
+		logger:          logging.GetLogger("watcher"),
+		eventHandler:    nopHandler{},
+		watchers:        make(map[string]context.CancelFunc),
+		watchStatus:     make(map[string]WatchStatus),
+		watchFilters:    make(map[string]*watchFilter),
+		discovery:       &config.DiscoveryConfig{},
+	}
+	w.checkpoints, _ = NewCheckpointStore("")
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		close(w.stopChan)
+		w.wg.Wait()
+	})
+
+	watched := func() []string {
+		var keys []string
+		for _, status := range w.ActiveWatches().Watches {
+			keys = append(keys, status.Group+"/"+status.Resource)
+		}
+		return keys
+	}
+
+	w.syncDiscoveredResources(ctx)
+	assert.Equal(t, []string{"/events", "/pods", "metrics.k8s.io/pods"}, watched())
+
+	// CRD installed
+	discovery.lists = discoveryLists(true)
+	w.syncDiscoveredResources(ctx)
+	assert.Contains(t, watched(), "helm.toolkit.fluxcd.io/helmreleases")
+
+	// CRD removed
+	discovery.lists = discoveryLists(false)
+	w.syncDiscoveredResources(ctx)
+	assert.NotContains(t, watched(), "helm.toolkit.fluxcd.io/helmreleases")
+
+	set := w.ActiveWatches()
+	assert.True(t, set.AutoDiscovery)
+	require.Len(t, set.Watches, 3)
+	assert.Empty(t, set.Pending)
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/moolen/spectre/internal/config"
 	"github.com/moolen/spectre/internal/logging"
 	"github.com/moolen/spectre/internal/models"
 	corev1 "k8s.io/api/core/v1"
@@ -34,15 +36,19 @@ type EventCaptureHandler struct {
 	auditLog      AuditLogWriter // Optional audit log
 	logger        *logging.Logger
 	pruner        *ManagedFieldsPruner
+	redactor      atomic.Pointer[Redactor] // Nil means redaction is disabled
+	cluster       string                   // Cluster name events are tagged with, empty for single-cluster setups
 }
 
 // NewEventCaptureHandler creates a new event capture handler (graph-only mode)
 func NewEventCaptureHandler(graphPipeline GraphPipeline) *EventCaptureHandler {
-	return &EventCaptureHandler{
+	h := &EventCaptureHandler{
 		graphPipeline: graphPipeline,
 		logger:        logging.GetLogger("event_handler"),
 		pruner:        NewManagedFieldsPruner(),
 	}
+	h.setDefaultRedactor()
+	return h
 }
 
 // SetAuditLog sets the audit log writer for the handler
@@ -50,15 +56,41 @@ func (h *EventCaptureHandler) SetAuditLog(writer AuditLogWriter) {
 	h.auditLog = writer
 }
 
+// SetCluster sets the name of the cluster this handler observes. Captured
+// events are tagged with it and their UIDs are qualified with the cluster name.
+func (h *EventCaptureHandler) SetCluster(cluster string) {
+	h.cluster = cluster
+}
+
 // NewEventCaptureHandlerWithMode creates an event handler with specified mode (graph-only now)
 func NewEventCaptureHandlerWithMode(storage interface{}, graphPipeline GraphPipeline, mode TimelineMode) *EventCaptureHandler {
 	// storage parameter is ignored - kept for signature compatibility
 	// mode must be TimelineModeGraph
-	return &EventCaptureHandler{
+	h := &EventCaptureHandler{
 		graphPipeline: graphPipeline,
 		logger:        logging.GetLogger("event_handler"),
 		pruner:        NewManagedFieldsPruner(),
 	}
+	h.setDefaultRedactor()
+	return h
+}
+
+// SetRedactor replaces the redactor used for captured objects.
+// Passing nil disables redaction. Safe to call while events are being handled.
+func (h *EventCaptureHandler) SetRedactor(redactor *Redactor) {
+	h.redactor.Store(redactor)
+}
+
+// setDefaultRedactor installs the built-in redaction rules so that sensitive
+// data is scrubbed even before the watcher config has been loaded
+func (h *EventCaptureHandler) setDefaultRedactor() {
+	redactor, err := NewRedactor(config.RedactionConfig{})
+	if err != nil {
+		// Built-in rules are static, so this only happens on a programming error
+		h.logger.Error("Failed to create default redactor: %v", err)
+		return
+	}
+	h.redactor.Store(redactor)
 }
 
 // OnAdd handles resource creation events
@@ -70,7 +102,7 @@ func (h *EventCaptureHandler) OnAdd(obj runtime.Object) error {
 	}
 
 	// Convert object to JSON and prune managedFields
-	data, dataSize, err := h.objectToJSON(obj)
+	data, dataSize, managers, err := h.objectToJSON(obj)
 	if err != nil {
 		h.logger.Error("Failed to convert object to JSON: %v", err)
 		return err
@@ -78,12 +110,13 @@ func (h *EventCaptureHandler) OnAdd(obj runtime.Object) error {
 
 	// Create event
 	event := &models.Event{
-		ID:        uuid.New().String(),
-		Timestamp: time.Now().UnixNano(),
-		Type:      models.EventTypeCreate,
-		Resource:  metadata,
-		Data:      data,
-		DataSize:  dataSize,
+		ID:            uuid.New().String(),
+		Timestamp:     time.Now().UnixNano(),
+		Type:          models.EventTypeCreate,
+		Resource:      metadata,
+		Data:          data,
+		DataSize:      dataSize,
+		FieldManagers: managers,
 	}
 
 	// Write based on mode
@@ -99,7 +132,7 @@ func (h *EventCaptureHandler) OnUpdate(oldObj, newObj runtime.Object) error {
 	}
 
 	// Convert object to JSON and prune managedFields
-	data, dataSize, err := h.objectToJSON(newObj)
+	data, dataSize, managers, err := h.objectToJSON(newObj)
 	if err != nil {
 		h.logger.Error("Failed to convert object to JSON: %v", err)
 		return err
@@ -107,12 +140,13 @@ func (h *EventCaptureHandler) OnUpdate(oldObj, newObj runtime.Object) error {
 
 	// Create event
 	event := &models.Event{
-		ID:        uuid.New().String(),
-		Timestamp: time.Now().UnixNano(),
-		Type:      models.EventTypeUpdate,
-		Resource:  metadata,
-		Data:      data,
-		DataSize:  dataSize,
+		ID:            uuid.New().String(),
+		Timestamp:     time.Now().UnixNano(),
+		Type:          models.EventTypeUpdate,
+		Resource:      metadata,
+		Data:          data,
+		DataSize:      dataSize,
+		FieldManagers: managers,
 	}
 
 	// Write based on mode
@@ -128,7 +162,7 @@ func (h *EventCaptureHandler) OnDelete(obj runtime.Object) error {
 	}
 
 	// For DELETE events, data can be nil or contain the last known state
-	data, dataSize, err := h.objectToJSON(obj)
+	data, dataSize, managers, err := h.objectToJSON(obj)
 	if err != nil {
 		h.logger.Error("Failed to convert object to JSON: %v", err)
 		return err
@@ -136,12 +170,13 @@ func (h *EventCaptureHandler) OnDelete(obj runtime.Object) error {
 
 	// Create event
 	event := &models.Event{
-		ID:        uuid.New().String(),
-		Timestamp: time.Now().UnixNano(),
-		Type:      models.EventTypeDelete,
-		Resource:  metadata,
-		Data:      data,
-		DataSize:  dataSize,
+		ID:            uuid.New().String(),
+		Timestamp:     time.Now().UnixNano(),
+		Type:          models.EventTypeDelete,
+		Resource:      metadata,
+		Data:          data,
+		DataSize:      dataSize,
+		FieldManagers: managers,
 	}
 
 	// Write based on mode
@@ -152,6 +187,10 @@ func (h *EventCaptureHandler) OnDelete(obj runtime.Object) error {
 func (h *EventCaptureHandler) writeEvent(event *models.Event) error {
 	ctx := context.Background() // Use background context for event processing
 
+	if h.cluster != "" {
+		event.Resource.SetCluster(h.cluster)
+	}
+
 	// Write to audit log FIRST (independent of graph mode)
 	if h.auditLog != nil {
 		if err := h.auditLog.WriteEvent(event); err != nil {
@@ -176,23 +215,37 @@ func (h *EventCaptureHandler) writeEvent(event *models.Event) error {
 }
 
 // objectToJSON converts a Kubernetes object to JSON, pruning managedFields
-func (h *EventCaptureHandler) objectToJSON(obj runtime.Object) (json.RawMessage, int32, error) {
+// and redacting sensitive values. The field managers summarized from
+// managedFields are returned alongside.
+func (h *EventCaptureHandler) objectToJSON(obj runtime.Object) (json.RawMessage, int32, map[string]string, error) {
 	// Marshal to JSON
 	jsonData, err := json.Marshal(obj)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to marshal object to JSON: %w", err)
+		return nil, 0, nil, fmt.Errorf("failed to marshal object to JSON: %w", err)
 	}
 
 	dataSize := int32(len(jsonData)) //nolint:gosec // safe conversion: data size is reasonable
 
-	// Prune managedFields to reduce size
-	jsonData, err = h.pruner.Prune(jsonData)
+	// Prune managedFields to reduce size, keeping who set which field
+	pruned, managers, err := h.pruner.PruneWithFieldManagers(jsonData)
 	if err != nil {
 		h.logger.Warn("Failed to prune managedFields: %v", err)
 		// Continue without pruning - don't fail the entire operation
+	} else {
+		jsonData = pruned
+	}
+
+	// Redact sensitive values. Unlike pruning, a redaction failure must not
+	// let the unredacted object through, so the event is dropped instead.
+	if redactor := h.redactor.Load(); redactor != nil {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		jsonData, err = redactor.Redact(gvk.Group, gvk.Version, gvk.Kind, jsonData)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to redact object: %w", err)
+		}
 	}
 
-	return json.RawMessage(jsonData), dataSize, nil
+	return json.RawMessage(jsonData), dataSize, managers, nil
 }
 
 // extractMetadata extracts resource metadata from a Kubernetes object
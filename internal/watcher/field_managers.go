@@ -0,0 +1,172 @@
+package watcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moolen/spectre/internal/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fieldOwner is a field in the managedFields trie together with the manager
+// that set it last
+type fieldOwner struct {
+	manager  string
+	time     time.Time
+	children map[string]*fieldOwner // keyed by path
+}
+
+// SummarizeFieldManagers condenses managedFields entries into a map from
+// field path to the manager that last set the field. Paths use the notation
+// of the JSON diff ("spec.template.spec.containers[name=app].image"). A
+// subtree whose fields all belong to the same manager is reported once, at
+// its root. Fields shared by several managers are attributed to the most
+// recent one.
+func SummarizeFieldManagers(entries []metav1.ManagedFieldsEntry) map[string]string {
+	root := &fieldOwner{}
+	for _, entry := range entries {
+		if entry.Manager == "" || entry.FieldsV1 == nil || len(entry.FieldsV1.Raw) == 0 {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		var at time.Time
+		if entry.Time != nil {
+			at = entry.Time.Time
+		}
+		root.add("", fields, entry.Manager, at)
+	}
+
+	if len(root.children) == 0 {
+		return nil
+	}
+	summary := make(map[string]string)
+	for path, child := range root.children {
+		child.summarize(path, summary)
+	}
+	return summary
+}
+
+// add records the fields of a FieldsV1 set below the node at prefix
+func (n *fieldOwner) add(prefix string, fields map[string]interface{}, manager string, at time.Time) {
+	for key, value := range fields {
+		if key == "." {
+			continue
+		}
+		path, ok := fieldPath(prefix, key)
+		if !ok {
+			// Set members (v:) make their list owned as a whole
+			n.own(manager, at)
+			continue
+		}
+
+		child := n.child(path)
+		nested, _ := value.(map[string]interface{})
+		if isLeaf(nested) {
+			child.own(manager, at)
+			continue
+		}
+		child.add(path, nested, manager, at)
+	}
+}
+
+func (n *fieldOwner) child(path string) *fieldOwner {
+	if n.children == nil {
+		n.children = make(map[string]*fieldOwner)
+	}
+	child, ok := n.children[path]
+	if !ok {
+		child = &fieldOwner{}
+		n.children[path] = child
+	}
+	return child
+}
+
+// own attributes the field to manager unless another manager set it later
+func (n *fieldOwner) own(manager string, at time.Time) {
+	if n.manager == "" || !at.Before(n.time) {
+		n.manager = manager
+		n.time = at
+	}
+}
+
+// owner returns the manager of every field in the subtree, or "" if the
+// subtree is shared by several managers
+func (n *fieldOwner) owner() string {
+	manager := n.manager
+	for _, child := range n.children {
+		childManager := child.owner()
+		if childManager == "" || (manager != "" && childManager != manager) {
+			return ""
+		}
+		manager = childManager
+	}
+	return manager
+}
+
+func (n *fieldOwner) summarize(path string, summary map[string]string) {
+	if manager := n.owner(); manager != "" {
+		summary[path] = manager
+		return
+	}
+	if n.manager != "" {
+		summary[path] = n.manager
+	}
+	for childPath, child := range n.children {
+		child.summarize(childPath, summary)
+	}
+}
+
+// isLeaf reports whether a FieldsV1 set holds no fields below its own
+func isLeaf(fields map[string]interface{}) bool {
+	for key := range fields {
+		if key != "." {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldPath converts a FieldsV1 key into the path of the field below prefix.
+// It returns false for set members, which have no path of their own.
+func fieldPath(prefix, key string) (string, bool) {
+	switch {
+	case strings.HasPrefix(key, "f:"):
+		if prefix == "" {
+			return key[2:], true
+		}
+		return prefix + "." + key[2:], true
+	case strings.HasPrefix(key, "k:"):
+		var listKey map[string]interface{}
+		if err := json.Unmarshal([]byte(key[2:]), &listKey); err != nil || len(listKey) == 0 {
+			return "", false
+		}
+		field := listKeyField(listKey)
+		return fmt.Sprintf("%s[%s=%v]", prefix, field, listKey[field]), true
+	case strings.HasPrefix(key, "i:"):
+		return fmt.Sprintf("%s[%s]", prefix, key[2:]), true
+	default:
+		return "", false
+	}
+}
+
+// listKeyField picks the field identifying a list element the way the JSON
+// diff does, so that summarized paths match diff paths
+func listKeyField(listKey map[string]interface{}) string {
+	for _, field := range models.ListKeyFields {
+		if _, ok := listKey[field]; ok {
+			return field
+		}
+	}
+	fields := make([]string, 0, len(listKey))
+	for field := range listKey {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields[0]
+}
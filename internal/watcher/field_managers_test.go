@@ -0,0 +1,94 @@
+package watcher
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPruneWithFieldManagers(t *testing.T) {
+	data := []byte(`{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {
+			"name": "api",
+			"labels": {"app": "api", "app.kubernetes.io/managed-by": "Helm"},
+			"managedFields": [
+				{
+					"manager": "helm", "operation": "Update", "apiVersion": "apps/v1",
+					"time": "2024-05-01T10:00:00Z", "fieldsType": "FieldsV1",
+					"fieldsV1": {
+						"f:metadata": {"f:labels": {".": {}, "f:app": {}, "f:app.kubernetes.io/managed-by": {}}},
+						"f:spec": {
+							"f:replicas": {},
+							"f:template": {"f:spec": {"f:containers": {
+								"k:{\"name\":\"app\"}": {".": {}, "f:name": {}, "f:image": {}, "f:ports": {
+									"k:{\"containerPort\":8080,\"protocol\":\"TCP\"}": {".": {}, "f:containerPort": {}}
+								}}
+							}}}
+						}
+					}
+				},
+				{
+					"manager": "kubectl-edit", "operation": "Update", "apiVersion": "apps/v1",
+					"time": "2024-05-02T09:30:00Z", "fieldsType": "FieldsV1",
+					"fieldsV1": {
+						"f:spec": {"f:template": {"f:spec": {"f:containers": {
+							"k:{\"name\":\"app\"}": {"f:image": {}}
+						}}}}
+					}
+				},
+				{
+					"manager": "kube-controller-manager", "operation": "Update", "apiVersion": "apps/v1",
+					"time": "2024-05-02T09:30:01Z", "fieldsType": "FieldsV1", "subresource": "status",
+					"fieldsV1": {"f:status": {"f:replicas": {}, "f:conditions": {".": {}, "k:{\"type\":\"Available\"}": {"f:status": {}}}}}
+				},
+				{
+					"manager": "finalizer-controller", "operation": "Update", "apiVersion": "apps/v1",
+					"time": "2024-05-01T10:00:00Z", "fieldsType": "FieldsV1",
+					"fieldsV1": {"f:metadata": {"f:finalizers": {".": {}, "v:\"example.com/cleanup\"": {}}}}
+				}
+			]
+		},
+		"spec": {"replicas": 2}
+	}`)
+
+	pruned, managers, err := NewManagedFieldsPruner().PruneWithFieldManagers(data)
+	if err != nil {
+		t.Fatalf("PruneWithFieldManagers() error = %v", err)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(pruned, &obj); err != nil {
+		t.Fatalf("failed to unmarshal pruned JSON: %v", err)
+	}
+	if _, ok := obj["metadata"].(map[string]interface{})["managedFields"]; ok {
+		t.Errorf("expected managedFields to be pruned")
+	}
+
+	expected := map[string]string{
+		"metadata.labels":     "helm",
+		"metadata.finalizers": "finalizer-controller",
+		"spec.replicas":       "helm",
+		"spec.template.spec.containers[name=app].name":  "helm",
+		"spec.template.spec.containers[name=app].image": "kubectl-edit",
+		"spec.template.spec.containers[name=app].ports": "helm",
+		"status": "kube-controller-manager",
+	}
+	if !reflect.DeepEqual(managers, expected) {
+		t.Errorf("field managers = %v, want %v", managers, expected)
+	}
+}
+
+func TestPruneWithFieldManagers_NoManagedFields(t *testing.T) {
+	pruned, managers, err := NewManagedFieldsPruner().PruneWithFieldManagers([]byte(`{"kind":"ConfigMap","metadata":{"name":"cm"}}`))
+	if err != nil {
+		t.Fatalf("PruneWithFieldManagers() error = %v", err)
+	}
+	if managers != nil {
+		t.Errorf("expected no field managers, got %v", managers)
+	}
+	if string(pruned) != `{"kind":"ConfigMap","metadata":{"name":"cm"}}` {
+		t.Errorf("unexpected pruned data %s", pruned)
+	}
+}
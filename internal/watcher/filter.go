@@ -0,0 +1,187 @@
+package watcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/moolen/spectre/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// resourceFilter holds the selection of one resource entry of the watcher config
+type resourceFilter struct {
+	namespace         string // empty matches all namespaces
+	labelSelector     labels.Selector
+	fieldSelector     fields.Selector
+	excludeNamespaces []*regexp.Regexp
+	excludeLabels     []labels.Selector
+}
+
+func newResourceFilter(resource config.Resource) (*resourceFilter, error) {
+	f := &resourceFilter{namespace: resource.Namespace}
+
+	var err error
+	if f.labelSelector, err = labels.Parse(resource.LabelSelector); err != nil {
+		return nil, fmt.Errorf("invalid labelSelector: %w", err)
+	}
+	if f.fieldSelector, err = fields.ParseSelector(resource.FieldSelector); err != nil {
+		return nil, fmt.Errorf("invalid fieldSelector: %w", err)
+	}
+	for _, pattern := range resource.ExcludeNamespaces {
+		re, err := config.NamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.excludeNamespaces = append(f.excludeNamespaces, re)
+	}
+	for _, selector := range resource.ExcludeLabels {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid excludeLabels selector: %w", err)
+		}
+		f.excludeLabels = append(f.excludeLabels, parsed)
+	}
+	return f, nil
+}
+
+// matches reports whether the filter selects the object. Label and field
+// selectors already applied by the API server are skipped.
+func (f *resourceFilter) matches(obj *unstructured.Unstructured, namespaced, serverLabels, serverFields bool) bool {
+	if namespaced {
+		namespace := obj.GetNamespace()
+		if f.namespace != "" && namespace != f.namespace {
+			return false
+		}
+		for _, re := range f.excludeNamespaces {
+			if re.MatchString(namespace) {
+				return false
+			}
+		}
+	}
+
+	objLabels := labels.Set(obj.GetLabels())
+	for _, selector := range f.excludeLabels {
+		if selector.Matches(objLabels) {
+			return false
+		}
+	}
+	if !serverLabels && !f.labelSelector.Matches(objLabels) {
+		return false
+	}
+	if !serverFields && !f.fieldSelector.Empty() && !f.fieldSelector.Matches(objectFields(obj, f.fieldSelector)) {
+		return false
+	}
+	return true
+}
+
+// objectFields collects the fields a selector refers to, e.g. "status.phase",
+// from the object
+func objectFields(obj *unstructured.Unstructured, selector fields.Selector) fields.Set {
+	set := fields.Set{}
+	for _, requirement := range selector.Requirements() {
+		value, found, err := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(requirement.Field, ".")...)
+		if err != nil || !found || value == nil {
+			set[requirement.Field] = ""
+			continue
+		}
+		set[requirement.Field] = fmt.Sprint(value)
+	}
+	return set
+}
+
+// watchFilter decides which objects of a watched GVR are captured. An object
+// is captured if any of the resource entries configured for the GVR selects
+// it. Selectors shared by all entries are passed to the List/Watch calls so
+// the API server filters them; everything else is applied client-side.
+type watchFilter struct {
+	filters []*resourceFilter
+
+	// labelSelector and fieldSelector are sent to the API server (empty if none)
+	labelSelector string
+	fieldSelector string
+
+	// fingerprint identifies the resource entries the filter was built from
+	fingerprint string
+}
+
+// newWatchFilter builds the filter of a GVR from its resource entries
+func newWatchFilter(resources []config.Resource) (*watchFilter, error) {
+	encoded, err := json.Marshal(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resources: %w", err)
+	}
+	wf := &watchFilter{fingerprint: hashContent(encoded)}
+	for i, resource := range resources {
+		filter, err := newResourceFilter(resource)
+		if err != nil {
+			return nil, err
+		}
+		wf.filters = append(wf.filters, filter)
+
+		if i == 0 {
+			wf.labelSelector = filter.labelSelector.String()
+			wf.fieldSelector = filter.fieldSelector.String()
+			continue
+		}
+		if filter.labelSelector.String() != wf.labelSelector {
+			wf.labelSelector = ""
+		}
+		if filter.fieldSelector.String() != wf.fieldSelector {
+			wf.fieldSelector = ""
+		}
+	}
+	return wf, nil
+}
+
+// matches reports whether the object is captured
+func (wf *watchFilter) matches(obj *unstructured.Unstructured, namespaced bool) bool {
+	if wf == nil || len(wf.filters) == 0 {
+		return true
+	}
+	for _, filter := range wf.filters {
+		if filter.matches(obj, namespaced, wf.labelSelector != "", wf.fieldSelector != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAll reports whether the object is captured, also checking the
+// selectors the API server applies. Used for objects that were not received
+// through the List/Watch calls, such as previously recorded state.
+func (wf *watchFilter) matchesAll(obj *unstructured.Unstructured, namespaced bool) bool {
+	if wf == nil || len(wf.filters) == 0 {
+		return true
+	}
+	for _, filter := range wf.filters {
+		if filter.matches(obj, namespaced, false, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// withClientFieldSelector returns a copy of the filter that applies the field
+// selector client-side, for resources whose API doesn't support selecting by
+// the given fields
+func (wf *watchFilter) withClientFieldSelector() *watchFilter {
+	copied := *wf
+	copied.fieldSelector = ""
+	return &copied
+}
+
+// listOptions returns the server-side selectors for List and Watch calls
+func (wf *watchFilter) listOptions() metav1.ListOptions {
+	if wf == nil {
+		return metav1.ListOptions{}
+	}
+	return metav1.ListOptions{
+		LabelSelector: wf.labelSelector,
+		FieldSelector: wf.fieldSelector,
+	}
+}
@@ -0,0 +1,102 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/moolen/spectre/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func filterObject(namespace string, labels map[string]interface{}, phase string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "pod",
+			"namespace": namespace,
+			"labels":    labels,
+		},
+		"status": map[string]interface{}{"phase": phase},
+	}}
+}
+
+func TestWatchFilter_Matches(t *testing.T) {
+	filter, err := newWatchFilter([]config.Resource{{
+		Version:           "v1",
+		Kind:              "Pod",
+		ExcludeNamespaces: []string{"kube-*", "/^team-.+-dev$/"},
+		ExcludeLabels:     []string{"spectre.dev/ignore=true"},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		expected bool
+	}{
+		{"plain namespace", filterObject("default", nil, "Running"), true},
+		{"glob excluded", filterObject("kube-system", nil, "Running"), false},
+		{"regex excluded", filterObject("team-a-dev", nil, "Running"), false},
+		{"regex not matching", filterObject("team-a-prod", nil, "Running"), true},
+		{"label excluded", filterObject("default", map[string]interface{}{"spectre.dev/ignore": "true"}, "Running"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filter.matches(tt.obj, true))
+		})
+	}
+
+	// Namespace excludes don't apply to cluster-scoped objects
+	assert.True(t, filter.matches(filterObject("kube-system", nil, ""), false))
+}
+
+func TestWatchFilter_ServerSideSelectors(t *testing.T) {
+	// Selectors shared by all entries of a GVR are sent to the API server
+	filter, err := newWatchFilter([]config.Resource{
+		{Version: "v1", Kind: "Pod", Namespace: "a", LabelSelector: "app=web", FieldSelector: "status.phase=Running"},
+		{Version: "v1", Kind: "Pod", Namespace: "b", LabelSelector: "app=web"},
+	})
+	require.NoError(t, err)
+
+	opts := filter.listOptions()
+	assert.Equal(t, "app=web", opts.LabelSelector)
+	assert.Empty(t, opts.FieldSelector)
+
+	web := map[string]interface{}{"app": "web"}
+	assert.True(t, filter.matches(filterObject("a", web, "Running"), true))
+	assert.False(t, filter.matches(filterObject("a", web, "Pending"), true))
+	assert.True(t, filter.matches(filterObject("b", web, "Pending"), true))
+	assert.False(t, filter.matches(filterObject("c", web, "Running"), true))
+}
+
+func TestWatchFilter_ClientFieldSelector(t *testing.T) {
+	filter, err := newWatchFilter([]config.Resource{
+		{Version: "v1", Kind: "Pod", FieldSelector: "status.phase!=Succeeded"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "status.phase!=Succeeded", filter.listOptions().FieldSelector)
+
+	// The server filtered, the object isn't checked again
+	assert.True(t, filter.matches(filterObject("default", nil, "Succeeded"), true))
+
+	client := filter.withClientFieldSelector()
+	assert.Empty(t, client.listOptions().FieldSelector)
+	assert.False(t, client.matches(filterObject("default", nil, "Succeeded"), true))
+	assert.True(t, client.matches(filterObject("default", nil, "Running"), true))
+	assert.Equal(t, "status.phase!=Succeeded", filter.listOptions().FieldSelector)
+}
+
+func TestWatchFilter_AllNamespacesEntry(t *testing.T) {
+	// An entry without namespace widens the watch to all namespaces
+	filter, err := newWatchFilter([]config.Resource{
+		{Version: "v1", Kind: "Pod", Namespace: "a"},
+		{Version: "v1", Kind: "Pod"},
+	})
+	require.NoError(t, err)
+	assert.True(t, filter.matches(filterObject("other", nil, ""), true))
+
+	var none *watchFilter
+	assert.True(t, none.matches(filterObject("other", nil, ""), true))
+}
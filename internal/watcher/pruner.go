@@ -3,6 +3,8 @@ package watcher
 import (
 	"encoding/json"
 	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ManagedFieldsPruner removes managedFields from Kubernetes resource objects
@@ -48,6 +50,47 @@ func (p *ManagedFieldsPruner) Prune(data []byte) ([]byte, error) {
 	return result, nil
 }
 
+// PruneWithFieldManagers removes managedFields from the JSON data like Prune,
+// and returns the summary of which manager set each field
+// (see SummarizeFieldManagers)
+func (p *ManagedFieldsPruner) PruneWithFieldManagers(data []byte) ([]byte, map[string]string, error) {
+	// Parse the JSON
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	var managers map[string]string
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		if managedFields, ok := metadata["managedFields"]; ok {
+			managers = p.summarize(managedFields)
+		}
+		delete(metadata, "managedFields")
+	}
+
+	// Marshal back to JSON
+	result, err := json.Marshal(obj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	return result, managers, nil
+}
+
+// summarize decodes the managedFields of an unstructured object. Malformed
+// entries only cost the summary, not the event.
+func (p *ManagedFieldsPruner) summarize(managedFields interface{}) map[string]string {
+	raw, err := json.Marshal(managedFields)
+	if err != nil {
+		return nil
+	}
+	var entries []metav1.ManagedFieldsEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil
+	}
+	return SummarizeFieldManagers(entries)
+}
+
 // PruneWithFields removes specified fields in addition to managedFields
 func (p *ManagedFieldsPruner) PruneWithFields(data []byte, fieldsToRemove []string) ([]byte, error) {
 	// Parse the JSON
@@ -0,0 +1,386 @@
+package watcher
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/moolen/spectre/internal/config"
+)
+
+// RedactedValuePrefix is prepended to every hashed value so that consumers
+// (UI, MCP tools, diffs) can recognise redacted content
+const RedactedValuePrefix = "redacted:sha256:"
+
+// redactedHashLength is the number of hex characters kept from the value hash.
+// 16 hex characters (64 bits) is plenty to detect changes between versions;
+// two values of a field collide with a chance of 1 in 2^64. The truncation
+// doesn't protect the values, the salt does: without it, low-entropy values can
+// be recovered by hashing candidates, with or without the full digest.
+const redactedHashLength = 16
+
+// processSalt is the random salt used when none is configured, generated
+// once so that reloading the config keeps the hashes
+var processSalt = sync.OnceValue(func() []byte {
+	return []byte(rand.Text())
+})
+
+// lastAppliedConfigAnnotation holds a full copy of the object as last applied
+// by kubectl, including any Secret data, so it is redacted for every kind
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// defaultEnvNamePatterns match container env variable names whose values are
+// likely to be credentials
+var defaultEnvNamePatterns = []string{
+	`(?i)passw(or)?d`,
+	`(?i)secret`,
+	`(?i)token`,
+	`(?i)api[_-]?key`,
+	`(?i)access[_-]?key`,
+	`(?i)private[_-]?key`,
+	`(?i)credential`,
+	`(?i)auth`,
+}
+
+// defaultRedactionRules are applied unless DisableDefaults is set
+var defaultRedactionRules = []config.RedactionRule{
+	{Group: "", Kind: "Secret", Paths: []string{".data", ".stringData"}},
+	{Group: "*", Kind: "*", Paths: []string{".metadata.annotations['" + lastAppliedConfigAnnotation + "']"}},
+}
+
+// pathSegment is a single step of a parsed redaction path
+type pathSegment struct {
+	key      string
+	wildcard bool // [*] or .* - matches every element of an array or map
+}
+
+// redactionRule is a RedactionRule with pre-parsed paths
+type redactionRule struct {
+	group   string
+	version string
+	kind    string
+	paths   [][]pathSegment
+}
+
+// matches reports whether the rule applies to the given GVK
+func (r *redactionRule) matches(group, version, kind string) bool {
+	if r.group != "*" && r.group != group {
+		return false
+	}
+	if r.version != "" && r.version != version {
+		return false
+	}
+	return r.kind == "*" || r.kind == kind
+}
+
+// Redactor replaces sensitive values in Kubernetes objects with stable hashes.
+// Keys are kept so that diffs can still report "key X changed" without the
+// value ever being stored.
+type Redactor struct {
+	rules           []redactionRule
+	envNamePatterns []*regexp.Regexp
+	salt            []byte
+}
+
+// NewRedactor creates a redactor from the watcher redaction configuration.
+// Returns nil (no redaction) if redaction is disabled.
+func NewRedactor(cfg config.RedactionConfig) (*Redactor, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+
+	salt, err := redactionSalt(cfg)
+	if err != nil {
+		return nil, err
+	}
+	r := &Redactor{
+		salt: salt,
+	}
+
+	var rules []config.RedactionRule
+	if !cfg.DisableDefaults {
+		rules = append(rules, defaultRedactionRules...)
+	}
+	rules = append(rules, cfg.Rules...)
+
+	for i, rule := range rules {
+		compiled := redactionRule{
+			group:   rule.Group,
+			version: rule.Version,
+			kind:    rule.Kind,
+		}
+		for _, path := range rule.Paths {
+			segments, err := parseRedactionPath(path)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d (%s): %w", i, rule.Kind, err)
+			}
+			compiled.paths = append(compiled.paths, segments)
+		}
+		r.rules = append(r.rules, compiled)
+	}
+
+	patterns := cfg.EnvNamePatterns
+	if len(patterns) == 0 && !cfg.DisableDefaults {
+		patterns = defaultEnvNamePatterns
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid env name pattern %q: %w", pattern, err)
+		}
+		r.envNamePatterns = append(r.envNamePatterns, re)
+	}
+
+	return r, nil
+}
+
+// Redact applies all matching rules to the JSON object and returns the redacted JSON
+func (r *Redactor) Redact(group, version, kind string, data []byte) ([]byte, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	r.RedactObject(group, version, kind, obj)
+
+	result, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	return result, nil
+}
+
+// RedactObject applies all matching rules to a decoded object in place
+func (r *Redactor) RedactObject(group, version, kind string, obj map[string]interface{}) {
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.matches(group, version, kind) {
+			continue
+		}
+		for _, path := range rule.paths {
+			r.redactPath(obj, path)
+		}
+	}
+
+	if len(r.envNamePatterns) > 0 {
+		r.redactEnvValues(obj)
+	}
+}
+
+// redactionSalt returns the configured salt, the salt of the salt file or,
+// without either, the random salt of this process
+func redactionSalt(cfg config.RedactionConfig) ([]byte, error) {
+	switch {
+	case cfg.HashSalt != "":
+		return []byte(cfg.HashSalt), nil
+	case cfg.HashSaltFile != "":
+		return loadOrCreateSalt(cfg.HashSaltFile)
+	}
+	return processSalt(), nil
+}
+
+// loadOrCreateSalt reads the salt from the file, generating a random salt and
+// writing it to the file if it doesn't exist
+func loadOrCreateSalt(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		salt := strings.TrimSpace(string(data))
+		if salt == "" {
+			return nil, fmt.Errorf("hash salt file %s is empty", path)
+		}
+		return []byte(salt), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read hash salt file: %w", err)
+	}
+
+	salt := rand.Text()
+	if err := os.WriteFile(path, []byte(salt+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write hash salt file: %w", err)
+	}
+	return []byte(salt), nil
+}
+
+// HashValue returns the stable redacted representation of a value: the HMAC of
+// the value keyed with the salt, truncated to redactedHashLength hex characters
+func (r *Redactor) HashValue(value string) string {
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(value))
+	return RedactedValuePrefix + hex.EncodeToString(mac.Sum(nil))[:redactedHashLength]
+}
+
+// redactPath walks the path and redacts whatever it points at
+func (r *Redactor) redactPath(node interface{}, path []pathSegment) {
+	if len(path) == 0 {
+		return
+	}
+
+	segment := path[0]
+	last := len(path) == 1
+
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		if segment.wildcard {
+			for key, child := range typed {
+				if last {
+					typed[key] = r.redactValue(child)
+				} else {
+					r.redactPath(child, path[1:])
+				}
+			}
+			return
+		}
+		child, ok := typed[segment.key]
+		if !ok {
+			return
+		}
+		if last {
+			typed[segment.key] = r.redactValue(child)
+		} else {
+			r.redactPath(child, path[1:])
+		}
+	case []interface{}:
+		if !segment.wildcard {
+			return
+		}
+		for i, child := range typed {
+			if last {
+				typed[i] = r.redactValue(child)
+			} else {
+				r.redactPath(child, path[1:])
+			}
+		}
+	}
+}
+
+// redactValue hashes every leaf of the value, keeping map keys and array shape
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = r.redactValue(child)
+		}
+		return typed
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = r.redactValue(child)
+		}
+		return typed
+	case string:
+		if strings.HasPrefix(typed, RedactedValuePrefix) {
+			return typed
+		}
+		return r.HashValue(typed)
+	default:
+		// Numbers and booleans are hashed through their JSON representation
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return r.HashValue(fmt.Sprintf("%v", typed))
+		}
+		return r.HashValue(string(encoded))
+	}
+}
+
+// redactEnvValues finds every container "env" list in the object and redacts
+// the value of entries whose name matches one of the env name patterns.
+// Walking the whole object covers Pods, workload templates and CRDs alike.
+func (r *Redactor) redactEnvValues(node interface{}) {
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if key == "env" {
+				if entries, ok := child.([]interface{}); ok {
+					r.redactEnvEntries(entries)
+					continue
+				}
+			}
+			r.redactEnvValues(child)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			r.redactEnvValues(child)
+		}
+	}
+}
+
+func (r *Redactor) redactEnvEntries(entries []interface{}) {
+	for _, entry := range entries {
+		envVar, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := envVar["name"].(string)
+		if !ok {
+			continue
+		}
+		value, ok := envVar["value"]
+		if !ok {
+			continue
+		}
+		for _, re := range r.envNamePatterns {
+			if re.MatchString(name) {
+				envVar["value"] = r.redactValue(value)
+				break
+			}
+		}
+	}
+}
+
+// parseRedactionPath parses a simple JSONPath expression into segments.
+// Supported syntax: optional "$" root prefix, ".field", ".*", "[*]" and
+// "['dotted.key']" / "[\"dotted.key\"]" for keys containing dots.
+// Array indices are not supported.
+func parseRedactionPath(path string) ([]pathSegment, error) {
+	p := strings.TrimPrefix(path, "$")
+	if p == "" {
+		return nil, fmt.Errorf("path %q is empty", path)
+	}
+
+	var segments []pathSegment
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end == -1 {
+				end = len(p)
+			}
+			key := p[:end]
+			if key == "" {
+				return nil, fmt.Errorf("path %q has an empty field name", path)
+			}
+			segments = append(segments, pathSegment{key: key, wildcard: key == "*"})
+			p = p[end:]
+		case '[':
+			end := strings.Index(p, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("path %q has an unterminated '['", path)
+			}
+			inner := p[1:end]
+			p = p[end+1:]
+			switch {
+			case inner == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, pathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				return nil, fmt.Errorf("path %q: unsupported subscript [%s] (use [*] or ['key'])", path, inner)
+			}
+		default:
+			return nil, fmt.Errorf("path %q: unexpected character %q", path, p[0])
+		}
+	}
+
+	return segments, nil
+}
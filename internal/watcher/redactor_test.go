@@ -0,0 +1,260 @@
+package watcher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moolen/spectre/internal/config"
+)
+
+func redactJSON(t *testing.T, r *Redactor, group, version, kind, data string) map[string]interface{} {
+	t.Helper()
+	out, err := r.Redact(group, version, kind, []byte(data))
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(out, &obj); err != nil {
+		t.Fatalf("failed to unmarshal redacted JSON: %v", err)
+	}
+	return obj
+}
+
+func TestRedactor_SecretDefaults(t *testing.T) {
+	r, err := NewRedactor(config.RedactionConfig{})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+
+	obj := redactJSON(t, r, "", "v1", "Secret", `{
+		"kind": "Secret",
+		"metadata": {"name": "db", "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"data\":{\"password\":\"c2VjcmV0\"}}"}},
+		"type": "Opaque",
+		"data": {"password": "c2VjcmV0", "username": "YWRtaW4="},
+		"stringData": {"token": "plain"}
+	}`)
+
+	data := obj["data"].(map[string]interface{})
+	if len(data) != 2 {
+		t.Fatalf("expected data keys to be kept, got %v", data)
+	}
+	for key, value := range data {
+		if !strings.HasPrefix(value.(string), RedactedValuePrefix) {
+			t.Errorf("data[%s] = %v, want redacted value", key, value)
+		}
+	}
+	if token := obj["stringData"].(map[string]interface{})["token"].(string); !strings.HasPrefix(token, RedactedValuePrefix) {
+		t.Errorf("stringData.token = %v, want redacted value", token)
+	}
+	if obj["type"] != "Opaque" {
+		t.Errorf("type = %v, want Opaque (non-sensitive fields must be kept)", obj["type"])
+	}
+
+	annotations := obj["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+	if v := annotations[lastAppliedConfigAnnotation].(string); !strings.HasPrefix(v, RedactedValuePrefix) {
+		t.Errorf("last-applied-configuration = %v, want redacted value", v)
+	}
+}
+
+func TestRedactor_StableHashes(t *testing.T) {
+	r, _ := NewRedactor(config.RedactionConfig{})
+	secret := func(password string) string {
+		return `{"data": {"password": "` + password + `"}}`
+	}
+
+	first := redactJSON(t, r, "", "v1", "Secret", secret("a"))
+	again := redactJSON(t, r, "", "v1", "Secret", secret("a"))
+	changed := redactJSON(t, r, "", "v1", "Secret", secret("b"))
+
+	hashA := first["data"].(map[string]interface{})["password"]
+	if hashA != again["data"].(map[string]interface{})["password"] {
+		t.Errorf("expected identical values to produce identical hashes")
+	}
+	if hashA == changed["data"].(map[string]interface{})["password"] {
+		t.Errorf("expected different values to produce different hashes")
+	}
+
+	salted, _ := NewRedactor(config.RedactionConfig{HashSalt: "pepper"})
+	saltedObj := redactJSON(t, salted, "", "v1", "Secret", secret("a"))
+	if hashA == saltedObj["data"].(map[string]interface{})["password"] {
+		t.Errorf("expected salt to change the hash")
+	}
+}
+
+func TestRedactor_HashSalt(t *testing.T) {
+	unsalted := hmac.New(sha256.New, nil)
+	unsalted.Write([]byte("a"))
+	unsaltedHash := RedactedValuePrefix + hex.EncodeToString(unsalted.Sum(nil))[:redactedHashLength]
+
+	// Without a configured salt, a random salt is used for the whole process
+	r, _ := NewRedactor(config.RedactionConfig{})
+	again, _ := NewRedactor(config.RedactionConfig{})
+	if r.HashValue("a") == unsaltedHash {
+		t.Errorf("expected a random salt when none is configured")
+	}
+	if r.HashValue("a") != again.HashValue("a") {
+		t.Errorf("expected the random salt to be kept for the process")
+	}
+
+	// A salt file is generated once and reused
+	path := filepath.Join(t.TempDir(), "salt")
+	first, err := NewRedactor(config.RedactionConfig{HashSaltFile: path})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(data)) == "" {
+		t.Fatalf("expected a generated salt in %s, got %q (%v)", path, data, err)
+	}
+	second, err := NewRedactor(config.RedactionConfig{HashSaltFile: path})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+	if first.HashValue("a") != second.HashValue("a") {
+		t.Errorf("expected the persisted salt to be reused")
+	}
+	if first.HashValue("a") == r.HashValue("a") {
+		t.Errorf("expected the salt file to be used instead of the random salt")
+	}
+
+	// hashSalt takes precedence over the salt file
+	salted, _ := NewRedactor(config.RedactionConfig{HashSalt: "pepper", HashSaltFile: path})
+	pepper, _ := NewRedactor(config.RedactionConfig{HashSalt: "pepper"})
+	if salted.HashValue("a") != pepper.HashValue("a") {
+		t.Errorf("expected hashSalt to take precedence over hashSaltFile")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(empty, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRedactor(config.RedactionConfig{HashSaltFile: empty}); err == nil {
+		t.Errorf("expected an error for an empty salt file")
+	}
+}
+
+func TestRedactor_EnvValues(t *testing.T) {
+	r, _ := NewRedactor(config.RedactionConfig{})
+
+	obj := redactJSON(t, r, "apps", "v1", "Deployment", `{
+		"spec": {"template": {"spec": {"containers": [{
+			"name": "app",
+			"env": [
+				{"name": "DB_PASSWORD", "value": "hunter2"},
+				{"name": "LOG_LEVEL", "value": "debug"},
+				{"name": "API_TOKEN", "valueFrom": {"secretKeyRef": {"name": "s", "key": "k"}}}
+			]
+		}]}}}
+	}`)
+
+	containers := obj["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	env := containers[0].(map[string]interface{})["env"].([]interface{})
+
+	if v := env[0].(map[string]interface{})["value"].(string); !strings.HasPrefix(v, RedactedValuePrefix) {
+		t.Errorf("DB_PASSWORD value = %v, want redacted", v)
+	}
+	if v := env[1].(map[string]interface{})["value"].(string); v != "debug" {
+		t.Errorf("LOG_LEVEL value = %v, want unchanged", v)
+	}
+	if _, ok := env[2].(map[string]interface{})["valueFrom"]; !ok {
+		t.Errorf("valueFrom reference should be kept")
+	}
+}
+
+func TestRedactor_CustomRules(t *testing.T) {
+	r, err := NewRedactor(config.RedactionConfig{
+		DisableDefaults: true,
+		Rules: []config.RedactionRule{
+			{Group: "example.com", Kind: "Database", Paths: []string{"$.spec.users[*].password", ".spec.connection['dsn.url']"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+
+	obj := redactJSON(t, r, "example.com", "v1", "Database", `{
+		"spec": {
+			"users": [{"name": "a", "password": "x"}, {"name": "b", "password": "y"}],
+			"connection": {"dsn.url": "postgres://a:x@db", "port": 5432}
+		}
+	}`)
+
+	spec := obj["spec"].(map[string]interface{})
+	for _, u := range spec["users"].([]interface{}) {
+		user := u.(map[string]interface{})
+		if !strings.HasPrefix(user["password"].(string), RedactedValuePrefix) {
+			t.Errorf("user %v password not redacted", user["name"])
+		}
+	}
+	connection := spec["connection"].(map[string]interface{})
+	if !strings.HasPrefix(connection["dsn.url"].(string), RedactedValuePrefix) {
+		t.Errorf("dsn.url not redacted")
+	}
+	if connection["port"] != float64(5432) {
+		t.Errorf("port = %v, want unchanged", connection["port"])
+	}
+
+	// Defaults are disabled, so Secrets pass through untouched
+	secret := redactJSON(t, r, "", "v1", "Secret", `{"data": {"password": "c2VjcmV0"}}`)
+	if secret["data"].(map[string]interface{})["password"] != "c2VjcmV0" {
+		t.Errorf("expected Secret data to be untouched when defaults are disabled")
+	}
+}
+
+func TestRedactor_Disabled(t *testing.T) {
+	r, err := NewRedactor(config.RedactionConfig{Disabled: true})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+	if r != nil {
+		t.Errorf("expected nil redactor when redaction is disabled")
+	}
+}
+
+func TestParseRedactionPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []pathSegment
+		wantErr bool
+	}{
+		{path: ".data", want: []pathSegment{{key: "data"}}},
+		{path: "$.spec.containers[*].env", want: []pathSegment{{key: "spec"}, {key: "containers"}, {wildcard: true}, {key: "env"}}},
+		{path: ".metadata.annotations['a.b/c']", want: []pathSegment{{key: "metadata"}, {key: "annotations"}, {key: "a.b/c"}}},
+		{path: `.data["x.y"]`, want: []pathSegment{{key: "data"}, {key: "x.y"}}},
+		{path: ".data.*", want: []pathSegment{{key: "data"}, {key: "*", wildcard: true}}},
+		{path: "", wantErr: true},
+		{path: ".items[0]", wantErr: true},
+		{path: ".data['unterminated", wantErr: true},
+		{path: "..data", wantErr: true},
+		{path: "data", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parseRedactionPath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseRedactionPath(%q) expected error, got %v", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRedactionPath(%q) error = %v", tt.path, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseRedactionPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("segment %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
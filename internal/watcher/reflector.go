@@ -0,0 +1,329 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/moolen/spectre/internal/logging"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// StateSource returns the last recorded state of the objects of a kind, keyed
+// by Kubernetes UID. After a restart the watcher diffs its initial list
+// against it, so only objects that changed while Spectre was down are emitted.
+type StateSource interface {
+	LastKnownObjects(ctx context.Context, gvk schema.GroupVersionKind) (map[string]*unstructured.Unstructured, error)
+}
+
+// errWatcherStopped is returned when the watcher component shuts down
+var errWatcherStopped = errors.New("watcher stopped")
+
+// resourceReflector mirrors the objects of one GVR into the event handler,
+// the way a client-go reflector feeds an informer. It lists once, then keeps
+// a watch open from the last seen resourceVersion (kept current by bookmarks)
+// and only relists when that resourceVersion expired. Relists are diffed
+// against the known objects so that only actual changes are emitted.
+type resourceReflector struct {
+	client      dynamic.ResourceInterface
+	gvr         schema.GroupVersionResource
+	gvk         schema.GroupVersionKind
+	namespaced  bool
+	filter      *watchFilter
+	handler     EventHandler
+	checkpoints *CheckpointStore
+	state       StateSource // optional
+	logger      *logging.Logger
+	stopChan    <-chan struct{}
+
+	// key identifies the GVR in the checkpoint store
+	key string
+
+	// objects holds the last seen state of every captured object by UID.
+	// It is complete after a list; after resuming a watch it only holds the
+	// objects changed since.
+	objects         map[string]*unstructured.Unstructured
+	complete        bool
+	resourceVersion string
+}
+
+// run lists and watches until the context is cancelled or the watcher stops
+func (r *resourceReflector) run(ctx context.Context) error {
+	if checkpoint, ok := r.checkpoints.Get(r.key); ok && checkpoint.Filter == r.filter.fingerprint {
+		r.logger.Info("Resuming watch for %s from resourceVersion %s", r.gvr.String(), checkpoint.ResourceVersion)
+		r.resourceVersion = checkpoint.ResourceVersion
+	}
+
+	for {
+		if err := r.checkStopped(ctx); err != nil {
+			return err
+		}
+
+		if r.resourceVersion == "" {
+			if err := r.list(ctx); err != nil {
+				if stopErr := r.checkStopped(ctx); stopErr != nil {
+					return stopErr
+				}
+				r.logger.Error("Failed to list resources %s: %v, retrying in 5s", r.gvr.String(), err)
+				r.sleep(ctx, 5*time.Second)
+				continue
+			}
+		}
+
+		err := r.watch(ctx)
+		if stopErr := r.checkStopped(ctx); stopErr != nil {
+			return stopErr
+		}
+		switch {
+		case err == nil:
+			// The server closed the watch, continue from the last resourceVersion
+			r.logger.Debug("Watch channel closed for %s, restarting", r.gvr.String())
+			r.sleep(ctx, time.Second)
+		case apierrors.IsResourceExpired(err) || apierrors.IsGone(err):
+			r.logger.Info("resourceVersion %s of %s expired, relisting", r.resourceVersion, r.gvr.String())
+			r.resourceVersion = ""
+		case apierrors.IsBadRequest(err) && r.filter.listOptions().FieldSelector != "":
+			r.useClientFieldSelector(err)
+		default:
+			r.logger.Error("Watch error for %s: %v, retrying in 5s", r.gvr.String(), err)
+			r.sleep(ctx, 5*time.Second)
+		}
+	}
+}
+
+// list fetches all objects and emits the differences to the known objects
+func (r *resourceReflector) list(ctx context.Context) error {
+	listed := make(map[string]*unstructured.Unstructured)
+
+	opts := r.filter.listOptions()
+	opts.Limit = 500 // Use pagination for large lists
+	for {
+		list, err := r.client.List(ctx, opts)
+		if err != nil && opts.FieldSelector != "" && apierrors.IsBadRequest(err) {
+			r.useClientFieldSelector(err)
+			return r.list(ctx)
+		}
+		if err != nil {
+			return err
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !r.filter.matches(obj, r.namespaced) {
+				continue
+			}
+			// Set GVK on the unstructured object (required for extractors to match resources)
+			obj.SetGroupVersionKind(r.gvk)
+			listed[string(obj.GetUID())] = obj
+		}
+
+		if list.GetContinue() == "" {
+			r.replace(ctx, listed)
+			r.setResourceVersion(list.GetResourceVersion())
+			return nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
+
+// replace emits creates, updates and deletes for the differences between the
+// listed objects and the known ones, then makes the listed objects the known
+// state. Objects with an unchanged resourceVersion are not emitted again.
+func (r *resourceReflector) replace(ctx context.Context, listed map[string]*unstructured.Unstructured) {
+	known := r.objects
+	if !r.complete {
+		known = r.recordedObjects(ctx)
+	}
+
+	created, updated, deleted := 0, 0, 0
+	for uid, obj := range listed {
+		previous, exists := known[uid]
+		switch {
+		case !exists:
+			created++
+			if err := r.handler.OnAdd(obj); err != nil {
+				r.logger.Error("Error handling Add event: %v", err)
+			}
+		case previous.GetResourceVersion() == "" || previous.GetResourceVersion() == obj.GetResourceVersion():
+			// Unchanged, or recorded without its state so changes can't be told
+		default:
+			updated++
+			if err := r.handler.OnUpdate(previous, obj); err != nil {
+				r.logger.Error("Error handling Update event: %v", err)
+			}
+		}
+	}
+	for uid, previous := range known {
+		if _, exists := listed[uid]; exists || !r.filter.matchesAll(previous, r.namespaced) {
+			continue
+		}
+		deleted++
+		if err := r.handler.OnDelete(previous); err != nil {
+			r.logger.Error("Error handling Delete event: %v", err)
+		}
+	}
+
+	r.objects = listed
+	r.complete = true
+	r.logger.Info("Listed %d %s: %d created, %d updated, %d deleted since last seen",
+		len(listed), r.gvr.String(), created, updated, deleted)
+}
+
+// recordedObjects returns the objects known before the first list: the
+// recorded state, updated with the objects seen since a resumed watch
+func (r *resourceReflector) recordedObjects(ctx context.Context) map[string]*unstructured.Unstructured {
+	known := make(map[string]*unstructured.Unstructured)
+	if r.state != nil {
+		recorded, err := r.state.LastKnownObjects(ctx, r.gvk)
+		if err != nil {
+			r.logger.Warn("Failed to read recorded state of %s, emitting all listed objects: %v", r.gvr.String(), err)
+		}
+		for uid, obj := range recorded {
+			known[uid] = obj
+		}
+	}
+	for uid, obj := range r.objects {
+		known[uid] = obj
+	}
+	return known
+}
+
+// watch processes watch events until the watch ends. It returns nil if the
+// server closed the watch and the watch error otherwise.
+func (r *resourceReflector) watch(ctx context.Context) error {
+	opts := r.filter.listOptions()
+	opts.ResourceVersion = r.resourceVersion
+	opts.AllowWatchBookmarks = true
+	watcher, err := r.client.Watch(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.stopChan:
+			return errWatcherStopped
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			if event.Type == watch.Error {
+				return apierrors.FromObject(event.Object)
+			}
+
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				r.logger.Warn("Received non-unstructured object in watch event")
+				continue
+			}
+			if event.Type != watch.Bookmark {
+				r.handle(event.Type, obj)
+			}
+			r.setResourceVersion(obj.GetResourceVersion())
+		}
+	}
+}
+
+// handle emits a watch event to the event handler and updates the known objects.
+// Filters applied client-side turn changes that move an object out of or into
+// the selection into deletes and adds, the way the API server does for its
+// selectors.
+func (r *resourceReflector) handle(eventType watch.EventType, obj *unstructured.Unstructured) {
+	uid := string(obj.GetUID())
+
+	// Filter by namespace and selectors
+	if !r.filter.matches(obj, r.namespaced) {
+		if _, known := r.objects[uid]; !known {
+			r.logger.Debug("Skipping event: %s %s/%s", eventType, obj.GetNamespace(), obj.GetName())
+			return
+		}
+		// The object was selected before and no longer is
+		obj.SetGroupVersionKind(r.gvk)
+		delete(r.objects, uid)
+		if err := r.handler.OnDelete(obj); err != nil {
+			r.logger.Error("Error handling Delete event: %v", err)
+		}
+		return
+	}
+
+	// Set GVK on the unstructured object (required for extractors to match resources)
+	obj.SetGroupVersionKind(r.gvk)
+
+	if _, known := r.objects[uid]; eventType == watch.Modified && !known && r.complete {
+		// The object wasn't selected before
+		eventType = watch.Added
+	}
+
+	switch eventType {
+	case watch.Added:
+		r.objects[uid] = obj
+		if err := r.handler.OnAdd(obj); err != nil {
+			r.logger.Error("Error handling Add event: %v", err)
+		}
+	case watch.Modified:
+		previous, exists := r.objects[uid]
+		if !exists {
+			previous = obj
+		}
+		r.objects[uid] = obj
+		if err := r.handler.OnUpdate(previous, obj); err != nil {
+			r.logger.Error("Error handling Update event: %v", err)
+		}
+	case watch.Deleted:
+		delete(r.objects, uid)
+		if err := r.handler.OnDelete(obj); err != nil {
+			r.logger.Error("Error handling Delete event: %v", err)
+		}
+	}
+}
+
+// setResourceVersion records the resourceVersion the watch continues from
+func (r *resourceReflector) setResourceVersion(resourceVersion string) {
+	if resourceVersion == "" {
+		return
+	}
+	r.resourceVersion = resourceVersion
+	r.checkpoints.Set(r.key, Checkpoint{
+		ResourceVersion: resourceVersion,
+		Filter:          r.filter.fingerprint,
+		UpdatedAt:       time.Now(),
+	})
+}
+
+// useClientFieldSelector switches to client-side field selection for
+// resources whose API doesn't support selecting by the configured fields
+func (r *resourceReflector) useClientFieldSelector(err error) {
+	r.logger.Warn("Field selector %q not supported by the API server for %s, filtering client-side: %v",
+		r.filter.listOptions().FieldSelector, r.gvr.String(), err)
+	r.filter = r.filter.withClientFieldSelector()
+	// Objects outside the selector may have been missed, relist
+	r.resourceVersion = ""
+}
+
+func (r *resourceReflector) checkStopped(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.stopChan:
+		return errWatcherStopped
+	default:
+		return nil
+	}
+}
+
+func (r *resourceReflector) sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-r.stopChan:
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,202 @@
+package watcher
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moolen/spectre/internal/config"
+	"github.com/moolen/spectre/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// recordingHandler records the emitted events as "<type>:<name>"
+type recordingHandler struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *recordingHandler) record(eventType string, obj runtime.Object) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, eventType+":"+obj.(*unstructured.Unstructured).GetName())
+}
+
+func (h *recordingHandler) OnAdd(obj runtime.Object) error { h.record("add", obj); return nil }
+func (h *recordingHandler) OnUpdate(_, obj runtime.Object) error {
+	h.record("update", obj)
+	return nil
+}
+func (h *recordingHandler) OnDelete(obj runtime.Object) error { h.record("delete", obj); return nil }
+
+func (h *recordingHandler) take() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	events := h.events
+	h.events = nil
+	sort.Strings(events)
+	return events
+}
+
+// staticState returns fixed recorded objects
+type staticState map[string]*unstructured.Unstructured
+
+func (s staticState) LastKnownObjects(context.Context, schema.GroupVersionKind) (map[string]*unstructured.Unstructured, error) {
+	return s, nil
+}
+
+func pod(name, resourceVersion string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(k8stypes.UID("uid-" + name))
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func newTestReflector(t *testing.T, state StateSource, objects ...runtime.Object) (*resourceReflector, *dynamicfake.FakeDynamicClient, *recordingHandler) {
+	t.Helper()
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podsGVR: "PodList"}, objects...)
+	filter, err := newWatchFilter([]config.Resource{{Version: "v1", Kind: "Pod"}})
+	require.NoError(t, err)
+	checkpoints, err := NewCheckpointStore("")
+	require.NoError(t, err)
+
+	handler := &recordingHandler{}
+	return &resourceReflector{
+		client:      client.Resource(podsGVR),
+		gvr:         podsGVR,
+		gvk:         schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		namespaced:  true,
+		filter:      filter,
+		handler:     handler,
+		checkpoints: checkpoints,
+		state:       state,
+		logger:      logging.GetLogger("watcher"),
+		stopChan:    make(chan struct{}),
+		key:         "/v1/pods",
+		objects:     make(map[string]*unstructured.Unstructured),
+	}, client, handler
+}
+
+func TestReflector_ListDiffsAgainstRecordedState(t *testing.T) {
+	state := staticState{
+		"uid-a": pod("a", "5"),
+		"uid-b": pod("b", "7"),
+		"uid-c": pod("c", "2"),
+	}
+	reflector, client, handler := newTestReflector(t, state, pod("a", "5"), pod("b", "9"), pod("d", "3"))
+	ctx := context.Background()
+
+	// Only the objects that changed since they were recorded are emitted
+	require.NoError(t, reflector.list(ctx))
+	assert.Equal(t, []string{"add:d", "delete:c", "update:b"}, handler.take())
+
+	// A relist diffs against the objects seen before
+	_, err := client.Resource(podsGVR).Namespace("default").Update(ctx, pod("a", "6"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, client.Resource(podsGVR).Namespace("default").Delete(ctx, "d", metav1.DeleteOptions{}))
+	require.NoError(t, reflector.list(ctx))
+	assert.Equal(t, []string{"delete:d", "update:a"}, handler.take())
+}
+
+func TestReflector_ResumesFromCheckpoint(t *testing.T) {
+	reflector, client, handler := newTestReflector(t, nil, pod("a", "5"))
+	reflector.checkpoints.Set(reflector.key, Checkpoint{ResourceVersion: "5", Filter: reflector.filter.fingerprint})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- reflector.run(ctx) }()
+
+	// Wait for the watch, then create an object
+	require.Eventually(t, func() bool {
+		for _, action := range client.Actions() {
+			if action.GetVerb() == "watch" {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err := client.Resource(podsGVR).Namespace("default").Create(ctx, pod("b", "6"), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		return len(handler.events) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	// Existing objects are not listed and re-emitted
+	assert.Equal(t, []string{"add:b"}, handler.take())
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "list", action.GetVerb())
+	}
+	checkpoint, ok := reflector.checkpoints.Get(reflector.key)
+	require.True(t, ok)
+	assert.Equal(t, "6", checkpoint.ResourceVersion)
+}
+
+func TestReflector_ClientSideFilterTransitions(t *testing.T) {
+	reflector, _, handler := newTestReflector(t, nil)
+	filter, err := newWatchFilter([]config.Resource{{Version: "v1", Kind: "Pod", FieldSelector: "status.phase!=Succeeded"}})
+	require.NoError(t, err)
+	reflector.filter = filter.withClientFieldSelector()
+
+	withPhase := func(obj *unstructured.Unstructured, phase string) *unstructured.Unstructured {
+		require.NoError(t, unstructured.SetNestedField(obj.Object, phase, "status", "phase"))
+		return obj
+	}
+
+	require.NoError(t, reflector.list(context.Background()))
+	reflector.handle(watch.Added, withPhase(pod("a", "1"), "Running"))
+	assert.Equal(t, []string{"add:a"}, handler.take())
+
+	// The pod stops matching: it is deleted from the captured objects
+	reflector.handle(watch.Modified, withPhase(pod("a", "2"), "Succeeded"))
+	assert.Equal(t, []string{"delete:a"}, handler.take())
+	assert.NotContains(t, reflector.objects, "uid-a")
+
+	// Further changes outside of the selection are skipped
+	reflector.handle(watch.Modified, withPhase(pod("a", "3"), "Succeeded"))
+	assert.Empty(t, handler.take())
+
+	// The pod matches again: it is added back
+	reflector.handle(watch.Modified, withPhase(pod("a", "4"), "Running"))
+	assert.Equal(t, []string{"add:a"}, handler.take())
+	assert.Contains(t, reflector.objects, "uid-a")
+}
+
+func TestCheckpointStore_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "checkpoints.json")
+
+	store, err := NewCheckpointStore(path)
+	require.NoError(t, err)
+	store.Set("/v1/pods", Checkpoint{ResourceVersion: "42", Filter: "f"})
+	require.NoError(t, store.Flush())
+
+	reloaded, err := NewCheckpointStore(path)
+	require.NoError(t, err)
+	checkpoint, ok := reloaded.Get("/v1/pods")
+	require.True(t, ok)
+	assert.Equal(t, "42", checkpoint.ResourceVersion)
+	assert.Equal(t, "f", checkpoint.Filter)
+}
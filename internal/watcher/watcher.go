@@ -11,25 +11,15 @@ import (
 
 	"github.com/moolen/spectre/internal/config"
 	"github.com/moolen/spectre/internal/logging"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-// pendingResource represents a resource that failed CRD discovery and needs retry
-type pendingResource struct {
-	Group     string
-	Version   string
-	Kind      string
-	Namespace string // Optional namespace filter
-}
-
 // Watcher monitors Kubernetes resources for changes
 type Watcher struct {
 	dynamicClient   dynamic.Interface
@@ -41,13 +31,25 @@ type Watcher struct {
 	logger          *logging.Logger
 	eventHandler    EventHandler
 	watchers        map[string]context.CancelFunc // Track active watchers by key
+	watchStatus     map[string]WatchStatus        // Describes the active watchers by key
 	watchersMutex   sync.RWMutex
-	// namespaceFilters maps GVR string to set of allowed namespaces (empty set means all namespaces)
-	namespaceFilters map[string]map[string]bool
-	namespaceMutex   sync.RWMutex
+
+	// loadMutex serializes (re)loads of the config with the periodic CRD discovery
+	loadMutex sync.Mutex
+	// discovery is the discovery config in auto discovery mode, nil otherwise.
+	// Written while holding both loadMutex and watchersMutex.
+	discovery *config.DiscoveryConfig
+	// watchFilters maps GVR string to the filter selecting the captured objects
+	watchFilters map[string]*watchFilter
+	filtersMutex sync.RWMutex
+
+	// checkpoints holds the last seen resourceVersion per GVR, stateSource
+	// the recorded state relists are diffed against after a restart
+	checkpoints *CheckpointStore
+	stateSource StateSource
 
 	// Pending resources that failed CRD discovery (CRD not installed yet)
-	pendingResources []pendingResource
+	pendingResources []config.Resource
 	pendingMutex     sync.RWMutex
 
 	// Readiness tracking
@@ -67,6 +69,12 @@ type EventHandler interface {
 	OnDelete(obj runtime.Object) error
 }
 
+// RedactorSetter is implemented by event handlers that redact captured objects.
+// The watcher uses it to push redaction settings on (re)load of the config.
+type RedactorSetter interface {
+	SetRedactor(redactor *Redactor)
+}
+
 // New creates a new Watcher instance
 func New(handler EventHandler, configPath string) (*Watcher, error) {
 	logger := logging.GetLogger("watcher")
@@ -103,14 +111,29 @@ func New(handler EventHandler, configPath string) (*Watcher, error) {
 		logger:              logger,
 		eventHandler:        handler,
 		watchers:            make(map[string]context.CancelFunc),
-		namespaceFilters:    make(map[string]map[string]bool),
+		watchStatus:         make(map[string]WatchStatus),
+		watchFilters:        make(map[string]*watchFilter),
 		initialLoadComplete: false,
 	}
+	w.checkpoints, _ = NewCheckpointStore("") // In-memory store, cannot fail
 
 	logger.Info("Watcher created successfully")
 	return w, nil
 }
 
+// SetCheckpointStore sets the store the last seen resourceVersions are kept
+// in. Must be called before Start.
+func (w *Watcher) SetCheckpointStore(store *CheckpointStore) {
+	w.checkpoints = store
+}
+
+// SetStateSource sets the source of the recorded object state. With it, the
+// initial list after a restart only emits objects that changed in the
+// meantime instead of re-creating every object. Must be called before Start.
+func (w *Watcher) SetStateSource(source StateSource) {
+	w.stateSource = source
+}
+
 // Start begins monitoring the configured resource types
 func (w *Watcher) Start(ctx context.Context) error {
 	w.logger.Info("Starting watchers from config file: %s", w.configPath)
@@ -123,6 +146,10 @@ func (w *Watcher) Start(ctx context.Context) error {
 	w.wg.Add(1)
 	go w.crdDiscoveryRetryLoop(ctx)
 
+	// Start the checkpoint goroutine
+	w.wg.Add(1)
+	go w.checkpointLoop(ctx)
+
 	// Initial load
 	if err := w.loadAndStartWatchers(ctx); err != nil {
 		return fmt.Errorf("failed to load initial watchers: %w", err)
@@ -157,6 +184,9 @@ func (w *Watcher) Stop(ctx context.Context) error {
 
 	select {
 	case <-done:
+		if err := w.checkpoints.Flush(); err != nil {
+			w.logger.Warn("Failed to persist watch checkpoints: %v", err)
+		}
 		w.logger.Info("Watcher component stopped")
 		return nil
 	case <-ctx.Done():
@@ -179,7 +209,25 @@ func (w *Watcher) loadAndStartWatchers(ctx context.Context) error {
 		return fmt.Errorf("failed to load watcher config: %w", err)
 	}
 
-	w.logger.Info("Loaded %d resource configurations", len(watcherConfig.Resources))
+	if watcherConfig.AutoDiscovery {
+		w.logger.Info("Loaded watcher config in auto discovery mode")
+	} else {
+		w.logger.Info("Loaded %d resource configurations", len(watcherConfig.Resources))
+	}
+
+	w.loadMutex.Lock()
+	defer w.loadMutex.Unlock()
+
+	// Apply redaction settings before any watcher (re)starts so that no object
+	// is captured with stale rules
+	if err := w.applyRedactionConfig(watcherConfig.Redaction); err != nil {
+		return fmt.Errorf("failed to apply redaction config: %w", err)
+	}
+
+	var discovery *config.DiscoveryConfig
+	if watcherConfig.AutoDiscovery {
+		discovery = &watcherConfig.Discovery
+	}
 
 	// Stop existing watchers
 	w.watchersMutex.Lock()
@@ -188,106 +236,148 @@ func (w *Watcher) loadAndStartWatchers(ctx context.Context) error {
 		cancel()
 		delete(w.watchers, key)
 	}
+	w.watchStatus = make(map[string]WatchStatus)
+	w.discovery = discovery
 	w.watchersMutex.Unlock()
 
-	// Clear namespace filters
-	w.namespaceMutex.Lock()
-	w.namespaceFilters = make(map[string]map[string]bool)
-	w.namespaceMutex.Unlock()
-
-	// Clear pending resources (will be repopulated if any fail)
+	// Clear watch filters
+	w.filtersMutex.Lock()
+	w.watchFilters = make(map[string]*watchFilter)
+	w.filtersMutex.Unlock()
+
+	var gvrMap map[string]*watchedGVR
+	var pending []config.Resource
+	if discovery != nil {
+		// Watch everything the API server serves, CRDs installed or removed
+		// later are picked up periodically
+		gvrMap, _, err = w.discoverResources(*discovery)
+		if err != nil {
+			return fmt.Errorf("failed to discover resources: %w", err)
+		}
+		w.logger.Info("Discovered %d watchable resources", len(gvrMap))
+	} else {
+		// Resolve GVRs, resources whose CRD is not installed yet are retried periodically
+		gvrMap, pending = w.resolveResources(watcherConfig.Resources)
+		for _, resource := range pending {
+			w.logger.Warn("Failed to resolve GVR for %s/%s/%s (will retry periodically)", resource.Group, resource.Version, resource.Kind)
+		}
+	}
 	w.pendingMutex.Lock()
-	w.pendingResources = nil
+	w.pendingResources = pending
 	w.pendingMutex.Unlock()
 
-	// Group resources by GVR
-	type gvrKey struct {
-		group    string
-		version  string
-		kind     string
-		resource string
-	}
-	type gvrInfo struct {
-		gvr        schema.GroupVersionResource
-		namespaced bool
-		namespaces map[string]bool // empty means all namespaces
-		kind       string
+	// Start one watcher per GVR
+	for key, info := range gvrMap {
+		w.startWatchedGVR(ctx, key, info)
 	}
 
-	gvrMap := make(map[gvrKey]*gvrInfo)
+	// Mark initial load as complete after starting all watchers
+	// we do not keep track of the individual watchers, because some CRDs might not exist yet
+	// this should not fail the initial load and affect the readiness of the watcher
+	w.readinessMutex.Lock()
+	w.initialLoadComplete = true
+	w.readinessMutex.Unlock()
+
+	return nil
+}
+
+// watchedGVR is a resolved GVR together with the resource entries of the
+// config that select its objects
+type watchedGVR struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+	kind       string
+	resources  []config.Resource
+}
 
-	// First pass: resolve all GVRs and collect namespace filters
-	for _, resource := range watcherConfig.Resources {
+// resolveResources resolves the GVRs of the configured resources and groups
+// the entries by GVR, keyed by "group/version/resource". Resources whose kind
+// is not served by the API server (yet) are returned as pending.
+func (w *Watcher) resolveResources(resources []config.Resource) (map[string]*watchedGVR, []config.Resource) {
+	gvrMap := make(map[string]*watchedGVR)
+	var pending []config.Resource
+
+	for _, resource := range resources {
 		gvr, namespaced, err := w.resolveGVR(schema.GroupVersionKind{
 			Group:   resource.Group,
 			Version: resource.Version,
 			Kind:    resource.Kind,
 		})
 		if err != nil {
-			w.logger.Warn("Failed to resolve GVR for %s/%s/%s: %v (will retry periodically)", resource.Group, resource.Version, resource.Kind, err)
-			// Add to pending resources for retry
-			w.pendingMutex.Lock()
-			w.pendingResources = append(w.pendingResources, pendingResource{
-				Group:     resource.Group,
-				Version:   resource.Version,
-				Kind:      resource.Kind,
-				Namespace: resource.Namespace,
-			})
-			w.pendingMutex.Unlock()
+			w.logger.Debug("Failed to resolve GVR for %s/%s/%s: %v", resource.Group, resource.Version, resource.Kind, err)
+			pending = append(pending, resource)
 			continue
 		}
 
-		key := gvrKey{
-			group:    gvr.Group,
-			version:  gvr.Version,
-			kind:     resource.Kind,
-			resource: gvr.Resource,
-		}
-
+		key := fmt.Sprintf("%s/%s/%s", gvr.Group, gvr.Version, gvr.Resource)
 		info, exists := gvrMap[key]
 		if !exists {
-			info = &gvrInfo{
+			info = &watchedGVR{
 				gvr:        gvr,
 				namespaced: namespaced,
-				namespaces: make(map[string]bool),
 				kind:       resource.Kind,
 			}
 			gvrMap[key] = info
 		}
+		info.resources = append(info.resources, resource)
+	}
 
-		// Add namespace filter if specified
-		if namespaced && resource.Namespace != "" {
-			info.namespaces[resource.Namespace] = true
-		}
-		// If namespace is empty for a namespaced resource, it means watch all namespaces
-		// We'll handle this by leaving namespaces map empty
+	return gvrMap, pending
+}
+
+// startWatchedGVR stores the filter of a GVR and starts its watcher
+func (w *Watcher) startWatchedGVR(ctx context.Context, key string, info *watchedGVR) {
+	filter, err := newWatchFilter(info.resources)
+	if err != nil {
+		w.logger.Error("Invalid filter for %s: %v", key, err)
+		return
 	}
 
-	// Second pass: start one watcher per GVR
-	for key, info := range gvrMap {
-		// Store namespace filters
-		gvrString := fmt.Sprintf("%s/%s/%s", key.group, key.version, key.resource)
-		w.namespaceMutex.Lock()
-		if len(info.namespaces) > 0 {
-			w.namespaceFilters[gvrString] = info.namespaces
-		} else {
-			// Empty map means watch all namespaces (for namespaced resources)
-			w.namespaceFilters[gvrString] = make(map[string]bool)
-		}
-		w.namespaceMutex.Unlock()
+	w.filtersMutex.Lock()
+	w.watchFilters[key] = filter
+	w.filtersMutex.Unlock()
 
-		// Start watcher for this GVR
-		if err := w.startGVRWatcher(ctx, info.gvr, info.namespaced, info.kind); err != nil {
-			w.logger.Error("Failed to start watcher for %s: %v", gvrString, err)
-		}
+	if err := w.startGVRWatcher(ctx, info.gvr, info.namespaced, info.kind); err != nil {
+		w.logger.Error("Failed to start watcher for %s: %v", key, err)
+		return
 	}
 
-	// Mark initial load as complete after starting all watchers
-	// we do not keep track of the individual watchers, because some CRDs might not exist yet
-	// this should not fail the initial load and affect the readiness of the watcher
-	w.readinessMutex.Lock()
-	w.initialLoadComplete = true
-	w.readinessMutex.Unlock()
+	w.watchersMutex.Lock()
+	w.watchStatus[key] = WatchStatus{
+		Group:         info.gvr.Group,
+		Version:       info.gvr.Version,
+		Resource:      info.gvr.Resource,
+		Kind:          info.kind,
+		Namespaced:    info.namespaced,
+		LabelSelector: filter.labelSelector,
+		FieldSelector: filter.fieldSelector,
+		Since:         time.Now(),
+	}
+	w.watchersMutex.Unlock()
+}
+
+// applyRedactionConfig builds a redactor from the config and hands it to the
+// event handler, if the handler supports redaction
+func (w *Watcher) applyRedactionConfig(cfg config.RedactionConfig) error {
+	setter, ok := w.eventHandler.(RedactorSetter)
+	if !ok {
+		return nil
+	}
+
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		return err
+	}
+
+	if redactor == nil {
+		w.logger.Warn("Redaction is disabled - sensitive values will be stored as captured")
+	} else {
+		if cfg.HashSalt == "" && cfg.HashSaltFile == "" {
+			w.logger.Warn("No redaction hashSalt or hashSaltFile configured - using a random salt, redacted values will hash differently after a restart")
+		}
+		w.logger.Info("Redaction enabled (%d rules, %d env name patterns)", len(redactor.rules), len(redactor.envNamePatterns))
+	}
+	setter.SetRedactor(redactor)
 
 	return nil
 }
@@ -363,7 +453,8 @@ func (w *Watcher) resolveGVR(gvk schema.GroupVersionKind) (schema.GroupVersionRe
 	return schema.GroupVersionResource{}, false, fmt.Errorf("resource kind %s not found in API group %s/%s", gvk.Kind, gvk.Group, gvk.Version)
 }
 
-// watchLoop performs a raw List/Watch loop for a resource without caching
+// watchLoop mirrors a resource into the event handler until the context is
+// cancelled, resuming from the last checkpointed resourceVersion if possible
 func (w *Watcher) watchLoop(ctx context.Context, gvr schema.GroupVersionResource, namespace, kind string, namespaced bool) error {
 	// Get the resource interface
 	// For namespaced resources watching all namespaces, use empty namespace
@@ -375,198 +466,55 @@ func (w *Watcher) watchLoop(ctx context.Context, gvr schema.GroupVersionResource
 		resourceInterface = w.dynamicClient.Resource(gvr).Namespace(namespace)
 	}
 
-	// Create GVK for setting on unstructured objects
-	// Unstructured objects from dynamic clients don't have GVK populated automatically
-	gvk := schema.GroupVersionKind{
-		Group:   gvr.Group,
-		Version: gvr.Version,
-		Kind:    kind,
-	}
-
-	// Get namespace filters for this GVR
+	// Get the filter for this GVR
 	gvrString := fmt.Sprintf("%s/%s/%s", gvr.Group, gvr.Version, gvr.Resource)
-	w.namespaceMutex.RLock()
-	allowedNamespaces := w.namespaceFilters[gvrString]
-	w.namespaceMutex.RUnlock()
-
-	// Helper function to check if a namespace is allowed
-	shouldProcess := func(objNamespace string) bool {
-		if !namespaced {
-			// Cluster-scoped resources are always processed
-			return true
-		}
-		// If allowedNamespaces is empty, watch all namespaces
-		if len(allowedNamespaces) == 0 {
-			return true
-		}
-		// Check if namespace is in the allowed set
-		return allowedNamespaces[objNamespace]
-	}
+	w.filtersMutex.RLock()
+	filter := w.watchFilters[gvrString]
+	w.filtersMutex.RUnlock()
+	if filter == nil {
+		filter = &watchFilter{}
+	}
+
+	reflector := &resourceReflector{
+		client: resourceInterface,
+		gvr:    gvr,
+		// Unstructured objects from dynamic clients don't have GVK populated automatically
+		gvk: schema.GroupVersionKind{
+			Group:   gvr.Group,
+			Version: gvr.Version,
+			Kind:    kind,
+		},
+		namespaced:  namespaced,
+		filter:      filter,
+		handler:     w.eventHandler,
+		checkpoints: w.checkpoints,
+		state:       w.stateSource,
+		logger:      w.logger,
+		stopChan:    w.stopChan,
+		key:         gvrString,
+		objects:     make(map[string]*unstructured.Unstructured),
+	}
+	return reflector.run(ctx)
+}
+
+// checkpointLoop periodically persists the checkpoints of the watches
+func (w *Watcher) checkpointLoop(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
 
-	// Retry loop for handling connection drops
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return
 		case <-w.stopChan:
-			return fmt.Errorf("watcher stopped")
-		default:
-		}
-
-		// Perform List to get initial state and resource version
-		list, err := resourceInterface.List(ctx, metav1.ListOptions{
-			Limit: 500, // Use pagination for large lists
-		})
-		if err != nil {
-			w.logger.Error("Failed to list resources %s: %v, retrying in 5s", gvr.String(), err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		// Process initial list as Add events
-		items := list.Items
-		for i := range items {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-w.stopChan:
-				return fmt.Errorf("watcher stopped")
-			default:
-			}
-
-			// Filter by namespace if needed
-			objNamespace := items[i].GetNamespace()
-			if !shouldProcess(objNamespace) {
-				continue
-			}
-
-			// Set GVK on the unstructured object (required for extractors to match resources)
-			items[i].SetGroupVersionKind(gvk)
-
-			if err := w.eventHandler.OnAdd(&items[i]); err != nil {
-				w.logger.Error("Error handling Add event: %v", err)
-			}
-		}
-
-		// Handle pagination if needed
-		resourceVersion := list.GetResourceVersion()
-		for list.GetContinue() != "" {
-			list, err = resourceInterface.List(ctx, metav1.ListOptions{
-				Limit:    500,
-				Continue: list.GetContinue(),
-			})
-			if err != nil {
-				w.logger.Error("Failed to list resources (pagination) %s: %v, retrying", gvr.String(), err)
-				break
-			}
-
-			items = list.Items
-			for i := range items {
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-w.stopChan:
-					return fmt.Errorf("watcher stopped")
-				default:
-				}
-
-				// Filter by namespace if needed
-				objNamespace := items[i].GetNamespace()
-				if !shouldProcess(objNamespace) {
-					continue
-				}
-
-				// Set GVK on the unstructured object (required for extractors to match resources)
-				items[i].SetGroupVersionKind(gvk)
-
-				if err := w.eventHandler.OnAdd(&items[i]); err != nil {
-					w.logger.Error("Error handling Add event: %v", err)
-				}
-			}
-
-			resourceVersion = list.GetResourceVersion()
-		}
-
-		// Start watching from the resource version
-		watcher, err := resourceInterface.Watch(ctx, metav1.ListOptions{
-			ResourceVersion: resourceVersion,
-		})
-		if err != nil {
-			w.logger.Error("Failed to start watch for %s: %v, retrying in 5s", gvr.String(), err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		// Process watch events
-		watchCh := watcher.ResultChan()
-		watchActive := true
-		for watchActive {
-			select {
-			case <-ctx.Done():
-				watcher.Stop()
-				return ctx.Err()
-			case <-w.stopChan:
-				watcher.Stop()
-				return fmt.Errorf("watcher stopped")
-			case event, ok := <-watchCh:
-				if !ok {
-					// Channel closed, restart watch
-					w.logger.Debug("Watch channel closed for %s, restarting", gvr.String())
-					watcher.Stop()
-					watchActive = false
-					break
-				}
-
-				if event.Type == watch.Error {
-					w.logger.Error("Watch error for %s: %v", gvr.String(), event.Object)
-					watcher.Stop()
-					watchActive = false
-					break
-				}
-
-				// Process the event
-				unstructuredObj, ok := event.Object.(*unstructured.Unstructured)
-				if !ok {
-					w.logger.Warn("Received non-unstructured object in watch event")
-					continue
-				}
-
-				// Filter by namespace if needed
-				objNamespace := unstructuredObj.GetNamespace()
-				if !shouldProcess(objNamespace) {
-					w.logger.Debug("Skipping event: %s %s/%s", event.Type, unstructuredObj.GetNamespace(), unstructuredObj.GetName())
-					continue
-				}
-
-				// Set GVK on the unstructured object (required for extractors to match resources)
-				unstructuredObj.SetGroupVersionKind(gvk)
-
-				switch event.Type {
-				case watch.Added:
-					if err := w.eventHandler.OnAdd(unstructuredObj); err != nil {
-						w.logger.Error("Error handling Add event: %v", err)
-					}
-				case watch.Modified:
-					// For Modified events, we need both old and new objects
-					// Since we don't cache, we'll pass the new object as both
-					// The event handler should handle this appropriately
-					if err := w.eventHandler.OnUpdate(unstructuredObj, unstructuredObj); err != nil {
-						w.logger.Error("Error handling Update event: %v", err)
-					}
-				case watch.Deleted:
-					if err := w.eventHandler.OnDelete(unstructuredObj); err != nil {
-						w.logger.Error("Error handling Delete event: %v", err)
-					}
-				case watch.Bookmark:
-					// Bookmark events are used for resync, we can ignore them
-				case watch.Error:
-					w.logger.Error("Watch error event received: %v", event.Object)
-				}
+			return
+		case <-ticker.C:
+			if err := w.checkpoints.Flush(); err != nil {
+				w.logger.Warn("Failed to persist watch checkpoints: %v", err)
 			}
 		}
-
-		// Small delay before restarting
-		time.Sleep(1 * time.Second)
 	}
 }
 
@@ -618,6 +566,7 @@ func (w *Watcher) hotReloadLoop(ctx context.Context) {
 // crdDiscoveryRetryLoop periodically retries starting watchers for resources
 // whose CRDs were not available at startup. This allows Spectre to start
 // watching resources like HelmRelease even if Flux is installed after Spectre.
+// In auto discovery mode it also starts and stops watchers as CRDs come and go.
 func (w *Watcher) crdDiscoveryRetryLoop(ctx context.Context) {
 	defer w.wg.Done()
 
@@ -633,12 +582,16 @@ func (w *Watcher) crdDiscoveryRetryLoop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			w.retryPendingResources(ctx)
+			w.syncDiscoveredResources(ctx)
 		}
 	}
 }
 
 // retryPendingResources attempts to resolve and start watchers for pending resources
 func (w *Watcher) retryPendingResources(ctx context.Context) {
+	w.loadMutex.Lock()
+	defer w.loadMutex.Unlock()
+
 	w.pendingMutex.Lock()
 	if len(w.pendingResources) == 0 {
 		w.pendingMutex.Unlock()
@@ -646,67 +599,14 @@ func (w *Watcher) retryPendingResources(ctx context.Context) {
 	}
 
 	// Copy pending resources and clear the list
-	pending := make([]pendingResource, len(w.pendingResources))
+	pending := make([]config.Resource, len(w.pendingResources))
 	copy(pending, w.pendingResources)
 	w.pendingResources = nil
 	w.pendingMutex.Unlock()
 
 	w.logger.Info("Retrying CRD discovery for %d pending resources", len(pending))
 
-	// Group resources by GVR (same logic as loadAndStartWatchers)
-	type gvrKey struct {
-		group    string
-		version  string
-		kind     string
-		resource string
-	}
-	type gvrInfo struct {
-		gvr        schema.GroupVersionResource
-		namespaced bool
-		namespaces map[string]bool
-		kind       string
-	}
-
-	gvrMap := make(map[gvrKey]*gvrInfo)
-	stillPending := []pendingResource{}
-
-	for _, resource := range pending {
-		gvr, namespaced, err := w.resolveGVR(schema.GroupVersionKind{
-			Group:   resource.Group,
-			Version: resource.Version,
-			Kind:    resource.Kind,
-		})
-		if err != nil {
-			// Still not available, add back to pending
-			stillPending = append(stillPending, resource)
-			continue
-		}
-
-		w.logger.Info("CRD now available: %s/%s/%s", resource.Group, resource.Version, resource.Kind)
-
-		key := gvrKey{
-			group:    gvr.Group,
-			version:  gvr.Version,
-			kind:     resource.Kind,
-			resource: gvr.Resource,
-		}
-
-		info, exists := gvrMap[key]
-		if !exists {
-			info = &gvrInfo{
-				gvr:        gvr,
-				namespaced: namespaced,
-				namespaces: make(map[string]bool),
-				kind:       resource.Kind,
-			}
-			gvrMap[key] = info
-		}
-
-		// Add namespace filter if specified
-		if namespaced && resource.Namespace != "" {
-			info.namespaces[resource.Namespace] = true
-		}
-	}
+	gvrMap, stillPending := w.resolveResources(pending)
 
 	// Re-add still-pending resources
 	if len(stillPending) > 0 {
@@ -718,33 +618,20 @@ func (w *Watcher) retryPendingResources(ctx context.Context) {
 
 	// Start watchers for newly available resources
 	for key, info := range gvrMap {
+		w.logger.Info("CRD now available: %s", key)
+
 		// Check if watcher already exists
-		watcherKey := fmt.Sprintf("%s/%s/%s", key.group, key.version, key.resource)
 		w.watchersMutex.RLock()
-		_, exists := w.watchers[watcherKey]
+		_, exists := w.watchers[key]
 		w.watchersMutex.RUnlock()
 
 		if exists {
-			w.logger.Debug("Watcher already exists for %s, skipping", watcherKey)
+			w.logger.Debug("Watcher already exists for %s, skipping", key)
 			continue
 		}
 
-		// Store namespace filters
-		gvrString := fmt.Sprintf("%s/%s/%s", key.group, key.version, key.resource)
-		w.namespaceMutex.Lock()
-		if len(info.namespaces) > 0 {
-			w.namespaceFilters[gvrString] = info.namespaces
-		} else {
-			w.namespaceFilters[gvrString] = make(map[string]bool)
-		}
-		w.namespaceMutex.Unlock()
-
-		// Start watcher for this GVR
-		if err := w.startGVRWatcher(ctx, info.gvr, info.namespaced, info.kind); err != nil {
-			w.logger.Error("Failed to start watcher for %s: %v", gvrString, err)
-		} else {
-			w.logger.Info("Started watcher for newly available CRD: %s", gvrString)
-		}
+		w.startWatchedGVR(ctx, key, info)
+		w.logger.Info("Started watcher for newly available CRD: %s", key)
 	}
 }
 
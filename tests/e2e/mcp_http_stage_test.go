@@ -154,9 +154,9 @@ func (s *MCPHTTPStage) tools_are_listed() *MCPHTTPStage {
 
 func (s *MCPHTTPStage) four_tools_are_available() *MCPHTTPStage {
 	s.Require.NotNil(s.tools, "tools must be listed first")
-	// Should have 5 tools (base tools including causal_paths)
+	// Should have 6 tools (base tools including causal_paths and search_resources)
 	toolCount := len(s.tools)
-	s.Assert.Equal(5, toolCount, "should have 5 tools, got %d", toolCount)
+	s.Assert.Equal(6, toolCount, "should have 6 tools, got %d", toolCount)
 	s.T.Logf("Available tools count: %d", toolCount)
 	return s
 }
@@ -169,8 +169,13 @@ func (s *MCPHTTPStage) expected_tools_are_present() *MCPHTTPStage {
 		"cluster_health":            false,
 		"resource_timeline_changes": false,
 		"resource_timeline":         false,
+		"search_resources":          false,
 		"detect_anomalies":          false,
+		"find_resources":            false,
 		"causal_paths":              false,
+		"blast_radius":              false,
+		"graph_query":               false,
+		"cluster_snapshot":          false,
 	}
 
 	for _, tool := range s.tools {
@@ -0,0 +1,22 @@
+//go:build integration
+// +build integration
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/moolen/spectre/internal/graph"
+	"github.com/moolen/spectre/internal/graph/graphtest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFalkorDBConformance runs the backend conformance suite against FalkorDB.
+// The in-memory backend runs the same suite in internal/graph.
+func TestFalkorDBConformance(t *testing.T) {
+	graphtest.RunConformance(t, func(t *testing.T) graph.Client {
+		harness, err := NewTestHarness(t)
+		require.NoError(t, err)
+		return harness.GetClient()
+	})
+}
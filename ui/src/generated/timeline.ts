@@ -27,6 +27,10 @@ export interface TimelineRequest {
   /** Pagination parameters */
   pageSize: number;
   cursor: string;
+  /** Multiple cluster filter (multi-cluster deployments) */
+  clusters: string[];
+  /** Spectre query language expression, e.g. "kind:Pod status:Error" */
+  query: string;
 }
 
 /** TimelineMetadata sent first in stream */
@@ -79,6 +83,8 @@ export interface TimelineResource {
   statusSegments: StatusSegment[];
   events: K8sEvent[];
   preExisting: boolean;
+  /** Cluster the resource lives in (empty for single-cluster deployments) */
+  cluster: string;
 }
 
 export interface TimelineResource_LabelsEntry {
@@ -90,6 +96,8 @@ export interface TimelineResource_LabelsEntry {
 export interface TimelineChunk {
   metadata?: TimelineMetadata | undefined;
   batch?: ResourceBatch | undefined;
+  /** Live update, only sent by WatchTimeline */
+  delta?: TimelineDelta | undefined;
 }
 
 /** ResourceBatch contains grouped resources */
@@ -101,8 +109,18 @@ export interface ResourceBatch {
   isFinalBatch: boolean;
 }
 
+/**
+ * TimelineDelta contains the status segments and events added to resources
+ * after the snapshot. Resources only carry the new segments and events.
+ */
+export interface TimelineDelta {
+  resources: TimelineResource[];
+  /** Newest change in the delta (Unix nanoseconds) */
+  timestamp: number;
+}
+
 function createBaseTimelineRequest(): TimelineRequest {
-  return { startTimestamp: 0, endTimestamp: 0, namespace: "", kind: "", name: "", labelSelector: "", namespaces: [], kinds: [], pageSize: 0, cursor: "" };
+  return { startTimestamp: 0, endTimestamp: 0, namespace: "", kind: "", name: "", labelSelector: "", namespaces: [], kinds: [], pageSize: 0, cursor: "", clusters: [], query: "" };
 }
 
 export const TimelineRequest: MessageFns<TimelineRequest> = {
@@ -137,6 +155,12 @@ export const TimelineRequest: MessageFns<TimelineRequest> = {
     if (message.cursor !== "") {
       writer.uint32(82).string(message.cursor);
     }
+    for (const v of message.clusters) {
+      writer.uint32(90).string(v);
+    }
+    if (message.query !== "") {
+      writer.uint32(98).string(message.query);
+    }
     return writer;
   },
 
@@ -227,6 +251,22 @@ export const TimelineRequest: MessageFns<TimelineRequest> = {
           message.cursor = reader.string();
           continue;
         }
+        case 11: {
+          if (tag !== 90) {
+            break;
+          }
+
+          message.clusters.push(reader.string());
+          continue;
+        }
+        case 12: {
+          if (tag !== 98) {
+            break;
+          }
+
+          message.query = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -248,6 +288,8 @@ export const TimelineRequest: MessageFns<TimelineRequest> = {
       kinds: globalThis.Array.isArray(object?.kinds) ? object.kinds.map((e: any) => globalThis.String(e)) : [],
       pageSize: isSet(object.pageSize) ? globalThis.Number(object.pageSize) : 0,
       cursor: isSet(object.cursor) ? globalThis.String(object.cursor) : "",
+      clusters: globalThis.Array.isArray(object?.clusters) ? object.clusters.map((e: any) => globalThis.String(e)) : [],
+      query: isSet(object.query) ? globalThis.String(object.query) : "",
     };
   },
 
@@ -283,6 +325,12 @@ export const TimelineRequest: MessageFns<TimelineRequest> = {
     if (message.cursor !== "") {
       obj.cursor = message.cursor;
     }
+    if (message.clusters?.length) {
+      obj.clusters = message.clusters;
+    }
+    if (message.query !== "") {
+      obj.query = message.query;
+    }
     return obj;
   },
 
@@ -301,6 +349,8 @@ export const TimelineRequest: MessageFns<TimelineRequest> = {
     message.kinds = object.kinds?.map((e) => e) || [];
     message.pageSize = object.pageSize ?? 0;
     message.cursor = object.cursor ?? "";
+    message.clusters = object.clusters?.map((e) => e) || [];
+    message.query = object.query ?? "";
     return message;
   },
 };
@@ -828,6 +878,7 @@ function createBaseTimelineResource(): TimelineResource {
     statusSegments: [],
     events: [],
     preExisting: false,
+    cluster: "",
   };
 }
 
@@ -866,6 +917,9 @@ export const TimelineResource: MessageFns<TimelineResource> = {
     if (message.preExisting !== false) {
       writer.uint32(88).bool(message.preExisting);
     }
+    if (message.cluster !== "") {
+      writer.uint32(98).string(message.cluster);
+    }
     return writer;
   },
 
@@ -967,6 +1021,14 @@ export const TimelineResource: MessageFns<TimelineResource> = {
           message.preExisting = reader.bool();
           continue;
         }
+        case 12: {
+          if (tag !== 98) {
+            break;
+          }
+
+          message.cluster = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -996,6 +1058,7 @@ export const TimelineResource: MessageFns<TimelineResource> = {
         : [],
       events: globalThis.Array.isArray(object?.events) ? object.events.map((e: any) => K8sEvent.fromJSON(e)) : [],
       preExisting: isSet(object.preExisting) ? globalThis.Boolean(object.preExisting) : false,
+      cluster: isSet(object.cluster) ? globalThis.String(object.cluster) : "",
     };
   },
 
@@ -1040,6 +1103,9 @@ export const TimelineResource: MessageFns<TimelineResource> = {
     if (message.preExisting !== false) {
       obj.preExisting = message.preExisting;
     }
+    if (message.cluster !== "") {
+      obj.cluster = message.cluster;
+    }
     return obj;
   },
 
@@ -1064,6 +1130,7 @@ export const TimelineResource: MessageFns<TimelineResource> = {
     message.statusSegments = object.statusSegments?.map((e) => StatusSegment.fromPartial(e)) || [];
     message.events = object.events?.map((e) => K8sEvent.fromPartial(e)) || [];
     message.preExisting = object.preExisting ?? false;
+    message.cluster = object.cluster ?? "";
     return message;
   },
 };
@@ -1145,7 +1212,7 @@ export const TimelineResource_LabelsEntry: MessageFns<TimelineResource_LabelsEnt
 };
 
 function createBaseTimelineChunk(): TimelineChunk {
-  return { metadata: undefined, batch: undefined };
+  return { metadata: undefined, batch: undefined, delta: undefined };
 }
 
 export const TimelineChunk: MessageFns<TimelineChunk> = {
@@ -1156,6 +1223,9 @@ export const TimelineChunk: MessageFns<TimelineChunk> = {
     if (message.batch !== undefined) {
       ResourceBatch.encode(message.batch, writer.uint32(18).fork()).join();
     }
+    if (message.delta !== undefined) {
+      TimelineDelta.encode(message.delta, writer.uint32(26).fork()).join();
+    }
     return writer;
   },
 
@@ -1182,6 +1252,14 @@ export const TimelineChunk: MessageFns<TimelineChunk> = {
           message.batch = ResourceBatch.decode(reader, reader.uint32());
           continue;
         }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.delta = TimelineDelta.decode(reader, reader.uint32());
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -1195,6 +1273,7 @@ export const TimelineChunk: MessageFns<TimelineChunk> = {
     return {
       metadata: isSet(object.metadata) ? TimelineMetadata.fromJSON(object.metadata) : undefined,
       batch: isSet(object.batch) ? ResourceBatch.fromJSON(object.batch) : undefined,
+      delta: isSet(object.delta) ? TimelineDelta.fromJSON(object.delta) : undefined,
     };
   },
 
@@ -1206,6 +1285,9 @@ export const TimelineChunk: MessageFns<TimelineChunk> = {
     if (message.batch !== undefined) {
       obj.batch = ResourceBatch.toJSON(message.batch);
     }
+    if (message.delta !== undefined) {
+      obj.delta = TimelineDelta.toJSON(message.delta);
+    }
     return obj;
   },
 
@@ -1220,6 +1302,9 @@ export const TimelineChunk: MessageFns<TimelineChunk> = {
     message.batch = (object.batch !== undefined && object.batch !== null)
       ? ResourceBatch.fromPartial(object.batch)
       : undefined;
+    message.delta = (object.delta !== undefined && object.delta !== null)
+      ? TimelineDelta.fromPartial(object.delta)
+      : undefined;
     return message;
   },
 };
@@ -1318,6 +1403,84 @@ export const ResourceBatch: MessageFns<ResourceBatch> = {
   },
 };
 
+function createBaseTimelineDelta(): TimelineDelta {
+  return { resources: [], timestamp: 0 };
+}
+
+export const TimelineDelta: MessageFns<TimelineDelta> = {
+  encode(message: TimelineDelta, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    for (const v of message.resources) {
+      TimelineResource.encode(v!, writer.uint32(10).fork()).join();
+    }
+    if (message.timestamp !== 0) {
+      writer.uint32(16).int64(message.timestamp);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): TimelineDelta {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseTimelineDelta();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.resources.push(TimelineResource.decode(reader, reader.uint32()));
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.timestamp = longToTimestampMs(reader.int64());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): TimelineDelta {
+    return {
+      resources: globalThis.Array.isArray(object?.resources)
+        ? object.resources.map((e: any) => TimelineResource.fromJSON(e))
+        : [],
+      timestamp: isSet(object.timestamp) ? globalThis.Number(object.timestamp) : 0,
+    };
+  },
+
+  toJSON(message: TimelineDelta): unknown {
+    const obj: any = {};
+    if (message.resources?.length) {
+      obj.resources = message.resources.map((e) => TimelineResource.toJSON(e));
+    }
+    if (message.timestamp !== 0) {
+      obj.timestamp = Math.round(message.timestamp);
+    }
+    return obj;
+  },
+
+  create(base?: DeepPartial<TimelineDelta>): TimelineDelta {
+    return TimelineDelta.fromPartial(base ?? {});
+  },
+  fromPartial(object: DeepPartial<TimelineDelta>): TimelineDelta {
+    const message = createBaseTimelineDelta();
+    message.resources = object.resources?.map((e) => TimelineResource.fromPartial(e)) || [];
+    message.timestamp = object.timestamp ?? 0;
+    return message;
+  },
+};
+
 /** TimelineService definition */
 export type TimelineServiceDefinition = typeof TimelineServiceDefinition;
 export const TimelineServiceDefinition = {
@@ -1332,6 +1495,18 @@ export const TimelineServiceDefinition = {
       responseStream: true,
       options: {},
     },
+    /**
+     * WatchTimeline streams the snapshot like GetTimeline, then keeps the
+     * stream open and sends a delta whenever new changes match the filters
+     */
+    watchTimeline: {
+      name: "WatchTimeline",
+      requestType: TimelineRequest,
+      requestStream: false,
+      responseType: TimelineChunk,
+      responseStream: true,
+      options: {},
+    },
   },
 } as const;
 
@@ -1418,6 +1593,7 @@ export class TimelineServiceClientImpl {
   constructor(rpc: any) {
     this.rpc = rpc;
     this.GetTimeline = this.GetTimeline.bind(this);
+    this.WatchTimeline = this.WatchTimeline.bind(this);
   }
 
   GetTimeline(request: DeepPartial<TimelineRequest>): Observable<TimelineChunk> {
@@ -1429,4 +1605,14 @@ export class TimelineServiceClientImpl {
     );
     return result.pipe(map((data: Uint8Array) => TimelineChunk.decode(data)));
   }
+
+  WatchTimeline(request: DeepPartial<TimelineRequest>): Observable<TimelineChunk> {
+    const data = TimelineRequest.encode(request as TimelineRequest).finish();
+    const result = this.rpc.serverStreamingRequest(
+      "api.TimelineService",
+      "WatchTimeline",
+      data
+    );
+    return result.pipe(map((data: Uint8Array) => TimelineChunk.decode(data)));
+  }
 }